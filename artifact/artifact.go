@@ -2,12 +2,15 @@ package artifact
 
 import (
 	"compress/gzip"
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -21,6 +24,8 @@ type Config struct {
 	BaseDir       string // Base directory for storage (default: ".devflow")
 	CompressAbove int64  // Compress artifacts larger than this (default: 10KB)
 	RetentionDays int    // Days to keep artifacts (default: 30)
+
+	Schemas *Registry // Typed artifact schemas (default: DefaultRegistry)
 }
 
 // Manager manages run artifacts
@@ -28,6 +33,9 @@ type Manager struct {
 	baseDir       string
 	compressAbove int64
 	retentionDays int
+	schemas       *Registry
+
+	versionMu sync.Mutex // serializes SaveVersion numbering
 }
 
 // Info contains metadata about a stored artifact
@@ -77,12 +85,29 @@ func NewManager(cfg Config) *Manager {
 	if cfg.RetentionDays == 0 {
 		cfg.RetentionDays = 30
 	}
+	if cfg.Schemas == nil {
+		cfg.Schemas = DefaultRegistry
+	}
 
 	return &Manager{
 		baseDir:       cfg.BaseDir,
 		compressAbove: cfg.CompressAbove,
 		retentionDays: cfg.RetentionDays,
+		schemas:       cfg.Schemas,
+	}
+}
+
+// HealthCheck verifies the base directory is writable
+func (m *Manager) HealthCheck(_ context.Context) error {
+	if err := os.MkdirAll(m.baseDir, 0755); err != nil {
+		return fmt.Errorf("artifact store not writable: %w", err)
+	}
+	f, err := os.CreateTemp(m.baseDir, ".health-*")
+	if err != nil {
+		return fmt.Errorf("artifact store not writable: %w", err)
 	}
+	f.Close()
+	return os.Remove(f.Name())
 }
 
 // RunDir returns the directory for a run
@@ -2,7 +2,10 @@
 //
 // Core types:
 //   - Manager: Saves and loads artifacts for workflow runs
+//   - Version: A saved version of an artifact (spec.v1.md, spec.v2.md, ...)
+//   - Registry: Typed artifact schemas, used by SaveTyped and LoadTyped
 //   - LifecycleManager: Handles cleanup, archival, and retention
+//   - LifecycleReport: Result of a scheduled or dry-run retention pass
 //   - ReviewResult: Code review findings artifact
 //   - TestOutput: Test execution results artifact
 //   - LintOutput: Linting results artifact
@@ -0,0 +1,77 @@
+package artifact
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Export errors
+var (
+	ErrRunNotFound = errors.New("run not found")
+	ErrRunExists   = errors.New("run already exists")
+)
+
+// ExportRun writes a tar.gz bundle of a run (artifacts, transcript, and
+// metadata) to w. Entries are stored under "<runID>/", the same layout as
+// lifecycle archives, so a bundle can also be restored as an archive.
+func (m *Manager) ExportRun(runID string, w io.Writer) error {
+	runDir := m.RunDir(runID)
+	info, err := os.Stat(runDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+		}
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+
+	if err := writeRunTar(w, runDir, runID); err != nil {
+		return fmt.Errorf("export run %s: %w", runID, err)
+	}
+	return nil
+}
+
+// ImportRun restores a bundle written by ExportRun and returns its run ID.
+// It fails with ErrRunExists rather than overwrite an existing run; a
+// failed import leaves nothing behind.
+func (m *Manager) ImportRun(r io.Reader) (string, error) {
+	runsDir := filepath.Join(m.baseDir, "runs")
+	if err := os.MkdirAll(runsDir, 0755); err != nil {
+		return "", err
+	}
+
+	// Extract beside the runs so the final rename stays on one filesystem
+	tmpDir, err := os.MkdirTemp(runsDir, ".import-")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	if err := extractRunTar(r, tmpDir); err != nil {
+		return "", fmt.Errorf("import run: %w", err)
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) != 1 || !entries[0].IsDir() {
+		return "", errors.New("import run: bundle must contain exactly one run directory")
+	}
+
+	runID := entries[0].Name()
+	runDir := m.RunDir(runID)
+	if _, err := os.Stat(runDir); err == nil {
+		return "", fmt.Errorf("%w: %s", ErrRunExists, runID)
+	}
+
+	if err := os.Rename(filepath.Join(tmpDir, runID), runDir); err != nil {
+		return "", fmt.Errorf("import run %s: %w", runID, err)
+	}
+	return runID, nil
+}
@@ -21,6 +21,35 @@ type RetentionConfig struct {
 	ArchiveRetentionDays int  // Days to keep archived runs
 	KeepFailed           bool // Keep failed runs longer
 	KeepMinRuns          int  // Minimum runs to keep regardless of age
+
+	// MaxTotalSize caps runs plus archives in bytes (0: no cap). RunOnce
+	// deletes the oldest archives, then the oldest runs, until under it.
+	MaxTotalSize int64
+
+	// FlowOverrides replaces age limits for runs of specific flows
+	FlowOverrides map[string]FlowRetention
+}
+
+// FlowRetention overrides age limits for one flow. Zero values fall back
+// to the RetentionConfig defaults.
+type FlowRetention struct {
+	RetentionDays    int
+	ArchiveAfterDays int
+}
+
+// thresholds returns the archive and delete cutoffs for a flow
+func (c RetentionConfig) thresholds(now time.Time, flowID string) (archiveBefore, deleteBefore time.Time) {
+	retention, archiveAfter := c.RetentionDays, c.ArchiveAfterDays
+	if o, ok := c.FlowOverrides[flowID]; ok {
+		if o.RetentionDays > 0 {
+			retention = o.RetentionDays
+		}
+		if o.ArchiveAfterDays > 0 {
+			archiveAfter = o.ArchiveAfterDays
+		}
+	}
+	return now.Add(-time.Duration(archiveAfter) * 24 * time.Hour),
+		now.Add(-time.Duration(retention) * 24 * time.Hour)
 }
 
 // DefaultRetentionConfig returns sensible defaults
@@ -76,48 +105,7 @@ func (m *LifecycleManager) Cleanup(dryRun bool) (*CleanupResult, error) {
 	}
 
 	now := time.Now()
-	archiveThreshold := now.Add(-time.Duration(m.config.ArchiveAfterDays) * 24 * time.Hour)
-	deleteThreshold := now.Add(-time.Duration(m.config.RetentionDays) * 24 * time.Hour)
-
-	type runInfo struct {
-		id      string
-		meta    *transcriptMeta
-		size    int64
-		endedAt time.Time
-	}
-
-	var runs []runInfo
-
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		runID := entry.Name()
-		runDir := filepath.Join(runsDir, runID)
-
-		// Load metadata
-		meta, err := loadRunMetadataFromDir(runDir)
-		if err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("load %s: %v", runID, err))
-			continue
-		}
-
-		// Calculate directory size
-		size := dirSize(runDir)
-
-		runs = append(runs, runInfo{
-			id:      runID,
-			meta:    meta,
-			size:    size,
-			endedAt: meta.EndedAt,
-		})
-	}
-
-	// Sort by end time (oldest first)
-	sort.Slice(runs, func(i, j int) bool {
-		return runs[i].endedAt.Before(runs[j].endedAt)
-	})
+	runs := loadRuns(runsDir, entries, result)
 
 	// Calculate how many we can potentially remove
 	canRemove := len(runs) - m.config.KeepMinRuns
@@ -127,14 +115,8 @@ func (m *LifecycleManager) Cleanup(dryRun bool) (*CleanupResult, error) {
 
 	removed := 0
 	for _, run := range runs {
-		// Skip failed runs if configured
-		if m.config.KeepFailed && run.meta.Status == "failed" {
-			result.Kept = append(result.Kept, run.id)
-			continue
-		}
-
-		// Still running - keep
-		if run.meta.Status == "running" {
+		// Failed (if configured) and running runs are always kept
+		if m.protected(run) {
 			result.Kept = append(result.Kept, run.id)
 			continue
 		}
@@ -147,6 +129,7 @@ func (m *LifecycleManager) Cleanup(dryRun bool) (*CleanupResult, error) {
 		}
 
 		runDir := filepath.Join(runsDir, run.id)
+		archiveThreshold, deleteThreshold := m.config.thresholds(now, run.meta.FlowID)
 
 		// Determine action based on age
 		if run.endedAt.Before(deleteThreshold) {
@@ -201,62 +184,11 @@ func (m *LifecycleManager) archiveRun(runID string) error {
 		return err
 	}
 
-	gz := gzip.NewWriter(f)
-	tw := tar.NewWriter(gz)
-
-	// Add all files from run directory
-	err = filepath.Walk(runDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		header, err := tar.FileInfoHeader(info, "")
-		if err != nil {
-			return err
-		}
-
-		relPath, _ := filepath.Rel(runDir, path)
-		header.Name = filepath.Join(runID, relPath)
-
-		if err := tw.WriteHeader(header); err != nil {
-			return err
-		}
-
-		if !info.IsDir() {
-			file, err := os.Open(path)
-			if err != nil {
-				return err
-			}
-			_, copyErr := io.Copy(tw, file)
-			_ = file.Close() // Read-only, error not critical
-			if copyErr != nil {
-				return copyErr
-			}
-		}
-
-		return nil
-	})
-
-	if err != nil {
-		_ = tw.Close()
-		_ = gz.Close()
+	if err := writeRunTar(f, runDir, runID); err != nil {
 		_ = f.Close()
 		_ = os.Remove(archivePath)
 		return err
 	}
-
-	// Close writers before removing source - check errors for write operations
-	if closeErr := tw.Close(); closeErr != nil {
-		_ = gz.Close()
-		_ = f.Close()
-		_ = os.Remove(archivePath)
-		return closeErr
-	}
-	if closeErr := gz.Close(); closeErr != nil {
-		_ = f.Close()
-		_ = os.Remove(archivePath)
-		return closeErr
-	}
 	if closeErr := f.Close(); closeErr != nil {
 		_ = os.Remove(archivePath)
 		return closeErr
@@ -372,46 +304,7 @@ func (m *LifecycleManager) extractArchive(archivePath, destDir string) error {
 	}
 	defer func() { _ = f.Close() }()
 
-	gz, err := gzip.NewReader(f)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = gz.Close() }()
-
-	tr := tar.NewReader(gz)
-
-	for {
-		header, err := tr.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return err
-		}
-
-		target := filepath.Join(destDir, header.Name)
-
-		// Ensure target is within destDir (security check)
-		if !strings.HasPrefix(filepath.Clean(target), filepath.Clean(destDir)) {
-			return fmt.Errorf("invalid path in archive: %s", header.Name)
-		}
-
-		switch header.Typeflag {
-		case tar.TypeDir:
-			if err := os.MkdirAll(target, 0755); err != nil {
-				return err
-			}
-		case tar.TypeReg:
-			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
-				return err
-			}
-			if err := extractFile(target, tr, header.Mode); err != nil {
-				return err
-			}
-		}
-	}
-
-	return nil
+	return extractRunTar(f, destDir)
 }
 
 // CleanupArchives removes archives older than retention period
@@ -507,9 +400,61 @@ type DiskUsageStats struct {
 
 // Helper functions
 
+// runInfo describes a run directory considered for cleanup
+type runInfo struct {
+	id      string
+	meta    *transcriptMeta
+	size    int64
+	endedAt time.Time
+}
+
+// loadRuns reads run metadata from entries in runsDir, oldest first.
+// Unreadable runs are recorded in result.Errors and skipped.
+func loadRuns(runsDir string, entries []os.DirEntry, result *CleanupResult) []runInfo {
+	var runs []runInfo
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		runID := entry.Name()
+		runDir := filepath.Join(runsDir, runID)
+
+		// Load metadata
+		meta, err := loadRunMetadataFromDir(runDir)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("load %s: %v", runID, err))
+			continue
+		}
+
+		runs = append(runs, runInfo{
+			id:      runID,
+			meta:    meta,
+			size:    dirSize(runDir),
+			endedAt: meta.EndedAt,
+		})
+	}
+
+	// Sort by end time (oldest first)
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].endedAt.Before(runs[j].endedAt)
+	})
+	return runs
+}
+
+// protected reports whether a run must never be removed
+func (m *LifecycleManager) protected(run runInfo) bool {
+	if m.config.KeepFailed && run.meta.Status == "failed" {
+		return true
+	}
+	return run.meta.Status == "running"
+}
+
 // transcriptMeta is a minimal type for reading metadata
 // This avoids circular imports with the main devflow package
 type transcriptMeta struct {
+	FlowID  string    `json:"flowId"`
 	Status  string    `json:"status"`
 	EndedAt time.Time `json:"endedAt"`
 }
@@ -546,6 +491,103 @@ func dirSize(path string) int64 {
 }
 
 // extractFile extracts a single file from an archive with proper close error handling
+// writeRunTar writes runDir as a gzipped tar with entries under runID/
+func writeRunTar(w io.Writer, runDir, runID string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	// Add all files from run directory
+	err := filepath.Walk(runDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		relPath, _ := filepath.Rel(runDir, path)
+		header.Name = filepath.ToSlash(filepath.Join(runID, relPath))
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			_, copyErr := io.Copy(tw, file)
+			_ = file.Close() // Read-only, error not critical
+			if copyErr != nil {
+				return copyErr
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		_ = tw.Close()
+		_ = gz.Close()
+		return err
+	}
+
+	// Close writers - check errors for write operations
+	if err := tw.Close(); err != nil {
+		_ = gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+// extractRunTar extracts a gzipped tar written by writeRunTar into destDir
+func extractRunTar(r io.Reader, destDir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = gz.Close() }()
+
+	tr := tar.NewReader(gz)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(destDir, header.Name)
+
+		// Ensure target is within destDir (security check)
+		rel, err := filepath.Rel(destDir, target)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path in archive: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := extractFile(target, tr, header.Mode); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func extractFile(target string, r io.Reader, mode int64) (err error) {
 	out, err := os.Create(target)
 	if err != nil {
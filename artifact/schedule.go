@@ -0,0 +1,232 @@
+package artifact
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LifecycleReport summarizes one RunOnce pass. In a dry run it lists what
+// would be archived and deleted without touching disk.
+type LifecycleReport struct {
+	DryRun     bool           `json:"dryRun"`
+	StartedAt  time.Time      `json:"startedAt"`
+	Runs       *CleanupResult `json:"runs"`     // Age-based run archival and deletion
+	Archives   *CleanupResult `json:"archives"` // Expired archives
+	Quota      *CleanupResult `json:"quota"`    // Removed for MaxTotalSize; archives as "archive/<runID>"
+	SizeBefore int64          `json:"sizeBefore"`
+	SizeAfter  int64          `json:"sizeAfter"` // Estimated in a dry run
+}
+
+// SpaceSaved returns the bytes freed (or that would be freed) by the pass
+func (r *LifecycleReport) SpaceSaved() int64 {
+	var saved int64
+	for _, res := range []*CleanupResult{r.Runs, r.Archives, r.Quota} {
+		if res != nil {
+			saved += res.SpaceSaved
+		}
+	}
+	return saved
+}
+
+// RunOnce applies the full retention policy: age-based cleanup of runs,
+// expiry of old archives, then the MaxTotalSize quota.
+func (m *LifecycleManager) RunOnce(ctx context.Context, dryRun bool) (*LifecycleReport, error) {
+	report := &LifecycleReport{DryRun: dryRun, StartedAt: time.Now()}
+
+	usage, err := m.DiskUsage()
+	if err != nil {
+		return nil, fmt.Errorf("disk usage: %w", err)
+	}
+	report.SizeBefore = usage.TotalSize
+
+	if report.Runs, err = m.Cleanup(dryRun); err != nil {
+		return nil, fmt.Errorf("cleanup runs: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	if report.Archives, err = m.CleanupArchives(dryRun); err != nil {
+		return report, fmt.Errorf("cleanup archives: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	// A dry run changed nothing on disk, so estimate from the results
+	size := report.SizeBefore - report.Runs.SpaceSaved - report.Archives.SpaceSaved
+	if !dryRun {
+		if usage, err = m.DiskUsage(); err != nil {
+			return report, fmt.Errorf("disk usage: %w", err)
+		}
+		size = usage.TotalSize
+	}
+
+	if report.Quota, err = m.enforceQuota(ctx, dryRun, size, report); err != nil {
+		return report, fmt.Errorf("enforce quota: %w", err)
+	}
+	report.SizeAfter = size - report.Quota.SpaceSaved
+
+	return report, nil
+}
+
+// RunPeriodically runs RunOnce immediately and then every interval until ctx
+// is canceled, reporting each result to onReport (which may be nil).
+func (m *LifecycleManager) RunPeriodically(ctx context.Context, interval time.Duration, dryRun bool, onReport func(*LifecycleReport, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := m.RunOnce(ctx, dryRun)
+		if onReport != nil {
+			onReport(report, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enforceQuota deletes the oldest archives, then the oldest removable runs,
+// until size is under MaxTotalSize. Runs and archives already handled by
+// earlier phases of a dry run are skipped.
+func (m *LifecycleManager) enforceQuota(ctx context.Context, dryRun bool, size int64, report *LifecycleReport) (*CleanupResult, error) {
+	result := &CleanupResult{
+		Deleted: make([]string, 0),
+		Kept:    make([]string, 0),
+		Errors:  make([]string, 0),
+	}
+	if m.config.MaxTotalSize <= 0 || size <= m.config.MaxTotalSize {
+		return result, nil
+	}
+
+	skipArchives := make(map[string]bool)
+	skipRuns := make(map[string]bool)
+	if dryRun {
+		for _, id := range report.Archives.Deleted {
+			skipArchives[id] = true
+		}
+		for _, id := range report.Runs.Deleted {
+			skipRuns[id] = true
+		}
+		for _, id := range report.Runs.Archived {
+			skipRuns[id] = true
+		}
+	}
+
+	// Archives first: they are the oldest data
+	archives, err := m.listArchiveFiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range archives {
+		if size <= m.config.MaxTotalSize {
+			return result, nil
+		}
+		if skipArchives[a.runID] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if !dryRun {
+			if err := os.Remove(a.path); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("delete archive %s: %v", a.runID, err))
+				continue
+			}
+		}
+		result.Deleted = append(result.Deleted, "archive/"+a.runID)
+		result.SpaceSaved += a.size
+		size -= a.size
+	}
+
+	runsDir := filepath.Join(m.baseDir, "runs")
+	entries, err := os.ReadDir(runsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, err
+	}
+
+	var runs []runInfo
+	for _, run := range loadRuns(runsDir, entries, result) {
+		if !skipRuns[run.id] {
+			runs = append(runs, run)
+		}
+	}
+
+	remaining := len(runs)
+	for _, run := range runs {
+		if size <= m.config.MaxTotalSize || remaining <= m.config.KeepMinRuns {
+			break
+		}
+		if m.protected(run) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if !dryRun {
+			if err := os.RemoveAll(filepath.Join(runsDir, run.id)); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("delete %s: %v", run.id, err))
+				continue
+			}
+		}
+		result.Deleted = append(result.Deleted, run.id)
+		result.SpaceSaved += run.size
+		size -= run.size
+		remaining--
+	}
+
+	if size > m.config.MaxTotalSize {
+		result.Errors = append(result.Errors,
+			fmt.Sprintf("still %d bytes over quota after removing all eligible runs", size-m.config.MaxTotalSize))
+	}
+	return result, nil
+}
+
+// archiveFile is an archive considered for quota enforcement
+type archiveFile struct {
+	runID   string
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// listArchiveFiles returns all run archives, oldest first
+func (m *LifecycleManager) listArchiveFiles() ([]archiveFile, error) {
+	var archives []archiveFile
+
+	err := filepath.Walk(filepath.Join(m.baseDir, "archive"), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".tar.gz") {
+			return nil
+		}
+		archives = append(archives, archiveFile{
+			runID:   strings.TrimSuffix(info.Name(), ".tar.gz"),
+			path:    path,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].modTime.Before(archives[j].modTime)
+	})
+	return archives, nil
+}
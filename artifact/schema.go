@@ -0,0 +1,260 @@
+package artifact
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Schema errors
+var (
+	ErrUnregisteredType = errors.New("artifact type not registered")
+	ErrSchemaMismatch   = errors.New("artifact does not match schema")
+	ErrSchemaVersion    = errors.New("unsupported artifact schema version")
+)
+
+// Kind identifies a typed artifact schema
+type Kind string
+
+// Built-in artifact kinds
+const (
+	KindReview        Kind = "review"
+	KindTestOutput    Kind = "test-output"
+	KindLintOutput    Kind = "lint-output"
+	KindSpecification Kind = "specification"
+)
+
+// ArtifactSpecification is the default name for structured specifications.
+// ArtifactSpec holds the markdown form.
+const ArtifactSpecification = "specification.json"
+
+// Schema maps an artifact kind to its Go type and current schema version
+type Schema struct {
+	Kind    Kind
+	Name    string       // Default artifact name, e.g. "review.json"
+	Version int          // Current schema version (>= 1)
+	Type    reflect.Type // Set by RegisterType
+
+	// Migrate upgrades data saved with an older schema version to the
+	// current one. Without it, older versions fail to load.
+	Migrate func(from int, data json.RawMessage) (json.RawMessage, error)
+
+	// Validate checks a decoded value (a pointer to Type) on load and save
+	Validate func(v any) error
+}
+
+// Registry holds artifact schemas by kind and Go type
+type Registry struct {
+	mu     sync.RWMutex
+	byKind map[Kind]Schema
+	byType map[reflect.Type]Kind
+}
+
+// NewRegistry creates an empty schema registry
+func NewRegistry() *Registry {
+	return &Registry{
+		byKind: make(map[Kind]Schema),
+		byType: make(map[reflect.Type]Kind),
+	}
+}
+
+// DefaultRegistry holds the built-in artifact schemas. It is used by
+// managers created without Config.Schemas.
+var DefaultRegistry = newDefaultRegistry()
+
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+	must(RegisterType[ReviewResult](r, Schema{Kind: KindReview, Name: ArtifactReview, Version: 1}))
+	must(RegisterType[TestOutput](r, Schema{Kind: KindTestOutput, Name: ArtifactTestOutput, Version: 1}))
+	must(RegisterType[LintOutput](r, Schema{Kind: KindLintOutput, Name: ArtifactLintOutput, Version: 1}))
+	must(RegisterType[Specification](r, Schema{Kind: KindSpecification, Name: ArtifactSpecification, Version: 1}))
+	return r
+}
+
+// RegisterType registers s as the schema for T
+func RegisterType[T any](r *Registry, s Schema) error {
+	s.Type = reflect.TypeOf((*T)(nil)).Elem()
+	return r.Register(s)
+}
+
+// Register adds a schema. Each kind and Go type may be registered once.
+func (r *Registry) Register(s Schema) error {
+	if s.Kind == "" {
+		return errors.New("schema kind is required")
+	}
+	if s.Type == nil {
+		return fmt.Errorf("schema %s: type is required", s.Kind)
+	}
+	if s.Version < 1 {
+		return fmt.Errorf("schema %s: version must be at least 1", s.Kind)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byKind[s.Kind]; ok {
+		return fmt.Errorf("schema %s already registered", s.Kind)
+	}
+	if kind, ok := r.byType[s.Type]; ok {
+		return fmt.Errorf("type %s already registered as %s", s.Type, kind)
+	}
+	r.byKind[s.Kind] = s
+	r.byType[s.Type] = s.Kind
+	return nil
+}
+
+// Lookup returns the schema for a kind
+func (r *Registry) Lookup(kind Kind) (Schema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.byKind[kind]
+	return s, ok
+}
+
+// Kinds returns all registered kinds
+func (r *Registry) Kinds() []Kind {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	kinds := make([]Kind, 0, len(r.byKind))
+	for k := range r.byKind {
+		kinds = append(kinds, k)
+	}
+	return kinds
+}
+
+// schemaFor returns the schema registered for T
+func schemaFor[T any](r *Registry) (Schema, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	kind, ok := r.byType[t]
+	if !ok {
+		return Schema{}, fmt.Errorf("%w: %s", ErrUnregisteredType, t)
+	}
+	return r.byKind[kind], nil
+}
+
+// typedEnvelope is the on-disk form of a typed artifact
+type typedEnvelope struct {
+	Kind          Kind            `json:"kind"`
+	SchemaVersion int             `json:"schemaVersion"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// SaveTyped saves v under its schema's default name
+func SaveTyped[T any](m *Manager, runID string, v *T) error {
+	s, err := schemaFor[T](m.schemas)
+	if err != nil {
+		return err
+	}
+	return SaveTypedAs(m, runID, s.Name, v)
+}
+
+// SaveTypedAs saves v under name, tagged with its kind and schema version
+func SaveTypedAs[T any](m *Manager, runID, name string, v *T) error {
+	data, err := encodeTyped(m.schemas, v)
+	if err != nil {
+		return err
+	}
+	return m.SaveArtifact(runID, name, data)
+}
+
+// LoadTyped loads the artifact at its schema's default name
+func LoadTyped[T any](m *Manager, runID string) (*T, error) {
+	s, err := schemaFor[T](m.schemas)
+	if err != nil {
+		return nil, err
+	}
+	return LoadTypedAs[T](m, runID, s.Name)
+}
+
+// LoadTypedAs loads and decodes the artifact saved under name. Older schema
+// versions are migrated; unknown fields, a different kind, or a newer
+// version fail with ErrSchemaMismatch or ErrSchemaVersion.
+func LoadTypedAs[T any](m *Manager, runID, name string) (*T, error) {
+	data, err := m.LoadArtifact(runID, name)
+	if err != nil {
+		return nil, err
+	}
+	v, err := decodeTyped[T](m.schemas, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return v, nil
+}
+
+// encodeTyped validates v and wraps it in a typed envelope
+func encodeTyped[T any](r *Registry, v *T) ([]byte, error) {
+	s, err := schemaFor[T](r)
+	if err != nil {
+		return nil, err
+	}
+	if s.Validate != nil {
+		if err := s.Validate(v); err != nil {
+			return nil, fmt.Errorf("validate %s: %w", s.Kind, err)
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(typedEnvelope{Kind: s.Kind, SchemaVersion: s.Version, Data: data}, "", "  ")
+}
+
+// decodeTyped unwraps, migrates, and strictly decodes a typed artifact.
+// Artifacts saved before typing (bare JSON) are read as version 1.
+func decodeTyped[T any](r *Registry, data []byte) (*T, error) {
+	s, err := schemaFor[T](r)
+	if err != nil {
+		return nil, err
+	}
+
+	var env typedEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSchemaMismatch, err)
+	}
+	if env.Kind == "" && env.SchemaVersion == 0 {
+		env = typedEnvelope{Kind: s.Kind, SchemaVersion: 1, Data: data}
+	}
+
+	if env.Kind != s.Kind {
+		return nil, fmt.Errorf("%w: kind %q, want %q", ErrSchemaMismatch, env.Kind, s.Kind)
+	}
+	switch {
+	case env.SchemaVersion > s.Version:
+		return nil, fmt.Errorf("%w: %s v%d is newer than supported v%d",
+			ErrSchemaVersion, s.Kind, env.SchemaVersion, s.Version)
+	case env.SchemaVersion < s.Version:
+		if s.Migrate == nil {
+			return nil, fmt.Errorf("%w: %s v%d has no migration to v%d",
+				ErrSchemaVersion, s.Kind, env.SchemaVersion, s.Version)
+		}
+		if env.Data, err = s.Migrate(env.SchemaVersion, env.Data); err != nil {
+			return nil, fmt.Errorf("migrate %s from v%d: %w", s.Kind, env.SchemaVersion, err)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(env.Data))
+	dec.DisallowUnknownFields()
+	var v T
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%w: %s v%d: %v", ErrSchemaMismatch, s.Kind, s.Version, err)
+	}
+
+	if s.Validate != nil {
+		if err := s.Validate(&v); err != nil {
+			return nil, fmt.Errorf("validate %s: %w", s.Kind, err)
+		}
+	}
+	return &v, nil
+}
@@ -0,0 +1,191 @@
+package artifact
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// SaveArtifactReader saves an artifact streamed from r and returns the
+// number of uncompressed bytes written. Like SaveArtifact, compressible
+// artifacts at or above the compression threshold are gzipped; only the
+// first CompressAbove bytes are buffered to decide.
+func (m *Manager) SaveArtifactReader(runID, name string, r io.Reader) (int64, error) {
+	w, err := m.CreateArtifact(runID, name)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := io.Copy(w, r)
+	if err != nil {
+		w.abort()
+		return n, err
+	}
+	return n, w.Close()
+}
+
+// LoadArtifactReader opens an artifact for streaming, decompressing
+// transparently. The caller must close the reader.
+func (m *Manager) LoadArtifactReader(runID, name string) (io.ReadCloser, error) {
+	artifactPath := filepath.Join(m.ArtifactDir(runID), name)
+
+	// Try compressed first
+	if f, err := os.Open(artifactPath + ".gz"); err == nil {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		return &gzipReadCloser{Reader: gz, file: f}, nil
+	}
+
+	f, err := os.Open(artifactPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrArtifactNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// CreateArtifact returns a writer that streams an artifact to disk. The
+// artifact appears only when Close succeeds, so readers never see a
+// partial file.
+func (m *Manager) CreateArtifact(runID, name string) (*ArtifactWriter, error) {
+	artifactPath := filepath.Join(m.ArtifactDir(runID), name)
+	if err := os.MkdirAll(filepath.Dir(artifactPath), 0755); err != nil {
+		return nil, err
+	}
+
+	return &ArtifactWriter{
+		path:      artifactPath,
+		compress:  InferType(name).Compressible,
+		threshold: m.compressAbove,
+	}, nil
+}
+
+// ArtifactWriter streams an artifact to disk. Writes are buffered in memory
+// until the compression threshold is reached, then gzipped to a temp file.
+type ArtifactWriter struct {
+	path      string
+	compress  bool
+	threshold int64
+
+	buf    bytes.Buffer
+	file   *os.File
+	gz     *gzip.Writer
+	err    error
+	closed bool
+}
+
+// Write implements io.Writer
+func (w *ArtifactWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.closed {
+		return 0, errors.New("write to closed artifact")
+	}
+
+	if w.gz != nil {
+		n, err := w.gz.Write(p)
+		w.err = err
+		return n, err
+	}
+
+	w.buf.Write(p)
+	if w.compress && int64(w.buf.Len()) >= w.threshold {
+		if err := w.startCompressed(); err != nil {
+			w.err = err
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// startCompressed switches from buffering to streaming through gzip
+func (w *ArtifactWriter) startCompressed() error {
+	f, err := os.Create(w.path + ".gz.tmp")
+	if err != nil {
+		return err
+	}
+	w.file = f
+	w.gz = gzip.NewWriter(f)
+
+	if _, err := w.gz.Write(w.buf.Bytes()); err != nil {
+		return err
+	}
+	w.buf = bytes.Buffer{}
+	return nil
+}
+
+// Close finishes the artifact, replacing any previous version
+func (w *ArtifactWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	if w.err != nil {
+		w.abort()
+		return w.err
+	}
+	w.closed = true
+
+	if w.gz == nil {
+		// Below the threshold: write uncompressed
+		tmp := w.path + ".tmp"
+		if err := os.WriteFile(tmp, w.buf.Bytes(), 0644); err != nil {
+			_ = os.Remove(tmp)
+			return err
+		}
+		if err := os.Rename(tmp, w.path); err != nil {
+			_ = os.Remove(tmp)
+			return err
+		}
+		os.Remove(w.path + ".gz")
+		return nil
+	}
+
+	tmp := w.file.Name()
+	if err := w.gz.Close(); err != nil {
+		_ = w.file.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := w.file.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, w.path+".gz"); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	os.Remove(w.path)
+	return nil
+}
+
+// abort discards a partially written artifact
+func (w *ArtifactWriter) abort() {
+	w.closed = true
+	if w.file != nil {
+		_ = w.file.Close()
+		_ = os.Remove(w.file.Name())
+	}
+}
+
+// gzipReadCloser closes both the gzip reader and the underlying file
+type gzipReadCloser struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (r *gzipReadCloser) Close() error {
+	err := r.Reader.Close()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -177,9 +177,10 @@ type Specification struct {
 	Raw          string            `json:"raw,omitempty"` // Original markdown
 }
 
-// SaveSpec saves a specification artifact
+// SaveSpec saves a specification artifact as a new version (spec.vN.md)
 func (m *Manager) SaveSpec(runID string, spec string) error {
-	return m.SaveArtifact(runID, ArtifactSpec, []byte(spec))
+	_, err := m.SaveVersion(runID, ArtifactSpec, []byte(spec))
+	return err
 }
 
 // LoadSpec loads a specification artifact
@@ -191,76 +192,49 @@ func (m *Manager) LoadSpec(runID string) (string, error) {
 	return string(data), nil
 }
 
-// SaveReview saves a review result artifact
+// SaveSpecification saves a structured specification artifact
+func (m *Manager) SaveSpecification(runID string, spec *Specification) error {
+	return SaveTyped(m, runID, spec)
+}
+
+// LoadSpecification loads a structured specification artifact
+func (m *Manager) LoadSpecification(runID string) (*Specification, error) {
+	return LoadTyped[Specification](m, runID)
+}
+
+// SaveReview saves a review result artifact as a new version (review.vN.json)
 func (m *Manager) SaveReview(runID string, review *ReviewResult) error {
-	data, err := json.MarshalIndent(review, "", "  ")
+	data, err := encodeTyped(m.schemas, review)
 	if err != nil {
 		return err
 	}
-	return m.SaveArtifact(runID, ArtifactReview, data)
+	_, err = m.SaveVersion(runID, ArtifactReview, data)
+	return err
 }
 
 // LoadReview loads a review result artifact
 func (m *Manager) LoadReview(runID string) (*ReviewResult, error) {
-	data, err := m.LoadArtifact(runID, ArtifactReview)
-	if err != nil {
-		return nil, err
-	}
-
-	var review ReviewResult
-	if err := json.Unmarshal(data, &review); err != nil {
-		return nil, err
-	}
-
-	return &review, nil
+	return LoadTyped[ReviewResult](m, runID)
 }
 
 // SaveTestOutput saves test output artifact
 func (m *Manager) SaveTestOutput(runID string, output *TestOutput) error {
-	data, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return err
-	}
-	return m.SaveArtifact(runID, ArtifactTestOutput, data)
+	return SaveTyped(m, runID, output)
 }
 
 // LoadTestOutput loads test output artifact
 func (m *Manager) LoadTestOutput(runID string) (*TestOutput, error) {
-	data, err := m.LoadArtifact(runID, ArtifactTestOutput)
-	if err != nil {
-		return nil, err
-	}
-
-	var output TestOutput
-	if err := json.Unmarshal(data, &output); err != nil {
-		return nil, err
-	}
-
-	return &output, nil
+	return LoadTyped[TestOutput](m, runID)
 }
 
 // SaveLintOutput saves lint output artifact
 func (m *Manager) SaveLintOutput(runID string, output *LintOutput) error {
-	data, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return err
-	}
-	return m.SaveArtifact(runID, ArtifactLintOutput, data)
+	return SaveTyped(m, runID, output)
 }
 
 // LoadLintOutput loads lint output artifact
 func (m *Manager) LoadLintOutput(runID string) (*LintOutput, error) {
-	data, err := m.LoadArtifact(runID, ArtifactLintOutput)
-	if err != nil {
-		return nil, err
-	}
-
-	var output LintOutput
-	if err := json.Unmarshal(data, &output); err != nil {
-		return nil, err
-	}
-
-	return &output, nil
+	return LoadTyped[LintOutput](m, runID)
 }
 
 // SaveDiff saves an implementation diff artifact
@@ -0,0 +1,122 @@
+package artifact
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version describes one saved version of an artifact
+type Version struct {
+	Number    int       `json:"number"`
+	Name      string    `json:"name"` // Versioned artifact name, e.g. "spec.v2.md"
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// VersionedName returns the artifact name for version n of name:
+// "spec.md" becomes "spec.v2.md", "notes" becomes "notes.v2".
+func VersionedName(name string, n int) string {
+	ext := filepath.Ext(name)
+	return fmt.Sprintf("%s.v%d%s", strings.TrimSuffix(name, ext), n, ext)
+}
+
+// SaveVersion saves data as the next version of name and returns its
+// version number. The unversioned name is also updated, so LoadArtifact
+// always returns the latest version.
+func (m *Manager) SaveVersion(runID, name string, data []byte) (int, error) {
+	m.versionMu.Lock()
+	defer m.versionMu.Unlock()
+
+	versions, err := m.Versions(runID, name)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 1
+	if len(versions) > 0 {
+		n = versions[len(versions)-1].Number + 1
+	}
+
+	if err := m.SaveArtifact(runID, VersionedName(name, n), data); err != nil {
+		return 0, err
+	}
+	if err := m.SaveArtifact(runID, name, data); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Versions returns the saved versions of name, oldest first
+func (m *Manager) Versions(runID, name string) ([]Version, error) {
+	dir := filepath.Join(m.ArtifactDir(runID), filepath.Dir(name))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	base := filepath.Base(name)
+	ext := filepath.Ext(base)
+	pattern := regexp.MustCompile(`^` + regexp.QuoteMeta(strings.TrimSuffix(base, ext)) +
+		`\.v([0-9]+)` + regexp.QuoteMeta(ext) + `(\.gz)?$`)
+
+	var versions []Version
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := pattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		versions = append(versions, Version{
+			Number:    n,
+			Name:      filepath.ToSlash(filepath.Join(filepath.Dir(name), strings.TrimSuffix(entry.Name(), ".gz"))),
+			Size:      info.Size(),
+			CreatedAt: info.ModTime(),
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Number < versions[j].Number
+	})
+	return versions, nil
+}
+
+// LoadVersion loads version n of name
+func (m *Manager) LoadVersion(runID, name string, n int) ([]byte, error) {
+	return m.LoadArtifact(runID, VersionedName(name, n))
+}
+
+// Latest loads the newest version of name and its version number. An
+// artifact saved without versioning is returned as version 0.
+func (m *Manager) Latest(runID, name string) ([]byte, int, error) {
+	versions, err := m.Versions(runID, name)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(versions) == 0 {
+		data, err := m.LoadArtifact(runID, name)
+		return data, 0, err
+	}
+
+	n := versions[len(versions)-1].Number
+	data, err := m.LoadVersion(runID, name, n)
+	return data, n, err
+}
@@ -2,6 +2,10 @@
 //
 // This package includes:
 //   - JWT token generation and validation with customizable claims
+//   - RS256/ES256/EdDSA signing and JWKS-based verification
+//   - Signed refresh tokens with rotation and a jti denylist
+//   - Server-side sessions with sliding expiration
+//   - PKCE authorization code flow helpers for desktop CLIs
 //   - API key generation with configurable prefixes
 //   - Token hashing utilities
 //
@@ -21,6 +25,46 @@
 //	// Validate
 //	claims, err := auth.ValidateAccessToken(cfg, token)
 //
+// # Key Pairs and JWKS
+//
+// Set SigningKey to sign with a private key instead of a shared secret.
+// Verifying services use the public keys, typically from a JWKS endpoint:
+//
+//	signer := auth.JWTConfig{SigningKey: privateKey, KeyID: "2024-06"}
+//	verifier := auth.JWTConfig{Keys: auth.NewJWKSFetcher("https://auth.example.com/.well-known/jwks.json")}
+//
+// NewJWK encodes a public key for publishing in a JWKSet.
+//
+// # Refresh Tokens
+//
+// Signed refresh tokens live for RefreshTokenTTL and are rotated on use.
+// The old token's ID is denylisted so it cannot be replayed:
+//
+//	pair, err := auth.GenerateSignedTokenPair(cfg, "user-123")
+//	pair, err = auth.RotateRefreshToken(ctx, cfg, pair.RefreshToken, denylist)
+//
+// NewMemoryDenylist suits a single process; implement Denylist over a
+// shared store otherwise.
+//
+// # Sessions
+//
+// SessionManager keeps sessions in a SessionStore and hands out JWTs that
+// name them, so logins can be listed and revoked. Each Touch extends the
+// session by IdleTimeout, up to MaxLifetime:
+//
+//	sessions := auth.NewSessionManager(auth.SessionConfig{JWT: cfg, Store: store})
+//	session, token, err := sessions.Create(ctx, "user-123", nil)
+//	session, err = sessions.Touch(ctx, token) // ErrSessionExpired, ErrSessionNotFound
+//
+// # Browser Sign-In
+//
+// AuthorizeWithPKCE runs the OAuth authorization code flow with PKCE and a
+// loopback callback listener, as recommended for native apps (RFC 8252):
+//
+//	token, err := auth.AuthorizeWithPKCE(ctx, oauthConfig, auth.CallbackConfig{}, openBrowser)
+//
+// NewPKCE, GenerateState, and ListenForCallback are available separately.
+//
 // # Custom Claims
 //
 // Extend BaseClaims for application-specific claims:
@@ -10,9 +10,34 @@ var (
 	// ErrTokenExpired indicates the token has expired.
 	ErrTokenExpired = errors.New("token expired")
 
+	// ErrTokenRevoked indicates the refresh token has been denylisted,
+	// usually because it was already rotated.
+	ErrTokenRevoked = errors.New("token revoked")
+
+	// ErrNoDenylist indicates refresh token rotation was attempted without a
+	// Denylist to invalidate the old token.
+	ErrNoDenylist = errors.New("refresh token rotation requires a denylist")
+
 	// ErrSecretTooShort indicates the JWT secret is too short.
 	ErrSecretTooShort = errors.New("JWT secret must be at least 32 bytes")
 
+	// ErrUnsupportedKey indicates a key type that cannot sign or verify JWTs.
+	ErrUnsupportedKey = errors.New("unsupported key type")
+
+	// ErrKeyNotFound indicates no verification key matches the token's key ID.
+	ErrKeyNotFound = errors.New("verification key not found")
+
+	// ErrSessionNotFound indicates the session does not exist or was revoked.
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrSessionExpired indicates the session was idle too long or reached
+	// its maximum lifetime.
+	ErrSessionExpired = errors.New("session expired")
+
+	// ErrStateMismatch indicates an OAuth callback whose state does not
+	// match the authorization request, a sign of a forged redirect.
+	ErrStateMismatch = errors.New("oauth state mismatch")
+
 	// ErrInvalidAPIKey indicates the API key format is invalid.
 	ErrInvalidAPIKey = errors.New("invalid API key format")
 )
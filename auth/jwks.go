@@ -0,0 +1,351 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JWKS caching defaults.
+const (
+	DefaultJWKSCacheTTL        = time.Hour
+	DefaultJWKSRefreshInterval = time.Minute
+	DefaultJWKSTimeout         = 10 * time.Second
+	DefaultJWKSStaleGrace      = 15 * time.Minute
+)
+
+// JWK is a public key in JSON Web Key form (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC and OKP (Ed25519)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKSet is a JSON Web Key Set, as served from a jwks.json endpoint.
+// It implements KeySet.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+var b64 = base64.RawURLEncoding
+
+// NewJWK encodes an RSA, ECDSA, or Ed25519 public key as a signing JWK.
+func NewJWK(kid string, key crypto.PublicKey) (JWK, error) {
+	jwk := JWK{Kid: kid, Use: "sig"}
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty, jwk.Alg = "RSA", "RS256"
+		jwk.N = b64.EncodeToString(k.N.Bytes())
+		jwk.E = b64.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+	case *ecdsa.PublicKey:
+		alg, err := ecAlg(k.Curve)
+		if err != nil {
+			return JWK{}, err
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		jwk.Kty, jwk.Alg, jwk.Crv = "EC", alg, k.Curve.Params().Name
+		jwk.X = b64.EncodeToString(k.X.FillBytes(make([]byte, size)))
+		jwk.Y = b64.EncodeToString(k.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty, jwk.Alg, jwk.Crv = "OKP", "EdDSA", "Ed25519"
+		jwk.X = b64.EncodeToString(k)
+	default:
+		return JWK{}, fmt.Errorf("%w: %T", ErrUnsupportedKey, key)
+	}
+	return jwk, nil
+}
+
+// ecAlg returns the JWT algorithm for an ECDSA curve
+func ecAlg(curve elliptic.Curve) (string, error) {
+	switch curve {
+	case elliptic.P256():
+		return "ES256", nil
+	case elliptic.P384():
+		return "ES384", nil
+	case elliptic.P521():
+		return "ES512", nil
+	}
+	return "", fmt.Errorf("%w: curve %s", ErrUnsupportedKey, curve.Params().Name)
+}
+
+// PublicKey decodes the JWK.
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := b64.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode JWK %q: n: %w", k.Kid, err)
+		}
+		e, err := b64.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode JWK %q: e: %w", k.Kid, err)
+		}
+		exp := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exp.IsInt64() || exp.Int64() < 2 || exp.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("decode JWK %q: invalid RSA key", k.Kid)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("%w: curve %q", ErrUnsupportedKey, k.Crv)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode JWK %q: x: %w", k.Kid, err)
+		}
+		y, err := b64.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decode JWK %q: y: %w", k.Kid, err)
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("decode JWK %q: point not on curve", k.Kid)
+		}
+		return key, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("%w: curve %q", ErrUnsupportedKey, k.Crv)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("decode JWK %q: invalid Ed25519 key", k.Kid)
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("%w: kty %q", ErrUnsupportedKey, k.Kty)
+	}
+}
+
+// PublicKey returns the key with the given ID. An empty kid matches the
+// set's only key. Keys marked for encryption ("use": "enc") are skipped.
+func (s JWKSet) PublicKey(kid string) (crypto.PublicKey, error) {
+	var candidates []JWK
+	for _, k := range s.Keys {
+		if k.Use == "enc" {
+			continue
+		}
+		if k.Kid == kid {
+			return k.PublicKey()
+		}
+		candidates = append(candidates, k)
+	}
+	if kid == "" && len(candidates) == 1 {
+		return candidates[0].PublicKey()
+	}
+	return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, kid)
+}
+
+// JWKSFetcher is a KeySet backed by a remote JWKS endpoint. Keys are cached
+// for TTL. An unknown kid triggers a refetch, at most once per
+// RefreshInterval, so newly rotated keys are picked up without hammering
+// the endpoint. Concurrent callers share a single in-flight fetch, and the
+// request is made without holding the cache lock. If a refetch fails, the
+// cached keys are still used until StaleGrace past their TTL.
+type JWKSFetcher struct {
+	// URL is the JWKS endpoint, e.g. https://auth.example.com/.well-known/jwks.json.
+	URL string
+
+	// Client makes the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// TTL is how long fetched keys are trusted.
+	// Defaults to DefaultJWKSCacheTTL (1 hour) if zero.
+	TTL time.Duration
+
+	// RefreshInterval is the minimum time between fetches.
+	// Defaults to DefaultJWKSRefreshInterval (1 minute) if zero.
+	RefreshInterval time.Duration
+
+	// StaleGrace is how long past TTL cached keys are still used while
+	// refetches fail. Defaults to DefaultJWKSStaleGrace (15 minutes) if zero.
+	StaleGrace time.Duration
+
+	mu        sync.Mutex
+	set       JWKSet
+	fetchedAt time.Time  // Last successful fetch
+	triedAt   time.Time  // Last attempt
+	lastErr   error      // Error from the last attempt, if it failed
+	inflight  *jwksFetch // Fetch in progress, shared by concurrent callers
+	now       func() time.Time
+}
+
+// jwksFetch is a fetch shared by every caller that arrives while it runs
+type jwksFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// NewJWKSFetcher creates a fetcher for url with default caching.
+func NewJWKSFetcher(url string) *JWKSFetcher {
+	return &JWKSFetcher{URL: url}
+}
+
+func (f *JWKSFetcher) ttl() time.Duration {
+	if f.TTL == 0 {
+		return DefaultJWKSCacheTTL
+	}
+	return f.TTL
+}
+
+func (f *JWKSFetcher) refreshInterval() time.Duration {
+	if f.RefreshInterval == 0 {
+		return DefaultJWKSRefreshInterval
+	}
+	return f.RefreshInterval
+}
+
+func (f *JWKSFetcher) staleGrace() time.Duration {
+	if f.StaleGrace == 0 {
+		return DefaultJWKSStaleGrace
+	}
+	return f.StaleGrace
+}
+
+func (f *JWKSFetcher) clock() time.Time {
+	if f.now != nil {
+		return f.now()
+	}
+	return time.Now()
+}
+
+// PublicKey implements KeySet, fetching the key set when the cache is
+// empty, expired, or missing kid.
+func (f *JWKSFetcher) PublicKey(kid string) (crypto.PublicKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fresh := !f.fetchedAt.IsZero() && f.clock().Sub(f.fetchedAt) < f.ttl()
+	if fresh {
+		if key, err := f.set.PublicKey(kid); err == nil {
+			return key, nil
+		}
+	}
+
+	var fetchErr error
+	if f.inflight != nil || f.triedAt.IsZero() || f.clock().Sub(f.triedAt) >= f.refreshInterval() {
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultJWKSTimeout)
+		fetchErr = f.fetch(ctx)
+		cancel()
+	}
+
+	// Past TTL plus StaleGrace the cached keys are no longer trusted
+	if f.fetchedAt.IsZero() || f.clock().Sub(f.fetchedAt) >= f.ttl()+f.staleGrace() {
+		if fetchErr == nil {
+			fetchErr = f.lastErr
+		}
+		switch {
+		case fetchErr == nil:
+			return nil, fmt.Errorf("%w: cached JWKS expired", ErrKeyNotFound)
+		case f.fetchedAt.IsZero():
+			return nil, fetchErr
+		default:
+			return nil, fmt.Errorf("%w: cached JWKS expired: %w", ErrKeyNotFound, fetchErr)
+		}
+	}
+
+	key, err := f.set.PublicKey(kid)
+	if err != nil && fetchErr != nil {
+		return nil, fetchErr
+	}
+	return key, err
+}
+
+// Refresh fetches the key set now, replacing the cache on success.
+// A fetch already in progress is shared rather than repeated.
+func (f *JWKSFetcher) Refresh(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fetch(ctx)
+}
+
+// fetch refreshes the key set, or waits for the fetch already in flight.
+// It is called with f.mu held and returns with it held, releasing it while
+// the request runs.
+func (f *JWKSFetcher) fetch(ctx context.Context) error {
+	if call := f.inflight; call != nil {
+		f.mu.Unlock()
+		defer f.mu.Lock()
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	call := &jwksFetch{done: make(chan struct{})}
+	f.inflight = call
+	started := f.clock()
+	f.triedAt = started
+	f.mu.Unlock()
+
+	set, err := f.download(ctx)
+
+	f.mu.Lock()
+	if err == nil {
+		f.set = set
+		f.fetchedAt = started
+	}
+	f.lastErr = err
+	f.inflight = nil
+	call.err = err
+	close(call.done)
+	return err
+}
+
+// download requests and decodes the key set
+func (f *JWKSFetcher) download(ctx context.Context) (JWKSet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.URL, nil)
+	if err != nil {
+		return JWKSet{}, fmt.Errorf("fetch JWKS: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return JWKSet{}, fmt.Errorf("fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return JWKSet{}, fmt.Errorf("fetch JWKS: %s returned %s", f.URL, resp.Status)
+	}
+
+	var set JWKSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return JWKSet{}, fmt.Errorf("fetch JWKS: decode: %w", err)
+	}
+	return set, nil
+}
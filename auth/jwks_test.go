@@ -0,0 +1,345 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testKeys(t *testing.T) map[string]crypto.Signer {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]crypto.Signer{"RS256": rsaKey, "ES256": ecKey, "EdDSA": edKey}
+}
+
+func TestAsymmetricSigning(t *testing.T) {
+	for alg, key := range testKeys(t) {
+		t.Run(alg, func(t *testing.T) {
+			signer := JWTConfig{SigningKey: key, KeyID: "key-1", Issuer: "test-app"}
+			token, err := GenerateAccessToken(signer, "user-123")
+			if err != nil {
+				t.Fatalf("GenerateAccessToken() error = %v", err)
+			}
+
+			// The signer verifies with its own public key.
+			claims, err := ValidateAccessToken(signer, token)
+			if err != nil {
+				t.Fatalf("ValidateAccessToken(signer) error = %v", err)
+			}
+			if claims.Subject != "user-123" {
+				t.Errorf("Subject = %q, want %q", claims.Subject, "user-123")
+			}
+
+			// A verifier only needs the JWKS.
+			jwk, err := NewJWK("key-1", key.Public())
+			if err != nil {
+				t.Fatalf("NewJWK() error = %v", err)
+			}
+			if jwk.Alg != alg {
+				t.Errorf("Alg = %q, want %q", jwk.Alg, alg)
+			}
+			data, err := json.Marshal(JWKSet{Keys: []JWK{jwk}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var set JWKSet
+			if err := json.Unmarshal(data, &set); err != nil {
+				t.Fatal(err)
+			}
+			verifier := JWTConfig{Keys: set, Issuer: "test-app"}
+			if _, err := ValidateAccessToken(verifier, token); err != nil {
+				t.Errorf("ValidateAccessToken(verifier) error = %v", err)
+			}
+
+			// An HMAC-only config cannot verify it.
+			hmac := JWTConfig{Secret: []byte("this-is-a-test-secret-key-32-bytes!")}
+			if _, err := ValidateAccessToken(hmac, token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("HMAC config: error = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestAsymmetricSigning_WrongKey(t *testing.T) {
+	keys := testKeys(t)
+	token, err := GenerateAccessToken(JWTConfig{SigningKey: keys["ES256"], KeyID: "a"}, "user-123")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk, err := NewJWK("a", &other.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateAccessToken(JWTConfig{Keys: JWKSet{Keys: []JWK{jwk}}}, token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestJWTConfig_HMACTokenRejectedWithoutSecret(t *testing.T) {
+	token, err := GenerateAccessToken(JWTConfig{Secret: []byte("this-is-a-test-secret-key-32-bytes!")}, "user-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := testKeys(t)
+	if _, err := ValidateAccessToken(JWTConfig{SigningKey: keys["RS256"]}, token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestParsePrivateKeyPEM(t *testing.T) {
+	for alg, key := range testKeys(t) {
+		t.Run(alg, func(t *testing.T) {
+			der, err := x509.MarshalPKCS8PrivateKey(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := ParsePrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+			if err != nil {
+				t.Fatalf("ParsePrivateKeyPEM() error = %v", err)
+			}
+			method, err := signingMethod(parsed)
+			if err != nil || method.Alg() != alg {
+				t.Errorf("signingMethod() = %v, %v, want %s", method, err, alg)
+			}
+
+			pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ParsePublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})); err != nil {
+				t.Errorf("ParsePublicKeyPEM() error = %v", err)
+			}
+		})
+	}
+
+	if _, err := ParsePrivateKeyPEM([]byte("not a key")); err == nil {
+		t.Error("ParsePrivateKeyPEM(garbage) error = nil")
+	}
+}
+
+func TestJWKSetPublicKey(t *testing.T) {
+	keys := testKeys(t)
+	a, _ := NewJWK("a", keys["ES256"].Public())
+	b, _ := NewJWK("b", keys["EdDSA"].Public())
+
+	set := JWKSet{Keys: []JWK{a, b}}
+	if _, err := set.PublicKey("b"); err != nil {
+		t.Errorf("PublicKey(b) error = %v", err)
+	}
+	if _, err := set.PublicKey("c"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("PublicKey(c) error = %v, want ErrKeyNotFound", err)
+	}
+	if _, err := set.PublicKey(""); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("PublicKey(\"\") with two keys: error = %v, want ErrKeyNotFound", err)
+	}
+	if _, err := (JWKSet{Keys: []JWK{a}}).PublicKey(""); err != nil {
+		t.Errorf("PublicKey(\"\") with one key: error = %v", err)
+	}
+}
+
+func TestJWKSFetcher(t *testing.T) {
+	keys := testKeys(t)
+	first, _ := NewJWK("first", keys["RS256"].Public())
+	second, _ := NewJWK("second", keys["ES256"].Public())
+
+	var fetches atomic.Int32
+	published := []JWK{first}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(JWKSet{Keys: published})
+	}))
+	defer srv.Close()
+
+	fetcher := NewJWKSFetcher(srv.URL)
+	fetcher.RefreshInterval = 1 // Allow refetching immediately
+
+	cfg := JWTConfig{SigningKey: keys["RS256"], KeyID: "first"}
+	token, err := GenerateAccessToken(cfg, "user-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := JWTConfig{Keys: fetcher}
+	for range 3 {
+		if _, err := ValidateAccessToken(verifier, token); err != nil {
+			t.Fatalf("ValidateAccessToken() error = %v", err)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("fetches = %d, want 1 (cached)", n)
+	}
+
+	// A rotated key is picked up by refetching on the unknown kid.
+	published = []JWK{first, second}
+	token, err = GenerateAccessToken(JWTConfig{SigningKey: keys["ES256"], KeyID: "second"}, "user-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateAccessToken(verifier, token); err != nil {
+		t.Errorf("ValidateAccessToken(rotated key) error = %v", err)
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Errorf("fetches = %d, want 2", n)
+	}
+}
+
+func TestJWKSFetcher_RateLimitsUnknownKeys(t *testing.T) {
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		_, _ = w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer srv.Close()
+
+	fetcher := NewJWKSFetcher(srv.URL)
+	for range 5 {
+		if _, err := fetcher.PublicKey("missing"); !errors.Is(err, ErrKeyNotFound) {
+			t.Fatalf("PublicKey() error = %v, want ErrKeyNotFound", err)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("fetches = %d, want 1", n)
+	}
+}
+
+func TestJWKSFetcher_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	if _, err := NewJWKSFetcher(srv.URL).PublicKey("a"); err == nil {
+		t.Error("PublicKey() error = nil, want fetch error")
+	}
+}
+
+func TestJWKSFetcher_StaleGrace(t *testing.T) {
+	keys := testKeys(t)
+	jwk, _ := NewJWK("first", keys["ES256"].Public())
+
+	var failing atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(JWKSet{Keys: []JWK{jwk}})
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	fetcher := NewJWKSFetcher(srv.URL)
+	fetcher.now = func() time.Time { return now }
+	if _, err := fetcher.PublicKey("first"); err != nil {
+		t.Fatalf("PublicKey() error = %v", err)
+	}
+
+	// Past TTL with the endpoint down, the cached key is served during the grace period
+	failing.Store(true)
+	now = now.Add(DefaultJWKSCacheTTL + time.Minute)
+	if _, err := fetcher.PublicKey("first"); err != nil {
+		t.Errorf("PublicKey() within grace error = %v", err)
+	}
+
+	now = now.Add(DefaultJWKSStaleGrace)
+	if _, err := fetcher.PublicKey("first"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("PublicKey() past grace error = %v, want ErrKeyNotFound", err)
+	}
+
+	// A successful refetch restores the cache
+	failing.Store(false)
+	now = now.Add(DefaultJWKSRefreshInterval)
+	if _, err := fetcher.PublicKey("first"); err != nil {
+		t.Errorf("PublicKey() after recovery error = %v", err)
+	}
+}
+
+func TestJWKSFetcher_SharedFetchOutsideLock(t *testing.T) {
+	keys := testKeys(t)
+	first, _ := NewJWK("first", keys["RS256"].Public())
+	second, _ := NewJWK("second", keys["ES256"].Public())
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-release
+		_ = json.NewEncoder(w).Encode(JWKSet{Keys: []JWK{first, second}})
+	}))
+	defer srv.Close()
+
+	fetcher := NewJWKSFetcher(srv.URL)
+	fetcher.RefreshInterval = 1
+	fetcher.set = JWKSet{Keys: []JWK{first}}
+	fetcher.fetchedAt = time.Now()
+
+	// Unknown kid: every caller waits on one blocked refetch
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := fetcher.PublicKey("second")
+			errs <- err
+		}()
+	}
+	for fetches.Load() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Cached keys stay available while the fetch is in flight
+	done := make(chan error, 1)
+	go func() {
+		_, err := fetcher.PublicKey("first")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("PublicKey(cached) error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("PublicKey(cached) blocked on the in-flight fetch")
+	}
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("PublicKey(rotated) error = %v", err)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("fetches = %d, want 1 shared fetch", n)
+	}
+}
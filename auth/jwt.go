@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"crypto"
 	"errors"
 	"fmt"
 	"time"
@@ -18,8 +19,22 @@ const (
 // JWTConfig holds configuration for JWT generation and validation.
 type JWTConfig struct {
 	// Secret is the HMAC signing key (must be at least 32 bytes).
+	// Not needed when SigningKey is set.
 	Secret []byte
 
+	// SigningKey is an RSA, ECDSA, or Ed25519 private key. When set, tokens
+	// are signed with RS256, ES256 (ES384/ES512 for larger curves), or EdDSA
+	// instead of HS256, so services can verify them without the secret.
+	SigningKey crypto.Signer
+
+	// KeyID is written to the "kid" header of signed tokens, so verifiers
+	// can pick the key from a JWKS.
+	KeyID string
+
+	// Keys supplies public keys to verify RS/ES/EdDSA tokens, e.g. a
+	// JWKSFetcher. Defaults to the public half of SigningKey.
+	Keys KeySet
+
 	// Issuer is the token issuer (e.g., "my-app").
 	Issuer string
 
@@ -70,8 +85,8 @@ func GenerateAccessToken(cfg JWTConfig, subject string) (string, error) {
 // GenerateAccessTokenWithClaims creates a JWT with custom claims.
 // The builder function receives a BaseClaims with standard fields pre-populated.
 func GenerateAccessTokenWithClaims[T jwt.Claims](cfg JWTConfig, builder func(BaseClaims) T) (string, error) {
-	if len(cfg.Secret) < 32 {
-		return "", ErrSecretTooShort
+	if _, _, err := cfg.signingKey(); err != nil {
+		return "", err
 	}
 
 	tokenID, err := nanoid.New()
@@ -89,15 +104,13 @@ func GenerateAccessTokenWithClaims[T jwt.Claims](cfg JWTConfig, builder func(Bas
 		},
 	}
 
-	claims := builder(base)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(cfg.Secret)
+	return cfg.sign(builder(base), "")
 }
 
 // ValidateAccessToken parses and validates a JWT, returning BaseClaims.
 func ValidateAccessToken(cfg JWTConfig, tokenString string) (*BaseClaims, error) {
 	claims := &BaseClaims{}
-	if err := validateAccessTokenInto(cfg, tokenString, claims); err != nil {
+	if err := validateTokenInto(cfg, tokenString, claims, false); err != nil {
 		return nil, err
 	}
 	return claims, nil
@@ -114,16 +127,13 @@ func ValidateAccessToken(cfg JWTConfig, tokenString string) (*BaseClaims, error)
 //	}
 //	fmt.Println(claims.TenantID)
 func ValidateAccessTokenAs(cfg JWTConfig, tokenString string, claims jwt.Claims) error {
-	return validateAccessTokenInto(cfg, tokenString, claims)
+	return validateTokenInto(cfg, tokenString, claims, false)
 }
 
-func validateAccessTokenInto(cfg JWTConfig, tokenString string, claims jwt.Claims) error {
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return cfg.Secret, nil
-	})
+// validateTokenInto parses tokenString into claims, requiring a refresh
+// token if refresh is set and rejecting one otherwise.
+func validateTokenInto(cfg JWTConfig, tokenString string, claims jwt.Claims, refresh bool) error {
+	token, err := jwt.ParseWithClaims(tokenString, claims, cfg.verificationKey)
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			return ErrTokenExpired
@@ -131,7 +141,7 @@ func validateAccessTokenInto(cfg JWTConfig, tokenString string, claims jwt.Claim
 		return ErrInvalidToken
 	}
 
-	if !token.Valid {
+	if !token.Valid || isRefreshToken(token) != refresh {
 		return ErrInvalidToken
 	}
 
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeySet looks up the public key that verifies a token. The kid argument is
+// the token's "kid" header, or "" if it has none.
+type KeySet interface {
+	PublicKey(kid string) (crypto.PublicKey, error)
+}
+
+// signingMethod returns the JWT algorithm for a private key
+func signingMethod(key crypto.Signer) (jwt.SigningMethod, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return jwt.SigningMethodES256, nil
+		case elliptic.P384():
+			return jwt.SigningMethodES384, nil
+		case elliptic.P521():
+			return jwt.SigningMethodES512, nil
+		}
+	case ed25519.PrivateKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, key)
+}
+
+// sign signs claims with the configured key, setting the "kid" header and,
+// if typ is non-empty, the "typ" header.
+func (c JWTConfig) sign(claims jwt.Claims, typ string) (string, error) {
+	method, key, err := c.signingKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	if c.KeyID != "" {
+		token.Header["kid"] = c.KeyID
+	}
+	if typ != "" {
+		token.Header["typ"] = typ
+	}
+	return token.SignedString(key)
+}
+
+// signingKey returns the algorithm and key used to sign tokens: SigningKey
+// if set, otherwise Secret with HS256.
+func (c JWTConfig) signingKey() (jwt.SigningMethod, any, error) {
+	if c.SigningKey != nil {
+		method, err := signingMethod(c.SigningKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		return method, c.SigningKey, nil
+	}
+	if len(c.Secret) < 32 {
+		return nil, nil, ErrSecretTooShort
+	}
+	return jwt.SigningMethodHS256, c.Secret, nil
+}
+
+// verificationKey is the jwt.Keyfunc for validation. HMAC tokens need
+// Secret; signed tokens are checked against Keys, or the public half of
+// SigningKey.
+func (c JWTConfig) verificationKey(token *jwt.Token) (any, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if len(c.Secret) == 0 {
+			return nil, errors.New("HMAC token but no secret configured")
+		}
+		return c.Secret, nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA, *jwt.SigningMethodEd25519:
+		kid, _ := token.Header["kid"].(string)
+		if c.Keys != nil {
+			return c.Keys.PublicKey(kid)
+		}
+		if c.SigningKey != nil && (kid == "" || kid == c.KeyID) {
+			return c.SigningKey.Public(), nil
+		}
+		return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, kid)
+	default:
+		return nil, errors.New("unexpected signing method")
+	}
+}
+
+// ParsePrivateKeyPEM parses an RSA, ECDSA, or Ed25519 private key in PKCS#8,
+// PKCS#1, or SEC 1 PEM form, for use as JWTConfig.SigningKey.
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("parse private key: no PEM block found")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, key)
+	}
+	if _, err := signingMethod(signer); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// ParsePublicKeyPEM parses a PKIX ("PUBLIC KEY") or PKCS#1 ("RSA PUBLIC
+// KEY") PEM public key.
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("parse public key: no PEM block found")
+	}
+
+	var key any
+	var err error
+	if block.Type == "RSA PUBLIC KEY" {
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	} else {
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+	return key, nil
+}
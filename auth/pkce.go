@@ -0,0 +1,291 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// PKCEMethodS256 is the code challenge method used by NewPKCE (RFC 7636).
+// The "plain" method is not supported: it offers no protection if the
+// authorization request is observed.
+const PKCEMethodS256 = "S256"
+
+// Callback listener defaults.
+const (
+	DefaultCallbackAddr = "127.0.0.1:0"
+	DefaultCallbackPath = "/callback"
+)
+
+// PKCE is a proof key for one authorization request. Send Challenge with
+// the authorization URL and Verifier with the token exchange; a stolen
+// authorization code is useless without the verifier.
+type PKCE struct {
+	Verifier  string
+	Challenge string
+	Method    string
+}
+
+// NewPKCE creates a random 43-character verifier and its S256 challenge.
+func NewPKCE() (*PKCE, error) {
+	verifier, err := randomURLString(32)
+	if err != nil {
+		return nil, fmt.Errorf("generate PKCE verifier: %w", err)
+	}
+	return &PKCE{
+		Verifier:  verifier,
+		Challenge: S256Challenge(verifier),
+		Method:    PKCEMethodS256,
+	}, nil
+}
+
+// S256Challenge returns the S256 code challenge for verifier.
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// VerifyPKCE reports whether verifier matches an S256 challenge, for
+// servers that accept PKCE.
+func VerifyPKCE(verifier, challenge string) bool {
+	return subtle.ConstantTimeCompare([]byte(S256Challenge(verifier)), []byte(challenge)) == 1
+}
+
+// AuthCodeOptions returns the parameters to pass to oauth2.Config.AuthCodeURL.
+func (p *PKCE) AuthCodeOptions() []oauth2.AuthCodeOption {
+	return []oauth2.AuthCodeOption{
+		oauth2.SetAuthURLParam("code_challenge", p.Challenge),
+		oauth2.SetAuthURLParam("code_challenge_method", p.Method),
+	}
+}
+
+// ExchangeOptions returns the parameters to pass to oauth2.Config.Exchange.
+func (p *PKCE) ExchangeOptions() []oauth2.AuthCodeOption {
+	return []oauth2.AuthCodeOption{oauth2.VerifierOption(p.Verifier)}
+}
+
+// GenerateState creates a random state value to bind a callback to the
+// authorization request that started it.
+func GenerateState() (string, error) {
+	state, err := randomURLString(24)
+	if err != nil {
+		return "", fmt.Errorf("generate state: %w", err)
+	}
+	return state, nil
+}
+
+func randomURLString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// OAuthError is an error returned to the callback by the authorization
+// server, such as "access_denied" when the user declines.
+type OAuthError struct {
+	Code        string
+	Description string
+	URI         string
+}
+
+// Error implements the error interface.
+func (e *OAuthError) Error() string {
+	if e.Description == "" {
+		return "oauth: " + e.Code
+	}
+	return fmt.Sprintf("oauth: %s: %s", e.Code, e.Description)
+}
+
+// CallbackConfig configures a local redirect listener.
+type CallbackConfig struct {
+	// Addr is the address to listen on. Defaults to DefaultCallbackAddr
+	// (loopback, random port). Use a fixed port if the provider requires
+	// an exact redirect URL.
+	Addr string
+
+	// Path is the redirect path. Defaults to DefaultCallbackPath.
+	Path string
+
+	// State is the expected state parameter. Callbacks with another state
+	// fail with ErrStateMismatch.
+	State string
+}
+
+func (c CallbackConfig) addr() string {
+	if c.Addr == "" {
+		return DefaultCallbackAddr
+	}
+	return c.Addr
+}
+
+func (c CallbackConfig) path() string {
+	if c.Path == "" {
+		return DefaultCallbackPath
+	}
+	return c.Path
+}
+
+// Callback is the result of a successful authorization redirect.
+type Callback struct {
+	Code  string
+	State string
+}
+
+// CallbackListener serves the redirect URI of a desktop OAuth flow on a
+// loopback address (RFC 8252) and delivers the first callback it gets.
+type CallbackListener struct {
+	server   *http.Server
+	listener net.Listener
+	path     string
+	state    string
+
+	once   sync.Once
+	result chan callbackResult
+}
+
+type callbackResult struct {
+	callback *Callback
+	err      error
+}
+
+// ListenForCallback starts a callback listener. Use RedirectURL as the
+// OAuth redirect URL, then Wait for the browser to return.
+func ListenForCallback(cfg CallbackConfig) (*CallbackListener, error) {
+	ln, err := net.Listen("tcp", cfg.addr())
+	if err != nil {
+		return nil, fmt.Errorf("listen for callback: %w", err)
+	}
+
+	l := &CallbackListener{
+		listener: ln,
+		path:     cfg.path(),
+		state:    cfg.State,
+		result:   make(chan callbackResult, 1),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(l.path, l.handle)
+	l.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() { _ = l.server.Serve(ln) }()
+	return l, nil
+}
+
+// RedirectURL is the URL the authorization server should redirect to.
+func (l *CallbackListener) RedirectURL() string {
+	return "http://" + l.listener.Addr().String() + l.path
+}
+
+// Wait blocks until the callback arrives or ctx is done, then shuts the
+// listener down. A provider error is returned as *OAuthError.
+func (l *CallbackListener) Wait(ctx context.Context) (*Callback, error) {
+	defer func() { _ = l.Close() }()
+
+	select {
+	case r := <-l.result:
+		return r.callback, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops the listener.
+func (l *CallbackListener) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return l.server.Shutdown(ctx)
+}
+
+func (l *CallbackListener) handle(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var res callbackResult
+	switch {
+	case query.Get("error") != "":
+		res.err = &OAuthError{
+			Code:        query.Get("error"),
+			Description: query.Get("error_description"),
+			URI:         query.Get("error_uri"),
+		}
+	case l.state != "" && subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(l.state)) != 1:
+		res.err = ErrStateMismatch
+	case query.Get("code") == "":
+		res.err = errors.New("oauth: callback has no authorization code")
+	default:
+		res.callback = &Callback{Code: query.Get("code"), State: query.Get("state")}
+	}
+
+	delivered := false
+	l.once.Do(func() {
+		l.result <- res
+		delivered = true
+	})
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	switch {
+	case !delivered:
+		w.WriteHeader(http.StatusConflict)
+		writeCallbackPage(w, "This sign-in link has already been used.")
+	case res.err != nil:
+		w.WriteHeader(http.StatusBadRequest)
+		writeCallbackPage(w, "Sign-in failed: "+res.err.Error())
+	default:
+		writeCallbackPage(w, "Signed in. You can close this window.")
+	}
+}
+
+func writeCallbackPage(w http.ResponseWriter, message string) {
+	_, _ = fmt.Fprintf(w, "<!DOCTYPE html><html><body><p>%s</p></body></html>", html.EscapeString(message))
+}
+
+// AuthorizeWithPKCE runs the authorization code flow with PKCE for a
+// desktop CLI: it starts a callback listener, sets conf's redirect URL to
+// it, calls open with the authorization URL (typically to launch a
+// browser), and exchanges the returned code for a token. conf is not
+// modified.
+func AuthorizeWithPKCE(ctx context.Context, conf *oauth2.Config, cfg CallbackConfig, open func(authURL string) error) (*oauth2.Token, error) {
+	pkce, err := NewPKCE()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.State == "" {
+		if cfg.State, err = GenerateState(); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := ListenForCallback(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = listener.Close() }()
+
+	c := *conf
+	c.RedirectURL = listener.RedirectURL()
+	if err := open(c.AuthCodeURL(cfg.State, pkce.AuthCodeOptions()...)); err != nil {
+		return nil, fmt.Errorf("open authorization URL: %w", err)
+	}
+
+	callback, err := listener.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := c.Exchange(ctx, callback.Code, pkce.ExchangeOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("exchange authorization code: %w", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestNewPKCE(t *testing.T) {
+	p, err := NewPKCE()
+	if err != nil {
+		t.Fatalf("NewPKCE() error = %v", err)
+	}
+	if len(p.Verifier) != 43 {
+		t.Errorf("len(Verifier) = %d, want 43", len(p.Verifier))
+	}
+	if p.Method != PKCEMethodS256 {
+		t.Errorf("Method = %q, want S256", p.Method)
+	}
+	if !VerifyPKCE(p.Verifier, p.Challenge) {
+		t.Error("VerifyPKCE(verifier, challenge) = false")
+	}
+	if VerifyPKCE(p.Verifier+"x", p.Challenge) {
+		t.Error("VerifyPKCE(wrong verifier) = true")
+	}
+
+	// RFC 7636 appendix B
+	if got := S256Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"); got != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("S256Challenge() = %q", got)
+	}
+}
+
+func callbackGet(t *testing.T, rawURL string) int {
+	t.Helper()
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		t.Fatalf("GET %s: %v", rawURL, err)
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestCallbackListener(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		l, err := ListenForCallback(CallbackConfig{State: "xyz"})
+		if err != nil {
+			t.Fatalf("ListenForCallback() error = %v", err)
+		}
+		if code := callbackGet(t, l.RedirectURL()+"?code=abc&state=xyz"); code != http.StatusOK {
+			t.Errorf("status = %d, want 200", code)
+		}
+		cb, err := l.Wait(ctx)
+		if err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+		if cb.Code != "abc" {
+			t.Errorf("Code = %q, want abc", cb.Code)
+		}
+	})
+
+	t.Run("state mismatch", func(t *testing.T) {
+		l, err := ListenForCallback(CallbackConfig{State: "xyz"})
+		if err != nil {
+			t.Fatalf("ListenForCallback() error = %v", err)
+		}
+		if code := callbackGet(t, l.RedirectURL()+"?code=abc&state=evil"); code != http.StatusBadRequest {
+			t.Errorf("status = %d, want 400", code)
+		}
+		if _, err := l.Wait(ctx); !errors.Is(err, ErrStateMismatch) {
+			t.Errorf("Wait() error = %v, want ErrStateMismatch", err)
+		}
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		l, err := ListenForCallback(CallbackConfig{Path: "/cb"})
+		if err != nil {
+			t.Fatalf("ListenForCallback() error = %v", err)
+		}
+		callbackGet(t, l.RedirectURL()+"?error=access_denied&error_description=User+declined")
+		_, err = l.Wait(ctx)
+		var oauthErr *OAuthError
+		if !errors.As(err, &oauthErr) || oauthErr.Code != "access_denied" || oauthErr.Description != "User declined" {
+			t.Errorf("Wait() error = %v, want access_denied OAuthError", err)
+		}
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		l, err := ListenForCallback(CallbackConfig{})
+		if err != nil {
+			t.Fatalf("ListenForCallback() error = %v", err)
+		}
+		short, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := l.Wait(short); !errors.Is(err, context.Canceled) {
+			t.Errorf("Wait() error = %v, want context.Canceled", err)
+		}
+	})
+}
+
+func TestAuthorizeWithPKCE(t *testing.T) {
+	var challenge string
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm() error = %v", err)
+		}
+		if !VerifyPKCE(r.Form.Get("code_verifier"), challenge) || r.Form.Get("code") != "the-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "tok", "token_type": "Bearer"})
+	}))
+	defer tokenServer.Close()
+
+	conf := &oauth2.Config{
+		ClientID: "cli",
+		Endpoint: oauth2.Endpoint{AuthURL: "https://auth.example.com/authorize", TokenURL: tokenServer.URL},
+	}
+
+	// The "browser" follows the redirect straight back to the listener.
+	browser := func(authURL string) error {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		if q.Get("code_challenge_method") != PKCEMethodS256 {
+			t.Errorf("code_challenge_method = %q", q.Get("code_challenge_method"))
+		}
+		challenge = q.Get("code_challenge")
+		go func() {
+			resp, err := http.Get(q.Get("redirect_uri") + "?code=the-code&state=" + url.QueryEscape(q.Get("state")))
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	token, err := AuthorizeWithPKCE(ctx, conf, CallbackConfig{}, browser)
+	if err != nil {
+		t.Fatalf("AuthorizeWithPKCE() error = %v", err)
+	}
+	if token.AccessToken != "tok" {
+		t.Errorf("AccessToken = %q, want tok", token.AccessToken)
+	}
+	if conf.RedirectURL != "" {
+		t.Error("AuthorizeWithPKCE modified conf")
+	}
+}
@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	nanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// RefreshTokenType is the "typ" header of signed refresh tokens. Access token
+// validation rejects tokens with this header, and refresh token validation
+// requires it, so one can never be used as the other.
+const RefreshTokenType = "refresh+jwt"
+
+// Denylist records the IDs (jti) of refresh tokens that must no longer be
+// accepted. Entries only need to be kept until the token would have expired.
+type Denylist interface {
+	// Revoke denylists jti until expiresAt. It must be atomic: when two
+	// calls race for the same jti, exactly one reports alreadyRevoked false.
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) (alreadyRevoked bool, err error)
+
+	// IsRevoked reports whether jti has been denylisted.
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// GenerateSignedRefreshToken creates a signed refresh token for subject that
+// lives for cfg.RefreshTokenTTL. Unlike GenerateRefreshToken, it needs no
+// server-side storage: it is checked with ValidateRefreshToken and a Denylist.
+func GenerateSignedRefreshToken(cfg JWTConfig, subject string) (string, error) {
+	if _, _, err := cfg.signingKey(); err != nil {
+		return "", err
+	}
+
+	tokenID, err := nanoid.New()
+	if err != nil {
+		return "", fmt.Errorf("generate token ID: %w", err)
+	}
+
+	now := time.Now()
+	claims := BaseClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    cfg.Issuer,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(cfg.refreshTTL())),
+			ID:        tokenID,
+		},
+	}
+
+	return cfg.sign(claims, RefreshTokenType)
+}
+
+// GenerateSignedTokenPair creates an access token and a signed refresh token
+// for subject. Use RotateRefreshToken to exchange the refresh token for a new
+// pair when the access token expires.
+func GenerateSignedTokenPair(cfg JWTConfig, subject string) (*TokenPair, error) {
+	accessToken, err := GenerateAccessToken(cfg, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := GenerateSignedRefreshToken(cfg, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(cfg.accessTTL().Seconds()),
+	}, nil
+}
+
+// ValidateRefreshToken parses and validates a signed refresh token. If
+// denylist is non-nil, revoked tokens return ErrTokenRevoked.
+func ValidateRefreshToken(ctx context.Context, cfg JWTConfig, tokenString string, denylist Denylist) (*BaseClaims, error) {
+	claims := &BaseClaims{}
+	if err := validateTokenInto(cfg, tokenString, claims, true); err != nil {
+		return nil, err
+	}
+	if claims.ID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	if denylist != nil {
+		revoked, err := denylist.IsRevoked(ctx, claims.ID)
+		if err != nil {
+			return nil, fmt.Errorf("check denylist: %w", err)
+		}
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+	return claims, nil
+}
+
+// RotateRefreshToken exchanges a signed refresh token for a new token pair
+// for the same subject, denylisting the old refresh token so it cannot be
+// used again. Presenting an already rotated token returns ErrTokenRevoked,
+// which callers may treat as a sign the token was stolen. Of concurrent
+// rotations of one token, only the first to revoke it gets a new pair.
+func RotateRefreshToken(ctx context.Context, cfg JWTConfig, tokenString string, denylist Denylist) (*TokenPair, error) {
+	if denylist == nil {
+		return nil, ErrNoDenylist
+	}
+
+	claims, err := ValidateRefreshToken(ctx, cfg, tokenString, denylist)
+	if err != nil {
+		return nil, err
+	}
+
+	alreadyRevoked, err := denylist.Revoke(ctx, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, fmt.Errorf("revoke refresh token: %w", err)
+	}
+	if alreadyRevoked {
+		return nil, ErrTokenRevoked
+	}
+
+	return GenerateSignedTokenPair(cfg, claims.Subject)
+}
+
+// MemoryDenylist is an in-process Denylist, suitable for single-instance
+// services and tests. Expired entries are dropped as new ones are added.
+type MemoryDenylist struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+// NewMemoryDenylist creates an empty in-memory denylist.
+func NewMemoryDenylist() *MemoryDenylist {
+	return &MemoryDenylist{revoked: make(map[string]time.Time)}
+}
+
+// Revoke implements Denylist.
+func (d *MemoryDenylist) Revoke(_ context.Context, jti string, expiresAt time.Time) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for id, until := range d.revoked {
+		if now.After(until) {
+			delete(d.revoked, id)
+		}
+	}
+	if _, ok := d.revoked[jti]; ok {
+		return true, nil
+	}
+	d.revoked[jti] = expiresAt
+	return false, nil
+}
+
+// IsRevoked implements Denylist.
+func (d *MemoryDenylist) IsRevoked(_ context.Context, jti string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, ok := d.revoked[jti]
+	return ok, nil
+}
+
+// isRefreshToken reports whether a parsed token carries the refresh type header
+func isRefreshToken(token *jwt.Token) bool {
+	typ, _ := token.Header["typ"].(string)
+	return typ == RefreshTokenType
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var refreshCfg = JWTConfig{
+	Secret:          []byte("this-is-a-test-secret-key-32-bytes!"),
+	Issuer:          "test-app",
+	RefreshTokenTTL: time.Hour,
+}
+
+func TestValidateRefreshToken(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("valid token", func(t *testing.T) {
+		token, err := GenerateSignedRefreshToken(refreshCfg, "user-123")
+		if err != nil {
+			t.Fatalf("GenerateSignedRefreshToken() error = %v", err)
+		}
+
+		claims, err := ValidateRefreshToken(ctx, refreshCfg, token, nil)
+		if err != nil {
+			t.Fatalf("ValidateRefreshToken() error = %v", err)
+		}
+		if claims.Subject != "user-123" {
+			t.Errorf("Subject = %q, want %q", claims.Subject, "user-123")
+		}
+		ttl := time.Until(claims.ExpiresAt.Time)
+		if ttl <= 59*time.Minute || ttl > time.Hour {
+			t.Errorf("expires in %v, want RefreshTokenTTL (1h)", ttl)
+		}
+	})
+
+	t.Run("access token rejected", func(t *testing.T) {
+		token, err := GenerateAccessToken(refreshCfg, "user-123")
+		if err != nil {
+			t.Fatalf("GenerateAccessToken() error = %v", err)
+		}
+		if _, err := ValidateRefreshToken(ctx, refreshCfg, token, nil); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("error = %v, want ErrInvalidToken", err)
+		}
+	})
+
+	t.Run("not accepted as access token", func(t *testing.T) {
+		token, err := GenerateSignedRefreshToken(refreshCfg, "user-123")
+		if err != nil {
+			t.Fatalf("GenerateSignedRefreshToken() error = %v", err)
+		}
+		if _, err := ValidateAccessToken(refreshCfg, token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("error = %v, want ErrInvalidToken", err)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		cfg := refreshCfg
+		cfg.RefreshTokenTTL = -time.Minute
+		token, err := GenerateSignedRefreshToken(cfg, "user-123")
+		if err != nil {
+			t.Fatalf("GenerateSignedRefreshToken() error = %v", err)
+		}
+		if _, err := ValidateRefreshToken(ctx, cfg, token, nil); !errors.Is(err, ErrTokenExpired) {
+			t.Errorf("error = %v, want ErrTokenExpired", err)
+		}
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		token, err := GenerateSignedRefreshToken(refreshCfg, "user-123")
+		if err != nil {
+			t.Fatalf("GenerateSignedRefreshToken() error = %v", err)
+		}
+		claims, err := ValidateRefreshToken(ctx, refreshCfg, token, nil)
+		if err != nil {
+			t.Fatalf("ValidateRefreshToken() error = %v", err)
+		}
+
+		denylist := NewMemoryDenylist()
+		if _, err := denylist.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			t.Fatalf("Revoke() error = %v", err)
+		}
+		if _, err := ValidateRefreshToken(ctx, refreshCfg, token, denylist); !errors.Is(err, ErrTokenRevoked) {
+			t.Errorf("error = %v, want ErrTokenRevoked", err)
+		}
+	})
+
+	t.Run("secret too short", func(t *testing.T) {
+		_, err := GenerateSignedRefreshToken(JWTConfig{Secret: []byte("short")}, "user-123")
+		if !errors.Is(err, ErrSecretTooShort) {
+			t.Errorf("error = %v, want ErrSecretTooShort", err)
+		}
+	})
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	denylist := NewMemoryDenylist()
+
+	pair, err := GenerateSignedTokenPair(refreshCfg, "user-123")
+	if err != nil {
+		t.Fatalf("GenerateSignedTokenPair() error = %v", err)
+	}
+
+	rotated, err := RotateRefreshToken(ctx, refreshCfg, pair.RefreshToken, denylist)
+	if err != nil {
+		t.Fatalf("RotateRefreshToken() error = %v", err)
+	}
+	if rotated.RefreshToken == pair.RefreshToken {
+		t.Error("rotation returned the same refresh token")
+	}
+
+	claims, err := ValidateAccessToken(refreshCfg, rotated.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() error = %v", err)
+	}
+	if claims.Subject != "user-123" {
+		t.Errorf("Subject = %q, want %q", claims.Subject, "user-123")
+	}
+
+	// The old token is spent; the new one still works.
+	if _, err := RotateRefreshToken(ctx, refreshCfg, pair.RefreshToken, denylist); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("reusing old token: error = %v, want ErrTokenRevoked", err)
+	}
+	if _, err := RotateRefreshToken(ctx, refreshCfg, rotated.RefreshToken, denylist); err != nil {
+		t.Errorf("RotateRefreshToken(new token) error = %v", err)
+	}
+
+	if _, err := RotateRefreshToken(ctx, refreshCfg, rotated.RefreshToken, nil); !errors.Is(err, ErrNoDenylist) {
+		t.Errorf("nil denylist: error = %v, want ErrNoDenylist", err)
+	}
+}
+
+func TestRotateRefreshToken_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	denylist := NewMemoryDenylist()
+
+	pair, err := GenerateSignedTokenPair(refreshCfg, "user-123")
+	if err != nil {
+		t.Fatalf("GenerateSignedTokenPair() error = %v", err)
+	}
+
+	const replays = 20
+	var wg sync.WaitGroup
+	var rotated, revoked atomic.Int32
+	for range replays {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := RotateRefreshToken(ctx, refreshCfg, pair.RefreshToken, denylist)
+			switch {
+			case err == nil:
+				rotated.Add(1)
+			case errors.Is(err, ErrTokenRevoked):
+				revoked.Add(1)
+			default:
+				t.Errorf("RotateRefreshToken() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if rotated.Load() != 1 || revoked.Load() != replays-1 {
+		t.Errorf("rotated %d, revoked %d; want 1 and %d", rotated.Load(), revoked.Load(), replays-1)
+	}
+}
+
+func TestMemoryDenylist_DropsExpired(t *testing.T) {
+	ctx := context.Background()
+	d := NewMemoryDenylist()
+
+	if _, err := d.Revoke(ctx, "old", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, err := d.Revoke(ctx, "new", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if again, _ := d.Revoke(ctx, "new", time.Now().Add(time.Hour)); !again {
+		t.Error("second Revoke(new) alreadyRevoked = false, want true")
+	}
+
+	if revoked, _ := d.IsRevoked(ctx, "old"); revoked {
+		t.Error("expired entry was not dropped")
+	}
+	if revoked, _ := d.IsRevoked(ctx, "new"); !revoked {
+		t.Error("IsRevoked(new) = false, want true")
+	}
+}
@@ -0,0 +1,316 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	nanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// Default session lifetimes.
+const (
+	DefaultSessionIdleTimeout = 30 * time.Minute
+	DefaultSessionMaxLifetime = 24 * time.Hour
+)
+
+// Session is a server-side login. The client holds a JWT naming the
+// session; the session itself can be revoked or expire for inactivity
+// before that token does.
+type Session struct {
+	ID        string
+	Subject   string
+	CreatedAt time.Time
+	LastSeen  time.Time
+	ExpiresAt time.Time // Absolute expiry, however active the session is
+	Metadata  map[string]string
+}
+
+// expired reports whether the session has hit its absolute expiry or been
+// idle longer than idle.
+func (s *Session) expired(now time.Time, idle time.Duration) bool {
+	return !now.Before(s.ExpiresAt) || now.Sub(s.LastSeen) >= idle
+}
+
+// SessionStore persists sessions. Implementations must be safe for
+// concurrent use.
+type SessionStore interface {
+	// Save creates or replaces a session.
+	Save(ctx context.Context, s *Session) error
+
+	// Get returns the session with id, or ErrSessionNotFound.
+	Get(ctx context.Context, id string) (*Session, error)
+
+	// Touch sets the LastSeen of an existing session. It returns
+	// ErrSessionNotFound if the session is gone and must never create one,
+	// so a Touch racing a Delete cannot bring a revoked session back.
+	Touch(ctx context.Context, id string, lastSeen time.Time) error
+
+	// Delete removes a session. Deleting a missing session is not an error.
+	Delete(ctx context.Context, id string) error
+
+	// List returns the sessions of subject, or all sessions if subject is "".
+	List(ctx context.Context, subject string) ([]*Session, error)
+}
+
+// SessionClaims are the claims of a session token.
+type SessionClaims struct {
+	BaseClaims
+	SessionID string `json:"sid"`
+}
+
+// SessionConfig configures a SessionManager.
+type SessionConfig struct {
+	// JWT signs and validates session tokens.
+	JWT JWTConfig
+
+	// Store holds sessions. Defaults to a new MemorySessionStore.
+	Store SessionStore
+
+	// IdleTimeout ends sessions not touched for this long.
+	// Defaults to DefaultSessionIdleTimeout (30 minutes) if zero.
+	IdleTimeout time.Duration
+
+	// MaxLifetime bounds a session regardless of activity, and is the
+	// lifetime of its token. Defaults to DefaultSessionMaxLifetime (24 hours) if zero.
+	MaxLifetime time.Duration
+}
+
+func (c SessionConfig) idleTimeout() time.Duration {
+	if c.IdleTimeout == 0 {
+		return DefaultSessionIdleTimeout
+	}
+	return c.IdleTimeout
+}
+
+func (c SessionConfig) maxLifetime() time.Duration {
+	if c.MaxLifetime == 0 {
+		return DefaultSessionMaxLifetime
+	}
+	return c.MaxLifetime
+}
+
+// SessionManager creates and validates sessions with sliding expiration:
+// each Touch extends the session by IdleTimeout, up to MaxLifetime.
+type SessionManager struct {
+	cfg   SessionConfig
+	store SessionStore
+	now   func() time.Time
+}
+
+// NewSessionManager creates a session manager.
+func NewSessionManager(cfg SessionConfig) *SessionManager {
+	store := cfg.Store
+	if store == nil {
+		store = NewMemorySessionStore()
+	}
+	return &SessionManager{cfg: cfg, store: store, now: time.Now}
+}
+
+// Create starts a session for subject and returns it with its token.
+func (m *SessionManager) Create(ctx context.Context, subject string, metadata map[string]string) (*Session, string, error) {
+	id, err := nanoid.New()
+	if err != nil {
+		return nil, "", fmt.Errorf("generate session ID: %w", err)
+	}
+
+	now := m.now()
+	s := &Session{
+		ID:        id,
+		Subject:   subject,
+		CreatedAt: now,
+		LastSeen:  now,
+		ExpiresAt: now.Add(m.cfg.maxLifetime()),
+		Metadata:  maps.Clone(metadata),
+	}
+
+	token, err := GenerateAccessTokenWithClaims(m.cfg.JWT, func(base BaseClaims) SessionClaims {
+		base.Subject = subject
+		base.ExpiresAt = jwt.NewNumericDate(s.ExpiresAt)
+		return SessionClaims{BaseClaims: base, SessionID: id}
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := m.store.Save(ctx, s); err != nil {
+		return nil, "", fmt.Errorf("save session: %w", err)
+	}
+	return s, token, nil
+}
+
+// Touch validates a session token and records activity, sliding the idle
+// expiry forward. It returns ErrSessionExpired for idle or expired sessions
+// (deleting them) and ErrSessionNotFound for revoked ones.
+func (m *SessionManager) Touch(ctx context.Context, token string) (*Session, error) {
+	claims := &SessionClaims{}
+	if err := ValidateAccessTokenAs(m.cfg.JWT, token, claims); err != nil {
+		return nil, err
+	}
+	if claims.SessionID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	s, err := m.store.Get(ctx, claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if s.Subject != claims.Subject {
+		return nil, ErrInvalidToken
+	}
+
+	now := m.now()
+	if s.expired(now, m.cfg.idleTimeout()) {
+		if err := m.store.Delete(ctx, s.ID); err != nil {
+			return nil, fmt.Errorf("delete session: %w", err)
+		}
+		return nil, ErrSessionExpired
+	}
+
+	if err := m.store.Touch(ctx, s.ID, now); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("touch session: %w", err)
+	}
+	s.LastSeen = now
+	return s, nil
+}
+
+// Revoke ends a session. Its token stops working immediately.
+func (m *SessionManager) Revoke(ctx context.Context, id string) error {
+	return m.store.Delete(ctx, id)
+}
+
+// RevokeAll ends every session of subject, e.g. after a password change.
+func (m *SessionManager) RevokeAll(ctx context.Context, subject string) error {
+	sessions, err := m.store.List(ctx, subject)
+	if err != nil {
+		return fmt.Errorf("list sessions: %w", err)
+	}
+	for _, s := range sessions {
+		if err := m.store.Delete(ctx, s.ID); err != nil {
+			return fmt.Errorf("delete session %s: %w", s.ID, err)
+		}
+	}
+	return nil
+}
+
+// Active lists the unexpired sessions of subject, or of everyone if
+// subject is "", most recently seen first.
+func (m *SessionManager) Active(ctx context.Context, subject string) ([]*Session, error) {
+	sessions, err := m.store.List(ctx, subject)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	now := m.now()
+	sessions = slices.DeleteFunc(sessions, func(s *Session) bool {
+		return s.expired(now, m.cfg.idleTimeout())
+	})
+	slices.SortFunc(sessions, func(a, b *Session) int {
+		return b.LastSeen.Compare(a.LastSeen)
+	})
+	return sessions, nil
+}
+
+// ExpireIdle deletes idle and expired sessions and returns how many it
+// removed. Run it periodically so stores do not grow without bound.
+func (m *SessionManager) ExpireIdle(ctx context.Context) (int, error) {
+	sessions, err := m.store.List(ctx, "")
+	if err != nil {
+		return 0, fmt.Errorf("list sessions: %w", err)
+	}
+
+	now := m.now()
+	removed := 0
+	for _, s := range sessions {
+		if !s.expired(now, m.cfg.idleTimeout()) {
+			continue
+		}
+		if err := m.store.Delete(ctx, s.ID); err != nil {
+			return removed, fmt.Errorf("delete session %s: %w", s.ID, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// MemorySessionStore is an in-process SessionStore, suitable for
+// single-instance services and tests.
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]Session
+}
+
+// NewMemorySessionStore creates an empty in-memory session store.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{sessions: make(map[string]Session)}
+}
+
+// Save implements SessionStore.
+func (st *MemorySessionStore) Save(_ context.Context, s *Session) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sessions[s.ID] = copySession(s)
+	return nil
+}
+
+// Get implements SessionStore.
+func (st *MemorySessionStore) Get(_ context.Context, id string) (*Session, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	s, ok := st.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	c := copySession(&s)
+	return &c, nil
+}
+
+// Touch implements SessionStore.
+func (st *MemorySessionStore) Touch(_ context.Context, id string, lastSeen time.Time) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	s, ok := st.sessions[id]
+	if !ok {
+		return ErrSessionNotFound
+	}
+	s.LastSeen = lastSeen
+	st.sessions[id] = s
+	return nil
+}
+
+// Delete implements SessionStore.
+func (st *MemorySessionStore) Delete(_ context.Context, id string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delete(st.sessions, id)
+	return nil
+}
+
+// List implements SessionStore.
+func (st *MemorySessionStore) List(_ context.Context, subject string) ([]*Session, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	var out []*Session
+	for _, s := range st.sessions {
+		if subject == "" || s.Subject == subject {
+			c := copySession(&s)
+			out = append(out, &c)
+		}
+	}
+	return out, nil
+}
+
+// copySession keeps callers from mutating stored metadata
+func copySession(s *Session) Session {
+	c := *s
+	c.Metadata = maps.Clone(s.Metadata)
+	return c
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestSessionManager returns a manager whose clock the test controls
+func newTestSessionManager(cfg SessionConfig) (*SessionManager, *time.Time) {
+	cfg.JWT = JWTConfig{Secret: []byte("this-is-a-test-secret-key-32-bytes!")}
+	m := NewSessionManager(cfg)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestSessionManager_SlidingExpiration(t *testing.T) {
+	ctx := context.Background()
+	m, now := newTestSessionManager(SessionConfig{IdleTimeout: 10 * time.Minute, MaxLifetime: time.Hour})
+
+	s, token, err := m.Create(ctx, "user-123", map[string]string{"device": "laptop"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if s.Subject != "user-123" || s.Metadata["device"] != "laptop" {
+		t.Errorf("Create() = %+v", s)
+	}
+
+	// Activity every 8 minutes keeps the session alive past the idle timeout.
+	for range 3 {
+		*now = now.Add(8 * time.Minute)
+		if _, err := m.Touch(ctx, token); err != nil {
+			t.Fatalf("Touch() error = %v", err)
+		}
+	}
+
+	*now = now.Add(11 * time.Minute)
+	if _, err := m.Touch(ctx, token); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("Touch() after idle error = %v, want ErrSessionExpired", err)
+	}
+	if _, err := m.Touch(ctx, token); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Touch() after expiry error = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestSessionManager_MaxLifetime(t *testing.T) {
+	ctx := context.Background()
+	m, now := newTestSessionManager(SessionConfig{IdleTimeout: 10 * time.Minute, MaxLifetime: 20 * time.Minute})
+
+	_, token, err := m.Create(ctx, "user-123", nil)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for range 2 {
+		*now = now.Add(9 * time.Minute)
+		if _, err := m.Touch(ctx, token); err != nil {
+			t.Fatalf("Touch() error = %v", err)
+		}
+	}
+	*now = now.Add(5 * time.Minute)
+	if _, err := m.Touch(ctx, token); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("Touch() past max lifetime error = %v, want ErrSessionExpired", err)
+	}
+}
+
+func TestSessionManager_Revoke(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestSessionManager(SessionConfig{})
+
+	s1, token1, err := m.Create(ctx, "alice", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, token2, err := m.Create(ctx, "alice", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, token3, err := m.Create(ctx, "bob", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Revoke(ctx, s1.ID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, err := m.Touch(ctx, token1); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Touch(revoked) error = %v, want ErrSessionNotFound", err)
+	}
+	if _, err := m.Touch(ctx, token2); err != nil {
+		t.Errorf("Touch(other session) error = %v", err)
+	}
+
+	if err := m.RevokeAll(ctx, "alice"); err != nil {
+		t.Fatalf("RevokeAll() error = %v", err)
+	}
+	if _, err := m.Touch(ctx, token2); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Touch after RevokeAll error = %v, want ErrSessionNotFound", err)
+	}
+	if _, err := m.Touch(ctx, token3); err != nil {
+		t.Errorf("Touch(bob) error = %v", err)
+	}
+}
+
+// revokingStore deletes each session right after reading it, as a Revoke
+// from another request between Touch's Get and its update would
+type revokingStore struct {
+	*MemorySessionStore
+}
+
+func (st revokingStore) Get(ctx context.Context, id string) (*Session, error) {
+	s, err := st.MemorySessionStore.Get(ctx, id)
+	if err == nil {
+		_ = st.Delete(ctx, id)
+	}
+	return s, err
+}
+
+func TestSessionManager_TouchRacingRevoke(t *testing.T) {
+	ctx := context.Background()
+	store := revokingStore{NewMemorySessionStore()}
+	m, _ := newTestSessionManager(SessionConfig{Store: store})
+
+	s, token, err := m.Create(ctx, "user-123", nil)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := m.Touch(ctx, token); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Touch() error = %v, want ErrSessionNotFound", err)
+	}
+	if _, err := store.MemorySessionStore.Get(ctx, s.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Error("Touch() recreated a revoked session")
+	}
+}
+
+func TestSessionManager_ActiveAndExpireIdle(t *testing.T) {
+	ctx := context.Background()
+	m, now := newTestSessionManager(SessionConfig{IdleTimeout: 10 * time.Minute})
+
+	if _, _, err := m.Create(ctx, "alice", nil); err != nil {
+		t.Fatal(err)
+	}
+	*now = now.Add(6 * time.Minute)
+	recent, _, err := m.Create(ctx, "alice", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := m.Create(ctx, "bob", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	active, err := m.Active(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Active() error = %v", err)
+	}
+	if len(active) != 2 || active[0].ID != recent.ID {
+		t.Errorf("Active(alice) = %d sessions, want 2 with most recent first", len(active))
+	}
+
+	*now = now.Add(5 * time.Minute) // First session is now idle
+	active, err = m.Active(ctx, "")
+	if err != nil {
+		t.Fatalf("Active() error = %v", err)
+	}
+	if len(active) != 2 {
+		t.Errorf("Active(all) = %d sessions, want 2", len(active))
+	}
+
+	removed, err := m.ExpireIdle(ctx)
+	if err != nil {
+		t.Fatalf("ExpireIdle() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("ExpireIdle() = %d, want 1", removed)
+	}
+}
+
+func TestSessionManager_RejectsPlainAccessToken(t *testing.T) {
+	m, _ := newTestSessionManager(SessionConfig{})
+	token, err := GenerateAccessToken(m.cfg.JWT, "user-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Touch(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Touch() error = %v, want ErrInvalidToken", err)
+	}
+}
@@ -3,8 +3,6 @@ package ssh
 import (
 	"fmt"
 	"io"
-	"net"
-	"os"
 
 	"golang.org/x/crypto/ssh/agent"
 )
@@ -24,17 +22,14 @@ func (a *AgentConnection) Close() error {
 	return nil
 }
 
-// GetAgent connects to the SSH agent via SSH_AUTH_SOCK.
+// GetAgent connects to the SSH agent. On Unix it uses the socket in
+// SSH_AUTH_SOCK. On Windows it uses SSH_AUTH_SOCK if set (a named pipe or
+// socket), then the Windows OpenSSH agent pipe, then Pageant.
 // The returned AgentConnection should be closed when done to avoid resource leaks.
 func GetAgent() (*AgentConnection, error) {
-	socket := os.Getenv("SSH_AUTH_SOCK")
-	if socket == "" {
-		return nil, ErrNoSSHAgent
-	}
-
-	conn, err := net.Dial("unix", socket)
+	conn, err := dialAgent()
 	if err != nil {
-		return nil, fmt.Errorf("connect to ssh-agent: %w", err)
+		return nil, err
 	}
 
 	return &AgentConnection{
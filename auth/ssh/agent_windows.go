@@ -0,0 +1,138 @@
+//go:build windows
+
+package ssh
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// OpenSSHAgentPipe is the named pipe of the Windows OpenSSH agent service.
+const OpenSSHAgentPipe = `\\.\pipe\openssh-ssh-agent`
+
+// Pageant WM_COPYDATA protocol constants.
+const (
+	pageantCopyDataID = 0x804e50ba
+	wmCopyData        = 0x004A
+)
+
+var (
+	user32            = windows.NewLazySystemDLL("user32.dll")
+	procFindWindowW   = user32.NewProc("FindWindowW")
+	procSendMessageW  = user32.NewProc("SendMessageW")
+	kernel32          = windows.NewLazySystemDLL("kernel32.dll")
+	procRtlMoveMemory = kernel32.NewProc("RtlMoveMemory")
+)
+
+// dialAgent connects to SSH_AUTH_SOCK if set, otherwise to the OpenSSH
+// agent pipe, otherwise to a running Pageant.
+func dialAgent() (io.ReadWriteCloser, error) {
+	if socket := os.Getenv("SSH_AUTH_SOCK"); socket != "" {
+		if strings.HasPrefix(socket, `\\.\pipe\`) {
+			return openPipe(socket)
+		}
+		conn, err := net.Dial("unix", socket)
+		if err != nil {
+			return nil, fmt.Errorf("connect to ssh-agent: %w", err)
+		}
+		return conn, nil
+	}
+
+	pipe, err := openPipe(OpenSSHAgentPipe)
+	if err == nil {
+		return pipe, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	if hwnd := findPageant(); hwnd != 0 {
+		return &pageantConn{roundTrip: func(request []byte) ([]byte, error) {
+			return pageantRoundTrip(hwnd, request)
+		}}, nil
+	}
+	return nil, ErrNoSSHAgent
+}
+
+// openPipe opens an agent named pipe for reading and writing
+func openPipe(name string) (io.ReadWriteCloser, error) {
+	f, err := os.OpenFile(name, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("connect to ssh-agent pipe: %w", err)
+	}
+	return f, nil
+}
+
+// findPageant returns Pageant's window handle, or 0 if it is not running
+func findPageant() uintptr {
+	name, err := windows.UTF16PtrFromString("Pageant")
+	if err != nil {
+		return 0
+	}
+	hwnd, _, _ := procFindWindowW.Call(uintptr(unsafe.Pointer(name)), uintptr(unsafe.Pointer(name)))
+	return hwnd
+}
+
+// copyDataStruct is the Win32 COPYDATASTRUCT
+type copyDataStruct struct {
+	dwData uintptr
+	cbData uint32
+	lpData uintptr
+}
+
+// pageantRoundTrip sends one request through shared memory and returns the
+// reply, following the protocol PuTTY's agent client uses.
+func pageantRoundTrip(hwnd uintptr, request []byte) ([]byte, error) {
+	mapName := fmt.Sprintf("PageantRequest%08x", windows.GetCurrentThreadId())
+	mapNamePtr, err := windows.UTF16PtrFromString(mapName)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := windows.CreateFileMapping(windows.InvalidHandle, nil, windows.PAGE_READWRITE, 0, pageantMaxMessage, mapNamePtr)
+	if err != nil {
+		return nil, fmt.Errorf("pageant: create shared memory: %w", err)
+	}
+	defer func() { _ = windows.CloseHandle(mapping) }()
+
+	addr, err := windows.MapViewOfFile(mapping, windows.FILE_MAP_WRITE, 0, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("pageant: map shared memory: %w", err)
+	}
+	defer func() { _ = windows.UnmapViewOfFile(addr) }()
+
+	// The mapping is not Go memory, so copy through the OS rather than
+	// converting addr to a pointer.
+	_, _, _ = procRtlMoveMemory.Call(addr, uintptr(unsafe.Pointer(&request[0])), uintptr(len(request)))
+
+	// Pageant is told the (ANSI) mapping name and finds the request there.
+	nameBytes := append([]byte(mapName), 0)
+	cds := copyDataStruct{
+		dwData: pageantCopyDataID,
+		cbData: uint32(len(nameBytes)),
+		lpData: uintptr(unsafe.Pointer(&nameBytes[0])),
+	}
+	ret, _, _ := procSendMessageW.Call(hwnd, wmCopyData, 0, uintptr(unsafe.Pointer(&cds)))
+	if ret == 0 {
+		return nil, errors.New("pageant: request refused")
+	}
+
+	var header [4]byte
+	_, _, _ = procRtlMoveMemory.Call(uintptr(unsafe.Pointer(&header[0])), addr, 4)
+	size := 4 + int(binary.BigEndian.Uint32(header[:]))
+	if size > pageantMaxMessage {
+		return nil, fmt.Errorf("pageant: reply of %d bytes exceeds %d", size, pageantMaxMessage)
+	}
+	reply := make([]byte, size)
+	_, _, _ = procRtlMoveMemory.Call(uintptr(unsafe.Pointer(&reply[0])), addr, uintptr(size))
+	return reply, nil
+}
@@ -0,0 +1,177 @@
+package ssh
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// CertInfo holds information about an OpenSSH certificate.
+type CertInfo struct {
+	// Path is the path to the certificate file, if read from one.
+	Path string
+
+	// KeyType is the certificate algorithm (e.g., "ssh-ed25519-cert-v01@openssh.com").
+	KeyType string
+
+	// KeyID is the identity the CA gave the certificate (ssh-keygen -I).
+	KeyID string
+
+	// Serial is the CA-assigned serial number.
+	Serial uint64
+
+	// Type is "user" or "host".
+	Type string
+
+	// Principals are the user or host names the certificate is valid for.
+	// Empty means any principal.
+	Principals []string
+
+	// ValidAfter and ValidBefore bound the validity period. A zero
+	// ValidBefore means the certificate does not expire.
+	ValidAfter  time.Time
+	ValidBefore time.Time
+
+	// Fingerprint is the SHA256 fingerprint of the certified key, which
+	// matches the fingerprint of the plain public key.
+	Fingerprint string
+
+	// CAFingerprint is the SHA256 fingerprint of the signing CA key.
+	CAFingerprint string
+
+	// CriticalOptions and Extensions are the certificate's options, such
+	// as "force-command" or "permit-pty".
+	CriticalOptions map[string]string
+	Extensions      map[string]string
+
+	// Certificate is the parsed certificate, for ValidateCertificate.
+	Certificate *gossh.Certificate
+}
+
+// ValidAt reports whether t falls within the validity period.
+func (c *CertInfo) ValidAt(t time.Time) bool {
+	return !t.Before(c.ValidAfter) && (c.ValidBefore.IsZero() || t.Before(c.ValidBefore))
+}
+
+// ReadCertificate reads and parses an OpenSSH certificate file
+// (e.g., ~/.ssh/id_ed25519-cert.pub).
+func ReadCertificate(path string) (*CertInfo, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // user-provided path expected
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseCertificate(path, string(data))
+}
+
+// ParseCertificate parses a certificate in authorized_keys format.
+func ParseCertificate(path, certData string) (*CertInfo, error) {
+	pub, _, _, _, err := gossh.ParseAuthorizedKey([]byte(certData))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKeyFormat, err)
+	}
+	cert, ok := pub.(*gossh.Certificate)
+	if !ok {
+		return nil, ErrNotCertificate
+	}
+
+	info := NewCertInfo(cert)
+	info.Path = path
+	return info, nil
+}
+
+// NewCertInfo describes a parsed certificate.
+func NewCertInfo(cert *gossh.Certificate) *CertInfo {
+	info := &CertInfo{
+		KeyType:         cert.Type(),
+		KeyID:           cert.KeyId,
+		Serial:          cert.Serial,
+		Type:            "user",
+		Principals:      slices.Clone(cert.ValidPrincipals),
+		ValidAfter:      time.Unix(int64(cert.ValidAfter), 0),
+		Fingerprint:     ComputeFingerprint(cert.Key.Marshal()),
+		CAFingerprint:   ComputeFingerprint(cert.SignatureKey.Marshal()),
+		CriticalOptions: cert.CriticalOptions,
+		Extensions:      cert.Extensions,
+		Certificate:     cert,
+	}
+	if cert.CertType == gossh.HostCert {
+		info.Type = "host"
+	}
+	if cert.ValidBefore != gossh.CertTimeInfinity {
+		info.ValidBefore = time.Unix(int64(cert.ValidBefore), 0)
+	}
+	return info
+}
+
+// CertificateFor reads the certificate stored next to a key, following the
+// OpenSSH naming convention (id_ed25519.pub -> id_ed25519-cert.pub).
+func CertificateFor(key *KeyInfo) (*CertInfo, error) {
+	return ReadCertificate(strings.TrimSuffix(key.Path, ".pub") + "-cert.pub")
+}
+
+// CertCheck describes what ValidateCertificate requires.
+type CertCheck struct {
+	// CAKeys are the trusted certificate authorities. At least one is required.
+	CAKeys []gossh.PublicKey
+
+	// Principal, if set, must be one of the certificate's principals (or
+	// the certificate must allow any principal).
+	Principal string
+
+	// Type, if non-zero, is the required certificate type: gossh.UserCert
+	// or gossh.HostCert.
+	Type uint32
+
+	// Now is the time to check validity at. Defaults to time.Now().
+	Now time.Time
+}
+
+// ValidateCertificate checks that cert was signed by one of the trusted CA
+// keys, is within its validity period, and matches the principal and type.
+func ValidateCertificate(cert *gossh.Certificate, check CertCheck) error {
+	if !slices.ContainsFunc(check.CAKeys, func(ca gossh.PublicKey) bool {
+		return bytes.Equal(ca.Marshal(), cert.SignatureKey.Marshal())
+	}) {
+		return fmt.Errorf("%w: signed by %s", ErrUntrustedCA, ComputeFingerprint(cert.SignatureKey.Marshal()))
+	}
+
+	if err := cert.SignatureKey.Verify(certSignedBytes(cert), cert.Signature); err != nil {
+		return fmt.Errorf("%w: bad signature: %w", ErrInvalidCertificate, err)
+	}
+
+	if check.Type != 0 && cert.CertType != check.Type {
+		return fmt.Errorf("%w: wrong certificate type %d", ErrInvalidCertificate, cert.CertType)
+	}
+
+	now := check.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	info := NewCertInfo(cert)
+	if now.Before(info.ValidAfter) {
+		return fmt.Errorf("%w: valid from %s", ErrCertNotYetValid, info.ValidAfter.Format(time.RFC3339))
+	}
+	if !info.ValidAt(now) {
+		return fmt.Errorf("%w: expired %s", ErrCertExpired, info.ValidBefore.Format(time.RFC3339))
+	}
+
+	if check.Principal != "" && len(cert.ValidPrincipals) > 0 && !slices.Contains(cert.ValidPrincipals, check.Principal) {
+		return fmt.Errorf("%w: %q", ErrPrincipalNotAllowed, check.Principal)
+	}
+	return nil
+}
+
+// certSignedBytes returns the part of the certificate the CA signed: its
+// wire form without the trailing signature field.
+func certSignedBytes(cert *gossh.Certificate) []byte {
+	c := *cert
+	c.Signature = nil
+	out := c.Marshal()
+	return out[:len(out)-4] // Drop the empty signature's length prefix
+}
@@ -0,0 +1,144 @@
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// newTestCert issues a user certificate for key signed by ca
+func newTestCert(t *testing.T, ca ssh.Signer, key ssh.PublicKey, principals []string, after, before time.Time) *ssh.Certificate {
+	t.Helper()
+	cert := &ssh.Certificate{
+		Key:             key,
+		Serial:          42,
+		CertType:        ssh.UserCert,
+		KeyId:           "alice@example.com",
+		ValidPrincipals: principals,
+		ValidAfter:      uint64(after.Unix()),
+		ValidBefore:     uint64(before.Unix()),
+		Permissions: ssh.Permissions{
+			Extensions: map[string]string{"permit-pty": ""},
+		},
+	}
+	if err := cert.SignCert(rand.Reader, ca); err != nil {
+		t.Fatalf("SignCert() error = %v", err)
+	}
+	return cert
+}
+
+func TestParseCertificate(t *testing.T) {
+	ca, user := newTestSigner(t), newTestSigner(t)
+	now := time.Now().Truncate(time.Second)
+	cert := newTestCert(t, ca, user.PublicKey(), []string{"alice", "deploy"}, now.Add(-time.Hour), now.Add(time.Hour))
+
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "id_ed25519.pub")
+	if err := os.WriteFile(keyPath, ssh.MarshalAuthorizedKey(user.PublicKey()), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "id_ed25519-cert.pub"), ssh.MarshalAuthorizedKey(cert), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ReadPublicKey(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := CertificateFor(key)
+	if err != nil {
+		t.Fatalf("CertificateFor() error = %v", err)
+	}
+
+	if info.KeyType != ssh.CertAlgoED25519v01 {
+		t.Errorf("KeyType = %q", info.KeyType)
+	}
+	if info.KeyID != "alice@example.com" || info.Serial != 42 || info.Type != "user" {
+		t.Errorf("KeyID, Serial, Type = %q, %d, %q", info.KeyID, info.Serial, info.Type)
+	}
+	if len(info.Principals) != 2 || info.Principals[1] != "deploy" {
+		t.Errorf("Principals = %v", info.Principals)
+	}
+	if !info.ValidBefore.Equal(now.Add(time.Hour)) {
+		t.Errorf("ValidBefore = %v, want %v", info.ValidBefore, now.Add(time.Hour))
+	}
+	if info.Fingerprint != key.Fingerprint {
+		t.Errorf("Fingerprint = %q, want key fingerprint %q", info.Fingerprint, key.Fingerprint)
+	}
+	if info.CAFingerprint != ComputeFingerprint(ca.PublicKey().Marshal()) {
+		t.Error("CAFingerprint does not match CA key")
+	}
+	if _, ok := info.Extensions["permit-pty"]; !ok {
+		t.Errorf("Extensions = %v, want permit-pty", info.Extensions)
+	}
+	if !info.ValidAt(now) || info.ValidAt(now.Add(2*time.Hour)) {
+		t.Error("ValidAt() does not match the validity period")
+	}
+
+	if _, err := ParseCertificate("", testED25519PublicKey); !errors.Is(err, ErrNotCertificate) {
+		t.Errorf("ParseCertificate(plain key) error = %v, want ErrNotCertificate", err)
+	}
+}
+
+func TestValidateCertificate(t *testing.T) {
+	ca, otherCA, user := newTestSigner(t), newTestSigner(t), newTestSigner(t)
+	now := time.Now()
+	cert := newTestCert(t, ca, user.PublicKey(), []string{"alice"}, now.Add(-time.Hour), now.Add(time.Hour))
+	trusted := []ssh.PublicKey{otherCA.PublicKey(), ca.PublicKey()}
+
+	tests := []struct {
+		name  string
+		cert  *ssh.Certificate
+		check CertCheck
+		want  error
+	}{
+		{"valid", cert, CertCheck{CAKeys: trusted, Principal: "alice", Type: ssh.UserCert}, nil},
+		{"untrusted CA", cert, CertCheck{CAKeys: []ssh.PublicKey{otherCA.PublicKey()}}, ErrUntrustedCA},
+		{"wrong principal", cert, CertCheck{CAKeys: trusted, Principal: "root"}, ErrPrincipalNotAllowed},
+		{"wrong type", cert, CertCheck{CAKeys: trusted, Type: ssh.HostCert}, ErrInvalidCertificate},
+		{"expired", cert, CertCheck{CAKeys: trusted, Now: now.Add(2 * time.Hour)}, ErrCertExpired},
+		{"not yet valid", cert, CertCheck{CAKeys: trusted, Now: now.Add(-2 * time.Hour)}, ErrCertNotYetValid},
+		{
+			"any principal",
+			newTestCert(t, ca, user.PublicKey(), nil, now.Add(-time.Hour), now.Add(time.Hour)),
+			CertCheck{CAKeys: trusted, Principal: "anyone"},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCertificate(tt.cert, tt.check)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("ValidateCertificate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := *cert
+		tampered.ValidPrincipals = []string{"root"}
+		err := ValidateCertificate(&tampered, CertCheck{CAKeys: trusted})
+		if !errors.Is(err, ErrInvalidCertificate) {
+			t.Errorf("error = %v, want ErrInvalidCertificate", err)
+		}
+	})
+}
@@ -4,7 +4,9 @@
 //   - SSH key discovery (find default key, list all keys)
 //   - Public key parsing and fingerprint computation
 //   - SSH agent connection and signing
-//   - Direct key file signing
+//   - known_hosts parsing, host key verification, and updates
+//   - OpenSSH certificate parsing and validation against CA keys
+//   - Direct key file signing, including passphrase-protected keys
 //
 // # Finding SSH Keys
 //
@@ -23,8 +25,18 @@
 //	    fmt.Printf("%s: %s\n", key.KeyType, key.Fingerprint)
 //	}
 //
+// Pick the key ~/.ssh/config assigns to a git remote's host:
+//
+//	info, err := ssh.FindKeyForRemote("git@github-work:org/repo.git")
+//
+// ParseConfig and ConfigFile.Resolve expose the HostName, User, Port, and
+// IdentityFile settings directly.
+//
 // # SSH Agent Signing
 //
+// GetAgent connects to SSH_AUTH_SOCK. On Windows it also finds the OpenSSH
+// agent service's named pipe and Pageant.
+//
 // Sign a challenge using the SSH agent:
 //
 //	agent, err := ssh.GetAgent()
@@ -34,12 +46,44 @@
 //
 //	sig, err := ssh.SignWithAgent(agent, fingerprint, challengeBytes)
 //
+// FIDO2 security keys (KeyInfo.SecurityKey) sign through the agent too;
+// failures wrap ErrSecurityKeyFailed, which tells the user to connect and
+// touch the key.
+//
 // # Direct Key Signing
 //
-// Sign using a private key file (unencrypted keys only):
+// Sign using a private key file:
 //
 //	sig, err := ssh.SignWithKeyFile(keyPath, challengeBytes)
 //
+// For encrypted keys, SignWithKeyFileOptions signs with the agent's copy of
+// the key if it has one, and otherwise asks a PassphraseFunc:
+//
+//	sig, err := ssh.SignWithKeyFileOptions(keyPath, challengeBytes, ssh.KeyFileOptions{
+//	    Agent:      agent,
+//	    Passphrase: promptPassphrase,
+//	})
+//
+// # Known Hosts
+//
+// Verify server host keys without shelling out to ssh:
+//
+//	err := ssh.VerifyHostKey(knownHostsPath, "github.com:22", hostKey)
+//	if errors.Is(err, ssh.ErrUnknownHost) {
+//	    err = ssh.AddHostKey(knownHostsPath, "github.com", hostKey, true) // hashed
+//	}
+//
+// HostKeyCallback plugs the same checks into a gossh.ClientConfig.
+//
+// # Certificates
+//
+// Organizations using an SSH CA issue certificates alongside keys. Read one
+// and check it against the CA:
+//
+//	info, err := ssh.ReadCertificate(home + "/.ssh/id_ed25519-cert.pub")
+//	fmt.Println(info.Principals, info.ValidBefore)
+//	err = ssh.ValidateCertificate(info.Certificate, ssh.CertCheck{CAKeys: cas, Principal: "alice"})
+//
 // # Custom Configuration
 //
 // Use Config for custom SSH directory or key preferences:
@@ -13,6 +13,53 @@ var (
 	// ErrKeyNotFound is returned when a specific key is not found in the agent.
 	ErrKeyNotFound = errors.New("SSH key not found in agent")
 
+	// ErrEncryptedKey is returned when a private key is passphrase protected
+	// and neither an agent copy nor a passphrase is available.
+	ErrEncryptedKey = errors.New("SSH private key is passphrase protected")
+
+	// ErrIncorrectPassphrase is returned when a passphrase does not decrypt a key.
+	ErrIncorrectPassphrase = errors.New("incorrect passphrase for SSH private key")
+
 	// ErrInvalidKeyFormat is returned when a public key file has invalid format.
 	ErrInvalidKeyFormat = errors.New("invalid SSH public key format")
+
+	// ErrNotCertificate is returned when a key is not an OpenSSH certificate.
+	ErrNotCertificate = errors.New("not an SSH certificate")
+
+	// ErrInvalidCertificate is returned when a certificate's signature or type is wrong.
+	ErrInvalidCertificate = errors.New("invalid SSH certificate")
+
+	// ErrUntrustedCA is returned when a certificate is not signed by a trusted CA key.
+	ErrUntrustedCA = errors.New("SSH certificate signed by untrusted CA")
+
+	// ErrCertExpired is returned when a certificate's validity period has ended.
+	ErrCertExpired = errors.New("SSH certificate expired")
+
+	// ErrCertNotYetValid is returned when a certificate's validity period has not begun.
+	ErrCertNotYetValid = errors.New("SSH certificate not yet valid")
+
+	// ErrPrincipalNotAllowed is returned when a certificate does not list the principal.
+	ErrPrincipalNotAllowed = errors.New("principal not allowed by SSH certificate")
+
+	// ErrSecurityKeyFailed is returned when the agent cannot sign with a
+	// FIDO2 security key, usually because the token is unplugged or was
+	// not touched in time.
+	ErrSecurityKeyFailed = errors.New("security key signing failed: check the key is connected and touch it when it blinks")
+
+	// ErrSecurityKeyNeedsAgent is returned when a security key file is used
+	// without an agent; its private key never leaves the hardware token.
+	ErrSecurityKeyNeedsAgent = errors.New("security keys can only sign through ssh-agent")
+
+	// ErrInvalidConfig is returned when an SSH config file cannot be parsed.
+	ErrInvalidConfig = errors.New("invalid SSH config")
+
+	// ErrUnknownHost is returned when known_hosts has no key for a host.
+	ErrUnknownHost = errors.New("host not in known_hosts")
+
+	// ErrHostKeyMismatch is returned when a host presents a key other than
+	// the one in known_hosts, which may indicate a man-in-the-middle attack.
+	ErrHostKeyMismatch = errors.New("host key does not match known_hosts")
+
+	// ErrHostKeyRevoked is returned when a host key is marked @revoked.
+	ErrHostKeyRevoked = errors.New("host key revoked")
 )
@@ -60,6 +60,10 @@ type KeyInfo struct {
 
 	// Comment is the optional key comment.
 	Comment string
+
+	// SecurityKey is true for FIDO2 keys (sk-ssh-ed25519, sk-ecdsa), which
+	// can only sign through ssh-agent with the token present.
+	SecurityKey bool
 }
 
 // FindDefaultKey finds the default SSH key using default configuration.
@@ -121,6 +125,7 @@ func ParsePublicKey(path, keyData string) (*KeyInfo, error) {
 		KeyType:     keyType,
 		Fingerprint: fingerprint,
 		Comment:     comment,
+		SecurityKey: IsSecurityKey(keyType),
 	}, nil
 }
 
@@ -0,0 +1,267 @@
+package ssh
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec // known_hosts hashing is defined as HMAC-SHA1
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// KnownHost is one entry of a known_hosts file.
+type KnownHost struct {
+	// Marker is "@cert-authority", "@revoked", or "".
+	Marker string
+
+	// Hosts are the entry's host patterns, possibly hashed ("|1|...").
+	Hosts []string
+
+	// Key is the host key.
+	Key gossh.PublicKey
+
+	// KeyType is the key algorithm (e.g., "ssh-ed25519").
+	KeyType string
+
+	// Fingerprint is the SHA256 fingerprint of the key.
+	Fingerprint string
+
+	// Comment is the optional trailing comment.
+	Comment string
+
+	// Line is the 1-based line number in the file.
+	Line int
+}
+
+// Hashed reports whether the entry's hosts are hashed (HashKnownHosts yes).
+func (k *KnownHost) Hashed() bool {
+	return len(k.Hosts) > 0 && strings.HasPrefix(k.Hosts[0], "|1|")
+}
+
+// Matches reports whether the entry applies to host ("example.com" or
+// "example.com:2222"), checking hashed hosts and wildcard patterns.
+func (k *KnownHost) Matches(host string) bool {
+	entry := knownhosts.Normalize(host)
+	matched := false
+	for _, pattern := range k.Hosts {
+		negate := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+
+		var ok bool
+		if strings.HasPrefix(pattern, "|1|") {
+			ok = hashedHostMatches(pattern, entry)
+		} else {
+			ok = wildcardMatch(pattern, entry)
+		}
+		if ok && negate {
+			return false
+		}
+		matched = matched || ok
+	}
+	return matched
+}
+
+// hashedHostMatches checks entry against a "|1|salt|hash" pattern
+func hashedHostMatches(pattern, entry string) bool {
+	parts := strings.Split(pattern, "|")
+	if len(parts) != 4 {
+		return false
+	}
+	salt, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte(entry))
+	return hmac.Equal(mac.Sum(nil), want)
+}
+
+// KnownHostsPath returns the user's known_hosts file (~/.ssh/known_hosts).
+func KnownHostsPath() (string, error) {
+	return KnownHostsPathWithConfig(Config{})
+}
+
+// KnownHostsPathWithConfig returns SSHDir/known_hosts.
+func KnownHostsPathWithConfig(cfg Config) (string, error) {
+	sshDir, err := cfg.sshDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(sshDir, "known_hosts"), nil
+}
+
+// ReadKnownHosts reads and parses a known_hosts file.
+func ReadKnownHosts(path string) ([]*KnownHost, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // user-provided path expected
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseKnownHosts(data)
+}
+
+// ParseKnownHosts parses known_hosts data. Comments and blank lines are
+// skipped.
+func ParseKnownHosts(data []byte) ([]*KnownHost, error) {
+	var hosts []*KnownHost
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		marker, patterns, key, comment, _, err := gossh.ParseKnownHosts(scanner.Bytes())
+		if errors.Is(err, io.EOF) {
+			continue // Blank or comment
+		}
+		if err != nil {
+			return nil, fmt.Errorf("known_hosts line %d: %w: %w", lineNo, ErrInvalidKeyFormat, err)
+		}
+		if marker != "" {
+			marker = "@" + marker
+		}
+		hosts = append(hosts, &KnownHost{
+			Marker:      marker,
+			Hosts:       patterns,
+			Key:         key,
+			KeyType:     key.Type(),
+			Fingerprint: ComputeFingerprint(key.Marshal()),
+			Comment:     comment,
+			Line:        lineNo,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read known_hosts: %w", err)
+	}
+	return hosts, nil
+}
+
+// HostKeyCallback returns a callback that verifies server host keys against
+// the given known_hosts files (default: ~/.ssh/known_hosts), for
+// gossh.ClientConfig.HostKeyCallback. Missing files count as empty.
+// Failures wrap ErrUnknownHost, ErrHostKeyMismatch, or ErrHostKeyRevoked.
+func HostKeyCallback(paths ...string) (gossh.HostKeyCallback, error) {
+	if len(paths) == 0 {
+		p, err := KnownHostsPath()
+		if err != nil {
+			return nil, err
+		}
+		paths = []string{p}
+	}
+
+	var existing []string
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			existing = append(existing, p)
+		}
+	}
+	if len(existing) == 0 {
+		return func(hostname string, _ net.Addr, key gossh.PublicKey) error {
+			return hostKeyError(hostname, key, &knownhosts.KeyError{})
+		}, nil
+	}
+
+	check, err := knownhosts.New(existing...)
+	if err != nil {
+		return nil, fmt.Errorf("load known_hosts: %w", err)
+	}
+	return func(hostname string, remote net.Addr, key gossh.PublicKey) error {
+		return hostKeyError(hostname, key, check(hostname, remote, key))
+	}, nil
+}
+
+// VerifyHostKey checks key against the known_hosts file at path for host
+// ("example.com" or "example.com:2222"). It returns nil if the key is known
+// for the host.
+func VerifyHostKey(path, host string, key gossh.PublicKey) error {
+	callback, err := HostKeyCallback(path)
+	if err != nil {
+		return err
+	}
+
+	address := withPort(host)
+	_, port, _ := net.SplitHostPort(address)
+	// The callback prefers the hostname; the remote address only supplies a
+	// parseable fallback.
+	remote := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}
+	remote.Port, _ = net.LookupPort("tcp", port)
+	return callback(address, remote, key)
+}
+
+// hostKeyError maps knownhosts errors to this package's sentinels
+func hostKeyError(host string, key gossh.PublicKey, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var revoked *knownhosts.RevokedError
+	if errors.As(err, &revoked) {
+		return fmt.Errorf("%w: %s key %s (%s:%d)", ErrHostKeyRevoked, host,
+			ComputeFingerprint(key.Marshal()), revoked.Revoked.Filename, revoked.Revoked.Line)
+	}
+
+	var keyErr *knownhosts.KeyError
+	if !errors.As(err, &keyErr) {
+		return err
+	}
+	if len(keyErr.Want) == 0 {
+		return fmt.Errorf("%w: %s (%s key %s)", ErrUnknownHost, host, key.Type(), ComputeFingerprint(key.Marshal()))
+	}
+	known := make([]string, len(keyErr.Want))
+	for i, w := range keyErr.Want {
+		known[i] = fmt.Sprintf("%s %s (%s:%d)", w.Key.Type(), ComputeFingerprint(w.Key.Marshal()), w.Filename, w.Line)
+	}
+	return fmt.Errorf("%w: %s presented %s %s, known_hosts has %s", ErrHostKeyMismatch, host,
+		key.Type(), ComputeFingerprint(key.Marshal()), strings.Join(known, ", "))
+}
+
+// AddHostKey appends host's key to the known_hosts file at path, creating
+// the file (and its directory) if needed. With hashed set, the host name is
+// stored hashed, like HashKnownHosts yes, so the file does not reveal which
+// hosts were visited.
+func AddHostKey(path, host string, key gossh.PublicKey, hashed bool) error {
+	entry := knownhosts.Normalize(host)
+	if hashed {
+		entry = knownhosts.HashHostname(entry)
+	}
+	line := entry + " " + strings.TrimSpace(string(gossh.MarshalAuthorizedKey(key))) + "\n"
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create ssh directory: %w", err)
+	}
+
+	// Start on a new line if the file does not end with one
+	if existing, err := os.ReadFile(path); err == nil && len(existing) > 0 && existing[len(existing)-1] != '\n' { //nolint:gosec // user-provided path expected
+		line = "\n" + line
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // user-provided path expected
+	if err != nil {
+		return fmt.Errorf("open known_hosts: %w", err)
+	}
+	if _, err := f.WriteString(line); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write known_hosts: %w", err)
+	}
+	return f.Close()
+}
+
+// withPort adds the default SSH port to a host without one
+func withPort(host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), "22")
+}
@@ -0,0 +1,154 @@
+package ssh
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestAddHostKey_VerifyHostKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ssh", "known_hosts")
+	hostKey := newTestSigner(t).PublicKey()
+	otherKey := newTestSigner(t).PublicKey()
+
+	if err := VerifyHostKey(path, "github.com", hostKey); !errors.Is(err, ErrUnknownHost) {
+		t.Errorf("missing file: error = %v, want ErrUnknownHost", err)
+	}
+
+	if err := AddHostKey(path, "github.com", hostKey, false); err != nil {
+		t.Fatalf("AddHostKey() error = %v", err)
+	}
+	if err := AddHostKey(path, "gitlab.corp:2222", otherKey, true); err != nil {
+		t.Fatalf("AddHostKey(hashed) error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "gitlab.corp") {
+		t.Error("hashed entry stored the host name in clear text")
+	}
+
+	tests := []struct {
+		host string
+		key  ssh.PublicKey
+		want error
+	}{
+		{"github.com", hostKey, nil},
+		{"github.com:22", hostKey, nil},
+		{"github.com", otherKey, ErrHostKeyMismatch},
+		{"gitlab.corp:2222", otherKey, nil},
+		{"gitlab.corp", otherKey, ErrUnknownHost}, // Port 22 is a different entry
+		{"example.com", hostKey, ErrUnknownHost},
+	}
+	for _, tt := range tests {
+		err := VerifyHostKey(path, tt.host, tt.key)
+		if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("VerifyHostKey(%s) error = %v, want %v", tt.host, err, tt.want)
+		}
+	}
+}
+
+func TestParseKnownHosts(t *testing.T) {
+	key := newTestSigner(t).PublicKey()
+	revokedKey := newTestSigner(t).PublicKey()
+	authorized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+	data := "# comment\n\n" +
+		"github.com,140.82.112.3 " + authorized + " gh\n" +
+		"*.corp,!secret.corp " + authorized + "\n" +
+		"@revoked * " + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(revokedKey))) + "\n"
+
+	hosts, err := ParseKnownHosts([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseKnownHosts() error = %v", err)
+	}
+	if len(hosts) != 3 {
+		t.Fatalf("got %d entries, want 3", len(hosts))
+	}
+
+	gh := hosts[0]
+	if gh.Line != 3 || gh.Comment != "gh" || len(gh.Hosts) != 2 || gh.Fingerprint != ComputeFingerprint(key.Marshal()) {
+		t.Errorf("entry = %+v", gh)
+	}
+	if !gh.Matches("140.82.112.3") || gh.Matches("gitlab.com") {
+		t.Error("Matches() wrong for plain hosts")
+	}
+
+	corp := hosts[1]
+	if !corp.Matches("git.corp") || corp.Matches("secret.corp") {
+		t.Error("Matches() wrong for wildcard and negated patterns")
+	}
+	if hosts[2].Marker != "@revoked" {
+		t.Errorf("Marker = %q, want @revoked", hosts[2].Marker)
+	}
+
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyHostKey(path, "github.com", revokedKey); !errors.Is(err, ErrHostKeyRevoked) {
+		t.Errorf("revoked key: error = %v, want ErrHostKeyRevoked", err)
+	}
+
+	if _, err := ParseKnownHosts([]byte("github.com ssh-ed25519 !!!\n")); err == nil {
+		t.Error("ParseKnownHosts(bad key) error = nil")
+	}
+}
+
+func TestKnownHost_MatchesHashed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	key := newTestSigner(t).PublicKey()
+	// The existing last line has no newline; the new entry must not join it.
+	existing := "github.com " + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+	if err := os.WriteFile(path, []byte(existing), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddHostKey(path, "[build.corp]:2200", key, true); err != nil {
+		t.Fatal(err)
+	}
+
+	hosts, err := ReadKnownHosts(path)
+	if err != nil {
+		t.Fatalf("ReadKnownHosts() error = %v", err)
+	}
+	if len(hosts) != 2 || hosts[0].Hashed() || !hosts[1].Hashed() {
+		t.Fatalf("entries = %+v, want plain then hashed", hosts)
+	}
+	if !hosts[1].Matches("build.corp:2200") || hosts[1].Matches("build.corp") {
+		t.Error("Matches() wrong for hashed host")
+	}
+}
+
+func TestKnownHost_MatchesPort(t *testing.T) {
+	key := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(newTestSigner(t).PublicKey())))
+	hosts, err := ParseKnownHosts([]byte("[git.corp]:2222,[*.build.corp]:22?? " + key + "\n"))
+	if err != nil {
+		t.Fatalf("ParseKnownHosts() error = %v", err)
+	}
+
+	entry := hosts[0]
+	for host, want := range map[string]bool{
+		"git.corp:2222":      true,
+		"GIT.corp:2222":      true,
+		"git.corp":           false,
+		"git.corp:2200":      false,
+		"ci.build.corp:2201": true,
+		"ci.build.corp":      false,
+	} {
+		if got := entry.Matches(host); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
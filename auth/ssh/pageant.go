@@ -0,0 +1,53 @@
+package ssh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// pageantMaxMessage is the size of the shared memory Pageant reads
+// requests from and writes replies to.
+const pageantMaxMessage = 8192
+
+// pageantConn adapts Pageant's one-shot request/reply API to the stream the
+// agent client expects: writes are buffered until a whole length-prefixed
+// request has arrived, which is then sent with roundTrip, and the reply is
+// returned by subsequent reads.
+type pageantConn struct {
+	roundTrip func(request []byte) ([]byte, error)
+	request   bytes.Buffer
+	reply     bytes.Buffer
+}
+
+// Write implements io.Writer.
+func (c *pageantConn) Write(p []byte) (int, error) {
+	c.request.Write(p)
+	for c.request.Len() >= 4 {
+		size := 4 + int(binary.BigEndian.Uint32(c.request.Bytes()))
+		if size > pageantMaxMessage {
+			c.request.Reset()
+			return 0, fmt.Errorf("pageant: request of %d bytes exceeds %d", size, pageantMaxMessage)
+		}
+		if c.request.Len() < size {
+			break
+		}
+
+		reply, err := c.roundTrip(c.request.Next(size))
+		if err != nil {
+			return 0, err
+		}
+		c.reply.Write(reply)
+	}
+	return len(p), nil
+}
+
+// Read implements io.Reader.
+func (c *pageantConn) Read(p []byte) (int, error) {
+	return c.reply.Read(p)
+}
+
+// Close implements io.Closer. Pageant holds no per-connection state.
+func (c *pageantConn) Close() error {
+	return nil
+}
@@ -0,0 +1,45 @@
+package ssh
+
+import (
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+func frame(body string) []byte {
+	msg := make([]byte, 4+len(body))
+	binary.BigEndian.PutUint32(msg, uint32(len(body)))
+	copy(msg[4:], body)
+	return msg
+}
+
+func TestPageantConn(t *testing.T) {
+	var requests []string
+	conn := &pageantConn{roundTrip: func(request []byte) ([]byte, error) {
+		requests = append(requests, string(request[4:]))
+		return frame("reply:" + string(request[4:])), nil
+	}}
+
+	// The agent client may write a request in pieces.
+	msg := frame("hello")
+	for _, part := range [][]byte{msg[:2], msg[2:6], msg[6:]} {
+		if _, err := conn.Write(part); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if len(requests) != 1 || requests[0] != "hello" {
+		t.Fatalf("requests = %q, want one hello", requests)
+	}
+
+	reply, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(reply) != string(frame("reply:hello")) {
+		t.Errorf("reply = %q", reply)
+	}
+
+	if _, err := conn.Write(frame(string(make([]byte, pageantMaxMessage)))); err == nil {
+		t.Error("Write(oversized) error = nil")
+	}
+}
@@ -1,9 +1,12 @@
 package ssh
 
 import (
+	"crypto/x509"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	gossh "golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/agent"
@@ -22,6 +25,10 @@ func SignWithAgent(ag agent.ExtendedAgent, fingerprint string, data []byte) (str
 	// Sign
 	sig, err := ag.Sign(key, data)
 	if err != nil {
+		if IsSecurityKey(key.Format) {
+			// Agents report a missing token or no touch as a bare failure
+			return "", fmt.Errorf("sign data: %w: %w", ErrSecurityKeyFailed, err)
+		}
 		return "", fmt.Errorf("sign data: %w", err)
 	}
 
@@ -40,9 +47,32 @@ func SignChallengeWithAgent(ag agent.ExtendedAgent, fingerprint, challenge strin
 	return SignWithAgent(ag, fingerprint, challengeBytes)
 }
 
+// PassphraseFunc supplies the passphrase for an encrypted private key,
+// typically by prompting the user. keyPath names the key being unlocked.
+type PassphraseFunc func(keyPath string) ([]byte, error)
+
+// KeyFileOptions controls how encrypted private keys are handled.
+type KeyFileOptions struct {
+	// Agent, if set, signs with the agent's copy of an encrypted key
+	// (including one in a forwarded agent) before asking for a passphrase.
+	Agent agent.ExtendedAgent
+
+	// Passphrase is called for encrypted keys the agent does not hold.
+	Passphrase PassphraseFunc
+}
+
 // SignWithKeyFile signs data using a private key file.
-// Note: Only unencrypted keys are supported. For encrypted keys, use ssh-agent.
+// Encrypted keys return ErrEncryptedKey; use SignWithKeyFileOptions to
+// supply a passphrase or an agent.
 func SignWithKeyFile(keyPath string, data []byte) (string, error) {
+	return SignWithKeyFileOptions(keyPath, data, KeyFileOptions{})
+}
+
+// SignWithKeyFileOptions signs data using a private key file, which may be
+// encrypted. For an encrypted key it tries opts.Agent first, so users with
+// the key loaded are not prompted, then opts.Passphrase. Security key files
+// (sk-ssh-ed25519, sk-ecdsa) always sign through opts.Agent.
+func SignWithKeyFileOptions(keyPath string, data []byte, opts KeyFileOptions) (string, error) {
 	keyData, err := os.ReadFile(keyPath) //nolint:gosec // user-provided path expected
 	if err != nil {
 		return "", fmt.Errorf("read private key: %w", err)
@@ -50,7 +80,22 @@ func SignWithKeyFile(keyPath string, data []byte) (string, error) {
 
 	signer, err := gossh.ParsePrivateKey(keyData)
 	if err != nil {
-		return "", fmt.Errorf("parse private key: %w (encrypted keys require ssh-agent)", err)
+		var missing *gossh.PassphraseMissingError
+		if !errors.As(err, &missing) {
+			if pub, pubErr := ReadPublicKey(keyPath + ".pub"); pubErr == nil && pub.SecurityKey {
+				return signSecurityKeyFile(opts.Agent, pub, data)
+			}
+			return "", fmt.Errorf("parse private key: %w", err)
+		}
+		if opts.Agent != nil {
+			sig, err := signWithAgentCopy(opts.Agent, keyPath, missing.PublicKey, data)
+			if !errors.Is(err, ErrKeyNotFound) {
+				return sig, err
+			}
+		}
+		if signer, err = decryptKey(keyPath, keyData, opts.Passphrase); err != nil {
+			return "", err
+		}
 	}
 
 	sig, err := signer.Sign(nil, data)
@@ -61,6 +106,76 @@ func SignWithKeyFile(keyPath string, data []byte) (string, error) {
 	return base64.StdEncoding.EncodeToString(gossh.Marshal(sig)), nil
 }
 
+// LoadSigner reads a private key file, decrypting it with passphrase if it
+// is encrypted. passphrase may be nil for unencrypted keys.
+func LoadSigner(keyPath string, passphrase PassphraseFunc) (gossh.Signer, error) {
+	keyData, err := os.ReadFile(keyPath) //nolint:gosec // user-provided path expected
+	if err != nil {
+		return nil, fmt.Errorf("read private key: %w", err)
+	}
+
+	signer, err := gossh.ParsePrivateKey(keyData)
+	if err == nil {
+		return signer, nil
+	}
+	var missing *gossh.PassphraseMissingError
+	if !errors.As(err, &missing) {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	return decryptKey(keyPath, keyData, passphrase)
+}
+
+// decryptKey parses an encrypted key with the passphrase from passphrase
+func decryptKey(keyPath string, keyData []byte, passphrase PassphraseFunc) (gossh.Signer, error) {
+	if passphrase == nil {
+		return nil, fmt.Errorf("%w: %s", ErrEncryptedKey, keyPath)
+	}
+
+	pass, err := passphrase(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("get passphrase: %w", err)
+	}
+
+	signer, err := gossh.ParsePrivateKeyWithPassphrase(keyData, pass)
+	if err != nil {
+		if errors.Is(err, x509.IncorrectPasswordError) {
+			return nil, fmt.Errorf("%w: %s", ErrIncorrectPassphrase, keyPath)
+		}
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	return signer, nil
+}
+
+// signSecurityKeyFile signs with a security key through the agent, which
+// talks to the token
+func signSecurityKeyFile(ag agent.ExtendedAgent, pub *KeyInfo, data []byte) (string, error) {
+	if ag == nil {
+		return "", fmt.Errorf("%w: %s", ErrSecurityKeyNeedsAgent, pub.Path)
+	}
+	sig, err := SignWithAgent(ag, pub.Fingerprint, data)
+	if errors.Is(err, ErrKeyNotFound) {
+		return "", fmt.Errorf("%w: add it with ssh-add %s", ErrKeyNotFound, strings.TrimSuffix(pub.Path, ".pub"))
+	}
+	return sig, err
+}
+
+// signWithAgentCopy signs with the agent key matching an encrypted key
+// file. The public key comes from the file itself when the format includes
+// it (OpenSSH), or from the .pub file next to it.
+func signWithAgentCopy(ag agent.ExtendedAgent, keyPath string, pub gossh.PublicKey, data []byte) (string, error) {
+	var fingerprint string
+	if pub != nil {
+		fingerprint = ComputeFingerprint(pub.Marshal())
+	} else {
+		info, err := ReadPublicKey(keyPath + ".pub")
+		if err != nil {
+			return "", ErrKeyNotFound
+		}
+		fingerprint = info.Fingerprint
+	}
+	return SignWithAgent(ag, fingerprint, data)
+}
+
 // SignChallengeWithKeyFile signs a base64-encoded challenge using a key file.
 func SignChallengeWithKeyFile(keyPath, challenge string) (string, error) {
 	challengeBytes, err := base64.RawStdEncoding.DecodeString(challenge)
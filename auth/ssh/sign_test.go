@@ -1,7 +1,10 @@
 package ssh
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/base64"
+	"encoding/pem"
 	"errors"
 	"os"
 	"path/filepath"
@@ -257,3 +260,135 @@ func TestSignatureVerification(t *testing.T) {
 		t.Errorf("signature verification failed: %v", err)
 	}
 }
+
+// writeEncryptedKey writes a passphrase-protected ed25519 key and returns
+// its path and public key.
+func writeEncryptedKey(t *testing.T, passphrase string) (string, ssh.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(priv, "test@example.com", []byte(passphrase))
+	if err != nil {
+		t.Fatalf("MarshalPrivateKeyWithPassphrase() error = %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyPath, sshPub, priv
+}
+
+func verifySignature(t *testing.T, pub ssh.PublicKey, data []byte, sigB64 string) {
+	t.Helper()
+	sigBytes, err := base64.StdEncoding.DecodeString(sigB64)
+	if err != nil {
+		t.Fatalf("DecodeString() error = %v", err)
+	}
+	sig := &ssh.Signature{}
+	if err := ssh.Unmarshal(sigBytes, sig); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if err := pub.Verify(data, sig); err != nil {
+		t.Errorf("signature verification failed: %v", err)
+	}
+}
+
+func TestSignWithKeyFile_Encrypted(t *testing.T) {
+	keyPath, pub, priv := writeEncryptedKey(t, "hunter2")
+	data := []byte("test data")
+
+	t.Run("no passphrase", func(t *testing.T) {
+		_, err := SignWithKeyFile(keyPath, data)
+		if !errors.Is(err, ErrEncryptedKey) {
+			t.Errorf("error = %v, want ErrEncryptedKey", err)
+		}
+	})
+
+	t.Run("passphrase", func(t *testing.T) {
+		var asked string
+		sig, err := SignWithKeyFileOptions(keyPath, data, KeyFileOptions{
+			Passphrase: func(path string) ([]byte, error) {
+				asked = path
+				return []byte("hunter2"), nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("SignWithKeyFileOptions() error = %v", err)
+		}
+		if asked != keyPath {
+			t.Errorf("passphrase asked for %q, want %q", asked, keyPath)
+		}
+		verifySignature(t, pub, data, sig)
+	})
+
+	t.Run("incorrect passphrase", func(t *testing.T) {
+		_, err := SignWithKeyFileOptions(keyPath, data, KeyFileOptions{
+			Passphrase: func(string) ([]byte, error) { return []byte("wrong"), nil },
+		})
+		if !errors.Is(err, ErrIncorrectPassphrase) {
+			t.Errorf("error = %v, want ErrIncorrectPassphrase", err)
+		}
+	})
+
+	t.Run("prompt error", func(t *testing.T) {
+		canceled := errors.New("canceled")
+		_, err := SignWithKeyFileOptions(keyPath, data, KeyFileOptions{
+			Passphrase: func(string) ([]byte, error) { return nil, canceled },
+		})
+		if !errors.Is(err, canceled) {
+			t.Errorf("error = %v, want prompt error", err)
+		}
+	})
+
+	t.Run("agent holds key", func(t *testing.T) {
+		keyring := agent.NewKeyring().(agent.ExtendedAgent)
+		if err := keyring.Add(agent.AddedKey{PrivateKey: priv}); err != nil {
+			t.Fatal(err)
+		}
+		sig, err := SignWithKeyFileOptions(keyPath, data, KeyFileOptions{
+			Agent: keyring,
+			Passphrase: func(string) ([]byte, error) {
+				t.Error("passphrase requested although agent holds the key")
+				return nil, errors.New("unexpected prompt")
+			},
+		})
+		if err != nil {
+			t.Fatalf("SignWithKeyFileOptions() error = %v", err)
+		}
+		verifySignature(t, pub, data, sig)
+	})
+
+	t.Run("agent without key falls back to passphrase", func(t *testing.T) {
+		keyring := agent.NewKeyring().(agent.ExtendedAgent)
+		sig, err := SignWithKeyFileOptions(keyPath, data, KeyFileOptions{
+			Agent:      keyring,
+			Passphrase: func(string) ([]byte, error) { return []byte("hunter2"), nil },
+		})
+		if err != nil {
+			t.Fatalf("SignWithKeyFileOptions() error = %v", err)
+		}
+		verifySignature(t, pub, data, sig)
+	})
+}
+
+func TestLoadSigner(t *testing.T) {
+	keyPath, pub, _ := writeEncryptedKey(t, "hunter2")
+
+	signer, err := LoadSigner(keyPath, func(string) ([]byte, error) { return []byte("hunter2"), nil })
+	if err != nil {
+		t.Fatalf("LoadSigner() error = %v", err)
+	}
+	if ComputeFingerprint(signer.PublicKey().Marshal()) != ComputeFingerprint(pub.Marshal()) {
+		t.Error("LoadSigner() returned a different key")
+	}
+
+	if _, err := LoadSigner(keyPath, nil); !errors.Is(err, ErrEncryptedKey) {
+		t.Errorf("LoadSigner(nil passphrase) error = %v, want ErrEncryptedKey", err)
+	}
+}
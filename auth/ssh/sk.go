@@ -0,0 +1,17 @@
+package ssh
+
+import "strings"
+
+// Security key (FIDO2/U2F) key types, created with ssh-keygen -t ed25519-sk
+// or ecdsa-sk. The private key stays on the hardware token; the key file
+// only holds a handle to it.
+const (
+	KeyTypeSKEd25519 = "sk-ssh-ed25519@openssh.com"
+	KeyTypeSKECDSA   = "sk-ecdsa-sha2-nistp256@openssh.com"
+)
+
+// IsSecurityKey reports whether keyType is a hardware security key type,
+// including certificates for one.
+func IsSecurityKey(keyType string) bool {
+	return strings.HasPrefix(keyType, "sk-")
+}
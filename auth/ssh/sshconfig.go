@@ -0,0 +1,387 @@
+package ssh
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// maxIncludeDepth bounds nested Include directives, as ssh does
+const maxIncludeDepth = 16
+
+// ConfigFile is a parsed OpenSSH client config (~/.ssh/config). Only the
+// settings this package needs are interpreted: HostName, User, Port,
+// IdentityFile, and IdentitiesOnly.
+type ConfigFile struct {
+	blocks []configBlock
+}
+
+// configBlock is a Host (or Match) section and its options in file order
+type configBlock struct {
+	patterns []string // nil matches every host
+	never    bool     // Match criteria this package cannot evaluate
+	options  []configOption
+}
+
+type configOption struct {
+	key   string // Lowercased
+	value string
+}
+
+// HostSettings are the effective settings for one host.
+type HostSettings struct {
+	// Host is the name that was resolved, possibly an alias.
+	Host string
+
+	// HostName is the real host to connect to. Defaults to Host.
+	HostName string
+
+	// User is the remote user, or "" if not configured.
+	User string
+
+	// Port defaults to "22".
+	Port string
+
+	// IdentityFiles are the private keys to try, in order, with ~ and
+	// % tokens expanded.
+	IdentityFiles []string
+
+	// IdentitiesOnly restricts authentication to IdentityFiles.
+	IdentitiesOnly bool
+}
+
+// ParseConfig reads an OpenSSH client config file, following Include
+// directives. Relative includes are resolved against the file's directory.
+func ParseConfig(configPath string) (*ConfigFile, error) {
+	c := &ConfigFile{blocks: []configBlock{{}}}
+	if err := c.parseFile(configPath, 0); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *ConfigFile) parseFile(configPath string, depth int) error {
+	f, err := os.Open(configPath) //nolint:gosec // user-provided path expected
+	if err != nil {
+		return fmt.Errorf("read ssh config: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return c.parse(f, filepath.Dir(configPath), configPath, depth)
+}
+
+func (c *ConfigFile) parse(r io.Reader, dir, name string, depth int) error {
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		key, args := splitConfigLine(scanner.Text())
+		if key == "" {
+			continue
+		}
+
+		switch key {
+		case "host":
+			c.blocks = append(c.blocks, configBlock{patterns: args})
+		case "match":
+			c.blocks = append(c.blocks, parseMatch(args))
+		case "include":
+			if depth >= maxIncludeDepth {
+				return fmt.Errorf("%s:%d: %w: Include nested too deeply", name, lineNo, ErrInvalidConfig)
+			}
+			for _, pattern := range args {
+				if err := c.include(dir, pattern, depth+1); err != nil {
+					return err
+				}
+			}
+		default:
+			if len(args) == 0 {
+				return fmt.Errorf("%s:%d: %w: %s has no value", name, lineNo, ErrInvalidConfig, key)
+			}
+			block := &c.blocks[len(c.blocks)-1]
+			block.options = append(block.options, configOption{key: key, value: strings.Join(args, " ")})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read ssh config: %w", err)
+	}
+	return nil
+}
+
+// include parses the files matching pattern into the current block
+func (c *ConfigFile) include(dir, pattern string, depth int) error {
+	pattern = expandHome(pattern)
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(dir, pattern)
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("%w: Include %s: %w", ErrInvalidConfig, pattern, err)
+	}
+	for _, m := range matches {
+		if err := c.parseFile(m, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitConfigLine returns the lowercased keyword and its arguments. Both
+// "Key value" and "Key=value" forms and double-quoted arguments are accepted.
+func splitConfigLine(line string) (string, []string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", nil
+	}
+
+	end := strings.IndexAny(line, " \t=")
+	if end < 0 {
+		return strings.ToLower(line), nil
+	}
+	key := strings.ToLower(line[:end])
+	rest := strings.TrimLeft(line[end:], " \t")
+	rest = strings.TrimPrefix(rest, "=")
+
+	var args []string
+	var b strings.Builder
+	inQuote, inArg := false, false
+	for _, r := range rest {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			inArg = true
+		case (r == ' ' || r == '\t') && !inQuote:
+			if inArg {
+				args = append(args, b.String())
+				b.Reset()
+				inArg = false
+			}
+		case r == '#' && !inQuote && !inArg:
+			return key, args // Trailing comment
+		default:
+			b.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, b.String())
+	}
+	return key, args
+}
+
+// parseMatch handles "Match all" and "Match host <patterns>"; other
+// criteria (exec, user, ...) are not evaluated and never match.
+func parseMatch(args []string) configBlock {
+	if len(args) == 1 && strings.EqualFold(args[0], "all") {
+		return configBlock{}
+	}
+	if len(args) == 2 && strings.EqualFold(args[0], "host") {
+		return configBlock{patterns: strings.Split(args[1], ",")}
+	}
+	return configBlock{never: true}
+}
+
+// matches reports whether host matches the block's patterns. A negated
+// pattern that matches excludes the host outright.
+func (b configBlock) matches(host string) bool {
+	if b.never {
+		return false
+	}
+	if b.patterns == nil {
+		return true
+	}
+	matched := false
+	for _, p := range b.patterns {
+		for _, pattern := range strings.Split(p, ",") {
+			negate := strings.HasPrefix(pattern, "!")
+			ok := wildcardMatch(strings.TrimPrefix(pattern, "!"), host)
+			if ok && negate {
+				return false
+			}
+			matched = matched || ok
+		}
+	}
+	return matched
+}
+
+// wildcardMatch matches s against an OpenSSH host pattern, ignoring case.
+// Only "*" (any run of characters) and "?" (one character) are special;
+// brackets and everything else match literally, so "[host]:2222" works.
+func wildcardMatch(pattern, s string) bool {
+	pattern, s = strings.ToLower(pattern), strings.ToLower(s)
+	p, i := 0, 0
+	star, starI := -1, 0
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == s[i]):
+			p++
+			i++
+		case p < len(pattern) && pattern[p] == '*':
+			star, starI = p, i
+			p++
+		case star >= 0:
+			// Let the last * absorb one more character
+			starI++
+			p, i = star+1, starI
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// Resolve returns the effective settings for host, which may be an alias
+// defined by a Host block. As in ssh, the first value found for each
+// setting wins, except IdentityFile, which accumulates.
+func (c *ConfigFile) Resolve(host string) *HostSettings {
+	s := &HostSettings{Host: host}
+	var identities []string
+	seen := map[string]bool{}
+	for _, b := range c.blocks {
+		if !b.matches(host) {
+			continue
+		}
+		for _, o := range b.options {
+			if o.key == "identityfile" {
+				identities = append(identities, o.value)
+				continue
+			}
+			if seen[o.key] {
+				continue
+			}
+			seen[o.key] = true
+			switch o.key {
+			case "hostname":
+				s.HostName = o.value
+			case "user":
+				s.User = o.value
+			case "port":
+				s.Port = o.value
+			case "identitiesonly":
+				s.IdentitiesOnly = strings.EqualFold(o.value, "yes")
+			}
+		}
+	}
+
+	if s.HostName == "" {
+		s.HostName = host
+	} else {
+		s.HostName = strings.ReplaceAll(s.HostName, "%h", host)
+	}
+	if s.Port == "" {
+		s.Port = "22"
+	}
+	for _, id := range identities {
+		if strings.EqualFold(id, "none") {
+			continue
+		}
+		s.IdentityFiles = append(s.IdentityFiles, expandTokens(id, s))
+	}
+	return s
+}
+
+// expandTokens expands ~ and the %d, %h, %r, %u, and %% tokens
+func expandTokens(value string, s *HostSettings) string {
+	home, _ := os.UserHomeDir()
+	localUser := ""
+	if u, err := user.Current(); err == nil {
+		localUser = u.Username
+	}
+	remoteUser := s.User
+	if remoteUser == "" {
+		remoteUser = localUser
+	}
+
+	r := strings.NewReplacer("%%", "%", "%d", home, "%h", s.HostName, "%r", remoteUser, "%u", localUser)
+	return expandHome(r.Replace(value))
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(p string) string {
+	if p != "~" && !strings.HasPrefix(p, "~/") {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, strings.TrimPrefix(p, "~"))
+}
+
+// HostFromRemote returns the SSH host (or Host alias) and user of a git
+// remote URL, such as git@github.com:org/repo.git,
+// ssh://git@github.com/org/repo, or work-github:org/repo. It returns ""
+// for non-SSH remotes (https, file paths).
+func HostFromRemote(remoteURL string) (host, user string) {
+	if strings.Contains(remoteURL, "://") {
+		u, err := url.Parse(remoteURL)
+		if err != nil || (u.Scheme != "ssh" && u.Scheme != "git+ssh") {
+			return "", ""
+		}
+		return u.Hostname(), u.User.Username()
+	}
+
+	// scp-like syntax: [user@]host:path
+	target, _, ok := strings.Cut(remoteURL, ":")
+	if !ok || strings.ContainsAny(target, "/\\") || len(target) == 1 {
+		return "", "" // Local path (or Windows drive letter)
+	}
+	if u, h, ok := strings.Cut(target, "@"); ok {
+		return h, u
+	}
+	return target, ""
+}
+
+// FindKeyForHost finds the key to use for host: the first IdentityFile in
+// ~/.ssh/config with a readable .pub file, or the default key.
+func FindKeyForHost(host string) (*KeyInfo, error) {
+	return FindKeyForHostWithConfig(Config{}, host)
+}
+
+// FindKeyForHostWithConfig finds the key for host using custom
+// configuration. The SSH config is read from SSHDir/config; a missing file
+// falls back to FindDefaultKeyWithConfig.
+func FindKeyForHostWithConfig(cfg Config, host string) (*KeyInfo, error) {
+	sshDir, err := cfg.sshDir()
+	if err != nil {
+		return nil, err
+	}
+
+	sshConfig, err := ParseConfig(filepath.Join(sshDir, "config"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return FindDefaultKeyWithConfig(cfg)
+		}
+		return nil, err
+	}
+
+	settings := sshConfig.Resolve(host)
+	for _, id := range settings.IdentityFiles {
+		if info, err := ReadPublicKey(id + ".pub"); err == nil {
+			return info, nil
+		}
+	}
+	if settings.IdentitiesOnly && len(settings.IdentityFiles) > 0 {
+		return nil, fmt.Errorf("%w: none of the IdentityFiles for %s have a .pub file", ErrNoSSHKeys, host)
+	}
+	return FindDefaultKeyWithConfig(cfg)
+}
+
+// FindKeyForRemote finds the key for a git remote URL's host. Non-SSH
+// remotes use the default key.
+func FindKeyForRemote(remoteURL string) (*KeyInfo, error) {
+	host, _ := HostFromRemote(remoteURL)
+	if host == "" {
+		return FindDefaultKey()
+	}
+	return FindKeyForHost(host)
+}
@@ -0,0 +1,176 @@
+package ssh
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeSSHConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, "config")
+	if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestParseConfig_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "work.conf"), []byte(`
+Host gitlab.corp
+  User git
+  IdentityFile `+dir+`/id_corp
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	configPath := writeSSHConfig(t, dir, `
+# Personal and work GitHub accounts
+Include work.conf
+
+Host github-work
+  HostName github.com
+  User git
+  IdentityFile `+dir+`/id_work
+  IdentitiesOnly yes
+
+Host github.com github-*
+  User personal
+  Port=2222
+  IdentityFile "`+dir+`/id_personal"
+
+Host * !internal
+  IdentityFile %d/.ssh/id_%h
+
+Match exec "true"
+  User never
+`)
+
+	cfg, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+
+	work := cfg.Resolve("github-work")
+	if work.HostName != "github.com" || work.User != "git" || work.Port != "2222" || !work.IdentitiesOnly {
+		t.Errorf("Resolve(github-work) = %+v", work)
+	}
+	home, _ := os.UserHomeDir()
+	wantIDs := []string{dir + "/id_work", dir + "/id_personal", filepath.Join(home, ".ssh/id_github.com")}
+	if !slices.Equal(work.IdentityFiles, wantIDs) {
+		t.Errorf("IdentityFiles = %v, want %v", work.IdentityFiles, wantIDs)
+	}
+
+	gh := cfg.Resolve("github.com")
+	if gh.HostName != "github.com" || gh.User != "personal" || gh.IdentitiesOnly {
+		t.Errorf("Resolve(github.com) = %+v", gh)
+	}
+
+	corp := cfg.Resolve("gitlab.corp")
+	if corp.User != "git" || corp.Port != "22" || corp.IdentityFiles[0] != dir+"/id_corp" {
+		t.Errorf("Resolve(gitlab.corp) = %+v", corp)
+	}
+
+	internal := cfg.Resolve("internal")
+	if len(internal.IdentityFiles) != 0 || internal.User != "" {
+		t.Errorf("Resolve(internal) = %+v, want negated pattern to skip Host *", internal)
+	}
+}
+
+func TestWildcardMatch(t *testing.T) {
+	tests := []struct {
+		pattern, host string
+		want          bool
+	}{
+		{"github.com", "GitHub.com", true},
+		{"*.corp", "git.corp", true},
+		{"*.corp", "corp", false},
+		{"git?.corp", "git1.corp", true},
+		{"git?.corp", "git.corp", false},
+		{"*git*", "my-gitlab", true},
+		{"[host]:2222", "[host]:2222", true},
+		{"[ab]", "a", false},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+		{"*", "", true},
+	}
+	for _, tt := range tests {
+		if got := wildcardMatch(tt.pattern, tt.host); got != tt.want {
+			t.Errorf("wildcardMatch(%q, %q) = %v, want %v", tt.pattern, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	if _, err := ParseConfig(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("ParseConfig(missing) error = nil")
+	}
+
+	dir := t.TempDir()
+	if _, err := ParseConfig(writeSSHConfig(t, dir, "Host x\n  User\n")); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("ParseConfig(no value) error = %v, want ErrInvalidConfig", err)
+	}
+
+	loop := writeSSHConfig(t, dir, "Include config\n")
+	if _, err := ParseConfig(loop); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("ParseConfig(include loop) error = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestHostFromRemote(t *testing.T) {
+	tests := []struct {
+		remote, host, user string
+	}{
+		{"git@github.com:org/repo.git", "github.com", "git"},
+		{"github-work:org/repo.git", "github-work", ""},
+		{"ssh://git@gitlab.corp:2222/org/repo.git", "gitlab.corp", "git"},
+		{"git+ssh://gitlab.corp/org/repo", "gitlab.corp", ""},
+		{"https://github.com/org/repo.git", "", ""},
+		{"/srv/git/repo.git", "", ""},
+		{"./relative:path", "", ""},
+		{`C:\repos\repo`, "", ""},
+	}
+	for _, tt := range tests {
+		host, user := HostFromRemote(tt.remote)
+		if host != tt.host || user != tt.user {
+			t.Errorf("HostFromRemote(%q) = %q, %q, want %q, %q", tt.remote, host, user, tt.host, tt.user)
+		}
+	}
+}
+
+func TestFindKeyForHostWithConfig(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"id_ed25519.pub", "id_work.pub"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(testED25519PublicKey+" "+name), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := Config{SSHDir: dir}
+
+	// No config file: default key
+	key, err := FindKeyForHostWithConfig(cfg, "github-work")
+	if err != nil || filepath.Base(key.Path) != "id_ed25519.pub" {
+		t.Fatalf("without config: key = %v, err = %v", key, err)
+	}
+
+	writeSSHConfig(t, dir, "Host github-work\n  HostName github.com\n  IdentityFile "+dir+"/id_missing\n  IdentityFile "+dir+"/id_work\n")
+	key, err = FindKeyForHostWithConfig(cfg, "github-work")
+	if err != nil {
+		t.Fatalf("FindKeyForHostWithConfig() error = %v", err)
+	}
+	if filepath.Base(key.Path) != "id_work.pub" {
+		t.Errorf("key = %s, want id_work.pub", key.Path)
+	}
+
+	key, err = FindKeyForHostWithConfig(cfg, "other.example.com")
+	if err != nil || filepath.Base(key.Path) != "id_ed25519.pub" {
+		t.Errorf("unconfigured host: key = %v, err = %v", key, err)
+	}
+
+	writeSSHConfig(t, dir, "Host locked\n  IdentityFile "+dir+"/id_missing\n  IdentitiesOnly yes\n")
+	if _, err := FindKeyForHostWithConfig(cfg, "locked"); !errors.Is(err, ErrNoSSHKeys) {
+		t.Errorf("IdentitiesOnly without key: error = %v, want ErrNoSSHKeys", err)
+	}
+}
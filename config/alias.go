@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2/unstable"
+	"gopkg.in/yaml.v3"
+)
+
+// renameDeprecated maps a key under a deprecated name in
+// ResolverConfig.Aliases to its new name. It returns the new key, and the
+// deprecated and replacement names as they would be written in the file.
+// ok is false for current keys.
+func (r *Resolver) renameDeprecated(key string) (renamed, deprecated, replacement string, ok bool) {
+	// Profile sections use the same names as the top level
+	var prefix string
+	if rest, isProfile := strings.CutPrefix(key, profilesKey+"."); isProfile {
+		if name, sub, found := strings.Cut(rest, "."); found {
+			prefix = profilesKey + "." + name + "."
+			key = sub
+		}
+	}
+
+	for old, current := range r.config.Aliases {
+		if key == old || strings.HasPrefix(key, old+".") || strings.HasPrefix(key, old+"[") {
+			return prefix + current + key[len(old):], prefix + old, prefix + current, true
+		}
+	}
+	return "", "", "", false
+}
+
+// fileSettings flattens a parsed config file into dotted keys, moving
+// deprecated keys to their new names with a warning that gives the file
+// and line. If both names are set, the new one wins.
+func (r *Resolver) fileSettings(path string, data []byte, parsed map[string]interface{}) map[string]string {
+	flat := make(map[string]string)
+	flatten("", parsed, func(key, value string) {
+		flat[key] = value
+	})
+	if len(r.config.Aliases) == 0 {
+		return flat
+	}
+
+	settings := make(map[string]string, len(flat))
+	replacements := make(map[string]string) // Deprecated name -> new name
+	for key, value := range flat {
+		renamed, deprecated, replacement, ok := r.renameDeprecated(key)
+		if !ok {
+			settings[key] = value
+			continue
+		}
+		replacements[deprecated] = replacement
+		if _, exists := flat[renamed]; !exists {
+			settings[renamed] = value
+		}
+	}
+
+	deprecated := make([]string, 0, len(replacements))
+	for key := range replacements {
+		deprecated = append(deprecated, key)
+	}
+	slices.Sort(deprecated)
+	for _, key := range deprecated {
+		location := path
+		if line := findKeyLine(path, data, key); line > 0 {
+			location = fmt.Sprintf("%s:%d", path, line)
+		}
+		r.warn(fmt.Sprintf("%s: %q is deprecated, use %q", location, key, replacements[key]))
+	}
+	return settings
+}
+
+// applyDeprecatedEnv reads environment variables named for deprecated
+// keys, for keys whose new variable is not set
+func (r *Resolver) applyDeprecatedEnv(cfg *Resolved) {
+	if r.config.EnvPrefix == "" {
+		return
+	}
+
+	olds := make([]string, 0, len(r.config.Aliases))
+	for old := range r.config.Aliases {
+		olds = append(olds, old)
+	}
+	slices.Sort(olds)
+
+	for _, old := range olds {
+		current := r.config.Aliases[old]
+		oldVar, newVar := r.config.EnvVar(old), r.config.EnvVar(current)
+		value := os.Getenv(oldVar)
+		if value == "" || os.Getenv(newVar) != "" {
+			continue
+		}
+		r.warn(fmt.Sprintf("%s is deprecated, use %s", oldVar, newVar))
+		cfg.set(current, value, SourceEnv, oldVar)
+	}
+}
+
+// findKeyLine returns the line a dotted key is written on in a config
+// file, or 0 if it cannot be found
+func findKeyLine(path string, data []byte, key string) int {
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return tomlKeyLine(data, key)
+	}
+
+	// JSON is also valid YAML, so one walk covers both
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return 0
+	}
+	node := doc.Content[0]
+	line := 0
+	for _, part := range strings.Split(key, ".") {
+		if node.Kind != yaml.MappingNode {
+			return 0
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == part {
+				line, next = node.Content[i].Line, node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return 0
+		}
+		node = next
+	}
+	return line
+}
+
+// tomlKeyLine returns the line a dotted key is first written on in a TOML
+// document, or 0 if it cannot be found. Keys under a [table] or
+// [[array.of.tables]] header are prefixed with the header's key.
+func tomlKeyLine(data []byte, key string) int {
+	var p unstable.Parser
+	p.Reset(data)
+
+	var table []string
+	for p.NextExpression() {
+		expr := p.Expression()
+		var path []string
+		if expr.Kind == unstable.KeyValue {
+			path = slices.Clone(table)
+		}
+		for it := expr.Key(); it.Next(); {
+			node := it.Node()
+			path = append(path, string(node.Data))
+			if strings.Join(path, ".") == key {
+				return p.Shape(node.Raw).Start.Line
+			}
+		}
+		if expr.Kind == unstable.Table || expr.Kind == unstable.ArrayTable {
+			table = path
+		}
+	}
+	return 0
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolver_Aliases(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalPath := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(globalPath, []byte(`format: table
+server_url: http://old
+llm:
+  model: opus
+profiles:
+  staging:
+    server_url: http://staging
+`), 0644)
+	localPath := filepath.Join(tmpDir, ".myapp.toml")
+	os.WriteFile(localPath, []byte("# Local\nretries = 3\n\n[llm]\nmodel = \"sonnet\"\n"), 0644)
+
+	var stderr bytes.Buffer
+	resolver := NewResolverWithPaths(ResolverConfig{
+		Aliases:   map[string]string{"server_url": "api_url", "llm": "models"},
+		Profile:   "staging",
+		ErrWriter: &stderr,
+	}, globalPath, localPath)
+	cfg := resolver.Resolve()
+
+	tests := []struct {
+		key    string
+		want   string
+		source Source
+	}{
+		{"api_url", "http://staging", ProfileSource("staging")},
+		{"models.model", "sonnet", SourceLocal},
+		{"format", "table", SourceGlobal},
+	}
+	for _, tt := range tests {
+		value, source := cfg.GetWithSource(tt.key)
+		if value != tt.want || source != tt.source {
+			t.Errorf("%s = %q (%s), want %q (%s)", tt.key, value, source, tt.want, tt.source)
+		}
+	}
+	if got := cfg.Get("server_url"); got != "" {
+		t.Errorf("server_url = %q, want moved to api_url", got)
+	}
+
+	want := []string{
+		globalPath + `:3: "llm" is deprecated, use "models"`,
+		globalPath + `:7: "profiles.staging.server_url" is deprecated, use "profiles.staging.api_url"`,
+		globalPath + `:2: "server_url" is deprecated, use "api_url"`,
+		localPath + `:4: "llm" is deprecated, use "models"`,
+	}
+	if got := strings.Join(resolver.Warnings, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("warnings =\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestResolver_AliasNewKeyWins(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalPath := filepath.Join(tmpDir, "config.json")
+	os.WriteFile(globalPath, []byte("{\n  \"api_url\": \"http://new\",\n  \"server_url\": \"http://old\"\n}\n"), 0644)
+
+	var stderr bytes.Buffer
+	resolver := NewResolverWithPaths(ResolverConfig{
+		Aliases:   map[string]string{"server_url": "api_url"},
+		ErrWriter: &stderr,
+	}, globalPath, "")
+
+	if got := resolver.Resolve().Get("api_url"); got != "http://new" {
+		t.Errorf("api_url = %q, want http://new", got)
+	}
+	if want := globalPath + `:3: "server_url" is deprecated`; !strings.Contains(stderr.String(), want) {
+		t.Errorf("warning = %q, want %q", stderr.String(), want)
+	}
+}
+
+func TestResolver_AliasEnv(t *testing.T) {
+	t.Setenv("ALIASTEST_SERVER_URL", "http://env-old")
+
+	var stderr bytes.Buffer
+	resolver := NewResolverWithPaths(ResolverConfig{
+		EnvPrefix: "ALIASTEST_",
+		Aliases:   map[string]string{"server_url": "api_url"},
+		ErrWriter: &stderr,
+	}, "", "")
+
+	cfg := resolver.Resolve()
+	if value, source := cfg.GetWithSource("api_url"); value != "http://env-old" || source != SourceEnv {
+		t.Errorf("api_url = %q (%s), want http://env-old (env)", value, source)
+	}
+	if !strings.Contains(stderr.String(), "ALIASTEST_SERVER_URL is deprecated, use ALIASTEST_API_URL") {
+		t.Errorf("warning = %q", stderr.String())
+	}
+
+	t.Setenv("ALIASTEST_API_URL", "http://env-new")
+	if got := resolver.Resolve().Get("api_url"); got != "http://env-new" {
+		t.Errorf("api_url = %q, want http://env-new", got)
+	}
+}
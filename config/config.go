@@ -1,19 +1,25 @@
 package config
 
 import (
+	"encoding"
+	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
-
-	"gopkg.in/yaml.v3"
+	"sync"
+	"time"
 )
 
 // ResolverConfig configures the hierarchical config resolver.
 type ResolverConfig struct {
 	// EnvPrefix is prepended to key names for environment variable lookup.
-	// For example, with EnvPrefix "MYAPP_", key "api_url" maps to MYAPP_API_URL.
+	// For example, with EnvPrefix "MYAPP_", key "api_url" maps to MYAPP_API_URL
+	// and "models.review" to MYAPP_MODELS_REVIEW; see EnvVar. Only keys with
+	// a default, a file value, or an alias are looked up.
 	EnvPrefix string
 
 	// GlobalConfigDir is the name of the directory under ~/.config/
@@ -22,28 +28,72 @@ type ResolverConfig struct {
 	GlobalConfigDir string
 
 	// GlobalConfigFile is the filename for global config.
-	// Defaults to "config.yaml" if empty.
+	// Defaults to "config.yaml" if empty. If the file does not exist, the
+	// same name with a .yaml, .yml, .toml, or .json extension is used if
+	// found. The format follows the extension.
 	GlobalConfigFile string
 
 	// LocalConfigName is the filename for local config in the git root.
-	// For example, ".myapp.yaml".
+	// For example, ".myapp.yaml". Like GlobalConfigFile, ".myapp.toml" or
+	// ".myapp.json" is used if it exists instead.
 	LocalConfigName string
 
+	// MergeLocalConfigs also reads LocalConfigName files in the directories
+	// between the git root and the working directory, so subprojects in a
+	// monorepo can override only the settings they need. Files closer to
+	// the working directory win.
+	MergeLocalConfigs bool
+
+	// Aliases maps deprecated key names to their replacements. Values set
+	// under an old name, in a file or its environment variable, are used
+	// for the new name with a warning giving the file and line.
+	Aliases map[string]string
+
+	// Profile selects a section under "profiles" in the config files whose
+	// values override the rest of both files. If empty, the profile is read
+	// from the environment variable for the "profile" key; see
+	// Resolver.Profile.
+	Profile string
+
 	// Defaults provides the default values for configuration keys.
 	Defaults map[string]string
 
-	// ValidGlobalKeys lists keys that can be set in global config.
-	// If nil, all keys are valid.
+	// ValidGlobalKeys lists keys that can be set in global config. A key
+	// also allows the keys nested under it. If nil, all keys are valid.
 	ValidGlobalKeys []string
 
-	// ValidLocalKeys lists keys that can be set in local config.
-	// If nil, all keys are valid.
+	// ValidLocalKeys lists keys that can be set in local config. A key
+	// also allows the keys nested under it. If nil, all keys are valid.
 	ValidLocalKeys []string
 
 	// GitRootFinder is a function that finds the git root directory.
 	// If nil, uses a simple git root detection.
 	GitRootFinder func(startDir string) (string, error)
 
+	// Schema, if set, validates values during Resolve; see Resolved.Err.
+	Schema *Schema
+
+	// Secrets resolves ${secret:path#key} references. ${env:NAME} and
+	// ${file:/path} references work without it.
+	Secrets SecretProvider
+
+	// TrustLocalReferences expands references in local config files too.
+	// By default only global, env, and flag values may use them: local
+	// files are committed to repositories, and a cloned repository could
+	// otherwise copy the user's environment or files into a setting such
+	// as a webhook URL. Local values with references return
+	// ErrUntrustedReference.
+	TrustLocalReferences bool
+
+	// WatchInterval is how often Watch checks the config files for changes.
+	// Defaults to DefaultWatchInterval if zero.
+	WatchInterval time.Duration
+
+	// RedactKeys lists keys whose values Explain hides in redacted mode, in
+	// addition to keys named like secrets (token, password, ...). A key
+	// also covers the keys nested under it.
+	RedactKeys []string
+
 	// ErrWriter is where warnings are written.
 	// Defaults to os.Stderr if nil.
 	ErrWriter io.Writer
@@ -61,10 +111,16 @@ type Resolver struct {
 	config     ResolverConfig
 	globalPath string
 	localPath  string
+	localPaths []string // Local files to merge, git root first
 	gitRoot    string
 
 	// Warnings collects non-fatal issues during resolution.
 	Warnings []string
+
+	mu          sync.Mutex // Guards Warnings and the fields below
+	subscribers map[int]func(Update)
+	nextSub     int
+	flags       map[string]string // From the last ResolveWithFlags, re-applied by Watch
 }
 
 // NewResolver creates a new configuration resolver.
@@ -83,7 +139,7 @@ func NewResolver(cfg ResolverConfig) *Resolver {
 		if root, err := cfg.GitRootFinder("."); err == nil && root != "" {
 			resolver.gitRoot = root
 			if cfg.LocalConfigName != "" {
-				resolver.localPath = filepath.Join(root, cfg.LocalConfigName)
+				resolver.localPath = findConfigFile(filepath.Join(root, cfg.LocalConfigName))
 			}
 		}
 	} else {
@@ -91,17 +147,25 @@ func NewResolver(cfg ResolverConfig) *Resolver {
 		if root := findGitRoot("."); root != "" {
 			resolver.gitRoot = root
 			if cfg.LocalConfigName != "" {
-				resolver.localPath = filepath.Join(root, cfg.LocalConfigName)
+				resolver.localPath = findConfigFile(filepath.Join(root, cfg.LocalConfigName))
 			}
 		}
 	}
 
+	// Collect local configs in subdirectories down to the working directory
+	if resolver.localPath != "" {
+		resolver.localPaths = []string{resolver.localPath}
+		if cfg.MergeLocalConfigs {
+			resolver.localPaths = append(resolver.localPaths, findLocalConfigs(resolver.gitRoot, ".", cfg.LocalConfigName)...)
+		}
+	}
+
 	// Set global config path
 	if cfg.GlobalConfigDir != "" {
 		if home, err := os.UserHomeDir(); err == nil {
-			resolver.globalPath = filepath.Join(
+			resolver.globalPath = findConfigFile(filepath.Join(
 				home, ".config", cfg.GlobalConfigDir, cfg.globalConfigFile(),
-			)
+			))
 		}
 	}
 
@@ -116,6 +180,9 @@ func NewResolverWithPaths(cfg ResolverConfig, globalPath, localPath string) *Res
 		globalPath: globalPath,
 		localPath:  localPath,
 	}
+	if localPath != "" {
+		resolver.localPaths = []string{localPath}
+	}
 
 	// Set default error writer
 	if cfg.ErrWriter == nil {
@@ -127,7 +194,9 @@ func NewResolverWithPaths(cfg ResolverConfig, globalPath, localPath string) *Res
 
 // warn adds a warning and optionally prints it.
 func (r *Resolver) warn(msg string) {
+	r.mu.Lock()
 	r.Warnings = append(r.Warnings, msg)
+	r.mu.Unlock()
 	if r.config.ErrWriter != nil {
 		fmt.Fprintf(r.config.ErrWriter, "Warning: %s\n", msg)
 	}
@@ -137,10 +206,58 @@ func (r *Resolver) warn(msg string) {
 type Resolved struct {
 	values  map[string]string
 	sources map[string]Source
+	origins map[string]string // File path or env var behind each value
+	err     error             // Schema violations
+	secrets SecretProvider
+	profile string
+
+	refuseLocal bool     // Refuse references in local config values
+	localFiles  []string // Local config files, for profile values
+
+	overridden map[string][]Setting // Lower-layer values, highest first
+	redact     []string
+}
+
+// Err returns the schema violations found during resolution as a
+// *ValidationError, or nil if the values are valid or no schema is set.
+func (c *Resolved) Err() error {
+	return c.err
 }
 
-// Get returns the value for a key, or empty string if not set.
+// set records a value with its source and origin, keeping the value it
+// replaces for Explain
+func (c *Resolved) set(key, value string, source Source, origin string) {
+	if old, ok := c.values[key]; ok {
+		prev := Setting{Value: old, Source: c.sources[key], Origin: c.origins[key]}
+		c.overridden[key] = append([]Setting{prev}, c.overridden[key]...)
+	}
+	c.values[key] = value
+	c.sources[key] = source
+	c.origins[key] = origin
+}
+
+// Get returns the value for a key with references such as ${env:TOKEN}
+// expanded, or empty string if not set or a reference cannot be resolved.
+// Use Value to see the error.
 func (c *Resolved) Get(key string) string {
+	value, _ := c.Value(key)
+	return value
+}
+
+// Value returns the value for a key with ${env:NAME}, ${file:/path}, and
+// ${secret:path#key} references resolved. References are looked up on
+// every call, so secrets are never stored in the Resolved.
+func (c *Resolved) Value(key string) (string, error) {
+	value, err := c.expand(key, c.values[key])
+	if err != nil {
+		return "", fmt.Errorf("config: %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// Raw returns the value for a key as configured, without expanding
+// references.
+func (c *Resolved) Raw(key string) string {
 	return c.values[key]
 }
 
@@ -149,12 +266,13 @@ func (c *Resolved) Source(key string) Source {
 	return c.sources[key]
 }
 
-// GetWithSource returns both the value and its source.
+// GetWithSource returns both the value, as from Get, and its source.
 func (c *Resolved) GetWithSource(key string) (string, Source) {
-	return c.values[key], c.sources[key]
+	return c.Get(key), c.sources[key]
 }
 
-// All returns a copy of all key-value pairs.
+// All returns a copy of all key-value pairs as configured, without
+// expanding references, so secrets are not copied out.
 func (c *Resolved) All() map[string]string {
 	result := make(map[string]string, len(c.values))
 	for k, v := range c.values {
@@ -173,50 +291,82 @@ func (c *Resolved) Keys() []string {
 }
 
 // Resolve builds the final config by merging all sources.
-// Priority (highest to lowest): flags > env > local > global > defaults.
+// Priority (highest to lowest): flags > env > profile > local > global >
+// defaults.
 func (r *Resolver) Resolve() *Resolved {
+	return r.resolve(r.Profile())
+}
+
+// resolve builds the config with the named profile applied
+func (r *Resolver) resolve(profile string) *Resolved {
 	cfg := &Resolved{
 		values:  make(map[string]string),
 		sources: make(map[string]Source),
+		origins: make(map[string]string),
+		secrets: r.config.Secrets,
+
+		overridden: make(map[string][]Setting),
+		redact:     r.config.RedactKeys,
+	}
+	if !r.config.TrustLocalReferences {
+		cfg.localFiles = r.localPaths
+		cfg.refuseLocal = true
 	}
 
 	// 1. Apply defaults (lowest priority)
 	r.applyDefaults(cfg)
 
 	// 2. Apply global config
-	r.applyGlobal(cfg)
+	profiles := &profileLayer{name: profile}
+	r.applyGlobal(cfg, profiles)
 
 	// 3. Apply local config
-	r.applyLocal(cfg)
+	r.applyLocal(cfg, profiles)
 
-	// 4. Apply environment variables (highest priority for now)
+	// 4. Apply the selected profile from either file
+	r.applyProfile(cfg, profiles)
+
+	// 5. Apply environment variables (highest priority for now)
 	r.applyEnv(cfg)
 
+	cfg.err = r.config.Schema.Validate(cfg)
 	return cfg
 }
 
-// ResolveWithFlags resolves config and applies flag overrides.
+// ResolveWithFlags resolves config and applies flag overrides. A "profile"
+// flag selects the profile, taking precedence over Resolver.Profile.
+// Watch re-applies the flags from the most recent call on every reload.
 func (r *Resolver) ResolveWithFlags(flags map[string]string) *Resolved {
-	cfg := r.Resolve()
+	r.mu.Lock()
+	r.flags = maps.Clone(flags)
+	if r.flags == nil {
+		r.flags = make(map[string]string)
+	}
+	r.mu.Unlock()
+
+	profile := flags["profile"]
+	if profile == "" {
+		profile = r.Profile()
+	}
+	cfg := r.resolve(profile)
 
 	for key, value := range flags {
 		if value != "" {
-			cfg.values[key] = value
-			cfg.sources[key] = SourceFlag
+			cfg.set(key, value, SourceFlag, "")
 		}
 	}
 
+	cfg.err = r.config.Schema.Validate(cfg)
 	return cfg
 }
 
 func (r *Resolver) applyDefaults(cfg *Resolved) {
 	for key, value := range r.config.Defaults {
-		cfg.values[key] = value
-		cfg.sources[key] = SourceDefault
+		cfg.set(key, value, SourceDefault, "")
 	}
 }
 
-func (r *Resolver) applyGlobal(cfg *Resolved) {
+func (r *Resolver) applyGlobal(cfg *Resolved, profiles *profileLayer) {
 	if r.globalPath == "" {
 		return
 	}
@@ -226,76 +376,82 @@ func (r *Resolver) applyGlobal(cfg *Resolved) {
 		return // File doesn't exist - not an error
 	}
 
-	var parsed map[string]interface{}
-	if err := yaml.Unmarshal(data, &parsed); err != nil {
+	parsed, err := parseConfigFile(r.globalPath, data)
+	if err != nil {
 		r.warn(fmt.Sprintf("could not parse %s: %v", r.globalPath, err))
 		return
 	}
 
-	for key, value := range parsed {
-		// Skip if not a valid global key (when validation is enabled)
-		if len(r.config.ValidGlobalKeys) > 0 && !contains(r.config.ValidGlobalKeys, key) {
+	for key, value := range r.fileSettings(r.globalPath, data, parsed) {
+		if profiles.take(key, value, r.globalPath, r.config.ValidGlobalKeys) {
 			continue
 		}
-		if strVal := toString(value); strVal != "" {
-			cfg.values[key] = strVal
-			cfg.sources[key] = SourceGlobal
+		// Skip if not a valid global key (when validation is enabled)
+		if len(r.config.ValidGlobalKeys) > 0 && !allowedKey(r.config.ValidGlobalKeys, key) {
+			continue
 		}
+		cfg.set(key, value, SourceGlobal, r.globalPath)
 	}
 }
 
-func (r *Resolver) applyLocal(cfg *Resolved) {
-	if r.localPath == "" {
-		return
+func (r *Resolver) applyLocal(cfg *Resolved, profiles *profileLayer) {
+	// Later files are closer to the working directory and override earlier ones
+	for _, path := range r.localPaths {
+		r.applyLocalFile(cfg, path, profiles)
 	}
+}
 
-	data, err := os.ReadFile(r.localPath)
+func (r *Resolver) applyLocalFile(cfg *Resolved, path string, profiles *profileLayer) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return
 	}
 
-	var parsed map[string]interface{}
-	if err := yaml.Unmarshal(data, &parsed); err != nil {
-		r.warn(fmt.Sprintf("could not parse %s: %v", r.localPath, err))
+	parsed, err := parseConfigFile(path, data)
+	if err != nil {
+		r.warn(fmt.Sprintf("could not parse %s: %v", path, err))
 		return
 	}
 
-	for key, value := range parsed {
-		// Skip if not a valid local key (when validation is enabled)
-		if len(r.config.ValidLocalKeys) > 0 && !contains(r.config.ValidLocalKeys, key) {
+	for key, value := range r.fileSettings(path, data, parsed) {
+		if profiles.take(key, value, path, r.config.ValidLocalKeys) {
 			continue
 		}
-		if strVal := toString(value); strVal != "" {
-			cfg.values[key] = strVal
-			cfg.sources[key] = SourceLocal
+		// Skip if not a valid local key (when validation is enabled)
+		if len(r.config.ValidLocalKeys) > 0 && !allowedKey(r.config.ValidLocalKeys, key) {
+			continue
 		}
+		cfg.set(key, value, SourceLocal, path)
 	}
 }
 
 func (r *Resolver) applyEnv(cfg *Resolved) {
+	r.applyDeprecatedEnv(cfg)
+
 	// Check environment for each known key (if prefix is set)
 	if r.config.EnvPrefix != "" {
 		allKeys := make(map[string]bool)
 		for k := range r.config.Defaults {
 			allKeys[k] = true
 		}
+		for _, k := range r.config.Aliases {
+			allKeys[k] = true
+		}
 		for k := range cfg.values {
 			allKeys[k] = true
 		}
 
 		for key := range allKeys {
-			envKey := r.config.EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+			envKey := r.config.EnvVar(key)
 			if value := os.Getenv(envKey); value != "" {
-				cfg.values[key] = value
-				cfg.sources[key] = SourceEnv
+				cfg.set(key, value, SourceEnv, envKey)
 			}
 		}
 	}
 
 	// Also check standard NO_COLOR env var (always, regardless of prefix)
 	if _, hasNoColor := os.LookupEnv("NO_COLOR"); hasNoColor {
-		cfg.values["no_color"] = "true"
-		cfg.sources["no_color"] = SourceEnv
+		cfg.set("no_color", "true", SourceEnv, "NO_COLOR")
 	}
 }
 
@@ -309,22 +465,20 @@ func (r *Resolver) GlobalPath() string {
 	return r.globalPath
 }
 
-// LocalPath returns the path to the local config file.
+// LocalPath returns the path to the local config file in the git root.
 func (r *Resolver) LocalPath() string {
 	return r.localPath
 }
 
-// Helper functions
-
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
-	}
-	return false
+// LocalPaths returns every local config file merged, from the git root
+// down to the working directory. Only the git root file is included
+// unless MergeLocalConfigs is set.
+func (r *Resolver) LocalPaths() []string {
+	return append([]string(nil), r.localPaths...)
 }
 
+// Helper functions
+
 func toString(v interface{}) string {
 	switch val := v.(type) {
 	case string:
@@ -336,11 +490,59 @@ func toString(v interface{}) string {
 		return "false"
 	case int, int64, float64:
 		return fmt.Sprintf("%v", val)
+	case json.Number:
+		return val.String()
+	case encoding.TextMarshaler:
+		// Dates and times, e.g. from TOML
+		text, err := val.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(text)
+	case []interface{}:
+		// Lists are comma-separated, as Resolved.Unmarshal decodes slices
+		items := make([]string, 0, len(val))
+		for _, item := range val {
+			if s := toString(item); s != "" {
+				items = append(items, s)
+			}
+		}
+		return strings.Join(items, ",")
 	default:
 		return ""
 	}
 }
 
+// findLocalConfigs returns the config files named name in the directories
+// below root on the way to startDir, root side first. root itself is not
+// included.
+func findLocalConfigs(root, startDir, name string) []string {
+	dir, err := filepath.Abs(startDir)
+	if err != nil || root == "" {
+		return nil
+	}
+	if root, err = filepath.Abs(root); err != nil {
+		return nil
+	}
+	if rel, err := filepath.Rel(root, dir); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return nil // At the root or outside it
+	}
+
+	var paths []string
+	for dir != root {
+		if path := findConfigFile(filepath.Join(dir, name)); fileExists(path) {
+			paths = append(paths, path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	slices.Reverse(paths)
+	return paths
+}
+
 // findGitRoot finds the git root by looking for .git directory.
 func findGitRoot(startDir string) string {
 	dir, err := filepath.Abs(startDir)
@@ -2,9 +2,10 @@
 //
 // This package supports layered configuration with clear precedence:
 //  1. Environment variables (highest priority)
-//  2. Local config (e.g., .myapp.yaml in git root)
-//  3. Global config (e.g., ~/.config/myapp/config.yaml)
-//  4. Built-in defaults (lowest priority)
+//  2. Selected profile (profiles.<name> in either config file)
+//  3. Local config (e.g., .myapp.yaml in git root)
+//  4. Global config (e.g., ~/.config/myapp/config.yaml)
+//  5. Built-in defaults (lowest priority)
 //
 // # Basic Usage
 //
@@ -24,6 +25,13 @@
 //	fmt.Println(cfg.Get("api_url"))        // "http://localhost:8080"
 //	fmt.Println(cfg.Source("api_url"))     // "default"
 //
+// # File Formats
+//
+// Config files are YAML, TOML, or JSON, chosen by extension. If the
+// configured file (config.yaml, .myapp.yaml) does not exist, the same name
+// with a .yml, .toml, or .json extension is used instead, and SaveGlobal
+// and SaveLocal write back in that file's format.
+//
 // # Environment Variables
 //
 // Environment variables are automatically detected using the configured prefix:
@@ -38,9 +46,105 @@
 //   - "default": Built-in default value
 //   - "global": ~/.config/<app>/config.yaml
 //   - "local": .myapp.yaml in git root
+//   - "profile:<name>": Selected profile (see ProfileSource)
 //   - "env": Environment variable
 //   - "flag": Command-line flag (set via SetFlagValue)
 //
+// # Typed Binding
+//
+// Unmarshal decodes resolved values into a struct with `config` tags,
+// converting bools, numbers, durations, and comma-separated lists:
+//
+//	var cfg struct {
+//	    APIURL    string        `config:"api_url"`
+//	    Timeout   time.Duration `config:"timeout"`
+//	    APISource config.Source `config:"api_url,source"`
+//	}
+//	err := resolver.Unmarshal(&cfg)
+//
+// Conversion failures are reported together as *FieldError values naming
+// the key and the layer that supplied the bad value.
+//
+// # Validation
+//
+// Set ResolverConfig.Schema to check values during Resolve. Every problem
+// is collected, with the layer and file that supplied the bad value:
+//
+//	schema := config.NewSchema().Required("api_url").OneOf("format", "table", "json")
+//	cfg := resolver.Resolve()
+//	if err := cfg.Err(); err != nil {
+//	    return err // *ValidationError
+//	}
+//
+// # Monorepos
+//
+// Set ResolverConfig.MergeLocalConfigs to also read local config files in
+// the directories between the git root and the working directory. Closer
+// files override farther ones, so a subproject only lists what differs.
+//
+// # Renamed Keys
+//
+// ResolverConfig.Aliases maps deprecated keys to their replacements, so old
+// config files and env vars keep working. Each use warns with the file and
+// line to update:
+//
+//	Aliases: map[string]string{"server_url": "api_url"},
+//	// Warning: /repo/.myapp.yaml:3: "server_url" is deprecated, use "api_url"
+//
+// # Profiles
+//
+// A profiles section holds named overrides, selected with ResolverConfig.Profile,
+// a "profile" flag, or MYAPP_PROFILE:
+//
+//	// profiles:
+//	//   staging:
+//	//     api_url: https://staging.example.com
+//	cfg := resolver.ResolveWithFlags(map[string]string{"profile": "staging"})
+//	cfg.Source("api_url") // "profile:staging"
+//
+// # Nested Keys
+//
+// Nested maps and lists in config files are flattened into dotted keys,
+// such as "models.review" and "notify.hooks[0].url". Lists of scalars are
+// kept as one comma-separated value:
+//
+//	model := cfg.Get("models.review")     // or MYAPP_MODELS_REVIEW
+//	channels := cfg.GetList("notify.channels")
+//	models := cfg.GetMap("models")        // {"review": ..., "plan": ...}
+//
+// # Secrets
+//
+// Values may reference secrets instead of containing them. References are
+// resolved at access time, using ResolverConfig.Secrets for ${secret:...}:
+//
+//	// jira_token: ${secret:vault/jira#token}
+//	// github_token: ${env:GITHUB_TOKEN}
+//	// db_password: ${file:/run/secrets/db_password}
+//	token, err := cfg.Value("jira_token")
+//
+// All and Raw return the references unexpanded. References in local config
+// files, which repositories commit, return ErrUntrustedReference unless
+// ResolverConfig.TrustLocalReferences is set.
+//
+// # Explaining Values
+//
+// Explain lists every key with its source and the lower-layer values it
+// overrode, optionally redacting secrets, for support dumps:
+//
+//	for _, e := range resolver.Explain(true) {
+//	    fmt.Println(e) // api_url = "http://env" (env MYAPP_API_URL), overrides ...
+//	}
+//
+// Diff compares two resolutions key by key.
+//
+// # Hot Reload
+//
+// Watch polls the global and local files and re-resolves when they change,
+// passing subscribers the changed keys with old and new values and sources:
+//
+//	resolver.Subscribe(func(u config.Update) { apply(u.Config) })
+//	go resolver.Watch(ctx)
+//
 // # Git Root Detection
 //
 // By default, the resolver looks for the local config in the git repository root.
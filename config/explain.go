@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Redacted replaces sensitive values in redacted explanations.
+const Redacted = "[redacted]"
+
+// sensitiveWords mark a key as secret when they appear in its last part
+var sensitiveWords = []string{"token", "secret", "password", "passwd", "credential", "private_key", "api_key", "apikey"}
+
+// Setting is one layer's value for a key.
+type Setting struct {
+	Value  string // As configured; secret references are not resolved
+	Source Source
+	Origin string // File or environment variable, if any
+}
+
+// String formats the setting as `"value" (source origin)`.
+func (s Setting) String() string {
+	from := string(s.Source)
+	if s.Origin != "" {
+		from += " " + s.Origin
+	}
+	return fmt.Sprintf("%q (%s)", s.Value, from)
+}
+
+// Explanation describes how a key got its effective value.
+type Explanation struct {
+	Key string
+	Setting
+	Overridden []Setting // Values from lower layers, highest first
+}
+
+// String formats the explanation on one line, e.g.
+//
+//	api_url = "http://local" (local /repo/.myapp.yaml), overrides "http://global" (global ...)
+func (e Explanation) String() string {
+	s := e.Key + " = " + e.Setting.String()
+	if len(e.Overridden) > 0 {
+		lower := make([]string, len(e.Overridden))
+		for i, o := range e.Overridden {
+			lower[i] = o.String()
+		}
+		s += ", overrides " + strings.Join(lower, ", ")
+	}
+	return s
+}
+
+// Explain resolves the configuration and explains every key. See
+// Resolved.Explain.
+func (r *Resolver) Explain(redact bool) []Explanation {
+	return r.Resolve().Explain(redact)
+}
+
+// Explain returns every key, sorted, with its effective value and source
+// and the values it overrode from lower layers. Values are shown as
+// configured, so ${secret:...} references appear as written.
+//
+// With redact set, values of keys named like secrets (api_token,
+// db.password, ...) or listed in ResolverConfig.RedactKeys are replaced
+// with Redacted, unless they are references, which are safe to show.
+func (c *Resolved) Explain(redact bool) []Explanation {
+	keys := c.Keys()
+	slices.Sort(keys)
+
+	result := make([]Explanation, 0, len(keys))
+	for _, key := range keys {
+		e := Explanation{
+			Key:        key,
+			Setting:    Setting{Value: c.values[key], Source: c.sources[key], Origin: c.origins[key]},
+			Overridden: slices.Clone(c.overridden[key]),
+		}
+		if redact && c.isSensitive(key) {
+			e.Value = redactValue(e.Value)
+			for i := range e.Overridden {
+				e.Overridden[i].Value = redactValue(e.Overridden[i].Value)
+			}
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// isSensitive reports whether key's value should be redacted
+func (c *Resolved) isSensitive(key string) bool {
+	if allowedKey(c.redact, key) {
+		return true
+	}
+	name := strings.ToLower(key[strings.LastIndexAny(key, ".]")+1:])
+	name = strings.ReplaceAll(name, "-", "_")
+	for _, word := range sensitiveWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue hides a value unless it only refers to a secret
+func redactValue(value string) string {
+	if value == "" || secretRefPattern.ReplaceAllString(value, "") == "" {
+		return value
+	}
+	return Redacted
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResolved_Explain(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalPath := filepath.Join(tmpDir, "config.yaml")
+	localPath := filepath.Join(tmpDir, ".myapp.yaml")
+	os.WriteFile(globalPath, []byte("api_url: http://global\njira_token: ${secret:vault/jira#token}\n"), 0644)
+	os.WriteFile(localPath, []byte("api_url: http://local\ngithub_token: ghp_abc\nwebhook: http://hook\n"), 0644)
+	t.Setenv("EXPLAINTEST_API_URL", "http://env")
+
+	resolver := NewResolverWithPaths(ResolverConfig{
+		EnvPrefix:  "EXPLAINTEST_",
+		Defaults:   map[string]string{"api_url": "http://default"},
+		RedactKeys: []string{"webhook"},
+	}, globalPath, localPath)
+
+	explained := resolver.Explain(false)
+	var keys []string
+	for _, e := range explained {
+		keys = append(keys, e.Key)
+	}
+	if want := []string{"api_url", "github_token", "jira_token", "webhook"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("keys = %v, want %v", keys, want)
+	}
+
+	apiURL := explained[0]
+	want := Explanation{
+		Key:     "api_url",
+		Setting: Setting{Value: "http://env", Source: SourceEnv, Origin: "EXPLAINTEST_API_URL"},
+		Overridden: []Setting{
+			{Value: "http://local", Source: SourceLocal, Origin: localPath},
+			{Value: "http://global", Source: SourceGlobal, Origin: globalPath},
+			{Value: "http://default", Source: SourceDefault},
+		},
+	}
+	if !reflect.DeepEqual(apiURL, want) {
+		t.Errorf("api_url = %+v, want %+v", apiURL, want)
+	}
+	wantLine := `api_url = "http://env" (env EXPLAINTEST_API_URL), overrides "http://local" (local ` + localPath +
+		`), "http://global" (global ` + globalPath + `), "http://default" (default)`
+	if got := apiURL.String(); got != wantLine {
+		t.Errorf("String() = %s\nwant %s", got, wantLine)
+	}
+	if got := explained[1].Value; got != "ghp_abc" {
+		t.Errorf("unredacted github_token = %q", got)
+	}
+
+	redacted := resolver.Explain(true)
+	tests := map[string]string{
+		"api_url":      "http://env",
+		"github_token": Redacted,
+		"jira_token":   "${secret:vault/jira#token}", // References are safe to show
+		"webhook":      Redacted,
+	}
+	for _, e := range redacted {
+		if e.Value != tests[e.Key] {
+			t.Errorf("redacted %s = %q, want %q", e.Key, e.Value, tests[e.Key])
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := &Resolved{
+		values:  map[string]string{"a": "1", "b": "2", "c": "3"},
+		sources: map[string]Source{"a": SourceGlobal, "b": SourceGlobal, "c": SourceGlobal},
+	}
+	next := &Resolved{
+		values:  map[string]string{"a": "1", "b": "2", "d": "4"},
+		sources: map[string]Source{"a": SourceGlobal, "b": SourceLocal, "d": SourceEnv},
+	}
+
+	want := []Change{
+		{Key: "b", OldValue: "2", NewValue: "2", OldSource: SourceGlobal, NewSource: SourceLocal},
+		{Key: "c", OldValue: "3", OldSource: SourceGlobal},
+		{Key: "d", NewValue: "4", NewSource: SourceEnv},
+	}
+	if got := Diff(old, next); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// configExtensions are tried, in order, when the configured file does not
+// exist
+var configExtensions = []string{".yaml", ".yml", ".toml", ".json"}
+
+// findConfigFile returns path if it exists, or else the first existing
+// file with the same name and another extension from configExtensions.
+// It returns path unchanged if none exist.
+func findConfigFile(path string) string {
+	if path == "" || fileExists(path) {
+		return path
+	}
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, ext := range configExtensions {
+		if candidate := base + ext; fileExists(candidate) {
+			return candidate
+		}
+	}
+	return path
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// parseConfigFile decodes a config file by its extension: .toml and .json
+// are parsed as such, anything else as YAML.
+func parseConfigFile(path string, data []byte) (map[string]interface{}, error) {
+	var parsed map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return parseTOML(data)
+	case ".json":
+		if len(bytes.TrimSpace(data)) == 0 {
+			return nil, nil
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber() // Keep 1000000 from becoming 1e+06
+		if err := dec.Decode(&parsed); err != nil {
+			return nil, err
+		}
+	default:
+		if err := yaml.Unmarshal(data, &parsed); err != nil {
+			return nil, err
+		}
+	}
+	return parsed, nil
+}
+
+// marshalConfigFile encodes values in the format of path's extension.
+func marshalConfigFile(path string, values map[string]interface{}) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return toml.Marshal(values)
+	case ".json":
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	default:
+		return yaml.Marshal(values)
+	}
+}
+
+// parseTOML decodes a TOML document. Integers decode as int64; dates and
+// times as time.Time or the toml.Local* types.
+func parseTOML(data []byte) (map[string]interface{}, error) {
+	var parsed map[string]interface{}
+	if err := toml.Unmarshal(data, &parsed); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			line, _ := decodeErr.Position()
+			return nil, fmt.Errorf("toml: line %d: %s", line, strings.TrimPrefix(decodeErr.Error(), "toml: "))
+		}
+		return nil, err
+	}
+	return parsed, nil
+}
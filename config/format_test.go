@@ -0,0 +1,180 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+func TestParseTOML(t *testing.T) {
+	data := `# Global settings
+api_url = "http://toml-server" # trailing comment
+retries = 3
+big = 1_000_000
+hex = 0xff
+ratio = 0.5
+enabled = true
+date = 2024-05-27
+literal = 'C:\path'
+quoted."dotted.key" = "x"
+tags = [
+  "a",
+  "b", # comment
+]
+multi = """
+line one
+line two"""
+
+[models]
+review = "opus"
+inline = { name = "sonnet", tokens = 100 }
+
+[[notify.hooks]]
+url = "https://a"
+
+[[notify.hooks]]
+url = "https://b"
+`
+	got, err := parseTOML([]byte(data))
+	if err != nil {
+		t.Fatalf("parseTOML() error = %v", err)
+	}
+
+	want := map[string]any{
+		"api_url": "http://toml-server",
+		"retries": int64(3),
+		"big":     int64(1000000),
+		"hex":     int64(255),
+		"ratio":   0.5,
+		"enabled": true,
+		"date":    toml.LocalDate{Year: 2024, Month: 5, Day: 27},
+		"literal": `C:\path`,
+		"quoted":  map[string]any{"dotted.key": "x"},
+		"tags":    []any{"a", "b"},
+		"multi":   "line one\nline two",
+		"models": map[string]any{
+			"review": "opus",
+			"inline": map[string]any{"name": "sonnet", "tokens": int64(100)},
+		},
+		"notify": map[string]any{
+			"hooks": []any{map[string]any{"url": "https://a"}, map[string]any{"url": "https://b"}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTOML() =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestParseTOML_Errors(t *testing.T) {
+	tests := map[string]string{
+		"missing equals":  "key \"value\"",
+		"unterminated":    "key = \"value",
+		"duplicate key":   "a = 1\na = 2",
+		"bad value":       "a = nope",
+		"trailing junk":   "a = 1 2",
+		"table over key":  "a = 1\n[a]",
+		"unclosed header": "[a",
+		"empty array":     "a = []\n[a.b]",
+		"extend array":    "a = []\n[[a.b]]",
+		"array as tables": "a = [1, 2]\n[[a]]",
+		"inline tables":   "a = [{b = 1}]\n[[a]]",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseTOML([]byte(data))
+			if err == nil || !strings.HasPrefix(err.Error(), "toml: ") {
+				t.Errorf("parseTOML(%q) error = %v, want toml error", data, err)
+			}
+		})
+	}
+}
+
+func TestParseTOML_SyntaxErrorLine(t *testing.T) {
+	_, err := parseTOML([]byte("a = 1\nb = \"unterminated\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "toml: line 2: ") {
+		t.Errorf("parseTOML() error = %v, want line 2", err)
+	}
+}
+
+func TestMarshalConfigFile_TOMLRoundTrip(t *testing.T) {
+	values := map[string]any{
+		"api_url": "http://x\n\"quoted\"",
+		"retries": int64(3),
+		"enabled": false,
+		"tags":    []any{"a", "b"},
+		"models":  map[string]any{"review": "opus", "my key": "v"},
+		"hooks":   []any{map[string]any{"url": "https://a"}},
+	}
+	data, err := marshalConfigFile("config.toml", values)
+	if err != nil {
+		t.Fatalf("marshalConfigFile() error = %v", err)
+	}
+	got, err := parseTOML(data)
+	if err != nil {
+		t.Fatalf("parseTOML() error = %v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(got, values) {
+		t.Errorf("round trip =\n%#v\nwant\n%#v\n%s", got, values, data)
+	}
+}
+
+func TestResolver_TOMLAndJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalPath := filepath.Join(tmpDir, "config.toml")
+	localPath := filepath.Join(tmpDir, ".myapp.json")
+	os.WriteFile(globalPath, []byte("api_url = \"http://global\"\nformat = \"table\"\nsince = 2024-05-27\n[models]\nreview = \"opus\"\n"), 0644)
+	os.WriteFile(localPath, []byte(`{"format": "json", "limit": 1000000, "tags": ["a", "b"]}`), 0644)
+
+	cfg := NewResolverWithPaths(ResolverConfig{}, globalPath, localPath).Resolve()
+
+	tests := []struct {
+		key    string
+		want   string
+		source Source
+	}{
+		{"api_url", "http://global", SourceGlobal},
+		{"models.review", "opus", SourceGlobal},
+		{"since", "2024-05-27", SourceGlobal},
+		{"format", "json", SourceLocal},
+		{"limit", "1000000", SourceLocal},
+		{"tags", "a,b", SourceLocal},
+	}
+	for _, tt := range tests {
+		value, source := cfg.GetWithSource(tt.key)
+		if value != tt.want || source != tt.source {
+			t.Errorf("%s = %q (%s), want %q (%s)", tt.key, value, source, tt.want, tt.source)
+		}
+	}
+}
+
+func TestResolver_DetectsConfigExtension(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	configDir := filepath.Join(tmpHome, ".config", "testapp")
+	os.MkdirAll(configDir, 0755)
+	os.WriteFile(filepath.Join(configDir, "config.toml"), []byte("api_url = \"http://toml\"\n"), 0644)
+
+	resolver := NewResolver(ResolverConfig{GlobalConfigDir: "testapp"})
+	if got := resolver.GlobalPath(); filepath.Base(got) != "config.toml" {
+		t.Errorf("GlobalPath() = %q, want config.toml", got)
+	}
+	if got := resolver.Resolve().Get("api_url"); got != "http://toml" {
+		t.Errorf("api_url = %q, want http://toml", got)
+	}
+
+	// SaveGlobal keeps the existing file's format
+	if err := (SaveConfig{GlobalConfigDir: "testapp"}).SaveGlobal("models.review", "opus"); err != nil {
+		t.Fatalf("SaveGlobal() error = %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(configDir, "config.toml"))
+	if want := "api_url = 'http://toml'\n\n[models]\nreview = 'opus'\n"; string(data) != want {
+		t.Errorf("config.toml = %q, want %q", data, want)
+	}
+	if _, err := os.Stat(filepath.Join(configDir, "config.yaml")); !os.IsNotExist(err) {
+		t.Errorf("config.yaml created alongside config.toml")
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResolver_MergeLocalConfigs(t *testing.T) {
+	root := t.TempDir()
+	service := filepath.Join(root, "services", "api")
+	os.MkdirAll(filepath.Join(root, ".git"), 0755)
+	os.MkdirAll(service, 0755)
+
+	rootConfig := filepath.Join(root, ".myapp.yaml")
+	servicesConfig := filepath.Join(root, "services", ".myapp.yaml")
+	apiConfig := filepath.Join(service, ".myapp.toml")
+	os.WriteFile(rootConfig, []byte("flow: default\nmodel: opus\nformat: table\n"), 0644)
+	os.WriteFile(servicesConfig, []byte("model: sonnet\n"), 0644)
+	os.WriteFile(apiConfig, []byte("flow = \"api\"\n"), 0644)
+	t.Chdir(service)
+
+	resolver := NewResolver(ResolverConfig{LocalConfigName: ".myapp.yaml", MergeLocalConfigs: true})
+
+	if got, want := resolver.LocalPaths(), []string{rootConfig, servicesConfig, apiConfig}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("LocalPaths() = %v, want %v", got, want)
+	}
+	if got := resolver.LocalPath(); got != rootConfig {
+		t.Errorf("LocalPath() = %q, want %q", got, rootConfig)
+	}
+
+	cfg := resolver.Resolve()
+	tests := map[string]string{
+		"flow":   apiConfig,
+		"model":  servicesConfig,
+		"format": rootConfig,
+	}
+	wantValues := map[string]string{"flow": "api", "model": "sonnet", "format": "table"}
+	for key, origin := range tests {
+		if got := cfg.Get(key); got != wantValues[key] {
+			t.Errorf("%s = %q, want %q", key, got, wantValues[key])
+		}
+		if cfg.Source(key) != SourceLocal || cfg.origins[key] != origin {
+			t.Errorf("%s from %s %s, want local %s", key, cfg.Source(key), cfg.origins[key], origin)
+		}
+	}
+
+	// Without the option only the git root file is read
+	resolver = NewResolver(ResolverConfig{LocalConfigName: ".myapp.yaml"})
+	if got := resolver.Resolve().Get("flow"); got != "default" {
+		t.Errorf("flow without merging = %q, want default", got)
+	}
+}
+
+func TestFindLocalConfigs_AtRoot(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, ".myapp.yaml"), []byte("a: 1\n"), 0644)
+
+	if got := findLocalConfigs(root, root, ".myapp.yaml"); got != nil {
+		t.Errorf("findLocalConfigs() at root = %v, want nil", got)
+	}
+	if got := findLocalConfigs(filepath.Join(root, "sub"), root, ".myapp.yaml"); got != nil {
+		t.Errorf("findLocalConfigs() outside root = %v, want nil", got)
+	}
+}
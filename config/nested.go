@@ -0,0 +1,149 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Nested YAML is flattened into dotted keys when files are read:
+//
+//	models:
+//	  review: opus          # models.review
+//	notify:
+//	  channels:
+//	    - slack             # notify.channels = "slack,email"
+//	    - email
+//	  hooks:
+//	    - url: https://a    # notify.hooks[0].url
+//
+// Lists of scalars become one comma-separated value, as Unmarshal decodes
+// slices. Lists holding maps or lists are indexed instead.
+
+// flatten calls set for every scalar under value, keyed by its dotted path
+func flatten(key string, value any, set func(key, value string)) {
+	switch val := value.(type) {
+	case map[string]any:
+		for k, v := range val {
+			flatten(joinKey(key, k), v, set)
+		}
+	case map[any]any:
+		for k, v := range val {
+			flatten(joinKey(key, fmt.Sprint(k)), v, set)
+		}
+	case []any:
+		if !hasNested(val) {
+			if s := toString(val); s != "" {
+				set(key, s)
+			}
+			return
+		}
+		for i, item := range val {
+			flatten(fmt.Sprintf("%s[%d]", key, i), item, set)
+		}
+	default:
+		if s := toString(val); s != "" {
+			set(key, s)
+		}
+	}
+}
+
+// joinKey appends a map key to a dotted path
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// hasNested reports whether a list holds maps or lists
+func hasNested(list []any) bool {
+	for _, item := range list {
+		switch item.(type) {
+		case map[string]any, map[any]any, []any:
+			return true
+		}
+	}
+	return false
+}
+
+// allowedKey reports whether key is in valid, or nested under a key in
+// valid ("models" allows "models.review")
+func allowedKey(valid []string, key string) bool {
+	for _, v := range valid {
+		if key == v || strings.HasPrefix(key, v+".") || strings.HasPrefix(key, v+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvVar returns the environment variable that overrides key. Dots,
+// dashes, and list indexes become underscores: with EnvPrefix "MYAPP_",
+// "models.review" maps to MYAPP_MODELS_REVIEW and "notify.hooks[0].url" to
+// MYAPP_NOTIFY_HOOKS_0_URL.
+func (c ResolverConfig) EnvVar(key string) string {
+	name := strings.NewReplacer("-", "_", ".", "_", "[", "_", "]", "").Replace(key)
+	return c.EnvPrefix + strings.ToUpper(name)
+}
+
+// GetList returns a list value, such as a YAML list or a comma-separated
+// string, as its items. Items are trimmed and empty items dropped.
+func (c *Resolved) GetList(key string) []string {
+	value := c.Get(key)
+	if value == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// GetMap returns the values nested under prefix, keyed by their path
+// below it. For "models" it returns {"review": ..., "plan": ...} from
+// models.review and models.plan. Values are expanded as by Get.
+func (c *Resolved) GetMap(prefix string) map[string]string {
+	result := make(map[string]string)
+	for key := range c.values {
+		if sub, ok := strings.CutPrefix(key, prefix+"."); ok {
+			result[sub] = c.Get(key)
+		}
+	}
+	return result
+}
+
+// Len returns the number of items in an indexed list, such as 2 for
+// notify.hooks[0] and notify.hooks[1].
+func (c *Resolved) Len(key string) int {
+	n := 0
+	for c.hasPrefix(fmt.Sprintf("%s[%d]", key, n)) {
+		n++
+	}
+	return n
+}
+
+// hasPrefix reports whether key or a key nested under it is set
+func (c *Resolved) hasPrefix(key string) bool {
+	for k := range c.values {
+		if k == key || strings.HasPrefix(k, key+".") || strings.HasPrefix(k, key+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// subKeys returns the keys nested under prefix, sorted
+func (c *Resolved) subKeys(prefix string) []string {
+	var keys []string
+	for key := range c.values {
+		if strings.HasPrefix(key, prefix+".") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const nestedYAML = `models:
+  review: opus
+  plan: sonnet
+notify:
+  channels:
+    - slack
+    - email
+  hooks:
+    - url: https://a.example.com
+      events: [merged]
+    - url: https://b.example.com
+`
+
+func TestResolver_NestedKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalPath := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(globalPath, []byte(nestedYAML), 0644)
+	t.Setenv("NESTEDTEST_MODELS_REVIEW", "haiku")
+	t.Setenv("NESTEDTEST_NOTIFY_HOOKS_1_URL", "https://c.example.com")
+
+	resolver := NewResolverWithPaths(ResolverConfig{
+		EnvPrefix: "NESTEDTEST_",
+		Defaults:  map[string]string{"models.fallback": "sonnet"},
+	}, globalPath, "")
+	cfg := resolver.Resolve()
+
+	tests := []struct {
+		key    string
+		want   string
+		source Source
+	}{
+		{"models.review", "haiku", SourceEnv},
+		{"models.plan", "sonnet", SourceGlobal},
+		{"models.fallback", "sonnet", SourceDefault},
+		{"notify.channels", "slack,email", SourceGlobal},
+		{"notify.hooks[0].url", "https://a.example.com", SourceGlobal},
+		{"notify.hooks[0].events", "merged", SourceGlobal},
+		{"notify.hooks[1].url", "https://c.example.com", SourceEnv},
+	}
+	for _, tt := range tests {
+		value, source := cfg.GetWithSource(tt.key)
+		if value != tt.want || source != tt.source {
+			t.Errorf("%s = %q (%s), want %q (%s)", tt.key, value, source, tt.want, tt.source)
+		}
+	}
+
+	if got := cfg.GetList("notify.channels"); !reflect.DeepEqual(got, []string{"slack", "email"}) {
+		t.Errorf("GetList(notify.channels) = %v", got)
+	}
+	want := map[string]string{"review": "haiku", "plan": "sonnet", "fallback": "sonnet"}
+	if got := cfg.GetMap("models"); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMap(models) = %v, want %v", got, want)
+	}
+	if got := cfg.Len("notify.hooks"); got != 2 {
+		t.Errorf("Len(notify.hooks) = %d, want 2", got)
+	}
+}
+
+func TestResolverConfig_EnvVar(t *testing.T) {
+	cfg := ResolverConfig{EnvPrefix: "MYAPP_"}
+	tests := map[string]string{
+		"api_url":             "MYAPP_API_URL",
+		"no-color":            "MYAPP_NO_COLOR",
+		"models.review":       "MYAPP_MODELS_REVIEW",
+		"notify.hooks[0].url": "MYAPP_NOTIFY_HOOKS_0_URL",
+	}
+	for key, want := range tests {
+		if got := cfg.EnvVar(key); got != want {
+			t.Errorf("EnvVar(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestResolver_NestedValidKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalPath := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(globalPath, []byte(nestedYAML), 0644)
+
+	cfg := NewResolverWithPaths(ResolverConfig{
+		ValidGlobalKeys: []string{"models"},
+	}, globalPath, "").Resolve()
+
+	if got := cfg.Get("models.review"); got != "opus" {
+		t.Errorf("models.review = %q, want opus", got)
+	}
+	if got := cfg.Get("notify.channels"); got != "" {
+		t.Errorf("notify.channels = %q, want filtered out", got)
+	}
+}
+
+func TestResolved_UnmarshalNested(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalPath := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(globalPath, []byte(nestedYAML), 0644)
+
+	type hook struct {
+		URL    string   `config:"url"`
+		Events []string `config:"events"`
+	}
+	var out struct {
+		Models map[string]string `config:"models"`
+		Notify struct {
+			Channels []string `config:"channels"`
+			Hooks    []hook   `config:"hooks"`
+		} `config:"notify"`
+	}
+	if err := NewResolverWithPaths(ResolverConfig{}, globalPath, "").Unmarshal(&out); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(out.Models, map[string]string{"review": "opus", "plan": "sonnet"}) {
+		t.Errorf("Models = %v", out.Models)
+	}
+	if !reflect.DeepEqual(out.Notify.Channels, []string{"slack", "email"}) {
+		t.Errorf("Channels = %v", out.Notify.Channels)
+	}
+	wantHooks := []hook{{URL: "https://a.example.com", Events: []string{"merged"}}, {URL: "https://b.example.com"}}
+	if !reflect.DeepEqual(out.Notify.Hooks, wantHooks) {
+		t.Errorf("Hooks = %+v, want %+v", out.Notify.Hooks, wantHooks)
+	}
+}
+
+func TestSetNested(t *testing.T) {
+	m := map[string]interface{}{"legacy.key": "a"}
+	setNested(m, "models.review", "opus")
+	setNested(m, "models.plan", "sonnet")
+	setNested(m, "legacy.key", "b")
+
+	models, ok := m["models"].(map[string]interface{})
+	if !ok || models["review"] != "opus" || models["plan"] != "sonnet" {
+		t.Errorf("models = %v", m["models"])
+	}
+	if m["legacy.key"] != "b" {
+		t.Errorf("legacy.key = %v, want updated in place", m["legacy.key"])
+	}
+
+	deleteNested(m, "models.review")
+	deleteNested(m, "models.plan")
+	if _, ok := m["models"]; ok {
+		t.Errorf("models = %v, want removed when empty", m["models"])
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// profilesKey is the section of a config file holding named profiles:
+//
+//	api_url: https://api.example.com
+//	profiles:
+//	  staging:
+//	    api_url: https://staging.example.com
+const profilesKey = "profiles"
+
+// ProfileSource returns the Source of values from the named profile, e.g.
+// "profile:staging".
+func ProfileSource(name string) Source {
+	return Source("profile:" + name)
+}
+
+// Profile returns the profile Resolve applies: ResolverConfig.Profile if
+// set, or else the environment variable for the "profile" key
+// (MYAPP_PROFILE with EnvPrefix "MYAPP_"). It returns "" for no profile.
+func (r *Resolver) Profile() string {
+	if r.config.Profile != "" {
+		return r.config.Profile
+	}
+	if r.config.EnvPrefix != "" {
+		return os.Getenv(r.config.EnvVar("profile"))
+	}
+	return ""
+}
+
+// Profile returns the name of the profile applied, or "" if none was.
+func (c *Resolved) Profile() string {
+	return c.profile
+}
+
+// profileLayer collects the active profile's values from the config files
+// so they can be applied above both files
+type profileLayer struct {
+	name     string
+	settings []profileSetting
+	found    bool
+}
+
+type profileSetting struct {
+	key, value, origin string
+}
+
+// take reports whether key belongs to a profile, keeping it if it belongs
+// to the active one and is allowed by valid
+func (p *profileLayer) take(key, value, origin string, valid []string) bool {
+	rest, ok := strings.CutPrefix(key, profilesKey+".")
+	if !ok {
+		return false
+	}
+	name, sub, ok := strings.Cut(rest, ".")
+	if !ok || name != p.name {
+		return true
+	}
+	p.found = true
+	if len(valid) == 0 || allowedKey(valid, sub) {
+		p.settings = append(p.settings, profileSetting{sub, value, origin})
+	}
+	return true
+}
+
+// applyProfile sets the collected profile values, local after global
+func (r *Resolver) applyProfile(cfg *Resolved, p *profileLayer) {
+	if p.name == "" {
+		return
+	}
+	if !p.found {
+		r.warn(fmt.Sprintf("profile %q not found in config files", p.name))
+		return
+	}
+	cfg.profile = p.name
+	for _, s := range p.settings {
+		cfg.set(s.key, s.value, ProfileSource(p.name), s.origin)
+	}
+}
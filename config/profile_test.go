@@ -0,0 +1,109 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeProfileConfigs(t *testing.T) (globalPath, localPath string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	globalPath = filepath.Join(tmpDir, "config.yaml")
+	localPath = filepath.Join(tmpDir, ".myapp.yaml")
+	os.WriteFile(globalPath, []byte(`api_url: http://global
+format: table
+timeout: 10s
+profiles:
+  staging:
+    api_url: http://staging
+    timeout: 30s
+  prod:
+    api_url: http://prod
+`), 0644)
+	os.WriteFile(localPath, []byte(`timeout: 5s
+profiles:
+  staging:
+    format: json
+`), 0644)
+	return globalPath, localPath
+}
+
+func TestResolver_Profile(t *testing.T) {
+	globalPath, localPath := writeProfileConfigs(t)
+	t.Setenv("PROFILETEST_TIMEOUT", "1m")
+
+	resolver := NewResolverWithPaths(ResolverConfig{
+		EnvPrefix: "PROFILETEST_",
+		Profile:   "staging",
+		Defaults:  map[string]string{"timeout": "1s"},
+	}, globalPath, localPath)
+	cfg := resolver.Resolve()
+
+	if cfg.Profile() != "staging" {
+		t.Errorf("Profile() = %q, want staging", cfg.Profile())
+	}
+
+	tests := []struct {
+		key    string
+		want   string
+		source Source
+	}{
+		{"api_url", "http://staging", "profile:staging"},
+		{"format", "json", ProfileSource("staging")},
+		{"timeout", "1m", SourceEnv}, // Env beats profile
+	}
+	for _, tt := range tests {
+		value, source := cfg.GetWithSource(tt.key)
+		if value != tt.want || source != tt.source {
+			t.Errorf("%s = %q (%s), want %q (%s)", tt.key, value, source, tt.want, tt.source)
+		}
+	}
+	if got := cfg.origins["format"]; got != localPath {
+		t.Errorf("format origin = %q, want %q", got, localPath)
+	}
+	for _, key := range cfg.Keys() {
+		if strings.HasPrefix(key, "profiles.") {
+			t.Errorf("profile key %q leaked into values", key)
+		}
+	}
+}
+
+func TestResolver_ProfileSelection(t *testing.T) {
+	globalPath, localPath := writeProfileConfigs(t)
+
+	resolver := NewResolverWithPaths(ResolverConfig{EnvPrefix: "PROFILETEST_"}, globalPath, localPath)
+	if got := resolver.Resolve().Get("api_url"); got != "http://global" {
+		t.Errorf("no profile: api_url = %q, want http://global", got)
+	}
+
+	t.Setenv("PROFILETEST_PROFILE", "prod")
+	if got := resolver.Resolve().Get("api_url"); got != "http://prod" {
+		t.Errorf("env profile: api_url = %q, want http://prod", got)
+	}
+
+	cfg := resolver.ResolveWithFlags(map[string]string{"profile": "staging"})
+	if value, source := cfg.GetWithSource("api_url"); value != "http://staging" || source != ProfileSource("staging") {
+		t.Errorf("flag profile: api_url = %q (%s), want http://staging (profile:staging)", value, source)
+	}
+}
+
+func TestResolver_ProfileNotFound(t *testing.T) {
+	globalPath, localPath := writeProfileConfigs(t)
+	var stderr bytes.Buffer
+
+	resolver := NewResolverWithPaths(ResolverConfig{Profile: "qa", ErrWriter: &stderr}, globalPath, localPath)
+	cfg := resolver.Resolve()
+
+	if cfg.Profile() != "" {
+		t.Errorf("Profile() = %q, want empty", cfg.Profile())
+	}
+	if got := cfg.Get("api_url"); got != "http://global" {
+		t.Errorf("api_url = %q, want http://global", got)
+	}
+	if !strings.Contains(stderr.String(), `profile "qa" not found`) {
+		t.Errorf("warning = %q", stderr.String())
+	}
+}
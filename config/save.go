@@ -5,8 +5,6 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-
-	"gopkg.in/yaml.v3"
 )
 
 // SaveConfig provides methods to save configuration values.
@@ -14,7 +12,9 @@ type SaveConfig struct {
 	// GlobalConfigDir is the directory under ~/.config/ for global config.
 	GlobalConfigDir string
 
-	// GlobalConfigFile is the filename. Defaults to "config.yaml".
+	// GlobalConfigFile is the filename. Defaults to "config.yaml". An
+	// existing file with the same name and a .yml, .toml, or .json extension
+	// is updated in its own format instead.
 	GlobalConfigFile string
 
 	// LocalConfigName is the filename for local config in git root.
@@ -41,7 +41,7 @@ func (c SaveConfig) SaveGlobal(key, value string) error {
 	}
 
 	// Validate key
-	if len(c.ValidGlobalKeys) > 0 && !contains(c.ValidGlobalKeys, key) {
+	if len(c.ValidGlobalKeys) > 0 && !allowedKey(c.ValidGlobalKeys, key) {
 		return fmt.Errorf("unknown global config key: %s\n\nValid keys: %s",
 			key, strings.Join(c.ValidGlobalKeys, ", "))
 	}
@@ -51,19 +51,19 @@ func (c SaveConfig) SaveGlobal(key, value string) error {
 		return err
 	}
 
-	configPath := filepath.Join(home, ".config", c.GlobalConfigDir, c.globalConfigFile())
+	configPath := findConfigFile(filepath.Join(home, ".config", c.GlobalConfigDir, c.globalConfigFile()))
 
 	// Load existing config
 	var existing map[string]interface{}
 	if data, readErr := os.ReadFile(configPath); readErr == nil {
-		_ = yaml.Unmarshal(data, &existing)
+		existing, _ = parseConfigFile(configPath, data)
 	}
 	if existing == nil {
 		existing = make(map[string]interface{})
 	}
 
 	// Update value
-	existing[key] = parseValue(value)
+	setNested(existing, key, parseValue(value))
 
 	// Ensure directory exists
 	if err := os.MkdirAll(filepath.Dir(configPath), 0o700); err != nil {
@@ -71,7 +71,7 @@ func (c SaveConfig) SaveGlobal(key, value string) error {
 	}
 
 	// Write config
-	data, err := yaml.Marshal(existing)
+	data, err := marshalConfigFile(configPath, existing)
 	if err != nil {
 		return err
 	}
@@ -89,27 +89,27 @@ func (c SaveConfig) SaveLocal(gitRoot, key, value string) error {
 	}
 
 	// Validate key
-	if len(c.ValidLocalKeys) > 0 && !contains(c.ValidLocalKeys, key) {
+	if len(c.ValidLocalKeys) > 0 && !allowedKey(c.ValidLocalKeys, key) {
 		return fmt.Errorf("unknown local config key: %s\n\nValid keys: %s",
 			key, strings.Join(c.ValidLocalKeys, ", "))
 	}
 
-	configPath := filepath.Join(gitRoot, c.LocalConfigName)
+	configPath := findConfigFile(filepath.Join(gitRoot, c.LocalConfigName))
 
 	// Load existing config
 	var existing map[string]interface{}
 	if data, readErr := os.ReadFile(configPath); readErr == nil {
-		_ = yaml.Unmarshal(data, &existing)
+		existing, _ = parseConfigFile(configPath, data)
 	}
 	if existing == nil {
 		existing = make(map[string]interface{})
 	}
 
 	// Update value
-	existing[key] = parseValue(value)
+	setNested(existing, key, parseValue(value))
 
 	// Write config
-	data, err := yaml.Marshal(existing)
+	data, err := marshalConfigFile(configPath, existing)
 	if err != nil {
 		return err
 	}
@@ -129,7 +129,7 @@ func (c SaveConfig) DeleteGlobalKey(key string) error {
 		return err
 	}
 
-	configPath := filepath.Join(home, ".config", c.GlobalConfigDir, c.globalConfigFile())
+	configPath := findConfigFile(filepath.Join(home, ".config", c.GlobalConfigDir, c.globalConfigFile()))
 
 	// Load existing config
 	data, err := os.ReadFile(configPath)
@@ -137,15 +137,15 @@ func (c SaveConfig) DeleteGlobalKey(key string) error {
 		return nil // Nothing to delete
 	}
 
-	var existing map[string]interface{}
-	if err := yaml.Unmarshal(data, &existing); err != nil {
+	existing, err := parseConfigFile(configPath, data)
+	if err != nil {
 		return nil
 	}
 
-	delete(existing, key)
+	deleteNested(existing, key)
 
 	// Write back
-	data, err = yaml.Marshal(existing)
+	data, err = marshalConfigFile(configPath, existing)
 	if err != nil {
 		return err
 	}
@@ -153,6 +153,39 @@ func (c SaveConfig) DeleteGlobalKey(key string) error {
 	return os.WriteFile(configPath, data, 0o600)
 }
 
+// setNested stores value at a dotted key, creating maps along the way.
+// A top-level key that already contains the dots is updated in place.
+func setNested(m map[string]interface{}, key string, value interface{}) {
+	if _, ok := m[key]; ok || !strings.Contains(key, ".") {
+		m[key] = value
+		return
+	}
+	head, rest, _ := strings.Cut(key, ".")
+	child, ok := m[head].(map[string]interface{})
+	if !ok {
+		child = make(map[string]interface{})
+		m[head] = child
+	}
+	setNested(child, rest, value)
+}
+
+// deleteNested removes a dotted key and any maps it leaves empty
+func deleteNested(m map[string]interface{}, key string) {
+	if _, ok := m[key]; ok || !strings.Contains(key, ".") {
+		delete(m, key)
+		return
+	}
+	head, rest, _ := strings.Cut(key, ".")
+	child, ok := m[head].(map[string]interface{})
+	if !ok {
+		return
+	}
+	deleteNested(child, rest)
+	if len(child) == 0 {
+		delete(m, head)
+	}
+}
+
 // parseValue converts string values to appropriate types for YAML.
 func parseValue(value string) interface{} {
 	lower := strings.ToLower(value)
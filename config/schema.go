@@ -0,0 +1,180 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Schema describes constraints on configuration values. Build one with
+// NewSchema and set it as ResolverConfig.Schema to validate during Resolve.
+type Schema struct {
+	rules map[string]*rule
+	order []string // Keys in declaration order, for stable errors
+}
+
+// rule holds the constraints on one key
+type rule struct {
+	required bool
+	allowed  []string
+	pattern  *regexp.Regexp
+	ranged   bool
+	min, max float64
+}
+
+// NewSchema creates an empty schema.
+func NewSchema() *Schema {
+	return &Schema{rules: make(map[string]*rule)}
+}
+
+// rule returns the rule for key, creating it on first use
+func (s *Schema) rule(key string) *rule {
+	r, ok := s.rules[key]
+	if !ok {
+		r = &rule{}
+		s.rules[key] = r
+		s.order = append(s.order, key)
+	}
+	return r
+}
+
+// Required marks keys that must have a non-empty value.
+func (s *Schema) Required(keys ...string) *Schema {
+	for _, key := range keys {
+		s.rule(key).required = true
+	}
+	return s
+}
+
+// OneOf restricts key to the given values.
+func (s *Schema) OneOf(key string, values ...string) *Schema {
+	s.rule(key).allowed = values
+	return s
+}
+
+// Match requires key to match a regular expression. It panics if pattern
+// does not compile, like regexp.MustCompile.
+func (s *Schema) Match(key, pattern string) *Schema {
+	s.rule(key).pattern = regexp.MustCompile(pattern)
+	return s
+}
+
+// Range requires key to be a number between min and max inclusive. Use
+// math.Inf for an open end.
+func (s *Schema) Range(key string, min, max float64) *Schema {
+	r := s.rule(key)
+	r.ranged, r.min, r.max = true, min, max
+	return s
+}
+
+// Problem is one value that violates the schema.
+type Problem struct {
+	Key     string
+	Value   string
+	Source  Source
+	Origin  string // File or environment variable that supplied the value
+	Message string // e.g. "must be one of table, json"
+}
+
+// String describes the problem and where the value came from.
+func (p Problem) String() string {
+	if p.Source == "" {
+		return fmt.Sprintf("%s: %s", p.Key, p.Message)
+	}
+	origin := string(p.Source)
+	if p.Origin != "" {
+		origin += " " + p.Origin
+	}
+	return fmt.Sprintf("%s = %q (from %s): %s", p.Key, p.Value, origin, p.Message)
+}
+
+// ValidationError reports every schema violation found in a resolution.
+type ValidationError struct {
+	Problems []Problem
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return "config: " + e.Problems[0].String()
+	}
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = "  - " + p.String()
+	}
+	return fmt.Sprintf("config: %d invalid settings:\n%s", len(e.Problems), strings.Join(lines, "\n"))
+}
+
+// Validate checks resolved values against the schema. It returns a
+// *ValidationError listing every problem, or nil.
+func (s *Schema) Validate(cfg *Resolved) error {
+	if s == nil {
+		return nil
+	}
+
+	var problems []Problem
+	for _, key := range s.order {
+		r := s.rules[key]
+		value, source := cfg.values[key], cfg.sources[key]
+		if value == "" {
+			if r.required {
+				problems = append(problems, Problem{Key: key, Message: "required but not set"})
+			}
+			continue
+		}
+		if hasSecretRef(value) {
+			continue // Resolved at access time, so not checked here
+		}
+
+		if msg := r.check(value); msg != "" {
+			problems = append(problems, Problem{
+				Key:     key,
+				Value:   value,
+				Source:  source,
+				Origin:  cfg.origins[key],
+				Message: msg,
+			})
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: problems}
+}
+
+// check returns why value breaks the rule, or ""
+func (r *rule) check(value string) string {
+	if len(r.allowed) > 0 && !slices.Contains(r.allowed, value) {
+		return "must be one of " + strings.Join(r.allowed, ", ")
+	}
+	if r.pattern != nil && !r.pattern.MatchString(value) {
+		return "must match " + r.pattern.String()
+	}
+	if r.ranged {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "must be a number"
+		}
+		if n < r.min || n > r.max {
+			return "must be " + describeRange(r.min, r.max)
+		}
+	}
+	return ""
+}
+
+// describeRange renders a range with open ends omitted
+func describeRange(min, max float64) string {
+	format := func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+	switch {
+	case math.IsInf(min, -1):
+		return "at most " + format(max)
+	case math.IsInf(max, 1):
+		return "at least " + format(min)
+	default:
+		return "between " + format(min) + " and " + format(max)
+	}
+}
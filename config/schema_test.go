@@ -0,0 +1,126 @@
+package config
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	tmpDir := t.TempDir()
+	localPath := filepath.Join(tmpDir, ".myapp.yaml")
+	os.WriteFile(localPath, []byte("format: xml\nproject_id: Bad ID\n"), 0644)
+	t.Setenv("SCHEMA_RETRIES", "50")
+
+	schema := NewSchema().
+		Required("api_url", "token").
+		OneOf("format", "table", "json").
+		Match("project_id", `^[a-z0-9-]+$`).
+		Range("retries", 0, 10).
+		Range("timeout_secs", 1, math.Inf(1))
+
+	resolver := NewResolverWithPaths(ResolverConfig{
+		EnvPrefix: "SCHEMA_",
+		Schema:    schema,
+		Defaults: map[string]string{
+			"api_url":      "http://localhost",
+			"retries":      "3",
+			"timeout_secs": "30",
+		},
+	}, "", localPath)
+
+	cfg := resolver.Resolve()
+	var verr *ValidationError
+	if !errors.As(cfg.Err(), &verr) {
+		t.Fatalf("Err() = %v, want *ValidationError", cfg.Err())
+	}
+
+	got := map[string]Problem{}
+	for _, p := range verr.Problems {
+		got[p.Key] = p
+	}
+	if len(got) != 4 {
+		t.Errorf("problems = %v, want token, format, project_id, retries", verr.Problems)
+	}
+	if p := got["token"]; p.Message != "required but not set" {
+		t.Errorf("token problem = %+v", p)
+	}
+	if p := got["format"]; p.Source != SourceLocal || p.Origin != localPath || p.Message != "must be one of table, json" {
+		t.Errorf("format problem = %+v", p)
+	}
+	if p := got["retries"]; p.Source != SourceEnv || p.Origin != "SCHEMA_RETRIES" || p.Message != "must be between 0 and 10" {
+		t.Errorf("retries problem = %+v", p)
+	}
+	if _, ok := got["timeout_secs"]; ok {
+		t.Error("timeout_secs is in range")
+	}
+
+	msg := cfg.Err().Error()
+	if !strings.Contains(msg, "4 invalid settings") || !strings.Contains(msg, `format = "xml" (from local `+localPath+")") {
+		t.Errorf("Error() = %q", msg)
+	}
+
+	// Flags can fix or break values
+	cfg = resolver.ResolveWithFlags(map[string]string{
+		"token": "t", "format": "json", "project_id": "proj-1", "retries": "5",
+	})
+	if err := cfg.Err(); err != nil {
+		t.Errorf("Err() after flags = %v, want nil", err)
+	}
+}
+
+func TestSchema_RangeMessages(t *testing.T) {
+	tests := []struct {
+		min, max float64
+		value    string
+		want     string
+	}{
+		{0, 10, "11", "must be between 0 and 10"},
+		{math.Inf(-1), 5, "6", "must be at most 5"},
+		{0.5, math.Inf(1), "0.1", "must be at least 0.5"},
+		{0, 10, "ten", "must be a number"},
+		{0, 10, "10", ""},
+	}
+	for _, tt := range tests {
+		cfg := NewResolverWithPaths(ResolverConfig{
+			Defaults: map[string]string{"n": tt.value},
+			Schema:   NewSchema().Range("n", tt.min, tt.max),
+		}, "", "").Resolve()
+
+		var got string
+		var verr *ValidationError
+		if errors.As(cfg.Err(), &verr) {
+			got = verr.Problems[0].Message
+		}
+		if got != tt.want {
+			t.Errorf("Range(%v, %v) on %q = %q, want %q", tt.min, tt.max, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSchema_Unmarshal(t *testing.T) {
+	resolver := NewResolverWithPaths(ResolverConfig{
+		Defaults: map[string]string{"retries": "3"},
+		Schema:   NewSchema().Required("api_url"),
+	}, "", "")
+
+	var cfg testAppConfig
+	err := resolver.Unmarshal(&cfg)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Unmarshal() error = %v, want *ValidationError", err)
+	}
+	if cfg.Retries != 3 {
+		t.Errorf("Retries = %d, valid fields should still decode", cfg.Retries)
+	}
+}
+
+func TestSchema_Nil(t *testing.T) {
+	cfg := NewResolverWithPaths(ResolverConfig{}, "", "").Resolve()
+	if err := cfg.Err(); err != nil {
+		t.Errorf("Err() without schema = %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Secret reference errors.
+var (
+	// ErrNoSecretProvider is returned for ${secret:...} references when
+	// ResolverConfig.Secrets is not set.
+	ErrNoSecretProvider = errors.New("config: no secret provider configured")
+
+	// ErrSecretNotFound is returned when a reference names a missing
+	// environment variable or secret.
+	ErrSecretNotFound = errors.New("config: secret not found")
+
+	// ErrUntrustedReference is returned for references in local config
+	// files unless ResolverConfig.TrustLocalReferences is set.
+	ErrUntrustedReference = errors.New("config: references are not allowed in local config")
+)
+
+// SecretProvider looks up secrets for ${secret:path#key} references, e.g.
+// from Vault or a cloud secret manager. key is "" if the reference has no
+// "#key" part.
+type SecretProvider interface {
+	GetSecret(path, key string) (string, error)
+}
+
+// SecretFunc adapts a function to SecretProvider.
+type SecretFunc func(path, key string) (string, error)
+
+// GetSecret calls f(path, key).
+func (f SecretFunc) GetSecret(path, key string) (string, error) {
+	return f(path, key)
+}
+
+// secretRefPattern matches ${env:NAME}, ${file:/path}, and ${secret:path#key}
+var secretRefPattern = regexp.MustCompile(`\$\{(env|file|secret):([^}]+)\}`)
+
+// hasSecretRef reports whether value contains a reference
+func hasSecretRef(value string) bool {
+	return secretRefPattern.MatchString(value)
+}
+
+// expand replaces the references in key's value. Other ${...} text is
+// kept.
+func (c *Resolved) expand(key, value string) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+	if c.refuseLocal && hasSecretRef(value) && c.fromLocalFile(key) {
+		return "", fmt.Errorf("%w (%s)", ErrUntrustedReference, c.origins[key])
+	}
+
+	var errs []error
+	expanded := secretRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		m := secretRefPattern.FindStringSubmatch(ref)
+		resolved, err := c.lookupRef(m[1], m[2])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ref, err))
+		}
+		return resolved
+	})
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	return expanded, nil
+}
+
+// fromLocalFile reports whether key's value came from a local config file,
+// directly or through a profile
+func (c *Resolved) fromLocalFile(key string) bool {
+	return c.sources[key] == SourceLocal || slices.Contains(c.localFiles, c.origins[key])
+}
+
+// lookupRef resolves one reference
+func (c *Resolved) lookupRef(scheme, ref string) (string, error) {
+	switch scheme {
+	case "env":
+		value, ok := os.LookupEnv(ref)
+		if !ok {
+			return "", ErrSecretNotFound
+		}
+		return value, nil
+	case "file":
+		data, err := os.ReadFile(ref)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	default: // secret
+		if c.secrets == nil {
+			return "", ErrNoSecretProvider
+		}
+		path, key, _ := strings.Cut(ref, "#")
+		return c.secrets.GetSecret(path, key)
+	}
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolved_SecretReferences(t *testing.T) {
+	tmpDir := t.TempDir()
+	tokenFile := filepath.Join(tmpDir, "token")
+	os.WriteFile(tokenFile, []byte("file-secret\n"), 0600)
+	t.Setenv("SECRETS_TEST_USER", "bot")
+
+	calls := 0
+	secrets := SecretFunc(func(path, key string) (string, error) {
+		calls++
+		if path == "vault/jira" && key == "token" {
+			return "vault-secret", nil
+		}
+		return "", ErrSecretNotFound
+	})
+
+	globalPath := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(globalPath, []byte(`user: ${env:SECRETS_TEST_USER}
+file_token: ${file:`+tokenFile+`}
+jira_token: ${secret:vault/jira#token}
+auth: "${env:SECRETS_TEST_USER}:${secret:vault/jira#token}"
+missing: ${secret:vault/other#token}
+literal: ${HOME}
+`), 0644)
+
+	cfg := NewResolverWithPaths(ResolverConfig{Secrets: secrets}, globalPath, "").Resolve()
+
+	tests := map[string]string{
+		"user":       "bot",
+		"file_token": "file-secret",
+		"jira_token": "vault-secret",
+		"auth":       "bot:vault-secret",
+		"literal":    "${HOME}",
+	}
+	for key, want := range tests {
+		if got := cfg.Get(key); got != want {
+			t.Errorf("Get(%q) = %q, want %q", key, got, want)
+		}
+	}
+
+	if got := cfg.Raw("jira_token"); got != "${secret:vault/jira#token}" {
+		t.Errorf("Raw(jira_token) = %q", got)
+	}
+	if got := cfg.All()["jira_token"]; got != "${secret:vault/jira#token}" {
+		t.Errorf("All()[jira_token] = %q, want unexpanded", got)
+	}
+
+	// Looked up on every access, not cached
+	before := calls
+	cfg.Get("jira_token")
+	if calls != before+1 {
+		t.Errorf("provider calls = %d, want %d", calls, before+1)
+	}
+
+	if _, err := cfg.Value("missing"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Value(missing) error = %v, want ErrSecretNotFound", err)
+	}
+	if got := cfg.Get("missing"); got != "" {
+		t.Errorf("Get(missing) = %q, want empty", got)
+	}
+}
+
+func TestResolved_SecretReferenceErrors(t *testing.T) {
+	cfg := &Resolved{values: map[string]string{
+		"secret": "${secret:vault/jira#token}",
+		"env":    "${env:SECRETS_TEST_UNSET}",
+		"file":   "${file:/nonexistent/token}",
+	}}
+
+	if _, err := cfg.Value("secret"); !errors.Is(err, ErrNoSecretProvider) {
+		t.Errorf("Value(secret) error = %v, want ErrNoSecretProvider", err)
+	}
+	if _, err := cfg.Value("env"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Value(env) error = %v, want ErrSecretNotFound", err)
+	}
+	if _, err := cfg.Value("file"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Value(file) error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestResolved_LocalReferencesRefused(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("SECRETS_TEST_TOKEN", "hunter2")
+
+	globalPath := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(globalPath, []byte("token: ${env:SECRETS_TEST_TOKEN}\n"), 0644)
+	localPath := filepath.Join(tmpDir, ".app.yaml")
+	os.WriteFile(localPath, []byte(`webhook_url: https://x/?t=${env:SECRETS_TEST_TOKEN}
+netrc: ${file:/home/u/.netrc}
+profiles:
+  ci:
+    sneaky: ${env:SECRETS_TEST_TOKEN}
+`), 0644)
+
+	cfg := NewResolverWithPaths(ResolverConfig{Profile: "ci"}, globalPath, localPath).Resolve()
+
+	if got := cfg.Get("token"); got != "hunter2" {
+		t.Errorf("Get(token) = %q, want global reference expanded", got)
+	}
+	for _, key := range []string{"webhook_url", "netrc", "sneaky"} {
+		if _, err := cfg.Value(key); !errors.Is(err, ErrUntrustedReference) {
+			t.Errorf("Value(%s) error = %v, want ErrUntrustedReference", key, err)
+		}
+	}
+
+	trusted := NewResolverWithPaths(ResolverConfig{Profile: "ci", TrustLocalReferences: true}, globalPath, localPath).Resolve()
+	if got := trusted.Get("sneaky"); got != "hunter2" {
+		t.Errorf("Get(sneaky) = %q with TrustLocalReferences, want hunter2", got)
+	}
+}
+
+func TestResolved_SecretReferencesUnmarshal(t *testing.T) {
+	t.Setenv("SECRETS_TEST_TIMEOUT", "5s")
+	cfg := &Resolved{
+		values:  map[string]string{"timeout": "${env:SECRETS_TEST_TIMEOUT}", "token": "${env:SECRETS_TEST_UNSET}"},
+		sources: map[string]Source{"timeout": SourceGlobal, "token": SourceGlobal},
+	}
+
+	var out struct {
+		Timeout string `config:"timeout"`
+		Token   string `config:"token"`
+	}
+	err := cfg.Unmarshal(&out)
+	if out.Timeout != "5s" {
+		t.Errorf("Timeout = %q, want 5s", out.Timeout)
+	}
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Key != "token" || !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("Unmarshal() error = %v, want FieldError for token", err)
+	}
+}
+
+func TestSchema_SkipsSecretReferences(t *testing.T) {
+	cfg := &Resolved{values: map[string]string{"token": "${secret:vault/jira#token}"}}
+	schema := NewSchema().Required("token").Match("token", `^[A-Za-z0-9]{20,}$`)
+
+	if err := schema.Validate(cfg); err != nil {
+		t.Errorf("Validate() error = %v, want nil for reference", err)
+	}
+}
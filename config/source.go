@@ -1,6 +1,7 @@
 package config
 
-// Source indicates where a configuration value came from.
+// Source indicates where a configuration value came from. Values from a
+// named profile have the Source returned by ProfileSource.
 type Source string
 
 // Configuration source constants.
@@ -0,0 +1,255 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTarget is returned when Unmarshal is not given a non-nil
+// pointer to a struct.
+var ErrInvalidTarget = errors.New("config: unmarshal target must be a non-nil pointer to a struct")
+
+// FieldError describes a resolved value that could not be converted to
+// its field's type.
+type FieldError struct {
+	Key    string // Config key, e.g. "timeout"
+	Field  string // Go field path, e.g. "Server.Timeout"
+	Value  string
+	Source Source // Layer that supplied the value
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("config: %s (from %s) = %q: %v", e.Key, e.Source, e.Value, e.Err)
+}
+
+// Unwrap returns the conversion error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Unmarshal resolves the configuration and decodes it into out, a pointer
+// to a struct. See Resolved.Unmarshal. Schema violations are returned
+// along with conversion errors.
+func (r *Resolver) Unmarshal(out any) error {
+	cfg := r.Resolve()
+	err := cfg.Unmarshal(out)
+	if cfg.Err() == nil {
+		return err
+	}
+	return errors.Join(cfg.Err(), err)
+}
+
+// Unmarshal decodes resolved values into out, a pointer to a struct whose
+// fields name their keys with `config` tags:
+//
+//	type Config struct {
+//	    APIURL    string        `config:"api_url"`
+//	    Timeout   time.Duration `config:"timeout"`
+//	    Retries   int           `config:"retries"`
+//	    Tags      []string      `config:"tags"` // Comma-separated
+//	    APISource config.Source `config:"api_url,source"`
+//	}
+//
+// Supported field types are strings, bools, integers, floats,
+// time.Duration, slices of those (comma-separated), and
+// encoding.TextUnmarshaler. A field tagged with the ",source" option and
+// of type Source receives the layer the key came from, for per-field
+// source tracking.
+//
+// Fields without a tag, or tagged "-", are ignored. Embedded structs are
+// flattened; other struct fields decode keys prefixed with their tag and a
+// dot ("server.port"). A map[string]T field collects the keys nested under
+// its key ("models.review" as "review"), and a slice of structs decodes
+// indexed keys ("notify.hooks[0].url"). Fields whose key is not set keep their value, so
+// out may carry defaults. All conversion failures are returned together,
+// each as a *FieldError.
+func (c *Resolved) Unmarshal(out any) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidTarget
+	}
+
+	var errs []error
+	c.decodeStruct(v.Elem(), "", "", &errs)
+	return errors.Join(errs...)
+}
+
+// decodeStruct sets the tagged fields of v from keys under prefix
+func (c *Resolved) decodeStruct(v reflect.Value, prefix, path string, errs *[]error) {
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		fv := v.Field(i)
+		fieldPath := path + sf.Name
+
+		tag, hasTag := sf.Tag.Lookup("config")
+		if sf.Anonymous && !hasTag && fv.Kind() == reflect.Struct {
+			c.decodeStruct(fv, prefix, path, errs)
+			continue
+		}
+		if !sf.IsExported() || !hasTag || tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		key := prefix + name
+
+		if opts == "source" {
+			if fv.Type() == reflect.TypeFor[Source]() {
+				fv.Set(reflect.ValueOf(c.sources[key]))
+			}
+			continue
+		}
+
+		switch {
+		case fv.Kind() == reflect.Struct && !isScalar(fv):
+			c.decodeStruct(fv, key+".", fieldPath+".", errs)
+		case fv.Kind() == reflect.Map && fv.Type().Key().Kind() == reflect.String:
+			c.decodeMap(fv, key, fieldPath, errs)
+		case fv.Kind() == reflect.Slice && isStructType(fv.Type().Elem()):
+			c.decodeStructSlice(fv, key, fieldPath, errs)
+		default:
+			c.decodeValue(fv, key, fieldPath, errs)
+		}
+	}
+}
+
+// decodeValue sets v from key, if set, and reports whether it did
+func (c *Resolved) decodeValue(v reflect.Value, key, path string, errs *[]error) bool {
+	raw, ok := c.values[key]
+	if !ok {
+		return false
+	}
+	value, err := c.expand(key, raw)
+	if err == nil {
+		err = setField(v, value)
+	}
+	if err != nil {
+		*errs = append(*errs, &FieldError{
+			Key:    key,
+			Field:  path,
+			Value:  raw,
+			Source: c.sources[key],
+			Err:    err,
+		})
+		return false
+	}
+	return true
+}
+
+// decodeMap fills a map[string]T from the keys nested under key
+func (c *Resolved) decodeMap(v reflect.Value, key, path string, errs *[]error) {
+	subKeys := c.subKeys(key)
+	if len(subKeys) == 0 {
+		return
+	}
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+	for _, sub := range subKeys {
+		name := strings.TrimPrefix(sub, key+".")
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if c.decodeValue(elem, sub, path+"["+name+"]", errs) {
+			v.SetMapIndex(reflect.ValueOf(name).Convert(v.Type().Key()), elem)
+		}
+	}
+}
+
+// decodeStructSlice fills a []struct from indexed keys (key[0].name)
+func (c *Resolved) decodeStructSlice(v reflect.Value, key, path string, errs *[]error) {
+	n := c.Len(key)
+	if n == 0 {
+		return
+	}
+	slice := reflect.MakeSlice(v.Type(), n, n)
+	for i := range n {
+		index := fmt.Sprintf("[%d]", i)
+		c.decodeStruct(slice.Index(i), key+index+".", path+index+".", errs)
+	}
+	v.Set(slice)
+}
+
+// isScalar reports whether a struct-typed field decodes from one string
+func isScalar(v reflect.Value) bool {
+	return v.Addr().Type().Implements(reflect.TypeFor[encoding.TextUnmarshaler]())
+}
+
+// isStructType reports whether t is a struct decoded field by field
+func isStructType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]())
+}
+
+// setField converts value to v's type and stores it
+func setField(v reflect.Value, value string) error {
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(value))
+		}
+	}
+
+	if v.Type() == reflect.TypeFor[time.Duration]() {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer")
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer")
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number")
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		if strings.TrimSpace(value) != "" {
+			items = strings.Split(value, ",")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setField(slice.Index(i), strings.TrimSpace(item)); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		v.Set(slice)
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if err := setField(elem.Elem(), value); err != nil {
+			return err
+		}
+		v.Set(elem)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+type testServerConfig struct {
+	Port int    `config:"port"`
+	Host string `config:"host"`
+}
+
+type testCommonConfig struct {
+	Verbose bool `config:"verbose"`
+}
+
+type testAppConfig struct {
+	testCommonConfig
+
+	APIURL    string           `config:"api_url"`
+	APISource Source           `config:"api_url,source"`
+	Timeout   time.Duration    `config:"timeout"`
+	Retries   int              `config:"retries"`
+	Ratio     float64          `config:"ratio"`
+	Tags      []string         `config:"tags"`
+	Ports     []uint16         `config:"ports"`
+	Addr      netip.Addr       `config:"addr"`
+	MaxCost   *float64         `config:"max_cost"`
+	Server    testServerConfig `config:"server"`
+	Format    string           `config:"format"`
+	Untagged  string
+	Skipped   string `config:"-"`
+}
+
+func TestResolved_Unmarshal(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalPath := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(globalPath, []byte("timeout: 5m\ntags: [a, b]\nserver.port: 9090\n"), 0644)
+
+	t.Setenv("UNM_API_URL", "https://env.example.com")
+
+	resolver := NewResolverWithPaths(ResolverConfig{
+		EnvPrefix: "UNM_",
+		Defaults: map[string]string{
+			"api_url":  "http://localhost",
+			"retries":  "3",
+			"ratio":    "0.5",
+			"verbose":  "true",
+			"ports":    "80, 443",
+			"addr":     "10.0.0.1",
+			"max_cost": "2.5",
+			"Untagged": "ignored",
+		},
+	}, globalPath, "")
+
+	cfg := testAppConfig{Format: "table"}
+	if err := resolver.Unmarshal(&cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if cfg.APIURL != "https://env.example.com" || cfg.APISource != SourceEnv {
+		t.Errorf("APIURL = %q from %q", cfg.APIURL, cfg.APISource)
+	}
+	if cfg.Timeout != 5*time.Minute {
+		t.Errorf("Timeout = %v, want 5m", cfg.Timeout)
+	}
+	if cfg.Retries != 3 || cfg.Ratio != 0.5 || !cfg.Verbose {
+		t.Errorf("Retries = %d, Ratio = %v, Verbose = %v", cfg.Retries, cfg.Ratio, cfg.Verbose)
+	}
+	if !slices.Equal(cfg.Tags, []string{"a", "b"}) {
+		t.Errorf("Tags = %v, want [a b]", cfg.Tags)
+	}
+	if !slices.Equal(cfg.Ports, []uint16{80, 443}) {
+		t.Errorf("Ports = %v, want [80 443]", cfg.Ports)
+	}
+	if cfg.Addr != netip.MustParseAddr("10.0.0.1") {
+		t.Errorf("Addr = %v", cfg.Addr)
+	}
+	if cfg.MaxCost == nil || *cfg.MaxCost != 2.5 {
+		t.Errorf("MaxCost = %v", cfg.MaxCost)
+	}
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Server.Port = %d, want 9090", cfg.Server.Port)
+	}
+	if cfg.Format != "table" {
+		t.Errorf("Format = %q, unset keys should keep the field value", cfg.Format)
+	}
+	if cfg.Untagged != "" {
+		t.Errorf("Untagged = %q, untagged fields should be ignored", cfg.Untagged)
+	}
+}
+
+func TestResolved_Unmarshal_Errors(t *testing.T) {
+	t.Setenv("UNMERR_TIMEOUT", "soon")
+
+	resolver := NewResolverWithPaths(ResolverConfig{
+		EnvPrefix: "UNMERR_",
+		Defaults: map[string]string{
+			"timeout": "1s",
+			"retries": "many",
+			"api_url": "http://localhost",
+		},
+	}, "", "")
+
+	var cfg testAppConfig
+	err := resolver.Unmarshal(&cfg)
+	if err == nil {
+		t.Fatal("Unmarshal() should fail")
+	}
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("error = %v, want *FieldError", err)
+	}
+	got := map[string]Source{}
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		if errors.As(e, &fieldErr) {
+			got[fieldErr.Key] = fieldErr.Source
+		}
+	}
+	if got["timeout"] != SourceEnv || got["retries"] != SourceDefault || len(got) != 2 {
+		t.Errorf("field errors = %v, want timeout (env) and retries (default)", got)
+	}
+	if cfg.APIURL != "http://localhost" {
+		t.Errorf("valid fields should still be set, APIURL = %q", cfg.APIURL)
+	}
+}
+
+func TestResolved_Unmarshal_InvalidTarget(t *testing.T) {
+	cfg := NewResolverWithPaths(ResolverConfig{}, "", "").Resolve()
+
+	var s testAppConfig
+	for _, out := range []any{s, nil, (*testAppConfig)(nil), new(string)} {
+		if err := cfg.Unmarshal(out); !errors.Is(err, ErrInvalidTarget) {
+			t.Errorf("Unmarshal(%T) error = %v, want ErrInvalidTarget", out, err)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"slices"
+	"time"
+)
+
+// DefaultWatchInterval is how often Watch checks config files by default.
+const DefaultWatchInterval = 2 * time.Second
+
+// Change is a key whose value or source changed on reload.
+type Change struct {
+	Key       string
+	OldValue  string
+	NewValue  string // "" if the key was removed
+	OldSource Source
+	NewSource Source
+}
+
+// Update is delivered to subscribers after a config file changes.
+type Update struct {
+	Config  *Resolved // The new configuration
+	Changes []Change  // Changed keys, sorted by key
+}
+
+// Subscribe registers fn to receive updates from Watch. Subscribers are
+// called in the watch goroutine, one update at a time, and only when at
+// least one key changed. The returned function unsubscribes.
+func (r *Resolver) Subscribe(fn func(Update)) (unsubscribe func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.subscribers == nil {
+		r.subscribers = make(map[int]func(Update))
+	}
+	id := r.nextSub
+	r.nextSub++
+	r.subscribers[id] = fn
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.subscribers, id)
+	}
+}
+
+// Watch polls the global and local config files and re-resolves when
+// either is created, modified, or removed, notifying subscribers of the
+// keys that changed. Environment variables are read on each reload but do
+// not trigger one. Flag overrides from the most recent ResolveWithFlags
+// call are re-applied on each reload. Watch blocks until ctx is done and
+// returns ctx.Err().
+//
+//	resolver.Subscribe(func(u config.Update) {
+//	    for _, c := range u.Changes {
+//	        log.Printf("%s: %q (%s) -> %q (%s)", c.Key, c.OldValue, c.OldSource, c.NewValue, c.NewSource)
+//	    }
+//	})
+//	go resolver.Watch(ctx)
+func (r *Resolver) Watch(ctx context.Context) error {
+	interval := r.config.WatchInterval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	paths := append([]string{r.globalPath}, r.localPaths...)
+	contents := make([][]byte, len(paths))
+	for i, path := range paths {
+		contents[i] = readIfExists(path)
+	}
+	current := r.reload()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		changed := false
+		for i, path := range paths {
+			data := readIfExists(path)
+			if !bytes.Equal(data, contents[i]) || (data == nil) != (contents[i] == nil) {
+				contents[i] = data
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		next := r.reload()
+		if changes := Diff(current, next); len(changes) > 0 {
+			r.notify(Update{Config: next, Changes: changes})
+		}
+		current = next
+	}
+}
+
+// reload resolves with the flags from the last ResolveWithFlags call, if any
+func (r *Resolver) reload() *Resolved {
+	r.mu.Lock()
+	flags := r.flags
+	r.mu.Unlock()
+
+	if flags == nil {
+		return r.Resolve()
+	}
+	return r.ResolveWithFlags(flags)
+}
+
+// notify calls every subscriber with u
+func (r *Resolver) notify(u Update) {
+	r.mu.Lock()
+	ids := make([]int, 0, len(r.subscribers))
+	for id := range r.subscribers {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	subs := make([]func(Update), 0, len(ids))
+	for _, id := range ids {
+		subs = append(subs, r.subscribers[id])
+	}
+	r.mu.Unlock()
+
+	for _, fn := range subs {
+		fn(u)
+	}
+}
+
+// Diff lists the keys whose value or source differs between two
+// resolutions, sorted by key. Values are compared as configured, so secret
+// references are not resolved.
+func Diff(old, next *Resolved) []Change {
+	keys := append(old.Keys(), next.Keys()...)
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	var changes []Change
+	for _, key := range keys {
+		// Compare references, not the secrets behind them
+		oldValue, oldSource := old.values[key], old.sources[key]
+		newValue, newSource := next.values[key], next.sources[key]
+		if oldValue != newValue || oldSource != newSource {
+			changes = append(changes, Change{
+				Key:       key,
+				OldValue:  oldValue,
+				NewValue:  newValue,
+				OldSource: oldSource,
+				NewSource: newSource,
+			})
+		}
+	}
+	return changes
+}
+
+// readIfExists returns a file's contents, or nil if it cannot be read
+func readIfExists(path string) []byte {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	if data == nil {
+		data = []byte{} // Distinguish an empty file from a missing one
+	}
+	return data
+}
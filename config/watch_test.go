@@ -0,0 +1,125 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResolver_Watch(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalPath := filepath.Join(tmpDir, "config.yaml")
+	localPath := filepath.Join(tmpDir, ".myapp.yaml")
+	os.WriteFile(globalPath, []byte("api_url: http://global\nformat: table\n"), 0644)
+
+	resolver := NewResolverWithPaths(ResolverConfig{
+		WatchInterval: 5 * time.Millisecond,
+		Defaults:      map[string]string{"format": "json"},
+	}, globalPath, localPath)
+
+	updates := make(chan Update, 10)
+	resolver.Subscribe(func(u Update) { updates <- u })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- resolver.Watch(ctx) }()
+
+	next := func() Update {
+		t.Helper()
+		select {
+		case u := <-updates:
+			return u
+		case <-time.After(2 * time.Second):
+			t.Fatal("no update received")
+			return Update{}
+		}
+	}
+
+	// Give Watch time to take its baseline
+	time.Sleep(20 * time.Millisecond)
+
+	// A new local file overrides the global value
+	os.WriteFile(localPath, []byte("api_url: http://local\n"), 0644)
+	u := next()
+	if len(u.Changes) != 1 {
+		t.Fatalf("changes = %+v, want api_url only", u.Changes)
+	}
+	c := u.Changes[0]
+	if c.Key != "api_url" || c.OldValue != "http://global" || c.OldSource != SourceGlobal ||
+		c.NewValue != "http://local" || c.NewSource != SourceLocal {
+		t.Errorf("change = %+v", c)
+	}
+	if u.Config.Get("api_url") != "http://local" {
+		t.Errorf("Config api_url = %q", u.Config.Get("api_url"))
+	}
+
+	// Removing a key from the global file falls back to the default
+	os.WriteFile(globalPath, []byte("api_url: http://global\n"), 0644)
+	u = next()
+	if len(u.Changes) != 1 || u.Changes[0].Key != "format" || u.Changes[0].NewValue != "json" ||
+		u.Changes[0].NewSource != SourceDefault {
+		t.Errorf("changes = %+v, want format back to default", u.Changes)
+	}
+
+	// Rewriting with the same effective values sends nothing
+	os.WriteFile(globalPath, []byte("# comment\napi_url: http://global\n"), 0644)
+	select {
+	case u := <-updates:
+		t.Errorf("unexpected update %+v", u)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Watch() = %v, want context.Canceled", err)
+	}
+}
+
+func TestResolver_WatchKeepsFlags(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalPath := filepath.Join(tmpDir, "config.yaml")
+	os.WriteFile(globalPath, []byte("api_url: http://global\nformat: table\n"), 0644)
+
+	resolver := NewResolverWithPaths(ResolverConfig{WatchInterval: 5 * time.Millisecond}, globalPath, "")
+	if got := resolver.ResolveWithFlags(map[string]string{"format": "yaml"}).Get("format"); got != "yaml" {
+		t.Fatalf("format = %q, want flag value", got)
+	}
+
+	updates := make(chan Update, 10)
+	resolver.Subscribe(func(u Update) { updates <- u })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go resolver.Watch(ctx)
+	time.Sleep(20 * time.Millisecond)
+
+	os.WriteFile(globalPath, []byte("api_url: http://changed\nformat: csv\n"), 0644)
+	select {
+	case u := <-updates:
+		if len(u.Changes) != 1 || u.Changes[0].Key != "api_url" {
+			t.Errorf("changes = %+v, want api_url only", u.Changes)
+		}
+		if got, src := u.Config.Get("format"), u.Config.Source("format"); got != "yaml" || src != SourceFlag {
+			t.Errorf("format = %q (%s), want flag value kept", got, src)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no update received")
+	}
+}
+
+func TestResolver_Unsubscribe(t *testing.T) {
+	resolver := NewResolverWithPaths(ResolverConfig{}, "", "")
+
+	calls := 0
+	unsubscribe := resolver.Subscribe(func(Update) { calls++ })
+	resolver.notify(Update{})
+	unsubscribe()
+	resolver.notify(Update{})
+
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
@@ -6,13 +6,17 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+
+	"github.com/randalmurphal/llmkit/truncate"
 )
 
 // ContextLimits configures file context limits.
 type ContextLimits struct {
-	MaxFileSize  int64 // Max size per file in bytes
-	MaxTotalSize int64 // Max total size in bytes
-	MaxFileCount int   // Max number of files
+	MaxFileSize   int64 // Max size per file in bytes
+	MaxTotalSize  int64 // Max total size in bytes
+	MaxFileCount  int   // Max number of files
+	MaxTokens     int   // Max total tokens, including file tags (0 = no limit)
+	MaxFileTokens int   // Max tokens per file before truncation (0 = no limit)
 }
 
 // DefaultContextLimits returns sensible default limits.
@@ -26,22 +30,32 @@ func DefaultContextLimits() ContextLimits {
 
 // ContextBuilder builds file context for Claude.
 type ContextBuilder struct {
-	workDir string
-	limits  ContextLimits
-	files   []contextFile
+	workDir          string
+	limits           ContextLimits
+	tokenizer        Tokenizer
+	includeIgnored   bool
+	includeGenerated bool
+	ignore           *IgnoreMatcher
+	cache            *SectionCache
+	files            []contextFile
 }
 
 type contextFile struct {
-	path    string
-	content []byte
-	binary  bool
+	path      string
+	content   []byte
+	binary    bool
+	generated string   // Generated content kind, e.g. "Lockfile"; empty for normal files
+	tag       string   // Section tag replacing <file path=...>, e.g. "repo_map"
+	lazy      bool     // Content not read yet (cached builds)
+	priority  Priority // Eviction order for BuildFitted
 }
 
 // NewContextBuilder creates a context builder for the given working directory.
 func NewContextBuilder(workDir string) *ContextBuilder {
 	return &ContextBuilder{
-		workDir: workDir,
-		limits:  DefaultContextLimits(),
+		workDir:   workDir,
+		limits:    DefaultContextLimits(),
+		tokenizer: DefaultTokenizer(),
 	}
 }
 
@@ -51,6 +65,39 @@ func (b *ContextBuilder) WithLimits(limits ContextLimits) *ContextBuilder {
 	return b
 }
 
+// WithTokenizer sets the tokenizer used for token limits and accounting.
+func (b *ContextBuilder) WithTokenizer(t Tokenizer) *ContextBuilder {
+	b.tokenizer = t
+	return b
+}
+
+// WithModel selects the tokenizer for a model family (see TokenizerFor).
+func (b *ContextBuilder) WithModel(model string) *ContextBuilder {
+	b.tokenizer = TokenizerFor(model)
+	return b
+}
+
+// WithCache reuses rendered sections of files unchanged since an earlier
+// build. With a cache, AddFile defers reading until Build misses the cache.
+func (b *ContextBuilder) WithCache(cache *SectionCache) *ContextBuilder {
+	b.cache = cache
+	return b
+}
+
+// IncludeIgnored makes AddGlob include files excluded by .gitignore or
+// .devflowignore.
+func (b *ContextBuilder) IncludeIgnored() *ContextBuilder {
+	b.includeIgnored = true
+	return b
+}
+
+// IncludeGenerated renders generated files, lockfiles, and minified JS/CSS
+// in full instead of as one-line placeholders.
+func (b *ContextBuilder) IncludeGenerated() *ContextBuilder {
+	b.includeGenerated = true
+	return b
+}
+
 // AddFile adds a single file to the context.
 func (b *ContextBuilder) AddFile(path string) error {
 	fullPath := filepath.Join(b.workDir, path)
@@ -64,21 +111,28 @@ func (b *ContextBuilder) AddFile(path string) error {
 		return fmt.Errorf("%s is a directory", path)
 	}
 
+	if b.cache != nil {
+		b.files = append(b.files, contextFile{path: path, lazy: true})
+		return nil
+	}
+
 	content, err := os.ReadFile(fullPath)
 	if err != nil {
 		return fmt.Errorf("read %s: %w", path, err)
 	}
 
 	b.files = append(b.files, contextFile{
-		path:    path,
-		content: content,
-		binary:  isBinary(content),
+		path:      path,
+		content:   content,
+		binary:    isBinary(content),
+		generated: detectGenerated(path, content),
 	})
 
 	return nil
 }
 
-// AddGlob adds files matching a glob pattern.
+// AddGlob adds files matching a glob pattern. Files excluded by .gitignore
+// or .devflowignore are skipped unless IncludeIgnored is set.
 func (b *ContextBuilder) AddGlob(pattern string) error {
 	matches, err := filepath.Glob(filepath.Join(b.workDir, pattern))
 	if err != nil {
@@ -103,6 +157,10 @@ func (b *ContextBuilder) AddGlob(pattern string) error {
 		}
 
 		if !info.IsDir() {
+			if !b.includeIgnored && b.ignoreMatcher().Ignored(relPath, false) {
+				slog.Debug("skipping ignored file", slog.String("path", relPath))
+				continue
+			}
 			if err := b.AddFile(relPath); err != nil {
 				slog.Debug("skipping unreadable file",
 					slog.String("path", relPath),
@@ -115,12 +173,94 @@ func (b *ContextBuilder) AddGlob(pattern string) error {
 	return nil
 }
 
+// AddRelevant adds the files a RelevanceSelector ranks highest for query
+// (ticket or spec text) and returns their paths. Unset size limits in config
+// default to the builder's limits.
+func (b *ContextBuilder) AddRelevant(query string, config RelevanceConfig) ([]string, error) {
+	if config.MaxTotalSize <= 0 {
+		config.MaxTotalSize = b.limits.MaxTotalSize
+	}
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = b.limits.MaxFileSize
+	}
+	if config.MaxFiles <= 0 && b.limits.MaxFileCount > len(b.files) {
+		config.MaxFiles = b.limits.MaxFileCount - len(b.files)
+	}
+
+	paths, err := NewRelevanceSelector(b.workDir, config).Select(query)
+	if err != nil {
+		return nil, fmt.Errorf("select relevant files: %w", err)
+	}
+
+	added := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if err := b.AddFile(path); err != nil {
+			slog.Debug("skipping unreadable file",
+				slog.String("path", path),
+				slog.String("error", err.Error()))
+			continue
+		}
+		added = append(added, path)
+	}
+	return added, nil
+}
+
+// AddDependencies adds the Go files of packages that the seed files' packages
+// import or are imported by (see ExpandDependencies), so the context shows
+// the interfaces a change must satisfy. It returns the added paths.
+func (b *ContextBuilder) AddDependencies(seeds []string, opts DependencyOptions) ([]string, error) {
+	paths, err := ExpandDependencies(b.workDir, seeds, opts)
+	if err != nil {
+		return nil, fmt.Errorf("expand dependencies: %w", err)
+	}
+
+	added := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if err := b.AddFile(path); err != nil {
+			slog.Debug("skipping unreadable file",
+				slog.String("path", path),
+				slog.String("error", err.Error()))
+			continue
+		}
+		added = append(added, path)
+	}
+	return added, nil
+}
+
+// AddRepoMap adds a compact tree of the working directory with file sizes
+// and top-level symbols (see RepoMap), rendered as a <repo_map> section.
+// Add it before detailed files so it comes first in the context.
+func (b *ContextBuilder) AddRepoMap(opts RepoMapOptions) error {
+	if b.includeIgnored {
+		opts.IncludeIgnored = true
+	}
+	repoMap, err := RepoMap(b.workDir, opts)
+	if err != nil {
+		return fmt.Errorf("repo map: %w", err)
+	}
+	b.files = append(b.files, contextFile{
+		path:    "repo_map",
+		content: []byte(repoMap),
+		tag:     "repo_map",
+	})
+	return nil
+}
+
+// ignoreMatcher loads ignore rules on first use
+func (b *ContextBuilder) ignoreMatcher() *IgnoreMatcher {
+	if b.ignore == nil {
+		b.ignore = NewIgnoreMatcher(b.workDir)
+	}
+	return b.ignore
+}
+
 // AddContent adds pre-loaded content with a virtual path.
 func (b *ContextBuilder) AddContent(path string, content []byte) {
 	b.files = append(b.files, contextFile{
-		path:    path,
-		content: content,
-		binary:  isBinary(content),
+		path:      path,
+		content:   content,
+		binary:    isBinary(content),
+		generated: detectGenerated(path, content),
 	})
 }
 
@@ -134,44 +274,157 @@ func (b *ContextBuilder) Build() (string, error) {
 
 	var buf bytes.Buffer
 	var totalSize int64
+	var totalTokens int
 
-	for _, f := range b.files {
-		content := f.content
-
-		// Handle binary files
-		if f.binary {
-			mimeType := detectMimeType(content)
-			fmt.Fprintf(&buf, "<file path=%q>\n", f.path)
-			fmt.Fprintf(&buf, "[Binary file: %d bytes, type: %s]\n", len(content), mimeType)
-			buf.WriteString("</file>\n\n")
-			continue
-		}
+	var blobs map[string]string
+	if b.cache != nil {
+		blobs = b.cache.blobHashes(b.workDir)
+	}
 
-		// Truncate large files
-		if int64(len(content)) > b.limits.MaxFileSize {
-			content = content[:b.limits.MaxFileSize]
-			content = append(content, []byte("\n\n[... truncated ...]")...)
+	for i := range b.files {
+		rendered, err := b.section(&b.files[i], blobs)
+		if err != nil {
+			return "", err
 		}
 
 		// Check total size
-		totalSize += int64(len(content))
+		totalSize += rendered.Size
 		if totalSize > b.limits.MaxTotalSize {
 			return "", fmt.Errorf("%w: total size %d > max %d",
 				ErrContextTooLarge, totalSize, b.limits.MaxTotalSize)
 		}
 
-		// Format file with XML-style tags
-		fmt.Fprintf(&buf, "<file path=%q>\n", f.path)
-		buf.Write(content)
-		if !bytes.HasSuffix(content, []byte("\n")) {
-			buf.WriteByte('\n')
+		// Check total tokens
+		if b.limits.MaxTokens > 0 {
+			totalTokens += rendered.Tokens
+			if totalTokens > b.limits.MaxTokens {
+				return "", fmt.Errorf("%w: %d tokens > max %d",
+					ErrContextTooLarge, totalTokens, b.limits.MaxTokens)
+			}
 		}
-		buf.WriteString("</file>\n\n")
+
+		buf.WriteString(rendered.Section)
 	}
 
 	return buf.String(), nil
 }
 
+// section renders a file, from the cache when its blob hash is known.
+// Tokens are counted only when a limit or the cache needs them.
+func (b *ContextBuilder) section(f *contextFile, blobs map[string]string) (renderedSection, error) {
+	var key string
+	if blob, ok := blobs[filepath.ToSlash(f.path)]; ok && f.tag == "" {
+		key = sectionKey(f.path, blob, b.limits, b.tokenizer, b.includeGenerated)
+		if rendered, ok := b.cache.get(key); ok {
+			return rendered, nil
+		}
+	}
+
+	if err := b.load(f); err != nil {
+		return renderedSection{}, err
+	}
+	section, size := b.renderFile(*f)
+	rendered := renderedSection{Section: section, Size: size, Binary: f.binary}
+	if b.limits.MaxTokens > 0 || key != "" {
+		rendered.Tokens = b.tokenizer.Count(section)
+	}
+	if key != "" {
+		b.cache.put(key, rendered)
+	}
+	return rendered, nil
+}
+
+// load reads the content of a lazily added file
+func (b *ContextBuilder) load(f *contextFile) error {
+	if !f.lazy {
+		return nil
+	}
+	content, err := os.ReadFile(filepath.Join(b.workDir, f.path))
+	if err != nil {
+		return fmt.Errorf("read %s: %w", f.path, err)
+	}
+	f.content, f.binary, f.lazy = content, isBinary(content), false
+	f.generated = detectGenerated(f.path, content)
+	return nil
+}
+
+// renderFile formats one file with XML-style tags, truncating it to the
+// per-file limits. It returns the section and the content size counted
+// against MaxTotalSize (zero for placeholders).
+func (b *ContextBuilder) renderFile(f contextFile) (string, int64) {
+	// Handle binary files
+	if f.binary {
+		var buf bytes.Buffer
+		mimeType := detectMimeType(f.content)
+		fmt.Fprintf(&buf, "<file path=%q>\n", f.path)
+		fmt.Fprintf(&buf, "[Binary file: %d bytes, type: %s]\n", len(f.content), mimeType)
+		buf.WriteString("</file>\n\n")
+		return buf.String(), 0
+	}
+
+	// Generated files, lockfiles, and minified code get the same treatment
+	if b.placeholder(f) {
+		placeholder := fmt.Sprintf("[%s: %d bytes]\n", f.generated, len(f.content))
+		return b.wrapSection(f, []byte(placeholder), ""), 0
+	}
+
+	content := b.limitContent(f.content)
+	return b.wrapSection(f, content, ""), int64(len(content))
+}
+
+// placeholder reports whether f renders as a one-line placeholder instead
+// of its content
+func (b *ContextBuilder) placeholder(f contextFile) bool {
+	return f.binary || (f.generated != "" && !b.includeGenerated)
+}
+
+// limitContent truncates text content to MaxFileSize and MaxFileTokens
+func (b *ContextBuilder) limitContent(content []byte) []byte {
+	if int64(len(content)) > b.limits.MaxFileSize {
+		content = content[:b.limits.MaxFileSize:b.limits.MaxFileSize]
+		content = append(content, []byte("\n\n[... truncated ...]")...)
+	}
+	if b.limits.MaxFileTokens > 0 {
+		text, truncated := truncate.NewFromEnd().
+			WithCounter(b.tokenizer).
+			WithSuffix("\n\n[... truncated ...]").
+			Truncate(string(content), b.limits.MaxFileTokens)
+		if truncated {
+			content = []byte(text)
+		}
+	}
+	return content
+}
+
+// wrapSection wraps content in the file's tags. attrs (e.g. ` part="1/3"`)
+// are added to the opening tag.
+func (b *ContextBuilder) wrapSection(f contextFile, content []byte, attrs string) string {
+	var buf bytes.Buffer
+	open, close := fmt.Sprintf("<file path=%q%s>", f.path, attrs), "</file>"
+	if f.tag != "" {
+		open, close = "<"+f.tag+attrs+">", "</"+f.tag+">"
+	}
+	buf.WriteString(open + "\n")
+	buf.Write(content)
+	if !bytes.HasSuffix(content, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	buf.WriteString(close + "\n\n")
+	return buf.String()
+}
+
+// loadAll reads lazily added files for accounting; unreadable ones count as
+// empty
+func (b *ContextBuilder) loadAll() {
+	for i := range b.files {
+		if err := b.load(&b.files[i]); err != nil {
+			slog.Debug("skipping unreadable file",
+				slog.String("path", b.files[i].path),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
 // FileCount returns the number of files added.
 func (b *ContextBuilder) FileCount() int {
 	return len(b.files)
@@ -179,6 +432,7 @@ func (b *ContextBuilder) FileCount() int {
 
 // TotalSize returns the total size of all files.
 func (b *ContextBuilder) TotalSize() int64 {
+	b.loadAll()
 	var total int64
 	for _, f := range b.files {
 		total += int64(len(f.content))
@@ -186,6 +440,33 @@ func (b *ContextBuilder) TotalSize() int64 {
 	return total
 }
 
+// TokenCount returns the total tokens of all files, before truncation.
+func (b *ContextBuilder) TokenCount() int {
+	b.loadAll()
+	var total int
+	for _, f := range b.files {
+		if !f.binary {
+			total += b.tokenizer.Count(string(f.content))
+		}
+	}
+	return total
+}
+
+// FileTokens returns per-file token accounting, in the order files were
+// added. Binary files count zero tokens.
+func (b *ContextBuilder) FileTokens() []FileTokens {
+	b.loadAll()
+	result := make([]FileTokens, 0, len(b.files))
+	for _, f := range b.files {
+		ft := FileTokens{Path: f.path, Bytes: int64(len(f.content)), Binary: f.binary}
+		if !f.binary {
+			ft.Tokens = b.tokenizer.Count(string(f.content))
+		}
+		result = append(result, ft)
+	}
+	return result
+}
+
 // Clear removes all files from the builder.
 func (b *ContextBuilder) Clear() {
 	b.files = nil
@@ -228,9 +509,10 @@ func detectMimeType(data []byte) string {
 
 // FileSelector helps select relevant files for context.
 type FileSelector struct {
-	workDir  string
-	includes []string
-	excludes []string
+	workDir        string
+	includes       []string
+	excludes       []string
+	includeIgnored bool
 }
 
 // NewFileSelector creates a file selector for the given directory.
@@ -252,7 +534,15 @@ func (s *FileSelector) Exclude(patterns ...string) *FileSelector {
 	return s
 }
 
-// Select returns files matching the include patterns but not the exclude patterns.
+// IncludeIgnored selects files excluded by .gitignore or .devflowignore.
+func (s *FileSelector) IncludeIgnored() *FileSelector {
+	s.includeIgnored = true
+	return s
+}
+
+// Select returns files matching the include patterns but not the exclude
+// patterns. Files excluded by .gitignore or .devflowignore are left out
+// unless IncludeIgnored is set.
 func (s *FileSelector) Select() ([]string, error) {
 	matches := make(map[string]bool)
 
@@ -280,6 +570,11 @@ func (s *FileSelector) Select() ([]string, error) {
 		}
 	}
 
+	var ignore *IgnoreMatcher
+	if !s.includeIgnored {
+		ignore = NewIgnoreMatcher(s.workDir)
+	}
+
 	// Convert to slice
 	result := make([]string, 0, len(matches))
 	for path := range matches {
@@ -287,6 +582,9 @@ func (s *FileSelector) Select() ([]string, error) {
 		if err != nil || info.IsDir() {
 			continue
 		}
+		if ignore != nil && ignore.Ignored(path, false) {
+			continue
+		}
 		result = append(result, path)
 	}
 
@@ -0,0 +1,177 @@
+package context
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/randalmurphal/devflow/git"
+)
+
+// SectionCache caches rendered context sections keyed by file path and git
+// blob hash, so files unchanged since the last build are neither read nor
+// re-rendered. Share one cache across nodes and runs in the same worktree.
+//
+// Files that are untracked or modified relative to the index have no
+// trustworthy blob hash and are always read.
+type SectionCache struct {
+	dir    string // Optional on-disk store; empty for memory only
+	runner git.CommandRunner
+
+	mu       sync.Mutex
+	sections map[string]renderedSection
+	stats    CacheStats
+}
+
+// CacheStats counts SectionCache lookups.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// renderedSection is a rendered file section with its accounting
+type renderedSection struct {
+	Section string `json:"section"`
+	Size    int64  `json:"size"`
+	Tokens  int    `json:"tokens"`
+	Binary  bool   `json:"binary"`
+}
+
+// NewSectionCache creates a section cache. With a non-empty dir, sections are
+// also persisted there and survive process restarts.
+func NewSectionCache(dir string) *SectionCache {
+	return &SectionCache{
+		dir:      dir,
+		runner:   git.NewExecRunner(),
+		sections: make(map[string]renderedSection),
+	}
+}
+
+// WithRunner sets the command runner used for git (for testing).
+func (c *SectionCache) WithRunner(runner git.CommandRunner) *SectionCache {
+	c.runner = runner
+	return c
+}
+
+// Stats returns a snapshot of hit and miss counts.
+func (c *SectionCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Clear drops all cached sections, including persisted ones.
+func (c *SectionCache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sections = make(map[string]renderedSection)
+	if c.dir == "" {
+		return nil
+	}
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("clear context cache: %w", err)
+	}
+	return nil
+}
+
+// blobHashes returns the index blob hash of every tracked file in workDir
+// that is unmodified in the worktree, keyed by slash path relative to
+// workDir. Errors (e.g. not a git repository) yield an empty map.
+func (c *SectionCache) blobHashes(workDir string) map[string]string {
+	blobs := make(map[string]string)
+
+	staged, err := c.runner.Run(workDir, "git", "ls-files", "--stage", "-z")
+	if err != nil {
+		slog.Debug("context cache disabled: git ls-files failed",
+			slog.String("dir", workDir),
+			slog.String("error", err.Error()))
+		return blobs
+	}
+	for _, entry := range strings.Split(staged, "\x00") {
+		// "<mode> <blob> <stage>\t<path>"
+		meta, path, ok := strings.Cut(entry, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) == 3 && fields[2] == "0" {
+			blobs[path] = fields[1]
+		}
+	}
+
+	modified, err := c.runner.Run(workDir, "git", "diff", "--name-only", "--relative", "-z")
+	if err != nil {
+		return make(map[string]string) // Can't tell what changed: trust nothing
+	}
+	for _, path := range strings.Split(modified, "\x00") {
+		delete(blobs, path)
+	}
+	return blobs
+}
+
+// get returns a cached section
+func (c *SectionCache) get(key string) (renderedSection, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s, ok := c.sections[key]; ok {
+		c.stats.Hits++
+		return s, true
+	}
+	if c.dir != "" {
+		if data, err := os.ReadFile(c.entryPath(key)); err == nil {
+			var s renderedSection
+			if json.Unmarshal(data, &s) == nil {
+				c.sections[key] = s
+				c.stats.Hits++
+				return s, true
+			}
+		}
+	}
+	c.stats.Misses++
+	return renderedSection{}, false
+}
+
+// put stores a rendered section
+func (c *SectionCache) put(key string, s renderedSection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sections[key] = s
+	if c.dir == "" {
+		return
+	}
+	data, err := json.Marshal(s)
+	if err == nil {
+		path := c.entryPath(key)
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+			err = os.WriteFile(path, data, 0o644)
+		}
+	}
+	if err != nil {
+		slog.Debug("failed to persist context section",
+			slog.String("dir", c.dir),
+			slog.String("error", err.Error()))
+	}
+}
+
+// entryPath is the on-disk location of a key, fanned out by prefix
+func (c *SectionCache) entryPath(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// sectionKey identifies a rendered section: the same blob renders
+// differently under another path, per-file limits, tokenizer, or generated
+// file handling
+func sectionKey(path, blob string, limits ContextLimits, tokenizer Tokenizer, includeGenerated bool) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d\x00%d\x00%T%+v\x00%t",
+		path, blob, limits.MaxFileSize, limits.MaxFileTokens, tokenizer, tokenizer, includeGenerated)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,160 @@
+package context
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Chunk is one part of a context split by BuildChunks.
+type Chunk struct {
+	Index   int      // 0-based position
+	Total   int      // Number of chunks
+	Content string   // Sections, each a complete <file> element
+	Tokens  int      // Tokens of Content
+	Paths   []string // Files with a section (or part) in this chunk
+}
+
+// Header returns a one-line label for sending chunks as separate messages,
+// e.g. "Context part 2 of 5".
+func (c Chunk) Header() string {
+	return fmt.Sprintf("Context part %d of %d", c.Index+1, c.Total)
+}
+
+// BuildChunks renders the context like Build, but packs it into chunks of at
+// most budget tokens each, for nodes that send context across several
+// messages or tool calls instead of failing on the total limits.
+//
+// Chunks only break between sections. A file that alone exceeds the budget
+// is split at line boundaries into parts tagged part="i/n", each in its own
+// chunk. Per-file limits still apply; MaxTotalSize, MaxTokens, and
+// MaxFileCount do not. The same input always yields the same chunks.
+func (b *ContextBuilder) BuildChunks(budget int) ([]Chunk, error) {
+	var blobs map[string]string
+	if b.cache != nil {
+		blobs = b.cache.blobHashes(b.workDir)
+	}
+
+	var chunks []Chunk
+	var cur Chunk
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() == 0 {
+			return
+		}
+		cur.Content = buf.String()
+		cur.Index = len(chunks)
+		chunks = append(chunks, cur)
+		cur = Chunk{}
+		buf.Reset()
+	}
+	add := func(path, section string, tokens int) {
+		if cur.Tokens+tokens > budget {
+			flush()
+		}
+		buf.WriteString(section)
+		cur.Tokens += tokens
+		cur.Paths = append(cur.Paths, path)
+	}
+
+	for i := range b.files {
+		f := &b.files[i]
+		rendered, err := b.section(f, blobs)
+		if err != nil {
+			return nil, err
+		}
+		tokens := rendered.Tokens
+		if tokens == 0 {
+			tokens = b.tokenizer.Count(rendered.Section)
+		}
+		if tokens <= budget {
+			add(f.path, rendered.Section, tokens)
+			continue
+		}
+
+		// Too large for any chunk: split into parts, one chunk each
+		if err := b.load(f); err != nil {
+			return nil, err
+		}
+		parts, err := b.splitSection(*f, budget)
+		if err != nil {
+			return nil, err
+		}
+		flush()
+		for _, part := range parts {
+			add(f.path, part, b.tokenizer.Count(part))
+			flush()
+		}
+	}
+	flush()
+
+	for i := range chunks {
+		chunks[i].Total = len(chunks)
+	}
+	return chunks, nil
+}
+
+// splitSection renders f as parts of at most budget tokens, breaking between
+// lines (or inside a line longer than a whole part)
+func (b *ContextBuilder) splitSection(f contextFile, budget int) ([]string, error) {
+	if b.placeholder(f) {
+		return nil, fmt.Errorf("%w: %d tokens for %s", ErrBudgetTooSmall, budget, f.path)
+	}
+	content := b.limitContent(f.content)
+
+	// Reserve room for the widest tags a part can get
+	overhead := b.tokenizer.Count(b.wrapSection(f, nil, ` part="9999/9999"`))
+	room := budget - overhead
+	if room <= 0 {
+		return nil, fmt.Errorf("%w: %d tokens for %s", ErrBudgetTooSmall, budget, f.path)
+	}
+
+	var pieces [][]byte
+	var cur []byte
+	curTokens := 0
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		lineTokens := b.tokenizer.Count(string(line))
+		if curTokens+lineTokens > room && len(cur) > 0 {
+			pieces = append(pieces, cur)
+			cur, curTokens = nil, 0
+		}
+		if lineTokens > room {
+			// A single line too long for a part: cut it by runes
+			runes := []rune(string(line))
+			for len(runes) > 0 {
+				n := b.fitRunes(runes, room)
+				pieces = append(pieces, []byte(string(runes[:n])))
+				runes = runes[n:]
+			}
+			continue
+		}
+		cur = append(cur, line...)
+		curTokens += lineTokens
+	}
+	if len(cur) > 0 {
+		pieces = append(pieces, cur)
+	}
+
+	parts := make([]string, len(pieces))
+	for i, piece := range pieces {
+		parts[i] = b.wrapSection(f, piece, fmt.Sprintf(` part="%d/%d"`, i+1, len(pieces)))
+	}
+	return parts, nil
+}
+
+// fitRunes returns how many leading runes fit in limit tokens (at least one)
+func (b *ContextBuilder) fitRunes(runes []rune, limit int) int {
+	low, high := 1, len(runes)
+	for low < high {
+		mid := (low + high + 1) / 2
+		if b.tokenizer.FitsInLimit(string(runes[:mid]), limit) {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low
+}
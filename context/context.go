@@ -7,6 +7,7 @@ import (
 	"github.com/randalmurphal/devflow/git"
 	"github.com/randalmurphal/devflow/pr"
 	"github.com/randalmurphal/devflow/prompt"
+	"github.com/randalmurphal/devflow/task"
 	"github.com/randalmurphal/devflow/transcript"
 	"github.com/randalmurphal/llmkit/claude"
 )
@@ -29,6 +30,9 @@ const (
 	promptServiceKey     serviceContextKey = "devflow.prompts"
 	runnerServiceKey     serviceContextKey = "devflow.runner"
 	prServiceKey         serviceContextKey = "devflow.pr"
+	cacheServiceKey      serviceContextKey = "devflow.contextcache"
+	usageServiceKey      serviceContextKey = "devflow.usage"
+	selectorServiceKey   serviceContextKey = "devflow.selector"
 )
 
 // WithGit adds a Git context to the context
@@ -187,3 +191,42 @@ func MustPR(ctx context.Context) pr.Provider {
 	}
 	return provider
 }
+
+// WithContextCache adds a context section cache to the context
+func WithContextCache(ctx context.Context, cache *SectionCache) context.Context {
+	return context.WithValue(ctx, cacheServiceKey, cache)
+}
+
+// ContextCache extracts the context section cache, or nil if not set
+func ContextCache(ctx context.Context) *SectionCache {
+	if cache, ok := ctx.Value(cacheServiceKey).(*SectionCache); ok {
+		return cache
+	}
+	return nil
+}
+
+// WithUsage adds a usage recorder to the context
+func WithUsage(ctx context.Context, recorder *task.UsageRecorder) context.Context {
+	return context.WithValue(ctx, usageServiceKey, recorder)
+}
+
+// Usage extracts the usage recorder, or nil if not set
+func Usage(ctx context.Context) *task.UsageRecorder {
+	if recorder, ok := ctx.Value(usageServiceKey).(*task.UsageRecorder); ok {
+		return recorder
+	}
+	return nil
+}
+
+// WithSelector adds a model selector to the context
+func WithSelector(ctx context.Context, selector *task.Selector) context.Context {
+	return context.WithValue(ctx, selectorServiceKey, selector)
+}
+
+// Selector extracts the model selector, or nil if not set
+func Selector(ctx context.Context) *task.Selector {
+	if selector, ok := ctx.Value(selectorServiceKey).(*task.Selector); ok {
+		return selector
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package context
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DependencyOptions configures dependency expansion.
+type DependencyOptions struct {
+	Depth        int  // Import hops followed from the seeds (default: 1)
+	Imports      bool // Follow packages the seeds import
+	Importers    bool // Follow packages that import the seeds
+	IncludeTests bool // Include _test.go files of expanded packages
+	MaxFiles     int  // Files returned (default: 20)
+}
+
+// ExpandDependencies returns the Go files of packages within depth import
+// hops of the seed files' packages, nearest first. Only packages in the
+// workDir module are followed; the seeds themselves are not returned. With
+// neither Imports nor Importers set, both directions are followed.
+func ExpandDependencies(workDir string, seeds []string, opts DependencyOptions) ([]string, error) {
+	if opts.Depth <= 0 {
+		opts.Depth = 1
+	}
+	if opts.MaxFiles <= 0 {
+		opts.MaxFiles = 20
+	}
+	if !opts.Imports && !opts.Importers {
+		opts.Imports, opts.Importers = true, true
+	}
+
+	graph, err := loadPackageGraph(workDir, opts.IncludeTests)
+	if err != nil {
+		return nil, err
+	}
+
+	// Breadth-first from the seed packages
+	seedFiles := make(map[string]bool)
+	dist := make(map[string]int)
+	var frontier []string
+	for _, seed := range seeds {
+		seed = filepath.ToSlash(filepath.Clean(seed))
+		seedFiles[seed] = true
+		dir := path.Dir(seed)
+		if _, ok := graph.files[dir]; ok {
+			if _, seen := dist[dir]; !seen {
+				dist[dir] = 0
+				frontier = append(frontier, dir)
+			}
+		}
+	}
+	for d := 1; d <= opts.Depth && len(frontier) > 0; d++ {
+		var next []string
+		visit := func(dir string) {
+			if _, seen := dist[dir]; !seen {
+				dist[dir] = d
+				next = append(next, dir)
+			}
+		}
+		for _, dir := range frontier {
+			if opts.Imports {
+				for dep := range graph.imports[dir] {
+					visit(dep)
+				}
+			}
+			if opts.Importers {
+				for dep := range graph.importers[dir] {
+					visit(dep)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	dirs := make([]string, 0, len(dist))
+	for dir, d := range dist {
+		if d > 0 {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		if dist[dirs[i]] != dist[dirs[j]] {
+			return dist[dirs[i]] < dist[dirs[j]]
+		}
+		return dirs[i] < dirs[j]
+	})
+
+	var result []string
+	for _, dir := range dirs {
+		for _, file := range graph.files[dir] {
+			if len(result) >= opts.MaxFiles {
+				return result, nil
+			}
+			if seedFiles[file] || (!opts.IncludeTests && strings.HasSuffix(file, "_test.go")) {
+				continue
+			}
+			result = append(result, filepath.FromSlash(file))
+		}
+	}
+	return result, nil
+}
+
+// packageGraph is the import graph of a module's packages, keyed by
+// slash-separated directory relative to the module root ("." for the root)
+type packageGraph struct {
+	files     map[string][]string // dir -> .go files, sorted
+	imports   map[string]map[string]bool
+	importers map[string]map[string]bool
+}
+
+// loadPackageGraph parses the imports of every Go file in the module. Test
+// file imports only count as edges with includeTests.
+func loadPackageGraph(workDir string, includeTests bool) (*packageGraph, error) {
+	module, err := modulePath(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &packageGraph{
+		files:     make(map[string][]string),
+		imports:   make(map[string]map[string]bool),
+		importers: make(map[string]map[string]bool),
+	}
+	ignore := NewIgnoreMatcher(workDir)
+	fset := token.NewFileSet()
+
+	err = filepath.WalkDir(workDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, relErr := filepath.Rel(workDir, p)
+		if relErr != nil || rel == "." {
+			return nil
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if ignore.Ignored(rel, true) || name == "vendor" || name == "testdata" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			// Nested modules are separate graphs
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(rel, ".go") || ignore.Ignored(rel, false) {
+			return nil
+		}
+
+		rel = filepath.ToSlash(rel)
+		dir := path.Dir(rel)
+		g.files[dir] = append(g.files[dir], rel)
+
+		if !includeTests && strings.HasSuffix(rel, "_test.go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, p, nil, parser.ImportsOnly)
+		if err != nil {
+			return nil // Unparseable files still count as package members
+		}
+		for _, imp := range f.Imports {
+			importPath, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			var dep string
+			switch {
+			case importPath == module:
+				dep = "."
+			case strings.HasPrefix(importPath, module+"/"):
+				dep = strings.TrimPrefix(importPath, module+"/")
+			default:
+				continue
+			}
+			if dep == dir {
+				continue // External test package importing its own package
+			}
+			addEdge(g.imports, dir, dep)
+			addEdge(g.importers, dep, dir)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for dir := range g.files {
+		sort.Strings(g.files[dir])
+	}
+	return g, nil
+}
+
+func addEdge(edges map[string]map[string]bool, from, to string) {
+	if edges[from] == nil {
+		edges[from] = make(map[string]bool)
+	}
+	edges[from][to] = true
+}
+
+// modulePath reads the module path from workDir/go.mod
+func modulePath(workDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(workDir, "go.mod"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNoGoModule
+		}
+		return "", fmt.Errorf("read go.mod: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "module"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Trim(strings.TrimSpace(rest), `"`), nil
+		}
+	}
+	return "", fmt.Errorf("read go.mod: no module directive")
+}
@@ -1,10 +1,21 @@
 // Package context provides dependency injection for workflow services.
 //
 // Core types:
-//   - Services: Collection of all devflow services for injection
+//   - Services: Collection of all devflow services for injection, with
+//     Close/Shutdown and HealthCheck for long-running daemons
 //   - ContextBuilder: Builds LLM context from files with size limits
 //   - FileSelector: Selects files for context based on patterns
+//   - ExpandDependencies: Go import/importer expansion from seed files
+//   - RepoMap: Compact directory tree with sizes and top-level symbols
+//   - RelevanceSelector: Ranks repository files against ticket or spec text
+//   - Priority: Eviction order for BuildFitted when limits are exceeded
+//   - Chunk: Part of a context split by BuildChunks under a token budget
+//   - SectionCache: Reuses rendered sections keyed by git blob hash
+//   - IgnoreMatcher: Applies .gitignore and .devflowignore rules
+//   - IncludeGenerated: Renders lockfiles, generated Go, and minified files
+//     in full rather than as placeholders
 //   - ContextLimits: Token and size limits for context building
+//   - Tokenizer: Pluggable token counter per model family
 //
 // Context injection functions:
 //   - WithGit/Git: Git context injection
@@ -13,6 +24,9 @@
 //   - WithArtifact/Artifact: Artifact manager injection
 //   - WithNotifier/Notifier: Notifier injection
 //   - WithRunner/Runner: Command runner injection (for testing)
+//   - WithContextCache/ContextCache: Context section cache injection
+//   - WithUsage/Usage: Per-task LLM usage recorder injection
+//   - Services.Tickets: Issue tracker, injected with ticketing.ContextWithProvider
 //
 // Example usage:
 //
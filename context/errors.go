@@ -6,4 +6,10 @@ import "errors"
 var (
 	// ErrContextTooLarge indicates the context exceeds size limits.
 	ErrContextTooLarge = errors.New("context too large")
+
+	// ErrNoGoModule indicates the working directory has no go.mod.
+	ErrNoGoModule = errors.New("no go.mod in working directory")
+
+	// ErrBudgetTooSmall indicates a chunk budget cannot hold a file section.
+	ErrBudgetTooSmall = errors.New("chunk token budget too small")
 )
@@ -0,0 +1,221 @@
+package context
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/randalmurphal/llmkit/truncate"
+)
+
+// Priority orders files for eviction when a build exceeds its limits.
+// Lower priorities are truncated or dropped first.
+type Priority int
+
+// Common priorities. Any int works; these leave room in between.
+const (
+	PriorityLow    Priority = -10
+	PriorityNormal Priority = 0 // Default for added files
+	PriorityHigh   Priority = 10
+
+	// PriorityRequired files are never truncated or dropped; BuildFitted
+	// fails if they alone exceed the limits.
+	PriorityRequired Priority = 1 << 20
+)
+
+// minTruncatedSize is the smallest useful remainder of a truncated file;
+// below it the file is dropped instead
+const minTruncatedSize = 512
+
+// OmittedFile reports content BuildFitted left out to meet the limits.
+type OmittedFile struct {
+	Path      string
+	Priority  Priority
+	Truncated bool  // Partially included; false means dropped
+	Bytes     int64 // Content bytes removed
+	Tokens    int   // Tokens removed
+}
+
+// BuildResult is the output of BuildFitted.
+type BuildResult struct {
+	Content string
+	Size    int64 // Content bytes counted against MaxTotalSize
+	Tokens  int   // Tokens of Content
+	Omitted []OmittedFile
+}
+
+// SetPriority sets the eviction priority of added files whose path matches
+// pattern (a filepath.Match glob or exact path) and returns how many matched.
+func (b *ContextBuilder) SetPriority(pattern string, priority Priority) int {
+	matched := 0
+	for i := range b.files {
+		if ok, _ := filepath.Match(pattern, b.files[i].path); ok || b.files[i].path == pattern {
+			b.files[i].priority = priority
+			matched++
+		}
+	}
+	return matched
+}
+
+// BuildFitted generates the context like Build, but instead of failing when
+// the limits are exceeded it truncates or drops the lowest-priority files
+// (latest added first among equals) until the rest fit. Files keep the order
+// they were added in. It fails only if PriorityRequired files alone exceed
+// the limits.
+func (b *ContextBuilder) BuildFitted() (*BuildResult, error) {
+	var blobs map[string]string
+	if b.cache != nil {
+		blobs = b.cache.blobHashes(b.workDir)
+	}
+
+	sections := make([]renderedSection, len(b.files))
+	kept := make([]bool, len(b.files))
+	var totalSize int64
+	var totalTokens, count int
+	for i := range b.files {
+		rendered, err := b.section(&b.files[i], blobs)
+		if err != nil {
+			return nil, err
+		}
+		if rendered.Tokens == 0 && rendered.Section != "" {
+			rendered.Tokens = b.tokenizer.Count(rendered.Section)
+		}
+		sections[i], kept[i] = rendered, true
+		totalSize += rendered.Size
+		totalTokens += rendered.Tokens
+		count++
+	}
+
+	// Eviction order: lowest priority first, later-added first among equals
+	order := make([]int, len(b.files))
+	for i := range order {
+		order[i] = len(b.files) - 1 - i
+	}
+	sort.SliceStable(order, func(x, y int) bool {
+		return b.files[order[x]].priority < b.files[order[y]].priority
+	})
+
+	sizeOver := func() int64 { return totalSize - b.limits.MaxTotalSize }
+	tokensOver := func() int {
+		if b.limits.MaxTokens <= 0 {
+			return 0
+		}
+		return totalTokens - b.limits.MaxTokens
+	}
+
+	var omitted []OmittedFile
+	for _, i := range order {
+		countOver := count > b.limits.MaxFileCount
+		if !countOver && sizeOver() <= 0 && tokensOver() <= 0 {
+			break
+		}
+		f := b.files[i]
+		if f.priority >= PriorityRequired {
+			continue
+		}
+
+		// Truncate when the file can absorb the overflow and stay useful
+		if !countOver && !b.placeholder(f) {
+			if rendered, ok := b.shrink(f, sections[i], sizeOver(), tokensOver()); ok {
+				omitted = append(omitted, OmittedFile{
+					Path:      f.path,
+					Priority:  f.priority,
+					Truncated: true,
+					Bytes:     sections[i].Size - rendered.Size,
+					Tokens:    sections[i].Tokens - rendered.Tokens,
+				})
+				totalSize -= sections[i].Size - rendered.Size
+				totalTokens -= sections[i].Tokens - rendered.Tokens
+				sections[i] = rendered
+				continue
+			}
+		}
+
+		kept[i] = false
+		count--
+		totalSize -= sections[i].Size
+		totalTokens -= sections[i].Tokens
+		omitted = append(omitted, OmittedFile{
+			Path:     f.path,
+			Priority: f.priority,
+			Bytes:    sections[i].Size,
+			Tokens:   sections[i].Tokens,
+		})
+	}
+
+	switch {
+	case count > b.limits.MaxFileCount:
+		return nil, fmt.Errorf("%w: %d required files > max %d",
+			ErrContextTooLarge, count, b.limits.MaxFileCount)
+	case sizeOver() > 0:
+		return nil, fmt.Errorf("%w: required files total size %d > max %d",
+			ErrContextTooLarge, totalSize, b.limits.MaxTotalSize)
+	case tokensOver() > 0:
+		return nil, fmt.Errorf("%w: required files %d tokens > max %d",
+			ErrContextTooLarge, totalTokens, b.limits.MaxTokens)
+	}
+
+	var sb strings.Builder
+	for i, s := range sections {
+		if kept[i] {
+			sb.WriteString(s.Section)
+		}
+	}
+	return &BuildResult{
+		Content: sb.String(),
+		Size:    totalSize,
+		Tokens:  totalTokens,
+		Omitted: omitted,
+	}, nil
+}
+
+// shrink re-renders f with its content cut by the size and token overflow.
+// ok is false when too little would remain to be worth keeping.
+func (b *ContextBuilder) shrink(f contextFile, current renderedSection, sizeOver int64, tokensOver int) (renderedSection, bool) {
+	const suffix = "\n\n[... truncated ...]"
+
+	content := f.content
+	if int64(len(content)) > b.limits.MaxFileSize {
+		content = content[:b.limits.MaxFileSize]
+	}
+	if sizeOver > 0 {
+		keep := int64(len(content)) - sizeOver - int64(len(suffix))
+		if keep < minTruncatedSize {
+			return renderedSection{}, false
+		}
+		content = append(content[:keep:keep], suffix...)
+	}
+	// Token counts are not additive across the cut and the tags, so retry
+	// with the remaining excess a few times
+	limit := current.Tokens - max(tokensOver, 0)
+	target := b.tokenizer.Count(string(content)) - max(tokensOver, 0)
+	for range 3 {
+		candidate := content
+		if tokensOver > 0 {
+			if target <= 0 {
+				return renderedSection{}, false
+			}
+			text, _ := truncate.NewFromEnd().
+				WithCounter(b.tokenizer).
+				WithSuffix(suffix).
+				Truncate(string(content), target)
+			if len(text) < minTruncatedSize {
+				return renderedSection{}, false
+			}
+			candidate = []byte(text)
+		}
+
+		f.content = candidate
+		section, size := b.renderFile(f)
+		rendered := renderedSection{Section: section, Size: size, Tokens: b.tokenizer.Count(section)}
+		if rendered.Size > current.Size-max(sizeOver, 0) {
+			return renderedSection{}, false
+		}
+		if rendered.Tokens <= limit {
+			return rendered, true
+		}
+		target -= rendered.Tokens - limit
+	}
+	return renderedSection{}, false
+}
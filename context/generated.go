@@ -0,0 +1,94 @@
+package context
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Generated content kinds, used in placeholders
+const (
+	generatedCode = "Generated file"
+	lockfile      = "Lockfile"
+	minifiedFile  = "Minified file"
+)
+
+// lockfileNames are dependency lockfiles, matched by base name
+var lockfileNames = map[string]bool{
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"bun.lockb":           true,
+	"go.sum":              true,
+	"Cargo.lock":          true,
+	"Gemfile.lock":        true,
+	"poetry.lock":         true,
+	"Pipfile.lock":        true,
+	"uv.lock":             true,
+	"composer.lock":       true,
+	"mix.lock":            true,
+	"flake.lock":          true,
+}
+
+// minifiableExts are extensions checked for minified content
+var minifiableExts = map[string]bool{
+	".js": true, ".mjs": true, ".cjs": true, ".css": true,
+}
+
+// generatedHeader is the Go convention for generated files
+// (https://go.dev/s/generatedcode)
+var generatedHeader = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// Minified content heuristics, applied to the first 8KB
+const (
+	minifiedMinSize    = 1024 // Smaller files are cheap enough to include
+	minifiedLineLength = 500  // Average line length above which text is minified
+)
+
+// detectGenerated classifies content that is text but not worth sending to
+// an LLM. It returns the kind for the placeholder, or "" for normal files.
+func detectGenerated(path string, content []byte) string {
+	base := filepath.Base(path)
+	ext := strings.ToLower(filepath.Ext(base))
+
+	switch {
+	case lockfileNames[base]:
+		return lockfile
+	case ext == ".go" && hasGeneratedHeader(content):
+		return generatedCode
+	case minifiableExts[ext] && isMinified(base, content):
+		return minifiedFile
+	}
+	return ""
+}
+
+// hasGeneratedHeader reports whether a Go file has a "Code generated ... DO
+// NOT EDIT." line before its package clause
+func hasGeneratedHeader(content []byte) bool {
+	header := content
+	if i := bytes.Index(content, []byte("\npackage ")); i >= 0 {
+		header = content[:i+1]
+	} else if bytes.HasPrefix(content, []byte("package ")) {
+		return false
+	}
+	return generatedHeader.Match(header)
+}
+
+// isMinified reports whether JS or CSS content is minified, by name
+// (foo.min.js) or by average line length
+func isMinified(base string, content []byte) bool {
+	if strings.Contains(strings.ToLower(base), ".min.") {
+		return true
+	}
+	sample := content
+	if len(sample) > 8192 {
+		sample = sample[:8192]
+	}
+	if len(sample) < minifiedMinSize {
+		return false
+	}
+	lines := bytes.Count(sample, []byte("\n")) + 1
+	return len(sample)/lines > minifiedLineLength
+}
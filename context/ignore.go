@@ -0,0 +1,231 @@
+package context
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// DevflowIgnoreFile lists extra paths to keep out of context, in .gitignore
+// syntax. Negated patterns ("!gen/api.go") can re-include gitignored files.
+const DevflowIgnoreFile = ".devflowignore"
+
+// IgnoreMatcher applies .gitignore rules, .git/info/exclude, and
+// .devflowignore to paths relative to a working directory. Nested .gitignore
+// files are loaded as directories are visited.
+type IgnoreMatcher struct {
+	workDir string
+
+	mu      sync.Mutex
+	dirs    map[string][]ignoreRule // dir -> rules from its .gitignore
+	base    []ignoreRule            // .git/info/exclude
+	devflow []ignoreRule            // .devflowignore, checked last
+}
+
+// ignoreRule is one compiled .gitignore pattern
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// NewIgnoreMatcher creates a matcher for workDir. Missing ignore files are
+// not an error.
+func NewIgnoreMatcher(workDir string) *IgnoreMatcher {
+	return &IgnoreMatcher{
+		workDir: workDir,
+		dirs:    make(map[string][]ignoreRule),
+		base:    loadIgnoreFile(filepath.Join(workDir, ".git", "info", "exclude"), ""),
+		devflow: loadIgnoreFile(filepath.Join(workDir, DevflowIgnoreFile), ""),
+	}
+}
+
+// Ignored reports whether relPath (slash or OS separated, relative to the
+// working directory) is excluded. Anything under an excluded directory is
+// excluded too, as with git.
+func (m *IgnoreMatcher) Ignored(relPath string, isDir bool) bool {
+	relPath = filepath.ToSlash(filepath.Clean(relPath))
+	if relPath == "." || relPath == "" {
+		return false
+	}
+
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.match(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.match(relPath, isDir)
+}
+
+// match applies rules to one path; the last matching rule wins
+func (m *IgnoreMatcher) match(p string, isDir bool) bool {
+	if p == ".git" || strings.HasSuffix(p, "/.git") {
+		return true
+	}
+
+	ignored := false
+	apply := func(rules []ignoreRule) {
+		for _, r := range rules {
+			if r.dirOnly && !isDir {
+				continue
+			}
+			if r.re.MatchString(p) {
+				ignored = !r.negate
+			}
+		}
+	}
+
+	apply(m.base)
+	// .gitignore files from the root down to the path's directory
+	dir := path.Dir(p)
+	apply(m.dirRules(""))
+	if dir != "." {
+		parts := strings.Split(dir, "/")
+		for i := 1; i <= len(parts); i++ {
+			apply(m.dirRules(strings.Join(parts[:i], "/")))
+		}
+	}
+	apply(m.devflow)
+	return ignored
+}
+
+// dirRules returns the cached rules from dir's .gitignore
+func (m *IgnoreMatcher) dirRules(dir string) []ignoreRule {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if rules, ok := m.dirs[dir]; ok {
+		return rules
+	}
+	rules := loadIgnoreFile(filepath.Join(m.workDir, filepath.FromSlash(dir), ".gitignore"), dir)
+	m.dirs[dir] = rules
+	return rules
+}
+
+// loadIgnoreFile parses an ignore file whose patterns are relative to dir
+func loadIgnoreFile(file, dir string) []ignoreRule {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Debug("skipping unreadable ignore file",
+				slog.String("path", file),
+				slog.String("error", err.Error()))
+		}
+		return nil
+	}
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		rule, ok, err := parseIgnoreLine(scanner.Text(), dir)
+		if err != nil {
+			slog.Warn("skipping invalid ignore pattern",
+				slog.String("path", file),
+				slog.Int("line", lineNo),
+				slog.String("error", err.Error()))
+			continue
+		}
+		if ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// parseIgnoreLine compiles one .gitignore line. It reports false for blank
+// lines and comments, and an error for a pattern that does not compile.
+func parseIgnoreLine(line, dir string) (ignoreRule, bool, error) {
+	line = strings.TrimRight(line, "\r")
+	if !strings.HasSuffix(line, `\ `) {
+		line = strings.TrimRight(line, " ")
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false, nil
+	}
+
+	var rule ignoreRule
+	switch {
+	case strings.HasPrefix(line, "!"):
+		rule.negate = true
+		line = line[1:]
+	case strings.HasPrefix(line, `\!`), strings.HasPrefix(line, `\#`):
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false, nil
+	}
+
+	// A slash anywhere but the end anchors the pattern to its directory
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	var prefix string
+	if dir != "" {
+		prefix = regexp.QuoteMeta(dir) + "/"
+	}
+	expr := "^" + prefix
+	if !anchored {
+		expr += "(?:.*/)?"
+	}
+	expr += globToRegexp(line) + "$"
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return ignoreRule{}, false, fmt.Errorf("pattern %q: %w", line, err)
+	}
+	rule.re = re
+	return rule, true, nil
+}
+
+// globToRegexp converts a gitignore glob to a regular expression
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			sb.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
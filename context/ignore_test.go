@@ -0,0 +1,150 @@
+package context
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTree creates files under dir; content is written as given
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestIgnoreMatcher_Ignored(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		// want maps paths to whether they are ignored; a trailing slash
+		// checks the path as a directory
+		want map[string]bool
+	}{
+		{
+			name:  "unanchored pattern matches at any depth",
+			files: map[string]string{".gitignore": "*.log\n"},
+			want:  map[string]bool{"a.log": true, "sub/deep/b.log": true, "a.txt": false},
+		},
+		{
+			name:  "leading slash anchors to the ignore file's directory",
+			files: map[string]string{".gitignore": "/build\n"},
+			want:  map[string]bool{"build": true, "build/out.bin": true, "sub/build": false},
+		},
+		{
+			name:  "middle slash anchors",
+			files: map[string]string{".gitignore": "docs/out\n"},
+			want:  map[string]bool{"docs/out": true, "x/docs/out": false},
+		},
+		{
+			name:  "leading **/ matches in every directory",
+			files: map[string]string{".gitignore": "**/tmp\n"},
+			want:  map[string]bool{"tmp": true, "a/b/tmp": true, "tmpfile": false},
+		},
+		{
+			name:  "trailing /** matches everything inside",
+			files: map[string]string{".gitignore": "vendor/**\n"},
+			want:  map[string]bool{"vendor/": false, "vendor/x.go": true, "vendor/a/b.go": true},
+		},
+		{
+			name:  "middle /**/ matches zero or more directories",
+			files: map[string]string{".gitignore": "a/**/b\n"},
+			want:  map[string]bool{"a/b": true, "a/x/y/b": true, "c/a/b": false},
+		},
+		{
+			name:  "trailing slash matches directories only",
+			files: map[string]string{".gitignore": "cache/\n"},
+			want:  map[string]bool{"cache/": true, "cache": false, "cache/entry": true, "sub/cache/": true},
+		},
+		{
+			name:  "negation re-includes",
+			files: map[string]string{".gitignore": "*.log\n!keep.log\n"},
+			want:  map[string]bool{"drop.log": true, "keep.log": false, "sub/keep.log": false},
+		},
+		{
+			name:  "escaped ! and # match literally",
+			files: map[string]string{".gitignore": "# comment\n\\!important\n\\#notes\n"},
+			want:  map[string]bool{"!important": true, "#notes": true, "# comment": false, "important": false},
+		},
+		{
+			name:  "trailing spaces are trimmed unless escaped",
+			files: map[string]string{".gitignore": "foo.txt   \nbar\\ \n"},
+			want:  map[string]bool{"foo.txt": true, "bar ": true, "bar": false},
+		},
+		{
+			name: "nested .gitignore is relative to its directory and overrides parents",
+			files: map[string]string{
+				".gitignore":     "*.gen\n",
+				"sub/.gitignore": "!keep.gen\n/local\n",
+			},
+			want: map[string]bool{
+				"a.gen": true, "sub/a.gen": true, "sub/keep.gen": false, "keep.gen": true,
+				"sub/local": true, "local": false, "sub/deeper/local": false,
+			},
+		},
+		{
+			name:  "children of an excluded directory cannot be re-included",
+			files: map[string]string{".gitignore": "build/\n!build/keep.txt\n"},
+			want:  map[string]bool{"build/keep.txt": true, "build/other.txt": true},
+		},
+		{
+			name:  ".git/info/exclude applies",
+			files: map[string]string{".git/info/exclude": "secret.env\n"},
+			want:  map[string]bool{"secret.env": true, "sub/secret.env": true, ".git/config": true},
+		},
+		{
+			name: ".devflowignore re-includes gitignored files and adds exclusions",
+			files: map[string]string{
+				".gitignore":      "*.pb.go\n",
+				DevflowIgnoreFile: "!api.pb.go\nfixtures/\n",
+			},
+			want: map[string]bool{
+				"api.pb.go": false, "other.pb.go": true, "fixtures/data.json": true, "main.go": false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTree(t, dir, tt.files)
+			m := NewIgnoreMatcher(dir)
+
+			for p, want := range tt.want {
+				isDir := strings.HasSuffix(p, "/")
+				if got := m.Ignored(strings.TrimSuffix(p, "/"), isDir); got != want {
+					t.Errorf("Ignored(%q, %v) = %v, want %v", p, isDir, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestIgnoreMatcher_WarnsOnInvalidPattern(t *testing.T) {
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{".gitignore": "[z-a]\n*.log\n"})
+	m := NewIgnoreMatcher(dir)
+
+	if !m.Ignored("a.log", false) {
+		t.Error("valid patterns after an invalid one were not applied")
+	}
+	out := logs.String()
+	if !strings.Contains(out, "skipping invalid ignore pattern") || !strings.Contains(out, "line=1") {
+		t.Errorf("log = %q, want a warning for line 1", out)
+	}
+}
@@ -0,0 +1,100 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/randalmurphal/devflow/notify"
+)
+
+// HealthChecker is implemented by services that can report readiness, such
+// as transcript stores, the artifact manager, and queued notifiers.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// Close shuts down all services with no deadline. See Shutdown.
+func (s *Services) Close() error {
+	return s.Shutdown(context.Background())
+}
+
+// Shutdown releases the services' resources: notifiers are flushed and
+// closed first (so queued events can still be recorded), then the transcript
+// store and LLM client. Services are closed when they implement io.Closer or
+// Close(ctx) error, and HTTP-backed ones drop idle connections. All services
+// are attempted and errors are joined.
+func (s *Services) Shutdown(ctx context.Context) error {
+	var errs []error
+	closeNamed := func(name string, svc any) {
+		if err := closeService(ctx, svc); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", name, err))
+		}
+	}
+
+	if s.Notifier != nil {
+		if err := notify.Close(ctx, s.Notifier); err != nil {
+			errs = append(errs, fmt.Errorf("close notifier: %w", err))
+		}
+	}
+	if s.Transcripts != nil {
+		closeNamed("transcripts", s.Transcripts)
+	}
+	if s.LLM != nil {
+		closeNamed("llm", s.LLM)
+	}
+	return errors.Join(errs...)
+}
+
+// closeService closes svc using whichever close method it has
+func closeService(ctx context.Context, svc any) error {
+	if c, ok := svc.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+
+	switch c := svc.(type) {
+	case interface{ Close(context.Context) error }:
+		return c.Close(ctx)
+	case io.Closer:
+		return c.Close()
+	}
+	return nil
+}
+
+// HealthCheck probes every configured service that can report readiness and
+// returns the joined failures, each prefixed with the service name. The git
+// repository is checked with "git rev-parse".
+func (s *Services) HealthCheck(ctx context.Context) error {
+	var errs []error
+	check := func(name string, svc any) {
+		h, ok := svc.(HealthChecker)
+		if !ok {
+			return
+		}
+		if err := h.HealthCheck(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	if s.Git != nil {
+		if _, err := s.Git.RunGit("rev-parse", "--git-dir"); err != nil {
+			errs = append(errs, fmt.Errorf("git: %w", err))
+		}
+	}
+	if s.LLM != nil {
+		check("llm", s.LLM)
+	}
+	if s.Transcripts != nil {
+		check("transcripts", s.Transcripts)
+	}
+	if s.Artifacts != nil {
+		check("artifacts", s.Artifacts)
+	}
+	if s.Notifier != nil {
+		if err := notify.HealthCheck(ctx, s.Notifier); err != nil {
+			errs = append(errs, fmt.Errorf("notifier: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,335 @@
+package context
+
+import (
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/randalmurphal/devflow/git"
+)
+
+// RelevanceConfig configures RelevanceSelector.
+type RelevanceConfig struct {
+	MaxFiles     int               // Files selected (default: 20)
+	MaxTotalSize int64             // Combined size of selected files (default: DefaultContextLimits)
+	MaxFileSize  int64             // Larger files are not scanned or selected (default: DefaultContextLimits)
+	HistoryDepth int               // Recent commits examined (default: 200; negative disables)
+	Runner       git.CommandRunner // Runs git log (default: git.ExecRunner)
+}
+
+// ScoredFile is a file ranked by RelevanceSelector.
+type ScoredFile struct {
+	Path    string
+	Size    int64
+	Score   float64
+	Reasons []string // e.g. "path:auth", "symbol:TokenStore", "history:2"
+}
+
+// RelevanceSelector ranks repository files against ticket or spec text and
+// selects the most relevant ones, in place of hand-written glob lists.
+//
+// Files score for keywords in their path, for declaring or mentioning
+// identifiers from the text, and for recent commits that touched them with
+// matching subjects. Ignored and binary files are skipped.
+type RelevanceSelector struct {
+	workDir string
+	config  RelevanceConfig
+}
+
+// NewRelevanceSelector creates a selector for workDir.
+func NewRelevanceSelector(workDir string, config RelevanceConfig) *RelevanceSelector {
+	defaults := DefaultContextLimits()
+	if config.MaxFiles <= 0 {
+		config.MaxFiles = 20
+	}
+	if config.MaxTotalSize <= 0 {
+		config.MaxTotalSize = defaults.MaxTotalSize
+	}
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = defaults.MaxFileSize
+	}
+	if config.HistoryDepth == 0 {
+		config.HistoryDepth = 200
+	}
+	if config.Runner == nil {
+		config.Runner = git.NewExecRunner()
+	}
+	return &RelevanceSelector{workDir: workDir, config: config}
+}
+
+// Relevance weights
+const (
+	pathKeywordScore   = 3.0
+	symbolDeclScore    = 5.0
+	contentMatchScore  = 1.0
+	historyMatchScore  = 2.0
+	recentlyTouchScore = 0.5
+)
+
+// Select returns the paths of the top-ranked files that fit the limits,
+// most relevant first.
+func (s *RelevanceSelector) Select(query string) ([]string, error) {
+	ranked, err := s.Rank(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	var total int64
+	for _, f := range ranked {
+		if len(paths) >= s.config.MaxFiles {
+			break
+		}
+		if total+f.Size > s.config.MaxTotalSize {
+			continue // Try smaller files further down
+		}
+		total += f.Size
+		paths = append(paths, f.Path)
+	}
+	return paths, nil
+}
+
+// Rank scores every candidate file against query and returns those with a
+// positive score, highest first.
+func (s *RelevanceSelector) Rank(query string) ([]ScoredFile, error) {
+	keywords := queryKeywords(query)
+	symbols := querySymbols(query)
+	if len(keywords) == 0 && len(symbols) == 0 {
+		return nil, nil
+	}
+
+	history := s.history(keywords)
+	ignore := NewIgnoreMatcher(s.workDir)
+
+	var ranked []ScoredFile
+	err := filepath.WalkDir(s.workDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		rel, relErr := filepath.Rel(s.workDir, path)
+		if relErr != nil || rel == "." {
+			return nil
+		}
+		if d.IsDir() {
+			if ignore.Ignored(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || ignore.Ignored(rel, false) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil || info.Size() > s.config.MaxFileSize {
+			return nil
+		}
+
+		f, ok := s.score(rel, info.Size(), keywords, symbols, history[filepath.ToSlash(rel)])
+		if ok {
+			ranked = append(ranked, f)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Path < ranked[j].Path
+	})
+	return ranked, nil
+}
+
+// fileHistory summarizes recent commits touching a file
+type fileHistory struct {
+	touches int // Recent commits touching the file
+	matches int // Of those, commits whose subject contains a keyword
+}
+
+// score rates one file; ok is false for binary or irrelevant files
+func (s *RelevanceSelector) score(rel string, size int64, keywords map[string]bool, symbols map[string]bool, hist fileHistory) (ScoredFile, bool) {
+	f := ScoredFile{Path: rel, Size: size}
+
+	// Path keywords
+	for _, word := range identifierWords(filepath.ToSlash(rel)) {
+		if keywords[word] {
+			f.Score += pathKeywordScore
+			f.Reasons = append(f.Reasons, "path:"+word)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(s.workDir, rel))
+	if err != nil || isBinary(content) {
+		return ScoredFile{}, false
+	}
+
+	// Declared symbols
+	declared := make(map[string]bool)
+	for _, m := range declPattern.FindAllSubmatch(content, -1) {
+		declared[string(m[1])] = true
+	}
+	for sym := range symbols {
+		if declared[sym] {
+			f.Score += symbolDeclScore
+			f.Reasons = append(f.Reasons, "symbol:"+sym)
+		}
+	}
+
+	// Keyword mentions, once per keyword
+	lower := strings.ToLower(string(content))
+	for word := range keywords {
+		if strings.Contains(lower, word) {
+			f.Score += contentMatchScore
+		}
+	}
+
+	// Recent history
+	if hist.matches > 0 {
+		f.Score += historyMatchScore * float64(hist.matches)
+		f.Reasons = append(f.Reasons, "history:"+strconv.Itoa(hist.matches))
+	}
+	if hist.touches > 0 && f.Score > 0 {
+		f.Score += recentlyTouchScore
+	}
+
+	sort.Strings(f.Reasons)
+	return f, f.Score > 0
+}
+
+// history reads recent commits and counts, per file, the commits touching it
+// and those whose subject mentions a keyword
+func (s *RelevanceSelector) history(keywords map[string]bool) map[string]fileHistory {
+	result := make(map[string]fileHistory)
+	if s.config.HistoryDepth < 0 {
+		return result
+	}
+
+	out, err := s.config.Runner.Run(s.workDir, "git", "log",
+		"-n", strconv.Itoa(s.config.HistoryDepth),
+		"--name-only", "--format=\x1e%s")
+	if err != nil {
+		slog.Debug("skipping git history for relevance",
+			slog.String("dir", s.workDir),
+			slog.String("error", err.Error()))
+		return result
+	}
+
+	for _, commit := range strings.Split(out, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(commit), "\n")
+		if len(lines) < 2 {
+			continue
+		}
+
+		matched := false
+		for _, word := range identifierWords(lines[0]) {
+			if keywords[word] {
+				matched = true
+				break
+			}
+		}
+		for _, file := range lines[1:] {
+			file = strings.TrimSpace(file)
+			if file == "" {
+				continue
+			}
+			h := result[file]
+			h.touches++
+			if matched {
+				h.matches++
+			}
+			result[file] = h
+		}
+	}
+	return result
+}
+
+// declPattern finds declared symbol names in common languages
+var declPattern = regexp.MustCompile(`(?m)^\s*(?:func\s+(?:\([^)]*\)\s*)?|type\s+|class\s+|def\s+|(?:export\s+)?(?:async\s+)?function\s+|interface\s+|struct\s+|enum\s+)([A-Za-z_][A-Za-z0-9_]*)`)
+
+// symbolPattern finds code-like identifiers in free text: CamelCase,
+// mixedCase, snake_case, or backquoted names
+var symbolPattern = regexp.MustCompile("`([A-Za-z_][A-Za-z0-9_.]*)`|\\b([A-Z][a-z0-9]+[A-Z][A-Za-z0-9]*|[a-z]+[A-Z][A-Za-z0-9]*|[a-z0-9]+_[a-z0-9_]+)\\b")
+
+// querySymbols extracts identifiers from query text
+func querySymbols(query string) map[string]bool {
+	symbols := make(map[string]bool)
+	for _, m := range symbolPattern.FindAllStringSubmatch(query, -1) {
+		sym := m[1] + m[2]
+		// "pkg.Name" declares Name
+		if i := strings.LastIndexByte(sym, '.'); i >= 0 {
+			sym = sym[i+1:]
+		}
+		if sym != "" {
+			symbols[sym] = true
+		}
+	}
+	return symbols
+}
+
+// queryKeywords extracts lowercased keywords from query text
+func queryKeywords(query string) map[string]bool {
+	keywords := make(map[string]bool)
+	for _, word := range identifierWords(query) {
+		if len(word) >= 3 && !stopWords[word] {
+			keywords[word] = true
+		}
+	}
+	return keywords
+}
+
+// identifierWords splits text into lowercase words, breaking identifiers at
+// case changes, digits, and punctuation ("TokenStore_v2.go" -> token, store,
+// v, go)
+func identifierWords(text string) []string {
+	var words []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) {
+			flush()
+			continue
+		}
+		// Break "tokenStore" before S, and "HTTPServer" before S
+		if unicode.IsUpper(r) && len(cur) > 0 {
+			prevLower := unicode.IsLower(cur[len(cur)-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return words
+}
+
+// stopWords are common words that carry no relevance signal
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true,
+	"this": true, "from": true, "into": true, "when": true, "then": true,
+	"should": true, "must": true, "will": true, "can": true, "not": true,
+	"are": true, "was": true, "were": true, "has": true, "have": true,
+	"add": true, "new": true, "use": true, "make": true, "all": true,
+	"any": true, "but": true, "its": true, "our": true, "their": true,
+	"them": true, "they": true, "you": true, "your": true, "also": true,
+	"some": true, "such": true, "only": true, "each": true, "more": true,
+	"than": true, "which": true, "what": true, "where": true, "how": true,
+	"implement": true, "support": true, "ticket": true, "file": true, "files": true,
+}
@@ -0,0 +1,202 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// RepoMapOptions configures RepoMap.
+type RepoMapOptions struct {
+	MaxDepth       int  // Directory levels expanded (default: 4)
+	MaxSymbols     int  // Symbols listed per file (default: 8)
+	MaxEntries     int  // Lines in the map before it is cut off (default: 500)
+	IncludeIgnored bool // Include files excluded by .gitignore/.devflowignore
+}
+
+// RepoMap returns a compact tree of workDir with file sizes and top-level
+// symbols, for cheap global orientation before detailed files:
+//
+//	auth/
+//	  jwt.go (4.1KB): JWTManager, NewJWTManager, JWTManager.Validate
+//	  store/ (6 files)
+//	main.go (312B): main
+//
+// Directories below MaxDepth are summarized by file count.
+func RepoMap(workDir string, opts RepoMapOptions) (string, error) {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 4
+	}
+	if opts.MaxSymbols <= 0 {
+		opts.MaxSymbols = 8
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 500
+	}
+
+	m := &repoMapper{workDir: workDir, opts: opts}
+	if !opts.IncludeIgnored {
+		m.ignore = NewIgnoreMatcher(workDir)
+	}
+	if err := m.walk("", 0); err != nil {
+		return "", err
+	}
+	if m.truncated {
+		m.sb.WriteString("[... truncated ...]\n")
+	}
+	return m.sb.String(), nil
+}
+
+// repoMapper accumulates the map while walking the tree
+type repoMapper struct {
+	workDir   string
+	opts      RepoMapOptions
+	ignore    *IgnoreMatcher
+	sb        strings.Builder
+	lines     int
+	truncated bool
+}
+
+// walk writes the entries of one directory, recursing until MaxDepth
+func (m *repoMapper) walk(rel string, depth int) error {
+	entries, err := os.ReadDir(filepath.Join(m.workDir, rel))
+	if err != nil {
+		return fmt.Errorf("read dir %s: %w", rel, err)
+	}
+
+	indent := strings.Repeat("  ", depth)
+	for _, e := range entries {
+		if m.lines >= m.opts.MaxEntries {
+			m.truncated = true
+			return nil
+		}
+
+		path := filepath.Join(rel, e.Name())
+		if m.ignore != nil && m.ignore.Ignored(path, e.IsDir()) {
+			continue
+		}
+
+		if e.IsDir() {
+			if depth+1 >= m.opts.MaxDepth {
+				n := m.countFiles(path)
+				if n == 1 {
+					m.line("%s%s/ (1 file)", indent, e.Name())
+				} else {
+					m.line("%s%s/ (%d files)", indent, e.Name(), n)
+				}
+				continue
+			}
+			m.line("%s%s/", indent, e.Name())
+			if err := m.walk(path, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		symbols := m.symbols(path, info.Size())
+		if len(symbols) > 0 {
+			m.line("%s%s (%s): %s", indent, e.Name(), formatSize(info.Size()), strings.Join(symbols, ", "))
+		} else {
+			m.line("%s%s (%s)", indent, e.Name(), formatSize(info.Size()))
+		}
+	}
+	return nil
+}
+
+func (m *repoMapper) line(format string, args ...any) {
+	fmt.Fprintf(&m.sb, format, args...)
+	m.sb.WriteByte('\n')
+	m.lines++
+}
+
+// countFiles counts non-ignored files under a collapsed directory
+func (m *repoMapper) countFiles(rel string) int {
+	count := 0
+	_ = filepath.WalkDir(filepath.Join(m.workDir, rel), func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		sub, relErr := filepath.Rel(m.workDir, path)
+		if relErr != nil {
+			return nil
+		}
+		if m.ignore != nil && m.ignore.Ignored(sub, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+// symbols lists a source file's top-level declarations. Large files are not
+// scanned.
+func (m *repoMapper) symbols(rel string, size int64) []string {
+	if !sourceExts[filepath.Ext(rel)] || size > DefaultContextLimits().MaxFileSize {
+		return nil
+	}
+	content, err := os.ReadFile(filepath.Join(m.workDir, rel))
+	if err != nil || isBinary(content) {
+		return nil
+	}
+
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, match := range topLevelPattern.FindAllSubmatch(content, -1) {
+		var name string
+		switch {
+		case len(match[1]) > 0: // Go method: Receiver.Name
+			name = string(match[1]) + "." + string(match[2])
+		default:
+			for _, g := range match[2:] {
+				if len(g) > 0 {
+					name = string(g)
+					break
+				}
+			}
+		}
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if len(symbols) == m.opts.MaxSymbols {
+			symbols = append(symbols, "...")
+			break
+		}
+		symbols = append(symbols, name)
+	}
+	return symbols
+}
+
+// sourceExts are the file types scanned for symbols
+var sourceExts = map[string]bool{
+	".go": true, ".py": true, ".js": true, ".jsx": true, ".mjs": true,
+	".ts": true, ".tsx": true, ".rb": true,
+}
+
+// topLevelPattern matches unindented declarations: Go funcs, methods, and
+// types; Python/JS/TS classes, functions, and defs
+var topLevelPattern = regexp.MustCompile(`(?m)^(?:func\s+(?:\(\s*\w*\s*\*?(\w+)[^)]*\)\s*)?(\w+)|type\s+(\w+)|class\s+(\w+)|def\s+(\w+)|(?:export\s+)?(?:default\s+)?(?:async\s+)?function\s+(\w+)|(?:export\s+)?(?:interface|enum)\s+(\w+))`)
+
+// formatSize renders a byte count compactly: 312B, 4.1KB, 2.0MB
+func formatSize(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%dB", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1fKB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1024*1024))
+	}
+}
@@ -2,24 +2,31 @@ package context
 
 import (
 	"context"
+	"path/filepath"
 
 	"github.com/randalmurphal/devflow/artifact"
 	"github.com/randalmurphal/devflow/git"
 	"github.com/randalmurphal/devflow/notify"
 	"github.com/randalmurphal/devflow/prompt"
+	"github.com/randalmurphal/devflow/task"
+	"github.com/randalmurphal/devflow/ticketing"
 	"github.com/randalmurphal/devflow/transcript"
 	"github.com/randalmurphal/llmkit/claude"
 )
 
 // Services wraps all devflow services for convenient initialization
 type Services struct {
-	Git         *git.Context
-	LLM         claude.Client // flowgraph claude.Client interface
-	Transcripts transcript.Manager
-	Artifacts   *artifact.Manager
-	Prompts     *prompt.Loader
-	Notifier    notify.Notifier    // Optional notification service
-	Runner      git.CommandRunner  // Optional command runner (defaults to ExecRunner)
+	Git          *git.Context
+	LLM          claude.Client // flowgraph claude.Client interface
+	Transcripts  transcript.Manager
+	Artifacts    *artifact.Manager
+	Prompts      *prompt.Loader
+	Notifier     notify.Notifier     // Optional notification service
+	Runner       git.CommandRunner   // Optional command runner (defaults to ExecRunner)
+	ContextCache *SectionCache       // Optional cache for built file context
+	Usage        *task.UsageRecorder // Optional per-task LLM usage tracking
+	Selector     *task.Selector      // Optional per-task model selection
+	Tickets      ticketing.Provider  // Optional issue tracker (Jira, GitHub, Linear)
 }
 
 // InjectAll adds all configured services to the context
@@ -45,6 +52,18 @@ func (s *Services) InjectAll(ctx context.Context) context.Context {
 	if s.Runner != nil {
 		ctx = WithRunner(ctx, s.Runner)
 	}
+	if s.ContextCache != nil {
+		ctx = WithContextCache(ctx, s.ContextCache)
+	}
+	if s.Usage != nil {
+		ctx = WithUsage(ctx, s.Usage)
+	}
+	if s.Selector != nil {
+		ctx = WithSelector(ctx, s.Selector)
+	}
+	if s.Tickets != nil {
+		ctx = ticketing.ContextWithProvider(ctx, s.Tickets)
+	}
 	return ctx
 }
 
@@ -113,5 +132,8 @@ func NewServices(cfg Config) (*Services, error) {
 	}
 	s.Prompts = prompt.NewLoader(promptDir)
 
+	// Cache built file context across nodes and runs
+	s.ContextCache = NewSectionCache(filepath.Join(baseDir, "context-cache"))
+
 	return s, nil
 }
@@ -0,0 +1,122 @@
+package context
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/randalmurphal/llmkit/tokens"
+)
+
+// Tokenizer counts tokens for a model family. llmkit's tokens.Counter
+// implementations satisfy it, so an exact tokenizer can be plugged in with
+// RegisterTokenizer.
+type Tokenizer = tokens.Counter
+
+// BPETokenizer approximates byte-pair encoding tokenizers by splitting text
+// the way they do: words into sub-word pieces, digits into groups of three,
+// and each punctuation character into its own token. It is much closer than
+// a characters-per-token ratio for source code, which is punctuation-heavy.
+type BPETokenizer struct {
+	WordPiece int // Average letters per sub-word token (default: 6)
+}
+
+// Count implements Tokenizer.
+func (t BPETokenizer) Count(text string) int {
+	piece := t.WordPiece
+	if piece <= 0 {
+		piece = 6
+	}
+
+	var count, word, digits, spaces int
+	flush := func() {
+		count += (word + piece - 1) / piece
+		count += (digits + 2) / 3
+		word, digits = 0, 0
+		// A single space merges with the next word; longer runs such as
+		// indentation become one token
+		if spaces > 1 {
+			count++
+		}
+		spaces = 0
+	}
+
+	for _, r := range text {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || r == '_'):
+			if digits > 0 || spaces > 0 {
+				flush()
+			}
+			word++
+		case unicode.IsDigit(r):
+			if word > 0 || spaces > 0 {
+				flush()
+			}
+			digits++
+		case r == ' ' || r == '\t':
+			if word > 0 || digits > 0 {
+				flush()
+			}
+			spaces++
+		default:
+			// Newlines, punctuation, and non-ASCII characters
+			flush()
+			count++
+		}
+	}
+	flush()
+	return count
+}
+
+// FitsInLimit implements Tokenizer.
+func (t BPETokenizer) FitsInLimit(text string, limit int) bool {
+	return t.Count(text) <= limit
+}
+
+var (
+	tokenizersMu sync.RWMutex
+	tokenizers   = map[string]Tokenizer{
+		"claude": BPETokenizer{WordPiece: 6},
+		"gpt":    BPETokenizer{WordPiece: 7},
+	}
+)
+
+// RegisterTokenizer sets the tokenizer for a model family. Models are matched
+// to families by prefix, so "claude" covers "claude-sonnet-4".
+func RegisterTokenizer(family string, t Tokenizer) {
+	tokenizersMu.Lock()
+	defer tokenizersMu.Unlock()
+	tokenizers[family] = t
+}
+
+// TokenizerFor returns the tokenizer for a model, using the longest matching
+// family prefix. Unknown models get DefaultTokenizer.
+func TokenizerFor(model string) Tokenizer {
+	tokenizersMu.RLock()
+	defer tokenizersMu.RUnlock()
+
+	var best string
+	var found Tokenizer
+	for family, t := range tokenizers {
+		if strings.HasPrefix(model, family) && len(family) > len(best) {
+			best, found = family, t
+		}
+	}
+	if found == nil {
+		return DefaultTokenizer()
+	}
+	return found
+}
+
+// DefaultTokenizer returns the tokenizer used when no model is set.
+func DefaultTokenizer() Tokenizer {
+	return BPETokenizer{WordPiece: 6}
+}
+
+// FileTokens is the token accounting for one file in a ContextBuilder.
+type FileTokens struct {
+	Path   string
+	Bytes  int64
+	Tokens int
+	Binary bool
+}
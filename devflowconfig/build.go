@@ -0,0 +1,151 @@
+package devflowconfig
+
+import (
+	"fmt"
+	"log/slog"
+
+	devcontext "github.com/randalmurphal/devflow/context"
+	"github.com/randalmurphal/devflow/jira"
+	"github.com/randalmurphal/devflow/notify"
+	"github.com/randalmurphal/devflow/task"
+	"github.com/randalmurphal/devflow/ticketing"
+	"github.com/randalmurphal/devflow/transcript"
+	"github.com/randalmurphal/devflow/workflow"
+)
+
+// NodeConfig returns the workflow node settings.
+func (c *Config) NodeConfig() workflow.NodeConfig {
+	return workflow.NodeConfig{
+		MaxReviewAttempts:      c.Nodes.MaxReviewAttempts,
+		TestCommand:            c.Nodes.TestCommand,
+		LintCommand:            c.Nodes.LintCommand,
+		BaseBranch:             c.Nodes.BaseBranch,
+		ChunkedReviewThreshold: c.Nodes.ChunkedReviewThreshold,
+	}
+}
+
+// Selector returns a model selector with the models settings applied.
+func (c *Config) Selector() (*task.Selector, error) {
+	return task.LoadConfig(c.resolved)
+}
+
+// RetentionPolicy returns the transcript retention settings.
+func (c *Config) RetentionPolicy() transcript.RetentionPolicy {
+	return transcript.RetentionPolicy{
+		MaxAge:     c.Retention.MaxAge,
+		MaxCount:   c.Retention.MaxCount,
+		MaxSize:    c.Retention.MaxSize,
+		KeepFailed: c.Retention.KeepFailed,
+	}
+}
+
+// TranscriptRetention returns a Retention for the runs under BaseDir.
+func (c *Config) TranscriptRetention() *transcript.Retention {
+	return &transcript.Retention{BaseDir: c.BaseDir, Policy: c.RetentionPolicy()}
+}
+
+// Notifier returns a notifier for every configured target, or nil if none
+// is configured.
+func (c *Config) Notifier() notify.Notifier {
+	var notifiers []notify.Notifier
+
+	if slack := c.Notify.Slack; slack.WebhookURL != "" || slack.Token != "" {
+		var opts []notify.SlackOption
+		if slack.Token != "" {
+			opts = append(opts, notify.WithSlackToken(slack.Token))
+		}
+		if slack.Channel != "" {
+			opts = append(opts, notify.WithSlackChannel(slack.Channel))
+		}
+		if slack.Threads {
+			opts = append(opts, notify.WithSlackThreads())
+		}
+		notifiers = append(notifiers, notify.NewSlackNotifier(slack.WebhookURL, opts...))
+	}
+
+	for _, hook := range c.Notify.Webhooks {
+		var opts []notify.WebhookOption
+		if hook.Secret != "" {
+			opts = append(opts, notify.WithWebhookSecret(hook.Secret))
+		}
+		notifiers = append(notifiers, notify.NewWebhookNotifier(hook.URL, hook.Headers, opts...))
+	}
+
+	if c.Notify.Log {
+		notifiers = append(notifiers, notify.NewLogNotifier(slog.Default()))
+	}
+
+	switch len(notifiers) {
+	case 0:
+		return nil
+	case 1:
+		return notifiers[0]
+	default:
+		return notify.NewMultiNotifier(notifiers...)
+	}
+}
+
+// TicketProvider returns the configured issue tracker, or nil if
+// tickets.provider is not set.
+func (c *Config) TicketProvider() (ticketing.Provider, error) {
+	t := c.Tickets
+	switch t.Provider {
+	case "":
+		return nil, nil
+	case "jira":
+		if t.Jira.URL == "" || t.Jira.Token == "" {
+			return nil, fmt.Errorf("%w: tickets.jira.url and tickets.jira.token", ErrMissingSetting)
+		}
+		cfg := jira.DefaultConfig()
+		cfg.URL = t.Jira.URL
+		cfg.Auth = jira.AuthConfig{Type: jira.AuthPAT, Token: t.Jira.Token}
+		if t.Jira.Email != "" {
+			cfg.Auth = jira.AuthConfig{Type: jira.AuthAPIToken, Email: t.Jira.Email, Token: t.Jira.Token}
+		}
+		client, err := jira.NewClient(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("jira: %w", err)
+		}
+		return ticketing.NewJiraProvider(client), nil
+	case "github":
+		provider, err := ticketing.NewGitHubProvider(t.GitHub.Token, t.GitHub.Owner, t.GitHub.Repo)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMissingSetting, err)
+		}
+		return provider, nil
+	case "linear":
+		provider, err := ticketing.NewLinearProvider(t.Linear.APIKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMissingSetting, err)
+		}
+		return provider, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTicketProvider, t.Provider)
+	}
+}
+
+// Services creates the devflow services for the repository, with the
+// configured notifier, ticket provider, and model selector. The LLM uses
+// models.default when set.
+func (c *Config) Services() (*devcontext.Services, error) {
+	services, err := devcontext.NewServices(devcontext.Config{
+		RepoPath:  c.repoPath,
+		BaseDir:   c.BaseDir,
+		PromptDir: c.PromptDir,
+		LLMModel:  c.Models["default"],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	services.Notifier = c.Notifier()
+	services.Selector, err = c.Selector()
+	if err != nil {
+		return nil, err
+	}
+	services.Tickets, err = c.TicketProvider()
+	if err != nil {
+		return nil, err
+	}
+	return services, nil
+}
@@ -0,0 +1,232 @@
+package devflowconfig
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/randalmurphal/devflow/config"
+	"github.com/randalmurphal/devflow/workflow"
+)
+
+// File and environment names for devflow configuration.
+const (
+	// LocalConfigName is the repository config file, merged from the git
+	// root down to the working directory.
+	LocalConfigName = ".devflow.yaml"
+
+	// GlobalConfigDir holds the user config: ~/.config/devflow/config.yaml.
+	GlobalConfigDir = "devflow"
+
+	// EnvPrefix prefixes environment overrides: DEVFLOW_NODES_BASE_BRANCH.
+	EnvPrefix = "DEVFLOW_"
+)
+
+// Config is the canonical devflow configuration:
+//
+//	base_dir: .devflow
+//	models:
+//	  default: sonnet
+//	  review: opus
+//	nodes:
+//	  test_command: go test -race ./...
+//	  base_branch: main
+//	retention:
+//	  max_age: 720h
+//	  keep_failed: true
+//	notify:
+//	  slack:
+//	    webhook_url: ${env:SLACK_WEBHOOK_URL}
+//	tickets:
+//	  provider: jira
+//	  jira:
+//	    url: https://example.atlassian.net
+//	    email: bot@example.com
+//	    token: ${secret:vault/jira#token}
+//
+// Use the builder methods (NodeConfig, Selector, Notifier, TicketProvider,
+// Services) rather than reading the settings directly.
+type Config struct {
+	BaseDir   string            `config:"base_dir"`   // Storage for transcripts and artifacts
+	PromptDir string            `config:"prompt_dir"` // Prompt templates
+	Models    map[string]string `config:"models"`     // Task type or tier -> model; see task.LoadConfig
+	Nodes     NodeSettings      `config:"nodes"`
+	Retention RetentionSettings `config:"retention"`
+	Notify    NotifySettings    `config:"notify"`
+	Tickets   TicketSettings    `config:"tickets"`
+
+	resolved *config.Resolved
+	repoPath string
+}
+
+// NodeSettings configures workflow nodes; see workflow.NodeConfig.
+type NodeSettings struct {
+	MaxReviewAttempts      int    `config:"max_review_attempts"`
+	TestCommand            string `config:"test_command"`
+	LintCommand            string `config:"lint_command"`
+	BaseBranch             string `config:"base_branch"`
+	ChunkedReviewThreshold int    `config:"chunked_review_threshold"` // Bytes; negative disables
+}
+
+// RetentionSettings configures transcript retention; see
+// transcript.RetentionPolicy. Zero values disable a limit.
+type RetentionSettings struct {
+	MaxAge     time.Duration `config:"max_age"`
+	MaxCount   int           `config:"max_count"`
+	MaxSize    int64         `config:"max_size"` // Bytes
+	KeepFailed bool          `config:"keep_failed"`
+}
+
+// NotifySettings configures notifiers. Every one configured receives
+// every event.
+type NotifySettings struct {
+	Slack    SlackSettings     `config:"slack"`
+	Webhooks []WebhookSettings `config:"webhooks"`
+	Log      bool              `config:"log"` // Also log events with slog
+}
+
+// SlackSettings configures Slack notifications, by webhook or bot token.
+type SlackSettings struct {
+	WebhookURL string `config:"webhook_url"`
+	Token      string `config:"token"`   // Bot token, for threads and chat.postMessage
+	Channel    string `config:"channel"` // Overrides the webhook's channel
+	Threads    bool   `config:"threads"` // Thread a flow's events (requires Token)
+}
+
+// WebhookSettings configures one generic webhook.
+type WebhookSettings struct {
+	URL     string            `config:"url"`
+	Secret  string            `config:"secret"` // HMAC signing secret
+	Headers map[string]string `config:"headers"`
+}
+
+// TicketSettings selects and configures the issue tracker.
+type TicketSettings struct {
+	Provider string         `config:"provider"` // jira, github, or linear
+	Jira     JiraSettings   `config:"jira"`
+	GitHub   GitHubSettings `config:"github"`
+	Linear   LinearSettings `config:"linear"`
+}
+
+// JiraSettings configures Jira. Email selects API token auth (Cloud);
+// without it Token is sent as a personal access token (Server/DC).
+type JiraSettings struct {
+	URL   string `config:"url"`
+	Email string `config:"email"`
+	Token string `config:"token"`
+}
+
+// GitHubSettings configures GitHub Issues.
+type GitHubSettings struct {
+	Token string `config:"token"`
+	Owner string `config:"owner"`
+	Repo  string `config:"repo"`
+}
+
+// LinearSettings configures Linear.
+type LinearSettings struct {
+	APIKey string `config:"api_key"`
+}
+
+// Options configures Load.
+type Options struct {
+	// RepoPath is the repository root. Defaults to the git root of the
+	// working directory.
+	RepoPath string
+
+	// Profile selects a profiles.<name> section; DEVFLOW_PROFILE or a
+	// "profile" flag also select one.
+	Profile string
+
+	// Flags are command-line overrides, keyed like config keys.
+	Flags map[string]string
+
+	// Secrets resolves ${secret:...} references, e.g. in ticket tokens.
+	Secrets config.SecretProvider
+
+	// TrustLocalReferences allows references in the repository's
+	// .devflow.yaml; by default only the global config may use them.
+	TrustLocalReferences bool
+
+	// ErrWriter receives config warnings. Defaults to os.Stderr.
+	ErrWriter io.Writer
+}
+
+// Defaults returns the default values for devflow settings.
+func Defaults() map[string]string {
+	nodes := workflow.DefaultNodeConfig()
+	return map[string]string{
+		"base_dir":                       ".devflow",
+		"prompt_dir":                     ".devflow/prompts",
+		"nodes.max_review_attempts":      strconv.Itoa(nodes.MaxReviewAttempts),
+		"nodes.test_command":             nodes.TestCommand,
+		"nodes.lint_command":             nodes.LintCommand,
+		"nodes.base_branch":              nodes.BaseBranch,
+		"nodes.chunked_review_threshold": strconv.Itoa(nodes.ChunkedReviewThreshold),
+	}
+}
+
+// Schema returns the validation rules for devflow settings.
+func Schema() *config.Schema {
+	return config.NewSchema().
+		OneOf("tickets.provider", "jira", "github", "linear").
+		Range("nodes.max_review_attempts", 1, math.Inf(1)).
+		Range("retention.max_count", 0, math.Inf(1)).
+		Range("retention.max_size", 0, math.Inf(1))
+}
+
+// NewResolver creates the config resolver for devflow's files, for callers
+// that want Watch or Explain as well as Load.
+func NewResolver(opts Options) *config.Resolver {
+	cfg := config.ResolverConfig{
+		EnvPrefix:            EnvPrefix,
+		GlobalConfigDir:      GlobalConfigDir,
+		LocalConfigName:      LocalConfigName,
+		MergeLocalConfigs:    true,
+		Defaults:             Defaults(),
+		Schema:               Schema(),
+		Secrets:              opts.Secrets,
+		TrustLocalReferences: opts.TrustLocalReferences,
+		Profile:              opts.Profile,
+		ErrWriter:            opts.ErrWriter,
+	}
+	if opts.RepoPath != "" {
+		repoPath := opts.RepoPath
+		cfg.GitRootFinder = func(string) (string, error) { return repoPath, nil }
+	}
+	return config.NewResolver(cfg)
+}
+
+// Load resolves and decodes the devflow configuration. Schema violations
+// and undecodable values are returned together; the returned Config holds
+// everything that could be read either way.
+func Load(opts Options) (*Config, error) {
+	resolver := NewResolver(opts)
+	resolved := resolver.ResolveWithFlags(opts.Flags)
+
+	cfg := &Config{resolved: resolved, repoPath: opts.RepoPath}
+	if cfg.repoPath == "" {
+		cfg.repoPath = resolver.GitRoot()
+	}
+	if cfg.repoPath == "" {
+		cfg.repoPath = "."
+	}
+
+	err := resolved.Unmarshal(cfg)
+	if resolved.Err() != nil {
+		err = errors.Join(resolved.Err(), err)
+	}
+	return cfg, err
+}
+
+// Resolved returns the underlying resolved values, for Explain and sources.
+func (c *Config) Resolved() *config.Resolved {
+	return c.resolved
+}
+
+// RepoPath returns the repository root the configuration was loaded for.
+func (c *Config) RepoPath() string {
+	return c.repoPath
+}
@@ -0,0 +1,181 @@
+package devflowconfig
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/randalmurphal/devflow/config"
+	"github.com/randalmurphal/devflow/notify"
+	"github.com/randalmurphal/devflow/task"
+	"github.com/randalmurphal/devflow/workflow"
+	"github.com/randalmurphal/llmkit/model"
+)
+
+const testConfig = `models:
+  review: opus
+nodes:
+  test_command: make test
+retention:
+  max_age: 720h
+  max_count: 50
+  keep_failed: true
+notify:
+  slack:
+    webhook_url: https://hooks.slack.example/T1
+  webhooks:
+    - url: https://ci.example.com/hook
+      secret: ${env:DEVFLOWCONFIG_TEST_SECRET}
+      headers:
+        X-Team: platform
+tickets:
+  provider: github
+  github:
+    token: ${env:DEVFLOWCONFIG_TEST_TOKEN}
+    owner: acme
+    repo: widgets
+`
+
+// loadTest loads data as the repository config, isolated from the user's
+// global config
+func loadTest(t *testing.T, data string, opts Options) (*Config, error) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	repo := t.TempDir()
+	os.WriteFile(filepath.Join(repo, LocalConfigName), []byte(data), 0644)
+
+	opts.RepoPath = repo
+	if opts.ErrWriter == nil {
+		opts.ErrWriter = &bytes.Buffer{}
+	}
+	return Load(opts)
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("DEVFLOWCONFIG_TEST_SECRET", "s3cret")
+	t.Setenv("DEVFLOWCONFIG_TEST_TOKEN", "ghp_test")
+	t.Setenv("DEVFLOW_NODES_BASE_BRANCH", "develop")
+
+	cfg, err := loadTest(t, testConfig, Options{TrustLocalReferences: true})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	defaults := workflow.DefaultNodeConfig()
+	wantNodes := workflow.NodeConfig{
+		MaxReviewAttempts:      defaults.MaxReviewAttempts,
+		TestCommand:            "make test",
+		LintCommand:            defaults.LintCommand,
+		BaseBranch:             "develop",
+		ChunkedReviewThreshold: defaults.ChunkedReviewThreshold,
+	}
+	if got := cfg.NodeConfig(); got != wantNodes {
+		t.Errorf("NodeConfig() = %+v, want %+v", got, wantNodes)
+	}
+	if cfg.BaseDir != ".devflow" {
+		t.Errorf("BaseDir = %q, want .devflow", cfg.BaseDir)
+	}
+
+	policy := cfg.RetentionPolicy()
+	if policy.MaxAge != 720*time.Hour || policy.MaxCount != 50 || !policy.KeepFailed {
+		t.Errorf("RetentionPolicy() = %+v", policy)
+	}
+
+	if got := cfg.Notify.Webhooks; len(got) != 1 || got[0].Secret != "s3cret" || got[0].Headers["X-Team"] != "platform" {
+		t.Errorf("Webhooks = %+v", got)
+	}
+	if _, ok := cfg.Notifier().(*notify.MultiNotifier); !ok {
+		t.Errorf("Notifier() = %T, want *notify.MultiNotifier for Slack and webhook", cfg.Notifier())
+	}
+
+	provider, err := cfg.TicketProvider()
+	if err != nil {
+		t.Fatalf("TicketProvider() error = %v", err)
+	}
+	if provider.Name() != "github" {
+		t.Errorf("TicketProvider().Name() = %q, want github", provider.Name())
+	}
+
+	selector, err := cfg.Selector()
+	if err != nil {
+		t.Fatalf("Selector() error = %v", err)
+	}
+	if got := selector.Select(task.Review); got != model.ModelOpus {
+		t.Errorf("Select(review) = %q, want opus", got)
+	}
+
+	if got := cfg.Resolved().Source("nodes.base_branch"); got != config.SourceEnv {
+		t.Errorf("nodes.base_branch source = %q, want env", got)
+	}
+}
+
+func TestLoad_LocalReferencesRefused(t *testing.T) {
+	t.Setenv("DEVFLOWCONFIG_TEST_SECRET", "s3cret")
+	t.Setenv("DEVFLOWCONFIG_TEST_TOKEN", "ghp_test")
+
+	cfg, err := loadTest(t, testConfig, Options{})
+	if !errors.Is(err, config.ErrUntrustedReference) {
+		t.Fatalf("Load() error = %v, want ErrUntrustedReference", err)
+	}
+	if got := cfg.Notify.Webhooks; len(got) != 1 || got[0].Secret != "" {
+		t.Errorf("Webhooks = %+v, want secret left unset", got)
+	}
+}
+
+func TestLoad_Empty(t *testing.T) {
+	cfg, err := loadTest(t, "", Options{})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.NodeConfig(); got != workflow.DefaultNodeConfig() {
+		t.Errorf("NodeConfig() = %+v, want defaults", got)
+	}
+	if cfg.Notifier() != nil {
+		t.Errorf("Notifier() = %T, want nil", cfg.Notifier())
+	}
+	if provider, err := cfg.TicketProvider(); provider != nil || err != nil {
+		t.Errorf("TicketProvider() = %v, %v, want nil, nil", provider, err)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	cfg, err := loadTest(t, "tickets:\n  provider: trello\nnodes:\n  max_review_attempts: 0\n", Options{})
+
+	var validation *config.ValidationError
+	if !errors.As(err, &validation) || len(validation.Problems) != 2 {
+		t.Fatalf("Load() error = %v, want 2 schema problems", err)
+	}
+	if _, err := cfg.TicketProvider(); !errors.Is(err, ErrUnknownTicketProvider) {
+		t.Errorf("TicketProvider() error = %v, want ErrUnknownTicketProvider", err)
+	}
+}
+
+func TestLoad_ProfileAndMissingSettings(t *testing.T) {
+	data := `tickets:
+  provider: linear
+profiles:
+  jira:
+    tickets:
+      provider: jira
+      jira:
+        url: https://example.atlassian.net
+`
+	cfg, err := loadTest(t, data, Options{Profile: "jira"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Tickets.Provider != "jira" {
+		t.Errorf("provider = %q, want jira from profile", cfg.Tickets.Provider)
+	}
+	if _, err := cfg.TicketProvider(); !errors.Is(err, ErrMissingSetting) {
+		t.Errorf("TicketProvider() error = %v, want ErrMissingSetting", err)
+	}
+
+	cfg, _ = loadTest(t, data, Options{})
+	if _, err := cfg.TicketProvider(); !errors.Is(err, ErrMissingSetting) {
+		t.Errorf("linear without key: error = %v, want ErrMissingSetting", err)
+	}
+}
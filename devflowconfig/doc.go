@@ -0,0 +1,36 @@
+// Package devflowconfig loads the canonical devflow configuration and
+// builds ready-to-use services from it, so consumers do not each write
+// their own glue between config files and devflow's packages.
+//
+// Configuration is resolved with the config package from, lowest to
+// highest precedence:
+//   - Built-in defaults (Defaults)
+//   - ~/.config/devflow/config.yaml
+//   - .devflow.yaml files from the git root down to the working directory
+//   - The selected profile (profiles.<name>)
+//   - DEVFLOW_* environment variables and flags
+//
+// Example usage:
+//
+//	cfg, err := devflowconfig.Load(devflowconfig.Options{RepoPath: repo})
+//	if err != nil {
+//	    return err
+//	}
+//	services, err := cfg.Services() // Notifier and Tickets included
+//	ctx = services.InjectAll(ctx)
+//	nodes := cfg.NodeConfig()
+//	selector, err := cfg.Selector()
+//
+// Secrets belong in references, not in the file:
+//
+//	tickets:
+//	  provider: jira
+//	  jira:
+//	    url: https://example.atlassian.net
+//	    email: bot@example.com
+//	    token: ${env:JIRA_TOKEN}
+//
+// References are only expanded in the global config unless
+// Options.TrustLocalReferences is set, since a cloned repository's
+// .devflow.yaml could otherwise read the user's environment.
+package devflowconfig
@@ -0,0 +1,14 @@
+package devflowconfig
+
+import "errors"
+
+// Configuration errors.
+var (
+	// ErrUnknownTicketProvider is returned when tickets.provider names no
+	// supported tracker.
+	ErrUnknownTicketProvider = errors.New("unknown ticket provider")
+
+	// ErrMissingSetting is returned when a setting the configured features
+	// need is empty.
+	ErrMissingSetting = errors.New("missing setting")
+)
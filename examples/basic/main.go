@@ -179,7 +179,8 @@ func transcriptExample(baseDir string) {
 		fmt.Printf("Error recording turn: %v\n", err)
 	}
 
-	// Add cost tracking (available on FileStore)
+	// Turn costs are counted automatically; AddCost covers usage outside
+	// any turn, such as embeddings (available on FileStore)
 	_ = store.AddCost(runID, 0.001)
 
 	// End the run
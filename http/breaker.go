@@ -0,0 +1,364 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultFailureThreshold is the number of consecutive failures that opens
+// a host's circuit.
+const DefaultFailureThreshold = 5
+
+// DefaultOpenTimeout is how long a circuit stays open before probing.
+const DefaultOpenTimeout = 30 * time.Second
+
+// ErrCircuitOpen indicates a request was rejected without being sent
+// because its host's circuit is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// BreakerState is the state of one host's circuit.
+type BreakerState int
+
+// Circuit states.
+const (
+	// StateClosed lets requests through and counts failures.
+	StateClosed BreakerState = iota
+
+	// StateOpen rejects requests until the open timeout passes.
+	StateOpen
+
+	// StateHalfOpen lets a limited number of probe requests through.
+	StateHalfOpen
+)
+
+// String returns the state name.
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+}
+
+// CircuitOpenError is returned for requests rejected by an open circuit.
+type CircuitOpenError struct {
+	// Host is the host whose circuit is open.
+	Host string
+
+	// RetryAt is when the circuit will next let a probe through.
+	RetryAt time.Time
+}
+
+// Error implements the error interface.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for %s until %s", e.Host, e.RetryAt.Format(time.RFC3339))
+}
+
+// Unwrap returns ErrCircuitOpen.
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// BreakerConfig configures a CircuitBreaker.
+type BreakerConfig struct {
+	// FailureThreshold is the consecutive failures that open a circuit
+	// (default: DefaultFailureThreshold).
+	FailureThreshold int
+
+	// OpenTimeout is how long an open circuit rejects requests before
+	// moving to half-open (default: DefaultOpenTimeout).
+	OpenTimeout time.Duration
+
+	// HalfOpenProbes is the number of successful probes that close a
+	// half-open circuit, and the most probes allowed in flight (default: 1).
+	HalfOpenProbes int
+
+	// IsFailure decides whether an outcome counts against the host. The
+	// default counts network errors and 5xx responses; 4xx responses,
+	// including 429, show the host is up.
+	IsFailure func(resp *http.Response, err error) bool
+
+	// OnStateChange is called, outside the breaker's lock, when a host's
+	// circuit changes state.
+	OnStateChange func(host string, from, to BreakerState)
+}
+
+// BreakerStats is a snapshot of one host's circuit for metrics.
+type BreakerStats struct {
+	Host                string
+	State               BreakerState
+	ConsecutiveFailures int
+	Successes           int64
+	Failures            int64
+	Rejected            int64     // Requests refused while open
+	OpenedAt            time.Time // Zero unless open or half-open
+}
+
+// CircuitBreaker tracks the health of each host separately, so a down Jira
+// does not block GitHub requests. It is safe for concurrent use.
+type CircuitBreaker struct {
+	cfg   BreakerConfig
+	now   func() time.Time
+	mu    sync.Mutex
+	hosts map[string]*hostCircuit
+}
+
+// hostCircuit is the circuit state for one host
+type hostCircuit struct {
+	state               BreakerState
+	consecutiveFailures int
+	probes              int // Probes in flight while half-open
+	probeSuccesses      int
+	openedAt            time.Time
+	successes           int64
+	failures            int64
+	rejected            int64
+}
+
+// NewCircuitBreaker creates a circuit breaker, applying defaults.
+func NewCircuitBreaker(cfg BreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = DefaultFailureThreshold
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = DefaultOpenTimeout
+	}
+	if cfg.HalfOpenProbes <= 0 {
+		cfg.HalfOpenProbes = 1
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = isBreakerFailure
+	}
+	return &CircuitBreaker{
+		cfg:   cfg,
+		now:   time.Now,
+		hosts: make(map[string]*hostCircuit),
+	}
+}
+
+// Allow asks whether a request to host may be sent. If so, the caller must
+// call done with the request's outcome; otherwise it returns a
+// *CircuitOpenError. An outcome of context.Canceled is not counted.
+func (b *CircuitBreaker) Allow(host string) (done func(resp *http.Response, err error), err error) {
+	b.mu.Lock()
+	c := b.circuit(host)
+	now := b.now()
+	from := c.state
+
+	if c.state == StateOpen && now.Sub(c.openedAt) >= b.cfg.OpenTimeout {
+		c.state = StateHalfOpen
+		c.probes, c.probeSuccesses = 0, 0
+	}
+
+	probe := false
+	switch c.state {
+	case StateOpen:
+		c.rejected++
+		retryAt := c.openedAt.Add(b.cfg.OpenTimeout)
+		b.mu.Unlock()
+		return nil, &CircuitOpenError{Host: host, RetryAt: retryAt}
+	case StateHalfOpen:
+		if c.probes >= b.cfg.HalfOpenProbes {
+			c.rejected++
+			b.mu.Unlock()
+			b.notify(host, from, StateHalfOpen)
+			return nil, &CircuitOpenError{Host: host, RetryAt: now}
+		}
+		c.probes++
+		probe = true
+	}
+	to := c.state
+	b.mu.Unlock()
+	b.notify(host, from, to)
+
+	var once sync.Once
+	return func(resp *http.Response, err error) {
+		once.Do(func() {
+			if errors.Is(err, context.Canceled) {
+				// The caller gave up; that says nothing about the host
+				b.release(host, probe)
+				return
+			}
+			b.record(host, probe, b.cfg.IsFailure(resp, err))
+		})
+	}, nil
+}
+
+// record applies the outcome of one allowed request
+func (b *CircuitBreaker) record(host string, probe, failed bool) {
+	b.mu.Lock()
+	c := b.circuit(host)
+	from := c.state
+
+	if probe && c.probes > 0 {
+		c.probes--
+	}
+
+	if failed {
+		c.failures++
+		c.consecutiveFailures++
+		if c.state == StateHalfOpen || c.consecutiveFailures >= b.cfg.FailureThreshold {
+			c.state = StateOpen
+			c.openedAt = b.now()
+		}
+	} else {
+		c.successes++
+		c.consecutiveFailures = 0
+		if c.state == StateHalfOpen && probe {
+			c.probeSuccesses++
+			if c.probeSuccesses >= b.cfg.HalfOpenProbes {
+				c.state = StateClosed
+				c.openedAt = time.Time{}
+			}
+		}
+	}
+	to := c.state
+	b.mu.Unlock()
+	b.notify(host, from, to)
+}
+
+// release frees a probe slot without recording an outcome
+func (b *CircuitBreaker) release(host string, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c := b.circuit(host); probe && c.probes > 0 {
+		c.probes--
+	}
+}
+
+// circuit returns host's circuit, creating it closed; b.mu must be held
+func (b *CircuitBreaker) circuit(host string) *hostCircuit {
+	c, ok := b.hosts[host]
+	if !ok {
+		c = &hostCircuit{}
+		b.hosts[host] = c
+	}
+	return c
+}
+
+// notify reports a state change, if there was one
+func (b *CircuitBreaker) notify(host string, from, to BreakerState) {
+	if from != to && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(host, from, to)
+	}
+}
+
+// State returns host's current state. An open circuit whose timeout has
+// passed reports half-open.
+func (b *CircuitBreaker) State(host string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.hosts[host]
+	if !ok {
+		return StateClosed
+	}
+	return b.effectiveState(c)
+}
+
+// effectiveState accounts for an elapsed open timeout; b.mu must be held
+func (b *CircuitBreaker) effectiveState(c *hostCircuit) BreakerState {
+	if c.state == StateOpen && b.now().Sub(c.openedAt) >= b.cfg.OpenTimeout {
+		return StateHalfOpen
+	}
+	return c.state
+}
+
+// Stats returns a snapshot of every host seen, sorted by host.
+func (b *CircuitBreaker) Stats() []BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := make([]BreakerStats, 0, len(b.hosts))
+	for host, c := range b.hosts {
+		stats = append(stats, BreakerStats{
+			Host:                host,
+			State:               b.effectiveState(c),
+			ConsecutiveFailures: c.consecutiveFailures,
+			Successes:           c.successes,
+			Failures:            c.failures,
+			Rejected:            c.rejected,
+			OpenedAt:            c.openedAt,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Host < stats[j].Host })
+	return stats
+}
+
+// Reset closes host's circuit and clears its counters.
+func (b *CircuitBreaker) Reset(host string) {
+	b.mu.Lock()
+	c, ok := b.hosts[host]
+	from := StateClosed
+	if ok {
+		from = c.state
+		delete(b.hosts, host)
+	}
+	b.mu.Unlock()
+	b.notify(host, from, StateClosed)
+}
+
+// isBreakerFailure is the default BreakerConfig.IsFailure
+func isBreakerFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp != nil && resp.StatusCode >= 500
+}
+
+// BreakerTransport is an http.RoundTripper that rejects requests to hosts
+// whose circuit is open and records the outcome of the rest.
+type BreakerTransport struct {
+	// Base performs each request (http.DefaultTransport if nil).
+	Base http.RoundTripper
+
+	Breaker *CircuitBreaker
+}
+
+// NewBreakerTransport wraps base with breaker.
+func NewBreakerTransport(base http.RoundTripper, breaker *CircuitBreaker) *BreakerTransport {
+	return &BreakerTransport{Base: base, Breaker: breaker}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *BreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	done, err := t.Breaker.Allow(req.URL.Host)
+	if err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+
+	resp, err := base.RoundTrip(req)
+	done(resp, err)
+	return resp, err
+}
+
+// WithCircuitBreaker returns a copy of client whose transport goes through
+// breaker. Apply it before WithRetry so each attempt is counted and an open
+// circuit ends the retries:
+//
+//	client := devhttp.WithRetry(devhttp.WithCircuitBreaker(base, breaker), policy)
+func WithCircuitBreaker(client *http.Client, breaker *CircuitBreaker) *http.Client {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	wrapped := *client
+	wrapped.Transport = NewBreakerTransport(client.Transport, breaker)
+	return &wrapped
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable time source for breaker tests
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestBreaker(cfg BreakerConfig) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewCircuitBreaker(cfg)
+	b.now = clock.now
+	return b, clock
+}
+
+// outcome runs one request through b with the given result
+func outcome(t *testing.T, b *CircuitBreaker, host string, status int, err error) error {
+	t.Helper()
+	done, allowErr := b.Allow(host)
+	if allowErr != nil {
+		return allowErr
+	}
+	var resp *http.Response
+	if err == nil {
+		resp = &http.Response{StatusCode: status}
+	}
+	done(resp, err)
+	return nil
+}
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	b, _ := newTestBreaker(BreakerConfig{FailureThreshold: 3})
+
+	for range 3 {
+		if err := outcome(t, b, "jira", 500, nil); err != nil {
+			t.Fatalf("Allow while closed: %v", err)
+		}
+	}
+	if got := b.State("jira"); got != StateOpen {
+		t.Fatalf("State = %v, want open", got)
+	}
+
+	err := outcome(t, b, "jira", 200, nil)
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want *CircuitOpenError", err)
+	}
+	if openErr.Host != "jira" {
+		t.Errorf("Host = %q, want jira", openErr.Host)
+	}
+
+	// Other hosts are unaffected
+	if err := outcome(t, b, "github", 200, nil); err != nil {
+		t.Errorf("other host rejected: %v", err)
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsCount(t *testing.T) {
+	b, _ := newTestBreaker(BreakerConfig{FailureThreshold: 2})
+
+	_ = outcome(t, b, "h", 0, errors.New("reset"))
+	_ = outcome(t, b, "h", 404, nil) // 4xx means the host is up
+	_ = outcome(t, b, "h", 502, nil)
+
+	if got := b.State("h"); got != StateClosed {
+		t.Errorf("State = %v, want closed", got)
+	}
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	var changes []string
+	b, clock := newTestBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      time.Minute,
+		OnStateChange: func(host string, from, to BreakerState) {
+			changes = append(changes, from.String()+"->"+to.String())
+		},
+	})
+
+	_ = outcome(t, b, "h", 503, nil)
+	clock.t = clock.t.Add(time.Minute)
+
+	if got := b.State("h"); got != StateHalfOpen {
+		t.Fatalf("State = %v, want half-open", got)
+	}
+
+	// One probe at a time
+	done, err := b.Allow("h")
+	if err != nil {
+		t.Fatalf("probe rejected: %v", err)
+	}
+	if _, err := b.Allow("h"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second probe err = %v, want ErrCircuitOpen", err)
+	}
+
+	// A failed probe reopens
+	done(nil, errors.New("refused"))
+	if got := b.State("h"); got != StateOpen {
+		t.Fatalf("State after failed probe = %v, want open", got)
+	}
+
+	// A successful probe closes
+	clock.t = clock.t.Add(time.Minute)
+	if err := outcome(t, b, "h", 200, nil); err != nil {
+		t.Fatalf("probe rejected: %v", err)
+	}
+	if got := b.State("h"); got != StateClosed {
+		t.Errorf("State after probe = %v, want closed", got)
+	}
+
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("changes[%d] = %q, want %q", i, changes[i], want[i])
+		}
+	}
+}
+
+func TestCircuitBreaker_CanceledNotCounted(t *testing.T) {
+	b, _ := newTestBreaker(BreakerConfig{FailureThreshold: 1})
+
+	_ = outcome(t, b, "h", 0, context.Canceled)
+	if got := b.State("h"); got != StateClosed {
+		t.Errorf("State = %v, want closed", got)
+	}
+}
+
+func TestCircuitBreaker_Stats(t *testing.T) {
+	b, _ := newTestBreaker(BreakerConfig{FailureThreshold: 1})
+
+	_ = outcome(t, b, "b.example", 200, nil)
+	_ = outcome(t, b, "a.example", 500, nil)
+	_ = outcome(t, b, "a.example", 200, nil) // Rejected
+
+	stats := b.Stats()
+	if len(stats) != 2 || stats[0].Host != "a.example" {
+		t.Fatalf("Stats = %+v, want a.example then b.example", stats)
+	}
+	a := stats[0]
+	if a.State != StateOpen || a.Failures != 1 || a.Rejected != 1 || a.OpenedAt.IsZero() {
+		t.Errorf("a.example = %+v", a)
+	}
+	if stats[1].Successes != 1 {
+		t.Errorf("b.example Successes = %d, want 1", stats[1].Successes)
+	}
+
+	b.Reset("a.example")
+	if got := b.State("a.example"); got != StateClosed {
+		t.Errorf("State after Reset = %v, want closed", got)
+	}
+}
+
+func TestBreakerTransport_StopsRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	breaker := NewCircuitBreaker(BreakerConfig{FailureThreshold: 2})
+	client := WithRetry(WithCircuitBreaker(server.Client(), breaker), fastPolicy(5))
+
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2 (retries end when the circuit opens)", got)
+	}
+}
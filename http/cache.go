@@ -0,0 +1,293 @@
+package http
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCacheEntries is the default capacity of a MemoryCache.
+const DefaultCacheEntries = 256
+
+// DefaultCacheMaxBody is the largest response body a CacheTransport stores.
+const DefaultCacheMaxBody = 1 << 20
+
+// CacheStatusHeader is set on responses served by a CacheTransport:
+// "revalidated" when a 304 was answered from the cache, "miss" otherwise.
+const CacheStatusHeader = "X-Devflow-Cache"
+
+// CachedResponse is a stored GET response and its validators.
+type CachedResponse struct {
+	StatusCode   int
+	Header       http.Header
+	Body         []byte
+	ETag         string
+	LastModified string
+	StoredAt     time.Time
+}
+
+// CacheStore holds cached responses by key. Implementations must be safe
+// for concurrent use.
+type CacheStore interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, resp *CachedResponse)
+	Delete(key string)
+}
+
+// MemoryCache is an in-memory CacheStore that evicts the least recently
+// used entry when full.
+type MemoryCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List // Front is most recently used
+	entries map[string]*list.Element
+}
+
+// memoryEntry is one MemoryCache element
+type memoryEntry struct {
+	key  string
+	resp *CachedResponse
+}
+
+// NewMemoryCache creates a cache holding up to maxEntries responses
+// (default: DefaultCacheEntries).
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheEntries
+	}
+	return &MemoryCache{max: maxEntries, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Get returns the response stored under key.
+func (c *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*memoryEntry).resp, true
+}
+
+// Set stores resp under key, evicting the oldest entry if full.
+func (c *MemoryCache) Set(key string, resp *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*memoryEntry).resp = resp
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, resp: resp})
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// Delete removes key.
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of cached responses.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// CacheStats counts CacheTransport outcomes.
+type CacheStats struct {
+	Revalidated int64 // 304s answered from the cache
+	Misses      int64 // GETs that fetched a full response
+	Stored      int64 // Responses added or refreshed
+}
+
+// CacheTransport is an http.RoundTripper that stores GET responses carrying
+// an ETag or Last-Modified header and revalidates them with If-None-Match
+// and If-Modified-Since. A 304 is answered from the cache, which saves the
+// body transfer and, for APIs such as GitHub's, rate limit quota.
+//
+// Entries are keyed by URL, Accept, and a hash of the Authorization header.
+// Credentials added by an inner transport (such as oauth2's) are not seen,
+// so give each set of credentials its own store.
+type CacheTransport struct {
+	// Base performs each request (http.DefaultTransport if nil).
+	Base http.RoundTripper
+
+	Store CacheStore
+
+	// MaxBodyBytes is the largest body stored (default: DefaultCacheMaxBody).
+	MaxBodyBytes int
+
+	revalidated atomic.Int64
+	misses      atomic.Int64
+	stored      atomic.Int64
+}
+
+// NewCacheTransport wraps base with conditional request caching in store
+// (a new MemoryCache if nil).
+func NewCacheTransport(base http.RoundTripper, store CacheStore) *CacheTransport {
+	if store == nil {
+		store = NewMemoryCache(0)
+	}
+	return &CacheTransport{Base: base, Store: store}
+}
+
+// WithCache returns a copy of client that caches GET responses in store.
+// Apply it outside WithRetry so only the final response is cached:
+//
+//	client := devhttp.WithCache(devhttp.WithRetry(base, policy), nil)
+func WithCache(client *http.Client, store CacheStore) *http.Client {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	wrapped := *client
+	wrapped.Transport = NewCacheTransport(client.Transport, store)
+	return &wrapped
+}
+
+// Stats returns the transport's counters.
+func (t *CacheTransport) Stats() CacheStats {
+	return CacheStats{
+		Revalidated: t.revalidated.Load(),
+		Misses:      t.misses.Load(),
+		Stored:      t.stored.Load(),
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	key := cacheKey(req)
+	if req.Method != http.MethodGet && req.Method != "" {
+		resp, err := base.RoundTrip(req)
+		if err == nil && resp.StatusCode < 400 && req.Method != http.MethodHead && req.Method != http.MethodOptions {
+			t.Store.Delete(key) // The resource changed
+		}
+		return resp, err
+	}
+
+	// Callers sending their own validators handle 304s themselves
+	if req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" ||
+		strings.Contains(req.Header.Get("Cache-Control"), "no-cache") {
+		return base.RoundTrip(req)
+	}
+
+	cached, ok := t.Store.Get(key)
+	sendReq := req
+	if ok {
+		sendReq = req.Clone(req.Context())
+		if cached.ETag != "" {
+			sendReq.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			sendReq.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := base.RoundTrip(sendReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		t.revalidated.Add(1)
+		_ = resp.Body.Close()
+		return cached.response(req, resp), nil
+	}
+
+	t.misses.Add(1)
+	resp.Header.Set(CacheStatusHeader, "miss")
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if (etag == "" && lastModified == "") || strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		t.Store.Delete(key)
+		return resp, nil
+	}
+
+	maxBody := t.MaxBodyBytes
+	if maxBody <= 0 {
+		maxBody = DefaultCacheMaxBody
+	}
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, int64(maxBody)+1))
+	if readErr != nil || len(body) > maxBody {
+		// Too large (or failed): hand back what was read plus the rest
+		resp.Body = &peekedBody{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	header.Del(CacheStatusHeader)
+	t.Store.Set(key, &CachedResponse{
+		StatusCode:   resp.StatusCode,
+		Header:       header,
+		Body:         body,
+		ETag:         etag,
+		LastModified: lastModified,
+		StoredAt:     time.Now(),
+	})
+	t.stored.Add(1)
+	return resp, nil
+}
+
+// response rebuilds the cached response for req, taking fresh headers
+// (such as rate limit counters) from the 304
+func (c *CachedResponse) response(req *http.Request, notModified *http.Response) *http.Response {
+	header := c.Header.Clone()
+	for name, values := range notModified.Header {
+		if name == "Content-Length" {
+			continue
+		}
+		header[name] = values
+	}
+	header.Set(CacheStatusHeader, "revalidated")
+	header.Set("Content-Length", strconv.Itoa(len(c.Body)))
+
+	return &http.Response{
+		Status:        strconv.Itoa(c.StatusCode) + " " + http.StatusText(c.StatusCode),
+		StatusCode:    c.StatusCode,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+		TLS:           notModified.TLS,
+	}
+}
+
+// cacheKey identifies a response by URL, Accept, and credentials
+func cacheKey(req *http.Request) string {
+	var auth string
+	if value := req.Header.Get("Authorization"); value != "" {
+		sum := sha256.Sum256([]byte(value))
+		auth = hex.EncodeToString(sum[:8])
+	}
+	return req.URL.String() + "\x00" + req.Header.Get("Accept") + "\x00" + auth
+}
@@ -0,0 +1,184 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// etagServer serves a versioned resource with an ETag and answers
+// If-None-Match with 304
+type etagServer struct {
+	version atomic.Int32
+	full    atomic.Int32
+	notMod  atomic.Int32
+}
+
+func (s *etagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPatch {
+		s.version.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	etag := fmt.Sprintf(`"v%d"`, s.version.Load())
+	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(100-s.full.Load()-s.notMod.Load()))
+	if r.Header.Get("If-None-Match") == etag {
+		s.notMod.Add(1)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.full.Add(1)
+	w.Header().Set("ETag", etag)
+	_, _ = fmt.Fprintf(w, "body %s", etag)
+}
+
+func getBody(t *testing.T, client *http.Client, url string) (string, *http.Response) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body), resp
+}
+
+func TestCacheTransport_Revalidates(t *testing.T) {
+	handler := &etagServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := WithCache(server.Client(), nil)
+	transport := client.Transport.(*CacheTransport)
+
+	body, resp := getBody(t, client, server.URL+"/pr/1")
+	if body != `body "v0"` || resp.Header.Get(CacheStatusHeader) != "miss" {
+		t.Fatalf("first Get = %q (%s)", body, resp.Header.Get(CacheStatusHeader))
+	}
+
+	body, resp = getBody(t, client, server.URL+"/pr/1")
+	if body != `body "v0"` || resp.StatusCode != http.StatusOK {
+		t.Errorf("cached Get = %d %q, want 200 with cached body", resp.StatusCode, body)
+	}
+	if resp.Header.Get(CacheStatusHeader) != "revalidated" {
+		t.Errorf("%s = %q, want revalidated", CacheStatusHeader, resp.Header.Get(CacheStatusHeader))
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "99" {
+		t.Errorf("X-RateLimit-Remaining = %q, want the 304's value", resp.Header.Get("X-RateLimit-Remaining"))
+	}
+
+	// A change on the server is picked up
+	handler.version.Add(1)
+	if body, _ := getBody(t, client, server.URL+"/pr/1"); body != `body "v1"` {
+		t.Errorf("Get after change = %q, want v1", body)
+	}
+
+	if got := handler.full.Load(); got != 2 {
+		t.Errorf("full responses = %d, want 2", got)
+	}
+	stats := transport.Stats()
+	if stats.Revalidated != 1 || stats.Misses != 2 || stats.Stored != 2 {
+		t.Errorf("Stats = %+v", stats)
+	}
+}
+
+func TestCacheTransport_InvalidatesOnWrite(t *testing.T) {
+	handler := &etagServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	store := NewMemoryCache(0)
+	client := WithCache(server.Client(), store)
+
+	getBody(t, client, server.URL+"/issue/1")
+	if store.Len() != 1 {
+		t.Fatalf("Len = %d, want 1", store.Len())
+	}
+
+	req, _ := http.NewRequest(http.MethodPatch, server.URL+"/issue/1", strings.NewReader("{}"))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	if store.Len() != 0 {
+		t.Errorf("Len after PATCH = %d, want 0", store.Len())
+	}
+}
+
+func TestCacheTransport_SeparatesCredentials(t *testing.T) {
+	handler := &etagServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := WithCache(server.Client(), nil)
+	for _, token := range []string{"alice", "bob"} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+		resp.Body.Close()
+		if resp.Header.Get(CacheStatusHeader) != "miss" {
+			t.Errorf("%s: %s = %q, want miss", token, CacheStatusHeader, resp.Header.Get(CacheStatusHeader))
+		}
+	}
+}
+
+func TestCacheTransport_SkipsUncacheable(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("If-None-Match") != "" {
+			t.Error("sent validator for an uncached response")
+		}
+		switch r.URL.Path {
+		case "/no-store":
+			w.Header().Set("ETag", `"x"`)
+			w.Header().Set("Cache-Control", "no-store")
+		case "/large":
+			w.Header().Set("ETag", `"x"`)
+			_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	store := NewMemoryCache(0)
+	client := &http.Client{Transport: &CacheTransport{Base: server.Client().Transport, Store: store, MaxBodyBytes: 10}}
+
+	for _, path := range []string{"/plain", "/no-store", "/large"} {
+		body, _ := getBody(t, client, server.URL+path)
+		if path == "/large" && len(body) != 100 {
+			t.Errorf("large body = %d bytes, want 100", len(body))
+		}
+		getBody(t, client, server.URL+path)
+	}
+	if store.Len() != 0 {
+		t.Errorf("Len = %d, want 0", store.Len())
+	}
+}
+
+func TestMemoryCache_Evicts(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", &CachedResponse{})
+	cache.Set("b", &CachedResponse{})
+	cache.Get("a") // b is now least recently used
+	cache.Set("c", &CachedResponse{})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("%s missing", key)
+		}
+	}
+}
@@ -4,10 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 	"time"
 )
 
@@ -25,8 +25,7 @@ type Client struct {
 	client      *http.Client
 	baseURL     string
 	serviceName string
-	maxRetries  int
-	retryWait   time.Duration
+	retry       RetryPolicy
 
 	// beforeRequest is called before each request (for auth headers, etc.)
 	beforeRequest func(req *http.Request)
@@ -34,11 +33,16 @@ type Client struct {
 
 // ClientConfig holds configuration for Client.
 type ClientConfig struct {
-	Client        *http.Client
-	BaseURL       string
-	ServiceName   string
-	MaxRetries    int
-	RetryWait     time.Duration
+	Client      *http.Client
+	BaseURL     string
+	ServiceName string
+
+	// MaxRetries is the total number of attempts per request.
+	MaxRetries int
+
+	// RetryWait is the wait before the first retry; later waits double.
+	RetryWait time.Duration
+
 	BeforeRequest func(req *http.Request)
 }
 
@@ -48,19 +52,20 @@ func NewClient(cfg ClientConfig) *Client {
 		client:        cfg.Client,
 		baseURL:       cfg.BaseURL,
 		serviceName:   cfg.ServiceName,
-		maxRetries:    cfg.MaxRetries,
-		retryWait:     cfg.RetryWait,
+		retry:         DefaultRetryPolicy(),
 		beforeRequest: cfg.BeforeRequest,
 	}
 
 	if c.client == nil {
 		c.client = &http.Client{Timeout: DefaultTimeout}
 	}
-	if c.maxRetries <= 0 {
-		c.maxRetries = DefaultMaxRetries
+	if cfg.MaxRetries > 0 {
+		c.retry.MaxRetries = cfg.MaxRetries - 1
+	} else {
+		c.retry.MaxRetries = DefaultMaxRetries - 1
 	}
-	if c.retryWait <= 0 {
-		c.retryWait = DefaultRetryWait
+	if cfg.RetryWait > 0 {
+		c.retry.MinWait = cfg.RetryWait
 	}
 
 	return c
@@ -71,7 +76,9 @@ func (c *Client) Request(ctx context.Context, method, path string, body any) (*h
 	return c.RequestWithHeaders(ctx, method, path, body, nil)
 }
 
-// RequestWithHeaders executes an HTTP request with custom headers.
+// RequestWithHeaders executes an HTTP request with custom headers. Retries
+// follow RetryPolicy: POST and PATCH requests are only resent after a 429,
+// unless headers include an Idempotency-Key.
 func (c *Client) RequestWithHeaders(
 	ctx context.Context,
 	method, path string,
@@ -87,66 +94,30 @@ func (c *Client) RequestWithHeaders(
 		bodyReader = bytes.NewReader(data)
 	}
 
-	url := c.baseURL + path
-
-	var lastErr error
-	for attempt := range c.maxRetries {
-		// Reset body reader for retry
-		if body != nil {
-			data, _ := json.Marshal(body)
-			bodyReader = bytes.NewReader(data)
-		}
-
-		req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
-		if err != nil {
-			return nil, fmt.Errorf("create request: %w", err)
-		}
-
-		// Set default headers
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Accept", "application/json")
-
-		// Apply custom headers
-		for k, v := range headers {
-			req.Header.Set(k, v)
-		}
-
-		// Apply auth headers via callback
-		if c.beforeRequest != nil {
-			c.beforeRequest(req)
-		}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
 
-		resp, err := c.client.Do(req)
-		if err != nil {
-			lastErr = err
-			if shouldRetry(err, nil) && attempt < c.maxRetries-1 {
-				wait := c.retryWait * time.Duration(1<<attempt) // Exponential backoff
-				select {
-				case <-ctx.Done():
-					return nil, ctx.Err()
-				case <-time.After(wait):
-					continue
-				}
-			}
-			return nil, fmt.Errorf("%s request failed: %w", c.serviceName, err)
-		}
+	// Set default headers
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
 
-		// Check for retryable status codes
-		if shouldRetry(nil, resp) && attempt < c.maxRetries-1 {
-			wait := c.getRetryWait(resp, attempt)
-			resp.Body.Close()
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(wait):
-				continue
-			}
-		}
+	// Apply custom headers
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 
-		return resp, nil
+	// Apply auth headers via callback
+	if c.beforeRequest != nil {
+		c.beforeRequest(req)
 	}
 
-	return nil, lastErr
+	resp, err := c.retry.Do(req, c.client.Do)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", c.serviceName, err)
+	}
+	return resp, nil
 }
 
 // Get performs a GET request and decodes the response into result.
@@ -212,61 +183,12 @@ func (c *Client) handleResponse(resp *http.Response, path string, result any) er
 
 // parseError parses an error response into an APIError.
 func (c *Client) parseError(resp *http.Response, path string) error {
-	body, _ := io.ReadAll(resp.Body)
-
-	apiErr := &APIError{
-		Service:    c.serviceName,
-		StatusCode: resp.StatusCode,
-		Endpoint:   path,
-		RequestID:  resp.Header.Get("X-Request-Id"),
-	}
-
-	// Try to parse error message from body
-	var errResp struct {
-		Message string `json:"message"`
-		Error   string `json:"error"`
-	}
-	if json.Unmarshal(body, &errResp) == nil {
-		if errResp.Message != "" {
-			apiErr.Message = errResp.Message
-		} else if errResp.Error != "" {
-			apiErr.Message = errResp.Error
-		}
-	}
-
-	if apiErr.Message == "" {
-		apiErr.Message = http.StatusText(resp.StatusCode)
-	}
-
-	return apiErr
-}
-
-// getRetryWait calculates the wait time for a retry.
-func (c *Client) getRetryWait(resp *http.Response, attempt int) time.Duration {
-	// Check for Retry-After header
-	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
-		if seconds, err := strconv.Atoi(retryAfter); err == nil {
-			return time.Duration(seconds) * time.Second
-		}
-	}
-
-	// Exponential backoff
-	return c.retryWait * time.Duration(1<<attempt)
-}
-
-// shouldRetry determines if a request should be retried.
-func shouldRetry(err error, resp *http.Response) bool {
-	if err != nil {
-		// Retry on network errors
-		return true
+	err := CheckResponse(c.serviceName, resp)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		apiErr.Endpoint = path
 	}
-
-	if resp != nil {
-		// Retry on rate limit or server errors
-		return resp.StatusCode == 429 || resp.StatusCode >= 500
-	}
-
-	return false
+	return err
 }
 
 // GetRaw performs a GET request and returns the raw response body.
@@ -2,11 +2,19 @@
 package http
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 )
 
+// MaxErrorBodyBytes is the most of an error response body kept in
+// APIError.Body.
+const MaxErrorBodyBytes = 64 << 10
+
 // Standard sentinel errors for integration clients.
 var (
 	// ErrNotFound indicates the requested resource does not exist.
@@ -24,6 +32,10 @@ var (
 	// ErrBadRequest indicates the request was malformed.
 	ErrBadRequest = errors.New("bad request")
 
+	// ErrConflict indicates the request conflicts with the resource's
+	// current state (409), or a precondition on it failed (412).
+	ErrConflict = errors.New("conflict")
+
 	// ErrServerError indicates a server-side error occurred.
 	ErrServerError = errors.New("server error")
 )
@@ -44,39 +56,138 @@ type APIError struct {
 
 	// RequestID is the request ID for debugging (if available).
 	RequestID string
+
+	// Body is the start of the response body (up to MaxErrorBodyBytes),
+	// when it was still unread.
+	Body []byte
+
+	// Err is the error reported by a provider's client library, if any.
+	// errors.Is and errors.As reach it as well as the status sentinel.
+	Err error
 }
 
 // Error implements the error interface.
 func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" && e.Err != nil {
+		message = e.Err.Error()
+	}
 	if e.RequestID != "" {
 		return fmt.Sprintf("%s API error (%d) at %s [%s]: %s",
-			e.Service, e.StatusCode, e.Endpoint, e.RequestID, e.Message)
+			e.Service, e.StatusCode, e.Endpoint, e.RequestID, message)
 	}
 	return fmt.Sprintf("%s API error (%d) at %s: %s",
-		e.Service, e.StatusCode, e.Endpoint, e.Message)
+		e.Service, e.StatusCode, e.Endpoint, message)
 }
 
 // Unwrap returns the underlying sentinel error based on status code.
 func (e *APIError) Unwrap() error {
-	switch e.StatusCode {
-	case 400:
+	return MapStatus(e.StatusCode)
+}
+
+// Is reports whether the wrapped library error matches target.
+func (e *APIError) Is(target error) bool {
+	return e.Err != nil && errors.Is(e.Err, target)
+}
+
+// As finds the first error in the wrapped library error that matches target.
+func (e *APIError) As(target any) bool {
+	return e.Err != nil && errors.As(e.Err, target)
+}
+
+// MapStatus returns the sentinel error for an HTTP status code, or nil for
+// success codes and statuses without one.
+func MapStatus(status int) error {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
 		return ErrBadRequest
-	case 401:
+	case http.StatusUnauthorized:
 		return ErrUnauthorized
-	case 403:
+	case http.StatusForbidden:
 		return ErrForbidden
-	case 404:
+	case http.StatusNotFound, http.StatusGone:
 		return ErrNotFound
-	case 429:
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return ErrConflict
+	case http.StatusTooManyRequests:
 		return ErrRateLimited
 	default:
-		if e.StatusCode >= 500 {
+		if status >= 500 && status < 600 {
 			return ErrServerError
 		}
 		return nil
 	}
 }
 
+// CheckResponse returns nil for a response below 400. Otherwise it reads
+// the body (keeping up to MaxErrorBodyBytes) and returns an *APIError
+// carrying it, with Message taken from a JSON "message", "error", or
+// "errorMessages" field when present. The caller still closes the body.
+func CheckResponse(service string, resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, MaxErrorBodyBytes))
+	apiErr := newAPIError(service, resp)
+	apiErr.Body = body
+	apiErr.Message = errorMessage(body)
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+// WrapError wraps err from a provider's client library, which has already
+// read resp, in an *APIError so callers can use the shared sentinels. It
+// returns err unchanged when resp is nil or not an error status.
+func WrapError(service string, resp *http.Response, err error) error {
+	if err == nil || resp == nil || resp.StatusCode < 400 {
+		return err
+	}
+	apiErr := newAPIError(service, resp)
+	apiErr.Err = err
+	return apiErr
+}
+
+// newAPIError fills in the fields every APIError takes from resp
+func newAPIError(service string, resp *http.Response) *APIError {
+	apiErr := &APIError{
+		Service:    service,
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-GitHub-Request-Id")
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		apiErr.Endpoint = resp.Request.URL.Path
+	}
+	return apiErr
+}
+
+// errorMessage extracts the message from common JSON error bodies
+func errorMessage(body []byte) string {
+	var errResp struct {
+		Message       string   `json:"message"`
+		Error         any      `json:"error"`
+		ErrorMessages []string `json:"errorMessages"`
+	}
+	if json.Unmarshal(body, &errResp) != nil {
+		return ""
+	}
+	switch {
+	case errResp.Message != "":
+		return errResp.Message
+	case len(errResp.ErrorMessages) > 0:
+		return strings.Join(errResp.ErrorMessages, "; ")
+	}
+	if msg, ok := errResp.Error.(string); ok {
+		return msg
+	}
+	return ""
+}
+
 // AuthError represents an authentication failure.
 type AuthError struct {
 	// Service is the integration that failed authentication.
@@ -164,6 +275,12 @@ func IsForbidden(err error) bool {
 	return errors.Is(err, ErrForbidden)
 }
 
+// IsConflict reports whether the error indicates a conflict with the
+// resource's current state.
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
 // IsRateLimited reports whether the error indicates rate limiting.
 func IsRateLimited(err error) bool {
 	return errors.Is(err, ErrRateLimited)
@@ -84,6 +84,17 @@ func TestAPIError(t *testing.T) {
 			wantMsg:    "jira API error (400) at /rest/api/2/search: Invalid JQL",
 			wantUnwrap: ErrBadRequest,
 		},
+		{
+			name: "conflict",
+			err: &APIError{
+				Service:    "github",
+				StatusCode: 409,
+				Endpoint:   "/repos/o/r/pulls/1/merge",
+				Err:        errors.New("Head branch was modified"),
+			},
+			wantMsg:    "github API error (409) at /repos/o/r/pulls/1/merge: Head branch was modified",
+			wantUnwrap: ErrConflict,
+		},
 	}
 
 	for _, tt := range tests {
@@ -98,6 +109,112 @@ func TestAPIError(t *testing.T) {
 	}
 }
 
+func TestMapStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{200, nil},
+		{304, nil},
+		{400, ErrBadRequest},
+		{422, ErrBadRequest},
+		{401, ErrUnauthorized},
+		{403, ErrForbidden},
+		{404, ErrNotFound},
+		{410, ErrNotFound},
+		{409, ErrConflict},
+		{412, ErrConflict},
+		{429, ErrRateLimited},
+		{418, nil},
+		{502, ErrServerError},
+	}
+
+	for _, tt := range tests {
+		if got := MapStatus(tt.status); got != tt.want {
+			t.Errorf("MapStatus(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/jira":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"errorMessages":["Issue is locked","Try later"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("<html>gone</html>"))
+		}
+	}))
+	defer server.Close()
+
+	check := func(path string) error {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		defer resp.Body.Close()
+		return CheckResponse("test", resp)
+	}
+
+	if err := check("/ok"); err != nil {
+		t.Errorf("CheckResponse(200) = %v, want nil", err)
+	}
+
+	err := check("/jira")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !IsConflict(err) {
+		t.Fatalf("err = %v, want conflict APIError", err)
+	}
+	if apiErr.Message != "Issue is locked; Try later" || apiErr.RequestID != "req-1" || apiErr.Endpoint != "/jira" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+
+	err = check("/missing")
+	if !errors.As(err, &apiErr) || !IsNotFound(err) {
+		t.Fatalf("err = %v, want not found APIError", err)
+	}
+	if string(apiErr.Body) != "<html>gone</html>" || apiErr.Message != "Not Found" {
+		t.Errorf("Body = %q, Message = %q", apiErr.Body, apiErr.Message)
+	}
+}
+
+// libraryError stands in for a provider library's error type
+type libraryError struct{ msg string }
+
+func (e *libraryError) Error() string { return e.msg }
+
+func TestWrapError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "https://api.example.com/repos/o/r/pulls/9", nil)
+	resp := &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Request: req}
+	libErr := &libraryError{msg: "GET /repos/o/r/pulls/9: 404 Not Found"}
+
+	err := WrapError("github", resp, libErr)
+	if !IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false", err)
+	}
+	var got *libraryError
+	if !errors.As(err, &got) || got != libErr {
+		t.Errorf("errors.As did not reach the library error")
+	}
+	if !errors.Is(err, libErr) {
+		t.Errorf("errors.Is did not reach the library error")
+	}
+
+	// Errors without an error response pass through unchanged
+	if got := WrapError("github", nil, libErr); got != libErr {
+		t.Errorf("WrapError(nil resp) = %v, want original", got)
+	}
+	resp.StatusCode = http.StatusOK
+	if got := WrapError("github", resp, libErr); got != libErr {
+		t.Errorf("WrapError(200) = %v, want original", got)
+	}
+}
+
 func TestAuthError(t *testing.T) {
 	err := &AuthError{
 		Service: "jira",
@@ -464,4 +581,41 @@ func TestClient(t *testing.T) {
 			t.Errorf("got %d attempts, want 3", attempts)
 		}
 	})
+
+	t.Run("does not resend POST on 5xx", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 || r.Header.Get(IdempotencyKeyHeader) == "" {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{"ok": "true"})
+		}))
+		defer server.Close()
+
+		client := NewClient(ClientConfig{
+			BaseURL:     server.URL,
+			ServiceName: "test",
+			MaxRetries:  3,
+			RetryWait:   1 * time.Millisecond,
+		})
+
+		err := client.Post(context.Background(), "/comments", map[string]string{"body": "hi"}, nil)
+		if !errors.Is(err, ErrServerError) || attempts != 1 {
+			t.Errorf("Post() error = %v after %d attempts, want one 5xx", err, attempts)
+		}
+
+		attempts = 0
+		resp, err := client.RequestWithHeaders(context.Background(), http.MethodPost, "/comments",
+			map[string]string{"body": "hi"}, map[string]string{IdempotencyKeyHeader: "k1"})
+		if err != nil {
+			t.Fatalf("RequestWithHeaders() error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || attempts != 2 {
+			t.Errorf("status %d after %d attempts, want 200 after 2", resp.StatusCode, attempts)
+		}
+	})
 }
@@ -0,0 +1,242 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// redacted replaces secret header and query values in logs.
+const redacted = "[redacted]"
+
+// DefaultRedactHeaders are headers whose values are never logged.
+var DefaultRedactHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"Private-Token",
+	"X-Hub-Signature", // Also X-Hub-Signature-256: names match by prefix
+}
+
+// DefaultRedactQuery are query parameters whose values are never logged.
+var DefaultRedactQuery = []string{"access_token", "token", "private_token", "api_key", "key", "client_secret", "code"}
+
+// LogConfig configures a LoggingTransport.
+type LogConfig struct {
+	// Logger receives one record per request (default: slog.Default()).
+	Logger *slog.Logger
+
+	// Level is the level of successful requests; the zero value is
+	// slog.LevelInfo. Failed requests and 5xx responses are logged at
+	// slog.LevelWarn or above.
+	Level slog.Level
+
+	// Headers includes request and response headers in each record.
+	Headers bool
+
+	// RedactHeaders are extra header names (or prefixes) to redact, added
+	// to DefaultRedactHeaders.
+	RedactHeaders []string
+
+	// RedactQuery are extra query parameter names to redact, added to
+	// DefaultRedactQuery.
+	RedactQuery []string
+
+	// MaxBodyBytes logs up to this many bytes of each request and response
+	// body. Zero logs no bodies. Response bodies are sampled by reading
+	// ahead, so leave it at zero for streaming responses.
+	MaxBodyBytes int
+}
+
+// LoggingTransport is an http.RoundTripper that logs each request's method,
+// URL, status, and duration with slog, redacting credentials.
+type LoggingTransport struct {
+	// Base performs each request (http.DefaultTransport if nil).
+	Base http.RoundTripper
+
+	cfg      LogConfig
+	disabled atomic.Bool
+}
+
+// NewLoggingTransport wraps base with request logging.
+func NewLoggingTransport(base http.RoundTripper, cfg LogConfig) *LoggingTransport {
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	return &LoggingTransport{Base: base, cfg: cfg}
+}
+
+// WithLogging returns a copy of client that logs requests, and its
+// transport for toggling with SetEnabled.
+func WithLogging(client *http.Client, cfg LogConfig) (*http.Client, *LoggingTransport) {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	transport := NewLoggingTransport(client.Transport, cfg)
+	wrapped := *client
+	wrapped.Transport = transport
+	return &wrapped, transport
+}
+
+// SetEnabled turns logging on or off. Transports start enabled.
+func (t *LoggingTransport) SetEnabled(enabled bool) {
+	t.disabled.Store(!enabled)
+}
+
+// Enabled reports whether requests are being logged.
+func (t *LoggingTransport) Enabled() bool {
+	return !t.disabled.Load()
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx := req.Context()
+	if t.disabled.Load() || loggingSkipped(ctx) || !t.cfg.Logger.Enabled(ctx, min(t.cfg.Level, slog.LevelWarn)) {
+		return base.RoundTrip(req)
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", t.redactURL(req.URL)),
+	}
+	if t.cfg.Headers {
+		attrs = append(attrs, t.headerAttr("request_headers", req.Header))
+	}
+	if t.cfg.MaxBodyBytes > 0 && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			sample, truncated := readSample(body, t.cfg.MaxBodyBytes)
+			_ = body.Close()
+			attrs = append(attrs, bodyAttr("request_body", sample, truncated))
+		}
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+
+	level := t.cfg.Level
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		t.cfg.Logger.LogAttrs(ctx, max(level, slog.LevelWarn), "http request failed", attrs...)
+		return nil, err
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if t.cfg.Headers {
+		attrs = append(attrs, t.headerAttr("response_headers", resp.Header))
+	}
+	if t.cfg.MaxBodyBytes > 0 && resp.Body != nil {
+		sample, truncated := peekBody(resp, t.cfg.MaxBodyBytes)
+		attrs = append(attrs, bodyAttr("response_body", sample, truncated))
+	}
+	if resp.StatusCode >= 500 {
+		level = max(level, slog.LevelWarn)
+	}
+	t.cfg.Logger.LogAttrs(ctx, level, "http request", attrs...)
+	return resp, nil
+}
+
+// redactURL renders u without credentials or secret query values
+func (t *LoggingTransport) redactURL(u *url.URL) string {
+	clean := *u
+	clean.User = nil
+	if clean.RawQuery != "" {
+		query := clean.Query()
+		for name := range query {
+			if matchesName(name, DefaultRedactQuery, false) || matchesName(name, t.cfg.RedactQuery, false) {
+				query.Set(name, redacted)
+			}
+		}
+		clean.RawQuery = query.Encode()
+	}
+	return clean.String()
+}
+
+// headerAttr groups headers, redacting secret values
+func (t *LoggingTransport) headerAttr(key string, header http.Header) slog.Attr {
+	attrs := make([]any, 0, len(header))
+	for name, values := range header {
+		value := strings.Join(values, ", ")
+		if matchesName(name, DefaultRedactHeaders, true) || matchesName(name, t.cfg.RedactHeaders, true) {
+			value = redacted
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.Group(key, attrs...)
+}
+
+// matchesName reports whether name equals one of names, case-insensitively,
+// or with prefix set, starts with one
+func matchesName(name string, names []string, prefix bool) bool {
+	for _, n := range names {
+		if strings.EqualFold(name, n) {
+			return true
+		}
+		if prefix && len(name) > len(n) && strings.EqualFold(name[:len(n)], n) {
+			return true
+		}
+	}
+	return false
+}
+
+// readSample reads up to limit bytes of r and whether more remained
+func readSample(r io.Reader, limit int) ([]byte, bool) {
+	buf, _ := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if len(buf) > limit {
+		return buf[:limit], true
+	}
+	return buf, false
+}
+
+// peekBody samples the start of resp's body and puts it back for the caller
+func peekBody(resp *http.Response, limit int) ([]byte, bool) {
+	buf, _ := io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+	resp.Body = &peekedBody{Reader: io.MultiReader(bytes.NewReader(buf), resp.Body), Closer: resp.Body}
+	if len(buf) > limit {
+		return buf[:limit], true
+	}
+	return buf, false
+}
+
+// peekedBody replays sampled bytes before the rest of the body
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyAttr renders a body sample
+func bodyAttr(key string, sample []byte, truncated bool) slog.Attr {
+	value := string(sample)
+	if truncated {
+		value += "...(truncated)"
+	}
+	return slog.String(key, value)
+}
+
+// logContextKey marks requests that should not be logged
+type logContextKey struct{}
+
+// WithoutLogging returns a context whose requests a LoggingTransport skips,
+// for calls whose bodies carry secrets (such as token exchanges).
+func WithoutLogging(ctx context.Context) context.Context {
+	return context.WithValue(ctx, logContextKey{}, true)
+}
+
+// loggingSkipped reports whether ctx came from WithoutLogging
+func loggingSkipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(logContextKey{}).(bool)
+	return skip
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newLogBuffer returns a JSON logger at debug level and its output
+func newLogBuffer() (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})), &buf
+}
+
+// decodeRecords parses JSON log lines
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("decode %q: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestLoggingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=abc")
+		_, _ = w.Write([]byte(`{"key":"PROJ-1","fields":{"summary":"long body"}}`))
+	}))
+	defer server.Close()
+
+	logger, buf := newLogBuffer()
+	client, _ := WithLogging(server.Client(), LogConfig{
+		Logger:        logger,
+		Headers:       true,
+		MaxBodyBytes:  10,
+		RedactHeaders: []string{"X-Custom-Secret"},
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/issue?access_token=tok&expand=names", strings.NewReader(`{"summary":"x"}`))
+	req.Header.Set("Authorization", "Bearer secret-token")
+	req.Header.Set("X-Custom-Secret", "hunter2")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// The caller still sees the whole body after sampling
+	if !strings.HasSuffix(string(body), `"long body"}}`) {
+		t.Errorf("body = %q, want full response", body)
+	}
+
+	out := buf.String()
+	for _, secret := range []string{"secret-token", "hunter2", "session=abc", "access_token=tok"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log contains %q: %s", secret, out)
+		}
+	}
+
+	records := decodeRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("records = %d, want 1", len(records))
+	}
+	rec := records[0]
+	if rec["method"] != "POST" || rec["status"] != float64(200) || rec["level"] != "INFO" {
+		t.Errorf("record = %v", rec)
+	}
+	if url, _ := rec["url"].(string); !strings.Contains(url, "expand=names") {
+		t.Errorf("url = %q, want non-secret query kept", url)
+	}
+	if rec["request_body"] != `{"summary"...(truncated)` {
+		t.Errorf("request_body = %v", rec["request_body"])
+	}
+	if rec["response_body"] != `{"key":"PR...(truncated)` {
+		t.Errorf("response_body = %v", rec["response_body"])
+	}
+	headers, _ := rec["request_headers"].(map[string]any)
+	if headers["Accept"] != "application/json" || headers["Authorization"] != "[redacted]" {
+		t.Errorf("request_headers = %v", headers)
+	}
+}
+
+func TestLoggingTransport_Toggle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	logger, buf := newLogBuffer()
+	client, transport := WithLogging(server.Client(), LogConfig{Logger: logger, Level: slog.LevelDebug})
+
+	transport.SetEnabled(false)
+	get := func(ctx context.Context) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+		resp.Body.Close()
+	}
+	get(context.Background())
+	if buf.Len() != 0 {
+		t.Errorf("disabled transport logged: %s", buf)
+	}
+
+	transport.SetEnabled(true)
+	get(WithoutLogging(context.Background()))
+	if buf.Len() != 0 {
+		t.Errorf("WithoutLogging request was logged: %s", buf)
+	}
+
+	get(context.Background())
+	if records := decodeRecords(t, buf); len(records) != 1 || records[0]["level"] != "DEBUG" {
+		t.Errorf("records = %v, want one debug record", records)
+	}
+}
+
+func TestLoggingTransport_Failures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	url := server.URL
+	client := server.Client()
+
+	logger, buf := newLogBuffer()
+	client, _ = WithLogging(client, LogConfig{Logger: logger, Level: slog.LevelDebug})
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	server.Close()
+	if _, err := client.Get(url); err == nil {
+		t.Fatal("expected error from closed server")
+	}
+
+	records := decodeRecords(t, buf)
+	if len(records) != 2 {
+		t.Fatalf("records = %d, want 2", len(records))
+	}
+	if records[0]["level"] != "WARN" || records[0]["status"] != float64(502) {
+		t.Errorf("5xx record = %v, want WARN with status", records[0])
+	}
+	if records[1]["level"] != "WARN" || records[1]["error"] == nil {
+		t.Errorf("failure record = %v, want WARN with error", records[1])
+	}
+}
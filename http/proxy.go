@@ -0,0 +1,170 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ErrInvalidProxy indicates a proxy URL that cannot be used.
+var ErrInvalidProxy = errors.New("invalid proxy")
+
+// ProxyConfig selects the proxy for one client. The zero value honors the
+// HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables.
+type ProxyConfig struct {
+	// URL is the proxy for every request from this client, overriding the
+	// environment. Schemes http, https, socks5, and socks5h are supported,
+	// e.g. "http://proxy.corp:3128" or "socks5://127.0.0.1:1080".
+	URL string `mapstructure:"url"`
+
+	// NoProxy lists hosts reached directly when URL is set, in NO_PROXY
+	// syntax: "internal.corp,.svc,10.0.0.0/8,localhost:8080". Empty falls
+	// back to the NO_PROXY environment variable.
+	NoProxy string `mapstructure:"no_proxy"`
+
+	// Direct ignores proxies, including those in the environment.
+	Direct bool `mapstructure:"direct"`
+}
+
+// ProxyFunc returns a function for http.Transport.Proxy.
+func (c ProxyConfig) ProxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if c.Direct {
+		return nil, nil
+	}
+	if c.URL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed URL", ErrInvalidProxy) // err would echo credentials
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("%w: unsupported scheme %q in %s", ErrInvalidProxy, proxyURL.Scheme, proxyURL.Redacted())
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("%w: missing host in %s", ErrInvalidProxy, proxyURL.Redacted())
+	}
+
+	noProxy := c.NoProxy
+	if noProxy == "" {
+		noProxy = getenvAny("NO_PROXY", "no_proxy")
+	}
+	bypass := parseNoProxy(noProxy)
+
+	return func(req *http.Request) (*url.URL, error) {
+		if bypass.matches(req.URL) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// getenvAny returns the first non-empty environment variable of names
+func getenvAny(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// noProxyList is a parsed NO_PROXY value
+type noProxyList struct {
+	all     bool
+	nets    []*net.IPNet
+	entries []noProxyEntry
+}
+
+// noProxyEntry is one host, domain, or IP in NO_PROXY, with optional port
+type noProxyEntry struct {
+	host   string // Lowercase, without a leading dot
+	suffix bool   // Written with a leading dot: subdomains only
+	port   string
+}
+
+// parseNoProxy parses a comma- or space-separated NO_PROXY value
+func parseNoProxy(value string) noProxyList {
+	var list noProxyList
+	for _, field := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "*" {
+			list.all = true
+			continue
+		}
+		if _, ipNet, err := net.ParseCIDR(field); err == nil {
+			list.nets = append(list.nets, ipNet)
+			continue
+		}
+
+		entry := noProxyEntry{host: field}
+		if host, port, err := net.SplitHostPort(field); err == nil {
+			entry.host, entry.port = host, port
+		}
+		entry.host = strings.Trim(entry.host, "[]")
+		if strings.HasPrefix(entry.host, "*.") {
+			entry.host = entry.host[1:]
+		}
+		if strings.HasPrefix(entry.host, ".") {
+			entry.host, entry.suffix = entry.host[1:], true
+		}
+		if entry.host != "" {
+			list.entries = append(list.entries, entry)
+		}
+	}
+	return list
+}
+
+// matches reports whether requests to u bypass the proxy. Loopback
+// addresses always do.
+func (l noProxyList) matches(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		}
+	}
+
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return true
+	}
+	if l.all {
+		return true
+	}
+
+	if ip != nil {
+		for _, n := range l.nets {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+	}
+
+	for _, e := range l.entries {
+		if e.port != "" && e.port != port {
+			continue
+		}
+		if host == e.host && !e.suffix {
+			return true
+		}
+		if strings.HasSuffix(host, "."+e.host) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,259 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxRetryWait caps the exponential backoff between retries.
+const DefaultMaxRetryWait = 30 * time.Second
+
+// DefaultRetryJitter is the default fraction of randomization applied to
+// backoff waits.
+const DefaultRetryJitter = 0.2
+
+// IdempotencyKeyHeader marks a request as safe to retry even though its
+// method is not idempotent.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryPolicy configures retries for RetryTransport and RetryPolicy.Do.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt; zero
+	// sends each request once.
+	MaxRetries int
+
+	// MinWait is the wait before the first retry; later waits double.
+	MinWait time.Duration
+
+	// MaxWait caps the backoff. A longer Retry-After is still honored.
+	MaxWait time.Duration
+
+	// Jitter randomizes each backoff by up to this fraction in either
+	// direction (0.2 means ±20%). Zero disables jitter.
+	Jitter float64
+
+	// RetryNonIdempotent allows retrying POST and PATCH requests without
+	// an Idempotency-Key header after network errors and 5xx responses.
+	RetryNonIdempotent bool
+
+	// ShouldRetry overrides which outcomes are retried. The default
+	// retries network errors, 429, and 5xx responses.
+	ShouldRetry func(resp *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		MinWait:    DefaultRetryWait,
+		MaxWait:    DefaultMaxRetryWait,
+		Jitter:     DefaultRetryJitter,
+	}
+}
+
+// RetryTransport is an http.RoundTripper that retries transient failures
+// with exponential backoff, honoring Retry-After.
+type RetryTransport struct {
+	// Base performs each attempt (http.DefaultTransport if nil).
+	Base http.RoundTripper
+
+	Policy RetryPolicy
+}
+
+// NewRetryTransport wraps base with retries following policy.
+func NewRetryTransport(base http.RoundTripper, policy RetryPolicy) *RetryTransport {
+	return &RetryTransport{Base: base, Policy: policy}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return t.Policy.Do(req, base.RoundTrip)
+}
+
+// WithRetry returns a copy of client whose transport retries following
+// policy. The original client is not modified.
+func WithRetry(client *http.Client, policy RetryPolicy) *http.Client {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	wrapped := *client
+	wrapped.Transport = NewRetryTransport(client.Transport, policy)
+	return &wrapped
+}
+
+// Do sends req with do, retrying as the policy allows. Response bodies of
+// retried attempts are closed; the last response is returned as is, so a
+// request that keeps failing with 503 returns that 503. Waits end early with
+// the context's error when req's context is canceled.
+func (p RetryPolicy) Do(req *http.Request, do func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	getBody, err := rewindableBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if getBody != nil && (attempt > 0 || req.GetBody == nil) {
+			attemptReq = req.Clone(ctx)
+			if attemptReq.Body, err = getBody(); err != nil {
+				return nil, fmt.Errorf("rewind request body: %w", err)
+			}
+		}
+
+		resp, doErr := do(attemptReq)
+		if attempt >= p.MaxRetries || !p.retryable(req, resp, doErr) {
+			return resp, doErr
+		}
+
+		wait := p.Backoff(attempt, resp)
+		if resp != nil {
+			drainAndClose(resp.Body)
+		}
+		if waitErr := sleepContext(ctx, wait); waitErr != nil {
+			return nil, waitErr
+		}
+	}
+}
+
+// retryable reports whether the outcome of req may be retried
+func (p RetryPolicy) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil && (req.Context().Err() != nil || errors.Is(err, ErrCircuitOpen)) {
+		return false
+	}
+	if p.ShouldRetry != nil {
+		if !p.ShouldRetry(resp, err) {
+			return false
+		}
+	} else if err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return false
+	}
+
+	// A 429 was refused before processing, so any method is safe to resend
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return p.RetryNonIdempotent || IsIdempotent(req)
+}
+
+// Backoff returns the wait before retry number attempt+1. A Retry-After
+// header on resp sets a floor; otherwise the wait is MinWait doubled per
+// attempt, capped at MaxWait, with jitter applied.
+func (p RetryPolicy) Backoff(attempt int, resp *http.Response) time.Duration {
+	minWait := p.MinWait
+	if minWait <= 0 {
+		minWait = DefaultRetryWait
+	}
+	maxWait := p.MaxWait
+	if maxWait <= 0 {
+		maxWait = DefaultMaxRetryWait
+	}
+
+	wait := maxWait
+	if attempt < 32 {
+		wait = min(minWait<<attempt, maxWait)
+	}
+	if p.Jitter > 0 {
+		wait = time.Duration(float64(wait) * (1 - p.Jitter + 2*p.Jitter*randFloat64()))
+	}
+
+	if resp != nil {
+		if after, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok && after > wait {
+			return after
+		}
+	}
+	return wait
+}
+
+// ParseRetryAfter parses a Retry-After header, given either as seconds or
+// as an HTTP date relative to now. It reports false for an empty or
+// malformed value; a date in the past yields zero.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// IsIdempotent reports whether req can be sent more than once without
+// changing the result: its method is idempotent (RFC 9110) or it carries
+// an Idempotency-Key header.
+func IsIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(IdempotencyKeyHeader) != "" || req.Header.Get("X-Idempotency-Key") != ""
+}
+
+// rewindableBody returns a function producing fresh copies of req's body,
+// buffering it when the request has no GetBody. It returns nil for a
+// request without a body.
+func rewindableBody(req *http.Request) (func() (io.ReadCloser, error), error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		return req.GetBody, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}, nil
+}
+
+// drainAndClose discards a little of body so the connection can be reused
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.CopyN(io.Discard, body, 4096)
+	_ = body.Close()
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// randFloat64 returns a random float64 in [0.0, 1.0)
+func randFloat64() float64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0.5
+	}
+	return float64(binary.LittleEndian.Uint64(b[:])>>11) / (1 << 53)
+}
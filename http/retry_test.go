@@ -0,0 +1,207 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastPolicy retries quickly so tests don't sleep
+func fastPolicy(retries int) RetryPolicy {
+	return RetryPolicy{MaxRetries: retries, MinWait: time.Millisecond, MaxWait: 5 * time.Millisecond}
+}
+
+func TestRetryTransport_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := WithRetry(server.Client(), fastPolicy(3))
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestRetryTransport_ReturnsLastResponse(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	resp, err := WithRetry(server.Client(), fastPolicy(2)).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3 (1 + 2 retries)", got)
+	}
+}
+
+func TestRetryTransport_IdempotencyGuard(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		key       string
+		wantCalls int32
+	}{
+		{name: "POST 500 not retried", status: 500, wantCalls: 1},
+		{name: "POST 500 with idempotency key", status: 500, key: "abc", wantCalls: 3},
+		{name: "POST 429 retried", status: 429, wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				if body, _ := io.ReadAll(r.Body); string(body) != "payload" {
+					t.Errorf("body = %q, want payload", body)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+			if tt.key != "" {
+				req.Header.Set(IdempotencyKeyHeader, tt.key)
+			}
+			resp, err := WithRetry(server.Client(), fastPolicy(2)).Do(req)
+			if err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+			resp.Body.Close()
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_BuffersBodyWithoutGetBody(t *testing.T) {
+	var bodies []string
+	attempts := 0
+	do := func(req *http.Request) (*http.Response, error) {
+		attempts++
+		body, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		if attempts == 1 {
+			return nil, errors.New("connection reset")
+		}
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, "http://example.com", nil)
+	req.Body = io.NopCloser(strings.NewReader("data"))
+
+	if _, err := fastPolicy(1).Do(req, do); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if len(bodies) != 2 || bodies[0] != "data" || bodies[1] != "data" {
+		t.Errorf("bodies = %q, want [data data]", bodies)
+	}
+}
+
+func TestRetryPolicy_ContextCanceledDuringWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	do := func(req *http.Request) (*http.Response, error) {
+		cancel()
+		return &http.Response{StatusCode: 503, Header: http.Header{}, Body: http.NoBody}, nil
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+	policy := RetryPolicy{MaxRetries: 3, MinWait: time.Hour, MaxWait: time.Hour}
+	if _, err := policy.Do(req, do); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MinWait: time.Second, MaxWait: 5 * time.Second}
+
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if got := policy.Backoff(attempt, nil); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"10"}}}
+	if got := policy.Backoff(0, resp); got != 10*time.Second {
+		t.Errorf("Backoff with Retry-After = %v, want 10s", got)
+	}
+
+	policy.Jitter = 0.5
+	for range 50 {
+		if got := policy.Backoff(1, nil); got < time.Second || got > 3*time.Second {
+			t.Fatalf("Backoff with jitter = %v, want within 1s..3s", got)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "120", want: 2 * time.Minute, wantOK: true},
+		{value: " 0 ", want: 0, wantOK: true},
+		{value: now.Add(30 * time.Second).Format(http.TimeFormat), want: 30 * time.Second, wantOK: true},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, wantOK: true},
+		{value: "", wantOK: false},
+		{value: "-5", wantOK: false},
+		{value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseRetryAfter(tt.value, now)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("ParseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	for method, want := range map[string]bool{
+		http.MethodGet:    true,
+		http.MethodPut:    true,
+		http.MethodDelete: true,
+		http.MethodPost:   false,
+		http.MethodPatch:  false,
+	} {
+		req, _ := http.NewRequest(method, "http://example.com", nil)
+		if got := IsIdempotent(req); got != want {
+			t.Errorf("IsIdempotent(%s) = %v, want %v", method, got, want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+)
+
+// ErrInvalidTLSConfig indicates TLS settings that cannot be used together.
+var ErrInvalidTLSConfig = errors.New("invalid TLS config")
+
+// TLSConfig holds TLS settings for reaching servers behind internal PKI.
+// The zero value uses the system roots.
+type TLSConfig struct {
+	// CertFile and KeyFile are a PEM client certificate and key for mutual
+	// TLS. Both or neither must be set.
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+
+	// CAFile is a PEM bundle of additional trusted CAs, added to the
+	// system roots.
+	CAFile string `mapstructure:"ca_file"`
+
+	// ServerName overrides the name checked against the server certificate.
+	ServerName string `mapstructure:"server_name"`
+
+	// InsecureSkipVerify disables server certificate verification. It
+	// exposes credentials to anyone on the network path; every client built
+	// with it logs a warning.
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+}
+
+// IsZero reports whether no TLS settings are configured.
+func (c TLSConfig) IsZero() bool {
+	return c == TLSConfig{}
+}
+
+// Build returns a *tls.Config for these settings, loading the certificate
+// files. It returns nil for the zero value.
+func (c TLSConfig) Build() (*tls.Config, error) {
+	if c.IsZero() {
+		return nil, nil
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, fmt.Errorf("%w: cert_file and key_file must be set together", ErrInvalidTLSConfig)
+	}
+
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.ServerName,
+	}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: no certificates in %s", ErrInvalidTLSConfig, c.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if c.InsecureSkipVerify {
+		slog.Warn("TLS certificate verification is DISABLED; connections can be intercepted and credentials stolen",
+			slog.String("setting", "insecure_skip_verify"))
+		cfg.InsecureSkipVerify = true // #nosec G402 -- explicit opt-in, warned above
+	}
+
+	return cfg, nil
+}
+
+// TransportConfig configures the http.Transport built by NewTransport.
+type TransportConfig struct {
+	// MaxIdleConns caps idle connections (default: 100).
+	MaxIdleConns int
+
+	// IdleConnTimeout is how long idle connections are kept (default: 90s).
+	IdleConnTimeout time.Duration
+
+	// TLS holds client certificate, CA, and verification settings.
+	TLS TLSConfig
+
+	// Proxy selects the proxy (default: from the environment).
+	Proxy ProxyConfig
+}
+
+// NewTransport builds an http.Transport from cfg, starting from
+// http.DefaultTransport's settings.
+func NewTransport(cfg TransportConfig) (*http.Transport, error) {
+	tlsConfig, err := cfg.TLS.Build()
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := cfg.Proxy.ProxyFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	if cfg.MaxIdleConns > 0 {
+		transport.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport, nil
+}
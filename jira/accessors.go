@@ -0,0 +1,183 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// customFieldPrefix starts the IDs of custom fields in issue payloads.
+const customFieldPrefix = "customfield_"
+
+// issueFieldsJSON has the JSON layout of IssueFields without its methods
+type issueFieldsJSON IssueFields
+
+// UnmarshalJSON decodes the typed fields and collects customfield_* values
+// into CustomFields. Custom values keep their JSON shape: numbers are
+// float64, objects map[string]any.
+func (f *IssueFields) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*issueFieldsJSON)(f)); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	f.CustomFields = nil
+	for id, value := range raw {
+		if !strings.HasPrefix(id, customFieldPrefix) || string(value) == "null" {
+			continue
+		}
+		var v any
+		if err := json.Unmarshal(value, &v); err != nil {
+			return fmt.Errorf("decode %s: %w", id, err)
+		}
+		if f.CustomFields == nil {
+			f.CustomFields = make(map[string]any)
+		}
+		f.CustomFields[id] = v
+	}
+	return nil
+}
+
+// MarshalJSON encodes the typed fields with CustomFields inlined, mirroring
+// UnmarshalJSON.
+func (f IssueFields) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(issueFieldsJSON(f))
+	if err != nil || len(f.CustomFields) == 0 {
+		return data, err
+	}
+
+	var merged map[string]any
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	for id, v := range f.CustomFields {
+		merged[id] = v
+	}
+	return json.Marshal(merged)
+}
+
+// StatusName returns the status name, or "" if the status was not fetched.
+func (f *IssueFields) StatusName() string {
+	if f.Status == nil {
+		return ""
+	}
+	return f.Status.Name
+}
+
+// StatusCategoryKey returns the status category: "new", "indeterminate",
+// or "done". Unlike status names, categories are the same on every
+// workflow.
+func (f *IssueFields) StatusCategoryKey() string {
+	if f.Status == nil {
+		return ""
+	}
+	return f.Status.StatusCategory.Key
+}
+
+// IsDone reports whether the issue's status is in the "done" category.
+func (f *IssueFields) IsDone() bool {
+	return f.StatusCategoryKey() == "done"
+}
+
+// PriorityName returns the priority name, or "" if none.
+func (f *IssueFields) PriorityName() string {
+	if f.Priority == nil {
+		return ""
+	}
+	return f.Priority.Name
+}
+
+// AssigneeID returns the assignee's account ID (Cloud) or username
+// (Server), or "" if the issue is unassigned.
+func (f *IssueFields) AssigneeID() string {
+	if f.Assignee == nil {
+		return ""
+	}
+	return f.Assignee.GetID()
+}
+
+// ComponentNames returns the names of the issue's components.
+func (f *IssueFields) ComponentNames() []string {
+	names := make([]string, len(f.Components))
+	for i, c := range f.Components {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// FixVersionNames returns the names of the issue's fix versions.
+func (f *IssueFields) FixVersionNames() []string {
+	names := make([]string, len(f.FixVersions))
+	for i, v := range f.FixVersions {
+		names[i] = v.Name
+	}
+	return names
+}
+
+// DueDateTime parses the due date. It returns the zero time if none is set.
+func (f *IssueFields) DueDateTime() (time.Time, error) {
+	return ParseDate(f.DueDate)
+}
+
+// ResolvedTime parses the resolution timestamp. It returns the zero time
+// if the issue is unresolved.
+func (f *IssueFields) ResolvedTime() (time.Time, error) {
+	return ParseTime(f.Resolved)
+}
+
+// DescriptionMarkdown returns the description as Markdown, converting
+// from ADF (v3) or Wiki Markup (v2).
+func (f *IssueFields) DescriptionMarkdown() (string, error) {
+	if s, ok := f.Description.(string); ok {
+		return WikiToMarkdown(s), nil
+	}
+	return NewADFConverter().FromADFAny(f.Description)
+}
+
+// CustomField decodes a custom field into out, e.g. a *float64 for story
+// points or a *[]Version for a version picker. It returns
+// ErrCustomFieldNotSet if the issue has no value for id.
+func (f *IssueFields) CustomField(id string, out any) error {
+	v, ok := f.CustomFields[id]
+	if !ok {
+		return fmt.Errorf("%s: %w", id, ErrCustomFieldNotSet)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", id, err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode %s: %w", id, err)
+	}
+	return nil
+}
+
+// CustomFieldString returns a text custom field, or the value of a
+// single-select option.
+func (f *IssueFields) CustomFieldString(id string) (string, bool) {
+	switch v := f.CustomFields[id].(type) {
+	case string:
+		return v, true
+	case map[string]any:
+		s, ok := v["value"].(string)
+		return s, ok
+	default:
+		return "", false
+	}
+}
+
+// CustomFieldNumber returns a number custom field such as story points.
+func (f *IssueFields) CustomFieldNumber(id string) (float64, bool) {
+	n, ok := f.CustomFields[id].(float64)
+	return n, ok
+}
+
+// ReleaseTime parses the version's release date. It returns the zero time
+// if none is set.
+func (v *Version) ReleaseTime() (time.Time, error) {
+	return ParseDate(v.ReleaseDate)
+}
@@ -0,0 +1,167 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// v3 (Cloud) payload: ADF description, accountId users
+const issueV3JSON = `{
+	"id": "10001",
+	"key": "PROJ-1",
+	"fields": {
+		"summary": "Add login",
+		"description": {"type": "doc", "version": 1, "content": [
+			{"type": "paragraph", "content": [{"type": "text", "text": "Needs ", "marks": []}, {"type": "text", "text": "SSO", "marks": [{"type": "strong"}]}]}
+		]},
+		"status": {"id": "3", "name": "Closed", "statusCategory": {"id": 3, "key": "done", "name": "Done"}},
+		"priority": {"id": "2", "name": "High"},
+		"assignee": {"accountId": "5b10ac8d82e05b22cc7d4ef5", "displayName": "Ada", "active": true},
+		"components": [{"id": "1", "name": "api"}, {"id": "2", "name": "web"}],
+		"fixVersions": [{"id": "7", "name": "1.2.0", "released": false, "releaseDate": "2025-03-01"}],
+		"created": "2025-01-15T10:30:00.000+0000",
+		"resolutiondate": "2025-01-20T08:00:00.000+0000",
+		"duedate": "2025-02-01",
+		"customfield_10016": 5,
+		"customfield_10020": {"id": "10100", "value": "Platform"},
+		"customfield_10030": null
+	}
+}`
+
+// v2 (Server) payload: wiki description, username users
+const issueV2JSON = `{
+	"id": "20001",
+	"key": "OPS-9",
+	"fields": {
+		"summary": "Rotate keys",
+		"description": "Rotate *all* keys",
+		"status": {"id": "1", "name": "Open", "statusCategory": {"id": 2, "key": "new", "name": "To Do"}},
+		"assignee": {"name": "jsmith", "key": "JIRAUSER10100", "displayName": "J Smith", "active": true},
+		"fixVersions": [],
+		"customfield_10002": "Ops team"
+	}
+}`
+
+func TestIssueFieldsAccessors_V3(t *testing.T) {
+	var issue Issue
+	if err := json.Unmarshal([]byte(issueV3JSON), &issue); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	f := &issue.Fields
+
+	if f.StatusName() != "Closed" || f.StatusCategoryKey() != "done" || !f.IsDone() {
+		t.Errorf("status = %q (%q), done = %v", f.StatusName(), f.StatusCategoryKey(), f.IsDone())
+	}
+	if f.PriorityName() != "High" {
+		t.Errorf("PriorityName() = %q", f.PriorityName())
+	}
+	if f.AssigneeID() != "5b10ac8d82e05b22cc7d4ef5" {
+		t.Errorf("AssigneeID() = %q", f.AssigneeID())
+	}
+	if got := f.ComponentNames(); !slices.Equal(got, []string{"api", "web"}) {
+		t.Errorf("ComponentNames() = %v", got)
+	}
+	if got := f.FixVersionNames(); !slices.Equal(got, []string{"1.2.0"}) {
+		t.Errorf("FixVersionNames() = %v", got)
+	}
+	if release, err := f.FixVersions[0].ReleaseTime(); err != nil || !release.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ReleaseTime() = %v, %v", release, err)
+	}
+
+	if due, err := f.DueDateTime(); err != nil || !due.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("DueDateTime() = %v, %v", due, err)
+	}
+	if resolved, err := f.ResolvedTime(); err != nil || !resolved.Equal(time.Date(2025, 1, 20, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("ResolvedTime() = %v, %v", resolved, err)
+	}
+
+	if md, err := f.DescriptionMarkdown(); err != nil || md != "Needs **SSO**" {
+		t.Errorf("DescriptionMarkdown() = %q, %v", md, err)
+	}
+
+	if points, ok := f.CustomFieldNumber("customfield_10016"); !ok || points != 5 {
+		t.Errorf("CustomFieldNumber() = %v, %v", points, ok)
+	}
+	if team, ok := f.CustomFieldString("customfield_10020"); !ok || team != "Platform" {
+		t.Errorf("CustomFieldString(select) = %q, %v", team, ok)
+	}
+	if _, ok := f.CustomFields["customfield_10030"]; ok {
+		t.Error("null custom field should be omitted")
+	}
+
+	var option struct {
+		ID    string `json:"id"`
+		Value string `json:"value"`
+	}
+	if err := f.CustomField("customfield_10020", &option); err != nil || option.ID != "10100" {
+		t.Errorf("CustomField() = %+v, %v", option, err)
+	}
+	if err := f.CustomField("customfield_99999", &option); !errors.Is(err, ErrCustomFieldNotSet) {
+		t.Errorf("CustomField(missing) error = %v, want ErrCustomFieldNotSet", err)
+	}
+}
+
+func TestIssueFieldsAccessors_V2(t *testing.T) {
+	var issue Issue
+	if err := json.Unmarshal([]byte(issueV2JSON), &issue); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	f := &issue.Fields
+
+	if f.StatusName() != "Open" || f.IsDone() {
+		t.Errorf("status = %q, done = %v", f.StatusName(), f.IsDone())
+	}
+	if f.PriorityName() != "" {
+		t.Errorf("PriorityName() = %q, want empty", f.PriorityName())
+	}
+	if f.AssigneeID() != "jsmith" {
+		t.Errorf("AssigneeID() = %q, want jsmith", f.AssigneeID())
+	}
+	if len(f.FixVersionNames()) != 0 {
+		t.Errorf("FixVersionNames() = %v, want none", f.FixVersionNames())
+	}
+	if due, err := f.DueDateTime(); err != nil || !due.IsZero() {
+		t.Errorf("DueDateTime() = %v, %v, want zero", due, err)
+	}
+	if md, err := f.DescriptionMarkdown(); err != nil || md != "Rotate **all** keys" {
+		t.Errorf("DescriptionMarkdown() = %q, %v", md, err)
+	}
+	if team, ok := f.CustomFieldString("customfield_10002"); !ok || team != "Ops team" {
+		t.Errorf("CustomFieldString() = %q, %v", team, ok)
+	}
+}
+
+func TestIssueFieldsAccessors_Unset(t *testing.T) {
+	var f IssueFields
+	if f.StatusName() != "" || f.StatusCategoryKey() != "" || f.AssigneeID() != "" {
+		t.Error("accessors on empty fields should return empty values")
+	}
+	if md, err := f.DescriptionMarkdown(); err != nil || md != "" {
+		t.Errorf("DescriptionMarkdown() = %q, %v", md, err)
+	}
+}
+
+func TestIssueFieldsJSONRoundTrip(t *testing.T) {
+	var issue Issue
+	if err := json.Unmarshal([]byte(issueV3JSON), &issue); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	data, err := json.Marshal(issue)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var again Issue
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if points, ok := again.Fields.CustomFieldNumber("customfield_10016"); !ok || points != 5 {
+		t.Errorf("custom field after round trip = %v, %v", points, ok)
+	}
+	if again.Fields.StatusName() != "Closed" {
+		t.Errorf("status after round trip = %q", again.Fields.StatusName())
+	}
+}
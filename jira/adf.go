@@ -3,7 +3,11 @@ package jira
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 // ADFDocument represents an Atlassian Document Format document.
@@ -236,125 +240,300 @@ func Strikethrough(text string) ADFNode {
 }
 
 // ADFConverter handles conversion between Markdown and ADF.
+//
+// It supports the Markdown used in specs and comments: headings, paragraphs
+// (line breaks become hard breaks), **strong**, *em*, ~~strike~~, `code`,
+// [links](url), fenced code blocks with a language, block quotes, nested
+// bullet and ordered lists, pipe tables, and horizontal rules. ADF nodes
+// without a Markdown equivalent use conventions that survive a round trip:
+//
+//	[@Jane Doe](mention:5b10ac8d82e05b22cc7d4ef5)  // Mention
+//
+//	> [!WARNING]                                   // Panel: NOTE (info), TIP (success),
+//	> Run the migration first.                     // IMPORTANT (note), WARNING, CAUTION (error)
 type ADFConverter struct{}
 
+// mentionScheme marks a Markdown link as a mention: [@Name](mention:<id>)
+const mentionScheme = "mention:"
+
+// panelAlerts maps GitHub-style alert names to ADF panel types
+var panelAlerts = map[string]string{
+	"NOTE":      "info",
+	"TIP":       "success",
+	"IMPORTANT": "note",
+	"WARNING":   "warning",
+	"CAUTION":   "error",
+}
+
+// markOrder is the nesting order of marks, outermost first. Marks without
+// a Markdown equivalent (underline, color) are not listed and are dropped.
+var markOrder = []string{ADFMarkLink, ADFMarkStrong, ADFMarkEm, ADFMarkStrike, ADFMarkCode}
+
+// tableDelimiter matches a table's header separator row: | --- | :-: |
+var tableDelimiter = regexp.MustCompile(`^\|?(\s*:?-+:?\s*\|)*\s*:?-+:?\s*\|?$`)
+
 // NewADFConverter creates a new ADF converter.
 func NewADFConverter() *ADFConverter {
 	return &ADFConverter{}
 }
 
 // ToADF converts Markdown text to an ADF document.
-// This is a simplified converter that handles basic markdown.
 func (c *ADFConverter) ToADF(markdown string) (*ADFDocument, error) {
 	doc := NewADFDocument()
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	doc.Content = append(doc.Content, c.parseBlocks(lines)...)
+	return doc, nil
+}
 
-	lines := strings.Split(markdown, "\n")
-	i := 0
-
-	for i < len(lines) {
+// parseBlocks parses lines into block nodes
+func (c *ADFConverter) parseBlocks(lines []string) []ADFNode {
+	var nodes []ADFNode
+	for i := 0; i < len(lines); {
 		line := lines[i]
+		var node ADFNode
 
-		// Empty line
-		if strings.TrimSpace(line) == "" {
+		switch {
+		case strings.TrimSpace(line) == "":
 			i++
 			continue
+		case isRule(line):
+			node = ADFNode{Type: ADFNodeRule}
+			i++
+		case fenceOf(line) != "":
+			node, i = c.parseCodeBlock(lines, i)
+		case isBlockquote(line):
+			node, i = c.parseBlockquote(lines, i)
+		case isTableStart(lines, i):
+			node, i = c.parseTable(lines, i)
+		default:
+			if level, text, ok := parseHeading(line); ok {
+				node = ADFNode{
+					Type:    ADFNodeHeading,
+					Attrs:   map[string]any{"level": level},
+					Content: c.parseInline(text, nil),
+				}
+				i++
+			} else if _, ok := parseListMarker(line); ok {
+				node, i = c.parseList(lines, i)
+			} else {
+				node, i = c.parseParagraph(lines, i)
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// parseParagraph parses lines up to a blank line or the start of another
+// block; each line break becomes a hard break.
+func (c *ADFConverter) parseParagraph(lines []string, i int) (ADFNode, int) {
+	node := ADFNode{Type: ADFNodeParagraph}
+	for start := i; i < len(lines); i++ {
+		if i > start && (strings.TrimSpace(lines[i]) == "" || startsBlock(lines, i)) {
+			break
 		}
+		if i > start {
+			node.Content = append(node.Content, ADFNode{Type: ADFNodeHardBreak})
+		}
+		node.Content = append(node.Content, c.parseInline(strings.TrimSpace(lines[i]), nil)...)
+	}
+	return node, i
+}
 
-		// Heading
-		if strings.HasPrefix(line, "#") {
-			level := 0
-			for _, ch := range line {
-				if ch == '#' {
-					level++
-				} else {
+// parseCodeBlock parses a fenced code block starting at lines[i]
+func (c *ADFConverter) parseCodeBlock(lines []string, i int) (ADFNode, int) {
+	fence := fenceOf(lines[i])
+	node := ADFNode{Type: ADFNodeCodeBlock}
+	if language := strings.TrimSpace(lines[i][len(fence):]); language != "" {
+		node.Attrs = map[string]any{"language": language}
+	}
+
+	var code []string
+	for i++; i < len(lines); i++ {
+		closing := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(closing, fence) && strings.Trim(closing, fence[:1]) == "" {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+	if text := strings.Join(code, "\n"); text != "" {
+		node.Content = []ADFNode{{Type: ADFNodeText, Text: text}}
+	}
+	return node, i
+}
+
+// parseBlockquote parses consecutive "> " lines as a blockquote, or as a
+// panel if the first line is an alert such as [!NOTE].
+func (c *ADFConverter) parseBlockquote(lines []string, i int) (ADFNode, int) {
+	var inner []string
+	for ; i < len(lines) && isBlockquote(lines[i]); i++ {
+		line := strings.TrimPrefix(strings.TrimLeft(lines[i], " "), ">")
+		inner = append(inner, strings.TrimPrefix(line, " "))
+	}
+
+	alert := strings.ToUpper(strings.TrimSpace(inner[0]))
+	if strings.HasPrefix(alert, "[!") && strings.HasSuffix(alert, "]") {
+		if panelType, ok := panelAlerts[alert[2:len(alert)-1]]; ok {
+			return ADFNode{
+				Type:    ADFNodePanel,
+				Attrs:   map[string]any{"panelType": panelType},
+				Content: c.parseBlocks(inner[1:]),
+			}, i
+		}
+	}
+	return ADFNode{Type: ADFNodeBlockquote, Content: c.parseBlocks(inner)}, i
+}
+
+// parseList parses a bullet or ordered list starting at lines[i]. Lines
+// indented past the item's marker belong to the item, so nested lists and
+// code blocks are parsed from them.
+func (c *ADFConverter) parseList(lines []string, i int) (ADFNode, int) {
+	first, _ := parseListMarker(lines[i])
+	node := ADFNode{Type: ADFNodeBulletList}
+	if first.ordered {
+		node.Type = ADFNodeOrderedList
+		if first.start != 1 {
+			node.Attrs = map[string]any{"order": first.start}
+		}
+	}
+
+	for i < len(lines) {
+		m, ok := parseListMarker(lines[i])
+		if !ok || m.indent != first.indent || m.ordered != first.ordered {
+			break
+		}
+
+		item := []string{lines[i][m.width:]}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				next := nextNonBlank(lines, i)
+				if next == len(lines) || indentOf(lines[next]) <= m.indent {
 					break
 				}
-			}
-			if level > 0 && level <= 6 {
-				text := strings.TrimSpace(strings.TrimLeft(line, "#"))
-				doc.AddHeading(level, text)
-				i++
+				item = append(item, "")
 				continue
 			}
+			if indentOf(line) <= m.indent {
+				break
+			}
+			item = append(item, line[min(indentOf(line), m.width):])
 		}
 
-		// Horizontal rule
-		if line == "---" || line == "***" || line == "___" {
-			doc.AddRule()
-			i++
-			continue
+		content := c.parseBlocks(item)
+		if len(content) == 0 {
+			content = []ADFNode{{Type: ADFNodeParagraph}}
 		}
+		node.Content = append(node.Content, ADFNode{Type: ADFNodeListItem, Content: content})
 
-		// Code block
-		if strings.HasPrefix(line, "```") {
-			language := strings.TrimPrefix(line, "```")
-			var codeLines []string
-			i++
-			for i < len(lines) && !strings.HasPrefix(lines[i], "```") {
-				codeLines = append(codeLines, lines[i])
-				i++
+		// Blank lines may separate items
+		if next := nextNonBlank(lines, i); next < len(lines) {
+			if m, ok := parseListMarker(lines[next]); ok && m.indent == first.indent && m.ordered == first.ordered {
+				i = next
 			}
-			i++ // Skip closing ```
-			doc.AddCodeBlock(strings.Join(codeLines, "\n"), language)
-			continue
 		}
+	}
+	return node, i
+}
 
-		// Blockquote
-		if strings.HasPrefix(line, "> ") {
-			text := strings.TrimPrefix(line, "> ")
-			doc.AddBlockquote(text)
-			i++
-			continue
+// parseTable parses a pipe table whose header row is lines[i]
+func (c *ADFConverter) parseTable(lines []string, i int) (ADFNode, int) {
+	header := splitTableRow(lines[i])
+	node := ADFNode{Type: ADFNodeTable, Content: []ADFNode{c.tableRow(header, len(header), ADFNodeTableHeader)}}
+	for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+		node.Content = append(node.Content, c.tableRow(splitTableRow(lines[i]), len(header), ADFNodeTableCell))
+	}
+	return node, i
+}
+
+// tableRow builds a row of width cells of the given type
+func (c *ADFConverter) tableRow(cells []string, width int, cellType string) ADFNode {
+	row := ADFNode{Type: ADFNodeTableRow}
+	for j := range width {
+		paragraph := ADFNode{Type: ADFNodeParagraph}
+		if j < len(cells) {
+			paragraph.Content = c.parseInline(cells[j], nil)
 		}
+		row.Content = append(row.Content, ADFNode{Type: cellType, Content: []ADFNode{paragraph}})
+	}
+	return row
+}
 
-		// Bullet list
-		if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") {
-			var items []string
-			for i < len(lines) && (strings.HasPrefix(lines[i], "- ") || strings.HasPrefix(lines[i], "* ")) {
-				item := strings.TrimPrefix(strings.TrimPrefix(lines[i], "- "), "* ")
-				items = append(items, item)
-				i++
+// parseInline parses inline Markdown into text and mention nodes, adding
+// marks to every text node.
+func (c *ADFConverter) parseInline(text string, marks []ADFMark) []ADFNode {
+	var nodes []ADFNode
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() > 0 {
+			nodes = appendText(nodes, buf.String(), marks)
+			buf.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		ch := text[i]
+		switch ch {
+		case '\\':
+			if i+1 < len(text) && isPunct(text[i+1]) {
+				buf.WriteByte(text[i+1])
+				i += 2
+				continue
 			}
-			doc.AddBulletList(items)
+
+		case '`':
+			if code, n, ok := parseCodeSpan(text[i:]); ok {
+				flush()
+				// Code combines only with links in ADF
+				var codeMarks []ADFMark
+				for _, m := range marks {
+					if m.Type == ADFMarkLink {
+						codeMarks = append(codeMarks, m)
+					}
+				}
+				nodes = appendText(nodes, code, withMark(codeMarks, ADFMark{Type: ADFMarkCode}))
+				i += n
+				continue
+			}
+			run := runLength(text[i:], ch)
+			buf.WriteString(text[i : i+run])
+			i += run
 			continue
-		}
 
-		// Ordered list
-		if len(line) > 2 && line[0] >= '0' && line[0] <= '9' && strings.Contains(line[:3], ". ") {
-			var items []string
-			for i < len(lines) {
-				l := lines[i]
-				if len(l) > 2 && l[0] >= '0' && l[0] <= '9' && strings.Contains(l[:4], ". ") {
-					idx := strings.Index(l, ". ")
-					items = append(items, l[idx+2:])
-					i++
+		case '[':
+			if label, href, n, ok := parseLink(text[i:]); ok {
+				flush()
+				if id, isMention := strings.CutPrefix(href, mentionScheme); isMention {
+					nodes = append(nodes, ADFNode{
+						Type:  ADFNodeMention,
+						Attrs: map[string]any{"id": id, "text": unescapeMarkdown(label)},
+					})
 				} else {
-					break
+					link := ADFMark{Type: ADFMarkLink, Attrs: map[string]any{"href": href}}
+					nodes = append(nodes, c.parseInline(label, withMark(marks, link))...)
 				}
+				i += n
+				continue
+			}
+
+		case '*', '_', '~':
+			if inner, markType, n, ok := parseEmphasis(text, i); ok {
+				flush()
+				nodes = append(nodes, c.parseInline(inner, withMark(marks, ADFMark{Type: markType}))...)
+				i += n
+				continue
 			}
-			doc.AddOrderedList(items)
+			run := runLength(text[i:], ch)
+			buf.WriteString(text[i : i+run])
+			i += run
 			continue
 		}
-
-		// Regular paragraph with inline formatting
-		paragraph := c.parseInlineFormatting(line)
-		doc.Content = append(doc.Content, paragraph)
+		buf.WriteByte(ch)
 		i++
 	}
-
-	return doc, nil
-}
-
-// parseInlineFormatting parses inline markdown formatting into ADF nodes.
-func (c *ADFConverter) parseInlineFormatting(text string) ADFNode {
-	// For simplicity, just return plain text
-	// A full implementation would parse **bold**, *italic*, `code`, [links](url), etc.
-	return ADFNode{
-		Type: ADFNodeParagraph,
-		Content: []ADFNode{
-			{Type: ADFNodeText, Text: text},
-		},
-	}
+	flush()
+	return nodes
 }
 
 // FromADF converts an ADF document to Markdown.
@@ -362,13 +541,7 @@ func (c *ADFConverter) FromADF(doc *ADFDocument) (string, error) {
 	if err := doc.Validate(); err != nil {
 		return "", err
 	}
-
-	var result strings.Builder
-	for i := range doc.Content {
-		c.nodeToMarkdown(&result, &doc.Content[i], 0)
-	}
-
-	return strings.TrimSpace(result.String()), nil
+	return strings.TrimSpace(c.blocksToMarkdown(doc.Content, false)), nil
 }
 
 // FromADFAny converts an ADF document from any type (for JSON unmarshaling).
@@ -396,133 +569,632 @@ func (c *ADFConverter) FromADFAny(v any) (string, error) {
 	return c.FromADF(&doc)
 }
 
-func (c *ADFConverter) nodeToMarkdown(w *strings.Builder, node *ADFNode, depth int) {
+// blocksToMarkdown renders block nodes separated by blank lines. Tight
+// blocks (inside list items) are separated by single line breaks, except
+// between paragraphs.
+func (c *ADFConverter) blocksToMarkdown(nodes []ADFNode, tight bool) string {
+	var w strings.Builder
+	prev := ""
+	for i := range nodes {
+		block := c.nodeToMarkdown(&nodes[i])
+		if block == "" {
+			continue
+		}
+		if w.Len() > 0 {
+			if tight && (prev != ADFNodeParagraph || nodes[i].Type != ADFNodeParagraph) {
+				w.WriteString("\n")
+			} else {
+				w.WriteString("\n\n")
+			}
+		}
+		w.WriteString(block)
+		prev = nodes[i].Type
+	}
+	return w.String()
+}
+
+func (c *ADFConverter) nodeToMarkdown(node *ADFNode) string {
 	switch node.Type {
 	case ADFNodeParagraph:
-		c.inlineToMarkdown(w, node.Content)
-		w.WriteString("\n\n")
+		return escapeBlockStarts(c.inlineToMarkdown(node.Content, false))
 
 	case ADFNodeHeading:
-		level := 1
-		if l, ok := node.Attrs["level"].(float64); ok {
-			level = int(l)
-		}
-		w.WriteString(strings.Repeat("#", level))
-		w.WriteString(" ")
-		c.inlineToMarkdown(w, node.Content)
-		w.WriteString("\n\n")
+		level := min(max(attrInt(node.Attrs, "level", 1), 1), 6)
+		return strings.Repeat("#", level) + " " + c.inlineToMarkdown(node.Content, false)
 
 	case ADFNodeCodeBlock:
-		lang := ""
-		if l, ok := node.Attrs["language"].(string); ok {
-			lang = l
+		var code strings.Builder
+		for _, text := range node.Content {
+			code.WriteString(text.Text)
 		}
-		w.WriteString("```")
-		w.WriteString(lang)
-		w.WriteString("\n")
-		c.inlineToMarkdown(w, node.Content)
-		w.WriteString("\n```\n\n")
+		lang, _ := node.Attrs["language"].(string)
+		fence := strings.Repeat("`", max(3, longestRun(code.String(), '`')+1))
+		return fence + lang + "\n" + code.String() + "\n" + fence
 
 	case ADFNodeBlockquote:
-		for i := range node.Content {
-			w.WriteString("> ")
-			c.nodeToMarkdown(w, &node.Content[i], depth)
-		}
+		return prefixLines(c.blocksToMarkdown(node.Content, false), "> ")
 
-	case ADFNodeBulletList:
-		for _, item := range node.Content {
-			w.WriteString("- ")
-			for _, content := range item.Content {
-				c.inlineToMarkdown(w, content.Content)
+	case ADFNodePanel:
+		panelType, _ := node.Attrs["panelType"].(string)
+		alert := "NOTE"
+		for name, t := range panelAlerts {
+			if t == panelType {
+				alert = name
 			}
-			w.WriteString("\n")
 		}
-		w.WriteString("\n")
+		return prefixLines("[!"+alert+"]\n"+c.blocksToMarkdown(node.Content, false), "> ")
 
-	case ADFNodeOrderedList:
-		for i, item := range node.Content {
-			fmt.Fprintf(w, "%d. ", i+1)
-			for _, content := range item.Content {
-				c.inlineToMarkdown(w, content.Content)
+	case ADFNodeBulletList, ADFNodeOrderedList:
+		start := attrInt(node.Attrs, "order", 1)
+		items := make([]string, 0, len(node.Content))
+		for i := range node.Content {
+			marker := "- "
+			if node.Type == ADFNodeOrderedList {
+				marker = strconv.Itoa(start+i) + ". "
 			}
-			w.WriteString("\n")
+			body := c.blocksToMarkdown(node.Content[i].Content, true)
+			indent := strings.Repeat(" ", len(marker))
+			items = append(items, marker+strings.TrimLeft(prefixLines(body, indent), " "))
 		}
-		w.WriteString("\n")
+		return strings.Join(items, "\n")
+
+	case ADFNodeTable:
+		return c.tableToMarkdown(node)
 
 	case ADFNodeRule:
-		w.WriteString("---\n\n")
+		return "---"
 
-	case ADFNodeText:
-		c.textToMarkdown(w, node)
+	case ADFNodeText, ADFNodeMention, ADFNodeEmoji, ADFNodeInlineCard, ADFNodeHardBreak:
+		return c.inlineToMarkdown([]ADFNode{*node}, false)
 
 	default:
 		// For unknown types, try to process content
-		for i := range node.Content {
-			c.nodeToMarkdown(w, &node.Content[i], depth+1)
+		return c.blocksToMarkdown(node.Content, false)
+	}
+}
+
+// tableToMarkdown renders a table as a pipe table with its first row as
+// the header
+func (c *ADFConverter) tableToMarkdown(node *ADFNode) string {
+	var rows []string
+	for r, row := range node.Content {
+		cells := make([]string, len(row.Content))
+		for i, cell := range row.Content {
+			var inline []ADFNode
+			for j, block := range cell.Content {
+				if j > 0 {
+					inline = append(inline, ADFNode{Type: ADFNodeText, Text: " "})
+				}
+				inline = append(inline, block.Content...)
+			}
+			cells[i] = c.inlineToMarkdown(inline, true)
+		}
+		rows = append(rows, "| "+strings.Join(cells, " | ")+" |")
+		if r == 0 {
+			rows = append(rows, "|"+strings.Repeat(" --- |", len(cells)))
 		}
 	}
+	return strings.Join(rows, "\n")
 }
 
-func (c *ADFConverter) inlineToMarkdown(w *strings.Builder, nodes []ADFNode) {
+// inlineToMarkdown renders inline nodes. In table cells, pipes are escaped
+// and hard breaks become spaces.
+func (c *ADFConverter) inlineToMarkdown(nodes []ADFNode, inTable bool) string {
+	w := &inlineWriter{inTable: inTable}
 	for i := range nodes {
 		node := &nodes[i]
 		switch node.Type {
 		case ADFNodeText:
-			c.textToMarkdown(w, node)
+			w.text(node.Text, node.Marks)
 		case ADFNodeHardBreak:
-			w.WriteString("\n")
+			if inTable {
+				w.text(" ", nil)
+			} else {
+				w.raw("\n")
+			}
 		case ADFNodeMention:
-			if id, ok := node.Attrs["id"].(string); ok {
-				w.WriteString("@")
-				w.WriteString(id)
+			id, _ := node.Attrs["id"].(string)
+			text, _ := node.Attrs["text"].(string)
+			if text == "" {
+				text = "@" + id
 			}
+			w.raw("[" + w.escape(text) + "](" + mentionScheme + id + ")")
 		case ADFNodeEmoji:
-			if shortName, ok := node.Attrs["shortName"].(string); ok {
-				w.WriteString(shortName)
-			}
+			shortName, _ := node.Attrs["shortName"].(string)
+			w.raw(shortName)
 		case ADFNodeInlineCard:
-			if url, ok := node.Attrs["url"].(string); ok {
-				w.WriteString(url)
-			}
+			url, _ := node.Attrs["url"].(string)
+			w.raw(url)
 		default:
 			// Unknown inline, try to extract text
-			c.inlineToMarkdown(w, node.Content)
-		}
-	}
-}
-
-func (c *ADFConverter) textToMarkdown(w *strings.Builder, node *ADFNode) {
-	text := node.Text
-
-	// Apply marks in reverse order for proper nesting
-	prefix := ""
-	suffix := ""
-
-	for _, mark := range node.Marks {
-		switch mark.Type {
-		case ADFMarkStrong:
-			prefix = "**" + prefix
-			suffix += "**"
-		case ADFMarkEm:
-			prefix = "*" + prefix
-			suffix += "*"
-		case ADFMarkStrike:
-			prefix = "~~" + prefix
-			suffix += "~~"
-		case ADFMarkCode:
-			prefix = "`" + prefix
-			suffix += "`"
-		case ADFMarkLink:
-			if href, ok := mark.Attrs["href"].(string); ok {
-				prefix = "[" + prefix
-				suffix = suffix + "](" + href + ")"
+			w.raw(c.inlineToMarkdown(node.Content, inTable))
+		}
+	}
+	return w.String()
+}
+
+// inlineWriter renders text nodes, opening and closing Markdown delimiters
+// as marks change between nodes so that adjacent nodes share them.
+type inlineWriter struct {
+	b       strings.Builder
+	open    []ADFMark
+	pending string // Trailing whitespace, written after closing delimiters
+	inTable bool
+}
+
+// text writes text with marks
+func (w *inlineWriter) text(text string, marks []ADFMark) {
+	marks = sortMarks(marks)
+	var code bool
+	if n := len(marks); n > 0 && marks[n-1].Type == ADFMarkCode {
+		marks, code = marks[:n-1], true
+	}
+
+	common := 0
+	for common < len(w.open) && common < len(marks) && markEqual(w.open[common], marks[common]) {
+		common++
+	}
+	w.closeTo(common)
+
+	core := strings.TrimSpace(text)
+	if core == "" {
+		w.pending += text
+		return
+	}
+	w.b.WriteString(w.pending + text[:strings.Index(text, core)])
+	w.pending = text[strings.Index(text, core)+len(core):]
+	for _, m := range marks[common:] {
+		w.b.WriteString(markOpener(m))
+		w.open = append(w.open, m)
+	}
+
+	if code {
+		fence := strings.Repeat("`", longestRun(core, '`')+1)
+		if strings.HasPrefix(core, "`") || strings.HasSuffix(core, "`") {
+			core = " " + core + " "
+		}
+		w.b.WriteString(fence + core + fence)
+	} else {
+		w.b.WriteString(w.escape(core))
+	}
+}
+
+// raw closes all marks and writes s as is
+func (w *inlineWriter) raw(s string) {
+	w.closeTo(0)
+	w.b.WriteString(s)
+}
+
+// closeTo closes open marks down to the first n
+func (w *inlineWriter) closeTo(n int) {
+	for len(w.open) > n {
+		w.b.WriteString(markCloser(w.open[len(w.open)-1]))
+		w.open = w.open[:len(w.open)-1]
+	}
+	w.b.WriteString(w.pending)
+	w.pending = ""
+}
+
+// escape backslash-escapes characters that would otherwise start inline
+// Markdown
+func (w *inlineWriter) escape(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case ch == '\\' || ch == '*' || ch == '`' || ch == '[' || ch == ']',
+			ch == '_' && (i == 0 || i+1 == len(text) || !isAlnum(text[i-1]) || !isAlnum(text[i+1])),
+			ch == '~' && (i+1 < len(text) && text[i+1] == '~' || i > 0 && text[i-1] == '~'),
+			ch == '|' && w.inTable:
+			b.WriteByte('\\')
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
+
+// String closes all marks and returns the Markdown
+func (w *inlineWriter) String() string {
+	w.closeTo(0)
+	return w.b.String()
+}
+
+// markOpener returns the Markdown that opens a mark
+func markOpener(m ADFMark) string {
+	switch m.Type {
+	case ADFMarkLink:
+		return "["
+	case ADFMarkStrong:
+		return "**"
+	case ADFMarkEm:
+		return "*"
+	case ADFMarkStrike:
+		return "~~"
+	}
+	return ""
+}
+
+// markCloser returns the Markdown that closes a mark
+func markCloser(m ADFMark) string {
+	if m.Type == ADFMarkLink {
+		href, _ := m.Attrs["href"].(string)
+		return "](" + href + ")"
+	}
+	return markOpener(m)
+}
+
+// sortMarks returns the marks that have a Markdown equivalent in nesting
+// order
+func sortMarks(marks []ADFMark) []ADFMark {
+	sorted := make([]ADFMark, 0, len(marks))
+	for _, t := range markOrder {
+		for _, m := range marks {
+			if m.Type == t {
+				sorted = append(sorted, m)
+				break
+			}
+		}
+	}
+	return sorted
+}
+
+// markEqual reports whether two marks are the same, including link targets
+func markEqual(a, b ADFMark) bool {
+	if a.Type != b.Type {
+		return false
+	}
+	if a.Type == ADFMarkLink {
+		return a.Attrs["href"] == b.Attrs["href"]
+	}
+	return true
+}
+
+// withMark returns marks plus m, in nesting order
+func withMark(marks []ADFMark, m ADFMark) []ADFMark {
+	return sortMarks(append(slices.Clone(marks), m))
+}
+
+// appendText appends a text node, merging it into the previous node if
+// their marks match
+func appendText(nodes []ADFNode, text string, marks []ADFMark) []ADFNode {
+	if n := len(nodes); n > 0 && nodes[n-1].Type == ADFNodeText &&
+		slices.EqualFunc(nodes[n-1].Marks, marks, markEqual) {
+		nodes[n-1].Text += text
+		return nodes
+	}
+	return append(nodes, ADFNode{Type: ADFNodeText, Text: text, Marks: marks})
+}
+
+// parseEmphasis parses **strong**, *em* (or with _), or ~~strike~~ at
+// text[i], returning the inner text, mark type, and length consumed.
+func parseEmphasis(text string, i int) (string, string, int, bool) {
+	ch := text[i]
+	run := runLength(text[i:], ch)
+	if ch == '_' && i > 0 && isAlnum(text[i-1]) {
+		return "", "", 0, false
+	}
+
+	var candidates []string
+	switch {
+	case ch == '~' && run >= 2:
+		candidates = []string{"~~"}
+	case ch != '~' && run >= 2:
+		candidates = []string{strings.Repeat(string(ch), 2), string(ch)}
+	case ch != '~':
+		candidates = []string{string(ch)}
+	}
+
+	for _, delim := range candidates {
+		start := i + len(delim)
+		if start >= len(text) || text[start] == ' ' {
+			continue
+		}
+		if end, ok := findCloser(text, start, delim); ok {
+			markType := ADFMarkEm
+			switch {
+			case ch == '~':
+				markType = ADFMarkStrike
+			case len(delim) == 2:
+				markType = ADFMarkStrong
+			}
+			return text[start:end], markType, end + len(delim) - i, true
+		}
+	}
+	return "", "", 0, false
+}
+
+// findCloser finds the closing delimiter for an emphasis opened before
+// text[start]. A run of the delimiter character closes it if it is not
+// preceded by a space and is not a different delimiter (a ** run does not
+// close *); in a longer run such as ***, the closer is at the end.
+func findCloser(text string, start int, delim string) (int, bool) {
+	ch := delim[0]
+	for j := start; j < len(text); {
+		switch text[j] {
+		case '\\':
+			j += 2
+			continue
+		case '`':
+			if _, n, ok := parseCodeSpan(text[j:]); ok {
+				j += n
+				continue
+			}
+		case ch:
+			run := runLength(text[j:], ch)
+			end := j + run - len(delim)
+			closes := run >= len(delim) && (run != 2 || len(delim) == 2) && (run != 1 || len(delim) == 1)
+			if closes && end > start && text[j-1] != ' ' &&
+				(ch != '_' || j+run == len(text) || !isAlnum(text[j+run])) {
+				return end, true
+			}
+			j += run
+			continue
+		}
+		j++
+	}
+	return 0, false
+}
+
+// parseCodeSpan parses a `code` span at the start of s, returning its
+// content and length
+func parseCodeSpan(s string) (string, int, bool) {
+	n := runLength(s, '`')
+	for j := n; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		run := runLength(s[j:], '`')
+		if run == n {
+			code := s[n:j]
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+				code = code[1 : len(code)-1]
+			}
+			return code, j + run, true
+		}
+		j += run
+	}
+	return "", 0, false
+}
+
+// parseLink parses [label](href) at the start of s, returning the label,
+// href, and length
+func parseLink(s string) (string, string, int, bool) {
+	depth := 0
+	for j := 0; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if j+1 >= len(s) || s[j+1] != '(' {
+				return "", "", 0, false
+			}
+			parens := 0
+			for k := j + 1; k < len(s); k++ {
+				switch s[k] {
+				case '(':
+					parens++
+				case ')':
+					parens--
+					if parens == 0 {
+						href := s[j+2 : k]
+						if href == "" || strings.ContainsAny(href, " \t") {
+							return "", "", 0, false
+						}
+						return s[1:j], href, k + 1, true
+					}
+				}
 			}
+			return "", "", 0, false
 		}
 	}
+	return "", "", 0, false
+}
+
+// listMarker describes a list item's marker line
+type listMarker struct {
+	indent  int  // Spaces before the marker
+	width   int  // Indent of the item's content
+	ordered bool // 1. rather than -
+	start   int  // Number of an ordered item
+}
+
+// parseListMarker parses "- item", "* item", "+ item", "1. item", or
+// "1) item"
+func parseListMarker(line string) (listMarker, bool) {
+	indent := indentOf(line)
+	rest := line[indent:]
+	if len(rest) >= 2 && strings.ContainsRune("-*+", rune(rest[0])) && rest[1] == ' ' {
+		return listMarker{indent: indent, width: indent + 2}, true
+	}
+
+	digits := 0
+	for digits < len(rest) && digits < 9 && rest[digits] >= '0' && rest[digits] <= '9' {
+		digits++
+	}
+	if digits == 0 || digits+1 >= len(rest) || (rest[digits] != '.' && rest[digits] != ')') || rest[digits+1] != ' ' {
+		return listMarker{}, false
+	}
+	start, _ := strconv.Atoi(rest[:digits])
+	return listMarker{indent: indent, width: indent + digits + 2, ordered: true, start: start}, true
+}
+
+// parseHeading parses "## Heading"
+func parseHeading(line string) (int, string, bool) {
+	level := runLength(line, '#')
+	if level == 0 || level > 6 || (len(line) > level && line[level] != ' ') {
+		return 0, "", false
+	}
+	return level, strings.TrimSpace(line[level:]), true
+}
+
+// fenceOf returns the code fence (``` or ~~~, possibly longer) that line
+// opens, or ""
+func fenceOf(line string) string {
+	for _, ch := range []byte{'`', '~'} {
+		if n := runLength(line, ch); n >= 3 {
+			if ch == '`' && strings.Contains(line[n:], "`") {
+				return ""
+			}
+			return line[:n]
+		}
+	}
+	return ""
+}
+
+// isRule reports whether line is a horizontal rule: ---, ***, or ___
+func isRule(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return len(trimmed) >= 3 && strings.Trim(trimmed, trimmed[:1]) == "" && strings.Contains("-*_", trimmed[:1])
+}
+
+// isBlockquote reports whether line starts a quote
+func isBlockquote(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, " "), ">")
+}
+
+// isTableStart reports whether lines[i] is a table header row
+func isTableStart(lines []string, i int) bool {
+	return strings.HasPrefix(strings.TrimSpace(lines[i]), "|") && i+1 < len(lines) &&
+		strings.Contains(lines[i+1], "-") && tableDelimiter.MatchString(strings.TrimSpace(lines[i+1]))
+}
+
+// startsBlock reports whether lines[i] starts a block other than a
+// paragraph
+func startsBlock(lines []string, i int) bool {
+	line := lines[i]
+	_, _, heading := parseHeading(line)
+	_, list := parseListMarker(line)
+	return heading || list || isRule(line) || fenceOf(line) != "" || isBlockquote(line) || isTableStart(lines, i)
+}
+
+// escapeBlockStarts escapes the start of paragraph lines that would
+// otherwise parse as another block, such as "- not a list"
+func escapeBlockStarts(paragraph string) string {
+	lines := strings.Split(paragraph, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if m, ok := parseListMarker(trimmed); ok && m.ordered {
+			lines[i] = trimmed[:m.width-2] + `\` + trimmed[m.width-2:]
+		} else if startsBlock([]string{trimmed}, 0) || strings.HasPrefix(trimmed, "|") {
+			lines[i] = `\` + trimmed
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// splitTableRow splits "| a | b |" into cells at unescaped pipes
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	start := 0
+	for j := 0; j < len(line); j++ {
+		switch line[j] {
+		case '\\':
+			j++
+		case '|':
+			cells = append(cells, strings.TrimSpace(line[start:j]))
+			start = j + 1
+		}
+	}
+	return append(cells, strings.TrimSpace(line[start:]))
+}
+
+// prefixLines prefixes every line of s, leaving blank lines unpadded
+func prefixLines(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// unescapeMarkdown removes backslash escapes
+func unescapeMarkdown(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isPunct(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// attrInt reads a numeric attribute, which is a float64 after decoding JSON
+func attrInt(attrs map[string]any, key string, def int) int {
+	switch v := attrs[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return int(n)
+		}
+	}
+	return def
+}
+
+// nextNonBlank returns the index of the first non-blank line from i, or
+// len(lines)
+func nextNonBlank(lines []string, i int) int {
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	return i
+}
+
+// indentOf returns the number of leading spaces
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// runLength returns how many times ch repeats at the start of s
+func runLength(s string, ch byte) int {
+	n := 0
+	for n < len(s) && s[n] == ch {
+		n++
+	}
+	return n
+}
+
+// longestRun returns the longest run of ch in s
+func longestRun(s string, ch byte) int {
+	longest := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == ch {
+			n := runLength(s[i:], ch)
+			longest = max(longest, n)
+			i += n - 1
+		}
+	}
+	return longest
+}
+
+// isPunct reports whether ch is ASCII punctuation, which may be escaped
+func isPunct(ch byte) bool {
+	return ch < 128 && unicode.IsPunct(rune(ch)) || strings.IndexByte("$+<=>^`|~", ch) >= 0
+}
 
-	w.WriteString(prefix)
-	w.WriteString(text)
-	w.WriteString(suffix)
+// isAlnum reports whether ch is an ASCII letter or digit
+func isAlnum(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
 }
 
 // MarkdownToADF is a convenience function that converts Markdown to ADF.
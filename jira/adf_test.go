@@ -0,0 +1,97 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata golden files")
+
+// TestADFGolden converts each testdata/adf/<name>.md to ADF, compares it
+// with <name>.json, and converts the JSON back to the same Markdown.
+func TestADFGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "adf", "*.md"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no golden files: %v", err)
+	}
+
+	for _, mdFile := range files {
+		name := strings.TrimSuffix(filepath.Base(mdFile), ".md")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(mdFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			markdown := strings.TrimSpace(string(data))
+			jsonFile := strings.TrimSuffix(mdFile, ".md") + ".json"
+
+			doc, err := MarkdownToADF(markdown)
+			if err != nil {
+				t.Fatalf("MarkdownToADF() error = %v", err)
+			}
+			got, err := json.MarshalIndent(doc, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+			if *updateGolden {
+				if err := os.WriteFile(jsonFile, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(jsonFile)
+			if err != nil {
+				t.Fatalf("read golden (run with -update to create): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("MarkdownToADF() mismatch with %s:\n%s", jsonFile, got)
+			}
+
+			var golden ADFDocument
+			if err := json.Unmarshal(want, &golden); err != nil {
+				t.Fatal(err)
+			}
+			back, err := ADFToMarkdown(&golden)
+			if err != nil {
+				t.Fatalf("ADFToMarkdown() error = %v", err)
+			}
+			if back != markdown {
+				t.Errorf("ADFToMarkdown() =\n%s\nwant\n%s", back, markdown)
+			}
+		})
+	}
+}
+
+func TestADFToMarkdown_MarkTransitions(t *testing.T) {
+	doc := NewADFDocument()
+	doc.Content = []ADFNode{{
+		Type: ADFNodeParagraph,
+		Content: []ADFNode{
+			Bold("bold "),
+			{Type: ADFNodeText, Text: "both", Marks: []ADFMark{{Type: ADFMarkEm}, {Type: ADFMarkStrong}}},
+			{Type: ADFNodeText, Text: " plain ", Marks: []ADFMark{{Type: ADFMarkUnderline}}},
+			Code("a`b"),
+		},
+	}}
+
+	got, err := ADFToMarkdown(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "**bold *both*** plain ``a`b``"; got != want {
+		t.Errorf("ADFToMarkdown() = %q, want %q", got, want)
+	}
+
+	// Converting back keeps the marks, minus underline
+	back, _ := MarkdownToADF(got)
+	content := back.Content[0].Content
+	if len(content) != 4 || content[1].Text != "both" || len(content[1].Marks) != 2 || content[3].Text != "a`b" {
+		t.Errorf("MarkdownToADF() = %+v", content)
+	}
+}
@@ -0,0 +1,212 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// agileAPIPath is the base path of the Jira Software (Agile) REST API,
+// which is the same on Cloud and Server.
+const agileAPIPath = "/rest/agile/1.0"
+
+// MaxSprintMoveIssues is the most issues MoveIssuesToSprint sends per request.
+const MaxSprintMoveIssues = 50
+
+// Sprint states.
+const (
+	SprintStateFuture = "future"
+	SprintStateActive = "active"
+	SprintStateClosed = "closed"
+)
+
+// Board represents a Jira Software board.
+type Board struct {
+	ID       int            `json:"id"`
+	Self     string         `json:"self,omitempty"`
+	Name     string         `json:"name"`
+	Type     string         `json:"type"` // "scrum", "kanban", "simple"
+	Location *BoardLocation `json:"location,omitempty"`
+}
+
+// BoardLocation is the project a board belongs to.
+type BoardLocation struct {
+	ProjectID   int    `json:"projectId,omitempty"`
+	ProjectKey  string `json:"projectKey,omitempty"`
+	ProjectName string `json:"projectName,omitempty"`
+}
+
+// Sprint represents a sprint on a scrum board.
+type Sprint struct {
+	ID            int    `json:"id"`
+	Self          string `json:"self,omitempty"`
+	State         string `json:"state"` // "future", "active", "closed"
+	Name          string `json:"name"`
+	StartDate     string `json:"startDate,omitempty"`
+	EndDate       string `json:"endDate,omitempty"`
+	CompleteDate  string `json:"completeDate,omitempty"`
+	OriginBoardID int    `json:"originBoardId,omitempty"`
+	Goal          string `json:"goal,omitempty"`
+}
+
+// BoardsResponse represents a page of boards.
+type BoardsResponse struct {
+	StartAt    int     `json:"startAt"`
+	MaxResults int     `json:"maxResults"`
+	Total      int     `json:"total"`
+	IsLast     bool    `json:"isLast"`
+	Values     []Board `json:"values"`
+}
+
+// SprintsResponse represents a page of sprints.
+type SprintsResponse struct {
+	StartAt    int      `json:"startAt"`
+	MaxResults int      `json:"maxResults"`
+	IsLast     bool     `json:"isLast"`
+	Values     []Sprint `json:"values"`
+}
+
+// BoardOptions filters GetBoards.
+type BoardOptions struct {
+	ProjectKey string // Boards of this project
+	Type       string // "scrum" or "kanban"
+	Name       string // Boards whose name contains this
+	StartAt    int
+	MaxResults int
+}
+
+// SprintOptions filters GetSprints.
+type SprintOptions struct {
+	State      string // SprintStateActive, SprintStateFuture, SprintStateClosed; comma-separated for several
+	StartAt    int
+	MaxResults int
+}
+
+// moveToSprintRequest is the body of a move-to-sprint request
+type moveToSprintRequest struct {
+	Issues []string `json:"issues"`
+}
+
+// GetBoards lists boards, optionally filtered by project, type, or name.
+func (c *Client) GetBoards(ctx context.Context, opts *BoardOptions) (*BoardsResponse, error) {
+	if opts == nil {
+		opts = &BoardOptions{}
+	}
+	query := url.Values{}
+	if opts.ProjectKey != "" {
+		query.Set("projectKeyOrId", opts.ProjectKey)
+	}
+	if opts.Type != "" {
+		query.Set("type", opts.Type)
+	}
+	if opts.Name != "" {
+		query.Set("name", opts.Name)
+	}
+	setPage(query, opts.StartAt, opts.MaxResults)
+
+	var result BoardsResponse
+	if err := c.send(ctx, http.MethodGet, agilePath("/board", query), nil, &result, nil); err != nil {
+		return nil, fmt.Errorf("get boards: %w", err)
+	}
+	return &result, nil
+}
+
+// GetSprints lists a board's sprints, optionally filtered by state.
+func (c *Client) GetSprints(ctx context.Context, boardID int, opts *SprintOptions) (*SprintsResponse, error) {
+	if opts == nil {
+		opts = &SprintOptions{}
+	}
+	query := url.Values{}
+	if opts.State != "" {
+		query.Set("state", opts.State)
+	}
+	setPage(query, opts.StartAt, opts.MaxResults)
+
+	path := agilePath("/board/"+strconv.Itoa(boardID)+"/sprint", query)
+	var result SprintsResponse
+	if err := c.send(ctx, http.MethodGet, path, nil, &result, ErrBoardNotFound); err != nil {
+		return nil, fmt.Errorf("get sprints of board %d: %w", boardID, err)
+	}
+	return &result, nil
+}
+
+// GetActiveSprint returns a board's current sprint. It returns
+// ErrSprintNotFound if the board has no active sprint.
+func (c *Client) GetActiveSprint(ctx context.Context, boardID int) (*Sprint, error) {
+	sprints, err := c.GetSprints(ctx, boardID, &SprintOptions{State: SprintStateActive})
+	if err != nil {
+		return nil, err
+	}
+	if len(sprints.Values) == 0 {
+		return nil, fmt.Errorf("active sprint of board %d: %w", boardID, ErrSprintNotFound)
+	}
+	return &sprints.Values[0], nil
+}
+
+// GetSprintIssues returns the issues in a sprint, optionally narrowed by
+// JQL, e.g. `assignee is EMPTY AND labels = "automation-ready"`.
+func (c *Client) GetSprintIssues(ctx context.Context, sprintID int, jql string, opts *SearchOptions) (*SearchResponse, error) {
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+	query := url.Values{}
+	if jql != "" {
+		query.Set("jql", jql)
+	}
+	if len(opts.Fields) > 0 {
+		query.Set("fields", strings.Join(opts.Fields, ","))
+	}
+	if len(opts.Expand) > 0 {
+		query.Set("expand", strings.Join(opts.Expand, ","))
+	}
+	setPage(query, opts.StartAt, opts.MaxResults)
+
+	path := agilePath("/sprint/"+strconv.Itoa(sprintID)+"/issue", query)
+	var result SearchResponse
+	if err := c.send(ctx, http.MethodGet, path, nil, &result, ErrSprintNotFound); err != nil {
+		return nil, fmt.Errorf("get issues of sprint %d: %w", sprintID, err)
+	}
+	return &result, nil
+}
+
+// MoveIssuesToSprint moves issues into a sprint, sending at most
+// MaxSprintMoveIssues per request.
+func (c *Client) MoveIssuesToSprint(ctx context.Context, sprintID int, keys ...string) error {
+	for _, key := range keys {
+		if !ValidateIssueKey(key) {
+			return fmt.Errorf("%w: %q", ErrIssueKeyInvalid, key)
+		}
+	}
+
+	path := agilePath("/sprint/"+strconv.Itoa(sprintID)+"/issue", nil)
+	for start := 0; start < len(keys); start += MaxSprintMoveIssues {
+		batch := keys[start:min(start+MaxSprintMoveIssues, len(keys))]
+		err := c.send(ctx, http.MethodPost, path, &moveToSprintRequest{Issues: batch}, nil, ErrSprintNotFound)
+		c.invalidateIssues(batch...)
+		if err != nil {
+			return fmt.Errorf("move issues to sprint %d: %w", sprintID, err)
+		}
+	}
+	return nil
+}
+
+// agilePath returns the Agile API path for endpoint with an optional query
+func agilePath(endpoint string, query url.Values) string {
+	if len(query) == 0 {
+		return agileAPIPath + endpoint
+	}
+	return agileAPIPath + endpoint + "?" + query.Encode()
+}
+
+// setPage adds pagination parameters when set
+func setPage(query url.Values, startAt, maxResults int) {
+	if startAt > 0 {
+		query.Set("startAt", strconv.Itoa(startAt))
+	}
+	if maxResults > 0 {
+		query.Set("maxResults", strconv.Itoa(maxResults))
+	}
+}
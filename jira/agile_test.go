@@ -0,0 +1,136 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient returns a client for a test server running handler
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	cfg := DefaultConfig()
+	cfg.URL = server.URL
+	cfg.APIVersion = APIVersionV3
+	cfg.Auth = AuthConfig{Type: AuthAPIToken, Email: "bot@example.com", Token: "token"}
+	cfg.RateLimit.MaxRetries = 0
+
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func TestGetBoards(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/board" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("projectKeyOrId"); got != "PROJ" {
+			t.Errorf("projectKeyOrId = %q, want PROJ", got)
+		}
+		if got := r.URL.Query().Get("type"); got != "scrum" {
+			t.Errorf("type = %q, want scrum", got)
+		}
+		fmt.Fprint(w, `{"isLast":true,"values":[{"id":7,"name":"PROJ board","type":"scrum","location":{"projectKey":"PROJ"}}]}`)
+	}))
+
+	boards, err := client.GetBoards(context.Background(), &BoardOptions{ProjectKey: "PROJ", Type: "scrum"})
+	if err != nil {
+		t.Fatalf("GetBoards() error = %v", err)
+	}
+	if len(boards.Values) != 1 || boards.Values[0].ID != 7 || boards.Values[0].Location.ProjectKey != "PROJ" {
+		t.Errorf("GetBoards() = %+v", boards)
+	}
+}
+
+func TestGetActiveSprint(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/agile/1.0/board/7/sprint":
+			if got := r.URL.Query().Get("state"); got != SprintStateActive {
+				t.Errorf("state = %q, want active", got)
+			}
+			fmt.Fprint(w, `{"isLast":true,"values":[{"id":42,"state":"active","name":"Sprint 12"}]}`)
+		case "/rest/agile/1.0/board/8/sprint":
+			fmt.Fprint(w, `{"isLast":true,"values":[]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	sprint, err := client.GetActiveSprint(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetActiveSprint() error = %v", err)
+	}
+	if sprint.ID != 42 || sprint.Name != "Sprint 12" {
+		t.Errorf("GetActiveSprint() = %+v", sprint)
+	}
+
+	if _, err := client.GetActiveSprint(context.Background(), 8); !errors.Is(err, ErrSprintNotFound) {
+		t.Errorf("no active sprint: error = %v, want ErrSprintNotFound", err)
+	}
+	if _, err := client.GetActiveSprint(context.Background(), 9); !errors.Is(err, ErrBoardNotFound) {
+		t.Errorf("unknown board: error = %v, want ErrBoardNotFound", err)
+	}
+}
+
+func TestGetSprintIssues(t *testing.T) {
+	jql := `assignee is EMPTY AND labels = "automation-ready"`
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/sprint/42/issue" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("jql"); got != jql {
+			t.Errorf("jql = %q, want %q", got, jql)
+		}
+		fmt.Fprint(w, `{"total":1,"issues":[{"key":"PROJ-1","fields":{"summary":"Automate it"}}]}`)
+	}))
+
+	result, err := client.GetSprintIssues(context.Background(), 42, jql, nil)
+	if err != nil {
+		t.Fatalf("GetSprintIssues() error = %v", err)
+	}
+	if result.Total != 1 || result.Issues[0].Key != "PROJ-1" {
+		t.Errorf("GetSprintIssues() = %+v", result)
+	}
+}
+
+func TestMoveIssuesToSprint(t *testing.T) {
+	var batches [][]string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/agile/1.0/sprint/42/issue" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		var body moveToSprintRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		batches = append(batches, body.Issues)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	keys := make([]string, MaxSprintMoveIssues+5)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("PROJ-%d", i+1)
+	}
+	if err := client.MoveIssuesToSprint(context.Background(), 42, keys...); err != nil {
+		t.Fatalf("MoveIssuesToSprint() error = %v", err)
+	}
+	if len(batches) != 2 || len(batches[0]) != MaxSprintMoveIssues || len(batches[1]) != 5 {
+		t.Errorf("batch sizes = %d batches, want %d and 5", len(batches), MaxSprintMoveIssues)
+	}
+
+	if err := client.MoveIssuesToSprint(context.Background(), 42, "bad key"); !errors.Is(err, ErrIssueKeyInvalid) {
+		t.Errorf("invalid key: error = %v, want ErrIssueKeyInvalid", err)
+	}
+}
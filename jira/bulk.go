@@ -0,0 +1,190 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// MaxBulkCreateIssues is the most issues Jira accepts in one bulk create
+// request; BulkCreateIssues splits larger batches.
+const MaxBulkCreateIssues = 50
+
+// BulkFailure is one item of a bulk operation that failed.
+type BulkFailure struct {
+	Index int    // Position in the input
+	Key   string // Issue key, for operations on existing issues
+	Err   error
+}
+
+// BulkCreateResult reports a BulkCreateIssues call.
+type BulkCreateResult struct {
+	// Issues is aligned with the input; entries for failed items are nil.
+	Issues   []*CreateIssueResponse
+	Failures []BulkFailure
+}
+
+// Err returns nil if every item succeeded, or an error wrapping
+// ErrBulkPartialFailure and each item's error.
+func (r *BulkCreateResult) Err() error {
+	return bulkErr(r.Failures, len(r.Issues))
+}
+
+// BulkTransitionResult reports a BulkTransition call.
+type BulkTransitionResult struct {
+	Transitioned []string // Keys transitioned, in input order
+	Failures     []BulkFailure
+}
+
+// Err returns nil if every item succeeded, or an error wrapping
+// ErrBulkPartialFailure and each item's error.
+func (r *BulkTransitionResult) Err() error {
+	return bulkErr(r.Failures, len(r.Transitioned)+len(r.Failures))
+}
+
+// bulkCreateRequest is the body of a bulk create request
+type bulkCreateRequest struct {
+	IssueUpdates []*CreateIssueRequest `json:"issueUpdates"`
+}
+
+// bulkCreateResponse is the response of a bulk create request. Created
+// issues are listed in input order, skipping failed elements.
+type bulkCreateResponse struct {
+	Issues []CreateIssueResponse `json:"issues"`
+	Errors []struct {
+		Status              int      `json:"status"`
+		FailedElementNumber int      `json:"failedElementNumber"`
+		ElementErrors       APIError `json:"elementErrors"`
+	} `json:"errors"`
+}
+
+// BulkCreateIssues creates issues in requests of at most
+// MaxBulkCreateIssues. Items Jira rejects, and whole requests that fail,
+// are reported in the result's Failures instead of stopping the batch; the
+// returned error is the result's Err. Creation stops early only if ctx is
+// canceled, with the remaining items reported as failed.
+func (c *Client) BulkCreateIssues(ctx context.Context, reqs []*CreateIssueRequest) (*BulkCreateResult, error) {
+	result := &BulkCreateResult{Issues: make([]*CreateIssueResponse, len(reqs))}
+
+	for start := 0; start < len(reqs); start += MaxBulkCreateIssues {
+		end := min(start+MaxBulkCreateIssues, len(reqs))
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			for i := start; i < len(reqs); i++ {
+				result.Failures = append(result.Failures, BulkFailure{Index: i, Err: ctxErr})
+			}
+			break
+		}
+
+		resp, err := c.bulkCreate(ctx, reqs[start:end])
+		if err != nil {
+			for i := start; i < end; i++ {
+				result.Failures = append(result.Failures, BulkFailure{Index: i, Err: err})
+			}
+			continue
+		}
+
+		failed := make(map[int]error, len(resp.Errors))
+		for _, e := range resp.Errors {
+			apiErr := e.ElementErrors
+			apiErr.StatusCode = e.Status
+			failed[e.FailedElementNumber] = &apiErr
+		}
+		created := 0
+		for i := start; i < end; i++ {
+			if err, ok := failed[i-start]; ok {
+				result.Failures = append(result.Failures, BulkFailure{Index: i, Err: err})
+				continue
+			}
+			if created >= len(resp.Issues) {
+				result.Failures = append(result.Failures, BulkFailure{Index: i, Err: errors.New("missing from bulk create response")})
+				continue
+			}
+			result.Issues[i] = &resp.Issues[created]
+			created++
+		}
+	}
+
+	return result, result.Err()
+}
+
+// bulkCreate sends one bulk create request. Jira answers 400 with the
+// per-element errors when every element fails, so that body is decoded too.
+func (c *Client) bulkCreate(ctx context.Context, reqs []*CreateIssueRequest) (*bulkCreateResponse, error) {
+	req, reqErr := c.newRequest(ctx, http.MethodPost, c.apiPath("/issue/bulk"), &bulkCreateRequest{IssueUpdates: reqs})
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	resp, respErr := c.doWithRetry(req)
+	if respErr != nil {
+		return nil, respErr
+	}
+	defer func() { _ = resp.Body.Close() }()
+	for _, r := range reqs {
+		if parent := r.Fields.Parent; parent != nil {
+			c.InvalidateIssue(parent.Key)
+		}
+	}
+
+	if resp.StatusCode == http.StatusBadRequest {
+		body, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			return nil, fmt.Errorf("read bulk create response: %w", readErr)
+		}
+		var result bulkCreateResponse
+		if json.Unmarshal(body, &result) == nil && len(result.Errors) > 0 {
+			return &result, nil
+		}
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if apiErr := c.checkError(resp); apiErr != nil {
+		return nil, apiErr
+	}
+
+	var result bulkCreateResponse
+	if decodeErr := json.NewDecoder(resp.Body).Decode(&result); decodeErr != nil {
+		return nil, fmt.Errorf("decode bulk create response: %w", decodeErr)
+	}
+	return &result, nil
+}
+
+// BulkTransition moves each issue through the named transition (see
+// TransitionIssueByName). Jira has no bulk transition endpoint, so issues
+// are transitioned one at a time; failures are collected rather than
+// stopping the batch, and the returned error is the result's Err.
+func (c *Client) BulkTransition(ctx context.Context, keys []string, transitionName string) (*BulkTransitionResult, error) {
+	result := &BulkTransitionResult{}
+	for i, key := range keys {
+		err := ctx.Err()
+		if err == nil {
+			err = c.TransitionIssueByName(ctx, key, transitionName)
+		}
+		if err != nil {
+			result.Failures = append(result.Failures, BulkFailure{Index: i, Key: key, Err: err})
+			continue
+		}
+		result.Transitioned = append(result.Transitioned, key)
+	}
+	return result, result.Err()
+}
+
+// bulkErr summarizes bulk failures as one error
+func bulkErr(failures []BulkFailure, total int) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	errs := make([]error, len(failures))
+	for i, f := range failures {
+		if f.Key != "" {
+			errs[i] = fmt.Errorf("%s: %w", f.Key, f.Err)
+		} else {
+			errs[i] = fmt.Errorf("item %d: %w", f.Index, f.Err)
+		}
+	}
+	return fmt.Errorf("%w: %d of %d failed: %w", ErrBulkPartialFailure, len(failures), total, errors.Join(errs...))
+}
@@ -0,0 +1,151 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func bulkRequests(n int) []*CreateIssueRequest {
+	reqs := make([]*CreateIssueRequest, n)
+	for i := range reqs {
+		reqs[i] = &CreateIssueRequest{Fields: CreateIssueFields{
+			Project:   ProjectRef{Key: "PROJ"},
+			IssueType: IssueTypeRef{Name: "Sub-task"},
+			Summary:   fmt.Sprintf("task %d", i),
+		}}
+	}
+	return reqs
+}
+
+func TestBulkCreateIssues_Chunks(t *testing.T) {
+	var calls atomic.Int32
+	next := 0
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/api/3/issue/bulk" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		calls.Add(1)
+		var body bulkCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		if len(body.IssueUpdates) > MaxBulkCreateIssues {
+			t.Errorf("chunk size = %d, want <= %d", len(body.IssueUpdates), MaxBulkCreateIssues)
+		}
+		var resp bulkCreateResponse
+		for range body.IssueUpdates {
+			next++
+			resp.Issues = append(resp.Issues, CreateIssueResponse{Key: fmt.Sprintf("PROJ-%d", next)})
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+
+	result, err := client.BulkCreateIssues(context.Background(), bulkRequests(120))
+	if err != nil {
+		t.Fatalf("BulkCreateIssues() error = %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("requests = %d, want 3", calls.Load())
+	}
+	if len(result.Issues) != 120 || result.Issues[0].Key != "PROJ-1" || result.Issues[119].Key != "PROJ-120" {
+		t.Errorf("Issues not aligned with input")
+	}
+}
+
+func TestBulkCreateIssues_PartialFailure(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"issues":[{"key":"PROJ-1"},{"key":"PROJ-2"}],"errors":[
+			{"status":400,"failedElementNumber":1,"elementErrors":{"errors":{"summary":"required"}}}
+		]}`)
+	}))
+
+	result, err := client.BulkCreateIssues(context.Background(), bulkRequests(3))
+	if !errors.Is(err, ErrBulkPartialFailure) {
+		t.Fatalf("error = %v, want ErrBulkPartialFailure", err)
+	}
+	if result.Issues[0].Key != "PROJ-1" || result.Issues[1] != nil || result.Issues[2].Key != "PROJ-2" {
+		t.Errorf("Issues = %v", result.Issues)
+	}
+	var apiErr *APIError
+	if len(result.Failures) != 1 || result.Failures[0].Index != 1 || !errors.As(result.Failures[0].Err, &apiErr) {
+		t.Fatalf("Failures = %+v", result.Failures)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Errors["summary"] != "required" {
+		t.Errorf("element error = %+v", apiErr)
+	}
+}
+
+func TestBulkCreateIssues_AllFailed(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body bulkCreateRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if len(body.IssueUpdates) == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"issues":[],"errors":[
+				{"status":400,"failedElementNumber":0,"elementErrors":{"errorMessages":["bad project"]}},
+				{"status":400,"failedElementNumber":1,"elementErrors":{"errorMessages":["bad project"]}}
+			]}`)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"errorMessages":["forbidden"]}`)
+	}))
+
+	result, err := client.BulkCreateIssues(context.Background(), bulkRequests(2))
+	if !errors.Is(err, ErrBulkPartialFailure) || len(result.Failures) != 2 {
+		t.Fatalf("BulkCreateIssues() = %+v, %v", result.Failures, err)
+	}
+	if !strings.Contains(result.Failures[1].Err.Error(), "bad project") {
+		t.Errorf("Failures[1].Err = %v", result.Failures[1].Err)
+	}
+
+	// A request-level failure marks every element in it as failed
+	result, err = client.BulkCreateIssues(context.Background(), bulkRequests(3))
+	if err == nil || len(result.Failures) != 3 {
+		t.Fatalf("BulkCreateIssues() = %+v, %v", result.Failures, err)
+	}
+	var apiErr *APIError
+	if !errors.As(result.Failures[2].Err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("Failures[2].Err = %v", result.Failures[2].Err)
+	}
+}
+
+func TestBulkTransition(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/rest/api/3/issue/PROJ-404/"):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errorMessages":["Issue does not exist"]}`)
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"transitions":[{"id":"21","name":"In Progress"}]}`)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+
+	result, err := client.BulkTransition(context.Background(), []string{"PROJ-1", "PROJ-404", "PROJ-2"}, "in progress")
+	if !errors.Is(err, ErrBulkPartialFailure) {
+		t.Fatalf("error = %v, want ErrBulkPartialFailure", err)
+	}
+	if len(result.Transitioned) != 2 || result.Transitioned[1] != "PROJ-2" {
+		t.Errorf("Transitioned = %v", result.Transitioned)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].Key != "PROJ-404" || result.Failures[0].Index != 1 {
+		t.Errorf("Failures = %+v", result.Failures)
+	}
+	if !strings.Contains(err.Error(), "PROJ-404") {
+		t.Errorf("error %q does not name the failed issue", err)
+	}
+
+	if _, err := client.BulkTransition(context.Background(), []string{"PROJ-1"}, "In Progress"); err != nil {
+		t.Errorf("BulkTransition() error = %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached responses stay fresh when WithCache is
+// given no TTL.
+const DefaultCacheTTL = 5 * time.Minute
+
+// responseCache holds raw responses of read-heavy calls (issues,
+// transitions, field metadata). Entries are decoded on every hit, so callers
+// never share values.
+type responseCache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// cacheEntry is one cached response
+type cacheEntry struct {
+	raw     json.RawMessage
+	expires time.Time
+}
+
+// WithCache caches GetIssue, GetTransitions, and GetFields responses for
+// ttl (DefaultCacheTTL if zero). Writes through the client (updates,
+// transitions, comments, assignment, links) invalidate the issues they
+// touch; changes made elsewhere show after the TTL or InvalidateIssue.
+func WithCache(ttl time.Duration) ClientOption {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return func(c *Client) {
+		c.cache = &responseCache{ttl: ttl, now: time.Now, entries: make(map[string]cacheEntry)}
+	}
+}
+
+// InvalidateIssue drops cached responses for an issue. It is a no-op
+// without WithCache.
+func (c *Client) InvalidateIssue(key string) {
+	c.cache.invalidate(issueCacheKey(key), transitionsCacheKey(key))
+}
+
+// ClearCache drops every cached response. It is a no-op without WithCache.
+func (c *Client) ClearCache() {
+	if c.cache == nil {
+		return
+	}
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+	clear(c.cache.entries)
+}
+
+// invalidateIssues drops cached responses for issues after a write
+func (c *Client) invalidateIssues(keys ...string) {
+	for _, key := range keys {
+		c.InvalidateIssue(key)
+	}
+}
+
+// cachedGet GETs path into out, serving and storing the response under
+// cacheKey when caching is enabled.
+func (c *Client) cachedGet(ctx context.Context, cacheKey, path string, out any, notFound error) error {
+	if raw, ok := c.cache.get(cacheKey); ok {
+		return json.Unmarshal(raw, out)
+	}
+
+	var raw json.RawMessage
+	if err := c.send(ctx, http.MethodGet, path, nil, &raw, notFound); err != nil {
+		return err
+	}
+	c.cache.set(cacheKey, raw)
+	return json.Unmarshal(raw, out)
+}
+
+// Cache keys
+const fieldsCacheKey = "fields"
+
+func issueCacheKey(key string) string       { return "issue:" + strings.ToUpper(key) }
+func transitionsCacheKey(key string) string { return "transitions:" + strings.ToUpper(key) }
+
+// get returns a fresh entry
+func (rc *responseCache) get(key string) (json.RawMessage, bool) {
+	if rc == nil {
+		return nil, false
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !rc.now().Before(entry.expires) {
+		delete(rc.entries, key)
+		return nil, false
+	}
+	return entry.raw, true
+}
+
+// set stores a response
+func (rc *responseCache) set(key string, raw json.RawMessage) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries[key] = cacheEntry{raw: raw, expires: rc.now().Add(rc.ttl)}
+}
+
+// invalidate drops entries
+func (rc *responseCache) invalidate(keys ...string) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for _, key := range keys {
+		delete(rc.entries, key)
+	}
+}
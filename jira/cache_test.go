@@ -0,0 +1,145 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// countingServer serves issues, transitions, and fields, counting GETs per
+// path
+func countingServer(t *testing.T, gets map[string]int, opts ...ClientOption) *Client {
+	t.Helper()
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		gets[r.URL.Path]++
+		switch r.URL.Path {
+		case "/rest/api/3/issue/PROJ-1":
+			fmt.Fprintf(w, `{"key":"PROJ-1","fields":{"summary":"v%d"}}`, gets[r.URL.Path])
+		case "/rest/api/3/issue/PROJ-1/transitions":
+			fmt.Fprint(w, `{"transitions":[{"id":"21","name":"Done"}]}`)
+		case "/rest/api/3/field":
+			fmt.Fprint(w, `[{"id":"customfield_10016","name":"Story Points","custom":true,"schema":{"type":"number"}}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
+func TestCache_ServesRepeatedReads(t *testing.T) {
+	gets := make(map[string]int)
+	client := countingServer(t, gets, WithCache(time.Minute))
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := client.GetIssue(ctx, "PROJ-1"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.GetTransitions(ctx, "PROJ-1"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.GetFields(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path, n := range gets {
+		if n != 1 {
+			t.Errorf("GET %s: %d requests, want 1", path, n)
+		}
+	}
+
+	// Cached values are not shared between callers
+	a, _ := client.GetIssue(ctx, "PROJ-1")
+	a.Fields.Summary = "changed"
+	if b, _ := client.GetIssue(ctx, "PROJ-1"); b.Fields.Summary != "v1" {
+		t.Errorf("cached summary = %q, want v1", b.Fields.Summary)
+	}
+
+	// Errors are not cached
+	for range 2 {
+		if _, err := client.GetIssue(ctx, "PROJ-2"); !IsNotFound(err) {
+			t.Errorf("GetIssue(PROJ-2) error = %v, want not found", err)
+		}
+	}
+	if gets["/rest/api/3/issue/PROJ-2"] != 2 {
+		t.Errorf("missing issue fetched %d times, want 2", gets["/rest/api/3/issue/PROJ-2"])
+	}
+}
+
+func TestCache_InvalidatesOnWrite(t *testing.T) {
+	gets := make(map[string]int)
+	client := countingServer(t, gets, WithCache(time.Minute))
+	ctx := context.Background()
+
+	if _, err := client.GetIssue(ctx, "PROJ-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UpdateIssue(ctx, "PROJ-1", map[string]any{"summary": "new"}); err != nil {
+		t.Fatal(err)
+	}
+	issue, err := client.GetIssue(ctx, "PROJ-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Fields.Summary != "v2" {
+		t.Errorf("summary after update = %q, want v2 (refetched)", issue.Fields.Summary)
+	}
+
+	if err := client.AssignIssue(ctx, "PROJ-1", "abc"); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = client.GetIssue(ctx, "PROJ-1")
+	client.InvalidateIssue("proj-1")
+	_, _ = client.GetIssue(ctx, "PROJ-1")
+	if gets["/rest/api/3/issue/PROJ-1"] != 4 {
+		t.Errorf("issue fetched %d times, want 4", gets["/rest/api/3/issue/PROJ-1"])
+	}
+}
+
+func TestCache_Expires(t *testing.T) {
+	gets := make(map[string]int)
+	client := countingServer(t, gets, WithCache(time.Minute))
+	now := time.Now()
+	client.cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, _ = client.GetFields(ctx)
+	now = now.Add(59 * time.Second)
+	_, _ = client.GetFields(ctx)
+	now = now.Add(time.Second)
+	_, _ = client.GetFields(ctx)
+	if gets["/rest/api/3/field"] != 2 {
+		t.Errorf("fields fetched %d times, want 2", gets["/rest/api/3/field"])
+	}
+
+	client.ClearCache()
+	_, _ = client.GetFields(ctx)
+	if gets["/rest/api/3/field"] != 3 {
+		t.Errorf("fields fetched %d times after ClearCache, want 3", gets["/rest/api/3/field"])
+	}
+}
+
+func TestCache_Disabled(t *testing.T) {
+	gets := make(map[string]int)
+	client := countingServer(t, gets)
+
+	for range 2 {
+		if _, err := client.GetIssue(context.Background(), "PROJ-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client.InvalidateIssue("PROJ-1")
+	client.ClearCache()
+	if gets["/rest/api/3/issue/PROJ-1"] != 2 {
+		t.Errorf("issue fetched %d times without cache, want 2", gets["/rest/api/3/issue/PROJ-1"])
+	}
+}
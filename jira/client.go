@@ -3,13 +3,10 @@ package jira
 import (
 	"bytes"
 	"context"
-	"crypto/rand"
 	"encoding/base64"
-	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
-	"math"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -18,6 +15,7 @@ import (
 	"time"
 
 	devhttp "github.com/randalmurphal/devflow/http"
+	"golang.org/x/oauth2"
 )
 
 // Client provides access to the Jira REST API.
@@ -32,9 +30,26 @@ type Client struct {
 	remaining int
 	resetTime time.Time
 
+	// Client-side concurrency cap and pacing (nil if neither is set)
+	limiter *requestLimiter
+
 	// Deployment info (cached)
-	deploymentType DeploymentType
-	serverInfo     *ServerInfo
+	deploymentType  DeploymentType
+	serverInfo      *ServerInfo
+	epicLinkFieldID string // Server "Epic Link" field, found on first use
+
+	// Response cache (nil unless WithCache)
+	cache *responseCache
+
+	// OAuth2 tokens (nil for a static access token)
+	tokenSource oauth2.TokenSource
+
+	// Per-host circuit breaker (nil unless WithCircuitBreaker)
+	breaker *devhttp.CircuitBreaker
+
+	// Request logging (nil unless WithRequestLogging)
+	logConfig *devhttp.LogConfig
+	logger    *devhttp.LoggingTransport
 }
 
 // ClientOption configures the client.
@@ -47,6 +62,23 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithCircuitBreaker sends requests through breaker, so an unreachable Jira
+// fails fast with devhttp.ErrCircuitOpen instead of every caller waiting
+// out its retries. Share one breaker across clients to share host state.
+func WithCircuitBreaker(breaker *devhttp.CircuitBreaker) ClientOption {
+	return func(c *Client) {
+		c.breaker = breaker
+	}
+}
+
+// WithRequestLogging logs each request with slog, redacting credentials,
+// for debugging integration failures. Toggle it with SetRequestLogging.
+func WithRequestLogging(cfg devhttp.LogConfig) ClientOption {
+	return func(c *Client) {
+		c.logConfig = &cfg
+	}
+}
+
 // NewClient creates a new Jira client.
 func NewClient(cfg *Config, opts ...ClientOption) (*Client, error) {
 	if validateErr := cfg.Validate(); validateErr != nil {
@@ -58,25 +90,46 @@ func NewClient(cfg *Config, opts ...ClientOption) (*Client, error) {
 		timeout = devhttp.DefaultTimeout
 	}
 
+	transport, transportErr := devhttp.NewTransport(devhttp.TransportConfig{
+		MaxIdleConns:    cfg.HTTP.MaxIdleConns,
+		IdleConnTimeout: cfg.HTTP.IdleConnTimeout,
+		TLS:             cfg.HTTP.TLS,
+		Proxy:           cfg.HTTP.Proxy,
+	})
+	if transportErr != nil {
+		return nil, fmt.Errorf("jira transport: %w", transportErr)
+	}
+
 	c := &Client{
 		cfg:     cfg,
 		baseURL: strings.TrimSuffix(cfg.URL, "/"),
 		httpClient: &http.Client{
-			Timeout: timeout,
-			Transport: &http.Transport{
-				MaxIdleConns:       cfg.HTTP.MaxIdleConns,
-				IdleConnTimeout:    cfg.HTTP.IdleConnTimeout,
-				DisableCompression: false,
-				DisableKeepAlives:  false,
-			},
+			Timeout:   timeout,
+			Transport: transport,
 		},
 		remaining: -1, // Unknown
+		limiter:   newRequestLimiter(cfg.RateLimit),
+	}
+
+	if cfg.Auth.Type == AuthOAuth2 {
+		if cfg.Auth.CloudID != "" {
+			c.baseURL = CloudAPIURL(cfg.Auth.CloudID)
+		}
+		if cfg.Auth.RefreshToken != "" {
+			c.tokenSource = configTokenSource(&cfg.Auth)
+		}
 	}
 
 	// Apply options
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.breaker != nil {
+		c.httpClient = devhttp.WithCircuitBreaker(c.httpClient, c.breaker)
+	}
+	if c.logConfig != nil {
+		c.httpClient, c.logger = devhttp.WithLogging(c.httpClient, *c.logConfig)
+	}
 
 	// Resolve API version
 	c.apiVersion = cfg.GetAPIVersion()
@@ -84,6 +137,14 @@ func NewClient(cfg *Config, opts ...ClientOption) (*Client, error) {
 	return c, nil
 }
 
+// SetRequestLogging turns request logging from WithRequestLogging on or off.
+// It does nothing for a client created without that option.
+func (c *Client) SetRequestLogging(enabled bool) {
+	if c.logger != nil {
+		c.logger.SetEnabled(enabled)
+	}
+}
+
 // DetectDeployment detects the Jira deployment type by calling serverInfo.
 func (c *Client) DetectDeployment(ctx context.Context) (DeploymentType, error) {
 	info, infoErr := c.GetServerInfo(ctx)
@@ -149,36 +210,16 @@ func (c *Client) tryGetServerInfo(ctx context.Context, version string) (*ServerI
 	return &info, nil
 }
 
-// GetIssue retrieves an issue by key.
+// GetIssue retrieves an issue by key. Responses are cached with WithCache.
 func (c *Client) GetIssue(ctx context.Context, key string) (*Issue, error) {
 	if !ValidateIssueKey(key) {
 		return nil, ErrIssueKeyInvalid
 	}
 
-	path := c.apiPath("/issue/" + key)
-	req, reqErr := c.newRequest(ctx, http.MethodGet, path, nil)
-	if reqErr != nil {
-		return nil, reqErr
-	}
-
-	resp, respErr := c.doWithRetry(req)
-	if respErr != nil {
-		return nil, respErr
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, ErrIssueNotFound
-	}
-	if apiErr := c.checkError(resp); apiErr != nil {
-		return nil, apiErr
-	}
-
 	var issue Issue
-	if decodeErr := json.NewDecoder(resp.Body).Decode(&issue); decodeErr != nil {
-		return nil, fmt.Errorf("decode issue: %w", decodeErr)
+	if err := c.cachedGet(ctx, issueCacheKey(key), c.apiPath("/issue/"+key), &issue, ErrIssueNotFound); err != nil {
+		return nil, err
 	}
-
 	return &issue, nil
 }
 
@@ -248,6 +289,9 @@ func (c *Client) CreateIssue(ctx context.Context, createReq *CreateIssueRequest)
 		return nil, respErr
 	}
 	defer func() { _ = resp.Body.Close() }()
+	if parent := createReq.Fields.Parent; parent != nil {
+		c.InvalidateIssue(parent.Key) // Lists its subtasks
+	}
 
 	if apiErr := c.checkError(resp); apiErr != nil {
 		return nil, apiErr
@@ -280,6 +324,7 @@ func (c *Client) UpdateIssue(ctx context.Context, key string, fields map[string]
 		return respErr
 	}
 	defer func() { _ = resp.Body.Close() }()
+	c.InvalidateIssue(key)
 
 	if resp.StatusCode == http.StatusNotFound {
 		return ErrIssueNotFound
@@ -291,36 +336,18 @@ func (c *Client) UpdateIssue(ctx context.Context, key string, fields map[string]
 	return nil
 }
 
-// GetTransitions gets available transitions for an issue.
+// GetTransitions gets available transitions for an issue. Responses are
+// cached with WithCache.
 func (c *Client) GetTransitions(ctx context.Context, key string) ([]Transition, error) {
 	if !ValidateIssueKey(key) {
 		return nil, ErrIssueKeyInvalid
 	}
 
-	path := c.apiPath("/issue/" + key + "/transitions")
-	req, reqErr := c.newRequest(ctx, http.MethodGet, path, nil)
-	if reqErr != nil {
-		return nil, reqErr
-	}
-
-	resp, respErr := c.doWithRetry(req)
-	if respErr != nil {
-		return nil, respErr
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, ErrIssueNotFound
-	}
-	if apiErr := c.checkError(resp); apiErr != nil {
-		return nil, apiErr
-	}
-
 	var result TransitionsResponse
-	if decodeErr := json.NewDecoder(resp.Body).Decode(&result); decodeErr != nil {
-		return nil, fmt.Errorf("decode transitions: %w", decodeErr)
+	path := c.apiPath("/issue/" + key + "/transitions")
+	if err := c.cachedGet(ctx, transitionsCacheKey(key), path, &result, ErrIssueNotFound); err != nil {
+		return nil, err
 	}
-
 	return result.Transitions, nil
 }
 
@@ -348,6 +375,7 @@ func (c *Client) TransitionIssue(ctx context.Context, key, transitionID string)
 		return respErr
 	}
 	defer func() { _ = resp.Body.Close() }()
+	c.InvalidateIssue(key)
 
 	if resp.StatusCode == http.StatusNotFound {
 		return ErrIssueNotFound
@@ -427,6 +455,7 @@ func (c *Client) AddComment(ctx context.Context, key string, body any) (*Comment
 		return nil, respErr
 	}
 	defer func() { _ = resp.Body.Close() }()
+	c.InvalidateIssue(key)
 
 	if resp.StatusCode == http.StatusNotFound {
 		return nil, ErrIssueNotFound
@@ -544,13 +573,15 @@ func (c *Client) newRequest(ctx context.Context, method, path string, body any)
 	req.Header.Set("Accept", "application/json")
 
 	// Set authentication
-	c.setAuth(req)
+	if authErr := c.setAuth(req); authErr != nil {
+		return nil, authErr
+	}
 
 	return req, nil
 }
 
 // setAuth sets the authentication header based on config.
-func (c *Client) setAuth(req *http.Request) {
+func (c *Client) setAuth(req *http.Request) error {
 	switch c.cfg.Auth.Type {
 	case AuthAPIToken:
 		// Cloud: email:api_token base64 encoded
@@ -569,104 +600,101 @@ func (c *Client) setAuth(req *http.Request) {
 		req.Header.Set("Authorization", "Bearer "+c.cfg.Auth.Token)
 
 	case AuthOAuth2:
-		// OAuth2: Bearer access token
-		if c.cfg.Auth.AccessToken != "" {
+		// OAuth2: Bearer access token, refreshed by the token source
+		if c.tokenSource != nil {
+			token, tokenErr := c.tokenSource.Token()
+			if tokenErr != nil {
+				return fmt.Errorf("oauth2 token: %w", tokenErr)
+			}
+			token.SetAuthHeader(req)
+		} else if c.cfg.Auth.AccessToken != "" {
 			req.Header.Set("Authorization", "Bearer "+c.cfg.Auth.AccessToken)
 		} else if c.cfg.Auth.Token != "" {
 			req.Header.Set("Authorization", "Bearer "+c.cfg.Auth.Token)
 		}
 	}
+	return nil
 }
 
-// doRequest executes an HTTP request.
+// doRequest executes an HTTP request once the limiter admits it. The
+// concurrency slot is held until the response body is closed.
 func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
-	return c.httpClient.Do(req)
-}
-
-// doWithRetry executes a request with retry on rate limiting.
-func (c *Client) doWithRetry(req *http.Request) (*http.Response, error) {
-	maxRetries := c.cfg.RateLimit.MaxRetries
-	if maxRetries == 0 {
-		maxRetries = devhttp.DefaultMaxRetries
-	}
-
-	delay := c.cfg.RateLimit.RetryWaitMin
-	if delay == 0 {
-		delay = devhttp.DefaultRetryWait
+	release, err := c.limiter.acquire(req.Context())
+	if err != nil {
+		return nil, err
 	}
 
-	maxDelay := c.cfg.RateLimit.RetryWaitMax
-	if maxDelay == 0 {
-		maxDelay = 30 * time.Second
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		release()
+		return nil, err
 	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
 
-	var lastErr error
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		// Clone request for retry (body needs to be re-readable)
-		clonedReq := req.Clone(req.Context())
-		if req.Body != nil {
-			// Read body and create new readers
-			bodyBytes, readErr := io.ReadAll(req.Body)
-			if readErr != nil {
-				return nil, fmt.Errorf("read request body: %w", readErr)
-			}
-			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-			clonedReq.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-		}
-
-		resp, doErr := c.doRequest(clonedReq)
-		if doErr != nil {
-			lastErr = doErr
-			if devhttp.IsRetryable(doErr) && attempt < maxRetries {
-				c.waitForRetry(req.Context(), delay)
-				delay = min(delay*2, maxDelay)
-				continue
-			}
-			return nil, doErr
-		}
-
-		// Update rate limit state from headers
-		c.updateRateLimitState(resp)
-
-		if resp.StatusCode != http.StatusTooManyRequests {
-			return resp, nil
+// doWithRetry executes a request, retrying rate-limited (429) responses.
+// Each attempt goes through the limiter and updates the rate limit state.
+func (c *Client) doWithRetry(req *http.Request) (*http.Response, error) {
+	return c.retryPolicy().Do(req, func(attemptReq *http.Request) (*http.Response, error) {
+		resp, err := c.doRequest(attemptReq)
+		if err == nil {
+			c.updateRateLimitState(resp)
 		}
-
-		// Rate limited - close body and retry
-		_ = resp.Body.Close()
-		lastErr = devhttp.ErrRateLimited
-
-		// Get retry delay from header if available
-		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
-			if seconds, parseErr := strconv.Atoi(retryAfter); parseErr == nil {
-				delay = time.Duration(seconds) * time.Second
+		return resp, err
+	})
+}
+
+// retryPolicy builds the shared retry policy from the rate limit config
+func (c *Client) retryPolicy() devhttp.RetryPolicy {
+	policy := devhttp.RetryPolicy{
+		MaxRetries: c.cfg.RateLimit.MaxRetries,
+		MinWait:    c.cfg.RateLimit.RetryWaitMin,
+		MaxWait:    c.cfg.RateLimit.RetryWaitMax,
+		ShouldRetry: func(resp *http.Response, err error) bool {
+			if err != nil {
+				return devhttp.IsRetryable(err)
 			}
-		}
+			return resp.StatusCode == http.StatusTooManyRequests
+		},
+	}
+	if policy.MaxRetries == 0 {
+		policy.MaxRetries = devhttp.DefaultMaxRetries
+	}
+	if c.cfg.RateLimit.RetryJitter {
+		policy.Jitter = 0.3
+	}
+	return policy
+}
 
-		// Apply jitter if enabled
-		if c.cfg.RateLimit.RetryJitter {
-			jitter := 0.7 + cryptoRandFloat64()*0.6
-			delay = time.Duration(float64(delay) * jitter)
-		}
+// send executes a request with retries and decodes a successful JSON
+// response into out, if non-nil. A 404 returns notFound when it is set.
+func (c *Client) send(ctx context.Context, method, path string, body, out any, notFound error) error {
+	req, reqErr := c.newRequest(ctx, method, path, body)
+	if reqErr != nil {
+		return reqErr
+	}
 
-		if attempt < maxRetries {
-			c.waitForRetry(req.Context(), delay)
-			delay = min(delay*2, maxDelay)
-		}
+	resp, respErr := c.doWithRetry(req)
+	if respErr != nil {
+		return respErr
 	}
+	defer func() { _ = resp.Body.Close() }()
 
-	if lastErr != nil {
-		return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
+	if resp.StatusCode == http.StatusNotFound && notFound != nil {
+		return notFound
+	}
+	if apiErr := c.checkError(resp); apiErr != nil {
+		return apiErr
 	}
-	return nil, fmt.Errorf("max retries exceeded")
-}
 
-// waitForRetry waits for the specified duration or until context is canceled.
-func (c *Client) waitForRetry(ctx context.Context, delay time.Duration) {
-	select {
-	case <-ctx.Done():
-	case <-time.After(delay):
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
 	}
+	if decodeErr := json.NewDecoder(resp.Body).Decode(out); decodeErr != nil {
+		return fmt.Errorf("decode response: %w", decodeErr)
+	}
+	return nil
 }
 
 // updateRateLimitState updates rate limit tracking from response headers.
@@ -701,6 +729,11 @@ func (c *Client) IsCloud() bool {
 	return c.deploymentType == DeploymentCloud
 }
 
+// BrowseURL returns the web URL of an issue on the configured site.
+func (c *Client) BrowseURL(key string) string {
+	return strings.TrimSuffix(c.cfg.URL, "/") + "/browse/" + key
+}
+
 // APIVersionInUse returns the API version being used.
 func (c *Client) APIVersionInUse() APIVersion {
 	return c.apiVersion
@@ -740,15 +773,3 @@ func ClientFromContext(ctx context.Context) *Client {
 func ContextWithClient(ctx context.Context, c *Client) context.Context {
 	return context.WithValue(ctx, jiraClientKey{}, c)
 }
-
-// cryptoRandFloat64 returns a cryptographically secure random float64 in [0.0, 1.0).
-func cryptoRandFloat64() float64 {
-	var b [8]byte
-	if _, err := rand.Read(b[:]); err != nil {
-		// Fallback to 0.5 if crypto/rand fails (shouldn't happen)
-		return 0.5
-	}
-	// Convert to uint64 and normalize to [0, 1)
-	u := binary.LittleEndian.Uint64(b[:])
-	return float64(u>>11) / (1 << 53) * math.Nextafter(1, 2)
-}
@@ -2,6 +2,8 @@ package jira
 
 import (
 	"time"
+
+	devhttp "github.com/randalmurphal/devflow/http"
 )
 
 // AuthType represents the type of authentication to use.
@@ -58,7 +60,11 @@ type AuthConfig struct {
 	ClientID     string `mapstructure:"client_id"`
 	ClientSecret string `mapstructure:"client_secret"`
 	AccessToken  string `mapstructure:"access_token"`
-	RefreshToken string `mapstructure:"refresh_token"`
+	RefreshToken string `mapstructure:"refresh_token"` // Refreshed automatically when set
+
+	// CloudID routes OAuth2 requests through api.atlassian.com to this
+	// site, as 3LO tokens require (see OAuth2Flow.ResolveCloudID).
+	CloudID string `mapstructure:"cloud_id"`
 }
 
 // HTTPConfig holds HTTP client configuration.
@@ -71,6 +77,12 @@ type HTTPConfig struct {
 
 	// IdleConnTimeout is how long to keep idle connections open.
 	IdleConnTimeout time.Duration `mapstructure:"idle_conn_timeout"`
+
+	// TLS configures client certificates and CAs for Jira behind internal PKI.
+	TLS devhttp.TLSConfig `mapstructure:"tls"`
+
+	// Proxy routes Jira traffic through a proxy other than the environment's.
+	Proxy devhttp.ProxyConfig `mapstructure:"proxy"`
 }
 
 // RateLimitConfig holds rate limiting configuration.
@@ -86,6 +98,13 @@ type RateLimitConfig struct {
 
 	// RetryJitter enables randomized jitter on retry waits.
 	RetryJitter bool `mapstructure:"retry_jitter"`
+
+	// MaxConcurrent caps the requests in flight at once; 0 means no cap.
+	MaxConcurrent int `mapstructure:"max_concurrent"`
+
+	// RequestsPerSecond paces requests to at most this rate; 0 means
+	// requests are sent as soon as a concurrency slot is free.
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
 }
 
 // DefaultConfig returns a Config with sensible defaults.
@@ -98,10 +117,11 @@ func DefaultConfig() *Config {
 			IdleConnTimeout: 90 * time.Second,
 		},
 		RateLimit: RateLimitConfig{
-			MaxRetries:   3,
-			RetryWaitMin: 1 * time.Second,
-			RetryWaitMax: 30 * time.Second,
-			RetryJitter:  true,
+			MaxRetries:    3,
+			RetryWaitMin:  1 * time.Second,
+			RetryWaitMax:  30 * time.Second,
+			RetryJitter:   true,
+			MaxConcurrent: 10,
 		},
 	}
 }
@@ -142,6 +162,10 @@ func (c *Config) Validate() error {
 		return ErrConfigAPIVersionInvalid
 	}
 
+	if c.RateLimit.MaxConcurrent < 0 || c.RateLimit.RequestsPerSecond < 0 {
+		return ErrConfigRateLimitInvalid
+	}
+
 	return nil
 }
 
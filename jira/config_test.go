@@ -23,6 +23,9 @@ func TestDefaultConfig(t *testing.T) {
 	if !cfg.RateLimit.RetryJitter {
 		t.Error("RateLimit.RetryJitter should be true")
 	}
+	if cfg.RateLimit.MaxConcurrent != 10 {
+		t.Errorf("RateLimit.MaxConcurrent = %v, want 10", cfg.RateLimit.MaxConcurrent)
+	}
 }
 
 func TestConfigValidate(t *testing.T) {
@@ -173,6 +176,19 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: ErrConfigAPIVersionInvalid,
 		},
+		{
+			name: "negative max concurrent",
+			config: Config{
+				URL: "https://example.atlassian.net",
+				Auth: AuthConfig{
+					Type:  AuthAPIToken,
+					Email: "user@example.com",
+					Token: "token",
+				},
+				RateLimit: RateLimitConfig{MaxConcurrent: -1},
+			},
+			wantErr: ErrConfigRateLimitInvalid,
+		},
 	}
 
 	for _, tt := range tests {
@@ -10,7 +10,21 @@
 //   - API Token (Cloud): Email + API token
 //   - Personal Access Token (Server/DC): PAT token
 //   - Basic Auth (legacy): Username + password
-//   - OAuth 2.0 (Cloud): Client credentials
+//   - OAuth 2.0 (Cloud): Authorization code flow (3LO) with refresh
+//
+// For OAuth 2.0, OAuth2Flow obtains the user's consent and tokens, and
+// resolves the site's cloud ID, which 3LO requests are routed by:
+//
+//	flow := jira.NewOAuth2Flow(&cfg.Auth, "https://app.example.com/callback")
+//	http.Redirect(w, r, flow.AuthCodeURL(state), http.StatusFound)
+//
+//	// In the callback handler
+//	token, err := flow.Exchange(ctx, r.URL.Query().Get("code"))
+//	cfg.Auth.CloudID, err = flow.ResolveCloudID(ctx, token, cfg.URL)
+//	flow.OnRefresh = saveToken // Refresh tokens rotate
+//	client, err := jira.NewClient(cfg, jira.WithTokenSource(flow.TokenSource(ctx, token)))
+//
+// A configured RefreshToken is also refreshed automatically.
 //
 // # Usage
 //
@@ -30,6 +44,157 @@
 //
 //	issue, err := client.GetIssue(ctx, "PROJ-123")
 //
+// # Issue Fields
+//
+// IssueFields accessors behave the same for v2 and v3 payloads and return
+// empty values for fields that were not fetched:
+//
+//	if issue.Fields.IsDone() { ... }          // Status category, not name
+//	owner := issue.Fields.AssigneeID()        // accountId or username
+//	due, err := issue.Fields.DueDateTime()    // time.Time
+//	md, err := issue.Fields.DescriptionMarkdown()
+//
+// Custom fields are collected into CustomFields by ID. Read them with
+// CustomFieldNumber, CustomFieldString, or CustomField for structured values:
+//
+//	points, ok := issue.Fields.CustomFieldNumber("customfield_10016")
+//
+// # JQL
+//
+// Build queries with the JQL builder rather than string concatenation. It
+// quotes and escapes values and rejects invalid field/operator combinations:
+//
+//	jql, err := jira.NewJQL().
+//		Project("PROJ").
+//		Status("To Do").
+//		Where("assignee", jira.OpIs, jira.JQLEmpty).
+//		OrderBy("created", jira.SortDesc).
+//		Build()
+//	result, err := client.SearchIssues(ctx, jql, nil)
+//
+// SearchIssues returns one page. SearchIssuesIter and SearchAllIssues page
+// through every match, up to a safety cap (DefaultSearchMaxIssues) after
+// which they stop with ErrSearchLimitReached:
+//
+//	for issue, err := range client.SearchIssuesIter(ctx, jql, nil) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(issue.Key)
+//	}
+//	issues, err := client.SearchAllIssues(ctx, jql, &jira.SearchAllOptions{MaxIssues: 200})
+//
+// # Assignment and Watchers
+//
+// Users are given as returned by User.GetID: an account ID on Cloud or a
+// username on Server. The client sends whichever form the instance expects:
+//
+//	err := client.AssignIssue(ctx, "PROJ-123", initiator.GetID())
+//	err = client.AddWatcher(ctx, "PROJ-123", reviewer.GetID())
+//	err = client.AssignIssue(ctx, "PROJ-123", "") // Unassign
+//
+// # Projects, Issue Types, and Users
+//
+// Look up what a create request refers to before sending it, rather than
+// decoding a 400:
+//
+//	project, err := client.GetProject(ctx, "PROJ") // ErrProjectNotFound if unknown
+//	story, err := client.FindIssueType(ctx, "PROJ", "Story")
+//	user, err := client.FindUser(ctx, "jane@example.com")
+//	err = client.AssignIssue(ctx, "PROJ-123", user.GetID())
+//
+// # Subtasks and Epics
+//
+// A spec can be split into child tickets. CreateSubtask fills in the
+// parent's project and subtask type; SetEpic and CreateIssueInEpic use the
+// parent field on Cloud and the Epic Link field on Server:
+//
+//	_, err := client.CreateSubtask(ctx, "PROJ-123", &jira.CreateIssueFields{Summary: "Add tests"})
+//	story, err := client.CreateIssueInEpic(ctx, "PROJ-100", &jira.CreateIssueFields{
+//		Project:   jira.ProjectRef{Key: "PROJ"},
+//		IssueType: jira.IssueTypeRef{Name: "Story"},
+//		Summary:   "Login page",
+//	})
+//	err = client.SetEpic(ctx, "PROJ-124", "PROJ-100")
+//
+// # Issue Links
+//
+//	// PROJ-124 blocks PROJ-123
+//	err := client.CreateIssueLink(ctx, jira.LinkBlocks, "PROJ-124", "PROJ-123")
+//	links, err := client.GetIssueLinks(ctx, "PROJ-123")
+//	err = client.DeleteIssueLink(ctx, links[0].ID)
+//
+// # Caching
+//
+// Batch workflows that reread the same issues can cache GetIssue,
+// GetTransitions, and GetFields responses to stay under the rate limit:
+//
+//	client, err := jira.NewClient(cfg, jira.WithCache(2*time.Minute))
+//
+// Writes made through the client invalidate the issues they change. Use
+// InvalidateIssue or ClearCache after changes made elsewhere.
+//
+// # Rate Limiting
+//
+// Rate-limited responses (429) are retried after their Retry-After delay.
+// To avoid tripping Cloud's burst limits in the first place, the client also
+// caps requests in flight (RateLimit.MaxConcurrent, default 10) and can pace
+// request starts:
+//
+//	cfg.RateLimit.MaxConcurrent = 5
+//	cfg.RateLimit.RequestsPerSecond = 10
+//
+// Parallel callers then wait for a slot instead of failing.
+//
+// WithCircuitBreaker stops sending requests to a Jira that keeps failing, so
+// callers get devhttp.ErrCircuitOpen at once instead of a full retry cycle:
+//
+//	breaker := devhttp.NewCircuitBreaker(devhttp.BreakerConfig{})
+//	client, err := jira.NewClient(cfg, jira.WithCircuitBreaker(breaker))
+//
+// # Proxies and Internal PKI
+//
+// Config.HTTP routes Jira traffic separately from other integrations:
+//
+//	cfg.HTTP.Proxy = devhttp.ProxyConfig{URL: "http://proxy.corp:3128"}
+//	cfg.HTTP.TLS = devhttp.TLSConfig{CAFile: "/etc/pki/corp-ca.pem"}
+//
+// Without a Proxy URL the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY
+// environment variables apply.
+//
+// WithRequestLogging logs each request's URL, status, and duration with
+// slog, redacting Authorization and token parameters. SetRequestLogging
+// turns it on and off while debugging:
+//
+//	client, err := jira.NewClient(cfg, jira.WithRequestLogging(devhttp.LogConfig{MaxBodyBytes: 2048}))
+//	client.SetRequestLogging(false)
+//
+// # Bulk Operations
+//
+// BulkCreateIssues splits large batches under Jira's limit of 50 issues per
+// request and reports items that fail without stopping the rest:
+//
+//	result, err := client.BulkCreateIssues(ctx, subtasks)
+//	for _, f := range result.Failures {
+//		log.Printf("subtask %d: %v", f.Index, f.Err)
+//	}
+//	moved, err := client.BulkTransition(ctx, []string{"PROJ-1", "PROJ-2"}, "In Progress")
+//
+// Both return an error wrapping ErrBulkPartialFailure if any item failed.
+//
+// # Agile Boards and Sprints
+//
+// The Agile API works the same on Cloud and Server. Intake workflows can pull
+// unassigned, automation-ready tickets from the current sprint:
+//
+//	boards, err := client.GetBoards(ctx, &jira.BoardOptions{ProjectKey: "PROJ", Type: "scrum"})
+//	sprint, err := client.GetActiveSprint(ctx, boards.Values[0].ID)
+//	jql, err := jira.NewJQL().Unassigned().Label("automation-ready").Build()
+//	issues, err := client.GetSprintIssues(ctx, sprint.ID, jql, nil)
+//
+//	// Move follow-up tickets into the sprint (batched by 50)
+//	err = client.MoveIssuesToSprint(ctx, sprint.ID, "PROJ-124", "PROJ-125")
+//
 // # Rich Text
 //
 // Jira Cloud uses Atlassian Document Format (ADF) for rich text fields like
@@ -42,6 +207,11 @@
 //	// Convert Wiki Markup to Markdown
 //	md := jira.WikiToMarkdown("*bold* text")
 //
+// The ADF converter round-trips headings, inline formatting, links, code
+// blocks with their language, nested lists, tables, mentions, and panels.
+// Mentions are written as [@Name](mention:<accountId>) and panels as
+// GitHub-style alerts (> [!WARNING]); see ADFConverter.
+//
 // # Error Handling
 //
 // The package uses devflow/http error types for consistent error handling
@@ -53,4 +223,9 @@
 //	if errors.Is(err, http.ErrRateLimited) {
 //		// Rate limited, check Retry-After
 //	}
+//	if errors.Is(err, http.ErrConflict) {
+//		// 409: the issue changed or is locked
+//	}
+//
+// An *APIError keeps the start of the response body in Body.
 package jira
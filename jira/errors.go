@@ -20,14 +20,31 @@ var (
 	ErrConfigPATAuth           = errors.New("pat auth requires token")
 	ErrConfigOAuth2Auth        = errors.New("oauth2 auth requires client_id and client_secret")
 	ErrConfigAPIVersionInvalid = errors.New("api_version must be auto, v2, or v3")
+	ErrConfigRateLimitInvalid  = errors.New("max_concurrent and requests_per_second must not be negative")
+)
+
+// OAuth errors.
+var (
+	ErrCloudIDNotFound = errors.New("jira cloud id not found in accessible resources")
 )
 
 // Issue errors.
 var (
-	ErrIssueNotFound    = errors.New("jira issue not found")
-	ErrProjectNotFound  = errors.New("jira project not found")
-	ErrIssueKeyRequired = errors.New("issue key is required")
-	ErrIssueKeyInvalid  = errors.New("invalid issue key format")
+	ErrIssueNotFound      = errors.New("jira issue not found")
+	ErrProjectNotFound    = errors.New("jira project not found")
+	ErrProjectKeyRequired = errors.New("project key is required")
+	ErrIssueKeyRequired   = errors.New("issue key is required")
+	ErrIssueKeyInvalid    = errors.New("invalid issue key format")
+
+	ErrIssueTypeNotFound     = errors.New("jira issue type not found")
+	ErrSubtaskTypeNotFound   = errors.New("no subtask issue type")
+	ErrEpicLinkFieldNotFound = errors.New("epic link field not found")
+	ErrCustomFieldNotSet     = errors.New("custom field not set on issue")
+)
+
+// Search errors.
+var (
+	ErrSearchLimitReached = errors.New("jira search stopped at max issues")
 )
 
 // Transition errors.
@@ -43,6 +60,31 @@ var (
 	ErrCommentIDRequired = errors.New("comment id is required")
 )
 
+// Agile errors.
+var (
+	ErrBoardNotFound  = errors.New("jira board not found")
+	ErrSprintNotFound = errors.New("jira sprint not found")
+)
+
+// Link errors.
+var (
+	ErrLinkNotFound     = errors.New("jira issue link not found")
+	ErrLinkIDRequired   = errors.New("issue link id is required")
+	ErrLinkTypeRequired = errors.New("issue link type is required")
+)
+
+// User errors.
+var (
+	ErrUserRequired  = errors.New("user is required")
+	ErrUserNotFound  = errors.New("jira user not found")
+	ErrUserAmbiguous = errors.New("jira user query matches several users")
+)
+
+// Bulk operation errors.
+var (
+	ErrBulkPartialFailure = errors.New("jira bulk operation partially failed")
+)
+
 // Webhook errors.
 var (
 	ErrWebhookInvalidSignature = errors.New("invalid webhook signature")
@@ -65,6 +107,7 @@ type APIError struct {
 	Errors        map[string]string `json:"errors,omitempty"`
 	Endpoint      string            `json:"-"`
 	RequestID     string            `json:"-"`
+	Body          []byte            `json:"-"` // Up to devhttp.MaxErrorBodyBytes
 }
 
 // Error implements the error interface.
@@ -85,23 +128,7 @@ func (e *APIError) Error() string {
 
 // Unwrap returns the underlying sentinel error based on status code.
 func (e *APIError) Unwrap() error {
-	switch e.StatusCode {
-	case http.StatusBadRequest:
-		return devhttp.ErrBadRequest
-	case http.StatusUnauthorized:
-		return devhttp.ErrUnauthorized
-	case http.StatusForbidden:
-		return devhttp.ErrForbidden
-	case http.StatusNotFound:
-		return devhttp.ErrNotFound
-	case http.StatusTooManyRequests:
-		return devhttp.ErrRateLimited
-	default:
-		if e.StatusCode >= 500 {
-			return devhttp.ErrServerError
-		}
-		return nil
-	}
+	return devhttp.MapStatus(e.StatusCode)
 }
 
 // IsNotFound returns true if this is a 404 error.
@@ -137,12 +164,13 @@ func NewAPIError(statusCode int, messages []string, fieldErrors map[string]strin
 func parseAPIError(resp *http.Response, endpoint string) error {
 	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, devhttp.MaxErrorBodyBytes))
 
 	apiErr := &APIError{
 		StatusCode: resp.StatusCode,
 		Endpoint:   endpoint,
 		RequestID:  resp.Header.Get("X-Request-Id"),
+		Body:       body,
 	}
 
 	// Try to parse Jira error response format
@@ -157,7 +185,10 @@ func parseAPIError(resp *http.Response, endpoint string) error {
 // IsNotFound reports whether the error indicates a resource was not found.
 func IsNotFound(err error) bool {
 	return errors.Is(err, devhttp.ErrNotFound) || errors.Is(err, ErrIssueNotFound) ||
-		errors.Is(err, ErrProjectNotFound) || errors.Is(err, ErrCommentNotFound)
+		errors.Is(err, ErrProjectNotFound) || errors.Is(err, ErrCommentNotFound) ||
+		errors.Is(err, ErrBoardNotFound) || errors.Is(err, ErrSprintNotFound) ||
+		errors.Is(err, ErrLinkNotFound) || errors.Is(err, ErrUserNotFound) ||
+		errors.Is(err, ErrIssueTypeNotFound)
 }
 
 // IsUnauthorized reports whether the error indicates authentication failed.
@@ -170,6 +201,12 @@ func IsForbidden(err error) bool {
 	return errors.Is(err, devhttp.ErrForbidden)
 }
 
+// IsConflict reports whether the error indicates a conflict, such as an
+// edit racing another change.
+func IsConflict(err error) bool {
+	return errors.Is(err, devhttp.ErrConflict)
+}
+
 // IsRateLimited reports whether the error indicates rate limiting.
 func IsRateLimited(err error) bool {
 	return errors.Is(err, devhttp.ErrRateLimited)
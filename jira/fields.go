@@ -0,0 +1,36 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+)
+
+// Field describes a system or custom issue field.
+type Field struct {
+	ID          string       `json:"id"` // e.g. "summary", "customfield_10016"
+	Key         string       `json:"key,omitempty"`
+	Name        string       `json:"name"` // e.g. "Story Points"
+	Custom      bool         `json:"custom"`
+	Searchable  bool         `json:"searchable,omitempty"`
+	ClauseNames []string     `json:"clauseNames,omitempty"` // JQL names
+	Schema      *FieldSchema `json:"schema,omitempty"`
+}
+
+// FieldSchema describes a field's value type.
+type FieldSchema struct {
+	Type     string `json:"type"`            // e.g. "string", "number", "array", "user"
+	Items    string `json:"items,omitempty"` // Element type of arrays
+	System   string `json:"system,omitempty"`
+	Custom   string `json:"custom,omitempty"` // Custom field type key
+	CustomID int    `json:"customId,omitempty"`
+}
+
+// GetFields lists the instance's system and custom fields. Responses are
+// cached with WithCache.
+func (c *Client) GetFields(ctx context.Context) ([]Field, error) {
+	var fields []Field
+	if err := c.cachedGet(ctx, fieldsCacheKey, c.apiPath("/field"), &fields, nil); err != nil {
+		return nil, fmt.Errorf("get fields: %w", err)
+	}
+	return fields, nil
+}
@@ -0,0 +1,131 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// epicLinkFieldType is the custom field type of Server's "Epic Link" field
+const epicLinkFieldType = "com.pyxis.greenhopper.jira:gh-epic-link"
+
+// CreateSubtask creates a subtask of parentKey. Project defaults to the
+// parent's project and IssueType to the project's subtask type ("Sub-task"
+// or "Subtask", depending on the project).
+func (c *Client) CreateSubtask(ctx context.Context, parentKey string, fields *CreateIssueFields) (*CreateIssueResponse, error) {
+	if !ValidateIssueKey(parentKey) {
+		return nil, ErrIssueKeyInvalid
+	}
+
+	subtask := *fields
+	subtask.Parent = &IssueRef{Key: parentKey}
+	if subtask.Project.Key == "" && subtask.Project.ID == "" {
+		subtask.Project.Key = projectKeyOf(parentKey)
+	}
+	if subtask.IssueType.Name == "" && subtask.IssueType.ID == "" {
+		issueType, err := c.subtaskType(ctx, subtask.Project)
+		if err != nil {
+			return nil, fmt.Errorf("create subtask of %s: %w", parentKey, err)
+		}
+		subtask.IssueType = IssueTypeRef{ID: issueType.ID}
+	}
+
+	resp, err := c.CreateIssue(ctx, &CreateIssueRequest{Fields: subtask})
+	if err != nil {
+		return nil, fmt.Errorf("create subtask of %s: %w", parentKey, err)
+	}
+	return resp, nil
+}
+
+// SetEpic adds an issue to an epic. Cloud sets the issue's parent; Server
+// and Data Center set the "Epic Link" custom field.
+func (c *Client) SetEpic(ctx context.Context, key, epicKey string) error {
+	if !ValidateIssueKey(key) || !ValidateIssueKey(epicKey) {
+		return ErrIssueKeyInvalid
+	}
+
+	var fields map[string]any
+	if c.usesAccountIDs() {
+		fields = map[string]any{"parent": IssueRef{Key: epicKey}}
+	} else {
+		fieldID, err := c.epicLinkField(ctx)
+		if err != nil {
+			return fmt.Errorf("add %s to epic %s: %w", key, epicKey, err)
+		}
+		fields = map[string]any{fieldID: epicKey}
+	}
+
+	if err := c.UpdateIssue(ctx, key, fields); err != nil {
+		return fmt.Errorf("add %s to epic %s: %w", key, epicKey, err)
+	}
+	return nil
+}
+
+// CreateIssueInEpic creates an issue and adds it to an epic. On Cloud this
+// is one request; on Server the issue is created, then linked, and a failed
+// link still returns the created issue with the error.
+func (c *Client) CreateIssueInEpic(ctx context.Context, epicKey string, fields *CreateIssueFields) (*CreateIssueResponse, error) {
+	if !ValidateIssueKey(epicKey) {
+		return nil, ErrIssueKeyInvalid
+	}
+
+	if c.usesAccountIDs() {
+		child := *fields
+		child.Parent = &IssueRef{Key: epicKey}
+		return c.CreateIssue(ctx, &CreateIssueRequest{Fields: child})
+	}
+
+	resp, err := c.CreateIssue(ctx, &CreateIssueRequest{Fields: *fields})
+	if err != nil {
+		return nil, err
+	}
+	return resp, c.SetEpic(ctx, resp.Key, epicKey)
+}
+
+// subtaskType returns the project's subtask issue type
+func (c *Client) subtaskType(ctx context.Context, project ProjectRef) (*IssueType, error) {
+	projectID := project.Key
+	if projectID == "" {
+		projectID = project.ID
+	}
+
+	issueTypes, err := c.ListIssueTypes(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range issueTypes {
+		if issueTypes[i].Subtask {
+			return &issueTypes[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w in project %s", ErrSubtaskTypeNotFound, projectID)
+}
+
+// epicLinkField returns the ID of the "Epic Link" custom field
+func (c *Client) epicLinkField(ctx context.Context) (string, error) {
+	c.mu.RLock()
+	fieldID := c.epicLinkFieldID
+	c.mu.RUnlock()
+	if fieldID != "" {
+		return fieldID, nil
+	}
+
+	fields, err := c.GetFields(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range fields {
+		if f.Schema != nil && f.Schema.Custom == epicLinkFieldType {
+			c.mu.Lock()
+			c.epicLinkFieldID = f.ID
+			c.mu.Unlock()
+			return f.ID, nil
+		}
+	}
+	return "", ErrEpicLinkFieldNotFound
+}
+
+// projectKeyOf returns the project key of an issue key: PROJ for PROJ-123
+func projectKeyOf(key string) string {
+	return key[:strings.LastIndex(key, "-")]
+}
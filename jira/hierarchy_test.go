@@ -0,0 +1,129 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCreateSubtask(t *testing.T) {
+	var created CreateIssueRequest
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/project/PROJ":
+			fmt.Fprint(w, `{"key":"PROJ","issueTypes":[{"id":"1","name":"Story"},{"id":"5","name":"Subtask","subtask":true}]}`)
+		case "/rest/api/3/project/NONE":
+			fmt.Fprint(w, `{"key":"NONE","issueTypes":[{"id":"1","name":"Story"}]}`)
+		case "/rest/api/3/issue":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":"10","key":"PROJ-2"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	resp, err := client.CreateSubtask(ctx, "PROJ-1", &CreateIssueFields{Summary: "Write tests"})
+	if err != nil {
+		t.Fatalf("CreateSubtask() error = %v", err)
+	}
+	f := created.Fields
+	if resp.Key != "PROJ-2" || f.Parent.Key != "PROJ-1" || f.Project.Key != "PROJ" || f.IssueType.ID != "5" || f.Summary != "Write tests" {
+		t.Errorf("created %+v", f)
+	}
+
+	if _, err := client.CreateSubtask(ctx, "NONE-1", &CreateIssueFields{Summary: "x"}); !errors.Is(err, ErrSubtaskTypeNotFound) {
+		t.Errorf("no subtask type: error = %v, want ErrSubtaskTypeNotFound", err)
+	}
+}
+
+func TestSetEpic(t *testing.T) {
+	var updates []map[string]any
+	var fieldRequests int
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/rest/api/3/field":
+			fieldRequests++
+			fmt.Fprint(w, `[{"id":"summary","name":"Summary"},
+				{"id":"customfield_10008","name":"Epic Link","custom":true,"schema":{"type":"any","custom":"com.pyxis.greenhopper.jira:gh-epic-link"}}]`)
+		case r.Method == http.MethodPut:
+			var body UpdateIssueRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			updates = append(updates, body.Fields)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	// Cloud: parent
+	if err := client.SetEpic(ctx, "PROJ-2", "PROJ-1"); err != nil {
+		t.Fatalf("SetEpic() error = %v", err)
+	}
+	if parent, _ := updates[0]["parent"].(map[string]any); parent["key"] != "PROJ-1" {
+		t.Errorf("cloud update = %v", updates[0])
+	}
+
+	// Server: Epic Link field, looked up once
+	client.deploymentType = DeploymentServer
+	for range 2 {
+		if err := client.SetEpic(ctx, "PROJ-3", "PROJ-1"); err != nil {
+			t.Fatalf("SetEpic() error = %v", err)
+		}
+	}
+	if updates[1]["customfield_10008"] != "PROJ-1" || fieldRequests != 1 {
+		t.Errorf("server update = %v after %d field requests", updates[1], fieldRequests)
+	}
+
+	if err := client.SetEpic(ctx, "PROJ-3", ""); !errors.Is(err, ErrIssueKeyInvalid) {
+		t.Errorf("no epic: error = %v, want ErrIssueKeyInvalid", err)
+	}
+}
+
+func TestCreateIssueInEpic(t *testing.T) {
+	var created CreateIssueRequest
+	var updated bool
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/rest/api/3/issue":
+			created = CreateIssueRequest{}
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"key":"PROJ-5"}`)
+		case r.URL.Path == "/rest/api/3/field":
+			fmt.Fprint(w, `[]`)
+		default:
+			updated = true
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	ctx := context.Background()
+	fields := &CreateIssueFields{Project: ProjectRef{Key: "PROJ"}, IssueType: IssueTypeRef{Name: "Story"}, Summary: "Login"}
+
+	if _, err := client.CreateIssueInEpic(ctx, "PROJ-1", fields); err != nil {
+		t.Fatal(err)
+	}
+	if created.Fields.Parent == nil || created.Fields.Parent.Key != "PROJ-1" || updated {
+		t.Errorf("cloud: parent = %+v, updated = %v", created.Fields.Parent, updated)
+	}
+	if fields.Parent != nil {
+		t.Error("CreateIssueInEpic() modified the caller's fields")
+	}
+
+	// Server without an Epic Link field: the issue is still returned
+	client.deploymentType = DeploymentServer
+	resp, err := client.CreateIssueInEpic(ctx, "PROJ-1", fields)
+	if !errors.Is(err, ErrEpicLinkFieldNotFound) || resp == nil || resp.Key != "PROJ-5" {
+		t.Errorf("server: CreateIssueInEpic() = %+v, %v", resp, err)
+	}
+	if created.Fields.Parent != nil {
+		t.Errorf("server: parent = %+v, want none", created.Fields.Parent)
+	}
+}
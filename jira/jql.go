@@ -0,0 +1,279 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrJQLInvalid indicates a JQL builder was given an invalid clause.
+var ErrJQLInvalid = errors.New("invalid jql")
+
+// RawJQL is a JQL value inserted without quoting: a function call such as
+// currentUser() or a keyword such as EMPTY. Strings are always quoted.
+type RawJQL string
+
+// Common unquoted JQL values.
+const (
+	JQLEmpty    RawJQL = "EMPTY"
+	CurrentUser RawJQL = "currentUser()"
+	OpenSprints RawJQL = "openSprints()"
+)
+
+// SortDir is a JQL ORDER BY direction.
+type SortDir string
+
+// Sort directions.
+const (
+	SortAsc  SortDir = "ASC"
+	SortDesc SortDir = "DESC"
+)
+
+// JQL operators accepted by Where.
+const (
+	OpEquals      = "="
+	OpNotEquals   = "!="
+	OpContains    = "~"
+	OpNotContains = "!~"
+	OpGreater     = ">"
+	OpGreaterEq   = ">="
+	OpLess        = "<"
+	OpLessEq      = "<="
+	OpIn          = "in"
+	OpNotIn       = "not in"
+	OpIs          = "is"
+	OpIsNot       = "is not"
+)
+
+// textFields support the ~ and !~ operators
+var textFields = []string{"summary", "description", "environment", "comment", "text"}
+
+var (
+	// plainField is a field name that needs no quoting
+	plainField = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$|^cf\[\d+\]$`)
+
+	// rawValue is a keyword or function call
+	rawValue = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\([^()"]*\))?$`)
+)
+
+// JQL builds a JQL query from clauses joined with AND, quoting and
+// escaping values so callers never concatenate user input into queries:
+//
+//	jql, err := jira.NewJQL().
+//		Project("PROJ").
+//		Status("To Do", "Selected for Development").
+//		Label("automation-ready").
+//		Where("assignee", jira.OpIs, jira.JQLEmpty).
+//		OrderBy("priority", jira.SortDesc).
+//		Build()
+//
+// Invalid clauses are collected and reported by Build.
+type JQL struct {
+	clauses []string
+	order   []string
+	errs    []error
+}
+
+// NewJQL creates an empty JQL builder.
+func NewJQL() *JQL {
+	return &JQL{}
+}
+
+// Project restricts to one or more projects.
+func (q *JQL) Project(keys ...string) *JQL {
+	return q.in("project", keys)
+}
+
+// Status restricts to one or more statuses.
+func (q *JQL) Status(names ...string) *JQL {
+	return q.in("status", names)
+}
+
+// Label requires one of the given labels.
+func (q *JQL) Label(labels ...string) *JQL {
+	return q.in("labels", labels)
+}
+
+// IssueType restricts to one or more issue types.
+func (q *JQL) IssueType(names ...string) *JQL {
+	return q.in("issuetype", names)
+}
+
+// Assignee restricts to a user (account ID or username), or CurrentUser.
+func (q *JQL) Assignee(user any) *JQL {
+	return q.Where("assignee", OpEquals, user)
+}
+
+// Unassigned restricts to issues without an assignee.
+func (q *JQL) Unassigned() *JQL {
+	return q.Where("assignee", OpIs, JQLEmpty)
+}
+
+// Text matches issues whose text fields contain the query.
+func (q *JQL) Text(query string) *JQL {
+	return q.Where("text", OpContains, query)
+}
+
+// in adds field = value for one value and field in (...) for several
+func (q *JQL) in(field string, values []string) *JQL {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	if len(values) == 1 {
+		return q.Where(field, OpEquals, args...)
+	}
+	return q.Where(field, OpIn, args...)
+}
+
+// Where adds a clause. Values may be strings (quoted), integers, times
+// (quoted as "2006-01-02 15:04"), or RawJQL. The operator must suit the
+// field and values: ~ only on text fields, is/is not only with EMPTY or
+// NULL, in/not in with at least one value, and others with exactly one.
+func (q *JQL) Where(field, op string, values ...any) *JQL {
+	clause, err := buildClause(field, strings.ToLower(op), values)
+	if err != nil {
+		q.errs = append(q.errs, err)
+		return q
+	}
+	q.clauses = append(q.clauses, clause)
+	return q
+}
+
+// Or adds the alternatives, each a group of AND-ed clauses, joined with OR.
+func (q *JQL) Or(alternatives ...*JQL) *JQL {
+	var parts []string
+	for _, alt := range alternatives {
+		q.errs = append(q.errs, alt.errs...)
+		if len(alt.clauses) > 0 {
+			parts = append(parts, "("+strings.Join(alt.clauses, " AND ")+")")
+		}
+	}
+	if len(parts) > 0 {
+		q.clauses = append(q.clauses, "("+strings.Join(parts, " OR ")+")")
+	}
+	return q
+}
+
+// OrderBy appends a sort key.
+func (q *JQL) OrderBy(field string, dir SortDir) *JQL {
+	if dir != SortAsc && dir != SortDesc {
+		q.errs = append(q.errs, fmt.Errorf("order by %s: direction %q is not ASC or DESC", field, dir))
+		return q
+	}
+	q.order = append(q.order, quoteField(field)+" "+string(dir))
+	return q
+}
+
+// Build returns the query, or an error wrapping ErrJQLInvalid that lists
+// every invalid clause.
+func (q *JQL) Build() (string, error) {
+	if len(q.errs) > 0 {
+		return "", fmt.Errorf("%w: %w", ErrJQLInvalid, errors.Join(q.errs...))
+	}
+	return q.String(), nil
+}
+
+// String returns the query built from the valid clauses.
+func (q *JQL) String() string {
+	query := strings.Join(q.clauses, " AND ")
+	if len(q.order) > 0 {
+		if query != "" {
+			query += " "
+		}
+		query += "ORDER BY " + strings.Join(q.order, ", ")
+	}
+	return query
+}
+
+// buildClause validates and formats one clause
+func buildClause(field, op string, values []any) (string, error) {
+	if field == "" {
+		return "", errors.New("empty field name")
+	}
+
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		f, err := formatValue(v)
+		if err != nil {
+			return "", fmt.Errorf("%s %s: %w", field, op, err)
+		}
+		formatted[i] = f
+	}
+
+	switch op {
+	case OpIn, OpNotIn:
+		if len(values) == 0 {
+			return "", fmt.Errorf("%s %s: needs at least one value", field, op)
+		}
+		return fmt.Sprintf("%s %s (%s)", quoteField(field), op, strings.Join(formatted, ", ")), nil
+	case OpIs, OpIsNot:
+		if len(values) != 1 || (values[0] != JQLEmpty && values[0] != RawJQL("NULL")) {
+			return "", fmt.Errorf("%s %s: value must be EMPTY or NULL", field, op)
+		}
+	case OpContains, OpNotContains:
+		if !isTextField(field) {
+			return "", fmt.Errorf("%s %s: operator only applies to text fields", field, op)
+		}
+		fallthrough
+	case OpEquals, OpNotEquals, OpGreater, OpGreaterEq, OpLess, OpLessEq:
+		if len(values) != 1 {
+			return "", fmt.Errorf("%s %s: needs exactly one value, got %d", field, op, len(values))
+		}
+		if values[0] == JQLEmpty {
+			return "", fmt.Errorf("%s %s: compare with EMPTY using is or is not", field, op)
+		}
+	default:
+		return "", fmt.Errorf("%s: unknown operator %q", field, op)
+	}
+	return fmt.Sprintf("%s %s %s", quoteField(field), op, formatted[0]), nil
+}
+
+// formatValue quotes or validates a clause value
+func formatValue(v any) (string, error) {
+	switch val := v.(type) {
+	case string:
+		return quoteJQL(val), nil
+	case RawJQL:
+		if !rawValue.MatchString(string(val)) {
+			return "", fmt.Errorf("raw value %q is not a keyword or function call", val)
+		}
+		return string(val), nil
+	case int:
+		return strconv.Itoa(val), nil
+	case int64:
+		return strconv.FormatInt(val, 10), nil
+	case time.Time:
+		return quoteJQL(val.Format("2006-01-02 15:04")), nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+// quoteJQL double-quotes a string, escaping backslashes and quotes
+func quoteJQL(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// quoteField quotes field names that are not plain identifiers, such as
+// custom field names with spaces ("Story Points")
+func quoteField(field string) string {
+	if plainField.MatchString(field) {
+		return field
+	}
+	return quoteJQL(field)
+}
+
+// isTextField reports whether a field supports ~; custom fields may be
+// text fields, so they are allowed
+func isTextField(field string) bool {
+	lower := strings.ToLower(field)
+	return slices.Contains(textFields, lower) ||
+		strings.HasPrefix(lower, "cf[") || strings.HasPrefix(lower, "customfield_") || !plainField.MatchString(field)
+}
@@ -0,0 +1,98 @@
+package jira
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJQLBuild(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *JQL
+		want  string
+	}{
+		{
+			name: "intake query",
+			query: NewJQL().
+				Project("PROJ").
+				Status("To Do", "Selected for Development").
+				Label("automation-ready").
+				Unassigned().
+				OrderBy("priority", SortDesc).
+				OrderBy("created", SortAsc),
+			want: `project = "PROJ" AND status in ("To Do", "Selected for Development") AND labels = "automation-ready" AND assignee is EMPTY ORDER BY priority DESC, created ASC`,
+		},
+		{
+			name:  "escapes quotes and backslashes",
+			query: NewJQL().Text(`say "hi" \ bye`),
+			want:  `text ~ "say \"hi\" \\ bye"`,
+		},
+		{
+			name:  "injection stays inside the string",
+			query: NewJQL().Project(`PROJ" OR project = "SECRET`),
+			want:  `project = "PROJ\" OR project = \"SECRET"`,
+		},
+		{
+			name:  "quotes field names with spaces",
+			query: NewJQL().Where("Story Points", OpGreater, 3),
+			want:  `"Story Points" > 3`,
+		},
+		{
+			name:  "functions and dates",
+			query: NewJQL().Assignee(CurrentUser).Where("sprint", OpIn, OpenSprints).Where("updated", OpGreaterEq, time.Date(2025, 1, 15, 9, 30, 0, 0, time.UTC)),
+			want:  `assignee = currentUser() AND sprint in (openSprints()) AND updated >= "2025-01-15 09:30"`,
+		},
+		{
+			name: "or groups",
+			query: NewJQL().Project("PROJ").Or(
+				NewJQL().Label("bug"),
+				NewJQL().IssueType("Bug").Status("Open"),
+			),
+			want: `project = "PROJ" AND ((labels = "bug") OR (issuetype = "Bug" AND status = "Open"))`,
+		},
+		{
+			name:  "order only",
+			query: NewJQL().OrderBy("created", SortDesc),
+			want:  `ORDER BY created DESC`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Build() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJQLBuildInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *JQL
+	}{
+		{"contains on non-text field", NewJQL().Where("status", OpContains, "Open")},
+		{"is with a value", NewJQL().Where("assignee", OpIs, "bob")},
+		{"equals EMPTY", NewJQL().Where("assignee", OpEquals, JQLEmpty)},
+		{"in without values", NewJQL().Where("status", OpIn)},
+		{"equals with two values", NewJQL().Where("status", OpEquals, "a", "b")},
+		{"unknown operator", NewJQL().Where("status", "like", "a")},
+		{"raw value injection", NewJQL().Where("assignee", OpEquals, RawJQL(`x() OR project = SECRET`))},
+		{"unsupported value", NewJQL().Where("votes", OpGreater, 1.5)},
+		{"bad sort direction", NewJQL().OrderBy("created", "SIDEWAYS")},
+		{"invalid alternative", NewJQL().Or(NewJQL().Where("status", OpContains, "x"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.query.Build(); !errors.Is(err, ErrJQLInvalid) {
+				t.Errorf("Build() error = %v, want ErrJQLInvalid", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package jira
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// requestLimiter caps concurrent requests and paces their start times,
+// so bursts of parallel calls stay under Jira Cloud's burst limits
+// instead of relying on 429 retries alone. A nil limiter admits everything.
+type requestLimiter struct {
+	slots    chan struct{} // nil if concurrency is not capped
+	interval time.Duration // minimum gap between request starts
+
+	mu   sync.Mutex
+	next time.Time // earliest start of the next request
+}
+
+// newRequestLimiter returns a limiter for cfg, or nil if cfg sets no limits
+func newRequestLimiter(cfg RateLimitConfig) *requestLimiter {
+	if cfg.MaxConcurrent <= 0 && cfg.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	l := &requestLimiter{}
+	if cfg.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, cfg.MaxConcurrent)
+	}
+	if cfg.RequestsPerSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / cfg.RequestsPerSecond)
+	}
+	return l
+}
+
+// acquire waits for a concurrency slot and the request's paced start time.
+// The returned release frees the slot and may be called more than once.
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	release := func() {}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+			release = sync.OnceFunc(func() { <-l.slots })
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if l.interval > 0 {
+		l.mu.Lock()
+		now := time.Now()
+		start := now
+		if l.next.After(now) {
+			start = l.next
+		}
+		l.next = start.Add(l.interval)
+		l.mu.Unlock()
+
+		if wait := start.Sub(now); wait > 0 {
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				release()
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	return release, nil
+}
+
+// releasingBody releases a limiter slot when the response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close closes the body and releases the slot.
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,107 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newLimitedClient(t *testing.T, handler http.Handler, limits RateLimitConfig) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	cfg := DefaultConfig()
+	cfg.URL = server.URL
+	cfg.APIVersion = APIVersionV3
+	cfg.Auth = AuthConfig{Type: AuthAPIToken, Email: "bot@example.com", Token: "token"}
+	cfg.RateLimit = limits
+
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func TestLimiter_MaxConcurrent(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	client := newLimitedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"key":"PROJ-1","fields":{}}`))
+	}), RateLimitConfig{MaxConcurrent: 3})
+
+	var wg sync.WaitGroup
+	for range 12 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetIssue(context.Background(), "PROJ-1"); err != nil {
+				t.Errorf("GetIssue() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > 3 {
+		t.Errorf("peak in-flight requests = %d, want <= 3", got)
+	}
+}
+
+func TestLimiter_RequestsPerSecond(t *testing.T) {
+	client := newLimitedClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"key":"PROJ-1","fields":{}}`))
+	}), RateLimitConfig{RequestsPerSecond: 20})
+
+	start := time.Now()
+	for range 4 {
+		if _, err := client.GetIssue(context.Background(), "PROJ-1"); err != nil {
+			t.Fatalf("GetIssue() error = %v", err)
+		}
+	}
+	// The first request starts immediately, the other three 50ms apart
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("4 requests at 20/s took %v, want >= 150ms", elapsed)
+	}
+}
+
+func TestLimiter_ContextCanceledWhileWaiting(t *testing.T) {
+	l := newRequestLimiter(RateLimitConfig{MaxConcurrent: 1})
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("acquire() with full slots error = %v, want deadline exceeded", err)
+	}
+
+	release()
+	release() // Idempotent
+	if release, err = l.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire() after release error = %v", err)
+	}
+	release()
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	if l := newRequestLimiter(RateLimitConfig{}); l != nil {
+		t.Errorf("newRequestLimiter(no limits) = %+v, want nil", l)
+	}
+}
@@ -0,0 +1,103 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Standard issue link type names.
+const (
+	LinkBlocks     = "Blocks"    // outward "blocks", inward "is blocked by"
+	LinkRelates    = "Relates"   // "relates to" both ways
+	LinkDuplicates = "Duplicate" // outward "duplicates", inward "is duplicated by"
+	LinkClones     = "Cloners"   // outward "clones", inward "is cloned by"
+)
+
+// IssueLinkType describes a kind of link between issues.
+type IssueLinkType struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name"`
+	Inward  string `json:"inward,omitempty"`  // e.g. "is blocked by"
+	Outward string `json:"outward,omitempty"` // e.g. "blocks"
+	Self    string `json:"self,omitempty"`
+}
+
+// IssueLink is a link between two issues. As returned on an issue, only
+// the other side is set: OutwardIssue when this issue is the inward side.
+type IssueLink struct {
+	ID           string        `json:"id,omitempty"`
+	Self         string        `json:"self,omitempty"`
+	Type         IssueLinkType `json:"type"`
+	InwardIssue  *Issue        `json:"inwardIssue,omitempty"`
+	OutwardIssue *Issue        `json:"outwardIssue,omitempty"`
+}
+
+// createIssueLinkRequest is the body of a create-link request
+type createIssueLinkRequest struct {
+	Type         IssueLinkType      `json:"type"`
+	InwardIssue  IssueRef           `json:"inwardIssue"`
+	OutwardIssue IssueRef           `json:"outwardIssue"`
+	Comment      *AddCommentRequest `json:"comment,omitempty"`
+}
+
+// CreateIssueLink links two issues so that fromKey relates to toKey by the
+// link type's outward description: CreateIssueLink(ctx, LinkBlocks,
+// "PROJ-1", "PROJ-2") records "PROJ-1 blocks PROJ-2".
+func (c *Client) CreateIssueLink(ctx context.Context, linkType, fromKey, toKey string) error {
+	if !ValidateIssueKey(fromKey) || !ValidateIssueKey(toKey) {
+		return ErrIssueKeyInvalid
+	}
+	if linkType == "" {
+		return ErrLinkTypeRequired
+	}
+
+	// The REST API shows the outward description on the inward issue
+	body := &createIssueLinkRequest{
+		Type:         IssueLinkType{Name: linkType},
+		InwardIssue:  IssueRef{Key: fromKey},
+		OutwardIssue: IssueRef{Key: toKey},
+	}
+	defer c.invalidateIssues(fromKey, toKey)
+	if err := c.send(ctx, http.MethodPost, c.apiPath("/issueLink"), body, nil, ErrIssueNotFound); err != nil {
+		return fmt.Errorf("link %s to %s: %w", fromKey, toKey, err)
+	}
+	return nil
+}
+
+// GetIssueLinks returns an issue's links to other issues.
+func (c *Client) GetIssueLinks(ctx context.Context, key string) ([]IssueLink, error) {
+	if !ValidateIssueKey(key) {
+		return nil, ErrIssueKeyInvalid
+	}
+
+	var issue Issue
+	if err := c.send(ctx, http.MethodGet, c.apiPath("/issue/"+key+"?fields=issuelinks"), nil, &issue, ErrIssueNotFound); err != nil {
+		return nil, fmt.Errorf("get links of %s: %w", key, err)
+	}
+	return issue.Fields.IssueLinks, nil
+}
+
+// DeleteIssueLink removes a link by ID (IssueLink.ID).
+func (c *Client) DeleteIssueLink(ctx context.Context, linkID string) error {
+	if linkID == "" {
+		return ErrLinkIDRequired
+	}
+	// The linked issues are unknown here, so drop every cached response
+	defer c.ClearCache()
+	if err := c.send(ctx, http.MethodDelete, c.apiPath("/issueLink/"+linkID), nil, nil, ErrLinkNotFound); err != nil {
+		return fmt.Errorf("delete link %s: %w", linkID, err)
+	}
+	return nil
+}
+
+// GetIssueLinkTypes lists the link types configured on the instance.
+func (c *Client) GetIssueLinkTypes(ctx context.Context) ([]IssueLinkType, error) {
+	var result struct {
+		IssueLinkTypes []IssueLinkType `json:"issueLinkTypes"`
+	}
+	if err := c.send(ctx, http.MethodGet, c.apiPath("/issueLinkType"), nil, &result, nil); err != nil {
+		return nil, fmt.Errorf("get link types: %w", err)
+	}
+	return result.IssueLinkTypes, nil
+}
@@ -0,0 +1,80 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCreateIssueLink(t *testing.T) {
+	var got createIssueLinkRequest
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/api/3/issueLink" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	if err := client.CreateIssueLink(context.Background(), LinkBlocks, "PROJ-1", "PROJ-2"); err != nil {
+		t.Fatalf("CreateIssueLink() error = %v", err)
+	}
+	if got.Type.Name != LinkBlocks || got.InwardIssue.Key != "PROJ-1" || got.OutwardIssue.Key != "PROJ-2" {
+		t.Errorf("request body = %+v", got)
+	}
+
+	if err := client.CreateIssueLink(context.Background(), LinkBlocks, "PROJ-1", "bad"); !errors.Is(err, ErrIssueKeyInvalid) {
+		t.Errorf("invalid key: error = %v, want ErrIssueKeyInvalid", err)
+	}
+	if err := client.CreateIssueLink(context.Background(), "", "PROJ-1", "PROJ-2"); !errors.Is(err, ErrLinkTypeRequired) {
+		t.Errorf("no type: error = %v, want ErrLinkTypeRequired", err)
+	}
+}
+
+func TestGetIssueLinks(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/PROJ-1" || r.URL.Query().Get("fields") != "issuelinks" {
+			t.Errorf("request = %s", r.URL)
+		}
+		fmt.Fprint(w, `{"key":"PROJ-1","fields":{"issuelinks":[
+			{"id":"10","type":{"name":"Blocks","inward":"is blocked by","outward":"blocks"},"outwardIssue":{"key":"PROJ-2"}},
+			{"id":"11","type":{"name":"Relates"},"inwardIssue":{"key":"PROJ-3"}}
+		]}}`)
+	}))
+
+	links, err := client.GetIssueLinks(context.Background(), "PROJ-1")
+	if err != nil {
+		t.Fatalf("GetIssueLinks() error = %v", err)
+	}
+	if len(links) != 2 || links[0].OutwardIssue.Key != "PROJ-2" || links[1].InwardIssue.Key != "PROJ-3" {
+		t.Errorf("GetIssueLinks() = %+v", links)
+	}
+}
+
+func TestDeleteIssueLink(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("method = %s", r.Method)
+		}
+		if r.URL.Path == "/rest/api/3/issueLink/10" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+
+	if err := client.DeleteIssueLink(context.Background(), "10"); err != nil {
+		t.Errorf("DeleteIssueLink() error = %v", err)
+	}
+	if err := client.DeleteIssueLink(context.Background(), "99"); !errors.Is(err, ErrLinkNotFound) || !IsNotFound(err) {
+		t.Errorf("missing link: error = %v, want ErrLinkNotFound", err)
+	}
+	if err := client.DeleteIssueLink(context.Background(), ""); !errors.Is(err, ErrLinkIDRequired) {
+		t.Errorf("no id: error = %v, want ErrLinkIDRequired", err)
+	}
+}
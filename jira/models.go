@@ -18,6 +18,9 @@ const (
 // TimeFormat is the standard Jira timestamp format.
 const TimeFormat = "2006-01-02T15:04:05.000-0700"
 
+// DateFormat is the Jira format of date-only fields such as duedate.
+const DateFormat = "2006-01-02"
+
 // APIVersion represents the Jira REST API version.
 type APIVersion string
 
@@ -70,6 +73,7 @@ type Project struct {
 	Description string            `json:"description,omitempty"`
 	Self        string            `json:"self"`
 	AvatarURLs  map[string]string `json:"avatarUrls,omitempty"`
+	IssueTypes  []IssueType       `json:"issueTypes,omitempty"` // Set by GetProject
 }
 
 // IssueType represents an issue type in Jira.
@@ -163,6 +167,7 @@ type IssueFields struct {
 	Created     string      `json:"created,omitempty"`
 	Updated     string      `json:"updated,omitempty"`
 	DueDate     string      `json:"duedate,omitempty"`
+	Resolved    string      `json:"resolutiondate,omitempty"`
 
 	// Custom fields are stored here with their field IDs as keys
 	// e.g., "customfield_10001": 5.0 (story points)
@@ -171,6 +176,9 @@ type IssueFields struct {
 	// Parent for subtasks
 	Parent *Issue `json:"parent,omitempty"`
 
+	// Links to other issues
+	IssueLinks []IssueLink `json:"issuelinks,omitempty"`
+
 	// Timetracking
 	TimeTracking *TimeTracking `json:"timetracking,omitempty"`
 }
@@ -418,6 +426,14 @@ func ParseTime(s string) (time.Time, error) {
 	return time.Time{}, &time.ParseError{Value: s}
 }
 
+// ParseDate parses a Jira date such as a due date or release date.
+func ParseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(DateFormat, s)
+}
+
 // FormatTime formats a time.Time as a Jira timestamp string.
 func FormatTime(t time.Time) string {
 	return t.Format(TimeFormat)
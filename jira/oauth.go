@@ -0,0 +1,197 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// Atlassian OAuth 2.0 (3LO) endpoints.
+const (
+	AtlassianAuthURL       = "https://auth.atlassian.com/authorize"
+	AtlassianTokenURL      = "https://auth.atlassian.com/oauth/token"
+	AccessibleResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+
+	// atlassianAPIURL is the base URL of OAuth 2.0 requests to a Cloud site
+	atlassianAPIURL = "https://api.atlassian.com/ex/jira/"
+)
+
+// DefaultOAuth2Scopes read and write issues and keep access with a refresh
+// token (offline_access).
+var DefaultOAuth2Scopes = []string{"read:jira-work", "write:jira-work", "read:jira-user", "offline_access"}
+
+// AccessibleResource is a Cloud site the user granted the app access to.
+type AccessibleResource struct {
+	ID        string   `json:"id"`  // Cloud ID
+	URL       string   `json:"url"` // e.g. https://your-domain.atlassian.net
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	AvatarURL string   `json:"avatarUrl,omitempty"`
+}
+
+// CloudAPIURL returns the base URL for OAuth 2.0 requests to the Cloud site
+// with the given ID; use it as Config.URL or set AuthConfig.CloudID.
+func CloudAPIURL(cloudID string) string {
+	return atlassianAPIURL + cloudID
+}
+
+// OAuth2Flow runs the OAuth 2.0 authorization code flow (3LO) for Jira
+// Cloud: send the user to AuthCodeURL, Exchange the returned code for a
+// token, then build a client with WithTokenSource, which refreshes the
+// token as it expires.
+type OAuth2Flow struct {
+	config       *oauth2.Config
+	resourcesURL string
+
+	// OnRefresh, if set, is called with each refreshed token. Atlassian
+	// rotates refresh tokens, so store the new one to use after a restart.
+	OnRefresh func(*oauth2.Token)
+}
+
+// NewOAuth2Flow creates a flow for the app identified by auth's ClientID
+// and ClientSecret. redirectURL must match the app's callback URL; scopes
+// default to DefaultOAuth2Scopes.
+func NewOAuth2Flow(auth *AuthConfig, redirectURL string, scopes ...string) *OAuth2Flow {
+	if len(scopes) == 0 {
+		scopes = DefaultOAuth2Scopes
+	}
+	return &OAuth2Flow{
+		config: &oauth2.Config{
+			ClientID:     auth.ClientID,
+			ClientSecret: auth.ClientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       scopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:   AtlassianAuthURL,
+				TokenURL:  AtlassianTokenURL,
+				AuthStyle: oauth2.AuthStyleInParams,
+			},
+		},
+		resourcesURL: AccessibleResourcesURL,
+	}
+}
+
+// AuthCodeURL returns the URL to send the user to for consent. state is
+// returned to the callback and must be checked there.
+func (f *OAuth2Flow) AuthCodeURL(state string) string {
+	return f.config.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("audience", "api.atlassian.com"),
+		oauth2.SetAuthURLParam("prompt", "consent"),
+	)
+}
+
+// Exchange trades the authorization code from the callback for a token.
+func (f *OAuth2Flow) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	token, err := f.config.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("exchange authorization code: %w", err)
+	}
+	return token, nil
+}
+
+// TokenSource returns a source that yields token until it expires, then
+// refreshes it with its refresh token, calling OnRefresh with each new
+// token. ctx is used for refresh requests.
+func (f *OAuth2Flow) TokenSource(ctx context.Context, token *oauth2.Token) oauth2.TokenSource {
+	return &refreshNotifier{
+		source:    f.config.TokenSource(ctx, token),
+		last:      token.AccessToken,
+		onRefresh: f.OnRefresh,
+	}
+}
+
+// AccessibleResources lists the Cloud sites the token grants access to.
+func (f *OAuth2Flow) AccessibleResources(ctx context.Context, token *oauth2.Token) ([]AccessibleResource, error) {
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, f.resourcesURL, nil)
+	if reqErr != nil {
+		return nil, fmt.Errorf("create request: %w", reqErr)
+	}
+	req.Header.Set("Accept", "application/json")
+	token.SetAuthHeader(req)
+
+	resp, respErr := http.DefaultClient.Do(req)
+	if respErr != nil {
+		return nil, fmt.Errorf("get accessible resources: %w", respErr)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseAPIError(resp, req.URL.Path)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var resources []AccessibleResource
+	if decodeErr := json.NewDecoder(resp.Body).Decode(&resources); decodeErr != nil {
+		return nil, fmt.Errorf("decode accessible resources: %w", decodeErr)
+	}
+	return resources, nil
+}
+
+// ResolveCloudID returns the Cloud ID of the site at siteURL (e.g.
+// https://your-domain.atlassian.net). With an empty siteURL, the token must
+// grant access to exactly one site.
+func (f *OAuth2Flow) ResolveCloudID(ctx context.Context, token *oauth2.Token, siteURL string) (string, error) {
+	resources, err := f.AccessibleResources(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	if siteURL == "" {
+		if len(resources) == 1 {
+			return resources[0].ID, nil
+		}
+		return "", fmt.Errorf("%w: token grants %d sites, specify one", ErrCloudIDNotFound, len(resources))
+	}
+	site := strings.TrimSuffix(siteURL, "/")
+	for _, r := range resources {
+		if strings.EqualFold(strings.TrimSuffix(r.URL, "/"), site) {
+			return r.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrCloudIDNotFound, siteURL)
+}
+
+// refreshNotifier reports tokens that differ from the last one seen
+type refreshNotifier struct {
+	source    oauth2.TokenSource
+	mu        sync.Mutex
+	last      string
+	onRefresh func(*oauth2.Token)
+}
+
+// Token implements oauth2.TokenSource.
+func (n *refreshNotifier) Token() (*oauth2.Token, error) {
+	token, err := n.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if token.AccessToken != n.last {
+		n.last = token.AccessToken
+		if n.onRefresh != nil {
+			n.onRefresh(token)
+		}
+	}
+	return token, nil
+}
+
+// WithTokenSource authenticates oauth2 requests with tokens from ts, such
+// as OAuth2Flow.TokenSource, instead of the configured access token.
+func WithTokenSource(ts oauth2.TokenSource) ClientOption {
+	return func(c *Client) {
+		c.tokenSource = ts
+	}
+}
+
+// configTokenSource builds a refreshing token source from the configured
+// refresh token. The configured access token's expiry is unknown, so the
+// first request refreshes it.
+func configTokenSource(auth *AuthConfig) oauth2.TokenSource {
+	flow := NewOAuth2Flow(auth, "")
+	return flow.TokenSource(context.Background(), &oauth2.Token{RefreshToken: auth.RefreshToken})
+}
@@ -0,0 +1,146 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// newTestFlow returns a flow whose token and resource endpoints are served
+// by handler
+func newTestFlow(t *testing.T, handler http.Handler) *OAuth2Flow {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	flow := NewOAuth2Flow(&AuthConfig{ClientID: "id", ClientSecret: "secret"}, "https://app.example.com/callback")
+	flow.config.Endpoint.TokenURL = server.URL + "/oauth/token"
+	flow.resourcesURL = server.URL + "/oauth/token/accessible-resources"
+	return flow
+}
+
+func TestOAuth2Flow_AuthCodeURL(t *testing.T) {
+	flow := NewOAuth2Flow(&AuthConfig{ClientID: "id", ClientSecret: "secret"}, "https://app.example.com/callback")
+	u, err := url.Parse(flow.AuthCodeURL("xyz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if u.Host != "auth.atlassian.com" || q.Get("audience") != "api.atlassian.com" || q.Get("prompt") != "consent" ||
+		q.Get("client_id") != "id" || q.Get("state") != "xyz" || !strings.Contains(q.Get("scope"), "offline_access") {
+		t.Errorf("AuthCodeURL() = %s", u)
+	}
+}
+
+func TestOAuth2Flow_ExchangeAndRefresh(t *testing.T) {
+	var grants []string
+	flow := newTestFlow(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		grants = append(grants, r.Form.Get("grant_type"))
+		if r.Form.Get("client_secret") != "secret" {
+			t.Errorf("client_secret = %q", r.Form.Get("client_secret"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"access-%d","refresh_token":"refresh-%d","token_type":"Bearer","expires_in":3600}`, len(grants), len(grants))
+	}))
+
+	token, err := flow.Exchange(context.Background(), "code")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" {
+		t.Errorf("Exchange() = %+v", token)
+	}
+
+	var refreshed []*oauth2.Token
+	flow.OnRefresh = func(tok *oauth2.Token) { refreshed = append(refreshed, tok) }
+	token.Expiry = time.Now().Add(-time.Minute)
+	source := flow.TokenSource(context.Background(), token)
+	for range 2 {
+		tok, err := source.Token()
+		if err != nil {
+			t.Fatalf("Token() error = %v", err)
+		}
+		if tok.AccessToken != "access-2" {
+			t.Errorf("Token() = %q, want access-2", tok.AccessToken)
+		}
+	}
+	if len(refreshed) != 1 || refreshed[0].RefreshToken != "refresh-2" {
+		t.Errorf("OnRefresh calls = %v", refreshed)
+	}
+	if len(grants) != 2 || grants[0] != "authorization_code" || grants[1] != "refresh_token" {
+		t.Errorf("grants = %v", grants)
+	}
+}
+
+func TestOAuth2Flow_ResolveCloudID(t *testing.T) {
+	flow := newTestFlow(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `[
+			{"id":"cloud-a","url":"https://a.atlassian.net","name":"a","scopes":["read:jira-work"]},
+			{"id":"cloud-b","url":"https://b.atlassian.net","name":"b"}
+		]`)
+	}))
+	ctx := context.Background()
+	token := &oauth2.Token{AccessToken: "tok"}
+
+	id, err := flow.ResolveCloudID(ctx, token, "https://B.atlassian.net/")
+	if err != nil || id != "cloud-b" {
+		t.Errorf("ResolveCloudID() = %q, %v", id, err)
+	}
+	if _, err := flow.ResolveCloudID(ctx, token, "https://c.atlassian.net"); !errors.Is(err, ErrCloudIDNotFound) {
+		t.Errorf("unknown site: error = %v, want ErrCloudIDNotFound", err)
+	}
+	if _, err := flow.ResolveCloudID(ctx, token, ""); !errors.Is(err, ErrCloudIDNotFound) {
+		t.Errorf("ambiguous site: error = %v, want ErrCloudIDNotFound", err)
+	}
+	var apiErr *APIError
+	if _, err := flow.AccessibleResources(ctx, &oauth2.Token{AccessToken: "bad"}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("bad token: error = %v, want 401 APIError", err)
+	}
+}
+
+func TestClient_OAuth2TokenSource(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"key":"PROJ-1"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := DefaultConfig()
+	cfg.URL = server.URL
+	cfg.APIVersion = APIVersionV3
+	cfg.Auth = AuthConfig{Type: AuthOAuth2, ClientID: "id", ClientSecret: "secret", AccessToken: "static"}
+	client, err := NewClient(cfg, WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "fresh"})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetIssue(context.Background(), "PROJ-1"); err != nil {
+		t.Fatal(err)
+	}
+	if auth != "Bearer fresh" {
+		t.Errorf("Authorization = %q, want token from source", auth)
+	}
+
+	cfg.Auth.CloudID = "cloud-a"
+	cfg.Auth.RefreshToken = "refresh"
+	client, err = NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.baseURL != "https://api.atlassian.com/ex/jira/cloud-a" || client.tokenSource == nil {
+		t.Errorf("baseURL = %q, token source set = %v", client.baseURL, client.tokenSource != nil)
+	}
+}
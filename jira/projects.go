@@ -0,0 +1,50 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GetProject returns a project by key or ID, including its issue types.
+// It fails with ErrProjectNotFound for unknown keys, so callers can check a
+// key before creating issues in it.
+func (c *Client) GetProject(ctx context.Context, keyOrID string) (*Project, error) {
+	if keyOrID == "" {
+		return nil, ErrProjectKeyRequired
+	}
+
+	var project Project
+	path := c.apiPath("/project/" + url.PathEscape(keyOrID))
+	if err := c.send(ctx, http.MethodGet, path, nil, &project, ErrProjectNotFound); err != nil {
+		return nil, fmt.Errorf("get project %s: %w", keyOrID, err)
+	}
+	return &project, nil
+}
+
+// ListIssueTypes returns the issue types available in a project, such as
+// Story, Bug, and its subtask type.
+func (c *Client) ListIssueTypes(ctx context.Context, projectKeyOrID string) ([]IssueType, error) {
+	project, err := c.GetProject(ctx, projectKeyOrID)
+	if err != nil {
+		return nil, err
+	}
+	return project.IssueTypes, nil
+}
+
+// FindIssueType returns the project's issue type with the given name,
+// ignoring case.
+func (c *Client) FindIssueType(ctx context.Context, projectKeyOrID, name string) (*IssueType, error) {
+	issueTypes, err := c.ListIssueTypes(ctx, projectKeyOrID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range issueTypes {
+		if strings.EqualFold(issueTypes[i].Name, name) {
+			return &issueTypes[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q in project %s", ErrIssueTypeNotFound, name, projectKeyOrID)
+}
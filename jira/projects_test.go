@@ -0,0 +1,45 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetProject(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/project/PROJ" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errorMessages":["No project could be found with key 'NOPE'."]}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"10000","key":"PROJ","name":"Project",
+			"issueTypes":[{"id":"1","name":"Story"},{"id":"2","name":"Bug"},{"id":"5","name":"Sub-task","subtask":true}]}`)
+	}))
+	ctx := context.Background()
+
+	project, err := client.GetProject(ctx, "PROJ")
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if project.Key != "PROJ" || len(project.IssueTypes) != 3 {
+		t.Errorf("GetProject() = %+v", project)
+	}
+
+	if _, err := client.GetProject(ctx, "NOPE"); !errors.Is(err, ErrProjectNotFound) || !IsNotFound(err) {
+		t.Errorf("unknown project: error = %v, want ErrProjectNotFound", err)
+	}
+	if _, err := client.GetProject(ctx, ""); !errors.Is(err, ErrProjectKeyRequired) {
+		t.Errorf("empty key: error = %v, want ErrProjectKeyRequired", err)
+	}
+
+	bug, err := client.FindIssueType(ctx, "PROJ", "bug")
+	if err != nil || bug.ID != "2" {
+		t.Errorf("FindIssueType(bug) = %+v, %v", bug, err)
+	}
+	if _, err := client.FindIssueType(ctx, "PROJ", "Epic"); !errors.Is(err, ErrIssueTypeNotFound) {
+		t.Errorf("FindIssueType(Epic) error = %v, want ErrIssueTypeNotFound", err)
+	}
+}
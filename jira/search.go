@@ -0,0 +1,112 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// DefaultSearchMaxIssues caps how many issues SearchIssuesIter and
+// SearchAllIssues fetch when SearchAllOptions.MaxIssues is zero, so a broad
+// JQL query cannot page through an entire instance.
+const DefaultSearchMaxIssues = 1000
+
+// SearchAllOptions configures SearchIssuesIter and SearchAllIssues.
+type SearchAllOptions struct {
+	// PageSize is the number of issues per request (default 50).
+	PageSize int
+	Fields   []string
+	Expand   []string
+
+	// MaxIssues stops paging after this many issues (default
+	// DefaultSearchMaxIssues; negative means no limit). If more matched,
+	// the search ends with ErrSearchLimitReached.
+	MaxIssues int
+
+	// OnPage, if set, is called with each page as it is fetched, e.g. to
+	// report progress. Returning an error stops the search with that error.
+	OnPage func(page *SearchResponse) error
+}
+
+// SearchIssuesIter returns an iterator over every issue matching jql,
+// fetching pages as the loop advances. Iteration stops at the first error,
+// which is yielded with a nil issue; the context is checked before each
+// page.
+//
+//	for issue, err := range client.SearchIssuesIter(ctx, jql, nil) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) SearchIssuesIter(ctx context.Context, jql string, opts *SearchAllOptions) iter.Seq2[*Issue, error] {
+	if opts == nil {
+		opts = &SearchAllOptions{}
+	}
+	limit := opts.MaxIssues
+	if limit == 0 {
+		limit = DefaultSearchMaxIssues
+	}
+
+	return func(yield func(*Issue, error) bool) {
+		seen := 0
+		for startAt := 0; ; {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				yield(nil, ctxErr)
+				return
+			}
+			if limit > 0 && seen == limit {
+				// More issues matched (the previous page was not the last)
+				yield(nil, fmt.Errorf("%w: %d issues", ErrSearchLimitReached, limit))
+				return
+			}
+
+			page, err := c.SearchIssues(ctx, jql, &SearchOptions{
+				StartAt:    startAt,
+				MaxResults: opts.PageSize,
+				Fields:     opts.Fields,
+				Expand:     opts.Expand,
+			})
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if opts.OnPage != nil {
+				if err := opts.OnPage(page); err != nil {
+					yield(nil, err)
+					return
+				}
+			}
+
+			for i := range page.Issues {
+				if limit > 0 && seen == limit {
+					yield(nil, fmt.Errorf("%w: %d issues", ErrSearchLimitReached, limit))
+					return
+				}
+				if !yield(&page.Issues[i], nil) {
+					return
+				}
+				seen++
+			}
+
+			startAt += len(page.Issues)
+			if len(page.Issues) == 0 || startAt >= page.Total {
+				return
+			}
+		}
+	}
+}
+
+// SearchAllIssues returns every issue matching jql, paging as needed (see
+// SearchIssuesIter). On error it returns the issues fetched so far, so a
+// search stopped by ErrSearchLimitReached still yields the first MaxIssues.
+func (c *Client) SearchAllIssues(ctx context.Context, jql string, opts *SearchAllOptions) ([]Issue, error) {
+	var issues []Issue
+	for issue, err := range c.SearchIssuesIter(ctx, jql, opts) {
+		if err != nil {
+			return issues, err
+		}
+		issues = append(issues, *issue)
+	}
+	return issues, nil
+}
@@ -0,0 +1,113 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// pagedSearchServer serves total issues PROJ-1..PROJ-total from /search
+func pagedSearchServer(t *testing.T, total int, requests *int) *Client {
+	t.Helper()
+	return newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		var body struct {
+			StartAt    int `json:"startAt"`
+			MaxResults int `json:"maxResults"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		resp := SearchResponse{StartAt: body.StartAt, MaxResults: body.MaxResults, Total: total}
+		for i := body.StartAt; i < min(body.StartAt+body.MaxResults, total); i++ {
+			resp.Issues = append(resp.Issues, Issue{Key: fmt.Sprintf("PROJ-%d", i+1)})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestSearchAllIssues(t *testing.T) {
+	var requests, pages int
+	client := pagedSearchServer(t, 25, &requests)
+
+	issues, err := client.SearchAllIssues(context.Background(), "project = PROJ", &SearchAllOptions{
+		PageSize: 10,
+		OnPage: func(page *SearchResponse) error {
+			pages++
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("SearchAllIssues() error = %v", err)
+	}
+	if len(issues) != 25 || issues[0].Key != "PROJ-1" || issues[24].Key != "PROJ-25" {
+		t.Errorf("SearchAllIssues() returned %d issues", len(issues))
+	}
+	if requests != 3 || pages != 3 {
+		t.Errorf("requests = %d, pages = %d, want 3", requests, pages)
+	}
+}
+
+func TestSearchAllIssues_MaxIssues(t *testing.T) {
+	var requests int
+	client := pagedSearchServer(t, 25, &requests)
+
+	issues, err := client.SearchAllIssues(context.Background(), "project = PROJ", &SearchAllOptions{PageSize: 10, MaxIssues: 20})
+	if !errors.Is(err, ErrSearchLimitReached) {
+		t.Fatalf("error = %v, want ErrSearchLimitReached", err)
+	}
+	if len(issues) != 20 || requests != 2 {
+		t.Errorf("got %d issues in %d requests, want 20 in 2", len(issues), requests)
+	}
+
+	// Exactly the cap is not an error
+	issues, err = client.SearchAllIssues(context.Background(), "project = PROJ", &SearchAllOptions{PageSize: 10, MaxIssues: 25})
+	if err != nil || len(issues) != 25 {
+		t.Errorf("SearchAllIssues() = %d issues, %v", len(issues), err)
+	}
+}
+
+func TestSearchIssuesIter_Stops(t *testing.T) {
+	var requests int
+	client := pagedSearchServer(t, 25, &requests)
+
+	// Breaking out of the loop fetches no further pages
+	n := 0
+	for _, err := range client.SearchIssuesIter(context.Background(), "project = PROJ", &SearchAllOptions{PageSize: 10}) {
+		if err != nil {
+			t.Fatalf("error = %v", err)
+		}
+		if n++; n == 5 {
+			break
+		}
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+
+	// A callback error ends the search
+	stop := errors.New("stop")
+	_, err := client.SearchAllIssues(context.Background(), "project = PROJ", &SearchAllOptions{
+		OnPage: func(*SearchResponse) error { return stop },
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("OnPage error = %v, want %v", err, stop)
+	}
+
+	// A canceled context ends the search before the next page
+	ctx, cancel := context.WithCancel(context.Background())
+	requests = 0
+	_, err = client.SearchAllIssues(ctx, "project = PROJ", &SearchAllOptions{
+		PageSize: 10,
+		OnPage: func(*SearchResponse) error {
+			cancel()
+			return nil
+		},
+	})
+	if !errors.Is(err, context.Canceled) || requests != 1 {
+		t.Errorf("canceled: error = %v after %d requests", err, requests)
+	}
+}
@@ -0,0 +1,148 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultUserSearchResults is how many users SearchUsers returns when
+// maxResults is zero.
+const DefaultUserSearchResults = 50
+
+// usesAccountIDs reports whether the instance identifies users by accountId
+// (Cloud) rather than username (Server/Data Center). Before DetectDeployment
+// runs, API v3, which only Cloud serves, implies Cloud.
+func (c *Client) usesAccountIDs() bool {
+	if c.deploymentType != "" {
+		return c.deploymentType == DeploymentCloud
+	}
+	return c.apiVersion == APIVersionV3
+}
+
+// userRef references user the way the instance expects
+func (c *Client) userRef(user string) UserRef {
+	if c.usesAccountIDs() {
+		return UserRef{AccountID: user}
+	}
+	return UserRef{Name: user}
+}
+
+// SearchUsers finds active and inactive users whose name or email matches
+// query. Cloud matches display name and email; Server matches username,
+// name, and email.
+func (c *Client) SearchUsers(ctx context.Context, query string, maxResults int) ([]User, error) {
+	if query == "" {
+		return nil, ErrUserRequired
+	}
+	if maxResults <= 0 {
+		maxResults = DefaultUserSearchResults
+	}
+
+	param := "username"
+	if c.usesAccountIDs() {
+		param = "query"
+	}
+	params := url.Values{param: {query}, "maxResults": {strconv.Itoa(maxResults)}}
+
+	var users []User
+	if err := c.send(ctx, http.MethodGet, c.apiPath("/user/search")+"?"+params.Encode(), nil, &users, nil); err != nil {
+		return nil, fmt.Errorf("search users %q: %w", query, err)
+	}
+	return users, nil
+}
+
+// FindUser resolves query (an email address, username, account ID, or
+// display name) to exactly one active user, for use with AssignIssue and
+// UserRef. Exact matches win over partial ones; several equally good
+// matches fail with ErrUserAmbiguous.
+func (c *Client) FindUser(ctx context.Context, query string) (*User, error) {
+	users, err := c.SearchUsers(ctx, query, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var exact, active []*User
+	for i := range users {
+		u := &users[i]
+		if !u.Active {
+			continue
+		}
+		active = append(active, u)
+		if strings.EqualFold(u.EmailAddress, query) || u.AccountID == query ||
+			strings.EqualFold(u.Name, query) || strings.EqualFold(u.DisplayName, query) {
+			exact = append(exact, u)
+		}
+	}
+
+	matches := exact
+	if len(matches) == 0 {
+		matches = active
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %q", ErrUserNotFound, query)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %d users match %q", ErrUserAmbiguous, len(matches), query)
+	}
+}
+
+// AssignIssue assigns an issue to a user, given as an account ID on Cloud or
+// a username on Server (as returned by User.GetID). An empty user unassigns
+// the issue.
+func (c *Client) AssignIssue(ctx context.Context, key, user string) error {
+	if !ValidateIssueKey(key) {
+		return ErrIssueKeyInvalid
+	}
+
+	// Unassigning needs an explicit null, which UserRef would omit
+	var body any = c.userRef(user)
+	if user == "" {
+		field := "name"
+		if c.usesAccountIDs() {
+			field = "accountId"
+		}
+		body = map[string]any{field: nil}
+	}
+	defer c.InvalidateIssue(key)
+	return c.send(ctx, http.MethodPut, c.apiPath("/issue/"+key+"/assignee"), body, nil, ErrIssueNotFound)
+}
+
+// AddWatcher adds a user (account ID on Cloud, username on Server) to an
+// issue's watchers.
+func (c *Client) AddWatcher(ctx context.Context, key, user string) error {
+	if !ValidateIssueKey(key) {
+		return ErrIssueKeyInvalid
+	}
+	if user == "" {
+		return ErrUserRequired
+	}
+
+	// The body is the bare identifier as a JSON string
+	defer c.InvalidateIssue(key)
+	return c.send(ctx, http.MethodPost, c.apiPath("/issue/"+key+"/watchers"), user, nil, ErrIssueNotFound)
+}
+
+// RemoveWatcher removes a user (account ID on Cloud, username on Server)
+// from an issue's watchers.
+func (c *Client) RemoveWatcher(ctx context.Context, key, user string) error {
+	if !ValidateIssueKey(key) {
+		return ErrIssueKeyInvalid
+	}
+	if user == "" {
+		return ErrUserRequired
+	}
+
+	param := "username"
+	if c.usesAccountIDs() {
+		param = "accountId"
+	}
+	path := c.apiPath("/issue/"+key+"/watchers") + "?" + url.Values{param: {user}}.Encode()
+	defer c.InvalidateIssue(key)
+	return c.send(ctx, http.MethodDelete, path, nil, nil, ErrIssueNotFound)
+}
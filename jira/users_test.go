@@ -0,0 +1,139 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestAssignIssue(t *testing.T) {
+	var bodies []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/rest/api/3/issue/PROJ-1/assignee" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	if err := client.AssignIssue(context.Background(), "PROJ-1", "5b10ac8d82e05b22cc7d4ef5"); err != nil {
+		t.Fatalf("AssignIssue() error = %v", err)
+	}
+	if err := client.AssignIssue(context.Background(), "PROJ-1", ""); err != nil {
+		t.Fatalf("AssignIssue(unassign) error = %v", err)
+	}
+	want := []string{`{"accountId":"5b10ac8d82e05b22cc7d4ef5"}`, `{"accountId":null}`}
+	if len(bodies) != 2 || bodies[0] != want[0] || bodies[1] != want[1] {
+		t.Errorf("bodies = %q, want %q", bodies, want)
+	}
+
+	// Server identifies users by username
+	client.deploymentType = DeploymentServer
+	bodies = nil
+	if err := client.AssignIssue(context.Background(), "PROJ-1", "jsmith"); err != nil {
+		t.Fatalf("AssignIssue() error = %v", err)
+	}
+	if len(bodies) != 1 || bodies[0] != `{"name":"jsmith"}` {
+		t.Errorf("server body = %q", bodies)
+	}
+}
+
+func TestWatchers(t *testing.T) {
+	var gotBody, gotQuery string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/PROJ-1/watchers" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		gotBody, gotQuery = string(body), r.URL.RawQuery
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	ctx := context.Background()
+
+	if err := client.AddWatcher(ctx, "PROJ-1", "abc123"); err != nil {
+		t.Fatalf("AddWatcher() error = %v", err)
+	}
+	if gotBody != `"abc123"` {
+		t.Errorf("AddWatcher body = %s", gotBody)
+	}
+
+	if err := client.RemoveWatcher(ctx, "PROJ-1", "abc123"); err != nil {
+		t.Fatalf("RemoveWatcher() error = %v", err)
+	}
+	if gotQuery != "accountId=abc123" {
+		t.Errorf("RemoveWatcher query = %s", gotQuery)
+	}
+
+	client.deploymentType = DeploymentDataCenter
+	if err := client.RemoveWatcher(ctx, "PROJ-1", "jsmith"); err != nil {
+		t.Fatalf("RemoveWatcher() error = %v", err)
+	}
+	if gotQuery != "username=jsmith" {
+		t.Errorf("server RemoveWatcher query = %s", gotQuery)
+	}
+
+	if err := client.AddWatcher(ctx, "PROJ-1", ""); !errors.Is(err, ErrUserRequired) {
+		t.Errorf("no user: error = %v, want ErrUserRequired", err)
+	}
+	if err := client.AddWatcher(ctx, "PROJ-2", "abc123"); !errors.Is(err, ErrIssueNotFound) {
+		t.Errorf("missing issue: error = %v, want ErrIssueNotFound", err)
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	var query string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/user/search" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `[
+			{"accountId":"a1","displayName":"Jane Doe","emailAddress":"jane@example.com","active":true},
+			{"accountId":"a2","displayName":"Jane Roe","emailAddress":"jroe@example.com","active":true},
+			{"accountId":"a3","displayName":"Jane Old","active":false}
+		]`)
+	}))
+	ctx := context.Background()
+
+	users, err := client.SearchUsers(ctx, "jane", 0)
+	if err != nil || len(users) != 3 {
+		t.Fatalf("SearchUsers() = %d users, %v", len(users), err)
+	}
+	if query != "maxResults=50&query=jane" {
+		t.Errorf("query = %s", query)
+	}
+
+	user, err := client.FindUser(ctx, "JANE@example.com")
+	if err != nil || user.AccountID != "a1" {
+		t.Errorf("FindUser(email) = %+v, %v", user, err)
+	}
+	if _, err := client.FindUser(ctx, "jane"); !errors.Is(err, ErrUserAmbiguous) {
+		t.Errorf("FindUser(jane) error = %v, want ErrUserAmbiguous", err)
+	}
+	if _, err := client.FindUser(ctx, "Jane Old"); !errors.Is(err, ErrUserAmbiguous) {
+		t.Errorf("FindUser(inactive) error = %v, want ErrUserAmbiguous (inactive users skipped)", err)
+	}
+
+	// Server searches by username
+	client.deploymentType = DeploymentServer
+	if _, err := client.SearchUsers(ctx, "jsmith", 10); err != nil {
+		t.Fatal(err)
+	}
+	if query != "maxResults=10&username=jsmith" {
+		t.Errorf("server query = %s", query)
+	}
+}
+
+func TestFindUser_NotFound(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	}))
+	if _, err := client.FindUser(context.Background(), "nobody"); !errors.Is(err, ErrUserNotFound) || !IsNotFound(err) {
+		t.Errorf("FindUser() error = %v, want ErrUserNotFound", err)
+	}
+}
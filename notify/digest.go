@@ -0,0 +1,192 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// DigestNotifier
+// =============================================================================
+
+// DigestConfig configures DigestNotifier.
+type DigestConfig struct {
+	Window      time.Duration // Aggregation window (default: 1h)
+	Events      []EventType   // Event types to aggregate (default: run completed/failed)
+	TopFailures int           // Failure messages listed in the summary (default: 5)
+}
+
+// DigestNotifier aggregates events over a window into one summary message,
+// reducing channel noise for batch workloads. Event types not being
+// aggregated pass straight through to the wrapped notifier.
+type DigestNotifier struct {
+	inner  Notifier
+	config DigestConfig
+
+	mu      sync.Mutex
+	start   time.Time
+	pending []Event
+}
+
+// NewDigestNotifier creates a digest notifier. Call Run to send digests
+// every window, or Flush to send one on demand.
+func NewDigestNotifier(inner Notifier, config DigestConfig) *DigestNotifier {
+	if config.Window <= 0 {
+		config.Window = time.Hour
+	}
+	if len(config.Events) == 0 {
+		config.Events = []EventType{EventRunCompleted, EventRunFailed}
+	}
+	if config.TopFailures <= 0 {
+		config.TopFailures = 5
+	}
+	return &DigestNotifier{inner: inner, config: config, start: time.Now()}
+}
+
+// Notify implements Notifier.
+func (n *DigestNotifier) Notify(ctx context.Context, event Event) error {
+	if !slices.Contains(n.config.Events, event.Type) {
+		return n.inner.Notify(ctx, event)
+	}
+
+	n.mu.Lock()
+	n.pending = append(n.pending, event)
+	n.mu.Unlock()
+	return nil
+}
+
+// Run flushes a digest every window until ctx is canceled, then flushes
+// what is left.
+func (n *DigestNotifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(n.config.Window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = n.Flush(context.Background())
+			return
+		case <-ticker.C:
+			_ = n.Flush(ctx)
+		}
+	}
+}
+
+// Flush sends a digest of the events collected since the last flush. Nothing
+// is sent for an empty window.
+func (n *DigestNotifier) Flush(ctx context.Context) error {
+	n.mu.Lock()
+	events := n.pending
+	start := n.start
+	n.pending = nil
+	n.start = time.Now()
+	n.mu.Unlock()
+
+	if len(events) == 0 {
+		return nil
+	}
+	return n.inner.Notify(ctx, n.summarize(events, start, time.Now()))
+}
+
+// summarize builds the digest event for a window
+func (n *DigestNotifier) summarize(events []Event, start, end time.Time) Event {
+	var completed, failed int
+	var cost float64
+	var tokens int64
+	failures := make(map[string]int)
+
+	for _, e := range events {
+		switch e.Type {
+		case EventRunCompleted:
+			completed++
+		case EventRunFailed, EventNodeFailed:
+			if e.Type == EventRunFailed {
+				failed++
+			}
+			msg := strings.TrimSpace(strings.SplitN(e.Message, "\n", 2)[0])
+			if msg == "" {
+				msg = "(no message)"
+			}
+			failures[msg]++
+		}
+		cost += metaFloat(e.Metadata, "cost")
+		tokens += int64(metaFloat(e.Metadata, "tokensIn") + metaFloat(e.Metadata, "tokensOut"))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Digest %s - %s: %d events",
+		start.Format("2006-01-02 15:04"), end.Format("15:04"), len(events))
+	if completed+failed > 0 {
+		fmt.Fprintf(&sb, ", %d runs completed, %d failed", completed, failed)
+	}
+	if cost > 0 {
+		fmt.Fprintf(&sb, ", $%.2f total cost", cost)
+	}
+
+	type failure struct {
+		msg   string
+		count int
+	}
+	var top []failure
+	for msg, c := range failures {
+		top = append(top, failure{msg, c})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].count != top[j].count {
+			return top[i].count > top[j].count
+		}
+		return top[i].msg < top[j].msg
+	})
+	if len(top) > n.config.TopFailures {
+		top = top[:n.config.TopFailures]
+	}
+	if len(top) > 0 {
+		sb.WriteString("\nTop failures:")
+		for _, f := range top {
+			fmt.Fprintf(&sb, "\n- %s (%dx)", f.msg, f.count)
+		}
+	}
+
+	severity := SeverityInfo
+	if failed > 0 {
+		severity = SeverityWarning
+	}
+
+	meta := map[string]any{
+		"events":      len(events),
+		"completed":   completed,
+		"failed":      failed,
+		"cost":        cost,
+		"tokens":      tokens,
+		"windowStart": start,
+	}
+
+	return Event{
+		Type:      EventDigest,
+		Message:   sb.String(),
+		Severity:  severity,
+		Timestamp: end,
+		Metadata:  meta,
+	}
+}
+
+// metaFloat reads a numeric metadata value
+func metaFloat(meta map[string]any, key string) float64 {
+	switch v := meta[key].(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// DigestNotifier Tests
+// =============================================================================
+
+func TestDigestNotifier(t *testing.T) {
+	var sent []Event
+	inner := NotifierFunc(func(_ context.Context, event Event) error {
+		sent = append(sent, event)
+		return nil
+	})
+	n := NewDigestNotifier(inner, DigestConfig{TopFailures: 1})
+	ctx := context.Background()
+
+	n.Notify(ctx, Event{Type: EventRunCompleted, Metadata: map[string]any{"cost": 0.5, "tokensIn": 100, "tokensOut": 50}})
+	n.Notify(ctx, Event{Type: EventRunCompleted, Metadata: map[string]any{"cost": 0.25}})
+	n.Notify(ctx, Event{Type: EventRunFailed, Message: "tests failed\nstack..."})
+	n.Notify(ctx, Event{Type: EventRunFailed, Message: "tests failed"})
+	n.Notify(ctx, Event{Type: EventRunFailed, Message: "lint failed"})
+	n.Notify(ctx, Event{Type: EventPRCreated, Message: "PR opened"}) // Not aggregated
+
+	if len(sent) != 1 || sent[0].Type != EventPRCreated {
+		t.Fatalf("before flush sent = %+v, want only pr_created", sent)
+	}
+
+	if err := n.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("sent %d events, want 2", len(sent))
+	}
+
+	digest := sent[1]
+	if digest.Type != EventDigest || digest.Severity != SeverityWarning {
+		t.Errorf("digest type/severity = %s/%s", digest.Type, digest.Severity)
+	}
+	for _, want := range []string{"2 runs completed, 3 failed", "$0.75 total cost", "- tests failed (2x)"} {
+		if !strings.Contains(digest.Message, want) {
+			t.Errorf("digest missing %q:\n%s", want, digest.Message)
+		}
+	}
+	if strings.Contains(digest.Message, "lint failed") {
+		t.Errorf("TopFailures=1 should list only the most common failure:\n%s", digest.Message)
+	}
+	if digest.Metadata["tokens"] != int64(150) {
+		t.Errorf("tokens = %v, want 150", digest.Metadata["tokens"])
+	}
+
+	// Empty window sends nothing
+	n.Flush(ctx)
+	if len(sent) != 2 {
+		t.Errorf("empty flush sent an event")
+	}
+}
+
+func TestDigestNotifier_Run(t *testing.T) {
+	sent := make(chan Event, 10)
+	inner := NotifierFunc(func(_ context.Context, event Event) error {
+		sent <- event
+		return nil
+	})
+	n := NewDigestNotifier(inner, DigestConfig{Window: 10 * time.Millisecond})
+	n.Notify(context.Background(), Event{Type: EventRunCompleted})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go n.Run(ctx)
+
+	select {
+	case e := <-sent:
+		if e.Type != EventDigest {
+			t.Errorf("Type = %s, want digest", e.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no digest sent")
+	}
+}
@@ -7,10 +7,19 @@
 //
 // Implementations:
 //   - SlackNotifier: Sends notifications to Slack webhooks
-//   - WebhookNotifier: Sends notifications to generic webhooks
+//   - WebhookNotifier: Sends notifications to generic webhooks, optionally
+//     HMAC-signed and in custom formats such as CloudEvents
 //   - LogNotifier: Logs notifications (for testing/debugging)
 //   - MultiNotifier: Combines multiple notifiers
+//   - QueueNotifier: Async delivery with retry, backoff, and dead-lettering
+//   - DigestNotifier: Aggregates events into hourly/daily summaries
+//   - ThrottleNotifier: Deduplicates events and rate limits per channel
+//   - Router: Sends events to notifiers by event type, flow, and severity
 //   - NopNotifier: No-op notifier (for testing)
+//   - TemplateNotifier: Formats messages from per-event-type templates
+//
+// Close and HealthCheck shut down and probe notifiers that support it,
+// cascading through wrappers and fan-out notifiers.
 //
 // Example usage:
 //
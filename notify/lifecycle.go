@@ -0,0 +1,144 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// =============================================================================
+// Lifecycle
+// =============================================================================
+
+// Closer is implemented by notifiers that hold resources, such as
+// QueueNotifier's worker, and by wrappers that pass Close on to them.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// HealthChecker is implemented by notifiers that can report readiness.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// Close shuts n down if it implements Closer. Wrappers and fan-out notifiers
+// cascade, so closing the outermost notifier flushes everything behind it.
+func Close(ctx context.Context, n Notifier) error {
+	if c, ok := n.(Closer); ok {
+		return c.Close(ctx)
+	}
+	return nil
+}
+
+// HealthCheck checks n if it implements HealthChecker.
+func HealthCheck(ctx context.Context, n Notifier) error {
+	if h, ok := n.(HealthChecker); ok {
+		return h.HealthCheck(ctx)
+	}
+	return nil
+}
+
+// closeAll closes each notifier, joining errors
+func closeAll(ctx context.Context, notifiers ...Notifier) error {
+	var errs []error
+	for _, n := range notifiers {
+		if err := Close(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkAll checks each notifier, joining errors
+func checkAll(ctx context.Context, notifiers ...Notifier) error {
+	var errs []error
+	for _, n := range notifiers {
+		if err := HealthCheck(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close implements Closer for all notifiers.
+func (m *MultiNotifier) Close(ctx context.Context) error {
+	return closeAll(ctx, m.Notifiers...)
+}
+
+// HealthCheck implements HealthChecker for all notifiers.
+func (m *MultiNotifier) HealthCheck(ctx context.Context) error {
+	return checkAll(ctx, m.Notifiers...)
+}
+
+// routeNotifiers lists every destination of the router
+func (r *Router) routeNotifiers() []Notifier {
+	notifiers := make([]Notifier, 0, len(r.Routes)+1)
+	for _, route := range r.Routes {
+		notifiers = append(notifiers, route.Notifier)
+	}
+	if r.Fallback != nil {
+		notifiers = append(notifiers, r.Fallback)
+	}
+	return notifiers
+}
+
+// Close implements Closer for every route and the fallback.
+func (r *Router) Close(ctx context.Context) error {
+	return closeAll(ctx, r.routeNotifiers()...)
+}
+
+// HealthCheck implements HealthChecker for every route and the fallback.
+func (r *Router) HealthCheck(ctx context.Context) error {
+	return checkAll(ctx, r.routeNotifiers()...)
+}
+
+// Close implements Closer for the wrapped notifier.
+func (n *TemplateNotifier) Close(ctx context.Context) error {
+	return Close(ctx, n.Notifier)
+}
+
+// HealthCheck implements HealthChecker for the wrapped notifier.
+func (n *TemplateNotifier) HealthCheck(ctx context.Context) error {
+	return HealthCheck(ctx, n.Notifier)
+}
+
+// Close implements Closer for the wrapped notifier.
+func (n *ThrottleNotifier) Close(ctx context.Context) error {
+	return Close(ctx, n.inner)
+}
+
+// HealthCheck implements HealthChecker for the wrapped notifier.
+func (n *ThrottleNotifier) HealthCheck(ctx context.Context) error {
+	return HealthCheck(ctx, n.inner)
+}
+
+// Close implements Closer. It sends a final digest of pending events, then
+// closes the wrapped notifier.
+func (n *DigestNotifier) Close(ctx context.Context) error {
+	flushErr := n.Flush(ctx)
+	if flushErr != nil {
+		flushErr = fmt.Errorf("flush digest: %w", flushErr)
+	}
+	return errors.Join(flushErr, Close(ctx, n.inner))
+}
+
+// HealthCheck implements HealthChecker for the wrapped notifier.
+func (n *DigestNotifier) HealthCheck(ctx context.Context) error {
+	return HealthCheck(ctx, n.inner)
+}
+
+// HealthCheck implements HealthChecker. The queue is unhealthy when closed or
+// full; otherwise the wrapped notifier is checked.
+func (q *QueueNotifier) HealthCheck(ctx context.Context) error {
+	q.mu.RLock()
+	closed := q.closed
+	q.mu.RUnlock()
+
+	switch {
+	case closed:
+		return ErrQueueClosed
+	case q.Pending() >= q.config.BufferSize:
+		return ErrQueueFull
+	}
+	return HealthCheck(ctx, q.inner)
+}
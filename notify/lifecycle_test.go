@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// =============================================================================
+// Lifecycle Tests
+// =============================================================================
+
+// closingNotifier records Close and reports a fixed health
+type closingNotifier struct {
+	closed int
+	health error
+}
+
+func (n *closingNotifier) Notify(context.Context, Event) error { return nil }
+
+func (n *closingNotifier) Close(context.Context) error {
+	n.closed++
+	return nil
+}
+
+func (n *closingNotifier) HealthCheck(context.Context) error { return n.health }
+
+func TestClose_Cascades(t *testing.T) {
+	a, b, c := &closingNotifier{}, &closingNotifier{}, &closingNotifier{}
+
+	queue := NewQueueNotifier(NewThrottleNotifier(a, ThrottleConfig{}), QueueConfig{})
+	router := NewRouter(Route{Notifier: queue})
+	router.Fallback = NewTemplateNotifier(b, NewTemplateSet())
+	multi := NewMultiNotifier(router, NewDigestNotifier(c, DigestConfig{}), NewLogNotifier(nil))
+
+	if err := Close(context.Background(), multi); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	for name, n := range map[string]*closingNotifier{"queue": a, "fallback": b, "digest": c} {
+		if n.closed != 1 {
+			t.Errorf("%s: closed %d times, want 1", name, n.closed)
+		}
+	}
+
+	if err := HealthCheck(context.Background(), queue); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("HealthCheck(closed queue) = %v, want ErrQueueClosed", err)
+	}
+}
+
+func TestHealthCheck_Joins(t *testing.T) {
+	errDown := errors.New("down")
+	multi := NewMultiNotifier(&closingNotifier{}, &closingNotifier{health: errDown}, NopNotifier{})
+
+	if err := HealthCheck(context.Background(), multi); !errors.Is(err, errDown) {
+		t.Errorf("HealthCheck() = %v, want errDown", err)
+	}
+	if err := HealthCheck(context.Background(), NopNotifier{}); err != nil {
+		t.Errorf("HealthCheck(nop) = %v, want nil", err)
+	}
+}
@@ -3,6 +3,7 @@ package notify
 import (
 	"context"
 	"log/slog"
+	"slices"
 )
 
 // =============================================================================
@@ -41,6 +42,101 @@ func (n *MultiNotifier) Notify(ctx context.Context, event Event) error {
 	return lastErr // Return last error, if any
 }
 
+// =============================================================================
+// Router
+// =============================================================================
+
+// Route sends events matching all of its filters to Notifier. Empty
+// filters match everything.
+type Route struct {
+	Name        string      // For logs
+	Notifier    Notifier    // Destination
+	Events      []EventType // Event types to send (empty: all)
+	Flows       []string    // Flow IDs to send (empty: all)
+	MinSeverity string      // Lowest severity to send (empty: all)
+
+	// Stop skips later routes once this one matches, so a specific route
+	// (e.g. one flow's channel) can override a general one listed after it.
+	Stop bool
+}
+
+// Matches reports whether event passes the route's filters.
+func (r Route) Matches(event Event) bool {
+	if len(r.Events) > 0 && !slices.Contains(r.Events, event.Type) {
+		return false
+	}
+	if len(r.Flows) > 0 && !slices.Contains(r.Flows, event.FlowID) {
+		return false
+	}
+	if r.MinSeverity != "" && severityRank(event.Severity) < severityRank(r.MinSeverity) {
+		return false
+	}
+	return true
+}
+
+// severityRank orders severities; unknown or empty severities rank as info.
+func severityRank(severity string) int {
+	switch severity {
+	case SeverityWarning:
+		return 1
+	case SeverityError:
+		return 2
+	case SeverityCritical:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Router sends each event to the notifiers of matching routes, in order,
+// instead of every notifier receiving every event.
+type Router struct {
+	Routes   []Route
+	Fallback Notifier // Receives events no route matched (may be nil)
+	Logger   *slog.Logger
+}
+
+// NewRouter creates a router. Errors from individual routes are logged but
+// don't stop other routes.
+func NewRouter(routes ...Route) *Router {
+	return &Router{
+		Routes: routes,
+		Logger: slog.Default(),
+	}
+}
+
+// Notify implements Notifier.
+func (r *Router) Notify(ctx context.Context, event Event) error {
+	var lastErr error
+	matched := false
+
+	for _, route := range r.Routes {
+		if !route.Matches(event) {
+			continue
+		}
+		matched = true
+
+		if err := route.Notifier.Notify(ctx, event); err != nil {
+			lastErr = err
+			if r.Logger != nil {
+				r.Logger.Warn("notifier failed",
+					"error", err,
+					"route", route.Name,
+					"event_type", event.Type,
+				)
+			}
+		}
+		if route.Stop {
+			break
+		}
+	}
+
+	if !matched && r.Fallback != nil {
+		return r.Fallback.Notify(ctx, event)
+	}
+	return lastErr // Return last error, if any
+}
+
 // =============================================================================
 // NopNotifier
 // =============================================================================
@@ -22,6 +22,8 @@ const (
 	EventNodeFailed    EventType = "node_failed"
 	EventReviewNeeded  EventType = "review_needed"
 	EventPRCreated     EventType = "pr_created"
+	EventTurnRecorded  EventType = "turn_recorded"
+	EventDigest        EventType = "digest"
 )
 
 // Severity constants for notifications and findings.
@@ -43,6 +45,10 @@ type Event struct {
 	Severity  string         `json:"severity"` // SeverityInfo, SeverityWarning, SeverityError
 	Timestamp time.Time      `json:"timestamp"`
 	Metadata  map[string]any `json:"metadata,omitempty"`
+
+	// State is the workflow state, available to message templates as
+	// {{.State}}. It is not serialized.
+	State any `json:"-"`
 }
 
 // =============================================================================
@@ -56,6 +62,14 @@ type Notifier interface {
 	Notify(ctx context.Context, event Event) error
 }
 
+// NotifierFunc adapts a function to the Notifier interface.
+type NotifierFunc func(ctx context.Context, event Event) error
+
+// Notify calls f(ctx, event).
+func (f NotifierFunc) Notify(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
 // =============================================================================
 // Context Injection
 // =============================================================================
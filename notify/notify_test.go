@@ -28,6 +28,8 @@ func TestEventTypes(t *testing.T) {
 		EventNodeFailed,
 		EventReviewNeeded,
 		EventPRCreated,
+		EventTurnRecorded,
+		EventDigest,
 	}
 
 	seen := make(map[EventType]bool)
@@ -39,6 +41,21 @@ func TestEventTypes(t *testing.T) {
 	}
 }
 
+func TestNotifierFunc(t *testing.T) {
+	var got Event
+	var n Notifier = NotifierFunc(func(_ context.Context, event Event) error {
+		got = event
+		return nil
+	})
+
+	if err := n.Notify(context.Background(), Event{Type: EventTurnRecorded, RunID: "run-1"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got.Type != EventTurnRecorded || got.RunID != "run-1" {
+		t.Errorf("NotifierFunc received %+v", got)
+	}
+}
+
 func TestSeverityLevels(t *testing.T) {
 	// Verify severity levels are unique
 	levels := []string{SeverityInfo, SeverityWarning, SeverityError}
@@ -211,6 +228,71 @@ func TestWebhookNotifier_CustomHeaders(t *testing.T) {
 	}
 }
 
+func TestWebhookNotifier_Signature(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, nil, WithWebhookSecret("s3cret"))
+	if err := n.Notify(context.Background(), Event{Type: EventRunStarted, RunID: "run-1"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if !strings.HasPrefix(signature, "sha256=") {
+		t.Fatalf("signature = %q, want sha256= prefix", signature)
+	}
+	if !VerifySignature("s3cret", body, signature) {
+		t.Error("VerifySignature() = false for valid signature")
+	}
+	if VerifySignature("wrong", body, signature) {
+		t.Error("VerifySignature() = true for wrong secret")
+	}
+}
+
+func TestWebhookNotifier_CloudEventsPayload(t *testing.T) {
+	var contentType, custom string
+	var ce map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		custom = r.Header.Get("X-Source")
+		_ = json.NewDecoder(r.Body).Decode(&ce)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, nil,
+		WithWebhookHeader("X-Source", "devflow"),
+		WithWebhookPayload(CloudEventsPayload("devflow/test")),
+	)
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := n.Notify(context.Background(), Event{Type: EventRunCompleted, RunID: "run-1", Timestamp: ts})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if contentType != "application/cloudevents+json" {
+		t.Errorf("Content-Type = %q", contentType)
+	}
+	if custom != "devflow" {
+		t.Errorf("X-Source = %q, want devflow", custom)
+	}
+	if ce["specversion"] != "1.0" || ce["type"] != "dev.devflow.run_completed" || ce["source"] != "devflow/test" {
+		t.Errorf("unexpected envelope: %v", ce)
+	}
+	if ce["subject"] != "run-1" || ce["time"] != "2024-01-02T03:04:05Z" {
+		t.Errorf("subject/time = %v/%v", ce["subject"], ce["time"])
+	}
+	data, _ := ce["data"].(map[string]any)
+	if data["run_id"] != "run-1" {
+		t.Errorf("data = %v, want event", data)
+	}
+}
+
 func TestWebhookNotifier_ErrorStatus(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -280,6 +362,124 @@ func TestSlackNotifier(t *testing.T) {
 	}
 }
 
+func TestSlackNotifier_Blocks(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := NewSlackNotifier(server.URL,
+		WithSlackBlocks(),
+		WithSlackTranscriptURL("https://devflow.example.com/runs/%s"),
+	)
+
+	err := n.Notify(context.Background(), Event{
+		Type:     EventRunCompleted,
+		RunID:    "run-123",
+		Message:  "Workflow completed successfully",
+		Metadata: map[string]any{"prUrl": "https://github.com/org/repo/pull/1", "cost": 0.42},
+	})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	raw, _ := json.Marshal(received)
+	body := string(raw)
+	for _, want := range []string{
+		`"type":"header"`,
+		`"type":"actions"`,
+		`"url":"https://github.com/org/repo/pull/1"`,
+		`"url":"https://devflow.example.com/runs/run-123"`,
+		`*cost*\n0.42`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("payload missing %s: %s", want, body)
+		}
+	}
+	if strings.Contains(body, `"text":"*prUrl*`) {
+		t.Error("prUrl should be a button, not a field")
+	}
+}
+
+func TestSlackNotifier_Threads(t *testing.T) {
+	var threadTS []string
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat.postMessage" {
+			t.Errorf("path = %s, want /chat.postMessage", r.URL.Path)
+		}
+		auth = r.Header.Get("Authorization")
+		var p slackPayload
+		json.NewDecoder(r.Body).Decode(&p)
+		threadTS = append(threadTS, p.ThreadTS)
+		w.Write([]byte(`{"ok":true,"ts":"1700000000.000100"}`))
+	}))
+	defer server.Close()
+
+	n := NewSlackNotifier("", WithSlackToken("xoxb-test"), WithSlackChannel("C123"), WithSlackThreads())
+	n.APIURL = server.URL
+
+	ctx := context.Background()
+	for _, et := range []EventType{EventRunStarted, EventNodeCompleted, EventRunCompleted} {
+		if err := n.Notify(ctx, Event{Type: et, RunID: "run-1"}); err != nil {
+			t.Fatalf("Notify(%s) error = %v", et, err)
+		}
+	}
+	// Thread is forgotten once the run ends
+	if err := n.Notify(ctx, Event{Type: EventNodeStarted, RunID: "run-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"", "1700000000.000100", "1700000000.000100", ""}
+	if strings.Join(threadTS, ",") != strings.Join(want, ",") {
+		t.Errorf("thread_ts = %v, want %v", threadTS, want)
+	}
+	if auth != "Bearer xoxb-test" {
+		t.Errorf("Authorization = %q", auth)
+	}
+}
+
+func TestSlackNotifier_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+	}))
+	defer server.Close()
+
+	n := NewSlackNotifier("", WithSlackToken("xoxb-test"))
+	n.APIURL = server.URL
+
+	err := n.Notify(context.Background(), Event{Type: EventRunStarted})
+	if err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Errorf("error = %v, want channel_not_found", err)
+	}
+}
+
+func TestSlackNotifier_FlowChannel(t *testing.T) {
+	var receivedPayload slackPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&receivedPayload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := NewSlackNotifier(server.URL,
+		WithSlackChannel("#dev"),
+		WithSlackFlowChannel("release", "#releases"),
+	)
+
+	n.Notify(context.Background(), Event{Type: EventRunCompleted, FlowID: "release"})
+	if receivedPayload.Channel != "#releases" {
+		t.Errorf("Channel = %s, want #releases", receivedPayload.Channel)
+	}
+
+	n.Notify(context.Background(), Event{Type: EventRunCompleted, FlowID: "ticket-to-pr"})
+	if receivedPayload.Channel != "#dev" {
+		t.Errorf("Channel = %s, want #dev", receivedPayload.Channel)
+	}
+}
+
 func TestSlackNotifier_EmojiForEvent(t *testing.T) {
 	n := &SlackNotifier{}
 
@@ -292,6 +492,8 @@ func TestSlackNotifier_EmojiForEvent(t *testing.T) {
 		{EventRunFailed, "❌"},
 		{EventPRCreated, "🔗"},
 		{EventReviewNeeded, "👀"},
+		{EventTurnRecorded, "💬"},
+		{EventDigest, "📊"},
 	}
 
 	for _, tt := range tests {
@@ -385,6 +587,59 @@ func (m *mockNotifier) Notify(ctx context.Context, event Event) error {
 	return m.err
 }
 
+func TestRouter(t *testing.T) {
+	var calls []string
+	slack := &mockNotifier{name: "slack", calls: &calls}
+	releases := &mockNotifier{name: "releases", calls: &calls}
+	pager := &mockNotifier{name: "pager", calls: &calls}
+	fallback := &mockNotifier{name: "fallback", calls: &calls}
+
+	router := NewRouter(
+		Route{Name: "pager", Notifier: pager, Events: []EventType{EventRunFailed}, MinSeverity: SeverityError},
+		Route{Name: "releases", Notifier: releases, Flows: []string{"release"}, Stop: true},
+		Route{Name: "slack", Notifier: slack, Events: []EventType{EventRunCompleted, EventRunFailed}},
+	)
+	router.Fallback = fallback
+
+	tests := []struct {
+		event Event
+		want  string
+	}{
+		{Event{Type: EventRunFailed, Severity: SeverityError}, "pager,slack"},
+		{Event{Type: EventRunFailed, Severity: SeverityWarning}, "slack"},
+		{Event{Type: EventRunCompleted}, "slack"},
+		{Event{Type: EventRunCompleted, FlowID: "release"}, "releases"},
+		{Event{Type: EventNodeStarted}, "fallback"},
+	}
+
+	for _, tt := range tests {
+		calls = nil
+		if err := router.Notify(context.Background(), tt.event); err != nil {
+			t.Errorf("Notify(%+v) error = %v", tt.event, err)
+		}
+		if got := strings.Join(calls, ","); got != tt.want {
+			t.Errorf("Notify(%s, flow=%q, severity=%q) routed to %q, want %q",
+				tt.event.Type, tt.event.FlowID, tt.event.Severity, got, tt.want)
+		}
+	}
+}
+
+func TestRouter_ContinuesOnError(t *testing.T) {
+	var calls []string
+	router := NewRouter(
+		Route{Notifier: &mockNotifier{name: "n1", calls: &calls, err: context.DeadlineExceeded}},
+		Route{Notifier: &mockNotifier{name: "n2", calls: &calls}},
+	)
+	router.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	if err := router.Notify(context.Background(), Event{Type: EventRunStarted}); err == nil {
+		t.Error("Router should return last error")
+	}
+	if len(calls) != 2 {
+		t.Errorf("Call count = %d, want 2", len(calls))
+	}
+}
+
 // =============================================================================
 // Context Injection Tests
 // =============================================================================
@@ -0,0 +1,269 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// QueueNotifier
+// =============================================================================
+
+// Queue errors.
+var (
+	ErrQueueFull   = errors.New("notification queue full")
+	ErrQueueClosed = errors.New("notification queue closed")
+)
+
+// QueueConfig configures QueueNotifier.
+type QueueConfig struct {
+	BufferSize     int           // Pending events before Notify rejects (default: 100)
+	MaxAttempts    int           // Delivery attempts per event (default: 5)
+	InitialBackoff time.Duration // Wait after the first failure (default: 1s)
+	MaxBackoff     time.Duration // Backoff cap (default: 1m)
+	DeadLetter     DeadLetterSink
+	Logger         *slog.Logger
+}
+
+// DeadLetter is a notification that could not be delivered.
+type DeadLetter struct {
+	Event    Event     `json:"event"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// DeadLetterSink stores undeliverable notifications.
+type DeadLetterSink interface {
+	DeadLetter(ctx context.Context, dl DeadLetter) error
+}
+
+// DeadLetterFunc adapts a function to DeadLetterSink.
+type DeadLetterFunc func(ctx context.Context, dl DeadLetter) error
+
+// DeadLetter calls f(ctx, dl).
+func (f DeadLetterFunc) DeadLetter(ctx context.Context, dl DeadLetter) error {
+	return f(ctx, dl)
+}
+
+// ArtifactSaver stores run artifacts. *artifact.Manager implements it.
+type ArtifactSaver interface {
+	SaveArtifact(runID, name string, data []byte) error
+}
+
+// ArtifactDeadLetter saves undeliverable notifications as JSON artifacts of
+// their run, under "notify-dead-letter/".
+type ArtifactDeadLetter struct {
+	Artifacts ArtifactSaver
+}
+
+// DeadLetter implements DeadLetterSink.
+func (s ArtifactDeadLetter) DeadLetter(_ context.Context, dl DeadLetter) error {
+	if dl.Event.RunID == "" {
+		return errors.New("dead letter has no run ID")
+	}
+	data, err := json.MarshalIndent(dl, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal dead letter: %w", err)
+	}
+	name := fmt.Sprintf("notify-dead-letter/%d-%s.json", dl.FailedAt.UnixNano(), dl.Event.Type)
+	return s.Artifacts.SaveArtifact(dl.Event.RunID, name, data)
+}
+
+// QueueStats counts queue activity.
+type QueueStats struct {
+	Delivered    int64
+	Retries      int64
+	DeadLettered int64
+	Rejected     int64 // Refused because the buffer was full
+}
+
+// QueueNotifier delivers events asynchronously through a bounded buffer,
+// retrying failures with exponential backoff. Events that exhaust their
+// attempts, or are rejected by a full buffer, go to the dead-letter sink.
+type QueueNotifier struct {
+	inner  Notifier
+	config QueueConfig
+	queue  chan Event
+
+	mu     sync.RWMutex // Guards closed against sends on a closed queue
+	closed bool
+
+	statsMu sync.Mutex
+	stats   QueueStats
+
+	ctx    context.Context // Canceled when Close gives up waiting
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewQueueNotifier wraps inner with an async retry queue and starts its
+// worker. Call Close to flush pending events.
+func NewQueueNotifier(inner Notifier, config QueueConfig) *QueueNotifier {
+	if config.BufferSize <= 0 {
+		config.BufferSize = 100
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = time.Minute
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &QueueNotifier{
+		inner:  inner,
+		config: config,
+		queue:  make(chan Event, config.BufferSize),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// Notify implements Notifier. It enqueues the event and returns without
+// waiting for delivery.
+func (q *QueueNotifier) Notify(ctx context.Context, event Event) error {
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return ErrQueueClosed
+	}
+	select {
+	case q.queue <- event:
+		q.mu.RUnlock()
+		return nil
+	default:
+		q.mu.RUnlock()
+	}
+
+	q.count(func(s *QueueStats) { s.Rejected++ })
+	q.deadLetter(ctx, event, 0, ErrQueueFull)
+	return ErrQueueFull
+}
+
+// Close stops accepting events and waits for pending ones to be delivered,
+// then closes the wrapped notifier. If ctx ends first, in-flight retries
+// stop and the remaining events are dead-lettered.
+func (q *QueueNotifier) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.queue)
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+		q.cancel()
+		return Close(ctx, q.inner)
+	case <-ctx.Done():
+		q.cancel()
+		<-q.done
+		return ctx.Err()
+	}
+}
+
+// Stats returns a snapshot of queue counters.
+func (q *QueueNotifier) Stats() QueueStats {
+	q.statsMu.Lock()
+	defer q.statsMu.Unlock()
+	return q.stats
+}
+
+// Pending returns the number of events waiting for delivery.
+func (q *QueueNotifier) Pending() int {
+	return len(q.queue)
+}
+
+func (q *QueueNotifier) run() {
+	defer close(q.done)
+	for event := range q.queue {
+		q.deliver(event)
+	}
+}
+
+// deliver sends one event, retrying with backoff until it succeeds, runs
+// out of attempts, or the queue is abandoned.
+func (q *QueueNotifier) deliver(event Event) {
+	backoff := q.config.InitialBackoff
+	var err error
+
+	for attempt := 1; attempt <= q.config.MaxAttempts; attempt++ {
+		if q.ctx.Err() != nil {
+			closeErr := errors.New("queue closed before delivery")
+			if err != nil {
+				closeErr = fmt.Errorf("queue closed: %w", err)
+			}
+			q.deadLetter(context.Background(), event, attempt-1, closeErr)
+			return
+		}
+
+		if err = q.inner.Notify(q.ctx, event); err == nil {
+			q.count(func(s *QueueStats) { s.Delivered++ })
+			return
+		}
+		if attempt == q.config.MaxAttempts {
+			break
+		}
+
+		q.count(func(s *QueueStats) { s.Retries++ })
+		q.config.Logger.Debug("notification failed, retrying",
+			"error", err,
+			"event_type", event.Type,
+			"attempt", attempt,
+			"backoff", backoff,
+		)
+
+		select {
+		case <-time.After(backoff):
+		case <-q.ctx.Done():
+		}
+		backoff = min(backoff*2, q.config.MaxBackoff)
+	}
+
+	q.deadLetter(context.Background(), event, q.config.MaxAttempts, err)
+}
+
+// deadLetter hands an undeliverable event to the sink, or logs it
+func (q *QueueNotifier) deadLetter(ctx context.Context, event Event, attempts int, err error) {
+	q.count(func(s *QueueStats) { s.DeadLettered++ })
+
+	q.config.Logger.Warn("notification undeliverable",
+		"error", err,
+		"event_type", event.Type,
+		"run_id", event.RunID,
+		"attempts", attempts,
+	)
+	if q.config.DeadLetter == nil {
+		return
+	}
+
+	dl := DeadLetter{Event: event, Attempts: attempts, Error: err.Error(), FailedAt: time.Now()}
+	if sinkErr := q.config.DeadLetter.DeadLetter(ctx, dl); sinkErr != nil {
+		q.config.Logger.Warn("failed to store dead letter",
+			"error", sinkErr,
+			"event_type", event.Type,
+			"run_id", event.RunID,
+		)
+	}
+}
+
+func (q *QueueNotifier) count(f func(*QueueStats)) {
+	q.statsMu.Lock()
+	f(&q.stats)
+	q.statsMu.Unlock()
+}
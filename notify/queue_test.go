@@ -0,0 +1,202 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// QueueNotifier Tests
+// =============================================================================
+
+// flakyNotifier fails the first `failures` calls.
+type flakyNotifier struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	events   []Event
+}
+
+func (f *flakyNotifier) Notify(ctx context.Context, event Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("temporary failure")
+	}
+	f.events = append(f.events, event)
+	return nil
+}
+
+func quietLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestQueueNotifier_RetriesUntilDelivered(t *testing.T) {
+	inner := &flakyNotifier{failures: 2}
+	q := NewQueueNotifier(inner, QueueConfig{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Logger:         quietLogger(),
+	})
+
+	if err := q.Notify(context.Background(), Event{Type: EventRunCompleted, RunID: "run-1"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	stats := q.Stats()
+	if stats.Delivered != 1 || stats.Retries != 2 || stats.DeadLettered != 0 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+	if len(inner.events) != 1 || inner.events[0].RunID != "run-1" {
+		t.Errorf("delivered events = %+v", inner.events)
+	}
+}
+
+func TestQueueNotifier_DeadLetter(t *testing.T) {
+	var mu sync.Mutex
+	var letters []DeadLetter
+	sink := DeadLetterFunc(func(_ context.Context, dl DeadLetter) error {
+		mu.Lock()
+		letters = append(letters, dl)
+		mu.Unlock()
+		return nil
+	})
+
+	q := NewQueueNotifier(&flakyNotifier{failures: 100}, QueueConfig{
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+		DeadLetter:     sink,
+		Logger:         quietLogger(),
+	})
+	q.Notify(context.Background(), Event{Type: EventRunFailed, RunID: "run-1"})
+	q.Close(context.Background())
+
+	if len(letters) != 1 {
+		t.Fatalf("got %d dead letters, want 1", len(letters))
+	}
+	if letters[0].Attempts != 2 || letters[0].Error != "temporary failure" {
+		t.Errorf("dead letter = %+v", letters[0])
+	}
+}
+
+func TestQueueNotifier_BufferFull(t *testing.T) {
+	block := make(chan struct{})
+	inner := NotifierFunc(func(ctx context.Context, event Event) error {
+		<-block
+		return nil
+	})
+
+	var rejected []Event
+	q := NewQueueNotifier(inner, QueueConfig{
+		BufferSize: 1,
+		DeadLetter: DeadLetterFunc(func(_ context.Context, dl DeadLetter) error {
+			rejected = append(rejected, dl.Event)
+			return nil
+		}),
+		Logger: quietLogger(),
+	})
+
+	// First event is picked up by the worker, second fills the buffer
+	q.Notify(context.Background(), Event{RunID: "1"})
+	deadline := time.Now().Add(time.Second)
+	for q.Pending() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	q.Notify(context.Background(), Event{RunID: "2"})
+
+	if err := q.Notify(context.Background(), Event{RunID: "3"}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Notify() error = %v, want ErrQueueFull", err)
+	}
+	if len(rejected) != 1 || rejected[0].RunID != "3" {
+		t.Errorf("rejected = %+v", rejected)
+	}
+
+	close(block)
+	q.Close(context.Background())
+	if q.Stats().Rejected != 1 || q.Stats().Delivered != 2 {
+		t.Errorf("Stats() = %+v", q.Stats())
+	}
+}
+
+func TestQueueNotifier_CloseTimeout(t *testing.T) {
+	var mu sync.Mutex
+	deadLettered := 0
+	q := NewQueueNotifier(&flakyNotifier{failures: 100}, QueueConfig{
+		MaxAttempts:    10,
+		InitialBackoff: time.Hour,
+		DeadLetter: DeadLetterFunc(func(_ context.Context, dl DeadLetter) error {
+			mu.Lock()
+			deadLettered++
+			mu.Unlock()
+			return nil
+		}),
+		Logger: quietLogger(),
+	})
+	q.Notify(context.Background(), Event{RunID: "1"})
+	q.Notify(context.Background(), Event{RunID: "2"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := q.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close() error = %v, want DeadlineExceeded", err)
+	}
+	if deadLettered != 2 {
+		t.Errorf("dead lettered %d events, want 2", deadLettered)
+	}
+
+	if err := q.Notify(context.Background(), Event{}); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Notify() after Close error = %v, want ErrQueueClosed", err)
+	}
+}
+
+type memArtifacts struct {
+	saved map[string][]byte
+}
+
+func (m *memArtifacts) SaveArtifact(runID, name string, data []byte) error {
+	m.saved[runID+"/"+name] = data
+	return nil
+}
+
+func TestArtifactDeadLetter(t *testing.T) {
+	store := &memArtifacts{saved: make(map[string][]byte)}
+	sink := ArtifactDeadLetter{Artifacts: store}
+
+	dl := DeadLetter{
+		Event:    Event{Type: EventRunFailed, RunID: "run-1"},
+		Attempts: 5,
+		Error:    "timeout",
+		FailedAt: time.Unix(1700000000, 0),
+	}
+	if err := sink.DeadLetter(context.Background(), dl); err != nil {
+		t.Fatalf("DeadLetter() error = %v", err)
+	}
+
+	for name, data := range store.saved {
+		if !strings.HasPrefix(name, "run-1/notify-dead-letter/") || !strings.HasSuffix(name, "-run_failed.json") {
+			t.Errorf("artifact name = %s", name)
+		}
+		var got DeadLetter
+		if err := json.Unmarshal(data, &got); err != nil || got.Error != "timeout" {
+			t.Errorf("artifact = %s (err %v)", data, err)
+		}
+	}
+	if len(store.saved) != 1 {
+		t.Errorf("saved %d artifacts, want 1", len(store.saved))
+	}
+
+	if err := sink.DeadLetter(context.Background(), DeadLetter{}); err == nil {
+		t.Error("expected error without run ID")
+	}
+}
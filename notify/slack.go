@@ -6,27 +6,59 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 	"time"
+
+	devhttp "github.com/randalmurphal/devflow/http"
 )
 
 // =============================================================================
 // SlackNotifier
 // =============================================================================
 
-// SlackNotifier sends notifications to a Slack webhook.
+// SlackNotifier sends notifications to a Slack webhook, or with a bot
+// token through the chat.postMessage API.
 type SlackNotifier struct {
 	WebhookURL string
 	Channel    string
 	Username   string
 	Client     *http.Client
+
+	// Token is a bot token. When set, messages go through chat.postMessage
+	// (APIURL) to Channel instead of the webhook; required for Threads.
+	Token  string
+	APIURL string
+
+	// Blocks sends Block Kit layouts instead of legacy attachments.
+	Blocks bool
+
+	// Threads posts follow-up events for a run as replies to its
+	// run_started message.
+	Threads bool
+
+	// TranscriptURL is a fmt pattern taking the run ID, used for the
+	// "View transcript" button (e.g. "https://devflow.example.com/runs/%s").
+	TranscriptURL string
+
+	// FlowChannels overrides Channel for events of specific flows.
+	FlowChannels map[string]string
+
+	mu      sync.Mutex
+	threads map[string]string // runID -> ts of the run_started message
 }
 
+// DefaultSlackAPIURL is the Slack Web API base URL.
+const DefaultSlackAPIURL = "https://slack.com/api"
+
 // NewSlackNotifier creates a Slack webhook notifier.
 func NewSlackNotifier(webhookURL string, opts ...SlackOption) *SlackNotifier {
 	n := &SlackNotifier{
 		WebhookURL: webhookURL,
 		Username:   "devflow",
-		Client:     &http.Client{Timeout: 10 * time.Second},
+		Client:     devhttp.WithRetry(&http.Client{Timeout: 10 * time.Second}, devhttp.DefaultRetryPolicy()),
+		APIURL:     DefaultSlackAPIURL,
 	}
 	for _, opt := range opts {
 		opt(n)
@@ -47,31 +79,108 @@ func WithSlackUsername(username string) SlackOption {
 	return func(n *SlackNotifier) { n.Username = username }
 }
 
+// WithSlackFlowChannel posts events of one flow to a different channel.
+func WithSlackFlowChannel(flowID, channel string) SlackOption {
+	return func(n *SlackNotifier) {
+		if n.FlowChannels == nil {
+			n.FlowChannels = make(map[string]string)
+		}
+		n.FlowChannels[flowID] = channel
+	}
+}
+
+// WithSlackToken posts with a bot token through chat.postMessage.
+func WithSlackToken(token string) SlackOption {
+	return func(n *SlackNotifier) { n.Token = token }
+}
+
+// WithSlackBlocks sends Block Kit layouts with buttons to the PR and transcript.
+func WithSlackBlocks() SlackOption {
+	return func(n *SlackNotifier) { n.Blocks = true }
+}
+
+// WithSlackThreads threads a run's events under its run_started message.
+// Requires a bot token: webhooks do not return message timestamps.
+func WithSlackThreads() SlackOption {
+	return func(n *SlackNotifier) { n.Threads = true }
+}
+
+// WithSlackTranscriptURL sets the transcript link pattern (fmt, run ID).
+func WithSlackTranscriptURL(pattern string) SlackOption {
+	return func(n *SlackNotifier) { n.TranscriptURL = pattern }
+}
+
 // Notify implements Notifier.
 func (n *SlackNotifier) Notify(ctx context.Context, event Event) error {
+	payload := n.buildPayload(event)
+
+	if n.Threads && event.Type != EventRunStarted {
+		payload.ThreadTS = n.threadFor(event.RunID)
+	}
+
+	var ts string
+	var err error
+	if n.Token != "" {
+		ts, err = n.postMessage(ctx, payload)
+	} else {
+		err = n.postWebhook(ctx, payload)
+	}
+	if err != nil {
+		return err
+	}
+
+	if n.Threads && event.RunID != "" {
+		switch event.Type {
+		case EventRunStarted:
+			if ts != "" {
+				n.setThread(event.RunID, ts)
+			}
+		case EventRunCompleted, EventRunFailed:
+			n.setThread(event.RunID, "")
+		}
+	}
+	return nil
+}
+
+// buildPayload formats event as legacy attachments or Block Kit
+func (n *SlackNotifier) buildPayload(event Event) slackPayload {
 	// Format message for Slack
 	emoji := n.emojiForEvent(event)
 	color := n.colorForSeverity(event.Severity)
+	title := fmt.Sprintf("%s %s", emoji, event.Type)
 
 	payload := slackPayload{
 		Username: n.Username,
-		Attachments: []slackAttachment{
-			{
-				Color:      color,
-				Title:      fmt.Sprintf("%s %s", emoji, event.Type),
-				Text:       event.Message,
-				Footer:     fmt.Sprintf("Flow: %s | Run: %s", event.FlowID, event.RunID),
-				FooterIcon: "https://cdn.anthropic.com/claude-logo-32.png",
-				Timestamp:  event.Timestamp.Unix(),
-				Fields:     n.fieldsFromMetadata(event.Metadata),
-			},
-		},
+		Channel:  n.Channel,
+	}
+	if channel, ok := n.FlowChannels[event.FlowID]; ok {
+		payload.Channel = channel
+	}
+
+	if n.Blocks {
+		payload.Text = title + ": " + event.Message // Notification fallback
+		payload.Attachments = []slackAttachment{
+			{Color: color, Blocks: n.blocksForEvent(event, title)},
+		}
+		return payload
 	}
 
-	if n.Channel != "" {
-		payload.Channel = n.Channel
+	payload.Attachments = []slackAttachment{
+		{
+			Color:      color,
+			Title:      title,
+			Text:       event.Message,
+			Footer:     fmt.Sprintf("Flow: %s | Run: %s", event.FlowID, event.RunID),
+			FooterIcon: "https://cdn.anthropic.com/claude-logo-32.png",
+			Timestamp:  event.Timestamp.Unix(),
+			Fields:     n.fieldsFromMetadata(event.Metadata),
+		},
 	}
+	return payload
+}
 
+// postWebhook sends payload to the incoming webhook
+func (n *SlackNotifier) postWebhook(ctx context.Context, payload slackPayload) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshal slack payload: %w", err)
@@ -96,6 +205,138 @@ func (n *SlackNotifier) Notify(ctx context.Context, event Event) error {
 	return nil
 }
 
+// postMessage sends payload with chat.postMessage and returns the message ts
+func (n *SlackNotifier) postMessage(ctx context.Context, payload slackPayload) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("marshal slack payload: %w", err)
+	}
+
+	apiURL := n.APIURL
+	if apiURL == "" {
+		apiURL = DefaultSlackAPIURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(apiURL, "/")+"/chat.postMessage", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+n.Token)
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send slack message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("slack returned %d", resp.StatusCode)
+	}
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		TS    string `json:"ts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode slack response: %w", err)
+	}
+	if !result.OK {
+		return "", fmt.Errorf("slack error: %s", result.Error)
+	}
+	return result.TS, nil
+}
+
+// threadFor returns the thread ts for a run, if any
+func (n *SlackNotifier) threadFor(runID string) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.threads[runID]
+}
+
+// setThread records (or with ts "" forgets) a run's thread
+func (n *SlackNotifier) setThread(runID, ts string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if ts == "" {
+		delete(n.threads, runID)
+		return
+	}
+	if n.threads == nil {
+		n.threads = make(map[string]string)
+	}
+	n.threads[runID] = ts
+}
+
+// blocksForEvent builds a Block Kit layout: header, message, metadata
+// fields, run context, and link buttons
+func (n *SlackNotifier) blocksForEvent(event Event, title string) []slackBlock {
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: title, Emoji: true}},
+	}
+
+	if event.Message != "" {
+		blocks = append(blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: event.Message},
+		})
+	}
+
+	prURL, _ := event.Metadata["prUrl"].(string)
+	transcriptURL, _ := event.Metadata["transcriptUrl"].(string)
+	if transcriptURL == "" && n.TranscriptURL != "" && event.RunID != "" {
+		transcriptURL = fmt.Sprintf(n.TranscriptURL, event.RunID)
+	}
+
+	// Metadata as fields; Slack allows at most 10 per section
+	keys := make([]string, 0, len(event.Metadata))
+	for k := range event.Metadata {
+		if k != "prUrl" && k != "transcriptUrl" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > 10 {
+		keys = keys[:10]
+	}
+	if len(keys) > 0 {
+		fields := make([]slackText, 0, len(keys))
+		for _, k := range keys {
+			fields = append(fields, slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%v", k, event.Metadata[k])})
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Fields: fields})
+	}
+
+	footer := fmt.Sprintf("Flow: %s | Run: %s", event.FlowID, event.RunID)
+	if event.NodeID != "" {
+		footer += " | Node: " + event.NodeID
+	}
+	blocks = append(blocks, slackBlock{
+		Type:     "context",
+		Elements: []any{slackText{Type: "mrkdwn", Text: footer}},
+	})
+
+	var buttons []any
+	if prURL != "" {
+		buttons = append(buttons, slackButton{
+			Type: "button", Text: slackText{Type: "plain_text", Text: "View PR"},
+			URL: prURL, ActionID: "view_pr", Style: "primary",
+		})
+	}
+	if transcriptURL != "" {
+		buttons = append(buttons, slackButton{
+			Type: "button", Text: slackText{Type: "plain_text", Text: "View transcript"},
+			URL: transcriptURL, ActionID: "view_transcript",
+		})
+	}
+	if len(buttons) > 0 {
+		blocks = append(blocks, slackBlock{Type: "actions", Elements: buttons})
+	}
+
+	return blocks
+}
+
 func (n *SlackNotifier) emojiForEvent(event Event) string {
 	switch event.Type {
 	case EventRunStarted:
@@ -114,6 +355,10 @@ func (n *SlackNotifier) emojiForEvent(event Event) string {
 		return "✓"
 	case EventNodeFailed:
 		return "⚠️"
+	case EventTurnRecorded:
+		return "💬"
+	case EventDigest:
+		return "📊"
 	default:
 		return "📢"
 	}
@@ -146,21 +391,24 @@ func (n *SlackNotifier) fieldsFromMetadata(metadata map[string]any) []slackField
 	return fields
 }
 
-// Slack webhook payload types
+// Slack payload types
 type slackPayload struct {
 	Username    string            `json:"username,omitempty"`
 	Channel     string            `json:"channel,omitempty"`
+	Text        string            `json:"text,omitempty"`
+	ThreadTS    string            `json:"thread_ts,omitempty"`
 	Attachments []slackAttachment `json:"attachments"`
 }
 
 type slackAttachment struct {
 	Color      string       `json:"color,omitempty"`
-	Title      string       `json:"title"`
-	Text       string       `json:"text"`
+	Title      string       `json:"title,omitempty"`
+	Text       string       `json:"text,omitempty"`
 	Footer     string       `json:"footer,omitempty"`
 	FooterIcon string       `json:"footer_icon,omitempty"`
 	Timestamp  int64        `json:"ts,omitempty"`
 	Fields     []slackField `json:"fields,omitempty"`
+	Blocks     []slackBlock `json:"blocks,omitempty"`
 }
 
 type slackField struct {
@@ -168,3 +416,25 @@ type slackField struct {
 	Value string `json:"value"`
 	Short bool   `json:"short"`
 }
+
+// Block Kit types
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []any       `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type  string `json:"type"` // plain_text or mrkdwn
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+type slackButton struct {
+	Type     string    `json:"type"`
+	Text     slackText `json:"text"`
+	URL      string    `json:"url"`
+	ActionID string    `json:"action_id"`
+	Style    string    `json:"style,omitempty"`
+}
@@ -0,0 +1,177 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// =============================================================================
+// Message Templates
+// =============================================================================
+
+// Template renders an event message with text/template. Templates see the
+// event's fields ({{.RunID}}, {{.Message}}, {{.Metadata.prUrl}}) and the
+// workflow state attached to the event ({{.State.Branch}}).
+type Template struct {
+	tmpl *template.Template
+}
+
+// templateFuncs are available to all message templates.
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"truncate": func(n int, s string) string {
+		if len(s) <= n {
+			return s
+		}
+		return s[:n] + "..."
+	},
+	"default": func(def string, v any) any {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+}
+
+// NewTemplate parses a message template.
+func NewTemplate(text string) (*Template, error) {
+	tmpl, err := template.New("message").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// MustTemplate parses a message template or panics.
+func MustTemplate(text string) *Template {
+	t, err := NewTemplate(text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Render executes the template for event.
+func (t *Template) Render(event Event) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, event); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// DefaultTemplateText holds the built-in message template for each event
+// type. Event types without an entry keep their original message.
+var DefaultTemplateText = map[EventType]string{
+	EventRunStarted:    `Run {{.RunID}} started{{with .FlowID}} ({{.}}){{end}}`,
+	EventRunCompleted:  `{{.Message}}{{with .Metadata.prUrl}} - {{.}}{{end}}`,
+	EventRunFailed:     `Run {{.RunID}} failed: {{.Message}}`,
+	EventNodeStarted:   `Node {{.NodeID}} started`,
+	EventNodeCompleted: `Node {{.NodeID}} completed`,
+	EventNodeFailed:    `Node {{.NodeID}} failed: {{.Message}}`,
+	EventReviewNeeded:  `Review needed for run {{.RunID}}{{with .Metadata.prUrl}}: {{.}}{{end}}`,
+	EventPRCreated:     `Pull request created{{with .Metadata.prUrl}}: {{.}}{{end}}`,
+}
+
+// TemplateSet maps event types to message templates.
+type TemplateSet struct {
+	mu       sync.RWMutex
+	byType   map[EventType]*Template
+	fallback *Template
+}
+
+// NewTemplateSet creates an empty template set. Events without a template
+// keep their original message.
+func NewTemplateSet() *TemplateSet {
+	return &TemplateSet{byType: make(map[EventType]*Template)}
+}
+
+// DefaultTemplates returns a template set with DefaultTemplateText loaded.
+func DefaultTemplates() *TemplateSet {
+	s := NewTemplateSet()
+	for et, text := range DefaultTemplateText {
+		s.byType[et] = MustTemplate(text)
+	}
+	return s
+}
+
+// Set parses text as the template for an event type.
+func (s *TemplateSet) Set(et EventType, text string) error {
+	t, err := NewTemplate(text)
+	if err != nil {
+		return fmt.Errorf("%s: %w", et, err)
+	}
+	s.mu.Lock()
+	s.byType[et] = t
+	s.mu.Unlock()
+	return nil
+}
+
+// SetFallback parses text as the template for event types without their own.
+func (s *TemplateSet) SetFallback(text string) error {
+	t, err := NewTemplate(text)
+	if err != nil {
+		return fmt.Errorf("fallback: %w", err)
+	}
+	s.mu.Lock()
+	s.fallback = t
+	s.mu.Unlock()
+	return nil
+}
+
+// Render formats the message for event. Without a matching template the
+// event's own message is returned.
+func (s *TemplateSet) Render(event Event) (string, error) {
+	s.mu.RLock()
+	t, ok := s.byType[event.Type]
+	if !ok {
+		t = s.fallback
+	}
+	s.mu.RUnlock()
+
+	if t == nil {
+		return event.Message, nil
+	}
+	return t.Render(event)
+}
+
+// =============================================================================
+// TemplateNotifier
+// =============================================================================
+
+// TemplateNotifier formats event messages with a TemplateSet before passing
+// events to the wrapped notifier.
+type TemplateNotifier struct {
+	Notifier  Notifier
+	Templates *TemplateSet
+}
+
+// NewTemplateNotifier wraps inner so messages are rendered from templates.
+// If templates is nil, DefaultTemplates is used.
+func NewTemplateNotifier(inner Notifier, templates *TemplateSet) *TemplateNotifier {
+	if templates == nil {
+		templates = DefaultTemplates()
+	}
+	return &TemplateNotifier{Notifier: inner, Templates: templates}
+}
+
+// Notify implements Notifier. If a template fails to render, the event is
+// sent with its original message.
+func (n *TemplateNotifier) Notify(ctx context.Context, event Event) error {
+	msg, err := n.Templates.Render(event)
+	if err != nil {
+		slog.Warn("notification template failed, using original message",
+			"error", err,
+			"event_type", event.Type,
+		)
+	} else {
+		event.Message = msg
+	}
+	return n.Notifier.Notify(ctx, event)
+}
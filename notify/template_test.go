@@ -0,0 +1,103 @@
+package notify
+
+import (
+	"context"
+	"testing"
+)
+
+// =============================================================================
+// Template Tests
+// =============================================================================
+
+func TestTemplate_Render(t *testing.T) {
+	type state struct{ Branch string }
+
+	tmpl := MustTemplate(`{{.RunID | upper}} on {{.State.Branch}}: {{.Metadata.cost}} {{.Metadata.missing | default "n/a"}}`)
+	got, err := tmpl.Render(Event{
+		RunID:    "run-1",
+		Metadata: map[string]any{"cost": 1.5},
+		State:    state{Branch: "feature/auth"},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := "RUN-1 on feature/auth: 1.5 n/a"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestNewTemplate_ParseError(t *testing.T) {
+	if _, err := NewTemplate("{{.RunID"); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestDefaultTemplates(t *testing.T) {
+	set := DefaultTemplates()
+
+	tests := []struct {
+		event Event
+		want  string
+	}{
+		{Event{Type: EventRunStarted, RunID: "run-1", FlowID: "ticket-to-pr"}, "Run run-1 started (ticket-to-pr)"},
+		{Event{Type: EventRunFailed, RunID: "run-1", Message: "boom"}, "Run run-1 failed: boom"},
+		{Event{Type: EventRunCompleted, Message: "done", Metadata: map[string]any{"prUrl": "https://pr/1"}}, "done - https://pr/1"},
+		{Event{Type: EventRunCompleted, Message: "done"}, "done"},
+		{Event{Type: EventTurnRecorded, Message: "turn 3"}, "turn 3"}, // No template: unchanged
+	}
+
+	for _, tt := range tests {
+		got, err := set.Render(tt.event)
+		if err != nil {
+			t.Errorf("Render(%s) error = %v", tt.event.Type, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Render(%s) = %q, want %q", tt.event.Type, got, tt.want)
+		}
+	}
+}
+
+func TestTemplateSet_Fallback(t *testing.T) {
+	set := NewTemplateSet()
+	if err := set.Set(EventPRCreated, "PR: {{.Metadata.prUrl}}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := set.SetFallback("[{{.Type}}] {{.Message}}"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := set.Render(Event{Type: EventPRCreated, Metadata: map[string]any{"prUrl": "u"}})
+	if got != "PR: u" {
+		t.Errorf("Render(pr_created) = %q", got)
+	}
+	got, _ = set.Render(Event{Type: EventNodeStarted, Message: "m"})
+	if got != "[node_started] m" {
+		t.Errorf("Render(fallback) = %q", got)
+	}
+}
+
+func TestTemplateNotifier(t *testing.T) {
+	var got Event
+	inner := NotifierFunc(func(_ context.Context, event Event) error {
+		got = event
+		return nil
+	})
+
+	set := NewTemplateSet()
+	_ = set.Set(EventRunFailed, "{{.State.Missing}}") // Fails: State is nil
+	_ = set.Set(EventRunCompleted, "Finished {{.RunID}}")
+	n := NewTemplateNotifier(inner, set)
+
+	_ = n.Notify(context.Background(), Event{Type: EventRunCompleted, RunID: "run-1", Message: "orig"})
+	if got.Message != "Finished run-1" {
+		t.Errorf("Message = %q, want rendered", got.Message)
+	}
+
+	_ = n.Notify(context.Background(), Event{Type: EventRunFailed, Message: "orig"})
+	if got.Message != "orig" {
+		t.Errorf("Message = %q, want original on render error", got.Message)
+	}
+}
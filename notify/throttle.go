@@ -0,0 +1,163 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// ThrottleNotifier
+// =============================================================================
+
+// ErrRateLimited indicates a notification was dropped by the rate limit.
+var ErrRateLimited = errors.New("notification rate limited")
+
+// ThrottleConfig configures ThrottleNotifier.
+type ThrottleConfig struct {
+	// DedupWindow suppresses repeats of an event (same run, type, and node)
+	// within this window. Zero disables deduplication.
+	DedupWindow time.Duration
+
+	// PerMinute caps notifications per minute per channel. Zero disables
+	// rate limiting.
+	PerMinute int
+
+	// ChannelKey groups events into rate-limit channels (default: one
+	// channel for the wrapped notifier). Use it with Slack flow channels.
+	ChannelKey func(Event) string
+}
+
+// ThrottleStats counts events dropped by ThrottleNotifier.
+type ThrottleStats struct {
+	Duplicates  int64
+	RateLimited int64
+}
+
+// ThrottleNotifier drops duplicate events and caps the notification rate,
+// protecting webhooks from retry storms.
+type ThrottleNotifier struct {
+	inner  Notifier
+	config ThrottleConfig
+	now    func() time.Time
+
+	mu        sync.Mutex
+	seen      map[string]time.Time // dedup key -> last sent
+	lastPrune time.Time
+	buckets   map[string]*tokenBucket
+	stats     ThrottleStats
+}
+
+// tokenBucket allows PerMinute events per minute with bursts up to PerMinute
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewThrottleNotifier wraps inner with deduplication and rate limiting.
+func NewThrottleNotifier(inner Notifier, config ThrottleConfig) *ThrottleNotifier {
+	return &ThrottleNotifier{
+		inner:   inner,
+		config:  config,
+		now:     time.Now,
+		seen:    make(map[string]time.Time),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// Notify implements Notifier. Duplicates are dropped silently; events over
+// the rate limit return ErrRateLimited so a QueueNotifier in front can
+// retry them later.
+func (n *ThrottleNotifier) Notify(ctx context.Context, event Event) error {
+	n.mu.Lock()
+	now := n.now()
+
+	if n.isDuplicate(event, now) {
+		n.stats.Duplicates++
+		n.mu.Unlock()
+		return nil
+	}
+	if !n.allow(event, now) {
+		n.stats.RateLimited++
+		n.mu.Unlock()
+		return ErrRateLimited
+	}
+	n.mu.Unlock()
+
+	if err := n.inner.Notify(ctx, event); err != nil {
+		// Not delivered: let a retry through the dedup check
+		n.mu.Lock()
+		delete(n.seen, dedupKey(event))
+		n.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Stats returns a snapshot of drop counters.
+func (n *ThrottleNotifier) Stats() ThrottleStats {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.stats
+}
+
+// isDuplicate records event and reports whether it repeats one sent within
+// the dedup window. Caller holds n.mu.
+func (n *ThrottleNotifier) isDuplicate(event Event, now time.Time) bool {
+	window := n.config.DedupWindow
+	if window <= 0 {
+		return false
+	}
+
+	// Forget old entries at most once per window
+	if now.Sub(n.lastPrune) > window {
+		for k, t := range n.seen {
+			if now.Sub(t) >= window {
+				delete(n.seen, k)
+			}
+		}
+		n.lastPrune = now
+	}
+
+	key := dedupKey(event)
+	if last, ok := n.seen[key]; ok && now.Sub(last) < window {
+		return true
+	}
+	n.seen[key] = now
+	return false
+}
+
+// allow takes a token from the event's channel bucket. Caller holds n.mu.
+func (n *ThrottleNotifier) allow(event Event, now time.Time) bool {
+	limit := float64(n.config.PerMinute)
+	if limit <= 0 {
+		return true
+	}
+
+	channel := ""
+	if n.config.ChannelKey != nil {
+		channel = n.config.ChannelKey(event)
+	}
+
+	b, ok := n.buckets[channel]
+	if !ok {
+		b = &tokenBucket{tokens: limit, last: now}
+		n.buckets[channel] = b
+	}
+
+	b.tokens = min(limit, b.tokens+now.Sub(b.last).Minutes()*limit)
+	b.last = now
+	if b.tokens < 1 {
+		// Not sent: don't let the dedup entry suppress a retry
+		delete(n.seen, dedupKey(event))
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// dedupKey identifies repeats of the same event
+func dedupKey(event Event) string {
+	return event.RunID + "\x00" + string(event.Type) + "\x00" + event.NodeID
+}
@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// ThrottleNotifier Tests
+// =============================================================================
+
+func TestThrottleNotifier_Dedup(t *testing.T) {
+	var calls []string
+	n := NewThrottleNotifier(&mockNotifier{name: "slack", calls: &calls}, ThrottleConfig{
+		DedupWindow: time.Minute,
+	})
+	now := time.Unix(1700000000, 0)
+	n.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	n.Notify(ctx, Event{Type: EventRunFailed, RunID: "run-1"})
+	n.Notify(ctx, Event{Type: EventRunFailed, RunID: "run-1"})    // Duplicate
+	n.Notify(ctx, Event{Type: EventRunFailed, RunID: "run-2"})    // Different run
+	n.Notify(ctx, Event{Type: EventRunCompleted, RunID: "run-1"}) // Different type
+	now = now.Add(2 * time.Minute)
+	n.Notify(ctx, Event{Type: EventRunFailed, RunID: "run-1"}) // Window passed
+
+	if len(calls) != 4 {
+		t.Errorf("delivered %d events, want 4", len(calls))
+	}
+	if got := n.Stats().Duplicates; got != 1 {
+		t.Errorf("Duplicates = %d, want 1", got)
+	}
+}
+
+func TestThrottleNotifier_DedupAllowsRetryAfterFailure(t *testing.T) {
+	inner := &flakyNotifier{failures: 1}
+	n := NewThrottleNotifier(inner, ThrottleConfig{DedupWindow: time.Minute})
+
+	event := Event{Type: EventRunFailed, RunID: "run-1"}
+	if err := n.Notify(context.Background(), event); err == nil {
+		t.Fatal("expected first delivery to fail")
+	}
+	if err := n.Notify(context.Background(), event); err != nil {
+		t.Fatalf("retry error = %v", err)
+	}
+	if len(inner.events) != 1 {
+		t.Errorf("delivered %d events, want 1", len(inner.events))
+	}
+}
+
+func TestThrottleNotifier_RateLimit(t *testing.T) {
+	var calls []string
+	n := NewThrottleNotifier(&mockNotifier{name: "slack", calls: &calls}, ThrottleConfig{
+		PerMinute:  2,
+		ChannelKey: func(e Event) string { return e.FlowID },
+	})
+	now := time.Unix(1700000000, 0)
+	n.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		err := n.Notify(ctx, Event{Type: EventNodeCompleted, FlowID: "a"})
+		if i < 2 && err != nil {
+			t.Errorf("event %d error = %v", i, err)
+		}
+		if i == 2 && !errors.Is(err, ErrRateLimited) {
+			t.Errorf("event %d error = %v, want ErrRateLimited", i, err)
+		}
+	}
+
+	// Separate channel has its own budget
+	if err := n.Notify(ctx, Event{Type: EventNodeCompleted, FlowID: "b"}); err != nil {
+		t.Errorf("other channel error = %v", err)
+	}
+
+	// Half a minute refills one token
+	now = now.Add(30 * time.Second)
+	if err := n.Notify(ctx, Event{Type: EventNodeCompleted, FlowID: "a"}); err != nil {
+		t.Errorf("after refill error = %v", err)
+	}
+
+	if len(calls) != 4 || n.Stats().RateLimited != 1 {
+		t.Errorf("delivered %d, stats %+v", len(calls), n.Stats())
+	}
+}
@@ -3,35 +3,85 @@ package notify
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
+
+	devhttp "github.com/randalmurphal/devflow/http"
 )
 
 // =============================================================================
 // WebhookNotifier
 // =============================================================================
 
+// SignatureHeader carries the HMAC-SHA256 signature of a webhook body as
+// "sha256=<hex>".
+const SignatureHeader = "X-Devflow-Signature-256"
+
+// PayloadFunc converts an event into a request body and content type,
+// letting WebhookNotifier speak third-party formats.
+type PayloadFunc func(event Event) (body []byte, contentType string, err error)
+
 // WebhookNotifier sends notifications to a generic HTTP webhook.
 type WebhookNotifier struct {
 	URL     string
 	Headers map[string]string
 	Client  *http.Client
+
+	// Secret signs each body with HMAC-SHA256 in SignatureHeader.
+	Secret string
+
+	// Payload builds the request body (default: the event as JSON).
+	Payload PayloadFunc
+}
+
+// WebhookOption configures WebhookNotifier.
+type WebhookOption func(*WebhookNotifier)
+
+// WithWebhookSecret signs request bodies with HMAC-SHA256.
+func WithWebhookSecret(secret string) WebhookOption {
+	return func(n *WebhookNotifier) { n.Secret = secret }
+}
+
+// WithWebhookHeader sets a request header.
+func WithWebhookHeader(key, value string) WebhookOption {
+	return func(n *WebhookNotifier) {
+		if n.Headers == nil {
+			n.Headers = make(map[string]string)
+		}
+		n.Headers[key] = value
+	}
+}
+
+// WithWebhookPayload sets the payload transformer, e.g. CloudEventsPayload.
+func WithWebhookPayload(fn PayloadFunc) WebhookOption {
+	return func(n *WebhookNotifier) { n.Payload = fn }
 }
 
 // NewWebhookNotifier creates a webhook notifier.
-func NewWebhookNotifier(url string, headers map[string]string) *WebhookNotifier {
-	return &WebhookNotifier{
+func NewWebhookNotifier(url string, headers map[string]string, opts ...WebhookOption) *WebhookNotifier {
+	n := &WebhookNotifier{
 		URL:     url,
 		Headers: headers,
-		Client:  &http.Client{Timeout: 10 * time.Second},
+		Client:  devhttp.WithRetry(&http.Client{Timeout: 10 * time.Second}, devhttp.DefaultRetryPolicy()),
+	}
+	for _, opt := range opts {
+		opt(n)
 	}
+	return n
 }
 
 // Notify implements Notifier.
 func (n *WebhookNotifier) Notify(ctx context.Context, event Event) error {
-	body, err := json.Marshal(event)
+	payload := n.Payload
+	if payload == nil {
+		payload = JSONPayload
+	}
+	body, contentType, err := payload(event)
 	if err != nil {
 		return fmt.Errorf("marshal event: %w", err)
 	}
@@ -41,10 +91,13 @@ func (n *WebhookNotifier) Notify(ctx context.Context, event Event) error {
 		return fmt.Errorf("create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 	for k, v := range n.Headers {
 		req.Header.Set(k, v)
 	}
+	if n.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(n.Secret, body))
+	}
 
 	resp, err := n.Client.Do(req)
 	if err != nil {
@@ -58,3 +111,57 @@ func (n *WebhookNotifier) Notify(ctx context.Context, event Event) error {
 
 	return nil
 }
+
+// Sign returns the SignatureHeader value for body: "sha256=<hex hmac>".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature (a SignatureHeader value) is
+// valid for body. Receivers should use it instead of comparing strings.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// JSONPayload encodes the event as JSON. It is the default payload.
+func JSONPayload(event Event) ([]byte, string, error) {
+	body, err := json.Marshal(event)
+	return body, "application/json", err
+}
+
+// CloudEventsPayload encodes events as structured-mode CloudEvents 1.0 with
+// type "dev.devflow.<event type>" and the event as data.
+func CloudEventsPayload(source string) PayloadFunc {
+	return func(event Event) ([]byte, string, error) {
+		ts := event.Timestamp
+		if ts.IsZero() {
+			ts = time.Now()
+		}
+		ce := cloudEvent{
+			SpecVersion:     "1.0",
+			ID:              fmt.Sprintf("%s-%s-%d", event.RunID, event.Type, ts.UnixNano()),
+			Source:          source,
+			Type:            "dev.devflow." + string(event.Type),
+			Subject:         event.RunID,
+			Time:            ts.UTC().Format(time.RFC3339Nano),
+			DataContentType: "application/json",
+			Data:            event,
+		}
+		body, err := json.Marshal(ce)
+		return body, "application/cloudevents+json", err
+	}
+}
+
+// cloudEvent is a CloudEvents 1.0 structured-mode envelope
+type cloudEvent struct {
+	SpecVersion     string `json:"specversion"`
+	ID              string `json:"id"`
+	Source          string `json:"source"`
+	Type            string `json:"type"`
+	Subject         string `json:"subject,omitempty"`
+	Time            string `json:"time"`
+	DataContentType string `json:"datacontenttype"`
+	Data            Event  `json:"data"`
+}
@@ -0,0 +1,102 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/randalmurphal/devflow/artifact"
+)
+
+// ErrAttachmentsUnsupported indicates the provider cannot upload attachments.
+var ErrAttachmentsUnsupported = errors.New("provider does not support attachments")
+
+// Attachment is a file published alongside a pull request.
+type Attachment struct {
+	Name    string // File name, e.g. "coverage.txt"
+	Content []byte
+}
+
+// AttachmentUploader is implemented by providers that can publish files
+// outside the repository: GitHub as a secret gist, GitLab as a private
+// project snippet.
+type AttachmentUploader interface {
+	// UploadAttachments publishes files under title and returns their web URL.
+	UploadAttachments(ctx context.Context, title string, files []Attachment) (string, error)
+}
+
+// Markers delimiting the artifacts section in a PR body.
+const (
+	attachmentsStart = "<!-- devflow:attachments -->"
+	attachmentsEnd   = "<!-- /devflow:attachments -->"
+)
+
+// AttachArtifacts uploads files through the provider and links them from
+// the PR body, so reviewers see them without access to .devflow. Calling it
+// again replaces the previous link. Returns the upload URL.
+func AttachArtifacts(ctx context.Context, p Provider, id int, title string, files []Attachment) (string, error) {
+	uploader, ok := p.(AttachmentUploader)
+	if !ok {
+		return "", ErrAttachmentsUnsupported
+	}
+	if len(files) == 0 {
+		return "", errors.New("no attachments to upload")
+	}
+
+	url, err := uploader.UploadAttachments(ctx, title, files)
+	if err != nil {
+		return "", fmt.Errorf("upload attachments: %w", err)
+	}
+
+	pull, err := p.GetPR(ctx, id)
+	if err != nil {
+		return url, fmt.Errorf("get PR: %w", err)
+	}
+
+	body := WithAttachmentsSection(pull.Body, url, files)
+	if _, err := p.UpdatePR(ctx, id, UpdateOptions{Body: &body}); err != nil {
+		return url, fmt.Errorf("update PR body: %w", err)
+	}
+	return url, nil
+}
+
+// WithAttachmentsSection returns body with an artifacts section linking to
+// url, replacing any section added earlier.
+func WithAttachmentsSection(body, url string, files []Attachment) string {
+	var sb strings.Builder
+	sb.WriteString(attachmentsStart + "\n")
+	sb.WriteString("## Artifacts\n\n")
+	for _, f := range files {
+		sb.WriteString(fmt.Sprintf("- [%s](%s)\n", f.Name, url))
+	}
+	sb.WriteString(attachmentsEnd)
+	section := sb.String()
+
+	start := strings.Index(body, attachmentsStart)
+	end := strings.Index(body, attachmentsEnd)
+	if start >= 0 && end > start {
+		return body[:start] + section + body[end+len(attachmentsEnd):]
+	}
+
+	body = strings.TrimRight(body, "\n")
+	if body == "" {
+		return section
+	}
+	return body + "\n\n" + section
+}
+
+// AttachmentsFromRun loads the named artifacts of a run as attachments,
+// e.g. artifact.ArtifactReview or "coverage.txt".
+func AttachmentsFromRun(mgr *artifact.Manager, runID string, names ...string) ([]Attachment, error) {
+	files := make([]Attachment, 0, len(names))
+	for _, name := range names {
+		data, err := mgr.LoadArtifact(runID, name)
+		if err != nil {
+			return nil, fmt.Errorf("load artifact %s: %w", name, err)
+		}
+		// Gists and snippets do not allow directories in file names
+		files = append(files, Attachment{Name: strings.ReplaceAll(name, "/", "-"), Content: data})
+	}
+	return files, nil
+}
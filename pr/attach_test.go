@@ -0,0 +1,95 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/randalmurphal/devflow/artifact"
+)
+
+// providerOnly hides MockProvider's optional interfaces.
+type providerOnly struct{ Provider }
+
+func TestAttachArtifacts(t *testing.T) {
+	var updatedBody string
+	mock := &MockProvider{
+		GetPRFunc: func(ctx context.Context, id int) (*PullRequest, error) {
+			return &PullRequest{ID: id, Body: "Implements auth"}, nil
+		},
+		UpdatePRFunc: func(ctx context.Context, id int, opts UpdateOptions) (*PullRequest, error) {
+			updatedBody = *opts.Body
+			return &PullRequest{ID: id, Body: *opts.Body}, nil
+		},
+		UploadAttachmentsFunc: func(ctx context.Context, title string, files []Attachment) (string, error) {
+			if title != "run-1 artifacts" {
+				t.Errorf("title = %q", title)
+			}
+			if len(files) != 2 {
+				t.Errorf("got %d files, want 2", len(files))
+			}
+			return "https://gist.example.com/abc", nil
+		},
+	}
+
+	url, err := AttachArtifacts(context.Background(), mock, 42, "run-1 artifacts", []Attachment{
+		{Name: "review.json", Content: []byte("{}")},
+		{Name: "coverage.txt", Content: []byte("80%")},
+	})
+	if err != nil {
+		t.Fatalf("AttachArtifacts() error = %v", err)
+	}
+	if url != "https://gist.example.com/abc" {
+		t.Errorf("url = %q", url)
+	}
+	if !strings.HasPrefix(updatedBody, "Implements auth\n\n") {
+		t.Errorf("original body not preserved: %q", updatedBody)
+	}
+	if !strings.Contains(updatedBody, "- [coverage.txt](https://gist.example.com/abc)") {
+		t.Errorf("body missing link: %q", updatedBody)
+	}
+}
+
+func TestAttachArtifacts_Unsupported(t *testing.T) {
+	_, err := AttachArtifacts(context.Background(), providerOnly{&MockProvider{}}, 1, "t",
+		[]Attachment{{Name: "a.txt"}})
+	if !errors.Is(err, ErrAttachmentsUnsupported) {
+		t.Errorf("error = %v, want ErrAttachmentsUnsupported", err)
+	}
+}
+
+func TestWithAttachmentsSection_Replaces(t *testing.T) {
+	body := WithAttachmentsSection("Summary", "https://old", []Attachment{{Name: "old.txt"}})
+	body += "\n\nFooter"
+	body = WithAttachmentsSection(body, "https://new", []Attachment{{Name: "new.txt"}})
+
+	if strings.Contains(body, "https://old") {
+		t.Errorf("old section not replaced: %q", body)
+	}
+	if strings.Count(body, attachmentsStart) != 1 {
+		t.Errorf("expected one section: %q", body)
+	}
+	if !strings.HasPrefix(body, "Summary\n\n") || !strings.HasSuffix(body, "Footer") {
+		t.Errorf("surrounding text changed: %q", body)
+	}
+}
+
+func TestAttachmentsFromRun(t *testing.T) {
+	mgr := artifact.NewManager(artifact.Config{BaseDir: t.TempDir()})
+	if err := mgr.SaveArtifact("run-1", "tool-output/out.txt", []byte("output")); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := AttachmentsFromRun(mgr, "run-1", "tool-output/out.txt")
+	if err != nil {
+		t.Fatalf("AttachmentsFromRun() error = %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "tool-output-out.txt" || string(files[0].Content) != "output" {
+		t.Errorf("files = %+v", files)
+	}
+
+	if _, err := AttachmentsFromRun(mgr, "run-1", "missing.txt"); !errors.Is(err, artifact.ErrArtifactNotFound) {
+		t.Errorf("error = %v, want ErrArtifactNotFound", err)
+	}
+}
@@ -5,6 +5,7 @@
 //   - Options: Configuration for creating a pull request
 //   - PullRequest: Represents a created pull request with URL and number
 //   - Builder: Fluent builder for constructing PR descriptions
+//   - Attachment: Artifact uploaded as a gist/snippet and linked from the PR
 //
 // Implementations:
 //   - GitHubProvider: GitHub PR provider using go-github
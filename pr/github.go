@@ -2,13 +2,17 @@ package pr
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strings"
 
 	"github.com/google/go-github/v57/github"
 	"golang.org/x/oauth2"
+
+	devhttp "github.com/randalmurphal/devflow/http"
 )
 
 // GitHubProvider implements Provider for GitHub repositories.
@@ -30,7 +34,8 @@ func NewGitHubProvider(token, owner, repo string) (*GitHubProvider, error) {
 	}
 
 	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(context.Background(), ts)
+	tc := devhttp.WithRetry(oauth2.NewClient(context.Background(), ts), devhttp.DefaultRetryPolicy())
+	tc = devhttp.WithCache(tc, nil) // 304s don't count against the rate limit
 	client := github.NewClient(tc)
 
 	return &GitHubProvider{
@@ -72,14 +77,14 @@ func (p *GitHubProvider) CreatePR(ctx context.Context, opts Options) (*PullReque
 		if resp != nil && resp.StatusCode == http.StatusUnprocessableEntity {
 			// Check if PR already exists
 			if strings.Contains(err.Error(), "A pull request already exists") {
-				return nil, ErrExists
+				return nil, fmt.Errorf("%w: %w", ErrExists, githubError(resp, err))
 			}
 			// Check if no changes
 			if strings.Contains(err.Error(), "No commits between") {
-				return nil, ErrNoChanges
+				return nil, fmt.Errorf("%w: %w", ErrNoChanges, githubError(resp, err))
 			}
 		}
-		return nil, fmt.Errorf("create PR: %w", err)
+		return nil, fmt.Errorf("create PR: %w", githubError(resp, err))
 	}
 
 	// Add labels if specified
@@ -118,9 +123,9 @@ func (p *GitHubProvider) GetPR(ctx context.Context, id int) (*PullRequest, error
 	pr, resp, err := p.client.PullRequests.Get(ctx, p.owner, p.repo, id)
 	if err != nil {
 		if resp != nil && resp.StatusCode == http.StatusNotFound {
-			return nil, ErrNotFound
+			return nil, fmt.Errorf("%w: %w", ErrNotFound, githubError(resp, err))
 		}
-		return nil, fmt.Errorf("get PR: %w", err)
+		return nil, fmt.Errorf("get PR: %w", githubError(resp, err))
 	}
 	return p.prFromGitHub(pr), nil
 }
@@ -139,9 +144,9 @@ func (p *GitHubProvider) UpdatePR(ctx context.Context, id int, opts UpdateOption
 		update.Base = &github.PullRequestBranch{Ref: opts.Base}
 	}
 
-	pr, _, err := p.client.PullRequests.Edit(ctx, p.owner, p.repo, id, update)
+	pr, resp, err := p.client.PullRequests.Edit(ctx, p.owner, p.repo, id, update)
 	if err != nil {
-		return nil, fmt.Errorf("update PR: %w", err)
+		return nil, fmt.Errorf("update PR: %w", githubError(resp, err))
 	}
 
 	// Update labels if specified
@@ -180,6 +185,38 @@ func (p *GitHubProvider) UpdatePR(ctx context.Context, id int, opts UpdateOption
 	return p.prFromGitHub(pr), nil
 }
 
+// UploadAttachments publishes files as a secret gist and returns its URL.
+func (p *GitHubProvider) UploadAttachments(ctx context.Context, title string, files []Attachment) (string, error) {
+	gistFiles := make(map[github.GistFilename]github.GistFile, len(files))
+	for _, f := range files {
+		gistFiles[github.GistFilename(f.Name)] = github.GistFile{Content: github.String(string(f.Content))}
+	}
+
+	gist, resp, err := p.client.Gists.Create(ctx, &github.Gist{
+		Description: github.String(title),
+		Public:      github.Bool(false),
+		Files:       gistFiles,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create gist: %w", githubError(resp, err))
+	}
+	return gist.GetHTMLURL(), nil
+}
+
+// SetCommitStatus sets a commit status on sha.
+func (p *GitHubProvider) SetCommitStatus(ctx context.Context, sha string, status CommitStatus) error {
+	_, resp, err := p.client.Repositories.CreateStatus(ctx, p.owner, p.repo, sha, &github.RepoStatus{
+		State:       github.String(string(status.State)),
+		Context:     github.String(status.Context),
+		Description: github.String(truncateDescription(status.Description)),
+		TargetURL:   stringOrNil(status.TargetURL),
+	})
+	if err != nil {
+		return fmt.Errorf("set commit status: %w", githubError(resp, err))
+	}
+	return nil
+}
+
 // MergePR merges a pull request.
 func (p *GitHubProvider) MergePR(ctx context.Context, id int, opts MergeOptions) error {
 	mergeOpts := &github.PullRequestOptions{
@@ -201,14 +238,14 @@ func (p *GitHubProvider) MergePR(ctx context.Context, id int, opts MergeOptions)
 		if resp != nil {
 			switch resp.StatusCode {
 			case http.StatusNotFound:
-				return ErrNotFound
+				return fmt.Errorf("%w: %w", ErrNotFound, githubError(resp, err))
 			case http.StatusMethodNotAllowed:
-				return ErrClosed
+				return fmt.Errorf("%w: %w", ErrClosed, githubError(resp, err))
 			case http.StatusConflict:
-				return ErrMergeConflict
+				return fmt.Errorf("%w: %w", ErrMergeConflict, githubError(resp, err))
 			}
 		}
-		return fmt.Errorf("merge PR: %w", err)
+		return fmt.Errorf("merge PR: %w", githubError(resp, err))
 	}
 
 	// Delete branch if requested
@@ -228,20 +265,20 @@ func (p *GitHubProvider) MergePR(ctx context.Context, id int, opts MergeOptions)
 
 // AddComment adds a comment to a pull request.
 func (p *GitHubProvider) AddComment(ctx context.Context, id int, body string) error {
-	_, _, err := p.client.Issues.CreateComment(ctx, p.owner, p.repo, id,
+	_, resp, err := p.client.Issues.CreateComment(ctx, p.owner, p.repo, id,
 		&github.IssueComment{Body: github.String(body)})
 	if err != nil {
-		return fmt.Errorf("add comment: %w", err)
+		return fmt.Errorf("add comment: %w", githubError(resp, err))
 	}
 	return nil
 }
 
 // RequestReview requests review from the specified users.
 func (p *GitHubProvider) RequestReview(ctx context.Context, id int, reviewers []string) error {
-	_, _, err := p.client.PullRequests.RequestReviewers(ctx, p.owner, p.repo, id,
+	_, resp, err := p.client.PullRequests.RequestReviewers(ctx, p.owner, p.repo, id,
 		github.ReviewersRequest{Reviewers: reviewers})
 	if err != nil {
-		return fmt.Errorf("request review: %w", err)
+		return fmt.Errorf("request review: %w", githubError(resp, err))
 	}
 	return nil
 }
@@ -273,9 +310,9 @@ func (p *GitHubProvider) ListPRs(ctx context.Context, filter Filter) ([]*PullReq
 		opts.PerPage = filter.Limit
 	}
 
-	prs, _, err := p.client.PullRequests.List(ctx, p.owner, p.repo, opts)
+	prs, resp, err := p.client.PullRequests.List(ctx, p.owner, p.repo, opts)
 	if err != nil {
-		return nil, fmt.Errorf("list PRs: %w", err)
+		return nil, fmt.Errorf("list PRs: %w", githubError(resp, err))
 	}
 
 	result := make([]*PullRequest, len(prs))
@@ -315,6 +352,7 @@ func (p *GitHubProvider) prFromGitHub(pr *github.PullRequest) *PullRequest {
 	// Branches
 	if pr.Head != nil {
 		result.Head = pr.Head.GetRef()
+		result.HeadSHA = pr.Head.GetSHA()
 	}
 	if pr.Base != nil {
 		result.Base = pr.Base.GetRef()
@@ -352,3 +390,31 @@ func (p *GitHubProvider) prFromGitHub(pr *github.PullRequest) *PullRequest {
 
 	return result
 }
+
+// stringOrNil returns nil for an empty string
+func stringOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// githubError wraps a go-github error in a devhttp.APIError, so callers can
+// match devhttp sentinels such as devhttp.ErrNotFound for any provider.
+// GitHub reports rate limits as 403, so those wrap devhttp.ErrRateLimited.
+func githubError(resp *github.Response, err error) error {
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &rateErr) || errors.As(err, &abuseErr) {
+		return fmt.Errorf("%w: %w", devhttp.ErrRateLimited, err)
+	}
+	if resp == nil {
+		return err
+	}
+	wrapped := devhttp.WrapError("github", resp.Response, err)
+	// go-github re-populates the body after decoding its ErrorResponse
+	if apiErr, ok := wrapped.(*devhttp.APIError); ok && resp.Body != nil {
+		apiErr.Body, _ = io.ReadAll(io.LimitReader(resp.Body, devhttp.MaxErrorBodyBytes))
+	}
+	return wrapped
+}
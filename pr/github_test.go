@@ -0,0 +1,37 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	devhttp "github.com/randalmurphal/devflow/http"
+)
+
+func TestGitHubProvider_Errors(t *testing.T) {
+	const body = `{"message":"Validation Failed","errors":[{"resource":"IssueComment","code":"custom","message":"body is too long"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	provider, err := NewGitHubProvider("token", "owner", "repo")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider: %v", err)
+	}
+	provider.client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	err = provider.AddComment(context.Background(), 7, "hi")
+	var apiErr *devhttp.APIError
+	if !errors.As(err, &apiErr) || apiErr.Service != "github" || !errors.Is(err, devhttp.ErrBadRequest) {
+		t.Fatalf("AddComment err = %#v, want github APIError", err)
+	}
+	if string(apiErr.Body) != body {
+		t.Errorf("APIError.Body = %q, want the response body", apiErr.Body)
+	}
+}
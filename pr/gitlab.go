@@ -2,12 +2,15 @@ package pr
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/xanzy/go-gitlab"
+
+	devhttp "github.com/randalmurphal/devflow/http"
 )
 
 // GitLabProvider implements Provider for GitLab repositories.
@@ -16,11 +19,37 @@ type GitLabProvider struct {
 	projectID string // Can be numeric ID or "namespace/project"
 }
 
+// GitLabOption configures NewGitLabProvider.
+type GitLabOption func(*gitlabOptions)
+
+// gitlabOptions collects GitLabOption settings
+type gitlabOptions struct {
+	httpClient *http.Client
+	transport  devhttp.TransportConfig
+}
+
+// WithGitLabHTTPClient sets the HTTP client used for API calls.
+func WithGitLabHTTPClient(client *http.Client) GitLabOption {
+	return func(o *gitlabOptions) { o.httpClient = client }
+}
+
+// WithGitLabTLS sets client certificates and CAs for a self-hosted GitLab
+// behind internal PKI. It is ignored when WithGitLabHTTPClient is also set.
+func WithGitLabTLS(cfg devhttp.TLSConfig) GitLabOption {
+	return func(o *gitlabOptions) { o.transport.TLS = cfg }
+}
+
+// WithGitLabProxy routes GitLab traffic through a proxy other than the
+// environment's. It is ignored when WithGitLabHTTPClient is also set.
+func WithGitLabProxy(cfg devhttp.ProxyConfig) GitLabOption {
+	return func(o *gitlabOptions) { o.transport.Proxy = cfg }
+}
+
 // NewGitLabProvider creates a new GitLab provider.
 // token is a personal access token.
 // baseURL is the GitLab instance URL (empty for gitlab.com).
 // projectID can be numeric ID or "namespace/project" path.
-func NewGitLabProvider(token, baseURL, projectID string) (*GitLabProvider, error) {
+func NewGitLabProvider(token, baseURL, projectID string, opts ...GitLabOption) (*GitLabProvider, error) {
 	if token == "" {
 		return nil, fmt.Errorf("GitLab token is required")
 	}
@@ -28,15 +57,27 @@ func NewGitLabProvider(token, baseURL, projectID string) (*GitLabProvider, error
 		return nil, fmt.Errorf("project ID is required")
 	}
 
-	var client *gitlab.Client
-	var err error
+	var options gitlabOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 
+	var clientOpts []gitlab.ClientOptionFunc
 	if baseURL != "" {
-		client, err = gitlab.NewClient(token, gitlab.WithBaseURL(baseURL))
-	} else {
-		client, err = gitlab.NewClient(token)
+		clientOpts = append(clientOpts, gitlab.WithBaseURL(baseURL))
+	}
+	if options.httpClient == nil && options.transport != (devhttp.TransportConfig{}) {
+		transport, err := devhttp.NewTransport(options.transport)
+		if err != nil {
+			return nil, fmt.Errorf("GitLab transport: %w", err)
+		}
+		options.httpClient = &http.Client{Transport: transport, Timeout: devhttp.DefaultTimeout}
+	}
+	if options.httpClient != nil {
+		clientOpts = append(clientOpts, gitlab.WithHTTPClient(options.httpClient))
 	}
 
+	client, err := gitlab.NewClient(token, clientOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("create GitLab client: %w", err)
 	}
@@ -49,7 +90,7 @@ func NewGitLabProvider(token, baseURL, projectID string) (*GitLabProvider, error
 
 // NewGitLabProviderFromURL creates a GitLab provider from a remote URL.
 // Example: "https://gitlab.com/namespace/project.git"
-func NewGitLabProviderFromURL(token, remoteURL string) (*GitLabProvider, error) {
+func NewGitLabProviderFromURL(token, remoteURL string, opts ...GitLabOption) (*GitLabProvider, error) {
 	owner, repo, err := ParseRepoFromURL(remoteURL)
 	if err != nil {
 		return nil, fmt.Errorf("parse remote URL: %w", err)
@@ -68,7 +109,7 @@ func NewGitLabProviderFromURL(token, remoteURL string) (*GitLabProvider, error)
 	}
 
 	projectID := owner + "/" + repo
-	return NewGitLabProvider(token, baseURL, projectID)
+	return NewGitLabProvider(token, baseURL, projectID, opts...)
 }
 
 // CreatePR creates a new merge request.
@@ -131,14 +172,14 @@ func (p *GitLabProvider) CreatePR(ctx context.Context, opts Options) (*PullReque
 	mr, resp, err := p.client.MergeRequests.CreateMergeRequest(p.projectID, mrOpts)
 	if err != nil {
 		if resp != nil && resp.StatusCode == http.StatusConflict {
-			return nil, ErrExists
+			return nil, fmt.Errorf("%w: %w", ErrExists, gitlabError(resp, err))
 		}
 		if resp != nil && resp.StatusCode == http.StatusBadRequest {
 			if strings.Contains(err.Error(), "No commits between") {
-				return nil, ErrNoChanges
+				return nil, fmt.Errorf("%w: %w", ErrNoChanges, gitlabError(resp, err))
 			}
 		}
-		return nil, fmt.Errorf("create MR: %w", err)
+		return nil, fmt.Errorf("create MR: %w", gitlabError(resp, err))
 	}
 
 	return p.prFromGitLab(mr), nil
@@ -149,9 +190,9 @@ func (p *GitLabProvider) GetPR(ctx context.Context, id int) (*PullRequest, error
 	mr, resp, err := p.client.MergeRequests.GetMergeRequest(p.projectID, id, nil)
 	if err != nil {
 		if resp != nil && resp.StatusCode == http.StatusNotFound {
-			return nil, ErrNotFound
+			return nil, fmt.Errorf("%w: %w", ErrNotFound, gitlabError(resp, err))
 		}
-		return nil, fmt.Errorf("get MR: %w", err)
+		return nil, fmt.Errorf("get MR: %w", gitlabError(resp, err))
 	}
 	return p.prFromGitLab(mr), nil
 }
@@ -173,9 +214,9 @@ func (p *GitLabProvider) UpdatePR(ctx context.Context, id int, opts UpdateOption
 		updateOpts.Labels = gitlab.Ptr(gitlab.LabelOptions(opts.Labels))
 	}
 
-	mr, _, err := p.client.MergeRequests.UpdateMergeRequest(p.projectID, id, updateOpts)
+	mr, resp, err := p.client.MergeRequests.UpdateMergeRequest(p.projectID, id, updateOpts)
 	if err != nil {
-		return nil, fmt.Errorf("update MR: %w", err)
+		return nil, fmt.Errorf("update MR: %w", gitlabError(resp, err))
 	}
 
 	return p.prFromGitLab(mr), nil
@@ -212,14 +253,14 @@ func (p *GitLabProvider) MergePR(ctx context.Context, id int, opts MergeOptions)
 		if resp != nil {
 			switch resp.StatusCode {
 			case http.StatusNotFound:
-				return ErrNotFound
+				return fmt.Errorf("%w: %w", ErrNotFound, gitlabError(resp, err))
 			case http.StatusMethodNotAllowed:
-				return ErrClosed
+				return fmt.Errorf("%w: %w", ErrClosed, gitlabError(resp, err))
 			case http.StatusNotAcceptable:
-				return ErrMergeConflict
+				return fmt.Errorf("%w: %w", ErrMergeConflict, gitlabError(resp, err))
 			}
 		}
-		return fmt.Errorf("merge MR: %w", err)
+		return fmt.Errorf("merge MR: %w", gitlabError(resp, err))
 	}
 
 	return nil
@@ -227,10 +268,10 @@ func (p *GitLabProvider) MergePR(ctx context.Context, id int, opts MergeOptions)
 
 // AddComment adds a note to a merge request.
 func (p *GitLabProvider) AddComment(ctx context.Context, id int, body string) error {
-	_, _, err := p.client.Notes.CreateMergeRequestNote(p.projectID, id,
+	_, resp, err := p.client.Notes.CreateMergeRequestNote(p.projectID, id,
 		&gitlab.CreateMergeRequestNoteOptions{Body: gitlab.Ptr(body)})
 	if err != nil {
-		return fmt.Errorf("add comment: %w", err)
+		return fmt.Errorf("add comment: %w", gitlabError(resp, err))
 	}
 	return nil
 }
@@ -249,14 +290,66 @@ func (p *GitLabProvider) RequestReview(ctx context.Context, id int, reviewers []
 		return fmt.Errorf("no valid reviewer IDs provided")
 	}
 
-	_, _, err := p.client.MergeRequests.UpdateMergeRequest(p.projectID, id,
+	_, resp, err := p.client.MergeRequests.UpdateMergeRequest(p.projectID, id,
 		&gitlab.UpdateMergeRequestOptions{ReviewerIDs: gitlab.Ptr(reviewerIDs)})
 	if err != nil {
-		return fmt.Errorf("request review: %w", err)
+		return fmt.Errorf("request review: %w", gitlabError(resp, err))
 	}
 	return nil
 }
 
+// UploadAttachments publishes files as a private project snippet and
+// returns its URL.
+func (p *GitLabProvider) UploadAttachments(ctx context.Context, title string, files []Attachment) (string, error) {
+	snippetFiles := make([]*gitlab.CreateSnippetFileOptions, 0, len(files))
+	for _, f := range files {
+		snippetFiles = append(snippetFiles, &gitlab.CreateSnippetFileOptions{
+			FilePath: gitlab.Ptr(f.Name),
+			Content:  gitlab.Ptr(string(f.Content)),
+		})
+	}
+
+	snippet, resp, err := p.client.ProjectSnippets.CreateSnippet(p.projectID, &gitlab.CreateProjectSnippetOptions{
+		Title:      gitlab.Ptr(title),
+		Visibility: gitlab.Ptr(gitlab.PrivateVisibility),
+		Files:      &snippetFiles,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("create snippet: %w", gitlabError(resp, err))
+	}
+	return snippet.WebURL, nil
+}
+
+// SetCommitStatus sets a commit status on sha.
+func (p *GitLabProvider) SetCommitStatus(ctx context.Context, sha string, status CommitStatus) error {
+	opts := &gitlab.SetCommitStatusOptions{
+		State:       gitLabBuildState(status.State),
+		Name:        gitlab.Ptr(status.Context),
+		Description: gitlab.Ptr(truncateDescription(status.Description)),
+	}
+	if status.TargetURL != "" {
+		opts.TargetURL = gitlab.Ptr(status.TargetURL)
+	}
+
+	_, resp, err := p.client.Commits.SetCommitStatus(p.projectID, sha, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("set commit status: %w", gitlabError(resp, err))
+	}
+	return nil
+}
+
+// gitLabBuildState maps a commit state to GitLab's build states
+func gitLabBuildState(state CommitState) gitlab.BuildStateValue {
+	switch state {
+	case CommitStateSuccess:
+		return gitlab.Success
+	case CommitStateFailure, CommitStateError:
+		return gitlab.Failed
+	default:
+		return gitlab.Running
+	}
+}
+
 // ListPRs lists merge requests matching the filter.
 func (p *GitLabProvider) ListPRs(ctx context.Context, filter Filter) ([]*PullRequest, error) {
 	opts := &gitlab.ListProjectMergeRequestsOptions{
@@ -288,9 +381,9 @@ func (p *GitLabProvider) ListPRs(ctx context.Context, filter Filter) ([]*PullReq
 		opts.PerPage = filter.Limit
 	}
 
-	mrs, _, err := p.client.MergeRequests.ListProjectMergeRequests(p.projectID, opts)
+	mrs, resp, err := p.client.MergeRequests.ListProjectMergeRequests(p.projectID, opts)
 	if err != nil {
-		return nil, fmt.Errorf("list MRs: %w", err)
+		return nil, fmt.Errorf("list MRs: %w", gitlabError(resp, err))
 	}
 
 	result := make([]*PullRequest, len(mrs))
@@ -309,6 +402,7 @@ func (p *GitLabProvider) prFromGitLab(mr *gitlab.MergeRequest) *PullRequest {
 		Title:   mr.Title,
 		Body:    mr.Description,
 		Head:    mr.SourceBranch,
+		HeadSHA: mr.SHA,
 		Base:    mr.TargetBranch,
 	}
 
@@ -366,3 +460,17 @@ func (p *GitLabProvider) prFromGitLab(mr *gitlab.MergeRequest) *PullRequest {
 
 	return result
 }
+
+// gitlabError wraps a go-gitlab error in a devhttp.APIError, so callers can
+// match devhttp sentinels such as devhttp.ErrNotFound for any provider.
+func gitlabError(resp *gitlab.Response, err error) error {
+	if resp == nil {
+		return err
+	}
+	wrapped := devhttp.WrapError("gitlab", resp.Response, err)
+	var errResp *gitlab.ErrorResponse
+	if apiErr, ok := wrapped.(*devhttp.APIError); ok && errors.As(err, &errResp) {
+		apiErr.Body = errResp.Body[:min(len(errResp.Body), devhttp.MaxErrorBodyBytes)]
+	}
+	return wrapped
+}
@@ -0,0 +1,48 @@
+package pr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	devhttp "github.com/randalmurphal/devflow/http"
+)
+
+func TestGitLabProvider_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"404 Not found"}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"403 Forbidden"}`))
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewGitLabProvider("token", server.URL, "group/project")
+	if err != nil {
+		t.Fatalf("NewGitLabProvider: %v", err)
+	}
+
+	_, err = provider.GetPR(context.Background(), 7)
+	if !errors.Is(err, ErrNotFound) || !errors.Is(err, devhttp.ErrNotFound) {
+		t.Errorf("GetPR err = %v, want ErrNotFound and devhttp.ErrNotFound", err)
+	}
+
+	err = provider.AddComment(context.Background(), 7, "hi")
+	if !devhttp.IsForbidden(err) {
+		t.Errorf("AddComment err = %v, want devhttp.ErrForbidden", err)
+	}
+	var apiErr *devhttp.APIError
+	if !errors.As(err, &apiErr) || apiErr.Service != "gitlab" || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("AddComment err = %#v, want gitlab APIError", err)
+	}
+	if string(apiErr.Body) != `{"message":"403 Forbidden"}` {
+		t.Errorf("APIError.Body = %q, want the response body", apiErr.Body)
+	}
+}
@@ -11,6 +11,9 @@ type MockProvider struct {
 	AddCommentFunc    func(ctx context.Context, id int, body string) error
 	RequestReviewFunc func(ctx context.Context, id int, reviewers []string) error
 	ListPRsFunc       func(ctx context.Context, filter Filter) ([]*PullRequest, error)
+
+	UploadAttachmentsFunc func(ctx context.Context, title string, files []Attachment) (string, error)
+	SetCommitStatusFunc   func(ctx context.Context, sha string, status CommitStatus) error
 }
 
 // CreatePR implements Provider.
@@ -68,3 +71,19 @@ func (m *MockProvider) ListPRs(ctx context.Context, filter Filter) ([]*PullReque
 	}
 	return []*PullRequest{}, nil
 }
+
+// UploadAttachments implements AttachmentUploader.
+func (m *MockProvider) UploadAttachments(ctx context.Context, title string, files []Attachment) (string, error) {
+	if m.UploadAttachmentsFunc != nil {
+		return m.UploadAttachmentsFunc(ctx, title, files)
+	}
+	return "https://example.com/gist/1", nil
+}
+
+// SetCommitStatus implements StatusSetter.
+func (m *MockProvider) SetCommitStatus(ctx context.Context, sha string, status CommitStatus) error {
+	if m.SetCommitStatusFunc != nil {
+		return m.SetCommitStatusFunc(ctx, sha, status)
+	}
+	return nil
+}
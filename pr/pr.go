@@ -105,6 +105,7 @@ type PullRequest struct {
 	State        State      // Current state
 	Draft        bool       // Whether it's a draft
 	Head         string     // Source branch
+	HeadSHA      string     // Head commit SHA
 	Base         string     // Target branch
 	CreatedAt    time.Time  // Creation time
 	UpdatedAt    time.Time  // Last update time
@@ -0,0 +1,116 @@
+package pr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/randalmurphal/devflow/notify"
+)
+
+// CommitState is the state of a commit status.
+type CommitState string
+
+const (
+	CommitStatePending CommitState = "pending"
+	CommitStateSuccess CommitState = "success"
+	CommitStateFailure CommitState = "failure"
+	CommitStateError   CommitState = "error"
+)
+
+// CommitStatus is a status shown on a commit in the PR UI.
+type CommitStatus struct {
+	State       CommitState
+	Context     string // Status name, e.g. "devflow/review"
+	Description string // Short summary (GitHub truncates at 140 characters)
+	TargetURL   string // Link for details (optional)
+}
+
+// StatusSetter is implemented by providers that can set commit statuses.
+type StatusSetter interface {
+	SetCommitStatus(ctx context.Context, sha string, status CommitStatus) error
+}
+
+// maxStatusDescription is GitHub's limit for status descriptions.
+const maxStatusDescription = 140
+
+// truncateDescription shortens a status description to the provider limit.
+func truncateDescription(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxStatusDescription {
+		return s
+	}
+	return string(runes[:maxStatusDescription-3]) + "..."
+}
+
+// StatusNotifier reports workflow events as commit statuses on the PR's
+// head commit, e.g. "devflow/review: passed". It implements notify.Notifier.
+//
+// The commit is read from the event's "headSha" metadata (set by
+// workflow.NotifyNode once a PR exists); events without one are skipped.
+type StatusNotifier struct {
+	Setter StatusSetter
+	Prefix string // Context prefix (default: "devflow")
+
+	// TargetURL is a fmt pattern taking the run ID, used as the status
+	// link (optional), e.g. "https://devflow.example.com/runs/%s".
+	TargetURL string
+}
+
+// NewStatusNotifier creates a commit-status notifier. GitHubProvider and
+// GitLabProvider implement StatusSetter.
+func NewStatusNotifier(setter StatusSetter) *StatusNotifier {
+	return &StatusNotifier{Setter: setter, Prefix: "devflow"}
+}
+
+// Notify implements notify.Notifier.
+func (n *StatusNotifier) Notify(ctx context.Context, event notify.Event) error {
+	sha, _ := event.Metadata["headSha"].(string)
+	if sha == "" {
+		slog.Debug("skipping commit status: no head SHA",
+			"event_type", event.Type,
+			"run_id", event.RunID)
+		return nil
+	}
+
+	status, ok := n.statusForEvent(event)
+	if !ok {
+		return nil
+	}
+	if n.TargetURL != "" && event.RunID != "" {
+		status.TargetURL = fmt.Sprintf(n.TargetURL, event.RunID)
+	}
+
+	return n.Setter.SetCommitStatus(ctx, sha, status)
+}
+
+// statusForEvent maps an event to a commit status. Node events report under
+// "<prefix>/<node>", run events under the prefix alone.
+func (n *StatusNotifier) statusForEvent(event notify.Event) (CommitStatus, bool) {
+	prefix := n.Prefix
+	if prefix == "" {
+		prefix = "devflow"
+	}
+
+	status := CommitStatus{Context: prefix}
+	if event.NodeID != "" {
+		status.Context = prefix + "/" + event.NodeID
+	}
+
+	switch event.Type {
+	case notify.EventRunStarted, notify.EventNodeStarted:
+		status.State, status.Description = CommitStatePending, "running"
+	case notify.EventReviewNeeded:
+		status.State, status.Description = CommitStatePending, "review needed"
+	case notify.EventRunCompleted, notify.EventNodeCompleted:
+		status.State, status.Description = CommitStateSuccess, "passed"
+	case notify.EventRunFailed, notify.EventNodeFailed:
+		status.State, status.Description = CommitStateFailure, "failed"
+		if event.Message != "" {
+			status.Description = "failed: " + event.Message
+		}
+	default:
+		return CommitStatus{}, false
+	}
+	return status, true
+}
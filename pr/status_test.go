@@ -0,0 +1,88 @@
+package pr
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/randalmurphal/devflow/notify"
+)
+
+func TestStatusNotifier(t *testing.T) {
+	var gotSHA string
+	var got CommitStatus
+	mock := &MockProvider{
+		SetCommitStatusFunc: func(ctx context.Context, sha string, status CommitStatus) error {
+			gotSHA, got = sha, status
+			return nil
+		},
+	}
+	n := NewStatusNotifier(mock)
+	n.TargetURL = "https://devflow.example.com/runs/%s"
+
+	tests := []struct {
+		event       notify.Event
+		wantContext string
+		wantState   CommitState
+		wantDesc    string
+	}{
+		{notify.Event{Type: notify.EventNodeStarted, NodeID: "review"}, "devflow/review", CommitStatePending, "running"},
+		{notify.Event{Type: notify.EventNodeCompleted, NodeID: "review"}, "devflow/review", CommitStateSuccess, "passed"},
+		{notify.Event{Type: notify.EventNodeFailed, NodeID: "test", Message: "3 tests failed"}, "devflow/test", CommitStateFailure, "failed: 3 tests failed"},
+		{notify.Event{Type: notify.EventRunCompleted}, "devflow", CommitStateSuccess, "passed"},
+	}
+
+	for _, tt := range tests {
+		tt.event.RunID = "run-1"
+		tt.event.Metadata = map[string]any{"headSha": "abc123"}
+		if err := n.Notify(context.Background(), tt.event); err != nil {
+			t.Fatalf("Notify(%s) error = %v", tt.event.Type, err)
+		}
+		if gotSHA != "abc123" {
+			t.Errorf("sha = %q", gotSHA)
+		}
+		if got.Context != tt.wantContext || got.State != tt.wantState || got.Description != tt.wantDesc {
+			t.Errorf("Notify(%s) status = %+v", tt.event.Type, got)
+		}
+		if got.TargetURL != "https://devflow.example.com/runs/run-1" {
+			t.Errorf("TargetURL = %q", got.TargetURL)
+		}
+	}
+}
+
+func TestStatusNotifier_Skips(t *testing.T) {
+	calls := 0
+	mock := &MockProvider{
+		SetCommitStatusFunc: func(ctx context.Context, sha string, status CommitStatus) error {
+			calls++
+			return nil
+		},
+	}
+	n := NewStatusNotifier(mock)
+
+	// No head SHA yet (PR not created)
+	n.Notify(context.Background(), notify.Event{Type: notify.EventRunStarted})
+	// Event type without a status mapping
+	n.Notify(context.Background(), notify.Event{
+		Type:     notify.EventTurnRecorded,
+		Metadata: map[string]any{"headSha": "abc123"},
+	})
+
+	if calls != 0 {
+		t.Errorf("SetCommitStatus called %d times, want 0", calls)
+	}
+}
+
+func TestTruncateDescription(t *testing.T) {
+	long := strings.Repeat("é", 200)
+	got := truncateDescription(long)
+	if n := len([]rune(got)); n != maxStatusDescription {
+		t.Errorf("len = %d runes, want %d", n, maxStatusDescription)
+	}
+	if !strings.HasSuffix(got, "...") {
+		t.Errorf("missing ellipsis: %q", got)
+	}
+	if truncateDescription("short") != "short" {
+		t.Error("short description changed")
+	}
+}
@@ -1,18 +1,33 @@
 // Package prompt provides prompt template loading and management.
 //
 // Core types:
-//   - Loader: Loads prompt templates from files or embedded resources
-//   - Template: A loaded prompt template with variable substitution
+//   - Loader: Loads prompt templates from layered sources: embedded defaults,
+//     then ~/.config/devflow/prompts, then the repository's .devflow/prompts
+//   - Source: Which layer a template came from (Loader.Source)
+//   - Metadata: YAML frontmatter with version, model hint, temperature,
+//     required variables, and a max_tokens budget enforced by truncating
+//     designated variables
+//   - Resolved: The variant, version, and content hash a prompt resolved to
+//   - VariantStrategy: Deterministic or weighted A/B variant selection per run
+//   - TemplateReport: Dry-render result of Loader.ValidateAll, listing parse
+//     errors, missing partials, unknown placeholders, and oversized output
+//   - Builder: Constructs prompts programmatically
+//
+// Templates use text/template with helpers such as truncateTokens,
+// codeblock, join, and date; legacy ${var} placeholders still render.
+// Templates can include shared partials ({{template "code-standards" .}})
+// and extend a base layout from the layouts/ directory. Loader.WithPins
+// pins prompts to older versions kept under versions/<name>/, and
+// Loader.Watch reloads edited templates in long-running services.
+// NewLoader detects the project language (DetectLanguage) and prefers
+// languages/<lang>/ templates, such as the built-in Python, TypeScript,
+// and Java implement and review prompts.
 //
 // Example usage:
 //
-//	loader := prompt.NewLoader(prompt.Config{
-//	    TemplateDir: ".devflow/prompts",
-//	    EmbedFS:     embeddedPrompts,
-//	})
-//	tmpl, err := loader.Load("generate-spec")
-//	result := tmpl.Execute(map[string]string{
-//	    "ticket": "TK-421",
-//	    "title":  "Add authentication",
+//	loader := prompt.NewLoader(projectDir)
+//	text, err := loader.LoadWithVars("generate-spec", map[string]any{
+//	    "Title":       "Add authentication",
+//	    "Description": "Implement OAuth2 flow",
 //	})
 package prompt
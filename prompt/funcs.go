@@ -0,0 +1,94 @@
+package prompt
+
+import (
+	"fmt"
+	"regexp"
+	"text/template"
+
+	"github.com/randalmurphal/llmkit/tokens"
+	"github.com/randalmurphal/llmkit/truncate"
+)
+
+// legacyVar matches ${name} placeholders from plain-substitution prompts
+var legacyVar = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandLegacyVars rewrites ${name} placeholders as template actions, so
+// older prompts keep working. Unset variables render as empty strings.
+func expandLegacyVars(body string) string {
+	return legacyVar.ReplaceAllString(body, `{{default "" .$1}}`)
+}
+
+// SetTokenCounter sets the counter used by the token helpers
+// (truncateTokens, countTokens). The default estimates ~4 characters per
+// token.
+func (l *Loader) SetTokenCounter(counter tokens.Counter) {
+	l.counter = counter
+	l.ClearCache()
+}
+
+// tokenFuncs returns the template helpers bound to the loader's counter
+func (l *Loader) tokenFuncs() template.FuncMap {
+	counter := l.counter
+	return template.FuncMap{
+		// {{.Diff | truncateTokens 2000}}
+		"truncateTokens": func(limit int, text string) string {
+			return truncateText(counter, text, limit)
+		},
+		"countTokens": counter.Count,
+	}
+}
+
+// truncateText shortens text to limit tokens, keeping its beginning
+func truncateText(counter tokens.Counter, text string, limit int) string {
+	truncated, _ := truncate.NewFromEnd().
+		WithCounter(counter).
+		WithSuffix("\n[... truncated ...]").
+		Truncate(text, limit)
+	return truncated
+}
+
+// render executes a prompt and enforces its max_tokens budget. Over budget,
+// the variables named in its truncate list are shortened in order, and the
+// prompt re-rendered, until it fits; if it still does not fit,
+// ErrPromptTooLarge is returned. vars is not modified.
+func (l *Loader) render(p *parsedPrompt, vars map[string]any) (string, error) {
+	text, err := p.execute(vars)
+	if err != nil || p.meta.MaxTokens <= 0 {
+		return text, err
+	}
+
+	size := l.counter.Count(text)
+	if size <= p.meta.MaxTokens {
+		return text, nil
+	}
+
+	trimmed := make(map[string]any, len(vars))
+	for k, v := range vars {
+		trimmed[k] = v
+	}
+	for _, name := range p.meta.Truncate {
+		value, ok := trimmed[name].(string)
+		if !ok {
+			continue
+		}
+		for size > p.meta.MaxTokens && value != "" {
+			current := l.counter.Count(value)
+			shorter := truncateText(l.counter, value, max(current-(size-p.meta.MaxTokens), 0))
+			if shorter == value || l.counter.Count(shorter) >= current {
+				shorter = "" // Truncation cannot make progress; drop the value
+			}
+			value = shorter
+			trimmed[name] = value
+
+			if text, err = p.execute(trimmed); err != nil {
+				return "", err
+			}
+			size = l.counter.Count(text)
+		}
+		if size <= p.meta.MaxTokens {
+			return text, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: renders to %d tokens (max %d)", ErrPromptTooLarge, size, p.meta.MaxTokens)
+}
@@ -0,0 +1,86 @@
+package prompt
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// languagesDir holds language-specific templates: languages/<lang>/<name>.txt
+const languagesDir = "languages"
+
+// Project languages recognized by DetectLanguage.
+const (
+	LanguageGo         = "go"
+	LanguagePython     = "python"
+	LanguageTypeScript = "typescript"
+	LanguageJavaScript = "javascript"
+	LanguageJava       = "java"
+)
+
+// languageMarkers maps root files to the language they indicate, in
+// detection order
+var languageMarkers = []struct {
+	file     string
+	language string
+}{
+	{"go.mod", LanguageGo},
+	{"tsconfig.json", LanguageTypeScript},
+	{"pyproject.toml", LanguagePython},
+	{"setup.py", LanguagePython},
+	{"setup.cfg", LanguagePython},
+	{"requirements.txt", LanguagePython},
+	{"Pipfile", LanguagePython},
+	{"pom.xml", LanguageJava},
+	{"build.gradle", LanguageJava},
+	{"build.gradle.kts", LanguageJava},
+	{"package.json", LanguageJavaScript}, // Refined to TypeScript below
+}
+
+// DetectLanguage guesses a project's primary language from marker files in
+// its root directory (go.mod, pyproject.toml, tsconfig.json, pom.xml, ...).
+// A package.json that depends on typescript counts as TypeScript. It returns
+// "" when no marker is found.
+func DetectLanguage(projectDir string) string {
+	for _, m := range languageMarkers {
+		path := filepath.Join(projectDir, m.file)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if m.language == LanguageJavaScript && usesTypeScript(path) {
+			return LanguageTypeScript
+		}
+		return m.language
+	}
+	return ""
+}
+
+// usesTypeScript reports whether a package.json depends on typescript
+func usesTypeScript(packageJSON string) bool {
+	data, err := os.ReadFile(packageJSON)
+	if err != nil {
+		return false
+	}
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return false
+	}
+	_, dep := pkg.Dependencies["typescript"]
+	_, devDep := pkg.DevDependencies["typescript"]
+	return dep || devDep
+}
+
+// SetLanguage selects language-specific templates (languages/<lang>/) ahead
+// of generic ones. NewLoader sets it from DetectLanguage; "" disables.
+func (l *Loader) SetLanguage(language string) {
+	l.language = language
+	l.ClearCache()
+}
+
+// Language returns the language used to select templates.
+func (l *Loader) Language() string {
+	return l.language
+}
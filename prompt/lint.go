@@ -0,0 +1,275 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"text/template"
+	"text/template/parse"
+)
+
+// DefaultMaxPromptTokens is the rendered size above which ValidateAll
+// reports a template as oversized.
+const DefaultMaxPromptTokens = 8000
+
+// IssueKind classifies a problem found by ValidateAll.
+type IssueKind string
+
+const (
+	IssueParse           IssueKind = "parse"            // Template, layout, or frontmatter fails to parse
+	IssueMissingPartial  IssueKind = "missing-partial"  // {{template "x"}} names no partial or block
+	IssueUnknownVariable IssueKind = "unknown-variable" // Placeholder is neither a sample nor required variable
+	IssueRender          IssueKind = "render"           // Execution with sample variables fails
+	IssueOversized       IssueKind = "oversized"        // Rendered output exceeds MaxTokens
+)
+
+// Issue is one problem in a template.
+type Issue struct {
+	Kind    IssueKind
+	Message string
+}
+
+// TemplateReport is the dry-render result of one template.
+type TemplateReport struct {
+	Name     string
+	Resolved Resolved
+	Rendered string // Output with sample variables; empty if rendering failed
+	Tokens   int
+	Issues   []Issue
+}
+
+// OK reports whether the template has no issues.
+func (r TemplateReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// ValidateOptions configures ValidateAll.
+type ValidateOptions struct {
+	// Vars are the sample variables to render with and the set of known
+	// placeholder names. Defaults to SampleVars(). Variables a template
+	// declares as required are always known and filled in if absent.
+	Vars map[string]any
+
+	// MaxTokens flags rendered output above this size.
+	// Defaults to DefaultMaxPromptTokens. A template's own max_tokens
+	// takes precedence.
+	MaxTokens int
+}
+
+// SampleVars returns sample values for the variables the workflow nodes and
+// default templates use.
+func SampleVars() map[string]any {
+	return map[string]any{
+		"Title":        "Add authentication",
+		"Description":  "Implement the OAuth2 authorization code flow.",
+		"Spec":         "## Overview\nAdd an OAuth2 login handler.",
+		"ProjectName":  "example",
+		"Language":     "Go",
+		"Framework":    "net/http",
+		"Requirements": []string{"Support refresh tokens"},
+		"Guidelines":   []string{"Keep handlers small"},
+	}
+}
+
+// ValidateAll dry-renders every available template with sample variables so
+// broken prompt edits are caught before a run. It reports parse failures,
+// missing partials, unknown placeholders, render errors, and oversized
+// output per template, sorted by name.
+func (l *Loader) ValidateAll(opts ValidateOptions) ([]TemplateReport, error) {
+	if opts.Vars == nil {
+		opts.Vars = SampleVars()
+	}
+	if opts.MaxTokens <= 0 {
+		opts.MaxTokens = DefaultMaxPromptTokens
+	}
+
+	names, err := l.List()
+	if err != nil {
+		return nil, fmt.Errorf("list prompts: %w", err)
+	}
+	slices.Sort(names)
+
+	reports := make([]TemplateReport, 0, len(names))
+	for _, name := range names {
+		reports = append(reports, l.validate(name, opts))
+	}
+	return reports, nil
+}
+
+// validate checks and dry-renders one template
+func (l *Loader) validate(name string, opts ValidateOptions) TemplateReport {
+	report := TemplateReport{Name: name}
+	addIssue := func(kind IssueKind, format string, args ...any) {
+		report.Issues = append(report.Issues, Issue{Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+
+	p, err := l.getTemplate(name)
+	if err != nil {
+		addIssue(IssueParse, "%v", err)
+		return report
+	}
+	report.Resolved = p.resolved
+
+	vars := make(map[string]any, len(opts.Vars)+len(p.meta.Required))
+	for k, v := range opts.Vars {
+		vars[k] = v
+	}
+	for _, req := range p.meta.Required {
+		if _, ok := vars[req]; !ok {
+			vars[req] = "<" + req + ">"
+		}
+	}
+
+	refs := collectRefs(p.tmpl)
+	for _, partial := range refs.templates {
+		if p.tmpl.Lookup(partial) == nil {
+			addIssue(IssueMissingPartial, "template %q is not defined", partial)
+		}
+	}
+	for _, v := range refs.vars {
+		if _, ok := vars[v]; !ok {
+			addIssue(IssueUnknownVariable, "placeholder %q is not a known variable", v)
+		}
+	}
+	if slices.ContainsFunc(report.Issues, func(i Issue) bool { return i.Kind == IssueMissingPartial }) {
+		return report // Rendering would only repeat the failure
+	}
+
+	limit := opts.MaxTokens
+	if p.meta.MaxTokens > 0 {
+		limit = p.meta.MaxTokens
+	}
+
+	rendered, err := l.render(p, vars)
+	if errors.Is(err, ErrPromptTooLarge) {
+		addIssue(IssueOversized, "%v", err)
+		return report
+	}
+	if err != nil {
+		addIssue(IssueRender, "%v", err)
+		return report
+	}
+	report.Rendered = rendered
+	report.Tokens = l.counter.Count(report.Rendered)
+	if report.Tokens > limit {
+		addIssue(IssueOversized, "renders to %d tokens (max %d)", report.Tokens, limit)
+	}
+	return report
+}
+
+// passesDot reports whether a {{template}} pipeline is just "." (or $)
+func passesDot(pipe *parse.PipeNode) bool {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	switch arg := pipe.Cmds[0].Args[0].(type) {
+	case *parse.DotNode:
+		return true
+	case *parse.VariableNode:
+		return len(arg.Ident) == 1 && arg.Ident[0] == "$"
+	}
+	return false
+}
+
+// templateRefs are the names a template tree refers to
+type templateRefs struct {
+	vars      []string // Top-level variables ({{.X}}, {{$.X}})
+	templates []string // {{template "x"}} targets
+}
+
+// collectRefs walks tmpl and the templates it includes, collecting
+// variables read from the top-level data and included template names,
+// deduplicated and sorted
+func collectRefs(tmpl *template.Template) templateRefs {
+	vars := make(map[string]bool)
+	templates := make(map[string]bool)
+
+	// Templates still to walk; rebound when included with a dot other than
+	// the variables map
+	type include struct {
+		name    string
+		rebound bool
+	}
+	queue := []include{{name: tmpl.Name()}}
+	seen := make(map[include]bool)
+
+	var walk func(node parse.Node, rebound bool)
+	walkList := func(list *parse.ListNode, rebound bool) {
+		if list == nil {
+			return
+		}
+		for _, n := range list.Nodes {
+			walk(n, rebound)
+		}
+	}
+	walkPipe := func(pipe *parse.PipeNode, rebound bool) {
+		if pipe == nil {
+			return
+		}
+		for _, cmd := range pipe.Cmds {
+			for _, arg := range cmd.Args {
+				walk(arg, rebound)
+			}
+		}
+	}
+	walk = func(node parse.Node, rebound bool) {
+		switch n := node.(type) {
+		case *parse.FieldNode:
+			// Inside range/with, dot is no longer the variables map
+			if !rebound {
+				vars[n.Ident[0]] = true
+			}
+		case *parse.VariableNode:
+			if n.Ident[0] == "$" && len(n.Ident) > 1 {
+				vars[n.Ident[1]] = true
+			}
+		case *parse.ChainNode:
+			walk(n.Node, rebound)
+		case *parse.PipeNode:
+			walkPipe(n, rebound)
+		case *parse.ActionNode:
+			walkPipe(n.Pipe, rebound)
+		case *parse.IfNode:
+			walkPipe(n.Pipe, rebound)
+			walkList(n.List, rebound)
+			walkList(n.ElseList, rebound)
+		case *parse.RangeNode:
+			walkPipe(n.Pipe, rebound)
+			walkList(n.List, true)
+			walkList(n.ElseList, rebound)
+		case *parse.WithNode:
+			walkPipe(n.Pipe, rebound)
+			walkList(n.List, true)
+			walkList(n.ElseList, rebound)
+		case *parse.TemplateNode:
+			templates[n.Name] = true
+			walkPipe(n.Pipe, rebound)
+			queue = append(queue, include{name: n.Name, rebound: rebound || !passesDot(n.Pipe)})
+		case *parse.ListNode:
+			walkList(n, rebound)
+		}
+	}
+
+	for len(queue) > 0 {
+		inc := queue[0]
+		queue = queue[1:]
+		if seen[inc] {
+			continue
+		}
+		seen[inc] = true
+		if t := tmpl.Lookup(inc.name); t != nil && t.Tree != nil {
+			walkList(t.Tree.Root, inc.rebound)
+		}
+	}
+
+	refs := templateRefs{}
+	for v := range vars {
+		refs.vars = append(refs.vars, v)
+	}
+	for t := range templates {
+		refs.templates = append(refs.templates, t)
+	}
+	slices.Sort(refs.vars)
+	slices.Sort(refs.templates)
+	return refs
+}
@@ -0,0 +1,98 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Prompt errors
+var (
+	// ErrMissingVariables indicates required template variables were not set.
+	ErrMissingVariables = errors.New("missing required prompt variables")
+
+	// ErrInvalidFrontmatter indicates a template's YAML frontmatter is malformed.
+	ErrInvalidFrontmatter = errors.New("invalid prompt frontmatter")
+
+	// ErrVersionNotFound indicates a pinned prompt version does not exist.
+	ErrVersionNotFound = errors.New("prompt version not found")
+
+	// ErrPromptTooLarge indicates a rendered prompt exceeds its max_tokens
+	// budget even after truncating its designated variables.
+	ErrPromptTooLarge = errors.New("prompt exceeds token budget")
+)
+
+// frontmatterDelim opens and closes the YAML frontmatter block
+const frontmatterDelim = "---"
+
+// Metadata is the optional YAML frontmatter at the top of a template:
+//
+//	---
+//	version: "2"
+//	model: sonnet
+//	temperature: 0.2
+//	required: [Title, Spec]
+//	max_tokens: 6000
+//	truncate: [Diff, Spec]
+//	---
+//	You are an expert developer...
+type Metadata struct {
+	Version     string   `yaml:"version,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	Model       string   `yaml:"model,omitempty"`       // Model hint for the node running the prompt
+	Temperature *float64 `yaml:"temperature,omitempty"` // Nil when unset
+	Required    []string `yaml:"required,omitempty"`    // Variables that must be set when rendering
+	Layout      string   `yaml:"layout,omitempty"`      // Base layout whose "content" block the body fills
+	MaxTokens   int      `yaml:"max_tokens,omitempty"`  // Rendered size budget; 0 for none
+	Truncate    []string `yaml:"truncate,omitempty"`    // Variables shortened, in order, to fit MaxTokens
+}
+
+// Missing returns the required variables that vars leaves unset, in
+// declaration order. A variable is unset if absent, nil, or an empty string.
+func (m Metadata) Missing(vars map[string]any) []string {
+	var missing []string
+	for _, name := range m.Required {
+		v, ok := vars[name]
+		if s, isString := v.(string); !ok || v == nil || (isString && s == "") {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// parseFrontmatter splits content into its metadata and template body.
+// Content without frontmatter has empty metadata.
+func parseFrontmatter(content string) (Metadata, string, error) {
+	var meta Metadata
+
+	first, rest, ok := strings.Cut(content, "\n")
+	if !ok || strings.TrimRight(first, "\r") != frontmatterDelim {
+		return meta, content, nil
+	}
+
+	// Find the closing delimiter on its own line
+	offset := 0
+	for {
+		line, next, found := strings.Cut(rest[offset:], "\n")
+		if strings.TrimRight(line, "\r") == frontmatterDelim {
+			header := rest[:offset]
+			body := ""
+			if found {
+				body = next
+			}
+			decoder := yaml.NewDecoder(strings.NewReader(header))
+			decoder.KnownFields(true)
+			if err := decoder.Decode(&meta); err != nil && !errors.Is(err, io.EOF) {
+				return meta, "", fmt.Errorf("%w: %v", ErrInvalidFrontmatter, err)
+			}
+			return meta, body, nil
+		}
+		if !found {
+			return meta, "", fmt.Errorf("%w: no closing %q", ErrInvalidFrontmatter, frontmatterDelim)
+		}
+		offset += len(line) + 1
+	}
+}
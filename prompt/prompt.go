@@ -5,10 +5,16 @@ import (
 	"embed"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
+	"github.com/randalmurphal/llmkit/tokens"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -16,35 +22,66 @@ import (
 // embeddedPrompts holds default prompts embedded in the binary.
 // To populate this, create a prompts/ directory with .txt files.
 //
-//go:embed prompts/*.txt
+//go:embed prompts/*.txt prompts/partials/*.txt prompts/layouts/*.txt prompts/languages/*/*.txt
 var embeddedPrompts embed.FS
 
+// Subdirectories of a prompt directory holding shared templates
+const (
+	partialsDir = "partials" // Included with {{template "name" .}}
+	layoutsDir  = "layouts"  // Selected with "layout:" in frontmatter
+)
+
+// contentBlock is the layout block a template's body fills
+const contentBlock = "content"
+
+// blankLines matches runs of more than one blank line
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
 // Loader loads and renders prompt templates.
 type Loader struct {
-	dirs    []string                      // Directories to search
-	cache   map[string]*template.Template // Cached templates
-	funcMap template.FuncMap              // Template functions
+	dirs     []searchDir              // Directories to search, highest precedence first
+	mu       sync.Mutex               // Guards cache (Watch clears it concurrently)
+	cache    map[string]*parsedPrompt // Cached templates
+	funcMap  template.FuncMap         // Template functions
+	pins     map[string]string        // Pinned versions by prompt name
+	variants VariantStrategy          // Picks among name@variant templates
+	counter  tokens.Counter           // Token counter for token helpers
+	language string                   // Selects languages/<lang>/ templates
+}
+
+// parsedPrompt is a parsed template with its frontmatter
+type parsedPrompt struct {
+	tmpl     *template.Template
+	meta     Metadata
+	resolved Resolved
 }
 
 // NewLoader creates a prompt loader for the given project directory.
 // It searches for prompts in the following order:
 // 1. .devflow/prompts/ in project
 // 2. prompts/ in project
-// 3. Embedded prompts in devflow binary
+// 3. Global prompts in ~/.config/devflow/prompts/
+// 4. Embedded prompts in devflow binary
 func NewLoader(projectDir string) *Loader {
+	dirs := []searchDir{
+		{path: filepath.Join(projectDir, ".devflow", "prompts"), source: SourceLocal},
+		{path: filepath.Join(projectDir, "prompts"), source: SourceLocal},
+	}
+	if global := GlobalPromptDir(); global != "" {
+		dirs = append(dirs, searchDir{path: global, source: SourceGlobal})
+	}
 	return &Loader{
-		dirs: []string{
-			filepath.Join(projectDir, ".devflow", "prompts"),
-			filepath.Join(projectDir, "prompts"),
-		},
-		cache:   make(map[string]*template.Template),
-		funcMap: defaultPromptFuncMap(),
+		dirs:     dirs,
+		cache:    make(map[string]*parsedPrompt),
+		funcMap:  defaultPromptFuncMap(),
+		counter:  tokens.NewEstimatingCounter(),
+		language: DetectLanguage(projectDir),
 	}
 }
 
-// AddSearchDir adds a directory to search for prompts.
+// AddSearchDir adds a directory to search for prompts, ahead of all others.
 func (l *Loader) AddSearchDir(dir string) {
-	l.dirs = append([]string{dir}, l.dirs...)
+	l.dirs = append([]searchDir{{path: dir, source: SourceCustom}}, l.dirs...)
 }
 
 // AddFunc adds a custom template function.
@@ -58,20 +95,49 @@ func (l *Loader) Load(name string) (string, error) {
 }
 
 // LoadWithVars loads and renders a prompt with variable substitution.
+// It fails with ErrMissingVariables if a variable the template's
+// frontmatter declares as required is unset.
 func (l *Loader) LoadWithVars(name string, vars map[string]any) (string, error) {
-	tmpl, err := l.getTemplate(name)
+	p, err := l.getTemplate(name)
 	if err != nil {
 		return "", err
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, vars); err != nil {
+	if missing := p.meta.Missing(vars); len(missing) > 0 {
+		return "", fmt.Errorf("%w: %s needs %s",
+			ErrMissingVariables, name, strings.Join(missing, ", "))
+	}
+
+	text, err := l.render(p, vars)
+	if err != nil {
 		return "", fmt.Errorf("render prompt %s: %w", name, err)
 	}
+	return text, nil
+}
+
+// execute renders the template
+func (p *parsedPrompt) execute(vars map[string]any) (string, error) {
+	var buf bytes.Buffer
+	if err := p.tmpl.Execute(&buf, vars); err != nil {
+		return "", err
+	}
 
+	// Layout blocks that render empty leave their surrounding newlines behind
+	if p.meta.Layout != "" {
+		return blankLines.ReplaceAllString(buf.String(), "\n\n"), nil
+	}
 	return buf.String(), nil
 }
 
+// Metadata returns a prompt's frontmatter (empty if it has none).
+func (l *Loader) Metadata(name string) (Metadata, error) {
+	p, err := l.getTemplate(name)
+	if err != nil {
+		return Metadata{}, err
+	}
+	return p.meta, nil
+}
+
 // Exists checks if a prompt exists.
 func (l *Loader) Exists(name string) bool {
 	_, err := l.loadRaw(name)
@@ -84,7 +150,7 @@ func (l *Loader) List() ([]string, error) {
 
 	// Search directories
 	for _, dir := range l.dirs {
-		entries, err := os.ReadDir(dir)
+		entries, err := os.ReadDir(dir.path)
 		if err != nil {
 			continue
 		}
@@ -115,66 +181,158 @@ func (l *Loader) List() ([]string, error) {
 }
 
 // getTemplate loads and caches a template.
-func (l *Loader) getTemplate(name string) (*template.Template, error) {
-	if tmpl, ok := l.cache[name]; ok {
-		return tmpl, nil
+func (l *Loader) getTemplate(name string) (*parsedPrompt, error) {
+	l.mu.Lock()
+	p, ok := l.cache[name]
+	l.mu.Unlock()
+	if ok {
+		return p, nil
 	}
 
-	content, err := l.loadRaw(name)
+	meta, body, resolved, err := l.loadVersioned(name)
 	if err != nil {
 		return nil, err
 	}
 
-	tmpl, err := template.New(name).Funcs(l.funcMap).Parse(content)
-	if err != nil {
+	tmpl := template.New(name).Funcs(l.tokenFuncs()).Funcs(l.funcMap)
+	if err := l.parsePartials(tmpl); err != nil {
+		return nil, fmt.Errorf("parse prompt template %s: %w", name, err)
+	}
+
+	if meta.Layout != "" {
+		layout, _, err := l.loadFile(layoutsDir, meta.Layout)
+		if err != nil {
+			return nil, fmt.Errorf("prompt %s: %w", name, err)
+		}
+		_, layoutBody, err := parseFrontmatter(layout)
+		if err != nil {
+			return nil, fmt.Errorf("parse prompt layout %s: %w", meta.Layout, err)
+		}
+		if _, err := tmpl.Parse(expandLegacyVars(layoutBody)); err != nil {
+			return nil, fmt.Errorf("parse prompt layout %s: %w", meta.Layout, err)
+		}
+		// The body fills the layout's content block and may override others
+		if _, err := tmpl.New(contentBlock).Parse(expandLegacyVars(body)); err != nil {
+			return nil, fmt.Errorf("parse prompt template %s: %w", name, err)
+		}
+	} else if _, err := tmpl.Parse(expandLegacyVars(body)); err != nil {
 		return nil, fmt.Errorf("parse prompt template %s: %w", name, err)
 	}
 
-	l.cache[name] = tmpl
-	return tmpl, nil
+	p = &parsedPrompt{tmpl: tmpl, meta: meta, resolved: resolved}
+	l.mu.Lock()
+	l.cache[name] = p
+	l.mu.Unlock()
+	return p, nil
 }
 
 // loadRaw loads raw prompt content without parsing.
 func (l *Loader) loadRaw(name string) (string, error) {
-	filename := name + ".txt"
+	content, _, err := l.loadFile("", name)
+	return content, err
+}
+
+// loadFile loads a template from subdir of the first search directory that
+// has it, falling back to the embedded prompts, and reports its layer.
+// Within each layer, a top-level template for the loader's language
+// (languages/<lang>/<name>.txt) is preferred over the generic one.
+func (l *Loader) loadFile(subdir, name string) (string, Source, error) {
+	filenames := []string{path.Join(subdir, name+".txt")}
+	if subdir == "" && l.language != "" {
+		filenames = append([]string{path.Join(languagesDir, l.language, name+".txt")}, filenames...)
+	}
 
 	// Search directories
 	for _, dir := range l.dirs {
-		path := filepath.Join(dir, filename)
-		data, err := os.ReadFile(path)
-		if err == nil {
-			return string(data), nil
+		for _, filename := range filenames {
+			data, err := os.ReadFile(filepath.Join(dir.path, filepath.FromSlash(filename)))
+			if err == nil {
+				return string(data), dir.source, nil
+			}
 		}
 	}
 
 	// Fall back to embedded
-	data, err := embeddedPrompts.ReadFile("prompts/" + filename)
-	if err != nil {
-		return "", fmt.Errorf("prompt not found: %s", name)
+	for _, filename := range filenames {
+		if data, err := embeddedPrompts.ReadFile("prompts/" + filename); err == nil {
+			return string(data), SourceEmbedded, nil
+		}
 	}
+	return "", "", fmt.Errorf("prompt not found: %s", path.Join(subdir, name))
+}
 
-	return string(data), nil
+// parsePartials adds every partial to tmpl as a named template. A partial
+// in a search directory overrides an embedded one of the same name.
+func (l *Loader) parsePartials(tmpl *template.Template) error {
+	partials := make(map[string]string)
+
+	entries, _ := embeddedPrompts.ReadDir("prompts/" + partialsDir)
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".txt"); ok && !entry.IsDir() {
+			data, err := embeddedPrompts.ReadFile("prompts/" + partialsDir + "/" + entry.Name())
+			if err == nil {
+				partials[name] = string(data)
+			}
+		}
+	}
+
+	// Lowest priority first, so earlier search directories win
+	for i := len(l.dirs) - 1; i >= 0; i-- {
+		dir := filepath.Join(l.dirs[i].path, partialsDir)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if name, ok := strings.CutSuffix(entry.Name(), ".txt"); ok && !entry.IsDir() {
+				data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				if err == nil {
+					partials[name] = string(data)
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(partials))
+	for name := range partials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, body, err := parseFrontmatter(partials[name])
+		if err == nil {
+			_, err = tmpl.New(name).Parse(expandLegacyVars(body))
+		}
+		if err != nil {
+			return fmt.Errorf("partial %s: %w", name, err)
+		}
+	}
+	return nil
 }
 
 // ClearCache clears the template cache.
 func (l *Loader) ClearCache() {
-	l.cache = make(map[string]*template.Template)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cache = make(map[string]*parsedPrompt)
 }
 
 // defaultPromptFuncMap returns default template functions.
 func defaultPromptFuncMap() template.FuncMap {
 	return template.FuncMap{
-		"join":     strings.Join,
-		"split":    strings.Split,
-		"trim":     strings.TrimSpace,
-		"upper":    strings.ToUpper,
-		"lower":    strings.ToLower,
-		"title":    cases.Title(language.English).String,
-		"contains": strings.Contains,
-		"replace":  strings.ReplaceAll,
-		"indent":   indentString,
-		"default":  defaultValue,
-		"quote":    quoteString,
+		"join":      strings.Join,
+		"split":     strings.Split,
+		"trim":      strings.TrimSpace,
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"title":     cases.Title(language.English).String,
+		"contains":  strings.Contains,
+		"replace":   strings.ReplaceAll,
+		"indent":    indentString,
+		"default":   defaultValue,
+		"quote":     quoteString,
+		"codeblock": codeBlock,
+		"date":      formatDate,
 	}
 }
 
@@ -209,6 +367,27 @@ func quoteString(s string) string {
 	return fmt.Sprintf("%q", s)
 }
 
+// codeBlock wraps code in a markdown fence tagged with lang, using a fence
+// longer than any backtick run in the code.
+func codeBlock(lang, code string) string {
+	longest, run := 0, 0
+	for _, r := range code {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + lang + "\n" + strings.TrimSuffix(code, "\n") + "\n" + fence
+}
+
+// formatDate formats the current time with a Go layout, e.g. "2006-01-02".
+func formatDate(layout string) string {
+	return time.Now().Format(layout)
+}
+
 // Builder helps construct prompts programmatically.
 type Builder struct {
 	parts []string
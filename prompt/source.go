@@ -0,0 +1,51 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Source indicates where a prompt template came from.
+type Source string
+
+// Prompt source constants, lowest precedence first.
+const (
+	// SourceEmbedded indicates a default template built into devflow.
+	SourceEmbedded Source = "embedded"
+
+	// SourceGlobal indicates the user's global prompt directory
+	// (~/.config/devflow/prompts).
+	SourceGlobal Source = "global"
+
+	// SourceLocal indicates the repository (.devflow/prompts or prompts/).
+	SourceLocal Source = "local"
+
+	// SourceCustom indicates a directory added with AddSearchDir.
+	SourceCustom Source = "custom"
+)
+
+// searchDir is a prompt directory and the layer it belongs to
+type searchDir struct {
+	path   string
+	source Source
+}
+
+// GlobalPromptDir returns the user's global prompt directory,
+// ~/.config/devflow/prompts, or "" if the home directory is unknown.
+func GlobalPromptDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "devflow", "prompts")
+}
+
+// Source returns the layer the current template for name comes from, or ""
+// if no layer has it. Pins are not considered; see Resolved.Source.
+func (l *Loader) Source(name string) Source {
+	_, source, err := l.loadFile("", name)
+	if err != nil {
+		return ""
+	}
+	return source
+}
@@ -0,0 +1,118 @@
+package prompt
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"os"
+	"slices"
+	"strings"
+)
+
+// VariantSep separates a prompt name from its variant: generate-spec@b
+const VariantSep = "@"
+
+// VariantStrategy picks one of a prompt's variants (sorted, non-empty) for a
+// run. It must be deterministic in its inputs so that retries and resumed
+// runs keep the same variant.
+type VariantStrategy func(name, runID string, variants []string) string
+
+// HashVariants spreads runs evenly across variants by hashing the run ID.
+// It is the default strategy.
+func HashVariants() VariantStrategy {
+	return func(name, runID string, variants []string) string {
+		return variants[runHash(name, runID)%uint64(len(variants))]
+	}
+}
+
+// WeightedVariants assigns runs to variants in proportion to their weights,
+// e.g. {"a": 90, "b": 10} for a 10% trial of b. Variants without a weight
+// are never picked; if no variant has one, runs are spread evenly.
+func WeightedVariants(weights map[string]int) VariantStrategy {
+	return func(name, runID string, variants []string) string {
+		total := 0
+		for _, v := range variants {
+			total += max(weights[v], 0)
+		}
+		if total == 0 {
+			return HashVariants()(name, runID, variants)
+		}
+
+		pick := int(runHash(name, runID) % uint64(total))
+		for _, v := range variants {
+			pick -= max(weights[v], 0)
+			if pick < 0 {
+				return v
+			}
+		}
+		return variants[len(variants)-1]
+	}
+}
+
+// runHash maps a prompt and run to a stable number
+func runHash(name, runID string) uint64 {
+	sum := sha256.Sum256([]byte(name + "\x00" + runID))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// SetVariantStrategy sets how Variant picks among a prompt's variants.
+func (l *Loader) SetVariantStrategy(strategy VariantStrategy) {
+	l.variants = strategy
+}
+
+// Variants returns the variant suffixes available for name, e.g. ["a", "b"]
+// for generate-spec@a.txt and generate-spec@b.txt, sorted.
+func (l *Loader) Variants(name string) []string {
+	prefix := name + VariantSep
+	found := make(map[string]bool)
+	collect := func(entries []os.DirEntry) {
+		for _, entry := range entries {
+			file := entry.Name()
+			if entry.IsDir() || !strings.HasPrefix(file, prefix) || !strings.HasSuffix(file, ".txt") {
+				continue
+			}
+			if variant := strings.TrimSuffix(strings.TrimPrefix(file, prefix), ".txt"); variant != "" {
+				found[variant] = true
+			}
+		}
+	}
+
+	for _, dir := range l.dirs {
+		if entries, err := os.ReadDir(dir.path); err == nil {
+			collect(entries)
+		}
+	}
+	if entries, err := embeddedPrompts.ReadDir("prompts"); err == nil {
+		collect(entries)
+	}
+
+	variants := make([]string, 0, len(found))
+	for v := range found {
+		variants = append(variants, v)
+	}
+	slices.Sort(variants)
+	return variants
+}
+
+// Variant returns the template name to load for name in the given run:
+// name@<variant> chosen by the variant strategy, or name itself when it has
+// no variants or is pinned to a version.
+func (l *Loader) Variant(name, runID string) string {
+	if _, pinned := l.pins[name]; pinned {
+		return name
+	}
+	variants := l.Variants(name)
+	if len(variants) == 0 {
+		return name
+	}
+	strategy := l.variants
+	if strategy == nil {
+		strategy = HashVariants()
+	}
+	return name + VariantSep + strategy(name, runID, variants)
+}
+
+// splitVariant splits "generate-spec@b" into "generate-spec" and "b"
+func splitVariant(name string) (string, string) {
+	base, variant, _ := strings.Cut(name, VariantSep)
+	return base, variant
+}
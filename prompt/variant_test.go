@@ -0,0 +1,136 @@
+package prompt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var abVariants = []string{"a", "b"}
+
+// pickCounts runs strategy over n run IDs and counts each variant picked
+func pickCounts(strategy VariantStrategy, variants []string, n int) map[string]int {
+	counts := make(map[string]int)
+	for i := range n {
+		counts[strategy("generate-spec", fmt.Sprintf("run-%d", i), variants)]++
+	}
+	return counts
+}
+
+func TestHashVariants_StablePerRun(t *testing.T) {
+	strategy := HashVariants()
+	for i := range 50 {
+		runID := fmt.Sprintf("run-%d", i)
+		first := strategy("generate-spec", runID, abVariants)
+		for range 3 {
+			if got := strategy("generate-spec", runID, abVariants); got != first {
+				t.Fatalf("%s picked %q then %q", runID, first, got)
+			}
+		}
+	}
+
+	counts := pickCounts(strategy, abVariants, 1000)
+	if counts["a"] < 400 || counts["b"] < 400 {
+		t.Errorf("HashVariants() split = %v, want roughly even", counts)
+	}
+}
+
+func TestWeightedVariants(t *testing.T) {
+	tests := []struct {
+		name     string
+		weights  map[string]int
+		variants []string
+		want     map[string][2]int // Allowed pick range per variant over 2000 runs
+	}{
+		{
+			name:     "follows weights",
+			weights:  map[string]int{"a": 90, "b": 10},
+			variants: abVariants,
+			want:     map[string][2]int{"a": {1700, 1900}, "b": {100, 300}},
+		},
+		{
+			name:     "zero, negative, and missing weights are never picked",
+			weights:  map[string]int{"a": 0, "b": -5, "c": 3},
+			variants: []string{"a", "b", "c", "d"},
+			want:     map[string][2]int{"c": {2000, 2000}},
+		},
+		{
+			name:     "no positive weight spreads evenly",
+			weights:  map[string]int{"a": 0, "b": -1},
+			variants: abVariants,
+			want:     map[string][2]int{"a": {800, 1200}, "b": {800, 1200}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts := pickCounts(WeightedVariants(tt.weights), tt.variants, 2000)
+			for v, n := range counts {
+				bounds, ok := tt.want[v]
+				if !ok {
+					t.Errorf("picked %q %d times, want never", v, n)
+				} else if n < bounds[0] || n > bounds[1] {
+					t.Errorf("picked %q %d times, want %d-%d", v, n, bounds[0], bounds[1])
+				}
+			}
+		})
+	}
+}
+
+func TestLoader_Variant(t *testing.T) {
+	project := t.TempDir()
+	dir := filepath.Join(project, ".devflow", "prompts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"generate-spec@a.txt", "generate-spec@b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("Spec "+name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l := NewLoader(project)
+
+	if got := l.Variants("generate-spec"); strings.Join(got, ",") != "a,b" {
+		t.Errorf("Variants() = %v, want [a b]", got)
+	}
+	if got := l.Variant("implement", "run-1"); got != "implement" {
+		t.Errorf("Variant() without variants = %q, want implement", got)
+	}
+
+	l.SetVariantStrategy(WeightedVariants(map[string]int{"b": 1}))
+	name := l.Variant("generate-spec", "run-1")
+	if name != "generate-spec@b" {
+		t.Fatalf("Variant() = %q, want generate-spec@b", name)
+	}
+	resolved, err := l.Resolve(name)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resolved.Name != "generate-spec" || resolved.Variant != "b" {
+		t.Errorf("Resolve() = %+v, want generate-spec variant b", resolved)
+	}
+
+	pinned := l.WithPins(map[string]string{"generate-spec": "1"})
+	if got := pinned.Variant("generate-spec", "run-1"); got != "generate-spec" {
+		t.Errorf("pinned Variant() = %q, want generate-spec", got)
+	}
+}
+
+func TestSplitVariant(t *testing.T) {
+	tests := []struct {
+		name, base, variant string
+	}{
+		{"generate-spec@b", "generate-spec", "b"},
+		{"generate-spec", "generate-spec", ""},
+		{"implement@", "implement", ""},
+		{"a@b@c", "a", "b@c"},
+	}
+	for _, tt := range tests {
+		base, variant := splitVariant(tt.name)
+		if base != tt.base || variant != tt.variant {
+			t.Errorf("splitVariant(%q) = %q, %q; want %q, %q", tt.name, base, variant, tt.base, tt.variant)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package prompt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"path"
+)
+
+// versionsDir holds older template versions for pinning:
+// versions/<name>/<version>.txt
+const versionsDir = "versions"
+
+// Resolved identifies the exact template a prompt name resolved to, for
+// recording alongside a run.
+type Resolved struct {
+	Name    string `json:"name"`              // Prompt name without variant
+	Variant string `json:"variant,omitempty"` // A/B variant, if any
+	Version string `json:"version,omitempty"` // Frontmatter version (or pin); empty if undeclared
+	Hash    string `json:"hash"`              // SHA-256 of the raw template file
+	Source  Source `json:"source,omitempty"`  // Layer the template came from
+}
+
+// String formats the resolution as "implement@b v2 (3f2a9c1b0d4e)".
+func (r Resolved) String() string {
+	hash := r.Hash
+	if len(hash) > 12 {
+		hash = hash[:12]
+	}
+	name := r.Name
+	if r.Variant != "" {
+		name += VariantSep + r.Variant
+	}
+	if r.Version == "" {
+		return fmt.Sprintf("%s (%s)", name, hash)
+	}
+	return fmt.Sprintf("%s v%s (%s)", name, r.Version, hash)
+}
+
+// WithPins returns a copy of the loader that resolves the named prompts to
+// the given versions, e.g. for one flow while others track the latest.
+// A pinned prompt uses the current template when its frontmatter version
+// matches, and versions/<name>/<version>.txt otherwise.
+func (l *Loader) WithPins(pins map[string]string) *Loader {
+	pinned := &Loader{
+		dirs:     append([]searchDir(nil), l.dirs...),
+		cache:    make(map[string]*parsedPrompt),
+		funcMap:  maps.Clone(l.funcMap),
+		pins:     maps.Clone(l.pins),
+		variants: l.variants,
+		counter:  l.counter,
+		language: l.language,
+	}
+	if pinned.pins == nil {
+		pinned.pins = make(map[string]string, len(pins))
+	}
+	maps.Copy(pinned.pins, pins)
+	return pinned
+}
+
+// Resolve reports which template version Load would use for name.
+func (l *Loader) Resolve(name string) (Resolved, error) {
+	p, err := l.getTemplate(name)
+	if err != nil {
+		return Resolved{}, err
+	}
+	return p.resolved, nil
+}
+
+// loadVersioned loads the raw content for name, honoring its pin
+func (l *Loader) loadVersioned(name string) (Metadata, string, Resolved, error) {
+	content, source, err := l.loadFile("", name)
+	var meta Metadata
+	var body string
+	if err == nil {
+		meta, body, err = parseFrontmatter(content)
+		if err != nil {
+			return meta, "", Resolved{}, fmt.Errorf("parse prompt template %s: %w", name, err)
+		}
+	}
+
+	pin, pinned := l.pins[name]
+	if pinned && (err != nil || meta.Version != pin) {
+		content, source, err = l.loadFile(path.Join(versionsDir, name), pin)
+		if err != nil {
+			return meta, "", Resolved{}, fmt.Errorf("%w: %s version %s", ErrVersionNotFound, name, pin)
+		}
+		meta, body, err = parseFrontmatter(content)
+		if err != nil {
+			return meta, "", Resolved{}, fmt.Errorf("parse prompt template %s version %s: %w", name, pin, err)
+		}
+		if meta.Version == "" {
+			meta.Version = pin
+		}
+	}
+	if err != nil {
+		return meta, "", Resolved{}, err
+	}
+
+	sum := sha256.Sum256([]byte(content))
+	base, variant := splitVariant(name)
+	resolved := Resolved{
+		Name:    base,
+		Variant: variant,
+		Version: meta.Version,
+		Hash:    hex.EncodeToString(sum[:]),
+		Source:  source,
+	}
+	return meta, body, resolved, nil
+}
@@ -0,0 +1,116 @@
+package prompt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const currentImplement = "---\nversion: \"3\"\n---\nImplement v3\n"
+
+// newVersionedLoader returns a loader over a project whose implement prompt
+// is at version 3, with version 2 kept under versions/
+func newVersionedLoader(t *testing.T) *Loader {
+	t.Helper()
+	project := t.TempDir()
+	dir := filepath.Join(project, ".devflow", "prompts")
+	files := map[string]string{
+		"implement.txt":            currentImplement,
+		"versions/implement/2.txt": "Implement v2\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewLoader(project)
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestLoader_ResolveVersions(t *testing.T) {
+	tests := []struct {
+		name        string
+		pins        map[string]string
+		wantVersion string
+		wantHash    string
+		wantText    string
+	}{
+		{"unpinned uses latest", nil, "3", sha256Hex(currentImplement), "Implement v3"},
+		{"pin matching latest", map[string]string{"implement": "3"}, "3", sha256Hex(currentImplement), "Implement v3"},
+		{"pin older version", map[string]string{"implement": "2"}, "2", sha256Hex("Implement v2\n"), "Implement v2"},
+		{"pin for another prompt", map[string]string{"review-code": "1"}, "3", sha256Hex(currentImplement), "Implement v3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newVersionedLoader(t)
+			if tt.pins != nil {
+				l = l.WithPins(tt.pins)
+			}
+
+			got, err := l.Resolve("implement")
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			want := Resolved{Name: "implement", Version: tt.wantVersion, Hash: tt.wantHash, Source: SourceLocal}
+			if got != want {
+				t.Errorf("Resolve() = %+v, want %+v", got, want)
+			}
+
+			text, err := l.Load("implement")
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if strings.TrimSpace(text) != tt.wantText {
+				t.Errorf("Load() = %q, want %q", text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestLoader_ResolveMissingPin(t *testing.T) {
+	l := newVersionedLoader(t)
+	pinned := l.WithPins(map[string]string{"implement": "9"})
+
+	if _, err := pinned.Resolve("implement"); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("Resolve() error = %v, want ErrVersionNotFound", err)
+	}
+	if _, err := pinned.Load("implement"); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("Load() error = %v, want ErrVersionNotFound", err)
+	}
+
+	// The original loader is unaffected by the copy's pins
+	if got, err := l.Resolve("implement"); err != nil || got.Version != "3" {
+		t.Errorf("original Resolve() = %+v, %v; want version 3", got, err)
+	}
+}
+
+func TestResolved_String(t *testing.T) {
+	hash := sha256Hex("x")
+	tests := []struct {
+		resolved Resolved
+		want     string
+	}{
+		{Resolved{Name: "implement", Version: "2", Hash: hash}, "implement v2 (" + hash[:12] + ")"},
+		{Resolved{Name: "implement", Variant: "b", Version: "2", Hash: hash}, "implement@b v2 (" + hash[:12] + ")"},
+		{Resolved{Name: "review-code", Hash: hash}, "review-code (" + hash[:12] + ")"},
+		{Resolved{Name: "short", Hash: "abc"}, "short (abc)"},
+	}
+	for _, tt := range tests {
+		if got := tt.resolved.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.resolved, got, tt.want)
+		}
+	}
+}
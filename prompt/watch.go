@@ -0,0 +1,124 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is how long Watch waits after the last change before
+// reloading, so that an editor's write-rename-chmod burst reloads once.
+const DefaultWatchDebounce = 100 * time.Millisecond
+
+// ReloadEvent describes a template change picked up by Watch.
+type ReloadEvent struct {
+	Changed []string // Added, modified, or removed template files
+	Time    time.Time
+}
+
+// Watch watches the search directories for added, modified, or removed
+// templates (including partials, layouts, and versions) until ctx is
+// canceled. Changes are collected until none arrive for the debounce
+// window; then Watch clears the template cache, so the next Load picks up
+// the edit, and reports the change to onReload (which may be nil).
+// Watch blocks; run it in its own goroutine.
+//
+// Only search directories that exist when Watch starts are watched;
+// subdirectories created later are picked up.
+func (l *Loader) Watch(ctx context.Context, debounce time.Duration, onReload func(ReloadEvent)) error {
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watch prompts: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	changed := make(map[string]bool)
+	for _, dir := range l.dirs {
+		addWatchTree(watcher, dir.path, nil)
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			slog.Warn("prompt watch error", slog.String("error", err.Error()))
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					// Templates may land in a new directory before it is watched
+					addWatchTree(watcher, event.Name, changed)
+					timer.Reset(debounce)
+					continue
+				}
+			}
+			if event.Op == fsnotify.Chmod || !strings.HasSuffix(event.Name, ".txt") {
+				continue
+			}
+			changed[event.Name] = true
+			timer.Reset(debounce)
+
+		case <-timer.C:
+			if len(changed) == 0 {
+				continue
+			}
+			paths := make([]string, 0, len(changed))
+			for path := range changed {
+				paths = append(paths, path)
+			}
+			slices.Sort(paths)
+			clear(changed)
+
+			l.ClearCache()
+			if onReload != nil {
+				onReload(ReloadEvent{Changed: paths, Time: time.Now()})
+			}
+		}
+	}
+}
+
+// addWatchTree watches root and every directory below it, since fsnotify
+// is not recursive. Templates found are recorded in found if it is non-nil.
+func addWatchTree(watcher *fsnotify.Watcher, root string, found map[string]bool) {
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if err := watcher.Add(path); err != nil {
+				slog.Warn("prompt watch failed",
+					slog.String("dir", path),
+					slog.String("error", err.Error()))
+			}
+			return nil
+		}
+		if found != nil && strings.HasSuffix(path, ".txt") {
+			found[path] = true
+		}
+		return nil
+	})
+}
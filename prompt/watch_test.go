@@ -0,0 +1,140 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoader_WatchReloadsEditedTemplate(t *testing.T) {
+	project := t.TempDir()
+	dir := filepath.Join(project, ".devflow", "prompts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "implement.txt")
+	if err := os.WriteFile(path, []byte("original\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLoader(project)
+	if text, err := l.Load("implement"); err != nil || strings.TrimSpace(text) != "original" {
+		t.Fatalf("Load() = %q, %v", text, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan ReloadEvent, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- l.Watch(ctx, 50*time.Millisecond, func(e ReloadEvent) { events <- e })
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Watch() error = %v", err)
+		}
+	})
+
+	// Watch has no ready signal, so keep editing until the first reload
+	var first ReloadEvent
+	deadline := time.After(5 * time.Second)
+	for edit := 1; first.Time.IsZero(); edit++ {
+		if err := os.WriteFile(path, fmt.Appendf(nil, "edit %d\n", edit), 0644); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case first = <-events:
+		case <-time.After(100 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("no ReloadEvent after editing the template")
+		}
+	}
+	if !slices.Equal(first.Changed, []string{path}) {
+		t.Errorf("ReloadEvent.Changed = %v, want [%s]", first.Changed, path)
+	}
+
+	// A burst of writes is debounced into one reload
+	drain(events, 200*time.Millisecond)
+	for i := range 5 {
+		if err := os.WriteFile(path, fmt.Appendf(nil, "burst %d\n", i), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case e := <-events:
+		if !slices.Equal(e.Changed, []string{path}) {
+			t.Errorf("ReloadEvent.Changed = %v, want [%s]", e.Changed, path)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no ReloadEvent after a burst of writes")
+	}
+	if n := drain(events, 300*time.Millisecond); n != 0 {
+		t.Errorf("burst produced %d extra reloads", n)
+	}
+
+	if text, err := l.Load("implement"); err != nil || strings.TrimSpace(text) != "burst 4" {
+		t.Errorf("Load() after reload = %q, %v; want burst 4", text, err)
+	}
+}
+
+func TestLoader_WatchNewDirectory(t *testing.T) {
+	project := t.TempDir()
+	dir := filepath.Join(project, ".devflow", "prompts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLoader(project)
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan ReloadEvent, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- l.Watch(ctx, 50*time.Millisecond, func(e ReloadEvent) { events <- e })
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	partial := filepath.Join(dir, "partials", "header.txt")
+	deadline := time.After(5 * time.Second)
+	for edit := 1; ; edit++ {
+		// Recreate the directory until Watch has seen it
+		_ = os.RemoveAll(filepath.Dir(partial))
+		if err := os.MkdirAll(filepath.Dir(partial), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(partial, fmt.Appendf(nil, "header %d\n", edit), 0644); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case e := <-events:
+			if !slices.Contains(e.Changed, partial) {
+				t.Errorf("ReloadEvent.Changed = %v, want %s", e.Changed, partial)
+			}
+			return
+		case <-time.After(100 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("no ReloadEvent for a template in a new directory")
+		}
+	}
+}
+
+// drain discards events until none arrive for quiet and returns how many
+// it discarded
+func drain(events <-chan ReloadEvent, quiet time.Duration) int {
+	n := 0
+	for {
+		select {
+		case <-events:
+			n++
+		case <-time.After(quiet):
+			return n
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/randalmurphal/devflow/config"
+	"github.com/randalmurphal/llmkit/model"
+)
+
+// ErrUnknownModelKey indicates a model setting names no task type or tier.
+var ErrUnknownModelKey = errors.New("unknown model setting")
+
+// Model settings read by LoadConfig.
+const (
+	// ConfigPrefix prefixes model config keys: models.review, models.fast
+	ConfigPrefix = "models."
+
+	// EnvPrefix prefixes model environment overrides: DEVFLOW_MODEL_REVIEW
+	EnvPrefix = "DEVFLOW_MODEL_"
+)
+
+// Tier and large-context settings, keyed like task types
+const (
+	settingThinking     = "thinking"
+	settingDefault      = "default"
+	settingFast         = "fast"
+	settingLargeContext = "large_context"
+)
+
+// LoadConfig builds a Selector from resolved config and environment
+// overrides, so deployments can change models without code changes.
+//
+// Settings are models.<name> in config and DEVFLOW_MODEL_<NAME> in the
+// environment, which takes precedence. <name> is a task type (review,
+// vote_judge, ...), a tier (thinking, default, fast), or large_context for
+// SetLargeContextModel. cfg may be nil to read only the environment.
+// Unknown names fail with ErrUnknownModelKey, so typos are not silently
+// ignored.
+func LoadConfig(cfg *config.Resolved) (*Selector, error) {
+	settings := make(map[string]string)
+
+	if cfg != nil {
+		for _, key := range cfg.Keys() {
+			if name, ok := strings.CutPrefix(key, ConfigPrefix); ok {
+				settings[name] = cfg.Get(key)
+			}
+		}
+	}
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		if name, ok := strings.CutPrefix(key, EnvPrefix); ok && value != "" {
+			settings[strings.ToLower(name)] = value
+		}
+	}
+
+	var opts []model.SelectorOption
+	overrides := make(map[any]model.ModelName)
+	var largeContext model.ModelName
+	for name, value := range settings {
+		m := model.ModelName(value)
+		switch name {
+		case settingThinking:
+			opts = append(opts, model.WithThinkingModel(m))
+		case settingDefault:
+			opts = append(opts, model.WithDefaultModel(m))
+		case settingFast:
+			opts = append(opts, model.WithFastModel(m))
+		case settingLargeContext:
+			largeContext = m
+		default:
+			if !slices.Contains(AllTypes, Type(name)) {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownModelKey, name)
+			}
+			overrides[Type(name)] = m
+		}
+	}
+	opts = append(opts, model.WithTaskOverrides(overrides))
+
+	selector := NewEscalatingSelector(opts...)
+	if largeContext != "" {
+		selector.SetLargeContextModel(largeContext)
+	}
+	return selector, nil
+}
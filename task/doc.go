@@ -1,23 +1,27 @@
 // Package task provides task-based model selection for LLM operations.
 //
 // Core types:
-//   - TaskType: Type of task (investigation, implementation, review, etc.)
-//   - Selector: Selects appropriate model based on task type
+//   - Type: Type of task (investigate, implement, review, etc.)
+//   - Selector: Selects the model for a task type, escalating to a model
+//     with a larger context window when the input would not fit
+//   - Selection: A ModelForInput decision and its rationale
+//   - Provider: LLM backend named by a model prefix (openai:gpt-4.1), returned
+//     with the bare model by Selector.ProviderFor
+//   - Rule: YAML-loaded model override (repo, task, diff size) evaluated by
+//     Selector.SelectWithRules, which traces the rule that matched
+//   - UsageRecorder: Aggregates LLM usage and spend per task type and model
 //
-// Task types:
-//   - TaskInvestigation: Code exploration, impact analysis
-//   - TaskImplementation: Writing code, making changes
-//   - TaskReview: Code review, validation
-//   - TaskArchitecture: Design decisions, high-stakes reasoning
-//   - TaskSimple: Quick searches, formatting
+// LoadConfig builds a Selector from models.<task> config keys and
+// DEVFLOW_MODEL_<TASK> environment overrides.
+//
+// Task types by tier:
+//   - Thinking: Investigate, Architecture, VoteJudge, Security
+//   - Default: Implement, Review, Validate, Fix, TestGeneration
+//   - Fast: Search, Transform, Summarize, Triage
 //
 // Example usage:
 //
-//	selector := task.NewSelector(task.Config{
-//	    Investigation: "claude-opus-4-20250514",
-//	    Implementation: "claude-sonnet-4-20250514",
-//	    Review:         "claude-opus-4-20250514",
-//	    Simple:         "claude-haiku-3-5-20241022",
-//	})
-//	model := selector.ModelFor(task.TaskReview)
+//	selector := task.NewEscalatingSelector(model.WithTaskOverride(task.Review, model.ModelOpus))
+//	name := selector.Select(task.Review)
+//	sel := selector.ModelForInput(task.Review, diffTokens)
 package task
@@ -0,0 +1,47 @@
+package task
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/randalmurphal/llmkit/model"
+)
+
+// Provider is the LLM backend a model is served by.
+type Provider string
+
+// Supported providers.
+const (
+	ProviderAnthropic Provider = "anthropic"
+	ProviderOpenAI    Provider = "openai"
+	ProviderGemini    Provider = "gemini"
+	ProviderOllama    Provider = "ollama"
+)
+
+// ProviderSep separates a provider prefix from the model: openai:gpt-4.1
+const ProviderSep = ":"
+
+// DefaultProvider serves models configured without a provider prefix.
+var DefaultProvider = ProviderAnthropic
+
+// KnownProviders lists the prefixes ParseModel recognizes. Other prefixes
+// are treated as part of the model name, so Ollama tags such as llama3:8b
+// stay intact.
+var KnownProviders = []Provider{ProviderAnthropic, ProviderOpenAI, ProviderGemini, ProviderOllama}
+
+// ParseModel splits a configured model such as "openai:gpt-4.1" into its
+// provider and model name. Names without a known provider prefix belong to
+// DefaultProvider.
+func ParseModel(name model.ModelName) (Provider, model.ModelName) {
+	prefix, rest, found := strings.Cut(string(name), ProviderSep)
+	if found && slices.Contains(KnownProviders, Provider(prefix)) {
+		return Provider(prefix), model.ModelName(rest)
+	}
+	return DefaultProvider, name
+}
+
+// ProviderFor returns the provider and bare model name Select picks for a
+// task, so the LLM client layer can route each task to its backend.
+func (s *Selector) ProviderFor(t Type) (Provider, model.ModelName) {
+	return ParseModel(s.Select(t))
+}
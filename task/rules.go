@@ -0,0 +1,175 @@
+package task
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+
+	"github.com/randalmurphal/llmkit/model"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidRule indicates a selector rule is malformed.
+var ErrInvalidRule = errors.New("invalid selector rule")
+
+// Rule overrides the model when all of its conditions match, e.g.
+// "if repo=payments and task=review use opus".
+type Rule struct {
+	Name  string          `yaml:"name"`
+	When  Condition       `yaml:"when"`
+	Model model.ModelName `yaml:"model"`
+}
+
+// Condition is the part of a Rule that must match. Unset fields match
+// anything; a rule needs at least one set.
+type Condition struct {
+	Repo            string `yaml:"repo,omitempty"`              // Repository name; path.Match glob
+	Task            Type   `yaml:"task,omitempty"`              // Task type
+	DiffLinesOver   int    `yaml:"diff_lines_over,omitempty"`   // Matches diffs longer than this
+	InputTokensOver int    `yaml:"input_tokens_over,omitempty"` // Matches inputs larger than this
+}
+
+// RuleInput describes the call being made, for rule evaluation.
+type RuleInput struct {
+	Task        Type
+	Repo        string
+	DiffLines   int
+	InputTokens int
+}
+
+// RuleResult is one rule's evaluation in a RuleDecision's trace.
+type RuleResult struct {
+	Rule    string
+	Matched bool
+	Reason  string // First condition that failed, or "matched"
+}
+
+// RuleDecision is the outcome of SelectWithRules.
+type RuleDecision struct {
+	Model model.ModelName
+	Rule  string       // Name of the matching rule; "" if none matched
+	Trace []RuleResult // Rules evaluated, in order, up to the match
+}
+
+// rulesFile is the YAML layout read by ParseRules
+type rulesFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// ParseRules parses and validates selector rules:
+//
+//	rules:
+//	  - name: payments-review
+//	    when: {repo: payments, task: review}
+//	    model: opus
+//	  - name: large-diff
+//	    when: {diff_lines_over: 3000}
+//	    model: sonnet-1m
+func ParseRules(data []byte) ([]Rule, error) {
+	var file rulesFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+
+	for i, rule := range file.Rules {
+		if rule.Name == "" {
+			file.Rules[i].Name = fmt.Sprintf("#%d", i+1) // Unnamed rules are numbered
+		}
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidRule, file.Rules[i].Name, err)
+		}
+	}
+	return file.Rules, nil
+}
+
+// LoadRules reads selector rules from a YAML file (see ParseRules).
+func LoadRules(filename string) ([]Rule, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("read rules: %w", err)
+	}
+	rules, err := ParseRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return rules, nil
+}
+
+// validate checks a rule has a model and a usable condition
+func (r Rule) validate() error {
+	if r.Model == "" {
+		return errors.New("no model")
+	}
+	if r.When == (Condition{}) {
+		return errors.New("no conditions")
+	}
+	if r.When.Task != "" && !slices.Contains(AllTypes, r.When.Task) {
+		return fmt.Errorf("unknown task %q", r.When.Task)
+	}
+	if _, err := path.Match(r.When.Repo, ""); err != nil {
+		return fmt.Errorf("bad repo pattern %q: %v", r.When.Repo, err)
+	}
+	return nil
+}
+
+// match reports whether the condition holds for in, and if not, why
+func (c Condition) match(in RuleInput) (bool, string) {
+	if c.Repo != "" {
+		if ok, _ := path.Match(c.Repo, in.Repo); !ok {
+			return false, fmt.Sprintf("repo %q does not match %q", in.Repo, c.Repo)
+		}
+	}
+	if c.Task != "" && c.Task != in.Task {
+		return false, fmt.Sprintf("task %s is not %s", in.Task, c.Task)
+	}
+	if c.DiffLinesOver > 0 && in.DiffLines <= c.DiffLinesOver {
+		return false, fmt.Sprintf("diff of %d lines is not over %d", in.DiffLines, c.DiffLinesOver)
+	}
+	if c.InputTokensOver > 0 && in.InputTokens <= c.InputTokensOver {
+		return false, fmt.Sprintf("input of %d tokens is not over %d", in.InputTokens, c.InputTokensOver)
+	}
+	return true, "matched"
+}
+
+// SetRules sets the rules SelectWithRules evaluates, in order.
+func (s *Selector) SetRules(rules []Rule) {
+	s.rules = slices.Clone(rules)
+}
+
+// Rules returns the selector's rules.
+func (s *Selector) Rules() []Rule {
+	return slices.Clone(s.rules)
+}
+
+// SelectWithRules returns the model of the first rule matching in, or
+// Select(in.Task) if none does, with a trace of the rules evaluated for
+// logging.
+func (s *Selector) SelectWithRules(in RuleInput) RuleDecision {
+	var decision RuleDecision
+	for _, rule := range s.rules {
+		matched, reason := rule.When.match(in)
+		decision.Trace = append(decision.Trace, RuleResult{Rule: rule.Name, Matched: matched, Reason: reason})
+		if matched {
+			decision.Model = rule.Model
+			decision.Rule = rule.Name
+			return decision
+		}
+	}
+	decision.Model = s.Select(in.Task)
+	return decision
+}
+
+// String formats the decision for logs, e.g.
+// "opus (rule payments-review)" or "sonnet (no rule matched)".
+func (d RuleDecision) String() string {
+	if d.Rule == "" {
+		return fmt.Sprintf("%s (no rule matched)", d.Model)
+	}
+	return fmt.Sprintf("%s (rule %s)", d.Model, d.Rule)
+}
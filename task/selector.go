@@ -0,0 +1,139 @@
+package task
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/randalmurphal/llmkit/model"
+)
+
+// DefaultContextWindow is the context window assumed for models missing
+// from ContextWindows.
+const DefaultContextWindow = 200_000
+
+// DefaultOutputReserve is the part of a context window kept free for the
+// model's response when checking whether an input fits.
+const DefaultOutputReserve = 8_192
+
+// ContextWindows maps models to their context window in tokens.
+var ContextWindows = map[model.ModelName]int{
+	model.ModelOpus:   200_000,
+	model.ModelSonnet: 200_000,
+	model.ModelHaiku:  200_000,
+}
+
+// Selector selects models for dev workflow tasks. It embeds the llmkit
+// selector, so Select and SelectForTier work as usual, and adds
+// input-size-aware selection.
+type Selector struct {
+	*model.Selector
+
+	windows      map[model.ModelName]int // Context window per model
+	largeContext model.ModelName         // Last resort for oversized inputs
+	rules        []Rule                  // Overrides for SelectWithRules
+}
+
+// Selection is the outcome of ModelForInput, with the rationale for logging.
+type Selection struct {
+	Task          Type
+	Model         model.ModelName
+	Requested     model.ModelName // Model Select picks for the task
+	InputTokens   int
+	ContextWindow int  // Window of Model
+	Escalated     bool // Model differs from Requested because of input size
+	Reason        string
+}
+
+// Clone returns a copy of the selector with the same configuration.
+func (s *Selector) Clone() *Selector {
+	return &Selector{
+		Selector:     s.Selector.Clone(),
+		windows:      maps.Clone(s.windows),
+		largeContext: s.largeContext,
+		rules:        slices.Clone(s.rules),
+	}
+}
+
+// WithGlobal returns a new selector with a global override applied.
+func (s *Selector) WithGlobal(m model.ModelName) *Selector {
+	clone := s.Clone()
+	clone.Selector = s.Selector.WithGlobal(m)
+	return clone
+}
+
+// SetContextWindow records a model's context window in tokens.
+func (s *Selector) SetContextWindow(m model.ModelName, tokens int) {
+	s.windows[m] = tokens
+}
+
+// SetLargeContextModel sets the model ModelForInput escalates to when an
+// input fits none of the tier models, e.g. a long-context Sonnet deployment.
+// Its window must be set with SetContextWindow.
+func (s *Selector) SetLargeContextModel(m model.ModelName) {
+	s.largeContext = m
+}
+
+// ContextWindow returns a model's context window in tokens.
+func (s *Selector) ContextWindow(m model.ModelName) int {
+	if tokens, ok := s.windows[m]; ok {
+		return tokens
+	}
+	return DefaultContextWindow
+}
+
+// ModelForInput picks the model for a task whose input is estimatedTokens
+// long. It starts from Select(t) and, if the input plus
+// DefaultOutputReserve does not fit that model's context window, escalates
+// to the next higher tier and then to the large-context model. If nothing
+// fits, the model with the largest window is returned. Selection.Reason
+// explains the choice.
+func (s *Selector) ModelForInput(t Type, estimatedTokens int) Selection {
+	requested := s.Select(t)
+	sel := Selection{
+		Task:        t,
+		Model:       requested,
+		Requested:   requested,
+		InputTokens: estimatedTokens,
+	}
+	needed := estimatedTokens + DefaultOutputReserve
+
+	sel.ContextWindow = s.ContextWindow(requested)
+	if needed <= sel.ContextWindow {
+		sel.Reason = fmt.Sprintf("%s: %d tokens fit %s (%d window)",
+			t, estimatedTokens, requested, sel.ContextWindow)
+		return sel
+	}
+
+	largest, largestWindow := requested, sel.ContextWindow
+	for _, candidate := range s.escalations(t) {
+		window := s.ContextWindow(candidate)
+		if needed <= window {
+			sel.Model, sel.ContextWindow, sel.Escalated = candidate, window, true
+			sel.Reason = fmt.Sprintf("%s: %d tokens exceed %s (%d window); escalated to %s (%d window)",
+				t, estimatedTokens, requested, s.ContextWindow(requested), candidate, window)
+			return sel
+		}
+		if window > largestWindow {
+			largest, largestWindow = candidate, window
+		}
+	}
+
+	sel.Model, sel.ContextWindow, sel.Escalated = largest, largestWindow, largest != requested
+	sel.Reason = fmt.Sprintf("%s: %d tokens exceed every configured model; using largest window %s (%d)",
+		t, estimatedTokens, largest, largestWindow)
+	return sel
+}
+
+// escalations returns the models above a task's tier, then the
+// large-context model
+func (s *Selector) escalations(t Type) []model.ModelName {
+	var models []model.ModelName
+	for tier := TierForTask(t) + 1; tier <= model.TierThinking; tier++ {
+		models = append(models, s.SelectForTier(tier))
+	}
+	if s.largeContext != "" {
+		models = append(models, s.largeContext)
+	}
+	return models
+}
@@ -1,6 +1,8 @@
 package task
 
 import (
+	"maps"
+
 	"github.com/randalmurphal/llmkit/model"
 )
 
@@ -13,39 +15,52 @@ const (
 	Investigate  Type = "investigate"
 	Architecture Type = "architecture"
 	VoteJudge    Type = "vote_judge"
+	Security     Type = "security" // Security scans of changes
 
 	// Standard dev tasks - default tier
-	Implement Type = "implement"
-	Review    Type = "review"
-	Validate  Type = "validate"
-	Fix       Type = "fix"
+	Implement      Type = "implement"
+	Review         Type = "review"
+	Validate       Type = "validate"
+	Fix            Type = "fix"
+	TestGeneration Type = "test_generation" // Tests for coverage gaps
 
 	// Fast tasks - can use smaller models
 	Search    Type = "search"
 	Transform Type = "transform"
 	Summarize Type = "summarize"
+	Triage    Type = "triage" // Ticket triage and labeling
 )
 
+// AllTypes lists every task type.
+var AllTypes = []Type{
+	Investigate, Architecture, VoteJudge, Security,
+	Implement, Review, Validate, Fix, TestGeneration,
+	Search, Transform, Summarize, Triage,
+}
+
 // DefaultModelMap maps task types to default models.
 var DefaultModelMap = map[Type]model.ModelName{
-	Investigate:  model.ModelOpus,
-	Architecture: model.ModelOpus,
-	VoteJudge:    model.ModelOpus,
-	Implement:    model.ModelSonnet,
-	Review:       model.ModelSonnet,
-	Validate:     model.ModelSonnet,
-	Fix:          model.ModelSonnet,
-	Search:       model.ModelHaiku,
-	Transform:    model.ModelHaiku,
-	Summarize:    model.ModelHaiku,
+	Investigate:    model.ModelOpus,
+	Architecture:   model.ModelOpus,
+	VoteJudge:      model.ModelOpus,
+	Security:       model.ModelOpus,
+	Implement:      model.ModelSonnet,
+	Review:         model.ModelSonnet,
+	Validate:       model.ModelSonnet,
+	Fix:            model.ModelSonnet,
+	TestGeneration: model.ModelSonnet,
+	Search:         model.ModelHaiku,
+	Transform:      model.ModelHaiku,
+	Summarize:      model.ModelHaiku,
+	Triage:         model.ModelHaiku,
 }
 
 // TierForTask returns the appropriate tier for a task type.
 func TierForTask(t Type) model.Tier {
 	switch t {
-	case Investigate, Architecture, VoteJudge:
+	case Investigate, Architecture, VoteJudge, Security:
 		return model.TierThinking
-	case Search, Transform, Summarize:
+	case Search, Transform, Summarize, Triage:
 		return model.TierFast
 	default:
 		return model.TierDefault
@@ -68,6 +83,15 @@ func NewSelector(opts ...model.SelectorOption) *model.Selector {
 	return model.NewSelector(allOpts...)
 }
 
+// NewEscalatingSelector creates a Selector like NewSelector, adding
+// input-size-aware selection with ModelForInput.
+func NewEscalatingSelector(opts ...model.SelectorOption) *Selector {
+	return &Selector{
+		Selector: NewSelector(opts...),
+		windows:  maps.Clone(ContextWindows),
+	}
+}
+
 // SelectModel selects the appropriate model for a task type.
 // Uses the default model map unless overridden.
 func SelectModel(t Type) model.ModelName {
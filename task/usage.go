@@ -0,0 +1,165 @@
+package task
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/randalmurphal/devflow/transcript"
+	"github.com/randalmurphal/llmkit/model"
+)
+
+// Usage is the accumulated LLM usage of a task type or model.
+type Usage struct {
+	Calls     int     `json:"calls"`
+	TokensIn  int     `json:"tokensIn"`
+	TokensOut int     `json:"tokensOut"`
+	Cost      float64 `json:"cost"` // USD
+}
+
+// Add accumulates other into u.
+func (u *Usage) Add(other Usage) {
+	u.Calls += other.Calls
+	u.TokensIn += other.TokensIn
+	u.TokensOut += other.TokensOut
+	u.Cost += other.Cost
+}
+
+// UsageRecorder aggregates LLM usage per task type and model so teams can
+// see where spend goes. Nodes call Record (or RecordTurn) after each LLM
+// call. It is safe for concurrent use.
+type UsageRecorder struct {
+	mu     sync.Mutex
+	totals map[Type]map[model.ModelName]Usage
+}
+
+// NewUsageRecorder creates an empty usage recorder.
+func NewUsageRecorder() *UsageRecorder {
+	return &UsageRecorder{totals: make(map[Type]map[model.ModelName]Usage)}
+}
+
+// Record adds one LLM call for a task type.
+func (r *UsageRecorder) Record(t Type, m model.ModelName, tokensIn, tokensOut int, cost float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byModel, ok := r.totals[t]
+	if !ok {
+		byModel = make(map[model.ModelName]Usage)
+		r.totals[t] = byModel
+	}
+	u := byModel[m]
+	u.Add(Usage{Calls: 1, TokensIn: tokensIn, TokensOut: tokensOut, Cost: cost})
+	byModel[m] = u
+}
+
+// RecordTurn adds a transcript turn's model, tokens, and cost for a task
+// type, so nodes that already record turns need not repeat the numbers.
+func (r *UsageRecorder) RecordTurn(t Type, turn transcript.Turn) {
+	r.Record(t, model.ModelName(turn.Model), turn.TokensIn, turn.TokensOut, turn.Cost)
+}
+
+// Reset clears all recorded usage.
+func (r *UsageRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.totals = make(map[Type]map[model.ModelName]Usage)
+}
+
+// TaskUsage is one task type's usage in a UsageReport.
+type TaskUsage struct {
+	Task   Type                      `json:"task"`
+	Usage  Usage                     `json:"usage"`
+	Models map[model.ModelName]Usage `json:"models"`
+}
+
+// UsageReport summarizes recorded usage, most expensive task first.
+type UsageReport struct {
+	Tasks []TaskUsage `json:"tasks"`
+	Total Usage       `json:"total"`
+}
+
+// Report returns the usage recorded so far.
+func (r *UsageRecorder) Report() UsageReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var report UsageReport
+	for t, byModel := range r.totals {
+		tu := TaskUsage{Task: t, Models: maps.Clone(byModel)}
+		for _, u := range byModel {
+			tu.Usage.Add(u)
+		}
+		report.Total.Add(tu.Usage)
+		report.Tasks = append(report.Tasks, tu)
+	}
+	slices.SortFunc(report.Tasks, func(a, b TaskUsage) int {
+		if c := cmp.Compare(b.Usage.Cost, a.Usage.Cost); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Task, b.Task)
+	})
+	return report
+}
+
+// String formats the report as one line per task type, e.g.
+// "review: 3 calls, 12000 in / 800 out, $0.0480 (62%)".
+func (r UsageReport) String() string {
+	var b strings.Builder
+	for _, tu := range r.Tasks {
+		share := 0.0
+		if r.Total.Cost > 0 {
+			share = tu.Usage.Cost / r.Total.Cost * 100
+		}
+		fmt.Fprintf(&b, "%s: %d calls, %d in / %d out, $%.4f (%.0f%%)\n",
+			tu.Task, tu.Usage.Calls, tu.Usage.TokensIn, tu.Usage.TokensOut, tu.Usage.Cost, share)
+	}
+	fmt.Fprintf(&b, "total: %d calls, %d in / %d out, $%.4f",
+		r.Total.Calls, r.Total.TokensIn, r.Total.TokensOut, r.Total.Cost)
+	return b.String()
+}
+
+// UsageByTask returns the per-task totals in the form kept in transcript
+// metadata (transcript.Meta.UsageByTask).
+func (r UsageReport) UsageByTask() map[string]transcript.TaskUsage {
+	usage := make(map[string]transcript.TaskUsage, len(r.Tasks))
+	for _, tu := range r.Tasks {
+		usage[string(tu.Task)] = transcript.TaskUsage{
+			Calls:     tu.Usage.Calls,
+			TokensIn:  tu.Usage.TokensIn,
+			TokensOut: tu.Usage.TokensOut,
+			Cost:      tu.Usage.Cost,
+		}
+	}
+	return usage
+}
+
+// UsageStore is a transcript store that keeps per-task usage in run
+// metadata, such as transcript.FileStore.
+type UsageStore interface {
+	SetUsageByTask(runID string, usage map[string]transcript.TaskUsage) error
+	EndRun(runID string, status transcript.RunStatus) error
+}
+
+// EndRun stores the usage recorded so far in the run's metadata and ends
+// the run, so Searcher.CostByTask can aggregate spend by task type.
+func (r *UsageRecorder) EndRun(store UsageStore, runID string, status transcript.RunStatus) error {
+	if err := store.SetUsageByTask(runID, r.Report().UsageByTask()); err != nil {
+		return fmt.Errorf("record usage by task: %w", err)
+	}
+	return store.EndRun(runID, status)
+}
+
+// Turn returns the report as a system turn, so a run's transcript shows
+// where its spend went alongside the turns that incurred it.
+func (r UsageReport) Turn() transcript.Turn {
+	return transcript.Turn{
+		Role:      "system",
+		Content:   "Usage by task\n" + r.String(),
+		Timestamp: time.Now(),
+	}
+}
@@ -0,0 +1,59 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/randalmurphal/devflow/transcript"
+)
+
+func TestUsageRecorder_EndRunStoresUsageByTask(t *testing.T) {
+	dir := t.TempDir()
+	store, err := transcript.NewFileStore(transcript.StoreConfig{BaseDir: dir})
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	recorder := NewUsageRecorder()
+	for i, runID := range []string{"run-1", "run-2"} {
+		if err := store.StartRun(runID, transcript.RunMetadata{FlowID: "flow"}); err != nil {
+			t.Fatalf("StartRun() error = %v", err)
+		}
+		recorder.Reset()
+		recorder.Record(Review, "opus", 1000, 100, 0.5)
+		recorder.Record(Review, "sonnet", 500, 50, 0.1)
+		if i == 0 {
+			recorder.Record(Implement, "sonnet", 2000, 400, 0.2)
+		}
+		if err := recorder.EndRun(store, runID, transcript.RunStatusCompleted); err != nil {
+			t.Fatalf("EndRun() error = %v", err)
+		}
+	}
+
+	// Saved with the transcript and readable from metadata alone
+	loaded, err := store.Load("run-1")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := transcript.TaskUsage{Calls: 2, TokensIn: 1500, TokensOut: 150, Cost: 0.6}
+	if got := loaded.Metadata.UsageByTask["review"]; got != want {
+		t.Errorf("Load() review usage = %+v, want %+v", got, want)
+	}
+	meta, err := store.LoadMetadata("run-1")
+	if err != nil {
+		t.Fatalf("LoadMetadata() error = %v", err)
+	}
+	if len(meta.UsageByTask) != 2 || meta.UsageByTask["implement"].Calls != 1 {
+		t.Errorf("LoadMetadata() UsageByTask = %+v", meta.UsageByTask)
+	}
+
+	byTask, err := transcript.NewSearcher(dir).CostByTask(transcript.ListFilter{})
+	if err != nil {
+		t.Fatalf("CostByTask() error = %v", err)
+	}
+	if review := byTask["review"]; review == nil || review.Runs != 2 || review.Turns != 4 {
+		t.Errorf("CostByTask() review = %+v, want 2 runs and 4 calls", review)
+	}
+	if implement := byTask["implement"]; implement == nil || implement.Runs != 1 {
+		t.Errorf("CostByTask() implement = %+v, want 1 run", implement)
+	}
+}
@@ -1,4 +1,5 @@
-// Package testutil provides utilities for testing.
+// Package testutil provides utilities for testing, including fixtures,
+// temporary git repositories, and an in-memory Jira server (JiraServer).
 package testutil
 
 import (
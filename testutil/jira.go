@@ -0,0 +1,416 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// DefaultJiraTransitions are offered for issues without scripted
+// transitions.
+var DefaultJiraTransitions = []JiraTransition{
+	{ID: "11", Name: "To Do", To: "To Do"},
+	{ID: "21", Name: "In Progress", To: "In Progress"},
+	{ID: "31", Name: "Done", To: "Done"},
+}
+
+// JiraIssue is an issue held by a JiraServer.
+type JiraIssue struct {
+	Key         string
+	Summary     string
+	Description any    // String (v2) or ADF document (v3)
+	Status      string // Defaults to "To Do"
+	Type        string // Defaults to "Task"
+	Assignee    string // Account ID or username; "" if unassigned
+	Labels      []string
+	Parent      string         // Parent issue key
+	Fields      map[string]any // Other fields, e.g. custom fields
+	Comments    []any          // Comment bodies, oldest first
+}
+
+// JiraTransition is a workflow transition offered on an issue.
+type JiraTransition struct {
+	ID   string
+	Name string
+	To   string // Status after the transition
+}
+
+// JiraRequest is a request received by a JiraServer.
+type JiraRequest struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+// JiraServer is an in-memory Jira REST API (v2 and v3) for tests. It
+// serves serverInfo, issue get/create/update, search, transitions, comments,
+// and assignment, so a jira.Client pointed at its URL works without
+// Atlassian. Search returns every issue; JQL is not evaluated.
+type JiraServer struct {
+	*httptest.Server
+
+	// Deployment is reported by serverInfo (default "Cloud").
+	Deployment string
+
+	mu          sync.Mutex
+	issues      map[string]*JiraIssue
+	transitions map[string][]JiraTransition
+	requests    []JiraRequest
+	throttled   int
+	retryAfter  time.Duration
+}
+
+// NewJiraServer starts a JiraServer that is closed when the test ends.
+func NewJiraServer(t *testing.T) *JiraServer {
+	t.Helper()
+
+	s := &JiraServer{
+		Deployment:  "Cloud",
+		issues:      make(map[string]*JiraIssue),
+		transitions: make(map[string][]JiraTransition),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// AddIssue adds or replaces an issue.
+func (s *JiraServer) AddIssue(issue JiraIssue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if issue.Status == "" {
+		issue.Status = "To Do"
+	}
+	if issue.Type == "" {
+		issue.Type = "Task"
+	}
+	s.issues[issue.Key] = &issue
+}
+
+// Issue returns a copy of an issue's current state.
+func (s *JiraServer) Issue(key string) (JiraIssue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issue, ok := s.issues[key]
+	if !ok {
+		return JiraIssue{}, false
+	}
+	return *issue, true
+}
+
+// SetTransitions scripts the transitions offered on an issue.
+func (s *JiraServer) SetTransitions(key string, transitions ...JiraTransition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transitions[key] = transitions
+}
+
+// ThrottleNext answers the next n requests with 429 Too Many Requests and
+// a Retry-After of retryAfter (whole seconds), to exercise client retries.
+func (s *JiraServer) ThrottleNext(n int, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.throttled = n
+	s.retryAfter = retryAfter
+}
+
+// Requests returns the requests received so far, including throttled ones.
+func (s *JiraServer) Requests() []JiraRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// serveHTTP records and routes a request
+func (s *JiraServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, JiraRequest{Method: r.Method, Path: r.URL.Path, Body: body})
+
+	if s.throttled > 0 {
+		s.throttled--
+		w.Header().Set("Retry-After", strconv.Itoa(int(s.retryAfter.Seconds())))
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		writeJiraError(w, http.StatusTooManyRequests, "Rate limit exceeded.")
+		return
+	}
+
+	// /rest/api/{2,3}/<endpoint>
+	path := r.URL.Path
+	for _, prefix := range []string{"/rest/api/2/", "/rest/api/3/"} {
+		path = strings.TrimPrefix(path, prefix)
+	}
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "serverInfo" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]any{
+			"baseUrl":        s.URL,
+			"version":        "1001.0.0",
+			"deploymentType": s.Deployment,
+		})
+	case path == "search" && r.Method == http.MethodPost:
+		s.search(w, body)
+	case path == "issue" && r.Method == http.MethodPost:
+		s.createIssue(w, body)
+	case len(parts) >= 2 && parts[0] == "issue":
+		issue, ok := s.issues[parts[1]]
+		if !ok {
+			writeJiraError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+			return
+		}
+		s.serveIssue(w, r.Method, issue, parts[2:], body)
+	default:
+		writeJiraError(w, http.StatusNotImplemented, "JiraServer does not support "+r.Method+" "+r.URL.Path)
+	}
+}
+
+// serveIssue handles /issue/{key}/<sub>
+func (s *JiraServer) serveIssue(w http.ResponseWriter, method string, issue *JiraIssue, sub []string, body []byte) {
+	endpoint := strings.Join(sub, "/")
+	switch {
+	case endpoint == "" && method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.issueJSON(issue))
+
+	case endpoint == "" && method == http.MethodPut:
+		var req struct {
+			Fields map[string]any `json:"fields"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeJiraError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		applyJiraFields(issue, req.Fields)
+		w.WriteHeader(http.StatusNoContent)
+
+	case endpoint == "transitions" && method == http.MethodGet:
+		var transitions []map[string]any
+		for _, t := range s.transitionsOf(issue.Key) {
+			transitions = append(transitions, map[string]any{"id": t.ID, "name": t.Name, "to": map[string]any{"name": t.To}})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"transitions": transitions})
+
+	case endpoint == "transitions" && method == http.MethodPost:
+		var req struct {
+			Transition struct {
+				ID string `json:"id"`
+			} `json:"transition"`
+		}
+		_ = json.Unmarshal(body, &req)
+		for _, t := range s.transitionsOf(issue.Key) {
+			if t.ID == req.Transition.ID {
+				issue.Status = t.To
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeJiraError(w, http.StatusBadRequest, "Transition id '"+req.Transition.ID+"' is not valid for this issue.")
+
+	case endpoint == "comment" && method == http.MethodGet:
+		comments := make([]map[string]any, len(issue.Comments))
+		for i, c := range issue.Comments {
+			comments[i] = map[string]any{"id": strconv.Itoa(i + 1), "body": c}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"comments": comments, "total": len(comments)})
+
+	case endpoint == "comment" && method == http.MethodPost:
+		var req struct {
+			Body any `json:"body"`
+		}
+		_ = json.Unmarshal(body, &req)
+		issue.Comments = append(issue.Comments, req.Body)
+		writeJSON(w, http.StatusCreated, map[string]any{"id": strconv.Itoa(len(issue.Comments)), "body": req.Body})
+
+	case endpoint == "assignee" && method == http.MethodPut:
+		var req map[string]any
+		_ = json.Unmarshal(body, &req)
+		issue.Assignee = userID(req)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeJiraError(w, http.StatusNotImplemented, "JiraServer does not support "+method+" issue/"+issue.Key+"/"+endpoint)
+	}
+}
+
+// createIssue handles POST /issue
+func (s *JiraServer) createIssue(w http.ResponseWriter, body []byte) {
+	var req struct {
+		Fields map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeJiraError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	project, _ := req.Fields["project"].(map[string]any)
+	projectKey, _ := project["key"].(string)
+	if projectKey == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"errors": map[string]string{"project": "project is required"}})
+		return
+	}
+
+	n := 1
+	for key := range s.issues {
+		if p, num, ok := strings.Cut(key, "-"); ok && p == projectKey {
+			if i, err := strconv.Atoi(num); err == nil && i >= n {
+				n = i + 1
+			}
+		}
+	}
+	issue := &JiraIssue{Key: fmt.Sprintf("%s-%d", projectKey, n), Status: "To Do", Type: "Task"}
+	applyJiraFields(issue, req.Fields)
+	s.issues[issue.Key] = issue
+
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"id":   strconv.Itoa(10000 + len(s.issues)),
+		"key":  issue.Key,
+		"self": s.URL + "/rest/api/3/issue/" + issue.Key,
+	})
+}
+
+// search handles POST /search, paging through every issue in key order
+func (s *JiraServer) search(w http.ResponseWriter, body []byte) {
+	var req struct {
+		StartAt    int `json:"startAt"`
+		MaxResults int `json:"maxResults"`
+	}
+	_ = json.Unmarshal(body, &req)
+	if req.MaxResults <= 0 {
+		req.MaxResults = 50
+	}
+
+	keys := slices.SortedFunc(maps.Keys(s.issues), compareIssueKeys)
+	page := []map[string]any{}
+	for _, key := range keys[min(req.StartAt, len(keys)):min(req.StartAt+req.MaxResults, len(keys))] {
+		page = append(page, s.issueJSON(s.issues[key]))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"startAt":    req.StartAt,
+		"maxResults": req.MaxResults,
+		"total":      len(keys),
+		"issues":     page,
+	})
+}
+
+// issueJSON renders an issue as the REST API does
+func (s *JiraServer) issueJSON(issue *JiraIssue) map[string]any {
+	fields := maps.Clone(issue.Fields)
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+	project, _, _ := strings.Cut(issue.Key, "-")
+	fields["summary"] = issue.Summary
+	fields["status"] = map[string]any{"name": issue.Status}
+	fields["issuetype"] = map[string]any{"name": issue.Type}
+	fields["project"] = map[string]any{"key": project}
+	if issue.Description != nil {
+		fields["description"] = issue.Description
+	}
+	if issue.Assignee != "" {
+		fields["assignee"] = map[string]any{"accountId": issue.Assignee, "name": issue.Assignee, "displayName": issue.Assignee}
+	}
+	if len(issue.Labels) > 0 {
+		fields["labels"] = issue.Labels
+	}
+	if issue.Parent != "" {
+		fields["parent"] = map[string]any{"key": issue.Parent}
+	}
+
+	return map[string]any{
+		"id":     issue.Key,
+		"key":    issue.Key,
+		"self":   s.URL + "/rest/api/3/issue/" + issue.Key,
+		"fields": fields,
+	}
+}
+
+// transitionsOf returns the transitions offered on an issue
+func (s *JiraServer) transitionsOf(key string) []JiraTransition {
+	if transitions, ok := s.transitions[key]; ok {
+		return transitions
+	}
+	return DefaultJiraTransitions
+}
+
+// applyJiraFields sets issue fields from a create or update request
+func applyJiraFields(issue *JiraIssue, fields map[string]any) {
+	for name, value := range fields {
+		switch name {
+		case "project":
+		case "summary":
+			issue.Summary, _ = value.(string)
+		case "description":
+			issue.Description = value
+		case "issuetype":
+			if t, ok := value.(map[string]any); ok {
+				issue.Type, _ = t["name"].(string)
+			}
+		case "assignee":
+			user, _ := value.(map[string]any)
+			issue.Assignee = userID(user)
+		case "labels":
+			issue.Labels = nil
+			if labels, ok := value.([]any); ok {
+				for _, l := range labels {
+					if s, ok := l.(string); ok {
+						issue.Labels = append(issue.Labels, s)
+					}
+				}
+			}
+		case "parent":
+			if p, ok := value.(map[string]any); ok {
+				issue.Parent, _ = p["key"].(string)
+			}
+		default:
+			if issue.Fields == nil {
+				issue.Fields = make(map[string]any)
+			}
+			issue.Fields[name] = value
+		}
+	}
+}
+
+// userID returns the account ID (Cloud) or name (Server) of a user object
+func userID(user map[string]any) string {
+	if id, ok := user["accountId"].(string); ok && id != "" {
+		return id
+	}
+	name, _ := user["name"].(string)
+	return name
+}
+
+// compareIssueKeys orders PROJ-2 before PROJ-10
+func compareIssueKeys(a, b string) int {
+	pa, na, _ := strings.Cut(a, "-")
+	pb, nb, _ := strings.Cut(b, "-")
+	if pa != pb {
+		return strings.Compare(pa, pb)
+	}
+	ia, _ := strconv.Atoi(na)
+	ib, _ := strconv.Atoi(nb)
+	return ia - ib
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeJiraError writes a Jira error response
+func writeJiraError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"errorMessages": []string{message}})
+}
@@ -0,0 +1,132 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/randalmurphal/devflow/jira"
+)
+
+func newJiraClient(t *testing.T, s *JiraServer, maxRetries int) *jira.Client {
+	t.Helper()
+
+	cfg := jira.DefaultConfig()
+	cfg.URL = s.URL
+	cfg.APIVersion = jira.APIVersionV3
+	cfg.Auth = jira.AuthConfig{Type: jira.AuthAPIToken, Email: "bot@example.com", Token: "token"}
+	cfg.RateLimit.MaxRetries = maxRetries
+	cfg.RateLimit.RetryJitter = false
+
+	client, err := jira.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func TestJiraServer_IssueLifecycle(t *testing.T) {
+	s := NewJiraServer(t)
+	s.AddIssue(JiraIssue{Key: "PROJ-1", Summary: "Existing"})
+	client := newJiraClient(t, s, 1)
+	ctx := context.Background()
+
+	issue, err := client.GetIssue(ctx, "PROJ-1")
+	if err != nil {
+		t.Fatalf("GetIssue() error = %v", err)
+	}
+	if issue.Fields.Summary != "Existing" || issue.Fields.Status.Name != "To Do" {
+		t.Errorf("issue = %q in %q", issue.Fields.Summary, issue.Fields.Status.Name)
+	}
+
+	created, err := client.CreateIssue(ctx, &jira.CreateIssueRequest{Fields: jira.CreateIssueFields{
+		Project:   jira.ProjectRef{Key: "PROJ"},
+		IssueType: jira.IssueTypeRef{Name: "Bug"},
+		Summary:   "New",
+		Labels:    []string{"automation"},
+	}})
+	if err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	if created.Key != "PROJ-2" {
+		t.Errorf("created key = %q, want PROJ-2", created.Key)
+	}
+
+	if err := client.TransitionIssueByName(ctx, "PROJ-2", "In Progress"); err != nil {
+		t.Fatalf("TransitionIssueByName() error = %v", err)
+	}
+	if _, err := client.AddComment(ctx, "PROJ-2", "Started"); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+
+	got, ok := s.Issue("PROJ-2")
+	if !ok {
+		t.Fatal("PROJ-2 not stored")
+	}
+	if got.Type != "Bug" || got.Status != "In Progress" || len(got.Labels) != 1 || len(got.Comments) != 1 {
+		t.Errorf("PROJ-2 = %+v", got)
+	}
+
+	results, err := client.SearchIssues(ctx, "project = PROJ", &jira.SearchOptions{MaxResults: 1})
+	if err != nil {
+		t.Fatalf("SearchIssues() error = %v", err)
+	}
+	if results.Total != 2 || len(results.Issues) != 1 || results.Issues[0].Key != "PROJ-1" {
+		t.Errorf("search = total %d, %d issues", results.Total, len(results.Issues))
+	}
+
+	if _, err := client.GetIssue(ctx, "PROJ-99"); !jira.IsNotFound(err) {
+		t.Errorf("GetIssue(PROJ-99) error = %v, want not found", err)
+	}
+}
+
+func TestJiraServer_ScriptedTransitions(t *testing.T) {
+	s := NewJiraServer(t)
+	s.AddIssue(JiraIssue{Key: "PROJ-1"})
+	s.SetTransitions("PROJ-1", JiraTransition{ID: "5", Name: "Start Review", To: "In Review"})
+	client := newJiraClient(t, s, 1)
+	ctx := context.Background()
+
+	if err := client.TransitionIssueByName(ctx, "PROJ-1", "Done"); !errors.Is(err, jira.ErrTransitionNotFound) {
+		t.Errorf("TransitionIssueByName(Done) error = %v, want ErrTransitionNotFound", err)
+	}
+	if err := client.TransitionIssueByName(ctx, "PROJ-1", "Start Review"); err != nil {
+		t.Fatalf("TransitionIssueByName() error = %v", err)
+	}
+	if got, _ := s.Issue("PROJ-1"); got.Status != "In Review" {
+		t.Errorf("status = %q, want In Review", got.Status)
+	}
+}
+
+func TestJiraServer_ThrottleNext(t *testing.T) {
+	s := NewJiraServer(t)
+	s.AddIssue(JiraIssue{Key: "PROJ-1"})
+	ctx := context.Background()
+
+	s.ThrottleNext(1, 0)
+	if _, err := newJiraClient(t, s, 2).GetIssue(ctx, "PROJ-1"); err != nil {
+		t.Fatalf("GetIssue() after one 429 error = %v", err)
+	}
+	if got := len(s.Requests()); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+
+	s.ThrottleNext(5, 0)
+	if _, err := newJiraClient(t, s, 1).GetIssue(ctx, "PROJ-1"); !jira.IsRateLimited(err) {
+		t.Errorf("GetIssue() error = %v, want rate limited", err)
+	}
+}
+
+func TestJiraServer_Unsupported(t *testing.T) {
+	s := NewJiraServer(t)
+
+	resp, err := http.Get(s.URL + "/rest/api/3/dashboard")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("status = %d, want 501", resp.StatusCode)
+	}
+}
@@ -0,0 +1,25 @@
+// Package ticketing provides a common interface over issue trackers so
+// workflows can take tickets from Jira, GitHub Issues, or Linear.
+//
+// Core types:
+//   - Provider: Interface for reading, commenting on, transitioning, and
+//     linking tickets
+//   - Ticket: Tracker-neutral ticket data (also workflow.Ticket)
+//
+// Implementations:
+//   - JiraProvider: Jira Cloud and Server via the jira package
+//   - GitHubProvider: GitHub Issues using go-github
+//   - LinearProvider: Linear via its GraphQL API
+//
+// Example usage:
+//
+//	provider, _ := ticketing.NewGitHubProvider(token, "owner", "repo")
+//	ticket, err := provider.GetTicket(ctx, "#42")
+//	err = provider.Comment(ctx, ticket.ID, "Implementation started")
+//	err = provider.Link(ctx, ticket.ID, pull.HTMLURL, "PR #7")
+//	err = provider.Transition(ctx, ticket.ID, "Done")
+//
+// Comments are Markdown; each provider converts them to its tracker's
+// format. Transition takes a status name: a workflow status for Jira and
+// Linear, "open" or "closed" (or "done") for GitHub.
+package ticketing
@@ -0,0 +1,18 @@
+package ticketing
+
+import "errors"
+
+// Ticketing errors
+var (
+	// ErrNoProvider indicates no ticket provider is configured.
+	ErrNoProvider = errors.New("no ticket provider configured")
+
+	// ErrNotFound indicates the ticket does not exist or is not visible.
+	ErrNotFound = errors.New("ticket not found")
+
+	// ErrInvalidID indicates the ticket ID is malformed for the provider.
+	ErrInvalidID = errors.New("invalid ticket id")
+
+	// ErrTransitionNotFound indicates the ticket cannot move to the status.
+	ErrTransitionNotFound = errors.New("ticket status transition not found")
+)
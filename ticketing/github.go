@@ -0,0 +1,153 @@
+package ticketing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// GitHubProvider implements Provider for GitHub Issues.
+type GitHubProvider struct {
+	client *github.Client
+	owner  string
+	repo   string
+}
+
+// NewGitHubProvider creates a GitHub Issues provider. owner and repo are
+// the default repository for IDs without one ("42" or "#42"); IDs of the
+// form "owner/repo#42" may name any repository.
+func NewGitHubProvider(token, owner, repo string) (*GitHubProvider, error) {
+	if token == "" {
+		return nil, fmt.Errorf("GitHub token is required")
+	}
+	if owner == "" || repo == "" {
+		return nil, fmt.Errorf("owner and repo are required")
+	}
+
+	return &GitHubProvider{
+		client: github.NewClient(nil).WithAuthToken(token),
+		owner:  owner,
+		repo:   repo,
+	}, nil
+}
+
+// Name returns "github".
+func (p *GitHubProvider) Name() string { return "github" }
+
+// GetTicket fetches an issue. Status is "open" or "closed".
+func (p *GitHubProvider) GetTicket(ctx context.Context, id string) (*Ticket, error) {
+	owner, repo, number, err := p.parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	issue, _, err := p.client.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return nil, githubErr(id, err)
+	}
+
+	ticket := &Ticket{
+		ID:          id,
+		Title:       issue.GetTitle(),
+		Description: issue.GetBody(),
+		Status:      issue.GetState(),
+		Assignee:    issue.GetAssignee().GetLogin(),
+		Reporter:    issue.GetUser().GetLogin(),
+		URL:         issue.GetHTMLURL(),
+		Metadata: map[string]string{
+			"repository": owner + "/" + repo,
+			"number":     strconv.Itoa(number),
+		},
+	}
+	for _, label := range issue.Labels {
+		ticket.Labels = append(ticket.Labels, label.GetName())
+	}
+	return ticket, nil
+}
+
+// Comment adds a comment to an issue.
+func (p *GitHubProvider) Comment(ctx context.Context, id, body string) error {
+	owner, repo, number, err := p.parseID(id)
+	if err != nil {
+		return err
+	}
+
+	comment := &github.IssueComment{Body: github.String(body)}
+	if _, _, err := p.client.Issues.CreateComment(ctx, owner, repo, number, comment); err != nil {
+		return githubErr(id, err)
+	}
+	return nil
+}
+
+// Transition opens or closes an issue. "closed" and "done" close it as
+// completed, "not planned" closes it as not planned, and "open" reopens it.
+func (p *GitHubProvider) Transition(ctx context.Context, id, status string) error {
+	owner, repo, number, err := p.parseID(id)
+	if err != nil {
+		return err
+	}
+
+	var req *github.IssueRequest
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "closed", "close", "done", "completed":
+		req = &github.IssueRequest{State: github.String("closed"), StateReason: github.String("completed")}
+	case "not planned", "not_planned":
+		req = &github.IssueRequest{State: github.String("closed"), StateReason: github.String("not_planned")}
+	case "open", "reopen", "reopened":
+		req = &github.IssueRequest{State: github.String("open")}
+	default:
+		return fmt.Errorf("%s to %q: %w", id, status, ErrTransitionNotFound)
+	}
+
+	if _, _, err := p.client.Issues.Edit(ctx, owner, repo, number, req); err != nil {
+		return githubErr(id, err)
+	}
+	return nil
+}
+
+// Link comments with the link, as GitHub issues have no link list.
+// Pull requests that mention the issue are linked by GitHub itself.
+func (p *GitHubProvider) Link(ctx context.Context, id, url, title string) error {
+	if title == "" {
+		title = url
+	}
+	return p.Comment(ctx, id, fmt.Sprintf("Linked: [%s](%s)", title, url))
+}
+
+// parseID splits "owner/repo#42", "#42", or "42" into its parts
+func (p *GitHubProvider) parseID(id string) (owner, repo string, number int, err error) {
+	owner, repo = p.owner, p.repo
+	ref, num, found := strings.Cut(id, "#")
+	if !found {
+		num = ref
+	} else if ref != "" {
+		var ok bool
+		owner, repo, ok = strings.Cut(ref, "/")
+		if !ok || owner == "" || repo == "" {
+			return "", "", 0, fmt.Errorf("%w: %q", ErrInvalidID, id)
+		}
+	}
+
+	number, convErr := strconv.Atoi(num)
+	if convErr != nil || number <= 0 {
+		return "", "", 0, fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	return owner, repo, number, nil
+}
+
+// githubErr maps GitHub 404 and 410 (deleted issue) responses to ErrNotFound
+func githubErr(id string, err error) error {
+	var apiErr *github.ErrorResponse
+	if errors.As(err, &apiErr) && apiErr.Response != nil {
+		switch apiErr.Response.StatusCode {
+		case http.StatusNotFound, http.StatusGone:
+			return fmt.Errorf("%s: %w: %w", id, ErrNotFound, err)
+		}
+	}
+	return fmt.Errorf("%s: %w", id, err)
+}
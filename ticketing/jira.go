@@ -0,0 +1,113 @@
+package ticketing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/randalmurphal/devflow/jira"
+)
+
+// JiraProvider implements Provider for Jira Cloud and Server.
+type JiraProvider struct {
+	client *jira.Client
+}
+
+// NewJiraProvider creates a provider backed by a Jira client.
+func NewJiraProvider(client *jira.Client) *JiraProvider {
+	return &JiraProvider{client: client}
+}
+
+// Name returns "jira".
+func (p *JiraProvider) Name() string { return "jira" }
+
+// GetTicket fetches an issue by key, converting its description to Markdown.
+func (p *JiraProvider) GetTicket(ctx context.Context, id string) (*Ticket, error) {
+	if !jira.ValidateIssueKey(id) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+
+	issue, err := p.client.GetIssue(ctx, id)
+	if err != nil {
+		return nil, jiraErr(id, err)
+	}
+
+	f := &issue.Fields
+	description, err := f.DescriptionMarkdown()
+	if err != nil {
+		return nil, fmt.Errorf("convert description of %s: %w", id, err)
+	}
+
+	ticket := &Ticket{
+		ID:          issue.Key,
+		Title:       f.Summary,
+		Description: description,
+		Status:      f.StatusName(),
+		Priority:    f.PriorityName(),
+		Labels:      f.Labels,
+		URL:         p.client.BrowseURL(issue.Key),
+		Metadata:    map[string]string{"statusCategory": f.StatusCategoryKey()},
+	}
+	if f.IssueType != nil {
+		ticket.Type = f.IssueType.Name
+	}
+	if f.Assignee != nil {
+		ticket.Assignee = f.Assignee.DisplayName
+	}
+	if f.Reporter != nil {
+		ticket.Reporter = f.Reporter.DisplayName
+	}
+	if f.Project != nil {
+		ticket.Metadata["project"] = f.Project.Key
+	}
+	return ticket, nil
+}
+
+// Comment adds a comment, sent as ADF (v3) or Wiki Markup (v2).
+func (p *JiraProvider) Comment(ctx context.Context, id, body string) error {
+	var content any = jira.MarkdownToWiki(body)
+	if p.client.APIVersionInUse() == jira.APIVersionV3 {
+		doc, err := jira.MarkdownToADF(body)
+		if err != nil {
+			return fmt.Errorf("convert comment: %w", err)
+		}
+		content = doc
+	}
+
+	if _, err := p.client.AddComment(ctx, id, content); err != nil {
+		return jiraErr(id, err)
+	}
+	return nil
+}
+
+// Transition executes the transition with the given name.
+func (p *JiraProvider) Transition(ctx context.Context, id, status string) error {
+	err := p.client.TransitionIssueByName(ctx, id, status)
+	if errors.Is(err, jira.ErrTransitionNotFound) {
+		return fmt.Errorf("%s to %q: %w", id, status, ErrTransitionNotFound)
+	}
+	if err != nil {
+		return jiraErr(id, err)
+	}
+	return nil
+}
+
+// Link adds a remote link. Linking the same URL again updates the link.
+func (p *JiraProvider) Link(ctx context.Context, id, url, title string) error {
+	link := &jira.RemoteLink{
+		GlobalID: url,
+		Object:   jira.RemoteLinkObject{URL: url, Title: title},
+	}
+	if _, err := p.client.AddRemoteLink(ctx, id, link); err != nil {
+		return jiraErr(id, err)
+	}
+	return nil
+}
+
+// jiraErr maps Jira not-found errors to ErrNotFound
+func jiraErr(id string, err error) error {
+	if jira.IsNotFound(err) {
+		return fmt.Errorf("%s: %w: %w", id, ErrNotFound, err)
+	}
+	return fmt.Errorf("%s: %w", id, err)
+}
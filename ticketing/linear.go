@@ -0,0 +1,265 @@
+package ticketing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	devhttp "github.com/randalmurphal/devflow/http"
+)
+
+// LinearAPIURL is the base URL of the Linear API.
+const LinearAPIURL = "https://api.linear.app"
+
+// LinearProvider implements Provider for Linear.
+type LinearProvider struct {
+	http *devhttp.Client
+}
+
+// NewLinearProvider creates a Linear provider from a personal API key or
+// an OAuth access token (prefixed with "Bearer ").
+func NewLinearProvider(apiKey string) (*LinearProvider, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("linear API key is required")
+	}
+	return newLinearProvider(LinearAPIURL, apiKey), nil
+}
+
+// newLinearProvider creates a provider for the API at baseURL
+func newLinearProvider(baseURL, apiKey string) *LinearProvider {
+	return &LinearProvider{http: devhttp.NewClient(devhttp.ClientConfig{
+		BaseURL:     baseURL,
+		ServiceName: "linear",
+		BeforeRequest: func(req *http.Request) {
+			req.Header.Set("Authorization", apiKey)
+		},
+	})}
+}
+
+// Name returns "linear".
+func (p *LinearProvider) Name() string { return "linear" }
+
+// linearIssue is the subset of a Linear issue devflow reads
+type linearIssue struct {
+	ID            string `json:"id"`
+	Identifier    string `json:"identifier"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	URL           string `json:"url"`
+	PriorityLabel string `json:"priorityLabel"`
+	State         struct {
+		Name string `json:"name"`
+		Type string `json:"type"` // triage, backlog, unstarted, started, completed, canceled
+	} `json:"state"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Assignee *struct {
+		Name string `json:"name"`
+	} `json:"assignee"`
+	Creator *struct {
+		Name string `json:"name"`
+	} `json:"creator"`
+	Team struct {
+		Key    string `json:"key"`
+		States struct {
+			Nodes []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"nodes"`
+		} `json:"states"`
+	} `json:"team"`
+}
+
+const linearIssueQuery = `query Issue($id: String!) {
+  issue(id: $id) {
+    id identifier title description url priorityLabel
+    state { name type }
+    labels { nodes { name } }
+    assignee { name }
+    creator { name }
+    team { key }
+  }
+}`
+
+const linearStatesQuery = `query IssueStates($id: String!) {
+  issue(id: $id) { id team { states { nodes { id name } } } }
+}`
+
+const linearIssueIDQuery = `query IssueID($id: String!) {
+  issue(id: $id) { id }
+}`
+
+const linearCommentMutation = `mutation CommentCreate($issueId: String!, $body: String!) {
+  commentCreate(input: { issueId: $issueId, body: $body }) { success }
+}`
+
+const linearUpdateStateMutation = `mutation IssueUpdate($id: String!, $stateId: String!) {
+  issueUpdate(id: $id, input: { stateId: $stateId }) { success }
+}`
+
+const linearAttachmentMutation = `mutation AttachmentCreate($issueId: String!, $url: String!, $title: String!) {
+  attachmentCreate(input: { issueId: $issueId, url: $url, title: $title }) { success }
+}`
+
+// GetTicket fetches an issue by identifier (e.g. "ENG-123") or ID.
+func (p *LinearProvider) GetTicket(ctx context.Context, id string) (*Ticket, error) {
+	issue, err := p.issue(ctx, linearIssueQuery, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket := &Ticket{
+		ID:          issue.Identifier,
+		Title:       issue.Title,
+		Description: issue.Description,
+		Status:      issue.State.Name,
+		Priority:    issue.PriorityLabel,
+		URL:         issue.URL,
+		Metadata: map[string]string{
+			"team":      issue.Team.Key,
+			"stateType": issue.State.Type,
+		},
+	}
+	for _, label := range issue.Labels.Nodes {
+		ticket.Labels = append(ticket.Labels, label.Name)
+	}
+	if issue.Assignee != nil {
+		ticket.Assignee = issue.Assignee.Name
+	}
+	if issue.Creator != nil {
+		ticket.Reporter = issue.Creator.Name
+	}
+	return ticket, nil
+}
+
+// Comment adds a comment. Linear comments are Markdown.
+func (p *LinearProvider) Comment(ctx context.Context, id, body string) error {
+	issue, err := p.issue(ctx, linearIssueIDQuery, id)
+	if err != nil {
+		return err
+	}
+	return p.mutate(ctx, id, "commentCreate", linearCommentMutation, map[string]any{
+		"issueId": issue.ID,
+		"body":    body,
+	})
+}
+
+// Transition moves an issue to the workflow state with the given name
+// (case-insensitive) in its team.
+func (p *LinearProvider) Transition(ctx context.Context, id, status string) error {
+	issue, err := p.issue(ctx, linearStatesQuery, id)
+	if err != nil {
+		return err
+	}
+
+	for _, state := range issue.Team.States.Nodes {
+		if strings.EqualFold(state.Name, status) {
+			return p.mutate(ctx, id, "issueUpdate", linearUpdateStateMutation, map[string]any{
+				"id":      issue.ID,
+				"stateId": state.ID,
+			})
+		}
+	}
+	return fmt.Errorf("%s to %q: %w", id, status, ErrTransitionNotFound)
+}
+
+// Link attaches a URL to an issue. Linear shows pull request URLs with
+// their review status.
+func (p *LinearProvider) Link(ctx context.Context, id, url, title string) error {
+	issue, err := p.issue(ctx, linearIssueIDQuery, id)
+	if err != nil {
+		return err
+	}
+	if title == "" {
+		title = url
+	}
+	return p.mutate(ctx, id, "attachmentCreate", linearAttachmentMutation, map[string]any{
+		"issueId": issue.ID,
+		"url":     url,
+		"title":   title,
+	})
+}
+
+// issue runs an issue query
+func (p *LinearProvider) issue(ctx context.Context, query, id string) (*linearIssue, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidID)
+	}
+
+	var data struct {
+		Issue *linearIssue `json:"issue"`
+	}
+	if err := p.graphql(ctx, query, map[string]any{"id": id}, &data); err != nil {
+		return nil, fmt.Errorf("%s: %w", id, err)
+	}
+	if data.Issue == nil {
+		return nil, fmt.Errorf("%s: %w", id, ErrNotFound)
+	}
+	return data.Issue, nil
+}
+
+// mutate runs a mutation whose payload reports success
+func (p *LinearProvider) mutate(ctx context.Context, id, name, mutation string, vars map[string]any) error {
+	var data map[string]struct {
+		Success bool `json:"success"`
+	}
+	if err := p.graphql(ctx, mutation, vars, &data); err != nil {
+		return fmt.Errorf("%s: %s: %w", id, name, err)
+	}
+	if !data[name].Success {
+		return fmt.Errorf("%s: %s was not successful", id, name)
+	}
+	return nil
+}
+
+// linearError is an error from a GraphQL response
+type linearError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Type string `json:"type"` // e.g. "entity not found", "ratelimited"
+	} `json:"extensions"`
+}
+
+// graphql posts a GraphQL request and decodes its data into out
+func (p *LinearProvider) graphql(ctx context.Context, query string, vars map[string]any, out any) error {
+	body := map[string]any{"query": query, "variables": vars}
+	resp, err := p.http.Request(ctx, http.MethodPost, "/graphql", body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// GraphQL errors come with 200 or 400, so check the body first
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []linearError   `json:"errors"`
+	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&envelope)
+	if len(envelope.Errors) > 0 {
+		first := envelope.Errors[0]
+		err := errors.New("linear: " + first.Message)
+		if strings.Contains(strings.ToLower(first.Extensions.Type+" "+first.Message), "not found") {
+			return fmt.Errorf("%w: %w", ErrNotFound, err)
+		}
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return &devhttp.APIError{
+			Service:    "linear",
+			StatusCode: resp.StatusCode,
+			Message:    http.StatusText(resp.StatusCode),
+			Endpoint:   "/graphql",
+			RequestID:  resp.Header.Get("X-Request-Id"),
+		}
+	}
+	if decodeErr != nil {
+		return fmt.Errorf("decode linear response: %w", decodeErr)
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
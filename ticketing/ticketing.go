@@ -0,0 +1,57 @@
+package ticketing
+
+import "context"
+
+// Ticket represents input ticket data from an issue tracker
+type Ticket struct {
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"` // Markdown
+	Status      string            `json:"status,omitempty"`
+	Priority    string            `json:"priority,omitempty"`
+	Type        string            `json:"type,omitempty"` // bug, feature, task, etc.
+	Labels      []string          `json:"labels,omitempty"`
+	Assignee    string            `json:"assignee,omitempty"`
+	Reporter    string            `json:"reporter,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// Provider reads and updates tickets in an issue tracker.
+type Provider interface {
+	// Name identifies the tracker: "jira", "github", or "linear".
+	Name() string
+
+	// GetTicket fetches a ticket by its tracker ID (e.g. "PROJ-123",
+	// "owner/repo#42", "ENG-7").
+	GetTicket(ctx context.Context, id string) (*Ticket, error)
+
+	// Comment adds a Markdown comment to a ticket.
+	Comment(ctx context.Context, id, body string) error
+
+	// Transition moves a ticket to the named status.
+	Transition(ctx context.Context, id, status string) error
+
+	// Link attaches a web link, such as a pull request, to a ticket.
+	Link(ctx context.Context, id, url, title string) error
+}
+
+// contextKey is a private type for context keys to avoid collisions.
+type contextKey struct{ name string }
+
+var providerKey = &contextKey{"ticket-provider"}
+
+// ContextWithProvider adds a ticket Provider to a context.Context.
+// Use ProviderFromContext to retrieve it.
+func ContextWithProvider(ctx context.Context, p Provider) context.Context {
+	return context.WithValue(ctx, providerKey, p)
+}
+
+// ProviderFromContext retrieves a ticket Provider from a context.Context.
+// Returns nil if no Provider is present.
+func ProviderFromContext(ctx context.Context) Provider {
+	if p, ok := ctx.Value(providerKey).(Provider); ok {
+		return p
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package ticketing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/randalmurphal/devflow/jira"
+	"github.com/randalmurphal/devflow/testutil"
+)
+
+// Compile-time interface checks
+var (
+	_ Provider = (*JiraProvider)(nil)
+	_ Provider = (*GitHubProvider)(nil)
+	_ Provider = (*LinearProvider)(nil)
+)
+
+func TestContextWithProvider(t *testing.T) {
+	if ProviderFromContext(context.Background()) != nil {
+		t.Error("ProviderFromContext(empty) should be nil")
+	}
+	p := &LinearProvider{}
+	ctx := ContextWithProvider(context.Background(), p)
+	if ProviderFromContext(ctx) != p {
+		t.Error("ProviderFromContext() did not return the stored provider")
+	}
+}
+
+func TestJiraProvider(t *testing.T) {
+	server := testutil.NewJiraServer(t)
+	server.AddIssue(testutil.JiraIssue{
+		Key:         "PROJ-1",
+		Summary:     "Add login",
+		Type:        "Story",
+		Description: map[string]any{"type": "doc", "version": 1, "content": []any{}},
+		Labels:      []string{"auth"},
+	})
+
+	cfg := jira.DefaultConfig()
+	cfg.URL = server.URL
+	cfg.APIVersion = jira.APIVersionV3
+	cfg.Auth = jira.AuthConfig{Type: jira.AuthAPIToken, Email: "bot@example.com", Token: "token"}
+	client, err := jira.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	p := NewJiraProvider(client)
+	ctx := context.Background()
+
+	ticket, err := p.GetTicket(ctx, "PROJ-1")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	if ticket.Title != "Add login" || ticket.Type != "Story" || ticket.Status != "To Do" {
+		t.Errorf("ticket = %+v", ticket)
+	}
+	if ticket.URL != server.URL+"/browse/PROJ-1" {
+		t.Errorf("URL = %q", ticket.URL)
+	}
+
+	if err := p.Comment(ctx, "PROJ-1", "Started **work**"); err != nil {
+		t.Fatalf("Comment() error = %v", err)
+	}
+	if err := p.Transition(ctx, "PROJ-1", "Done"); err != nil {
+		t.Fatalf("Transition() error = %v", err)
+	}
+	if err := p.Transition(ctx, "PROJ-1", "Shipped"); !errors.Is(err, ErrTransitionNotFound) {
+		t.Errorf("Transition(unknown) error = %v, want ErrTransitionNotFound", err)
+	}
+	issue, _ := server.Issue("PROJ-1")
+	if issue.Status != "Done" || len(issue.Comments) != 1 {
+		t.Errorf("issue = %+v", issue)
+	}
+	if _, ok := issue.Comments[0].(map[string]any); !ok {
+		t.Errorf("v3 comment body = %T, want ADF document", issue.Comments[0])
+	}
+
+	if _, err := p.GetTicket(ctx, "PROJ-404"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTicket(missing) error = %v, want ErrNotFound", err)
+	}
+	if _, err := p.GetTicket(ctx, "not a key"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("GetTicket(invalid) error = %v, want ErrInvalidID", err)
+	}
+}
+
+func TestGitHubProvider_ParseID(t *testing.T) {
+	p := &GitHubProvider{owner: "acme", repo: "app"}
+	tests := []struct {
+		id     string
+		owner  string
+		repo   string
+		number int
+		ok     bool
+	}{
+		{"42", "acme", "app", 42, true},
+		{"#42", "acme", "app", 42, true},
+		{"other/lib#7", "other", "lib", 7, true},
+		{"other#7", "", "", 0, false},
+		{"#x", "", "", 0, false},
+		{"0", "", "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			owner, repo, number, err := p.parseID(tt.id)
+			if tt.ok != (err == nil) {
+				t.Fatalf("parseID() error = %v", err)
+			}
+			if tt.ok && (owner != tt.owner || repo != tt.repo || number != tt.number) {
+				t.Errorf("parseID() = %s/%s#%d", owner, repo, number)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidID) {
+				t.Errorf("error = %v, want ErrInvalidID", err)
+			}
+		})
+	}
+}
+
+func newTestGitHubProvider(t *testing.T, handler http.Handler) *GitHubProvider {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	p, err := NewGitHubProvider("token", "acme", "app")
+	if err != nil {
+		t.Fatalf("NewGitHubProvider() error = %v", err)
+	}
+	p.client.BaseURL, _ = url.Parse(server.URL + "/")
+	return p
+}
+
+func TestGitHubProvider(t *testing.T) {
+	var edits []map[string]any
+	var comments []string
+	p := newTestGitHubProvider(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/app/issues/42":
+			_, _ = w.Write([]byte(`{"number":42,"title":"Crash on start","body":"Steps...","state":"open",
+				"labels":[{"name":"bug"}],"user":{"login":"reporter"},"assignee":{"login":"dev"},
+				"html_url":"https://github.com/acme/app/issues/42"}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/app/issues/42":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			edits = append(edits, body)
+			_, _ = w.Write([]byte(`{"number":42}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/app/issues/42/comments":
+			var body struct {
+				Body string `json:"body"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			comments = append(comments, body.Body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	ctx := context.Background()
+
+	ticket, err := p.GetTicket(ctx, "#42")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	if ticket.Title != "Crash on start" || ticket.Status != "open" || ticket.Assignee != "dev" ||
+		ticket.Reporter != "reporter" || len(ticket.Labels) != 1 {
+		t.Errorf("ticket = %+v", ticket)
+	}
+
+	if err := p.Transition(ctx, "42", "Done"); err != nil {
+		t.Fatalf("Transition() error = %v", err)
+	}
+	if len(edits) != 1 || edits[0]["state"] != "closed" || edits[0]["state_reason"] != "completed" {
+		t.Errorf("edits = %v", edits)
+	}
+	if err := p.Transition(ctx, "42", "In Review"); !errors.Is(err, ErrTransitionNotFound) {
+		t.Errorf("Transition(In Review) error = %v, want ErrTransitionNotFound", err)
+	}
+
+	if err := p.Link(ctx, "42", "https://github.com/acme/app/pull/7", "PR #7"); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "[PR #7](https://github.com/acme/app/pull/7)") {
+		t.Errorf("comments = %q", comments)
+	}
+
+	if _, err := p.GetTicket(ctx, "#404"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTicket(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLinearProvider(t *testing.T) {
+	var mutations []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "lin_api_key" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		switch {
+		case req.Variables["id"] == "ENG-404":
+			_, _ = w.Write([]byte(`{"data":null,"errors":[{"message":"Entity not found: Issue","extensions":{"type":"invalid input"}}]}`))
+		case strings.HasPrefix(req.Query, "query Issue("):
+			_, _ = w.Write([]byte(`{"data":{"issue":{"id":"uuid-1","identifier":"ENG-7","title":"Sync fails",
+				"description":"Details","url":"https://linear.app/acme/issue/ENG-7","priorityLabel":"High",
+				"state":{"name":"Todo","type":"unstarted"},"labels":{"nodes":[{"name":"backend"}]},
+				"assignee":{"name":"Dev"},"creator":{"name":"PM"},"team":{"key":"ENG"}}}}`))
+		case strings.HasPrefix(req.Query, "query IssueStates"):
+			_, _ = w.Write([]byte(`{"data":{"issue":{"id":"uuid-1","team":{"states":{"nodes":[
+				{"id":"s1","name":"Todo"},{"id":"s2","name":"In Progress"},{"id":"s3","name":"Done"}]}}}}}`))
+		case strings.HasPrefix(req.Query, "query IssueID"):
+			_, _ = w.Write([]byte(`{"data":{"issue":{"id":"uuid-1"}}}`))
+		case strings.HasPrefix(req.Query, "mutation "):
+			mutations = append(mutations, req.Variables)
+			name := strings.Fields(req.Query)[1]
+			name = strings.ToLower(name[:1]) + name[1:strings.Index(name, "(")]
+			_, _ = w.Write([]byte(`{"data":{"` + name + `":{"success":true}}}`))
+		default:
+			t.Errorf("unexpected query %q", req.Query)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	p := newLinearProvider(server.URL, "lin_api_key")
+	ctx := context.Background()
+
+	ticket, err := p.GetTicket(ctx, "ENG-7")
+	if err != nil {
+		t.Fatalf("GetTicket() error = %v", err)
+	}
+	if ticket.ID != "ENG-7" || ticket.Status != "Todo" || ticket.Priority != "High" ||
+		ticket.Assignee != "Dev" || ticket.Metadata["team"] != "ENG" {
+		t.Errorf("ticket = %+v", ticket)
+	}
+
+	if err := p.Transition(ctx, "ENG-7", "in progress"); err != nil {
+		t.Fatalf("Transition() error = %v", err)
+	}
+	if err := p.Transition(ctx, "ENG-7", "Shipped"); !errors.Is(err, ErrTransitionNotFound) {
+		t.Errorf("Transition(unknown) error = %v, want ErrTransitionNotFound", err)
+	}
+	if err := p.Comment(ctx, "ENG-7", "Started"); err != nil {
+		t.Fatalf("Comment() error = %v", err)
+	}
+	if err := p.Link(ctx, "ENG-7", "https://github.com/acme/app/pull/7", ""); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+
+	if len(mutations) != 3 {
+		t.Fatalf("mutations = %v", mutations)
+	}
+	if mutations[0]["stateId"] != "s2" || mutations[1]["issueId"] != "uuid-1" || mutations[2]["title"] != "https://github.com/acme/app/pull/7" {
+		t.Errorf("mutations = %v", mutations)
+	}
+
+	if _, err := p.GetTicket(ctx, "ENG-404"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTicket(missing) error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,112 @@
+package transcript
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression selects how large transcripts are compressed on disk
+type Compression string
+
+const (
+	CompressionGzip Compression = "gzip" // transcript.json.gz (default)
+	CompressionZstd Compression = "zstd" // transcript.json.zst
+	CompressionNone Compression = "none" // always transcript.json
+)
+
+// DefaultCompressionThreshold is the size above which transcripts are compressed
+const DefaultCompressionThreshold = 100 * 1024 // 100KB
+
+const transcriptFile = "transcript.json"
+
+// transcriptFiles lists the on-disk variants of a transcript in the order
+// Load tries them. Save removes all variants but the one it writes.
+var transcriptFiles = []struct {
+	name        string
+	compression Compression
+}{
+	{transcriptFile + ".zst", CompressionZstd},
+	{transcriptFile + ".gz", CompressionGzip},
+	{transcriptFile, CompressionNone},
+}
+
+// fileFor returns the transcript file name for a compression
+func (c Compression) fileFor() (string, error) {
+	switch c {
+	case CompressionGzip, "":
+		return transcriptFile + ".gz", nil
+	case CompressionZstd:
+		return transcriptFile + ".zst", nil
+	case CompressionNone:
+		return transcriptFile, nil
+	default:
+		return "", fmt.Errorf("unknown compression %q", c)
+	}
+}
+
+// compress encodes data with c
+func (c Compression) compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch c {
+	case CompressionGzip, "":
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(data); err != nil {
+			return nil, err
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+	case CompressionZstd:
+		enc, err := zstd.NewWriter(&buf)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := enc.Write(data); err != nil {
+			_ = enc.Close()
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+	case CompressionNone:
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unknown compression %q", c)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// readTranscriptFile reads and decodes a transcript file written with c
+func readTranscriptFile(path string, c Compression) ([]byte, error) {
+	f, err := os.Open(path) //nolint:gosec // path built from store base dir
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	switch c {
+	case CompressionGzip:
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = gz.Close() }()
+		return io.ReadAll(gz)
+	case CompressionZstd:
+		dec, err := zstd.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer dec.Close()
+		return io.ReadAll(dec)
+	default:
+		return io.ReadAll(f)
+	}
+}
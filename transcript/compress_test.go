@@ -0,0 +1,79 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveWith_CompressionRoundTrip(t *testing.T) {
+	tests := []struct {
+		compression Compression
+		threshold   int
+		wantFile    string
+	}{
+		{CompressionGzip, -1, "transcript.json.gz"},
+		{CompressionZstd, -1, "transcript.json.zst"},
+		{CompressionNone, -1, "transcript.json"},
+		{"", -1, "transcript.json.gz"},                // default is gzip
+		{CompressionZstd, 1 << 20, "transcript.json"}, // below threshold
+	}
+
+	content := strings.Repeat("compressible transcript content ", 200)
+	for _, tt := range tests {
+		t.Run(string(tt.compression)+"/"+tt.wantFile, func(t *testing.T) {
+			dir := t.TempDir()
+			tr := NewTranscript("run-1", "flow")
+			tr.Turns = append(tr.Turns, Turn{ID: 1, Role: "assistant", Content: content})
+
+			if err := tr.SaveWith(dir, tt.compression, tt.threshold); err != nil {
+				t.Fatalf("SaveWith() error = %v", err)
+			}
+
+			runDir := filepath.Join(dir, "runs", "run-1")
+			for _, variant := range transcriptFiles {
+				_, err := os.Stat(filepath.Join(runDir, variant.name))
+				if exists := err == nil; exists != (variant.name == tt.wantFile) {
+					t.Errorf("%s exists = %v, want only %s", variant.name, exists, tt.wantFile)
+				}
+			}
+
+			loaded, err := Load(dir, "run-1")
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if len(loaded.Turns) != 1 || loaded.Turns[0].Content != content {
+				t.Errorf("Load() did not round-trip the turn content")
+			}
+		})
+	}
+}
+
+func TestSaveWith_SwitchingCompressionRemovesOldVariant(t *testing.T) {
+	dir := t.TempDir()
+	tr := NewTranscript("run-1", "flow")
+	tr.Turns = append(tr.Turns, Turn{ID: 1, Role: "user", Content: "first"})
+	if err := tr.SaveWith(dir, CompressionGzip, -1); err != nil {
+		t.Fatalf("SaveWith(gzip) error = %v", err)
+	}
+
+	tr.Turns[0].Content = "second"
+	if err := tr.SaveWith(dir, CompressionZstd, -1); err != nil {
+		t.Fatalf("SaveWith(zstd) error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "runs", "run-1", "transcript.json.gz")); !os.IsNotExist(err) {
+		t.Errorf("stale gzip variant kept: %v", err)
+	}
+
+	loaded, err := Load(dir, "run-1")
+	if err != nil || loaded.Turns[0].Content != "second" {
+		t.Errorf("Load() = %+v, %v; want the zstd variant", loaded, err)
+	}
+}
+
+func TestNewFileStore_UnknownCompression(t *testing.T) {
+	if _, err := NewFileStore(StoreConfig{BaseDir: t.TempDir(), Compression: "brotli"}); err == nil {
+		t.Error("NewFileStore() error = nil, want unknown compression")
+	}
+}
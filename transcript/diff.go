@@ -0,0 +1,405 @@
+package transcript
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DiffKind classifies an aligned pair of turns or tool calls
+type DiffKind string
+
+const (
+	DiffSame    DiffKind = "same"
+	DiffChanged DiffKind = "changed"
+	DiffAdded   DiffKind = "added"   // Only in B
+	DiffRemoved DiffKind = "removed" // Only in A
+)
+
+// RunDiff is a structural comparison of two transcripts, typically the same
+// flow run with a different model or prompt version.
+type RunDiff struct {
+	A, B Meta
+
+	TokensInDelta  int
+	TokensOutDelta int
+	CostDelta      float64
+	DurationDelta  time.Duration
+
+	Turns    []TurnDiff
+	Verdicts []VerdictDiff // Only verdicts that differ
+}
+
+// TurnDiff compares a pair of aligned turns. A is nil for added turns and B
+// is nil for removed turns.
+type TurnDiff struct {
+	Kind DiffKind
+	A, B *Turn
+
+	TokensInDelta  int
+	TokensOutDelta int
+	ToolCalls      []ToolCallDiff // Only tool calls that differ
+	ContentDiff    []string       // Line diff ("-", "+", " " prefixed) for changed content
+}
+
+// ToolCallDiff compares tool calls at the same position in aligned turns
+type ToolCallDiff struct {
+	Kind          DiffKind
+	Name          string
+	InputChanged  bool
+	OutputChanged bool
+	ErrorChanged  bool
+}
+
+// VerdictDiff reports an outcome that differs between runs: a review
+// verdict or a node's completed/failed status
+type VerdictDiff struct {
+	Key  string
+	A, B string // Empty if the run has no such verdict
+}
+
+// Changed reports whether the runs differ in any turn or verdict
+func (d *RunDiff) Changed() bool {
+	if len(d.Verdicts) > 0 {
+		return true
+	}
+	for _, td := range d.Turns {
+		if td.Kind != DiffSame {
+			return true
+		}
+	}
+	return false
+}
+
+// maxContentDiffLines bounds the line diff; larger contents are only
+// reported as changed
+const maxContentDiffLines = 2000
+
+// DiffTranscripts aligns the turns of a and b and compares them.
+//
+// Turns are aligned by a longest-common-subsequence over roles that prefers
+// pairing turns with identical content, so an inserted or dropped turn does
+// not shift every later comparison.
+func DiffTranscripts(a, b *Transcript) *RunDiff {
+	d := &RunDiff{
+		A:              a.Metadata,
+		B:              b.Metadata,
+		TokensInDelta:  b.Metadata.TotalTokensIn - a.Metadata.TotalTokensIn,
+		TokensOutDelta: b.Metadata.TotalTokensOut - a.Metadata.TotalTokensOut,
+		CostDelta:      b.Metadata.TotalCost - a.Metadata.TotalCost,
+		DurationDelta:  b.Duration() - a.Duration(),
+	}
+	d.A.RunID = a.RunID
+	d.B.RunID = b.RunID
+
+	for _, pair := range alignTurns(a.Turns, b.Turns) {
+		d.Turns = append(d.Turns, diffTurn(pair[0], pair[1]))
+	}
+
+	d.Verdicts = diffVerdicts(extractVerdicts(a), extractVerdicts(b))
+	return d
+}
+
+// alignTurns returns aligned (a, b) pairs; either side may be nil
+func alignTurns(a, b []Turn) [][2]*Turn {
+	n, m := len(a), len(b)
+
+	score := func(x, y *Turn) int {
+		if x.Role != y.Role {
+			return 0
+		}
+		if x.Content == y.Content {
+			return 3
+		}
+		return 2
+	}
+
+	// best[i][j] is the best alignment score of a[i:] and b[j:]
+	best := make([][]int, n+1)
+	for i := range best {
+		best[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			v := max(best[i+1][j], best[i][j+1])
+			if s := score(&a[i], &b[j]); s > 0 {
+				v = max(v, s+best[i+1][j+1])
+			}
+			best[i][j] = v
+		}
+	}
+
+	var pairs [][2]*Turn
+	i, j := 0, 0
+	for i < n && j < m {
+		s := score(&a[i], &b[j])
+		switch {
+		case s > 0 && best[i][j] == s+best[i+1][j+1]:
+			pairs = append(pairs, [2]*Turn{&a[i], &b[j]})
+			i++
+			j++
+		case best[i][j] == best[i+1][j]:
+			pairs = append(pairs, [2]*Turn{&a[i], nil})
+			i++
+		default:
+			pairs = append(pairs, [2]*Turn{nil, &b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		pairs = append(pairs, [2]*Turn{&a[i], nil})
+	}
+	for ; j < m; j++ {
+		pairs = append(pairs, [2]*Turn{nil, &b[j]})
+	}
+	return pairs
+}
+
+func diffTurn(a, b *Turn) TurnDiff {
+	switch {
+	case a == nil:
+		return TurnDiff{Kind: DiffAdded, B: b, TokensInDelta: b.TokensIn, TokensOutDelta: b.TokensOut}
+	case b == nil:
+		return TurnDiff{Kind: DiffRemoved, A: a, TokensInDelta: -a.TokensIn, TokensOutDelta: -a.TokensOut}
+	}
+
+	td := TurnDiff{
+		Kind:           DiffSame,
+		A:              a,
+		B:              b,
+		TokensInDelta:  b.TokensIn - a.TokensIn,
+		TokensOutDelta: b.TokensOut - a.TokensOut,
+		ToolCalls:      diffToolCalls(a.ToolCalls, b.ToolCalls),
+	}
+
+	if a.Content != b.Content {
+		td.ContentDiff = diffLines(a.Content, b.Content)
+	}
+	if a.Content != b.Content || len(td.ToolCalls) > 0 {
+		td.Kind = DiffChanged
+	}
+	return td
+}
+
+func diffToolCalls(a, b []ToolCall) []ToolCallDiff {
+	var diffs []ToolCallDiff
+	for i := 0; i < max(len(a), len(b)); i++ {
+		switch {
+		case i >= len(a):
+			diffs = append(diffs, ToolCallDiff{Kind: DiffAdded, Name: b[i].Name})
+		case i >= len(b):
+			diffs = append(diffs, ToolCallDiff{Kind: DiffRemoved, Name: a[i].Name})
+		case a[i].Name != b[i].Name:
+			diffs = append(diffs,
+				ToolCallDiff{Kind: DiffRemoved, Name: a[i].Name},
+				ToolCallDiff{Kind: DiffAdded, Name: b[i].Name})
+		default:
+			tc := ToolCallDiff{
+				Kind:          DiffChanged,
+				Name:          a[i].Name,
+				InputChanged:  !reflect.DeepEqual(a[i].Input, b[i].Input),
+				OutputChanged: a[i].Output != b[i].Output,
+				ErrorChanged:  a[i].Error != b[i].Error,
+			}
+			if tc.InputChanged || tc.OutputChanged || tc.ErrorChanged {
+				diffs = append(diffs, tc)
+			}
+		}
+	}
+	return diffs
+}
+
+// diffLines returns a line diff of a and b. Unchanged lines are included so
+// the changes read in context.
+func diffLines(a, b string) []string {
+	la := strings.Split(a, "\n")
+	lb := strings.Split(b, "\n")
+	if len(la) > maxContentDiffLines || len(lb) > maxContentDiffLines {
+		return nil
+	}
+
+	n, m := len(la), len(lb)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if la[i] == lb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case la[i] == lb[j]:
+			out = append(out, " "+la[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "-"+la[i])
+			i++
+		default:
+			out = append(out, "+"+lb[j])
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		out = append(out, "-"+la[i])
+	}
+	for ; j < m; j++ {
+		out = append(out, "+"+lb[j])
+	}
+	return out
+}
+
+var (
+	reviewVerdictPattern  = regexp.MustCompile(`"verdict"\s*:\s*"([^"]+)"`)
+	reviewApprovedPattern = regexp.MustCompile(`"approved"\s*:\s*(true|false)`)
+	nodeOutcomePattern    = regexp.MustCompile(`^Node (\S+) (completed|failed)`)
+)
+
+// extractVerdicts collects the outcomes of a run: the last review verdict
+// found in assistant output, and each node's completed/failed status as
+// recorded by workflow.WithTranscript.
+func extractVerdicts(t *Transcript) map[string]string {
+	verdicts := map[string]string{"status": string(t.Metadata.Status)}
+
+	for _, turn := range t.Turns {
+		switch turn.Role {
+		case "assistant":
+			if m := reviewVerdictPattern.FindStringSubmatch(turn.Content); m != nil {
+				verdicts["review"] = m[1]
+			} else if m := reviewApprovedPattern.FindStringSubmatch(turn.Content); m != nil {
+				verdicts["review"] = map[string]string{"true": "APPROVE", "false": "REQUEST_CHANGES"}[m[1]]
+			}
+		case "system":
+			if m := nodeOutcomePattern.FindStringSubmatch(turn.Content); m != nil {
+				verdicts["node:"+m[1]] = m[2]
+			}
+		}
+	}
+	return verdicts
+}
+
+func diffVerdicts(a, b map[string]string) []VerdictDiff {
+	keys := make(map[string]struct{})
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+
+	var diffs []VerdictDiff
+	for k := range keys {
+		if a[k] != b[k] {
+			diffs = append(diffs, VerdictDiff{Key: k, A: a[k], B: b[k]})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Key < diffs[j].Key })
+	return diffs
+}
+
+// ExportDiffMarkdown writes a structural diff of two transcripts as markdown
+func (v *Viewer) ExportDiffMarkdown(w io.Writer, a, b *Transcript) error {
+	d := DiffTranscripts(a, b)
+
+	fmt.Fprintf(w, "# Transcript Diff: %s vs %s\n\n", a.RunID, b.RunID)
+
+	fmt.Fprintf(w, "## Summary\n\n")
+	fmt.Fprintf(w, "| Field | A | B | Delta |\n")
+	fmt.Fprintf(w, "|-------|---|---|-------|\n")
+	fmt.Fprintf(w, "| Flow | %s | %s | |\n", a.Metadata.FlowID, b.Metadata.FlowID)
+	fmt.Fprintf(w, "| Status | %s | %s | |\n", a.Metadata.Status, b.Metadata.Status)
+	fmt.Fprintf(w, "| Turns | %d | %d | %+d |\n", len(a.Turns), len(b.Turns), len(b.Turns)-len(a.Turns))
+	fmt.Fprintf(w, "| Tokens In | %d | %d | %+d |\n", a.Metadata.TotalTokensIn, b.Metadata.TotalTokensIn, d.TokensInDelta)
+	fmt.Fprintf(w, "| Tokens Out | %d | %d | %+d |\n", a.Metadata.TotalTokensOut, b.Metadata.TotalTokensOut, d.TokensOutDelta)
+	fmt.Fprintf(w, "| Cost | $%.4f | $%.4f | %+.4f |\n", a.Metadata.TotalCost, b.Metadata.TotalCost, d.CostDelta)
+	fmt.Fprintf(w, "| Duration | %s | %s | %s |\n",
+		a.Duration().Round(time.Second), b.Duration().Round(time.Second), d.DurationDelta.Round(time.Second))
+	fmt.Fprintln(w)
+
+	if len(d.Verdicts) > 0 {
+		fmt.Fprintf(w, "## Verdicts\n\n")
+		fmt.Fprintf(w, "| Verdict | A | B |\n")
+		fmt.Fprintf(w, "|---------|---|---|\n")
+		for _, vd := range d.Verdicts {
+			fmt.Fprintf(w, "| %s | %s | %s |\n", vd.Key, orDash(vd.A), orDash(vd.B))
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "## Turns\n\n")
+	if !d.Changed() {
+		fmt.Fprintf(w, "All %d turns are identical.\n", len(d.Turns))
+		return nil
+	}
+
+	for _, td := range d.Turns {
+		switch td.Kind {
+		case DiffSame:
+			fmt.Fprintf(w, "- Turn %d/%d %s: identical\n", td.A.ID, td.B.ID, td.A.Role)
+		case DiffAdded:
+			fmt.Fprintf(w, "- **Added** turn %d %s (%d chars, %+d/%+d tokens)\n",
+				td.B.ID, td.B.Role, len(td.B.Content), td.TokensInDelta, td.TokensOutDelta)
+		case DiffRemoved:
+			fmt.Fprintf(w, "- **Removed** turn %d %s (%d chars, %+d/%+d tokens)\n",
+				td.A.ID, td.A.Role, len(td.A.Content), td.TokensInDelta, td.TokensOutDelta)
+		case DiffChanged:
+			fmt.Fprintf(w, "- **Changed** turn %d/%d %s (%+d/%+d tokens)\n",
+				td.A.ID, td.B.ID, td.A.Role, td.TokensInDelta, td.TokensOutDelta)
+			for _, tc := range td.ToolCalls {
+				fmt.Fprintf(w, "  - Tool `%s`: %s\n", tc.Name, describeToolCallDiff(tc))
+			}
+			if td.ContentDiff != nil {
+				fmt.Fprintf(w, "\n  ```diff\n")
+				for _, line := range td.ContentDiff {
+					fmt.Fprintf(w, "  %s\n", line)
+				}
+				fmt.Fprintf(w, "  ```\n\n")
+			} else if td.A.Content != td.B.Content {
+				fmt.Fprintf(w, "  - Content changed (%d vs %d chars)\n", len(td.A.Content), len(td.B.Content))
+			}
+		}
+	}
+
+	return nil
+}
+
+func describeToolCallDiff(tc ToolCallDiff) string {
+	switch tc.Kind {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	}
+
+	var parts []string
+	if tc.InputChanged {
+		parts = append(parts, "input")
+	}
+	if tc.OutputChanged {
+		parts = append(parts, "output")
+	}
+	if tc.ErrorChanged {
+		parts = append(parts, "error")
+	}
+	return strings.Join(parts, ", ") + " changed"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
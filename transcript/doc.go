@@ -5,7 +5,12 @@
 //   - Turn: A single message in a conversation (user, assistant, or tool)
 //   - Manager: Interface for transcript lifecycle management
 //   - FileStore: File-based transcript storage implementation
-//   - Searcher: Grep-based transcript search
+//   - SQLiteStore: SQLite-backed storage with indexed queries
+//   - ObjectStore: Object storage with local write buffer
+//   - S3Storage: ObjectStorage for S3-compatible services (S3, MinIO, GCS)
+//   - Redactor: Secret scrubbing applied before turns are stored
+//   - Searcher: Transcript search (index-backed, falling back to grep)
+//   - Index: Embedded inverted index maintained on RecordTurn
 //   - Viewer: Transcript display and export
 //
 // Example usage:
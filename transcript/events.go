@@ -0,0 +1,243 @@
+package transcript
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/randalmurphal/devflow/notify"
+)
+
+// EventStore wraps a Manager and emits run lifecycle events: run started,
+// turn recorded, and run completed/failed. Events are delivered after the
+// wrapped store succeeds; delivery errors are logged and never fail the
+// store operation.
+//
+// Turn events carry turn metadata (ID, role, tokens, model, cost) but not
+// content, which may not have been redacted yet. Consumers that index
+// content should Load the run.
+type EventStore struct {
+	Manager
+
+	notifier notify.Notifier
+	timeout  time.Duration
+
+	mu    sync.Mutex
+	runs  map[string]RunMetadata
+	turns map[string]int
+}
+
+// NewEventStore wraps inner so lifecycle events are sent to notifier. Use
+// notify.NotifierFunc to emit to a callback.
+func NewEventStore(inner Manager, notifier notify.Notifier) *EventStore {
+	return &EventStore{
+		Manager:  inner,
+		notifier: notifier,
+		timeout:  10 * time.Second,
+		runs:     make(map[string]RunMetadata),
+		turns:    make(map[string]int),
+	}
+}
+
+// Unwrap returns the wrapped Manager
+func (s *EventStore) Unwrap() Manager {
+	return s.Manager
+}
+
+// StartRun starts the run and emits notify.EventRunStarted
+func (s *EventStore) StartRun(runID string, meta RunMetadata) error {
+	if err := s.Manager.StartRun(runID, meta); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.runs[runID] = meta
+	s.turns[runID] = 0
+	s.mu.Unlock()
+
+	s.emit(notify.Event{
+		Type:     notify.EventRunStarted,
+		RunID:    runID,
+		FlowID:   meta.FlowID,
+		NodeID:   meta.NodeID,
+		Message:  fmt.Sprintf("Run %s started", runID),
+		Severity: notify.SeverityInfo,
+	})
+	return nil
+}
+
+// RecordTurn records the turn and emits notify.EventTurnRecorded
+func (s *EventStore) RecordTurn(runID string, turn Turn) error {
+	if err := s.Manager.RecordTurn(runID, turn); err != nil {
+		return err
+	}
+
+	// Turn IDs are only known for runs started through this store
+	s.mu.Lock()
+	meta, tracked := s.runs[runID]
+	turnID := 0
+	if tracked {
+		s.turns[runID]++
+		turnID = s.turns[runID]
+	}
+	s.mu.Unlock()
+
+	metadata := map[string]any{
+		"role":      turn.Role,
+		"tokensIn":  turn.TokensIn,
+		"tokensOut": turn.TokensOut,
+		"toolCalls": len(turn.ToolCalls),
+	}
+	if tracked {
+		metadata["turnId"] = turnID
+	}
+	if turn.Model != "" {
+		metadata["model"] = turn.Model
+	}
+	if turn.Cost > 0 {
+		metadata["cost"] = turn.Cost
+	}
+
+	s.emit(notify.Event{
+		Type:     notify.EventTurnRecorded,
+		RunID:    runID,
+		FlowID:   meta.FlowID,
+		NodeID:   meta.NodeID,
+		Message:  fmt.Sprintf("%s turn recorded", turn.Role),
+		Severity: notify.SeverityInfo,
+		Metadata: metadata,
+	})
+	return nil
+}
+
+// RecordToolCall adds a tool call to the last turn, if the wrapped store supports it
+func (s *EventStore) RecordToolCall(runID string, tc ToolCall) error {
+	r, ok := s.Manager.(interface {
+		RecordToolCall(runID string, tc ToolCall) error
+	})
+	if !ok {
+		return fmt.Errorf("%T does not support RecordToolCall", s.Manager)
+	}
+	return r.RecordToolCall(runID, tc)
+}
+
+// AddCost adds cost not attributed to any turn, if the wrapped store
+// supports it; see FileStore.AddCost
+func (s *EventStore) AddCost(runID string, cost float64) error {
+	r, ok := s.Manager.(interface {
+		AddCost(runID string, cost float64) error
+	})
+	if !ok {
+		return fmt.Errorf("%T does not support AddCost", s.Manager)
+	}
+	return r.AddCost(runID, cost)
+}
+
+// SetUsageByTask records per-task usage, if the wrapped store supports it;
+// see FileStore.SetUsageByTask
+func (s *EventStore) SetUsageByTask(runID string, usage map[string]TaskUsage) error {
+	r, ok := s.Manager.(interface {
+		SetUsageByTask(runID string, usage map[string]TaskUsage) error
+	})
+	if !ok {
+		return fmt.Errorf("%T does not support SetUsageByTask", s.Manager)
+	}
+	return r.SetUsageByTask(runID, usage)
+}
+
+// EndRun ends the run and emits notify.EventRunCompleted or notify.EventRunFailed
+func (s *EventStore) EndRun(runID string, status RunStatus) error {
+	if err := s.Manager.EndRun(runID, status); err != nil {
+		return err
+	}
+	s.emitEnd(runID, status, "")
+	return nil
+}
+
+// EndRunWithError ends the run as failed and emits notify.EventRunFailed
+func (s *EventStore) EndRunWithError(runID string, runErr error) error {
+	r, ok := s.Manager.(interface {
+		EndRunWithError(runID string, err error) error
+	})
+	if ok {
+		if err := r.EndRunWithError(runID, runErr); err != nil {
+			return err
+		}
+	} else if err := s.Manager.EndRun(runID, RunStatusFailed); err != nil {
+		return err
+	}
+
+	msg := ""
+	if runErr != nil {
+		msg = runErr.Error()
+	}
+	s.emitEnd(runID, RunStatusFailed, msg)
+	return nil
+}
+
+func (s *EventStore) emitEnd(runID string, status RunStatus, errMsg string) {
+	s.mu.Lock()
+	meta := s.runs[runID]
+	delete(s.runs, runID)
+	delete(s.turns, runID)
+	s.mu.Unlock()
+
+	event := notify.Event{
+		Type:     notify.EventRunCompleted,
+		RunID:    runID,
+		FlowID:   meta.FlowID,
+		NodeID:   meta.NodeID,
+		Message:  fmt.Sprintf("Run %s %s", runID, status),
+		Severity: notify.SeverityInfo,
+		Metadata: map[string]any{"status": string(status)},
+	}
+
+	switch status {
+	case RunStatusFailed:
+		event.Type = notify.EventRunFailed
+		event.Severity = notify.SeverityError
+	case RunStatusCanceled:
+		event.Type = notify.EventRunFailed
+		event.Severity = notify.SeverityWarning
+	}
+	if errMsg != "" {
+		event.Message = errMsg
+	}
+
+	// Final totals, as written by the wrapped store
+	if m, err := s.Manager.LoadMetadata(runID); err == nil {
+		if event.FlowID == "" {
+			event.FlowID = m.FlowID
+		}
+		event.Metadata["turns"] = m.TurnCount
+		event.Metadata["tokensIn"] = m.TotalTokensIn
+		event.Metadata["tokensOut"] = m.TotalTokensOut
+		event.Metadata["cost"] = m.TotalCost
+		if !m.EndedAt.IsZero() {
+			event.Metadata["durationMs"] = m.EndedAt.Sub(m.StartedAt).Milliseconds()
+		}
+	}
+
+	s.emit(event)
+}
+
+func (s *EventStore) emit(event notify.Event) {
+	if s.notifier == nil {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if err := s.notifier.Notify(ctx, event); err != nil {
+		slog.Warn("failed to emit transcript event",
+			slog.String("run_id", event.RunID),
+			slog.String("type", string(event.Type)),
+			slog.String("error", err.Error()))
+	}
+}
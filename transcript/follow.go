@@ -0,0 +1,141 @@
+package transcript
+
+import "sync"
+
+// follower delivers turns to a Follow subscriber without ever blocking
+// RecordTurn: turns are queued and pumped to the channel by a goroutine.
+type follower struct {
+	out    chan Turn
+	notify chan struct{}
+	done   chan struct{}
+
+	mu     sync.Mutex
+	queue  []Turn
+	closed bool // no more turns will be pushed
+
+	stopOnce sync.Once
+}
+
+func newFollower(initial []Turn) *follower {
+	f := &follower{
+		out:    make(chan Turn),
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		queue:  append([]Turn(nil), initial...),
+	}
+	go f.pump()
+	return f
+}
+
+// push queues a turn for delivery
+func (f *follower) push(turn Turn) {
+	f.mu.Lock()
+	f.queue = append(f.queue, turn)
+	f.mu.Unlock()
+	f.wake()
+}
+
+// finish marks the run as ended; the channel closes once the queue drains
+func (f *follower) finish() {
+	f.mu.Lock()
+	f.closed = true
+	f.mu.Unlock()
+	f.wake()
+}
+
+// stop abandons delivery and closes the channel
+func (f *follower) stop() {
+	f.stopOnce.Do(func() { close(f.done) })
+}
+
+func (f *follower) wake() {
+	select {
+	case f.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (f *follower) pump() {
+	defer close(f.out)
+
+	for {
+		f.mu.Lock()
+		pending := f.queue
+		f.queue = nil
+		closed := f.closed
+		f.mu.Unlock()
+
+		for _, turn := range pending {
+			select {
+			case f.out <- turn:
+			case <-f.done:
+				return
+			}
+		}
+
+		if closed && len(pending) == 0 {
+			return
+		}
+		if len(pending) > 0 {
+			continue // re-check for turns queued while sending
+		}
+
+		select {
+		case <-f.notify:
+		case <-f.done:
+			return
+		}
+	}
+}
+
+// Follow streams the turns of a run. Existing turns are delivered first,
+// then new turns as they are recorded. The channel closes when the run ends
+// or cancel is called. Following a finished run delivers its saved turns and
+// closes. Tool calls recorded after a turn was delivered are not re-sent.
+func (s *FileStore) Follow(runID string) (<-chan Turn, func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	active, ok := s.active[runID]
+	if !ok {
+		t, err := s.loadWithRecovery(runID)
+		if err != nil {
+			return nil, nil, err
+		}
+		f := newFollower(t.Turns)
+		f.finish()
+		return f.out, f.stop, nil
+	}
+
+	f := newFollower(active.transcript.Turns)
+	if active.followers == nil {
+		active.followers = make(map[*follower]struct{})
+	}
+	active.followers[f] = struct{}{}
+
+	cancel := func() {
+		s.mu.Lock()
+		if a, ok := s.active[runID]; ok {
+			delete(a.followers, f)
+		}
+		s.mu.Unlock()
+		f.stop()
+	}
+
+	return f.out, cancel, nil
+}
+
+// notifyFollowers delivers a turn to all followers. Caller holds s.mu.
+func (a *activeRun) notifyFollowers(turn Turn) {
+	for f := range a.followers {
+		f.push(turn)
+	}
+}
+
+// finishFollowers closes all follower channels once drained. Caller holds s.mu.
+func (a *activeRun) finishFollowers() {
+	for f := range a.followers {
+		f.finish()
+	}
+	a.followers = nil
+}
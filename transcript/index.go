@@ -0,0 +1,565 @@
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// indexDir is the index location relative to the transcript base directory.
+// Each run's postings are persisted to their own <runID>.json segment.
+const indexDir = "index/runs"
+
+// Index is an embedded inverted index over transcript turns.
+//
+// The index holds postings (term frequencies per run and turn) but not turn
+// content; matched turns are read back from the transcript store. Each run's
+// postings are persisted to <baseDir>/index/runs/<runID>.json, and Save only
+// rewrites the segments of runs that changed. FileStore maintains the index
+// on RecordTurn when StoreConfig.Index is set; Searcher.Search uses it when
+// present and falls back to grep otherwise.
+type Index struct {
+	dir  string
+	load func(runID string) (*Transcript, error)
+
+	mu       sync.RWMutex
+	docs     map[docKey]*indexDoc
+	postings map[string]map[docKey]int // term -> doc -> term frequency
+	pending  map[docKey]string         // content of turns whose run is not yet saved
+	dirty    map[string]bool           // runs whose segment must be rewritten
+}
+
+type docKey struct {
+	RunID  string
+	TurnID int
+}
+
+// indexDoc is a single indexed turn
+type indexDoc struct {
+	RunID     string         `json:"runId"`
+	TurnID    int            `json:"turnId"`
+	FlowID    string         `json:"flowId,omitempty"`
+	Role      string         `json:"role"`
+	Timestamp time.Time      `json:"timestamp"`
+	Terms     map[string]int `json:"terms"`
+}
+
+// OpenIndex loads the index under baseDir, creating an empty one if none exists.
+func OpenIndex(baseDir string) (*Index, error) {
+	idx, err := newIndex(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(idx.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return idx, nil
+		}
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(idx.dir, entry.Name())) //nolint:gosec // path built from index dir
+		if err != nil {
+			return nil, err
+		}
+		var docs []*indexDoc
+		if err := json.Unmarshal(data, &docs); err != nil {
+			return nil, fmt.Errorf("decode index segment %s: %w", entry.Name(), err)
+		}
+		for _, doc := range docs {
+			idx.addDoc(doc)
+		}
+	}
+
+	return idx, nil
+}
+
+// IndexExists reports whether a persisted index exists under baseDir
+func IndexExists(baseDir string) bool {
+	_, err := os.Stat(filepath.Join(baseDir, indexDir))
+	return err == nil
+}
+
+// RebuildIndex indexes every saved transcript under baseDir and persists the
+// result, one run at a time.
+func RebuildIndex(baseDir string) (*Index, error) {
+	if err := os.RemoveAll(filepath.Join(baseDir, indexDir)); err != nil {
+		return nil, err
+	}
+	idx, err := newIndex(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(idx.dir, 0755); err != nil {
+		return nil, err
+	}
+
+	store, err := NewFileStore(StoreConfig{BaseDir: baseDir})
+	if err != nil {
+		return nil, err
+	}
+	runs, err := store.List(ListFilter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, meta := range runs {
+		t, err := store.Load(meta.RunID)
+		if err != nil {
+			continue
+		}
+		idx.AddTranscript(t)
+		if err := idx.Save(); err != nil {
+			return nil, err
+		}
+	}
+
+	return idx, nil
+}
+
+func newIndex(baseDir string) (*Index, error) {
+	store, err := NewFileStore(StoreConfig{BaseDir: baseDir})
+	if err != nil {
+		return nil, err
+	}
+	return &Index{
+		dir:      filepath.Join(baseDir, indexDir),
+		load:     store.Load,
+		docs:     make(map[docKey]*indexDoc),
+		postings: make(map[string]map[docKey]int),
+		pending:  make(map[docKey]string),
+		dirty:    make(map[string]bool),
+	}, nil
+}
+
+// Add indexes a single turn
+func (idx *Index) Add(runID, flowID string, turn Turn) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	terms := make(map[string]int)
+	for _, term := range tokenize(turn.Content) {
+		terms[term]++
+	}
+	idx.addDoc(&indexDoc{
+		RunID:     runID,
+		TurnID:    turn.ID,
+		FlowID:    flowID,
+		Role:      turn.Role,
+		Timestamp: turn.Timestamp,
+		Terms:     terms,
+	})
+	idx.pending[docKey{RunID: runID, TurnID: turn.ID}] = turn.Content
+	idx.dirty[runID] = true
+}
+
+// AddTranscript indexes every turn of a transcript
+func (idx *Index) AddTranscript(t *Transcript) {
+	for _, turn := range t.Turns {
+		idx.Add(t.RunID, t.Metadata.FlowID, turn)
+	}
+}
+
+// RemoveRun drops all turns of a run from the index
+func (idx *Index) RemoveRun(runID string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for key, doc := range idx.docs {
+		if key.RunID == runID {
+			idx.removeDoc(key, doc)
+		}
+	}
+	idx.dirty[runID] = true
+}
+
+// Save persists the segments of runs that changed since the last Save
+func (idx *Index) Save() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if len(idx.dirty) == 0 {
+		return nil
+	}
+
+	byRun := make(map[string][]*indexDoc, len(idx.dirty))
+	for key, doc := range idx.docs {
+		if idx.dirty[key.RunID] {
+			byRun[key.RunID] = append(byRun[key.RunID], doc)
+		}
+	}
+
+	if err := os.MkdirAll(idx.dir, 0755); err != nil {
+		return err
+	}
+	for runID := range idx.dirty {
+		if err := idx.writeSegment(runID, byRun[runID]); err != nil {
+			return err
+		}
+		delete(idx.dirty, runID)
+	}
+
+	// Persisted runs are read back from the store
+	clear(idx.pending)
+	return nil
+}
+
+// writeSegment replaces a run's segment, removing it when the run has no docs
+func (idx *Index) writeSegment(runID string, docs []*indexDoc) error {
+	path := filepath.Join(idx.dir, runID+".json")
+	if len(docs) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	sort.Slice(docs, func(i, j int) bool { return docs[i].TurnID < docs[j].TurnID })
+	data, err := json.Marshal(docs)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// DocCount returns the number of indexed turns
+func (idx *Index) DocCount() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Search runs a query and returns matching turns, best match first.
+//
+// Query syntax:
+//
+//	word            turn must contain the term
+//	"exact phrase"  turn must contain the phrase
+//	-word           turn must not contain the term
+//	role:assistant  restrict by role
+//	flow:ticket-to-pr
+//	after:2024-01-02 before:2024-02-01   (YYYY-MM-DD or RFC 3339)
+//
+// Turns whose transcript can no longer be loaded are skipped.
+func (idx *Index) Search(query string, maxResults int) ([]SearchResult, error) {
+	q, err := ParseIndexQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	type scored struct {
+		doc     *indexDoc
+		content string
+		score   int
+	}
+	var matches []scored
+	contents := &contentCache{idx: idx, runs: make(map[string]*Transcript)}
+
+	for _, doc := range idx.candidates(q) {
+		if !q.matchesDoc(doc) {
+			continue
+		}
+		var content string
+		if len(q.Phrases) > 0 {
+			var ok bool
+			if content, ok = contents.get(doc); !ok || !q.matchesPhrases(content) {
+				continue
+			}
+		}
+		score := 0
+		for _, term := range q.Terms {
+			score += doc.Terms[term]
+		}
+		matches = append(matches, scored{doc: doc, content: content, score: score})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].doc.Timestamp.After(matches[j].doc.Timestamp)
+	})
+
+	results := make([]SearchResult, 0, len(matches))
+	for _, m := range matches {
+		if maxResults > 0 && len(results) == maxResults {
+			break
+		}
+		content := m.content
+		if len(q.Phrases) == 0 {
+			var ok bool
+			if content, ok = contents.get(m.doc); !ok {
+				continue
+			}
+		}
+		results = append(results, SearchResult{
+			RunID:   m.doc.RunID,
+			TurnID:  m.doc.TurnID,
+			Role:    m.doc.Role,
+			Content: content,
+			Match:   q.snippet(content),
+		})
+	}
+	return results, nil
+}
+
+// contentCache loads turn content for a single search, one transcript per run
+type contentCache struct {
+	idx  *Index
+	runs map[string]*Transcript
+}
+
+func (c *contentCache) get(doc *indexDoc) (string, bool) {
+	key := docKey{RunID: doc.RunID, TurnID: doc.TurnID}
+	if content, ok := c.idx.pending[key]; ok {
+		return content, true
+	}
+
+	t, ok := c.runs[doc.RunID]
+	if !ok {
+		var err error
+		if t, err = c.idx.load(doc.RunID); err != nil {
+			t = nil
+		}
+		c.runs[doc.RunID] = t
+	}
+	if t == nil {
+		return "", false
+	}
+	for _, turn := range t.Turns {
+		if turn.ID == doc.TurnID {
+			return turn.Content, true
+		}
+	}
+	return "", false
+}
+
+// candidates returns docs containing every required term (or all docs if none)
+func (idx *Index) candidates(q *IndexQuery) map[docKey]*indexDoc {
+	required := append([]string(nil), q.Terms...)
+	for _, phrase := range q.Phrases {
+		required = append(required, tokenize(phrase)...)
+	}
+
+	if len(required) == 0 {
+		return idx.docs
+	}
+
+	// Intersect starting from the rarest term
+	sort.Slice(required, func(i, j int) bool {
+		return len(idx.postings[required[i]]) < len(idx.postings[required[j]])
+	})
+
+	result := make(map[docKey]*indexDoc)
+	for key := range idx.postings[required[0]] {
+		result[key] = idx.docs[key]
+	}
+	for _, term := range required[1:] {
+		posting := idx.postings[term]
+		for key := range result {
+			if _, ok := posting[key]; !ok {
+				delete(result, key)
+			}
+		}
+	}
+	return result
+}
+
+func (idx *Index) addDoc(doc *indexDoc) {
+	key := docKey{RunID: doc.RunID, TurnID: doc.TurnID}
+	if old, ok := idx.docs[key]; ok {
+		idx.removeDoc(key, old)
+	}
+
+	idx.docs[key] = doc
+	for term, freq := range doc.Terms {
+		posting, ok := idx.postings[term]
+		if !ok {
+			posting = make(map[docKey]int)
+			idx.postings[term] = posting
+		}
+		posting[key] = freq
+	}
+}
+
+func (idx *Index) removeDoc(key docKey, doc *indexDoc) {
+	for term := range doc.Terms {
+		if posting, ok := idx.postings[term]; ok {
+			delete(posting, key)
+			if len(posting) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+	}
+	delete(idx.docs, key)
+	delete(idx.pending, key)
+}
+
+// IndexQuery is a parsed index search query
+type IndexQuery struct {
+	Terms    []string
+	Phrases  []string
+	Excluded []string
+	Role     string
+	FlowID   string
+	After    time.Time
+	Before   time.Time
+}
+
+// ParseIndexQuery parses the query syntax accepted by Index.Search
+func ParseIndexQuery(query string) (*IndexQuery, error) {
+	q := &IndexQuery{}
+
+	for _, tok := range splitQuery(query) {
+		switch {
+		case strings.HasPrefix(tok, `"`):
+			if phrase := strings.ToLower(strings.Trim(tok, `"`)); phrase != "" {
+				q.Phrases = append(q.Phrases, phrase)
+			}
+		case strings.HasPrefix(tok, "-") && len(tok) > 1:
+			q.Excluded = append(q.Excluded, tokenize(tok[1:])...)
+		case strings.HasPrefix(tok, "role:"):
+			q.Role = strings.TrimPrefix(tok, "role:")
+		case strings.HasPrefix(tok, "flow:"):
+			q.FlowID = strings.TrimPrefix(tok, "flow:")
+		case strings.HasPrefix(tok, "after:"):
+			t, err := parseQueryTime(strings.TrimPrefix(tok, "after:"))
+			if err != nil {
+				return nil, err
+			}
+			q.After = t
+		case strings.HasPrefix(tok, "before:"):
+			t, err := parseQueryTime(strings.TrimPrefix(tok, "before:"))
+			if err != nil {
+				return nil, err
+			}
+			q.Before = t
+		default:
+			q.Terms = append(q.Terms, tokenize(tok)...)
+		}
+	}
+
+	return q, nil
+}
+
+func (q *IndexQuery) matchesDoc(doc *indexDoc) bool {
+	if q.Role != "" && doc.Role != q.Role {
+		return false
+	}
+	if q.FlowID != "" && doc.FlowID != q.FlowID {
+		return false
+	}
+	if !q.After.IsZero() && doc.Timestamp.Before(q.After) {
+		return false
+	}
+	if !q.Before.IsZero() && doc.Timestamp.After(q.Before) {
+		return false
+	}
+
+	for _, ex := range q.Excluded {
+		if doc.Terms[ex] > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesPhrases reports whether content contains every quoted phrase
+func (q *IndexQuery) matchesPhrases(content string) bool {
+	lower := strings.ToLower(content)
+	for _, phrase := range q.Phrases {
+		if !strings.Contains(lower, phrase) {
+			return false
+		}
+	}
+	return true
+}
+
+// snippet returns the line containing the first phrase or term match
+func (q *IndexQuery) snippet(content string) string {
+	needles := append([]string(nil), q.Phrases...)
+	needles = append(needles, q.Terms...)
+
+	lower := strings.ToLower(content)
+	for _, needle := range needles {
+		pos := strings.Index(lower, needle)
+		if pos == -1 {
+			continue
+		}
+		start := strings.LastIndex(content[:pos], "\n") + 1
+		end := strings.Index(content[pos:], "\n")
+		if end == -1 {
+			end = len(content)
+		} else {
+			end += pos
+		}
+		return strings.TrimSpace(content[start:end])
+	}
+	return ""
+}
+
+// splitQuery splits on whitespace, keeping quoted phrases together
+func splitQuery(query string) []string {
+	var tokens []string
+	var b strings.Builder
+	inQuote := false
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			b.WriteRune(r)
+			inQuote = !inQuote
+			if !inQuote {
+				tokens = append(tokens, b.String())
+				b.Reset()
+			}
+		case unicode.IsSpace(r) && !inQuote:
+			if b.Len() > 0 {
+				tokens = append(tokens, b.String())
+				b.Reset()
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() > 0 {
+		tokens = append(tokens, b.String())
+	}
+	return tokens
+}
+
+func parseQueryTime(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD or RFC 3339", s)
+	}
+	return t, nil
+}
+
+// tokenize lowercases text and splits it into letter/digit terms
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+}
@@ -0,0 +1,177 @@
+package transcript
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newIndexedStore returns a FileStore under a temp dir that maintains idx
+func newIndexedStore(t *testing.T) (*FileStore, *Index, string) {
+	t.Helper()
+	dir := t.TempDir()
+	idx, err := OpenIndex(dir)
+	if err != nil {
+		t.Fatalf("OpenIndex() error = %v", err)
+	}
+	store, err := NewFileStore(StoreConfig{BaseDir: dir, Index: idx})
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	return store, idx, dir
+}
+
+func recordRun(t *testing.T, store *FileStore, runID, flowID string, turns ...Turn) {
+	t.Helper()
+	if err := store.StartRun(runID, RunMetadata{FlowID: flowID}); err != nil {
+		t.Fatalf("StartRun() error = %v", err)
+	}
+	for _, turn := range turns {
+		if err := store.RecordTurn(runID, turn); err != nil {
+			t.Fatalf("RecordTurn() error = %v", err)
+		}
+	}
+	if err := store.EndRun(runID, RunStatusCompleted); err != nil {
+		t.Fatalf("EndRun() error = %v", err)
+	}
+}
+
+func searchRuns(t *testing.T, idx *Index, query string) string {
+	t.Helper()
+	results, err := idx.Search(query, 0)
+	if err != nil {
+		t.Fatalf("Search(%q) error = %v", query, err)
+	}
+	var runs []string
+	for _, r := range results {
+		runs = append(runs, r.RunID)
+	}
+	return strings.Join(runs, ",")
+}
+
+func TestIndex_Search(t *testing.T) {
+	store, idx, _ := newIndexedStore(t)
+	recordRun(t, store, "run-1", "ticket-to-pr",
+		Turn{Role: "user", Content: "Fix the null pointer in the parser"},
+		Turn{Role: "assistant", Content: "Found it.\nThe null pointer comes from parse() returning nil."},
+	)
+	recordRun(t, store, "run-2", "review",
+		Turn{Role: "assistant", Content: "pointer arithmetic looks fine, null checks present"},
+	)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`pointer role:assistant`, "run-2,run-1"},
+		{`"null pointer" role:assistant`, "run-1"},
+		{`pointer -parser flow:ticket-to-pr`, "run-1"},
+		{`pointer flow:review`, "run-2"},
+		{`missing`, ""},
+	}
+	for _, tt := range tests {
+		if got := searchRuns(t, idx, tt.query); got != tt.want {
+			t.Errorf("Search(%q) runs = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	results, _ := idx.Search(`"null pointer" role:assistant`, 0)
+	if len(results) != 1 || results[0].TurnID != 2 ||
+		results[0].Match != "The null pointer comes from parse() returning nil." {
+		t.Errorf("results = %+v, want turn 2 with the matching line", results)
+	}
+}
+
+func TestIndex_PersistsPostingsPerRun(t *testing.T) {
+	store, _, dir := newIndexedStore(t)
+	recordRun(t, store, "run-1", "flow", Turn{Role: "user", Content: "secret-sounding content"})
+	recordRun(t, store, "run-2", "flow", Turn{Role: "user", Content: "other content"})
+
+	data, err := os.ReadFile(filepath.Join(dir, indexDir, "run-1.json"))
+	if err != nil {
+		t.Fatalf("run-1 segment: %v", err)
+	}
+	if strings.Contains(string(data), "secret-sounding content") {
+		t.Errorf("segment stores turn content: %s", data)
+	}
+	var docs []*indexDoc
+	if err := json.Unmarshal(data, &docs); err != nil || len(docs) != 1 || docs[0].Terms["sounding"] != 1 {
+		t.Errorf("segment = %s (error %v), want postings for one turn", data, err)
+	}
+
+	// A reopened index reads content back from the saved transcripts
+	reopened, err := OpenIndex(dir)
+	if err != nil {
+		t.Fatalf("OpenIndex() error = %v", err)
+	}
+	if reopened.DocCount() != 2 {
+		t.Errorf("DocCount() = %d, want 2", reopened.DocCount())
+	}
+	results, err := reopened.Search(`"sounding content"`, 0)
+	if err != nil || len(results) != 1 || results[0].Content != "secret-sounding content" {
+		t.Errorf("Search() = %+v, %v", results, err)
+	}
+}
+
+func TestIndex_Delete(t *testing.T) {
+	store, idx, dir := newIndexedStore(t)
+	recordRun(t, store, "run-1", "flow", Turn{Role: "user", Content: "deploy the service"})
+	recordRun(t, store, "run-2", "flow", Turn{Role: "user", Content: "deploy the docs"})
+
+	if err := store.Delete("run-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got := searchRuns(t, idx, "deploy"); got != "run-2" {
+		t.Errorf("Search() after delete = %q, want run-2", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, indexDir, "run-1.json")); !os.IsNotExist(err) {
+		t.Errorf("run-1 segment still present: %v", err)
+	}
+
+	reopened, err := OpenIndex(dir)
+	if err != nil {
+		t.Fatalf("OpenIndex() error = %v", err)
+	}
+	if got := searchRuns(t, reopened, "deploy"); got != "run-2" {
+		t.Errorf("reopened Search() = %q, want run-2", got)
+	}
+}
+
+func TestRebuildIndex(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(StoreConfig{BaseDir: dir})
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	recordRun(t, store, "run-1", "flow", Turn{Role: "user", Content: "rebuild me"})
+	recordRun(t, store, "run-2", "flow", Turn{Role: "assistant", Content: "and me too"})
+
+	// A stale segment for a run that no longer exists is dropped
+	if err := os.MkdirAll(filepath.Join(dir, indexDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, indexDir, "gone.json"), []byte(`[]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := RebuildIndex(dir)
+	if err != nil {
+		t.Fatalf("RebuildIndex() error = %v", err)
+	}
+	if idx.DocCount() != 2 {
+		t.Errorf("DocCount() = %d, want 2", idx.DocCount())
+	}
+	if !IndexExists(dir) {
+		t.Error("IndexExists() = false after rebuild")
+	}
+	if _, err := os.Stat(filepath.Join(dir, indexDir, "gone.json")); !os.IsNotExist(err) {
+		t.Errorf("stale segment kept: %v", err)
+	}
+
+	results, err := NewSearcher(dir).Search("me role:assistant", SearchOptions{})
+	if err != nil || len(results) != 1 || results[0].RunID != "run-2" || results[0].Content != "and me too" {
+		t.Errorf("Searcher.Search() = %+v, %v", results, err)
+	}
+}
@@ -0,0 +1,145 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// turnLogFile is the append-only log of changes to an active run.
+const turnLogFile = "turns.jsonl"
+
+// DefaultCompactEvery is the default number of log records between snapshots.
+const DefaultCompactEvery = 100
+
+// logRecord is one line of the turn log.
+//
+// Records are idempotent so a log can be replayed over a snapshot that
+// already contains some of them (e.g., after a crash during compaction):
+// turns are keyed by ID, tool calls by turn ID and position, and cost is
+// recorded as a running total.
+type logRecord struct {
+	Turn      *Turn     `json:"turn,omitempty"`
+	ToolCall  *ToolCall `json:"toolCall,omitempty"`
+	TurnID    int       `json:"turnId,omitempty"`
+	Index     int       `json:"index,omitempty"`
+	TotalCost *float64  `json:"totalCost,omitempty"`
+
+	UsageByTask map[string]TaskUsage `json:"usageByTask,omitempty"`
+}
+
+// appendLog appends a record to the run's turn log
+func appendLog(runDir string, rec logRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	f, err := os.OpenFile(filepath.Join(runDir, turnLogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644) //nolint:gosec // path built from store base dir
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// truncateLog empties the turn log after a snapshot
+func truncateLog(runDir string) error {
+	err := os.Truncate(filepath.Join(runDir, turnLogFile), 0)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// removeLog deletes the turn log once the run is finalized
+func removeLog(runDir string) error {
+	err := os.Remove(filepath.Join(runDir, turnLogFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// replayLog applies the run's turn log to t. It reports whether a log was
+// found. A truncated final line (from a crash mid-write) is skipped.
+func replayLog(runDir string, t *Transcript) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(runDir, turnLogFile)) //nolint:gosec // path built from store base dir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var rec logRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			slog.Debug("skipping unreadable turn log record",
+				slog.String("run_id", t.RunID),
+				slog.String("error", err.Error()))
+			continue
+		}
+		applyRecord(t, rec)
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, bufio.ErrTooLong) {
+		return true, err
+	}
+
+	recountTokens(t)
+	return true, nil
+}
+
+// applyRecord applies a single log record to t, ignoring records already present
+func applyRecord(t *Transcript, rec logRecord) {
+	switch {
+	case rec.Turn != nil:
+		if rec.Turn.ID == len(t.Turns)+1 {
+			t.Turns = append(t.Turns, *rec.Turn)
+			t.Metadata.TotalCost += rec.Turn.Cost
+		}
+	case rec.ToolCall != nil:
+		if rec.TurnID < 1 || rec.TurnID > len(t.Turns) {
+			return
+		}
+		turn := &t.Turns[rec.TurnID-1]
+		if rec.Index == len(turn.ToolCalls) {
+			turn.ToolCalls = append(turn.ToolCalls, *rec.ToolCall)
+		}
+	case rec.TotalCost != nil:
+		t.Metadata.TotalCost = *rec.TotalCost
+	case rec.UsageByTask != nil:
+		t.Metadata.UsageByTask = rec.UsageByTask
+	}
+}
+
+// recountTokens recomputes token totals and turn count from the turns
+func recountTokens(t *Transcript) {
+	t.Metadata.TotalTokensIn = 0
+	t.Metadata.TotalTokensOut = 0
+	for _, turn := range t.Turns {
+		switch turn.Role {
+		case "user", "system":
+			t.Metadata.TotalTokensIn += turn.TokensIn
+		case "assistant":
+			t.Metadata.TotalTokensOut += turn.TokensOut
+		}
+	}
+	t.Metadata.TurnCount = len(t.Turns)
+}
@@ -0,0 +1,118 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// startInterruptedRun records turns and tool calls without ending the run,
+// as if the process had crashed
+func startInterruptedRun(t *testing.T, config StoreConfig, runID string, turns int) {
+	t.Helper()
+	store, err := NewFileStore(config)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	if err := store.StartRun(runID, RunMetadata{FlowID: "flow"}); err != nil {
+		t.Fatalf("StartRun() error = %v", err)
+	}
+	for i := range turns {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		if err := store.RecordTurn(runID, Turn{Role: role, Content: "turn", TokensIn: 1, TokensOut: 2}); err != nil {
+			t.Fatalf("RecordTurn() error = %v", err)
+		}
+	}
+	if err := store.RecordToolCall(runID, ToolCall{Name: "bash", Output: "ok"}); err != nil {
+		t.Fatalf("RecordToolCall() error = %v", err)
+	}
+}
+
+func loadRecovered(t *testing.T, dir, runID string) *Transcript {
+	t.Helper()
+	store, err := NewFileStore(StoreConfig{BaseDir: dir})
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	recovered, err := store.Load(runID)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return recovered
+}
+
+func TestFileStore_RecoversFromLog(t *testing.T) {
+	dir := t.TempDir()
+	startInterruptedRun(t, StoreConfig{BaseDir: dir}, "run-1", 3)
+
+	recovered := loadRecovered(t, dir, "run-1")
+	if len(recovered.Turns) != 3 || recovered.Metadata.TurnCount != 3 {
+		t.Fatalf("Turns = %d, TurnCount = %d; want 3", len(recovered.Turns), recovered.Metadata.TurnCount)
+	}
+	if recovered.Metadata.TotalTokensIn != 2 || recovered.Metadata.TotalTokensOut != 2 {
+		t.Errorf("tokens = %d/%d, want 2/2", recovered.Metadata.TotalTokensIn, recovered.Metadata.TotalTokensOut)
+	}
+	if calls := recovered.Turns[2].ToolCalls; len(calls) != 1 || calls[0].Name != "bash" {
+		t.Errorf("ToolCalls = %+v, want the bash call on the last turn", calls)
+	}
+}
+
+func TestFileStore_ReplaySkipsTruncatedWrite(t *testing.T) {
+	dir := t.TempDir()
+	startInterruptedRun(t, StoreConfig{BaseDir: dir}, "run-1", 2)
+
+	// A crash mid-append leaves half a record at the end of the log
+	logPath := filepath.Join(dir, "runs", "run-1", turnLogFile)
+	f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"turn":{"id":3,"role":"user","cont`)
+	_ = f.Close()
+
+	recovered := loadRecovered(t, dir, "run-1")
+	if len(recovered.Turns) != 2 || len(recovered.Turns[1].ToolCalls) != 1 {
+		t.Errorf("Turns = %+v, want the two complete turns and their tool call", recovered.Turns)
+	}
+}
+
+func TestFileStore_CompactionAndOverlappingReplay(t *testing.T) {
+	dir := t.TempDir()
+	// Snapshot after every 2 records: 5 turns + 1 tool call leave an empty log
+	startInterruptedRun(t, StoreConfig{BaseDir: dir, CompactEvery: 2}, "run-1", 5)
+
+	logPath := filepath.Join(dir, "runs", "run-1", turnLogFile)
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 0 {
+		t.Errorf("log has %d records after compaction, want 0", lines)
+	}
+
+	// Replaying records already in the snapshot (a crash between snapshot
+	// and truncation) must not duplicate them
+	snapshot := loadRecovered(t, dir, "run-1")
+	for i := range snapshot.Turns {
+		turn := snapshot.Turns[i]
+		if err := appendLog(filepath.Join(dir, "runs", "run-1"), logRecord{Turn: &turn}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	extra := Turn{ID: 6, Role: "user", Content: "after snapshot"}
+	if err := appendLog(filepath.Join(dir, "runs", "run-1"), logRecord{Turn: &extra}); err != nil {
+		t.Fatal(err)
+	}
+
+	recovered := loadRecovered(t, dir, "run-1")
+	if len(recovered.Turns) != 6 || recovered.Turns[5].Content != "after snapshot" {
+		t.Errorf("Turns = %d, want 5 from the snapshot plus 1 from the log", len(recovered.Turns))
+	}
+	if len(recovered.Turns[4].ToolCalls) != 1 {
+		t.Errorf("ToolCalls on turn 5 = %d, want 1", len(recovered.Turns[4].ToolCalls))
+	}
+}
@@ -22,7 +22,45 @@ type Manager interface {
 type ListFilter struct {
 	FlowID string
 	Status RunStatus
-	After  time.Time
-	Before time.Time
+	After  time.Time // Started at or after
+	Before time.Time // Started at or before
+
+	// EndedAfter and EndedBefore bound the end time; runs that have not
+	// ended are excluded when either is set.
+	EndedAfter  time.Time
+	EndedBefore time.Time
+
+	// SortBy orders results (default SortByStartedAt), newest/largest first
+	// unless Ascending is set.
+	SortBy    SortField
+	Ascending bool
+
+	// Limit is the page size. When more runs match, ListPage returns a
+	// NextCursor to pass back as Cursor for the next page.
 	Limit  int
+	Cursor string
+}
+
+// Matches reports whether meta passes the filter's flow, status, and time
+// conditions. Sorting, Limit, and Cursor are not considered.
+func (f ListFilter) Matches(meta Meta) bool {
+	if f.FlowID != "" && meta.FlowID != f.FlowID {
+		return false
+	}
+	if f.Status != "" && meta.Status != f.Status {
+		return false
+	}
+	if !f.After.IsZero() && meta.StartedAt.Before(f.After) {
+		return false
+	}
+	if !f.Before.IsZero() && meta.StartedAt.After(f.Before) {
+		return false
+	}
+	if !f.EndedAfter.IsZero() && (meta.EndedAt.IsZero() || meta.EndedAt.Before(f.EndedAfter)) {
+		return false
+	}
+	if !f.EndedBefore.IsZero() && (meta.EndedAt.IsZero() || meta.EndedAt.After(f.EndedBefore)) {
+		return false
+	}
+	return true
 }
@@ -0,0 +1,678 @@
+package transcript
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrObjectNotFound is returned by ObjectStorage.Get when the key does not exist.
+	ErrObjectNotFound = errors.New("object not found")
+
+	// ErrStoreClosed is returned when a run ends after ObjectStore.Close.
+	// The run is kept in the local buffer but not uploaded.
+	ErrStoreClosed = errors.New("transcript store closed")
+)
+
+// remoteMetaFile caches remote run metadata under the ObjectStore base
+// directory, so listings only download metadata for runs not seen before.
+const remoteMetaFile = "remote-runs.json"
+
+// ObjectStorage is a minimal blob storage interface.
+//
+// S3Storage covers S3 and S3-compatible services (including GCS through its
+// XML API); adapters for other object stores implement these four
+// operations. Keys use forward slashes regardless of platform.
+type ObjectStorage interface {
+	// Put uploads the object, replacing any existing object with the same key.
+	Put(ctx context.Context, key string, r io.Reader) error
+
+	// Get downloads the object. Returns ErrObjectNotFound if missing.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns all keys beginning with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// ObjectStoreConfig configures an ObjectStore.
+type ObjectStoreConfig struct {
+	// BaseDir is the local write buffer and download cache.
+	BaseDir string
+
+	// Storage is the object storage backend (required).
+	Storage ObjectStorage
+
+	// Prefix is prepended to every object key (e.g., "devflow/").
+	Prefix string
+
+	// UploadWorkers is the number of background uploaders (default: 2).
+	UploadWorkers int
+
+	// MaxUploadAttempts is the number of tries per run before giving up (default: 3).
+	MaxUploadAttempts int
+
+	// Redactor, if set, scrubs secrets before anything is written locally.
+	Redactor *Redactor
+
+	// Compression and CompressionThreshold configure the local FileStore.
+	Compression          Compression
+	CompressionThreshold int
+
+	// MaxToolOutput and ToolOutputPreview configure tool output overflow
+	// for the local FileStore; overflow artifacts are uploaded with the run.
+	MaxToolOutput     int
+	ToolOutputPreview int
+}
+
+// ObjectStore stores transcripts in object storage.
+//
+// Active runs are recorded to a local FileStore. When a run ends its files
+// are uploaded in the background, so multiple machines and ephemeral CI
+// runners can share one central archive. Runs not present locally are
+// downloaded on demand and cached under BaseDir.
+type ObjectStore struct {
+	local       *FileStore
+	storage     ObjectStorage
+	prefix      string
+	maxAttempts int
+
+	uploads chan uploadJob
+	done    chan struct{} // closed by Close to cut retry backoff short
+	pending sync.WaitGroup
+	workers sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+
+	metaMu sync.Mutex
+	remote map[string]Meta // metadata of remote runs, persisted to remoteMetaFile
+
+	closeOnce sync.Once
+}
+
+type uploadJob struct {
+	runID   string
+	attempt int
+}
+
+// NewObjectStore creates an object-storage-backed transcript store and
+// starts its background uploaders. Call Close to flush pending uploads.
+func NewObjectStore(config ObjectStoreConfig) (*ObjectStore, error) {
+	if config.Storage == nil {
+		return nil, fmt.Errorf("object storage backend required")
+	}
+
+	local, err := NewFileStore(StoreConfig{
+		BaseDir:              config.BaseDir,
+		Redactor:             config.Redactor,
+		Compression:          config.Compression,
+		CompressionThreshold: config.CompressionThreshold,
+		MaxToolOutput:        config.MaxToolOutput,
+		ToolOutputPreview:    config.ToolOutputPreview,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workers := config.UploadWorkers
+	if workers <= 0 {
+		workers = 2
+	}
+	maxAttempts := config.MaxUploadAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
+	s := &ObjectStore{
+		local:       local,
+		storage:     config.Storage,
+		prefix:      config.Prefix,
+		maxAttempts: maxAttempts,
+		uploads:     make(chan uploadJob, 64),
+		done:        make(chan struct{}),
+		remote:      loadRemoteMeta(config.BaseDir),
+	}
+
+	for i := 0; i < workers; i++ {
+		s.workers.Add(1)
+		go s.uploadWorker()
+	}
+
+	return s, nil
+}
+
+// StartRun begins a new transcript
+func (s *ObjectStore) StartRun(runID string, meta RunMetadata) error {
+	if _, err := s.remoteMetadata(context.Background(), runID); err == nil {
+		return ErrRunAlreadyExists
+	}
+	return s.local.StartRun(runID, meta)
+}
+
+// RecordTurn adds a turn to an active transcript
+func (s *ObjectStore) RecordTurn(runID string, turn Turn) error {
+	return s.local.RecordTurn(runID, turn)
+}
+
+// RecordToolCall adds a tool call to the last turn of an active transcript
+func (s *ObjectStore) RecordToolCall(runID string, tc ToolCall) error {
+	return s.local.RecordToolCall(runID, tc)
+}
+
+// AddCost adds cost not attributed to any turn; see FileStore.AddCost
+func (s *ObjectStore) AddCost(runID string, cost float64) error {
+	return s.local.AddCost(runID, cost)
+}
+
+// SetUsageByTask records per-task usage; see FileStore.SetUsageByTask
+func (s *ObjectStore) SetUsageByTask(runID string, usage map[string]TaskUsage) error {
+	return s.local.SetUsageByTask(runID, usage)
+}
+
+// EndRun completes a transcript and schedules its upload.
+// After Close the run is saved locally and ErrStoreClosed is returned.
+func (s *ObjectStore) EndRun(runID string, status RunStatus) error {
+	if err := s.local.EndRun(runID, status); err != nil {
+		return err
+	}
+	return s.enqueue(runID)
+}
+
+// EndRunWithError completes a transcript with an error and schedules its upload
+func (s *ObjectStore) EndRunWithError(runID string, err error) error {
+	if endErr := s.local.EndRunWithError(runID, err); endErr != nil {
+		return endErr
+	}
+	return s.enqueue(runID)
+}
+
+// Load retrieves a complete transcript, downloading it if not cached locally
+func (s *ObjectStore) Load(runID string) (*Transcript, error) {
+	t, err := s.local.Load(runID)
+	if err == nil {
+		return t, nil
+	}
+	if !errors.Is(err, ErrRunNotFound) && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err := s.download(context.Background(), runID); err != nil {
+		return nil, err
+	}
+	return s.local.Load(runID)
+}
+
+// LoadMetadata retrieves just the metadata
+func (s *ObjectStore) LoadMetadata(runID string) (*Meta, error) {
+	meta, err := s.local.LoadMetadata(runID)
+	if err == nil || !errors.Is(err, ErrRunNotFound) {
+		return meta, err
+	}
+	if meta, ok := s.cachedMeta(runID); ok {
+		return &meta, nil
+	}
+	return s.remoteMetadata(context.Background(), runID)
+}
+
+// List returns metadata for runs matching filter across local and remote runs
+func (s *ObjectStore) List(filter ListFilter) ([]Meta, error) {
+	page, err := s.ListPage(filter)
+	if err != nil {
+		return nil, err
+	}
+	return page.Runs, nil
+}
+
+// ListPage returns one page of local and remote runs matching filter.
+// Remote metadata is downloaded once per run and cached under BaseDir.
+func (s *ObjectStore) ListPage(filter ListFilter) (*ListPage, error) {
+	ctx := context.Background()
+
+	seen := make(map[string]bool)
+	var results []Meta
+
+	local, err := s.local.List(ListFilter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, meta := range local {
+		seen[meta.RunID] = true
+		if filter.Matches(meta) {
+			results = append(results, meta)
+		}
+	}
+
+	keys, err := s.storage.List(ctx, s.key("runs/"))
+	if err != nil {
+		return nil, fmt.Errorf("list remote runs: %w", err)
+	}
+
+	s.metaMu.Lock()
+	defer s.metaMu.Unlock()
+
+	remote := make(map[string]bool)
+	changed := false
+	for _, key := range keys {
+		if path.Base(key) != "metadata.json" || path.Dir(path.Dir(key)) != strings.TrimSuffix(s.key("runs/"), "/") {
+			continue
+		}
+		runID := path.Base(path.Dir(key))
+		remote[runID] = true
+		if seen[runID] {
+			continue
+		}
+		seen[runID] = true
+
+		meta, ok := s.remote[runID]
+		if !ok {
+			fetched, err := s.remoteMetadata(ctx, runID)
+			if err != nil {
+				slog.Debug("skipping remote transcript with unreadable metadata",
+					slog.String("run_id", runID),
+					slog.String("error", err.Error()))
+				continue
+			}
+			meta = *fetched
+			s.remote[runID] = meta
+			changed = true
+		}
+		if filter.Matches(meta) {
+			results = append(results, meta)
+		}
+	}
+
+	// Forget runs deleted from the archive by other machines
+	for runID := range s.remote {
+		if !remote[runID] {
+			delete(s.remote, runID)
+			changed = true
+		}
+	}
+	if changed {
+		s.saveRemoteMeta()
+	}
+
+	return filter.page(results)
+}
+
+// Delete removes a run locally and from object storage
+func (s *ObjectStore) Delete(runID string) error {
+	if err := s.local.Delete(runID); err != nil {
+		return err
+	}
+
+	s.metaMu.Lock()
+	if _, ok := s.remote[runID]; ok {
+		delete(s.remote, runID)
+		s.saveRemoteMeta()
+	}
+	s.metaMu.Unlock()
+
+	ctx := context.Background()
+	keys, err := s.storage.List(ctx, s.runKey(runID, ""))
+	if err != nil {
+		return fmt.Errorf("list remote run: %w", err)
+	}
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			return fmt.Errorf("delete %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Flush blocks until all scheduled uploads have finished or ctx is done.
+func (s *ObjectStore) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HealthCheck verifies the local staging store is writable
+func (s *ObjectStore) HealthCheck(ctx context.Context) error {
+	return s.local.HealthCheck(ctx)
+}
+
+// Close waits for pending uploads and stops the background uploaders.
+// Uploads waiting to retry are retried immediately rather than after
+// their backoff.
+func (s *ObjectStore) Close() error {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		s.mu.Unlock()
+
+		close(s.done)
+		s.pending.Wait()
+		close(s.uploads)
+		s.workers.Wait()
+	})
+	return nil
+}
+
+// Local returns the local write buffer
+func (s *ObjectStore) Local() *FileStore {
+	return s.local
+}
+
+func (s *ObjectStore) enqueue(runID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStoreClosed
+	}
+	s.pending.Add(1)
+	s.uploads <- uploadJob{runID: runID, attempt: 1}
+	return nil
+}
+
+func (s *ObjectStore) uploadWorker() {
+	defer s.workers.Done()
+
+	for job := range s.uploads {
+		err := s.upload(context.Background(), job.runID)
+		if err == nil {
+			s.pending.Done()
+			continue
+		}
+
+		if job.attempt >= s.maxAttempts {
+			slog.Error("transcript upload failed",
+				slog.String("run_id", job.runID),
+				slog.Int("attempts", job.attempt),
+				slog.String("error", err.Error()))
+			s.pending.Done()
+			continue
+		}
+
+		slog.Warn("transcript upload failed, retrying",
+			slog.String("run_id", job.runID),
+			slog.Int("attempt", job.attempt),
+			slog.String("error", err.Error()))
+
+		// Retry after a short backoff without blocking this worker's queue slot
+		job.attempt++
+		go func(job uploadJob) {
+			timer := time.NewTimer(time.Duration(job.attempt) * time.Second)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-s.done:
+			}
+			// Close keeps the uploads channel open until pending jobs finish
+			s.uploads <- job
+		}(job)
+	}
+}
+
+// upload copies every file in the local run directory to object storage.
+// metadata.json goes last so remote listings only see complete runs.
+func (s *ObjectStore) upload(ctx context.Context, runID string) error {
+	runDir := filepath.Join(s.local.BaseDir(), "runs", runID)
+
+	// Walk subdirectories too, so overflow artifacts travel with the run
+	var names []string
+	err := filepath.WalkDir(runDir, func(p string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(runDir, p)
+		if err != nil {
+			return err
+		}
+		if name := filepath.ToSlash(rel); name != "metadata.json" {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	names = append(names, "metadata.json")
+
+	for _, name := range names {
+		if err := s.putFile(ctx, filepath.Join(runDir, filepath.FromSlash(name)), s.runKey(runID, name)); err != nil {
+			return fmt.Errorf("upload %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (s *ObjectStore) putFile(ctx context.Context, localPath, key string) error {
+	f, err := os.Open(localPath) //nolint:gosec // path built from store base dir
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	return s.storage.Put(ctx, key, f)
+}
+
+// download copies a remote run into the local cache
+func (s *ObjectStore) download(ctx context.Context, runID string) error {
+	keys, err := s.storage.List(ctx, s.runKey(runID, ""))
+	if err != nil {
+		return fmt.Errorf("list remote run: %w", err)
+	}
+	if len(keys) == 0 {
+		return ErrRunNotFound
+	}
+
+	runDir := filepath.Join(s.local.BaseDir(), "runs", runID)
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		return err
+	}
+
+	runPrefix := s.runKey(runID, "")
+	for _, key := range keys {
+		name := strings.TrimPrefix(key, runPrefix)
+		localPath := filepath.Join(runDir, filepath.FromSlash(name))
+		if !strings.HasPrefix(localPath, runDir+string(filepath.Separator)) {
+			continue // never write outside the run directory
+		}
+		if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+			return err
+		}
+		if err := s.getFile(ctx, key, localPath); err != nil {
+			return fmt.Errorf("download %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (s *ObjectStore) getFile(ctx context.Context, key, localPath string) error {
+	rc, err := s.storage.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+
+	// Write to a temp file first so a failed download never leaves a partial cache entry
+	tmp := localPath + ".tmp"
+	f, err := os.Create(tmp) //nolint:gosec // path built from store base dir
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, rc); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, localPath)
+}
+
+func (s *ObjectStore) remoteMetadata(ctx context.Context, runID string) (*Meta, error) {
+	rc, err := s.storage.Get(ctx, s.runKey(runID, "metadata.json"))
+	if err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil, ErrRunNotFound
+		}
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta Meta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+func (s *ObjectStore) cachedMeta(runID string) (Meta, bool) {
+	s.metaMu.Lock()
+	defer s.metaMu.Unlock()
+	meta, ok := s.remote[runID]
+	return meta, ok
+}
+
+// loadRemoteMeta reads the remote metadata cache, starting empty if it is
+// missing or unreadable
+func loadRemoteMeta(baseDir string) map[string]Meta {
+	remote := make(map[string]Meta)
+	data, err := os.ReadFile(filepath.Join(baseDir, remoteMetaFile)) //nolint:gosec // path built from store base dir
+	if err != nil {
+		return remote
+	}
+	if err := json.Unmarshal(data, &remote); err != nil {
+		slog.Debug("ignoring unreadable remote transcript cache", slog.String("error", err.Error()))
+		return make(map[string]Meta)
+	}
+	return remote
+}
+
+// saveRemoteMeta persists the remote metadata cache; callers hold metaMu.
+// Failures only cost extra downloads on the next listing, so they are logged.
+func (s *ObjectStore) saveRemoteMeta() {
+	err := func() error {
+		data, err := json.Marshal(s.remote)
+		if err != nil {
+			return err
+		}
+		p := filepath.Join(s.local.BaseDir(), remoteMetaFile)
+		if err := os.WriteFile(p+".tmp", data, 0644); err != nil {
+			return err
+		}
+		return os.Rename(p+".tmp", p)
+	}()
+	if err != nil {
+		slog.Debug("saving remote transcript cache failed", slog.String("error", err.Error()))
+	}
+}
+
+func (s *ObjectStore) key(rel string) string {
+	return strings.TrimPrefix(path.Join(s.prefix, rel), "/") + trailingSlash(rel)
+}
+
+func (s *ObjectStore) runKey(runID, name string) string {
+	if name == "" {
+		return s.key("runs/" + runID + "/")
+	}
+	return s.key("runs/" + runID + "/" + name)
+}
+
+// trailingSlash preserves a trailing slash that path.Join would strip
+func trailingSlash(rel string) string {
+	if strings.HasSuffix(rel, "/") {
+		return "/"
+	}
+	return ""
+}
+
+// DirStorage is an ObjectStorage backed by a local or network-mounted
+// directory. Useful for shared filesystems and for testing.
+type DirStorage struct {
+	dir string
+}
+
+// NewDirStorage creates directory-backed object storage
+func NewDirStorage(dir string) *DirStorage {
+	return &DirStorage{dir: dir}
+}
+
+// Put writes the object to dir/key
+func (d *DirStorage) Put(_ context.Context, key string, r io.Reader) error {
+	p := filepath.Join(d.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0644)
+}
+
+// Get opens dir/key
+func (d *DirStorage) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(d.dir, filepath.FromSlash(key))) //nolint:gosec // key is store-controlled
+	if os.IsNotExist(err) {
+		return nil, ErrObjectNotFound
+	}
+	return f, err
+}
+
+// Delete removes dir/key
+func (d *DirStorage) Delete(_ context.Context, key string) error {
+	err := os.Remove(filepath.Join(d.dir, filepath.FromSlash(key)))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// List walks dir and returns keys beginning with prefix
+func (d *DirStorage) List(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(d.dir, func(p string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(d.dir, p)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	return keys, err
+}
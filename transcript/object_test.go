@@ -0,0 +1,128 @@
+package transcript
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingStorage wraps ObjectStorage, counting Gets and optionally failing Puts
+type countingStorage struct {
+	ObjectStorage
+	gets    atomic.Int32
+	failPut atomic.Bool
+}
+
+func (c *countingStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	c.gets.Add(1)
+	return c.ObjectStorage.Get(ctx, key)
+}
+
+func (c *countingStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	if c.failPut.Load() {
+		return errors.New("storage unavailable")
+	}
+	return c.ObjectStorage.Put(ctx, key, r)
+}
+
+func newTestObjectStore(t *testing.T, storage ObjectStorage) *ObjectStore {
+	t.Helper()
+	s, err := NewObjectStore(ObjectStoreConfig{BaseDir: t.TempDir(), Storage: storage, UploadWorkers: 1})
+	if err != nil {
+		t.Fatalf("NewObjectStore() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestObjectStore_ListCachesRemoteMetadata(t *testing.T) {
+	storage := &countingStorage{ObjectStorage: NewDirStorage(t.TempDir())}
+
+	writer := newTestObjectStore(t, storage)
+	for _, runID := range []string{"run-1", "run-2"} {
+		if err := writer.StartRun(runID, RunMetadata{FlowID: "flow"}); err != nil {
+			t.Fatalf("StartRun() error = %v", err)
+		}
+		if err := writer.EndRun(runID, RunStatusCompleted); err != nil {
+			t.Fatalf("EndRun() error = %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reader := newTestObjectStore(t, storage)
+	for range 3 {
+		runs, err := reader.List(ListFilter{})
+		if err != nil || len(runs) != 2 {
+			t.Fatalf("List() = %d runs, %v; want 2", len(runs), err)
+		}
+	}
+	storage.gets.Store(0)
+
+	// A new store on the same cache directory lists without downloading
+	cached, err := NewObjectStore(ObjectStoreConfig{BaseDir: reader.Local().BaseDir(), Storage: storage})
+	if err != nil {
+		t.Fatalf("NewObjectStore() error = %v", err)
+	}
+	defer func() { _ = cached.Close() }()
+	if runs, err := cached.List(ListFilter{}); err != nil || len(runs) != 2 {
+		t.Fatalf("List() = %d runs, %v; want 2", len(runs), err)
+	}
+	if n := storage.gets.Load(); n != 0 {
+		t.Errorf("List() made %d remote Gets with a warm cache, want 0", n)
+	}
+
+	// Deleted runs drop out of the cache
+	if err := reader.Delete("run-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if runs, err := cached.List(ListFilter{}); err != nil || len(runs) != 1 || runs[0].RunID != "run-2" {
+		t.Errorf("List() after delete = %+v, %v", runs, err)
+	}
+}
+
+func TestObjectStore_EndRunAfterClose(t *testing.T) {
+	s := newTestObjectStore(t, NewDirStorage(t.TempDir()))
+	if err := s.StartRun("run-1", RunMetadata{}); err != nil {
+		t.Fatalf("StartRun() error = %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if err := s.EndRun("run-1", RunStatusCompleted); !errors.Is(err, ErrStoreClosed) {
+		t.Errorf("EndRun() after Close error = %v, want ErrStoreClosed", err)
+	}
+	if _, err := s.Local().Load("run-1"); err != nil {
+		t.Errorf("run not kept locally: %v", err)
+	}
+}
+
+func TestObjectStore_CloseSkipsRetryBackoff(t *testing.T) {
+	storage := &countingStorage{ObjectStorage: NewDirStorage(t.TempDir())}
+	storage.failPut.Store(true)
+
+	s, err := NewObjectStore(ObjectStoreConfig{BaseDir: t.TempDir(), Storage: storage, MaxUploadAttempts: 5})
+	if err != nil {
+		t.Fatalf("NewObjectStore() error = %v", err)
+	}
+	if err := s.StartRun("run-1", RunMetadata{}); err != nil {
+		t.Fatalf("StartRun() error = %v", err)
+	}
+	if err := s.EndRun("run-1", RunStatusCompleted); err != nil {
+		t.Fatalf("EndRun() error = %v", err)
+	}
+
+	// Backoff across five attempts would take ten seconds
+	start := time.Now()
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Close() took %v, want it to skip retry backoff", elapsed)
+	}
+}
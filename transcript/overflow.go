@@ -0,0 +1,63 @@
+package transcript
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"unicode/utf8"
+)
+
+// DefaultToolOutputPreview is the number of bytes of an overflowed tool
+// output kept inline in the transcript
+const DefaultToolOutputPreview = 2 * 1024 // 2KB
+
+// ArtifactSaver stores tool output that overflows StoreConfig.MaxToolOutput.
+// *artifact.Manager implements it.
+type ArtifactSaver interface {
+	SaveArtifact(runID, name string, data []byte) error
+}
+
+// OutputRef points at the full output of a tool call whose Output was
+// truncated to a preview
+type OutputRef struct {
+	Artifact string `json:"artifact"` // Artifact name within the run
+	Size     int    `json:"size"`     // Size of the full output in bytes
+}
+
+var unsafeArtifactChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// spillToolOutput moves tool output larger than the store's limit to an
+// artifact, leaving a preview and a reference. If the artifact cannot be
+// written the output is kept inline rather than lost. Caller holds s.mu.
+func (s *FileStore) spillToolOutput(runID string, turnID, index int, tc *ToolCall) {
+	if s.maxToolOutput <= 0 || len(tc.Output) <= s.maxToolOutput {
+		return
+	}
+
+	name := fmt.Sprintf("tool-output/turn-%03d-%02d-%s.txt",
+		turnID, index, unsafeArtifactChars.ReplaceAllString(tc.Name, "_"))
+	if err := s.artifacts.SaveArtifact(runID, name, []byte(tc.Output)); err != nil {
+		slog.Warn("failed to save tool output artifact, keeping output inline",
+			slog.String("run_id", runID),
+			slog.String("artifact", name),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	size := len(tc.Output)
+	preview := previewPrefix(tc.Output, s.toolOutputPreview)
+	tc.Output = fmt.Sprintf("%s\n... [truncated %d of %d bytes; full output in artifact %s]",
+		preview, size-len(preview), size, name)
+	tc.OutputRef = &OutputRef{Artifact: name, Size: size}
+}
+
+// previewPrefix returns at most n bytes of s without splitting a UTF-8 rune
+func previewPrefix(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
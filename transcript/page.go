@@ -0,0 +1,151 @@
+package transcript
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SortField selects the order of List results
+type SortField string
+
+const (
+	SortByStartedAt SortField = "started_at" // Default
+	SortByCost      SortField = "cost"
+	SortByTokens    SortField = "tokens" // In + out
+)
+
+// ErrInvalidCursor is returned when a ListFilter.Cursor cannot be decoded or
+// was issued for a different sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ListPage is one page of List results. NextCursor is empty on the last page.
+type ListPage struct {
+	Runs       []Meta `json:"runs"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// pageCursor records the sort key of the last run on a page. Pages resume
+// strictly after it, so runs added or removed between requests never cause
+// duplicates or gaps.
+type pageCursor struct {
+	Sort      SortField `json:"s"`
+	Ascending bool      `json:"a,omitempty"`
+	StartedAt int64     `json:"t"`
+	Cost      float64   `json:"c,omitempty"`
+	Tokens    int       `json:"k,omitempty"`
+	RunID     string    `json:"id"`
+}
+
+// sortField returns the filter's sort field, defaulting to SortByStartedAt
+func (f ListFilter) sortField() SortField {
+	if f.SortBy == "" {
+		return SortByStartedAt
+	}
+	return f.SortBy
+}
+
+// compare orders a before b (negative), after b (positive), or equal (zero)
+// by the filter's sort field and direction, breaking ties by run ID
+func (f ListFilter) compare(a, b Meta) int {
+	c := 0
+	switch f.sortField() {
+	case SortByCost:
+		c = cmpOrdered(a.TotalCost, b.TotalCost)
+	case SortByTokens:
+		c = cmpOrdered(a.TotalTokensIn+a.TotalTokensOut, b.TotalTokensIn+b.TotalTokensOut)
+	default:
+		c = a.StartedAt.Compare(b.StartedAt)
+	}
+	if c == 0 {
+		c = cmpOrdered(a.RunID, b.RunID)
+	}
+	if !f.Ascending {
+		c = -c
+	}
+	return c
+}
+
+func cmpOrdered[T int | float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// page sorts matching results, skips past the cursor, and applies Limit
+func (f ListFilter) page(results []Meta) (*ListPage, error) {
+	switch f.sortField() {
+	case SortByStartedAt, SortByCost, SortByTokens:
+	default:
+		return nil, fmt.Errorf("unknown sort field %q", f.SortBy)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return f.compare(results[i], results[j]) < 0
+	})
+
+	if f.Cursor != "" {
+		cursor, err := f.decodeCursor()
+		if err != nil {
+			return nil, err
+		}
+		last := cursor.meta()
+		start := sort.Search(len(results), func(i int) bool {
+			return f.compare(results[i], last) > 0
+		})
+		results = results[start:]
+	}
+
+	page := &ListPage{Runs: results}
+	if f.Limit > 0 && len(results) > f.Limit {
+		page.Runs = results[:f.Limit]
+		page.NextCursor = f.encodeCursor(page.Runs[f.Limit-1])
+	}
+	return page, nil
+}
+
+// encodeCursor returns an opaque cursor resuming after meta
+func (f ListFilter) encodeCursor(meta Meta) string {
+	data, _ := json.Marshal(pageCursor{
+		Sort:      f.sortField(),
+		Ascending: f.Ascending,
+		StartedAt: meta.StartedAt.UnixNano(),
+		Cost:      meta.TotalCost,
+		Tokens:    meta.TotalTokensIn + meta.TotalTokensOut,
+		RunID:     meta.RunID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor decodes the filter's cursor and checks it matches the sort order
+func (f ListFilter) decodeCursor() (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(f.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != f.sortField() || c.Ascending != f.Ascending {
+		return nil, fmt.Errorf("%w: issued for a different sort order", ErrInvalidCursor)
+	}
+	return &c, nil
+}
+
+// meta returns a Meta carrying the cursor's sort keys, for comparison
+func (c *pageCursor) meta() Meta {
+	return Meta{
+		RunID:         c.RunID,
+		StartedAt:     time.Unix(0, c.StartedAt),
+		TotalCost:     c.Cost,
+		TotalTokensIn: c.Tokens,
+	}
+}
@@ -0,0 +1,186 @@
+package transcript
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// pageTestRuns has ties on every sort key
+func pageTestRuns() []Meta {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	runs := []struct {
+		id     string
+		minute int
+		cost   float64
+		tokens int
+	}{
+		{"run-a", 0, 0.50, 100},
+		{"run-b", 1, 0.25, 300},
+		{"run-c", 1, 0.50, 100},
+		{"run-d", 2, 0.10, 200},
+		{"run-e", 3, 0.50, 300},
+		{"run-f", 3, 0.25, 100},
+		{"run-g", 4, 1.00, 200},
+	}
+	metas := make([]Meta, len(runs))
+	for i, r := range runs {
+		metas[i] = Meta{
+			RunID:          r.id,
+			StartedAt:      base.Add(time.Duration(r.minute) * time.Minute),
+			TotalCost:      r.cost,
+			TotalTokensIn:  r.tokens / 2,
+			TotalTokensOut: r.tokens - r.tokens/2,
+		}
+	}
+	return metas
+}
+
+// walkPages collects run IDs page by page, calling between after each page
+func walkPages(t *testing.T, filter ListFilter, runs func() []Meta, between func(page int)) []string {
+	t.Helper()
+	var ids []string
+	for page := 1; ; page++ {
+		result, err := filter.page(runs())
+		if err != nil {
+			t.Fatalf("page %d error = %v", page, err)
+		}
+		if len(result.Runs) > filter.Limit {
+			t.Fatalf("page %d has %d runs, limit %d", page, len(result.Runs), filter.Limit)
+		}
+		for _, m := range result.Runs {
+			ids = append(ids, m.RunID)
+		}
+		if result.NextCursor == "" {
+			return ids
+		}
+		if between != nil {
+			between(page)
+		}
+		filter.Cursor = result.NextCursor
+		if page > 100 {
+			t.Fatal("paging did not terminate")
+		}
+	}
+}
+
+func TestListFilter_PageWalksEverySortOrder(t *testing.T) {
+	keys := map[SortField]func(Meta) float64{
+		SortByStartedAt: func(m Meta) float64 { return float64(m.StartedAt.UnixNano()) },
+		SortByCost:      func(m Meta) float64 { return m.TotalCost },
+		SortByTokens:    func(m Meta) float64 { return float64(m.TotalTokensIn + m.TotalTokensOut) },
+	}
+	byID := make(map[string]Meta)
+	for _, m := range pageTestRuns() {
+		byID[m.RunID] = m
+	}
+
+	for field, key := range keys {
+		for _, ascending := range []bool{true, false} {
+			for _, limit := range []int{1, 2, 3} {
+				filter := ListFilter{SortBy: field, Ascending: ascending, Limit: limit}
+				ids := walkPages(t, filter, pageTestRuns, nil)
+
+				sorted := slices.Clone(ids)
+				slices.Sort(sorted)
+				if !slices.Equal(sorted, []string{"run-a", "run-b", "run-c", "run-d", "run-e", "run-f", "run-g"}) {
+					t.Errorf("%s asc=%v limit=%d: runs = %v, want each run once", field, ascending, limit, ids)
+					continue
+				}
+				for i := 1; i < len(ids); i++ {
+					prev, cur := byID[ids[i-1]], byID[ids[i]]
+					kp, kc := key(prev), key(cur)
+					inOrder := kp < kc || (kp == kc && prev.RunID < cur.RunID)
+					if !ascending {
+						inOrder = kp > kc || (kp == kc && prev.RunID > cur.RunID)
+					}
+					if !inOrder {
+						t.Errorf("%s asc=%v limit=%d: %s before %s", field, ascending, limit, prev.RunID, cur.RunID)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestListFilter_PageWithInsertBetweenRequests(t *testing.T) {
+	runs := pageTestRuns()
+	current := func() []Meta { return slices.Clone(runs) }
+
+	// Newest first; after the first page (run-g, run-f) add one run that
+	// sorts before the cursor and one that sorts after it
+	filter := ListFilter{Limit: 2}
+	ids := walkPages(t, filter, current, func(page int) {
+		if page != 1 {
+			return
+		}
+		runs = append(runs,
+			Meta{RunID: "run-newest", StartedAt: runs[6].StartedAt.Add(time.Hour)},
+			Meta{RunID: "run-0", StartedAt: runs[0].StartedAt.Add(-time.Hour)},
+		)
+	})
+
+	want := []string{"run-g", "run-f", "run-e", "run-d", "run-c", "run-b", "run-a", "run-0"}
+	if !slices.Equal(ids, want) {
+		t.Errorf("pages = %v, want %v", ids, want)
+	}
+}
+
+func TestListFilter_PageRejectsCursor(t *testing.T) {
+	first, err := ListFilter{SortBy: SortByCost, Limit: 2}.page(pageTestRuns())
+	if err != nil || first.NextCursor == "" {
+		t.Fatalf("page() = %+v, %v; want a next cursor", first, err)
+	}
+
+	tests := map[string]ListFilter{
+		"different sort field": {SortBy: SortByTokens, Limit: 2, Cursor: first.NextCursor},
+		"different direction":  {SortBy: SortByCost, Ascending: true, Limit: 2, Cursor: first.NextCursor},
+		"not base64":           {Limit: 2, Cursor: "not a cursor!"},
+		"not json":             {Limit: 2, Cursor: "bm90IGpzb24"},
+	}
+	for name, filter := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := filter.page(pageTestRuns()); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("page() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestListFilter_PageUnknownSortField(t *testing.T) {
+	if _, err := (ListFilter{SortBy: "duration"}).page(pageTestRuns()); err == nil {
+		t.Error("page() error = nil, want unknown sort field")
+	}
+}
+
+func TestFileStore_ListPage(t *testing.T) {
+	store, err := NewFileStore(StoreConfig{BaseDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	for _, runID := range []string{"run-1", "run-2", "run-3"} {
+		if err := store.StartRun(runID, RunMetadata{FlowID: "flow"}); err != nil {
+			t.Fatalf("StartRun() error = %v", err)
+		}
+	}
+
+	filter := ListFilter{SortBy: SortByStartedAt, Ascending: true, Limit: 2}
+	var got []string
+	for {
+		page, err := store.ListPage(filter)
+		if err != nil {
+			t.Fatalf("ListPage() error = %v", err)
+		}
+		for _, m := range page.Runs {
+			got = append(got, m.RunID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+	if !slices.Equal(got, []string{"run-1", "run-2", "run-3"}) {
+		t.Errorf("ListPage() pages = %v, want run-1, run-2, run-3", got)
+	}
+}
@@ -0,0 +1,87 @@
+package transcript
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/randalmurphal/llmkit/model"
+)
+
+// ModelPrice is the price of a model in USD per 1K tokens
+type ModelPrice struct {
+	InputPer1K  float64 `json:"inputPer1K" yaml:"inputPer1K"`
+	OutputPer1K float64 `json:"outputPer1K" yaml:"outputPer1K"`
+}
+
+// Cost returns the cost of the given token counts
+func (p ModelPrice) Cost(tokensIn, tokensOut int) float64 {
+	return float64(tokensIn)/1000*p.InputPer1K + float64(tokensOut)/1000*p.OutputPer1K
+}
+
+// PricingRegistry maps model names to prices.
+//
+// Lookups try an exact match first, then the longest registered name
+// contained in the model string, so "claude-sonnet-4-20250514" resolves
+// to the "sonnet" entry.
+type PricingRegistry struct {
+	mu     sync.RWMutex
+	prices map[string]ModelPrice
+}
+
+// NewPricingRegistry creates a registry seeded with llmkit's model prices
+func NewPricingRegistry() *PricingRegistry {
+	r := &PricingRegistry{prices: make(map[string]ModelPrice)}
+	for name, p := range model.ModelPrices {
+		r.prices[string(name)] = ModelPrice{
+			InputPer1K:  p.InputPerMillion / 1000,
+			OutputPer1K: p.OutputPerMillion / 1000,
+		}
+	}
+	return r
+}
+
+// Set registers or replaces the price for a model
+func (r *PricingRegistry) Set(modelName string, price ModelPrice) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prices[strings.ToLower(modelName)] = price
+}
+
+// Lookup returns the price for a model
+func (r *PricingRegistry) Lookup(modelName string) (ModelPrice, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	name := strings.ToLower(modelName)
+	if p, ok := r.prices[name]; ok {
+		return p, true
+	}
+
+	var best string
+	for registered := range r.prices {
+		if strings.Contains(name, registered) && len(registered) > len(best) {
+			best = registered
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return r.prices[best], true
+}
+
+// Cost returns the cost of a call to modelName, or 0 if the model is unknown
+func (r *PricingRegistry) Cost(modelName string, tokensIn, tokensOut int) float64 {
+	p, ok := r.Lookup(modelName)
+	if !ok {
+		return 0
+	}
+	return p.Cost(tokensIn, tokensOut)
+}
+
+// PriceTurn sets turn.Cost from its model and token counts if not already set
+func (r *PricingRegistry) PriceTurn(turn *Turn) {
+	if turn.Cost != 0 || turn.Model == "" {
+		return
+	}
+	turn.Cost = r.Cost(turn.Model, turn.TokensIn, turn.TokensOut)
+}
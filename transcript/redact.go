@@ -0,0 +1,237 @@
+package transcript
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// RedactionRule is a named pattern whose matches are replaced before a
+// transcript is written.
+type RedactionRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultRedactionRules detect common credentials and personal data.
+var DefaultRedactionRules = []RedactionRule{
+	{Name: "private-key", Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{Name: "aws-access-key", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "github-token", Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{Name: "gitlab-token", Pattern: regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`)},
+	{Name: "slack-token", Pattern: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`)},
+	{Name: "anthropic-key", Pattern: regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}\b`)},
+	{Name: "openai-key", Pattern: regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9]{32,}\b`)},
+	{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\b`)},
+	{Name: "bearer-token", Pattern: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{20,}=*`)},
+	{Name: "secret-assignment", Pattern: regexp.MustCompile(`(?i)\b(?:api[_-]?key|secret|password|passwd|token|access[_-]?key)["']?\s*[:=]\s*["']?[^\s"',;]{8,}`)},
+	{Name: "email", Pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+}
+
+// highEntropyCandidate matches tokens long enough to be generated secrets.
+var highEntropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/_=-]{24,}`)
+
+// Redactor replaces secrets in transcript content before it is stored.
+//
+// Rules are applied in order, then any remaining long token whose Shannon
+// entropy exceeds EntropyThreshold is redacted. Matches are replaced with
+// "[REDACTED:<rule>]".
+type Redactor struct {
+	Rules []RedactionRule
+
+	// EntropyThreshold is the minimum bits per character for the entropy
+	// detector (0 disables it). Random base64 scores about 6, English
+	// prose and identifiers below 4.
+	EntropyThreshold float64
+}
+
+// NewRedactor creates a Redactor with DefaultRedactionRules and a 4.5
+// bits/char entropy detector.
+func NewRedactor() *Redactor {
+	rules := make([]RedactionRule, len(DefaultRedactionRules))
+	copy(rules, DefaultRedactionRules)
+	return &Redactor{
+		Rules:            rules,
+		EntropyThreshold: 4.5,
+	}
+}
+
+// AddRule appends a custom rule
+func (r *Redactor) AddRule(name, pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	r.Rules = append(r.Rules, RedactionRule{Name: name, Pattern: re})
+	return nil
+}
+
+// Redact returns text with secrets replaced and the number of matches per rule.
+func (r *Redactor) Redact(text string) (string, map[string]int) {
+	counts := make(map[string]int)
+	if text == "" {
+		return text, counts
+	}
+
+	for _, rule := range r.Rules {
+		text = rule.Pattern.ReplaceAllStringFunc(text, func(match string) string {
+			// Leave earlier redactions alone, e.g. "API_KEY=[REDACTED:...]"
+			if strings.Contains(match, "[REDACTED:") {
+				return match
+			}
+			counts[rule.Name]++
+			return redactionMarker(rule.Name)
+		})
+	}
+
+	if r.EntropyThreshold > 0 {
+		text = highEntropyCandidate.ReplaceAllStringFunc(text, func(match string) string {
+			if strings.HasPrefix(match, "[REDACTED") || shannonEntropy(match) < r.EntropyThreshold {
+				return match
+			}
+			counts["high-entropy"]++
+			return redactionMarker("high-entropy")
+		})
+	}
+
+	return text, counts
+}
+
+// RedactTurn redacts a turn's content and tool calls in place
+func (r *Redactor) RedactTurn(turn *Turn) map[string]int {
+	var counts map[string]int
+	turn.Content, counts = r.Redact(turn.Content)
+	for i := range turn.ToolCalls {
+		mergeCounts(counts, r.RedactToolCall(&turn.ToolCalls[i]))
+	}
+	return counts
+}
+
+// RedactToolCall redacts a tool call's input values, output, and error in place
+func (r *Redactor) RedactToolCall(tc *ToolCall) map[string]int {
+	counts := make(map[string]int)
+
+	var c map[string]int
+	tc.Output, c = r.Redact(tc.Output)
+	mergeCounts(counts, c)
+	tc.Error, c = r.Redact(tc.Error)
+	mergeCounts(counts, c)
+
+	if tc.Input != nil {
+		redacted, _ := r.redactValue(tc.Input, counts).(map[string]any)
+		tc.Input = redacted
+	}
+	return counts
+}
+
+// redactValue walks JSON-like values, redacting every string
+func (r *Redactor) redactValue(v any, counts map[string]int) any {
+	switch val := v.(type) {
+	case string:
+		out, c := r.Redact(val)
+		mergeCounts(counts, c)
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = r.redactValue(item, counts)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = r.redactValue(item, counts)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// RedactionReport summarizes the redactions made during a run
+type RedactionReport struct {
+	RunID  string         `json:"runId"`
+	Total  int            `json:"total"`
+	ByRule map[string]int `json:"byRule"`
+}
+
+// Rules returns the names of rules that matched, sorted
+func (r *RedactionReport) Rules() []string {
+	names := make([]string, 0, len(r.ByRule))
+	for name := range r.ByRule {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *RedactionReport) add(counts map[string]int) {
+	for name, n := range counts {
+		r.ByRule[name] += n
+		r.Total += n
+	}
+}
+
+// redactionReportFile stores the per-run RedactionReport
+const redactionReportFile = "redactions.json"
+
+func (r *RedactionReport) save(runDir string) error {
+	if r.Total == 0 {
+		return nil
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(runDir, redactionReportFile), data, 0644)
+}
+
+func loadRedactionReport(runDir, runID string) (*RedactionReport, error) {
+	data, err := os.ReadFile(filepath.Join(runDir, redactionReportFile)) //nolint:gosec // path built from store base dir
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &RedactionReport{RunID: runID, ByRule: map[string]int{}}, nil
+		}
+		return nil, err
+	}
+
+	var report RedactionReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func redactionMarker(rule string) string {
+	return "[REDACTED:" + rule + "]"
+}
+
+func mergeCounts(dst, src map[string]int) {
+	for name, n := range src {
+		dst[name] += n
+	}
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	freq := make(map[rune]int)
+	n := 0
+	for _, c := range s {
+		freq[c]++
+		n++
+	}
+
+	var entropy float64
+	for _, count := range freq {
+		p := float64(count) / float64(n)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
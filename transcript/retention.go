@@ -0,0 +1,309 @@
+package transcript
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// RetentionPolicy decides which finished runs are due for archival or purge.
+// Zero values disable the corresponding limit. Running runs, and failed runs
+// with KeepFailed, are never selected but still count toward MaxCount and
+// MaxSize, so the limits bound everything under BaseDir as closely as they can.
+type RetentionPolicy struct {
+	MaxAge     time.Duration // Select runs that ended longer ago than this
+	MaxCount   int           // Keep at most this many runs (newest first)
+	MaxSize    int64         // Keep at most this many bytes of runs (newest first)
+	KeepFailed bool          // Never select failed runs
+}
+
+// Retention applies a RetentionPolicy to the runs under BaseDir.
+//
+// Archive writes each selected run as <runID>.tar.gz under ArchiveDir/YYYY-MM
+// (or to Storage under <Prefix>archive/YYYY-MM/ when Storage is set) and then
+// removes it; Purge removes selected runs without archiving.
+type Retention struct {
+	BaseDir string
+	Policy  RetentionPolicy
+
+	// ArchiveDir defaults to <BaseDir>/archive.
+	ArchiveDir string
+
+	// Storage, if set, receives archives instead of ArchiveDir.
+	Storage ObjectStorage
+	Prefix  string
+
+	// Index, if set, has removed runs dropped from it.
+	Index *Index
+}
+
+// RetentionResult summarizes an Archive or Purge pass
+type RetentionResult struct {
+	Archived   []string `json:"archived,omitempty"`
+	Purged     []string `json:"purged,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+	BytesFreed int64    `json:"bytesFreed"`
+}
+
+// Select returns the runs the policy would remove, oldest first.
+func (r *Retention) Select() ([]Meta, error) {
+	store, err := NewFileStore(StoreConfig{BaseDir: r.BaseDir})
+	if err != nil {
+		return nil, err
+	}
+
+	// Newest first, so count and size limits keep the most recent runs
+	runs, err := store.List(ListFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var selected []Meta
+	var kept int
+	var keptSize int64
+
+	for _, meta := range runs {
+		size := dirSize(filepath.Join(r.BaseDir, "runs", meta.RunID))
+
+		if meta.Status == RunStatusRunning || (r.Policy.KeepFailed && meta.Status == RunStatusFailed) {
+			kept++
+			keptSize += size
+			continue
+		}
+
+		ended := meta.EndedAt
+		if ended.IsZero() {
+			ended = meta.StartedAt
+		}
+
+		switch {
+		case r.Policy.MaxAge > 0 && now.Sub(ended) > r.Policy.MaxAge,
+			r.Policy.MaxCount > 0 && kept >= r.Policy.MaxCount,
+			r.Policy.MaxSize > 0 && keptSize+size > r.Policy.MaxSize:
+			selected = append(selected, meta)
+		default:
+			kept++
+			keptSize += size
+		}
+	}
+
+	// Oldest first
+	for i, j := 0, len(selected)-1; i < j; i, j = i+1, j-1 {
+		selected[i], selected[j] = selected[j], selected[i]
+	}
+	return selected, nil
+}
+
+// Archive compresses selected runs to the archive destination and removes them.
+func (r *Retention) Archive(ctx context.Context, dryRun bool) (*RetentionResult, error) {
+	selected, err := r.Select()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RetentionResult{}
+	for _, meta := range selected {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		runDir := filepath.Join(r.BaseDir, "runs", meta.RunID)
+		size := dirSize(runDir)
+
+		if !dryRun {
+			if err := r.archiveRun(ctx, meta); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("archive %s: %v", meta.RunID, err))
+				continue
+			}
+			if err := r.removeRun(meta.RunID); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("remove %s: %v", meta.RunID, err))
+				continue
+			}
+		}
+		result.Archived = append(result.Archived, meta.RunID)
+		result.BytesFreed += size
+	}
+
+	return result, r.saveIndex(dryRun)
+}
+
+// Purge deletes selected runs without archiving them.
+func (r *Retention) Purge(ctx context.Context, dryRun bool) (*RetentionResult, error) {
+	selected, err := r.Select()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RetentionResult{}
+	for _, meta := range selected {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		size := dirSize(filepath.Join(r.BaseDir, "runs", meta.RunID))
+		if !dryRun {
+			if err := r.removeRun(meta.RunID); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("purge %s: %v", meta.RunID, err))
+				continue
+			}
+		}
+		result.Purged = append(result.Purged, meta.RunID)
+		result.BytesFreed += size
+	}
+
+	return result, r.saveIndex(dryRun)
+}
+
+// RetentionAction selects what a scheduled retention pass does
+type RetentionAction string
+
+const (
+	RetentionArchive RetentionAction = "archive"
+	RetentionPurge   RetentionAction = "purge"
+)
+
+// Schedule runs the action immediately and then every interval until ctx is
+// canceled, reporting each result to onResult (which may be nil).
+func (r *Retention) Schedule(ctx context.Context, interval time.Duration, action RetentionAction, onResult func(*RetentionResult, error)) {
+	pass := r.Archive
+	if action == RetentionPurge {
+		pass = r.Purge
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := pass(ctx, false)
+		if onResult != nil {
+			onResult(result, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Retention) archiveRun(ctx context.Context, meta Meta) error {
+	runDir := filepath.Join(r.BaseDir, "runs", meta.RunID)
+	month := meta.StartedAt.Format("2006-01")
+	name := meta.RunID + ".tar.gz"
+
+	if r.Storage != nil {
+		var buf bytes.Buffer
+		if err := writeRunArchive(&buf, runDir, meta.RunID); err != nil {
+			return err
+		}
+		key := path.Join(r.Prefix, "archive", month, name)
+		return r.Storage.Put(ctx, key, &buf)
+	}
+
+	archiveDir := r.ArchiveDir
+	if archiveDir == "" {
+		archiveDir = filepath.Join(r.BaseDir, "archive")
+	}
+	dir := filepath.Join(archiveDir, month)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(dir, name+".tmp")
+	f, err := os.Create(tmp) //nolint:gosec // path built from archive dir
+	if err != nil {
+		return err
+	}
+	if err := writeRunArchive(f, runDir, meta.RunID); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, name))
+}
+
+func (r *Retention) removeRun(runID string) error {
+	if err := os.RemoveAll(filepath.Join(r.BaseDir, "runs", runID)); err != nil {
+		return err
+	}
+	if r.Index != nil {
+		r.Index.RemoveRun(runID)
+	}
+	return nil
+}
+
+func (r *Retention) saveIndex(dryRun bool) error {
+	if dryRun || r.Index == nil {
+		return nil
+	}
+	return r.Index.Save()
+}
+
+// writeRunArchive writes runDir as a gzipped tar with entries under runID/
+func writeRunArchive(w io.Writer, runDir, runID string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.Walk(runDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(runDir, p)
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(runID, rel))
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(p) //nolint:gosec // path from walking run dir
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// dirSize returns the total size of regular files under dir
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,224 @@
+package transcript
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// retentionRun describes a run to create for retention tests
+type retentionRun struct {
+	id      string
+	status  RunStatus
+	age     time.Duration // Time since it started; it ended a minute later
+	content int           // Bytes of turn content
+}
+
+// newRetentionStore creates runs under a temp dir and returns the dir
+func newRetentionStore(t *testing.T, runs []retentionRun) string {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := NewFileStore(StoreConfig{BaseDir: dir, Compression: CompressionNone})
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	now := time.Now()
+	for _, run := range runs {
+		if err := store.StartRun(run.id, RunMetadata{FlowID: "flow"}); err != nil {
+			t.Fatalf("StartRun() error = %v", err)
+		}
+		if err := store.RecordTurn(run.id, Turn{Role: "user", Content: strings.Repeat("x", run.content)}); err != nil {
+			t.Fatalf("RecordTurn() error = %v", err)
+		}
+		if run.status != RunStatusRunning {
+			if err := store.EndRun(run.id, run.status); err != nil {
+				t.Fatalf("EndRun() error = %v", err)
+			}
+		}
+
+		meta, err := store.LoadMetadata(run.id)
+		if err != nil {
+			t.Fatalf("LoadMetadata() error = %v", err)
+		}
+		meta.StartedAt = now.Add(-run.age)
+		if run.status != RunStatusRunning {
+			meta.EndedAt = meta.StartedAt.Add(time.Minute)
+		}
+		if err := store.writeMetadata(run.id, meta); err != nil {
+			t.Fatalf("writeMetadata() error = %v", err)
+		}
+	}
+	return dir
+}
+
+func runIDs(metas []Meta) string {
+	ids := make([]string, len(metas))
+	for i, m := range metas {
+		ids[i] = m.RunID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestRetention_Select(t *testing.T) {
+	day := 24 * time.Hour
+	runs := []retentionRun{
+		{"old-failed", RunStatusFailed, 10 * day, 100},
+		{"old", RunStatusCompleted, 9 * day, 100},
+		{"old-running", RunStatusRunning, 8 * day, 100},
+		{"mid", RunStatusCompleted, 3 * day, 100},
+		{"new", RunStatusCompleted, day, 100},
+	}
+	dir := newRetentionStore(t, runs)
+	runSize := dirSize(filepath.Join(dir, "runs", "new")) // Same as mid and old
+
+	tests := []struct {
+		name   string
+		policy RetentionPolicy
+		want   string // Selected runs, oldest first
+	}{
+		{"no limits", RetentionPolicy{}, ""},
+		{"max age", RetentionPolicy{MaxAge: 5 * day}, "old-failed,old"},
+		{"max age keeps failed", RetentionPolicy{MaxAge: 5 * day, KeepFailed: true}, "old"},
+		// The running run fills the third slot
+		{"max count", RetentionPolicy{MaxCount: 3}, "old-failed,old"},
+		{"max count keeps failed", RetentionPolicy{MaxCount: 3, KeepFailed: true}, "old"},
+		{"max size", RetentionPolicy{MaxSize: runSize + runSize/2}, "old-failed,old,mid"},
+		// Without the running run, old would fit beside new and mid
+		{"max size counts running", RetentionPolicy{MaxSize: 3 * runSize}, "old-failed,old"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Retention{BaseDir: dir, Policy: tt.policy}
+			selected, err := r.Select()
+			if err != nil {
+				t.Fatalf("Select() error = %v", err)
+			}
+			if got := runIDs(selected); got != tt.want {
+				t.Errorf("Select() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetention_DryRunRemovesNothing(t *testing.T) {
+	dir := newRetentionStore(t, []retentionRun{
+		{"old", RunStatusCompleted, 48 * time.Hour, 100},
+		{"new", RunStatusCompleted, time.Hour, 100},
+	})
+	r := &Retention{BaseDir: dir, Policy: RetentionPolicy{MaxCount: 1}}
+
+	for name, pass := range map[string]func(context.Context, bool) (*RetentionResult, error){
+		"Archive": r.Archive, "Purge": r.Purge,
+	} {
+		result, err := pass(context.Background(), true)
+		if err != nil {
+			t.Fatalf("%s(dry run) error = %v", name, err)
+		}
+		if got := len(result.Archived) + len(result.Purged); got != 1 || result.BytesFreed == 0 {
+			t.Errorf("%s(dry run) = %+v, want old reported", name, result)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "runs", "old")); err != nil {
+			t.Errorf("%s(dry run) removed the run: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "archive")); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote an archive: %v", err)
+	}
+}
+
+func TestRetention_Archive(t *testing.T) {
+	dir := newRetentionStore(t, []retentionRun{
+		{"old", RunStatusCompleted, 48 * time.Hour, 100},
+		{"new", RunStatusCompleted, time.Hour, 100},
+	})
+	old, err := (&FileStore{baseDir: dir}).LoadMetadata("old")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Retention{BaseDir: dir, Policy: RetentionPolicy{MaxCount: 1}}
+	result, err := r.Archive(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if len(result.Archived) != 1 || result.Archived[0] != "old" || len(result.Errors) != 0 {
+		t.Fatalf("Archive() = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "runs", "old")); !os.IsNotExist(err) {
+		t.Errorf("archived run still on disk: %v", err)
+	}
+
+	archive := filepath.Join(dir, "archive", old.StartedAt.Format("2006-01"), "old.tar.gz")
+	f, err := os.Open(archive)
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	tr := tar.NewReader(gz)
+	names := make(map[string]bool)
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names[h.Name] = true
+	}
+	if !names["old/metadata.json"] || !names["old/transcript.json"] {
+		t.Errorf("archive entries = %v, want old/metadata.json and old/transcript.json", names)
+	}
+}
+
+func TestRetention_ArchiveFailureKeepsRun(t *testing.T) {
+	dir := newRetentionStore(t, []retentionRun{
+		{"old", RunStatusCompleted, 48 * time.Hour, 100},
+	})
+	// A file where the archive directory should be makes every write fail
+	blocked := filepath.Join(t.TempDir(), "archive")
+	if err := os.WriteFile(blocked, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Retention{BaseDir: dir, ArchiveDir: blocked, Policy: RetentionPolicy{MaxAge: time.Hour}}
+	result, err := r.Archive(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if len(result.Archived) != 0 || len(result.Errors) != 1 || result.BytesFreed != 0 {
+		t.Errorf("Archive() = %+v, want one error and nothing archived", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "runs", "old", "metadata.json")); err != nil {
+		t.Errorf("run removed after a failed archive: %v", err)
+	}
+}
+
+func TestRetention_Purge(t *testing.T) {
+	dir := newRetentionStore(t, []retentionRun{
+		{"old", RunStatusCompleted, 48 * time.Hour, 100},
+		{"new", RunStatusCompleted, time.Hour, 100},
+	})
+
+	r := &Retention{BaseDir: dir, Policy: RetentionPolicy{MaxAge: 24 * time.Hour}}
+	result, err := r.Purge(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if len(result.Purged) != 1 || result.Purged[0] != "old" {
+		t.Errorf("Purge() = %+v, want old", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "runs", "old")); !os.IsNotExist(err) {
+		t.Errorf("purged run still on disk: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "runs", "new")); err != nil {
+		t.Errorf("kept run removed: %v", err)
+	}
+}
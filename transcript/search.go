@@ -39,6 +39,36 @@ type SearchResult struct {
 	Match     string `json:"match,omitempty"`
 }
 
+// Search runs an index query (see Index.Search for syntax) when a
+// persisted index exists, falling back to SearchContent otherwise.
+// Without an index, only the query's terms and phrases are used; role,
+// flow, date, and exclusion filters require the index.
+func (s *Searcher) Search(query string, opts SearchOptions) ([]SearchResult, error) {
+	if IndexExists(s.baseDir) {
+		idx, err := OpenIndex(s.baseDir)
+		if err == nil {
+			return idx.Search(query, opts.MaxResults)
+		}
+		slog.Debug("transcript index unreadable, falling back to grep",
+			slog.String("error", err.Error()))
+	}
+
+	q, err := ParseIndexQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := strings.Join(q.Terms, " ")
+	if len(q.Phrases) > 0 {
+		pattern = q.Phrases[0]
+	}
+	if pattern == "" {
+		return nil, nil
+	}
+
+	return s.SearchContent(pattern, opts)
+}
+
 // SearchContent searches transcript content using ripgrep or grep
 func (s *Searcher) SearchContent(query string, opts SearchOptions) ([]SearchResult, error) {
 	runsDir := filepath.Join(s.baseDir, "runs")
@@ -55,8 +85,10 @@ func (s *Searcher) SearchContent(query string, opts SearchOptions) ([]SearchResu
 func (s *Searcher) searchWithRipgrep(runsDir, query string, opts SearchOptions) ([]SearchResult, error) {
 	args := []string{
 		"--json",
+		"--search-zip",
 		"-g", "transcript.json",
 		"-g", "transcript.json.gz",
+		"-g", "transcript.json.zst",
 	}
 
 	if !opts.CaseSensitive {
@@ -331,6 +363,124 @@ func (s *Searcher) RunStats(filter ListFilter) (*Statistics, error) {
 	return stats, nil
 }
 
+// Usage aggregates token usage and cost for a group of turns or runs
+type Usage struct {
+	Runs      int     `json:"runs"`
+	Turns     int     `json:"turns"`
+	TokensIn  int     `json:"tokensIn"`
+	TokensOut int     `json:"tokensOut"`
+	Cost      float64 `json:"cost"`
+}
+
+// CostByModel aggregates per-turn usage and cost by model for matching runs.
+// Turns without a model are grouped under "unknown".
+func (s *Searcher) CostByModel(filter ListFilter) (map[string]*Usage, error) {
+	store, err := NewFileStore(StoreConfig{BaseDir: s.baseDir})
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := store.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	byModel := make(map[string]*Usage)
+	for _, run := range runs {
+		t, err := store.Load(run.RunID)
+		if err != nil {
+			slog.Debug("skipping unreadable transcript",
+				slog.String("run_id", run.RunID),
+				slog.String("error", err.Error()))
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for _, turn := range t.Turns {
+			name := turn.Model
+			if name == "" {
+				name = "unknown"
+			}
+			u, ok := byModel[name]
+			if !ok {
+				u = &Usage{}
+				byModel[name] = u
+			}
+			if !seen[name] {
+				seen[name] = true
+				u.Runs++
+			}
+			u.Turns++
+			u.TokensIn += turn.TokensIn
+			u.TokensOut += turn.TokensOut
+			u.Cost += turn.Cost
+		}
+	}
+
+	return byModel, nil
+}
+
+// CostByFlow aggregates run usage and cost by flow for matching runs
+func (s *Searcher) CostByFlow(filter ListFilter) (map[string]*Usage, error) {
+	store, err := NewFileStore(StoreConfig{BaseDir: s.baseDir})
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := store.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	byFlow := make(map[string]*Usage)
+	for _, run := range runs {
+		u, ok := byFlow[run.FlowID]
+		if !ok {
+			u = &Usage{}
+			byFlow[run.FlowID] = u
+		}
+		u.Runs++
+		u.Turns += run.TurnCount
+		u.TokensIn += run.TotalTokensIn
+		u.TokensOut += run.TotalTokensOut
+		u.Cost += run.TotalCost
+	}
+
+	return byFlow, nil
+}
+
+// CostByTask aggregates the UsageByTask metadata of matching runs by task
+// type. Turns counts LLM calls. Runs without UsageByTask are skipped.
+func (s *Searcher) CostByTask(filter ListFilter) (map[string]*Usage, error) {
+	store, err := NewFileStore(StoreConfig{BaseDir: s.baseDir})
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := store.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	byTask := make(map[string]*Usage)
+	for _, run := range runs {
+		for name, tu := range run.UsageByTask {
+			u, ok := byTask[name]
+			if !ok {
+				u = &Usage{}
+				byTask[name] = u
+			}
+			u.Runs++
+			u.Turns += tu.Calls
+			u.TokensIn += tu.TokensIn
+			u.TokensOut += tu.TokensOut
+			u.Cost += tu.Cost
+		}
+	}
+
+	return byTask, nil
+}
+
 // Statistics holds aggregated run statistics
 type Statistics struct {
 	TotalRuns      int
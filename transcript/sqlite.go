@@ -0,0 +1,550 @@
+package transcript
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sqliteSchema creates the tables and indexes used by SQLiteStore.
+// Times are stored as Unix nanoseconds so range queries use the indexes.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	run_id      TEXT PRIMARY KEY,
+	flow_id     TEXT NOT NULL DEFAULT '',
+	node_id     TEXT NOT NULL DEFAULT '',
+	input       TEXT NOT NULL DEFAULT '{}',
+	started_at  INTEGER NOT NULL,
+	ended_at    INTEGER NOT NULL DEFAULT 0,
+	status      TEXT NOT NULL,
+	tokens_in   INTEGER NOT NULL DEFAULT 0,
+	tokens_out  INTEGER NOT NULL DEFAULT 0,
+	total_cost  REAL NOT NULL DEFAULT 0,
+	turn_count  INTEGER NOT NULL DEFAULT 0,
+	error       TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_runs_flow ON runs(flow_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_runs_status ON runs(status, started_at);
+CREATE INDEX IF NOT EXISTS idx_runs_started ON runs(started_at);
+CREATE INDEX IF NOT EXISTS idx_runs_tokens ON runs((tokens_in + tokens_out));
+CREATE INDEX IF NOT EXISTS idx_runs_cost ON runs(total_cost);
+
+CREATE TABLE IF NOT EXISTS turns (
+	run_id      TEXT NOT NULL REFERENCES runs(run_id) ON DELETE CASCADE,
+	turn_id     INTEGER NOT NULL,
+	role        TEXT NOT NULL,
+	content     TEXT NOT NULL,
+	tokens_in   INTEGER NOT NULL DEFAULT 0,
+	tokens_out  INTEGER NOT NULL DEFAULT 0,
+	timestamp   INTEGER NOT NULL,
+	tool_calls  TEXT NOT NULL DEFAULT '',
+	duration_ms INTEGER NOT NULL DEFAULT 0,
+	model       TEXT NOT NULL DEFAULT '',
+	cost        REAL NOT NULL DEFAULT 0,
+	PRIMARY KEY (run_id, turn_id)
+);
+CREATE INDEX IF NOT EXISTS idx_turns_model ON turns(model);
+`
+
+const runColumns = `run_id, flow_id, node_id, input, started_at, ended_at, status,
+	tokens_in, tokens_out, total_cost, turn_count, error`
+
+// SQLiteStore stores transcripts in a SQLite database.
+//
+// Unlike FileStore, run metadata lives in an indexed table, so List and
+// Query stay fast with many thousands of runs. Turns are written as they
+// are recorded, so an interrupted run keeps every turn recorded so far.
+//
+// SQLiteStore works with any database/sql SQLite driver; the caller opens
+// the database with the driver of their choice:
+//
+//	import _ "modernc.org/sqlite"
+//
+//	db, err := sql.Open("sqlite", ".devflow/transcripts.db")
+//	store, err := transcript.NewSQLiteStore(db)
+type SQLiteStore struct {
+	db      *sql.DB
+	pricing *PricingRegistry
+}
+
+// NewSQLiteStore creates a SQLite-backed transcript store, creating the
+// schema if it does not exist.
+func NewSQLiteStore(db *sql.DB) (*SQLiteStore, error) {
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return nil, fmt.Errorf("create transcript schema: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// WithPricing sets a registry used to price turns that have a Model but no Cost
+func (s *SQLiteStore) WithPricing(pricing *PricingRegistry) *SQLiteStore {
+	s.pricing = pricing
+	return s
+}
+
+// DB returns the underlying database handle
+func (s *SQLiteStore) DB() *sql.DB {
+	return s.db
+}
+
+// Close closes the underlying database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// HealthCheck verifies the database is reachable
+func (s *SQLiteStore) HealthCheck(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping transcript database: %w", err)
+	}
+	return nil
+}
+
+// StartRun begins a new transcript
+func (s *SQLiteStore) StartRun(runID string, meta RunMetadata) error {
+	input, err := json.Marshal(meta.Input)
+	if err != nil {
+		return fmt.Errorf("marshal input: %w", err)
+	}
+
+	res, err := s.db.Exec(`INSERT OR IGNORE INTO runs (run_id, flow_id, node_id, input, started_at, status)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		runID, meta.FlowID, meta.NodeID, string(input), time.Now().UnixNano(), string(RunStatusRunning))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrRunAlreadyExists
+	}
+	return nil
+}
+
+// RecordTurn adds a turn to an active transcript
+func (s *SQLiteStore) RecordTurn(runID string, turn Turn) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var turnCount int
+	if err := s.requireRunning(tx, runID, &turnCount); err != nil {
+		return err
+	}
+
+	turn.ID = turnCount + 1
+	if turn.Timestamp.IsZero() {
+		turn.Timestamp = time.Now()
+	}
+	if s.pricing != nil {
+		s.pricing.PriceTurn(&turn)
+	}
+
+	toolCalls, err := marshalToolCalls(turn.ToolCalls)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`INSERT INTO turns
+		(run_id, turn_id, role, content, tokens_in, tokens_out, timestamp, tool_calls, duration_ms, model, cost)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		runID, turn.ID, turn.Role, turn.Content, turn.TokensIn, turn.TokensOut,
+		turn.Timestamp.UnixNano(), toolCalls, turn.DurationMs, turn.Model, turn.Cost); err != nil {
+		return err
+	}
+
+	// Token accounting matches FileStore: input on user/system, output on assistant
+	var addIn, addOut int
+	switch turn.Role {
+	case "user", "system":
+		addIn = turn.TokensIn
+	case "assistant":
+		addOut = turn.TokensOut
+	}
+
+	if _, err := tx.Exec(`UPDATE runs SET turn_count = ?, tokens_in = tokens_in + ?, tokens_out = tokens_out + ?,
+		total_cost = total_cost + ? WHERE run_id = ?`, turn.ID, addIn, addOut, turn.Cost, runID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RecordToolCall adds a tool call to the last turn of an active transcript
+func (s *SQLiteStore) RecordToolCall(runID string, tc ToolCall) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var turnCount int
+	if err := s.requireRunning(tx, runID, &turnCount); err != nil {
+		return err
+	}
+	if turnCount == 0 {
+		return fmt.Errorf("no turns to add tool call to")
+	}
+
+	var raw string
+	if err := tx.QueryRow(`SELECT tool_calls FROM turns WHERE run_id = ? AND turn_id = ?`,
+		runID, turnCount).Scan(&raw); err != nil {
+		return err
+	}
+
+	calls, err := unmarshalToolCalls(raw)
+	if err != nil {
+		return err
+	}
+	encoded, err := marshalToolCalls(append(calls, tc))
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE turns SET tool_calls = ? WHERE run_id = ? AND turn_id = ?`,
+		encoded, runID, turnCount); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddCost adds cost not attributed to any turn; see FileStore.AddCost
+func (s *SQLiteStore) AddCost(runID string, cost float64) error {
+	res, err := s.db.Exec(`UPDATE runs SET total_cost = total_cost + ? WHERE run_id = ? AND status = ?`,
+		cost, runID, string(RunStatusRunning))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrRunNotStarted
+	}
+	return nil
+}
+
+// EndRun completes a transcript
+func (s *SQLiteStore) EndRun(runID string, status RunStatus) error {
+	return s.endRun(runID, status, "")
+}
+
+// EndRunWithError completes a transcript with an error
+func (s *SQLiteStore) EndRunWithError(runID string, err error) error {
+	var msg string
+	if err != nil {
+		msg = err.Error()
+	}
+	return s.endRun(runID, RunStatusFailed, msg)
+}
+
+func (s *SQLiteStore) endRun(runID string, status RunStatus, errMsg string) error {
+	res, err := s.db.Exec(`UPDATE runs SET status = ?, ended_at = ?, error = ? WHERE run_id = ? AND status = ?`,
+		string(status), time.Now().UnixNano(), errMsg, runID, string(RunStatusRunning))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrRunNotStarted
+	}
+	return nil
+}
+
+// Load retrieves a complete transcript
+func (s *SQLiteStore) Load(runID string) (*Transcript, error) {
+	meta, err := s.LoadMetadata(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT turn_id, role, content, tokens_in, tokens_out, timestamp, tool_calls, duration_ms,
+		model, cost FROM turns WHERE run_id = ? ORDER BY turn_id`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	turns := make([]Turn, 0, meta.TurnCount)
+	for rows.Next() {
+		var turn Turn
+		var ts int64
+		var toolCalls string
+		if err := rows.Scan(&turn.ID, &turn.Role, &turn.Content, &turn.TokensIn, &turn.TokensOut,
+			&ts, &toolCalls, &turn.DurationMs, &turn.Model, &turn.Cost); err != nil {
+			return nil, err
+		}
+		turn.Timestamp = time.Unix(0, ts)
+		if turn.ToolCalls, err = unmarshalToolCalls(toolCalls); err != nil {
+			return nil, err
+		}
+		turns = append(turns, turn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Transcript{
+		RunID:    runID,
+		Metadata: *meta,
+		Turns:    turns,
+	}, nil
+}
+
+// LoadMetadata retrieves just the metadata
+func (s *SQLiteStore) LoadMetadata(runID string) (*Meta, error) {
+	row := s.db.QueryRow(`SELECT `+runColumns+` FROM runs WHERE run_id = ?`, runID)
+	meta, err := scanMeta(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRunNotFound
+	}
+	return meta, err
+}
+
+// List returns metadata for runs matching filter
+func (s *SQLiteStore) List(filter ListFilter) ([]Meta, error) {
+	return s.Query(QueryFilter{ListFilter: filter})
+}
+
+// ListPage returns one page of runs matching filter
+func (s *SQLiteStore) ListPage(filter ListFilter) (*ListPage, error) {
+	return s.QueryPage(QueryFilter{ListFilter: filter})
+}
+
+// QueryFilter extends ListFilter with token and cost ranges.
+// Zero values are ignored.
+type QueryFilter struct {
+	ListFilter
+	MinTokens int     // Minimum total tokens (in + out)
+	MaxTokens int     // Maximum total tokens (in + out)
+	MinCost   float64 // Minimum total cost
+	MaxCost   float64 // Maximum total cost
+}
+
+// Query returns metadata for runs matching filter in the filter's sort order
+func (s *SQLiteStore) Query(filter QueryFilter) ([]Meta, error) {
+	page, err := s.QueryPage(filter)
+	if err != nil {
+		return nil, err
+	}
+	return page.Runs, nil
+}
+
+// QueryPage returns one page of runs matching filter. Sorting and cursor
+// pagination run in SQL, so pages are served from the indexes.
+func (s *SQLiteStore) QueryPage(filter QueryFilter) (*ListPage, error) {
+	var where []string
+	var args []any
+
+	if filter.FlowID != "" {
+		where = append(where, "flow_id = ?")
+		args = append(args, filter.FlowID)
+	}
+	if filter.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, string(filter.Status))
+	}
+	if !filter.After.IsZero() {
+		where = append(where, "started_at >= ?")
+		args = append(args, filter.After.UnixNano())
+	}
+	if !filter.Before.IsZero() {
+		where = append(where, "started_at <= ?")
+		args = append(args, filter.Before.UnixNano())
+	}
+	if filter.MinTokens > 0 {
+		where = append(where, "(tokens_in + tokens_out) >= ?")
+		args = append(args, filter.MinTokens)
+	}
+	if filter.MaxTokens > 0 {
+		where = append(where, "(tokens_in + tokens_out) <= ?")
+		args = append(args, filter.MaxTokens)
+	}
+	if filter.MinCost > 0 {
+		where = append(where, "total_cost >= ?")
+		args = append(args, filter.MinCost)
+	}
+	if filter.MaxCost > 0 {
+		where = append(where, "total_cost <= ?")
+		args = append(args, filter.MaxCost)
+	}
+
+	if !filter.EndedAfter.IsZero() {
+		where = append(where, "ended_at != 0 AND ended_at >= ?")
+		args = append(args, filter.EndedAfter.UnixNano())
+	}
+	if !filter.EndedBefore.IsZero() {
+		where = append(where, "ended_at != 0 AND ended_at <= ?")
+		args = append(args, filter.EndedBefore.UnixNano())
+	}
+
+	var key string
+	switch filter.sortField() {
+	case SortByStartedAt:
+		key = "started_at"
+	case SortByCost:
+		key = "total_cost"
+	case SortByTokens:
+		key = "(tokens_in + tokens_out)"
+	default:
+		return nil, fmt.Errorf("unknown sort field %q", filter.SortBy)
+	}
+	dir, op := "DESC", "<"
+	if filter.Ascending {
+		dir, op = "ASC", ">"
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := filter.decodeCursor()
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		switch filter.sortField() {
+		case SortByCost:
+			value = cursor.Cost
+		case SortByTokens:
+			value = cursor.Tokens
+		default:
+			value = cursor.StartedAt
+		}
+		where = append(where, fmt.Sprintf("(%s %s ? OR (%s = ? AND run_id %s ?))", key, op, key, op))
+		args = append(args, value, value, cursor.RunID)
+	}
+
+	query := `SELECT ` + runColumns + ` FROM runs`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, run_id %s", key, dir, dir)
+	if filter.Limit > 0 {
+		// One extra row tells us whether there is a next page
+		query += " LIMIT ?"
+		args = append(args, filter.Limit+1)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []Meta
+	for rows.Next() {
+		meta, err := scanMeta(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *meta)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &ListPage{Runs: results}
+	if filter.Limit > 0 && len(results) > filter.Limit {
+		page.Runs = results[:filter.Limit]
+		page.NextCursor = filter.encodeCursor(page.Runs[filter.Limit-1])
+	}
+	return page, nil
+}
+
+// Delete removes a run
+func (s *SQLiteStore) Delete(runID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Delete turns explicitly; foreign keys may not be enabled on the connection
+	if _, err := tx.Exec(`DELETE FROM turns WHERE run_id = ?`, runID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM runs WHERE run_id = ?`, runID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListActive returns all active run IDs
+func (s *SQLiteStore) ListActive() ([]string, error) {
+	rows, err := s.db.Query(`SELECT run_id FROM runs WHERE status = ?`, string(RunStatusRunning))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// requireRunning checks that runID is a running run and returns its turn count.
+func (s *SQLiteStore) requireRunning(tx *sql.Tx, runID string, turnCount *int) error {
+	var status string
+	err := tx.QueryRow(`SELECT status, turn_count FROM runs WHERE run_id = ?`, runID).Scan(&status, turnCount)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && RunStatus(status) != RunStatusRunning) {
+		return ErrRunNotStarted
+	}
+	return err
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanMeta(row rowScanner) (*Meta, error) {
+	var meta Meta
+	var input, status string
+	var startedAt, endedAt int64
+
+	if err := row.Scan(&meta.RunID, &meta.FlowID, &meta.NodeID, &input, &startedAt, &endedAt, &status,
+		&meta.TotalTokensIn, &meta.TotalTokensOut, &meta.TotalCost, &meta.TurnCount, &meta.Error); err != nil {
+		return nil, err
+	}
+
+	meta.Status = RunStatus(status)
+	meta.StartedAt = time.Unix(0, startedAt)
+	if endedAt != 0 {
+		meta.EndedAt = time.Unix(0, endedAt)
+	}
+	if input != "" && input != "null" {
+		if err := json.Unmarshal([]byte(input), &meta.Input); err != nil {
+			return nil, fmt.Errorf("decode input for %s: %w", meta.RunID, err)
+		}
+	}
+
+	return &meta, nil
+}
+
+func marshalToolCalls(calls []ToolCall) (string, error) {
+	if len(calls) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(calls)
+	if err != nil {
+		return "", fmt.Errorf("marshal tool calls: %w", err)
+	}
+	return string(data), nil
+}
+
+func unmarshalToolCalls(raw string) ([]ToolCall, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var calls []ToolCall
+	if err := json.Unmarshal([]byte(raw), &calls); err != nil {
+		return nil, fmt.Errorf("decode tool calls: %w", err)
+	}
+	return calls, nil
+}
@@ -0,0 +1,171 @@
+package transcript
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func newTestSQLiteStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "transcripts.db"))
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	store, err := NewSQLiteStore(db)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestSQLiteStore_Lifecycle(t *testing.T) {
+	store := newTestSQLiteStore(t)
+
+	if err := store.StartRun("run-1", RunMetadata{FlowID: "ticket-to-pr", Input: map[string]any{"ticket": "PROJ-1"}}); err != nil {
+		t.Fatalf("StartRun() error = %v", err)
+	}
+	if err := store.StartRun("run-1", RunMetadata{}); !errors.Is(err, ErrRunAlreadyExists) {
+		t.Errorf("StartRun(duplicate) error = %v, want ErrRunAlreadyExists", err)
+	}
+
+	turns := []Turn{
+		{Role: "system", Content: "You are helpful", TokensIn: 5},
+		{Role: "user", Content: "Fix the bug", TokensIn: 10},
+		{Role: "assistant", Content: "Done", TokensOut: 20, Model: "claude-sonnet", Cost: 0.25},
+	}
+	for _, turn := range turns {
+		if err := store.RecordTurn("run-1", turn); err != nil {
+			t.Fatalf("RecordTurn() error = %v", err)
+		}
+	}
+	if err := store.RecordToolCall("run-1", ToolCall{Name: "edit", Output: "ok"}); err != nil {
+		t.Fatalf("RecordToolCall() error = %v", err)
+	}
+
+	active, err := store.ListActive()
+	if err != nil || len(active) != 1 || active[0] != "run-1" {
+		t.Errorf("ListActive() = %v, %v; want [run-1]", active, err)
+	}
+
+	if err := store.EndRun("run-1", RunStatusCompleted); err != nil {
+		t.Fatalf("EndRun() error = %v", err)
+	}
+	if err := store.RecordTurn("run-1", Turn{Role: "user"}); !errors.Is(err, ErrRunNotStarted) {
+		t.Errorf("RecordTurn(ended) error = %v, want ErrRunNotStarted", err)
+	}
+	if err := store.EndRun("run-1", RunStatusCompleted); !errors.Is(err, ErrRunNotStarted) {
+		t.Errorf("EndRun(ended) error = %v, want ErrRunNotStarted", err)
+	}
+
+	loaded, err := store.Load("run-1")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	meta := loaded.Metadata
+	if meta.FlowID != "ticket-to-pr" || meta.Status != RunStatusCompleted || meta.EndedAt.IsZero() ||
+		meta.TurnCount != 3 || meta.TotalTokensIn != 15 || meta.TotalTokensOut != 20 || meta.TotalCost != 0.25 {
+		t.Errorf("Metadata = %+v", meta)
+	}
+	if len(loaded.Turns) != 3 {
+		t.Fatalf("Turns = %d, want 3", len(loaded.Turns))
+	}
+	last := loaded.Turns[2]
+	if last.ID != 3 || last.Content != "Done" || last.Model != "claude-sonnet" ||
+		len(last.ToolCalls) != 1 || last.ToolCalls[0].Name != "edit" {
+		t.Errorf("last turn = %+v", last)
+	}
+
+	if err := store.Delete("run-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Load("run-1"); !errors.Is(err, ErrRunNotFound) {
+		t.Errorf("Load(deleted) error = %v, want ErrRunNotFound", err)
+	}
+}
+
+func TestSQLiteStore_EndRunWithError(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	if err := store.StartRun("run-1", RunMetadata{}); err != nil {
+		t.Fatalf("StartRun() error = %v", err)
+	}
+	if err := store.EndRunWithError("run-1", errors.New("agent crashed")); err != nil {
+		t.Fatalf("EndRunWithError() error = %v", err)
+	}
+
+	meta, err := store.LoadMetadata("run-1")
+	if err != nil || meta.Status != RunStatusFailed || meta.Error != "agent crashed" {
+		t.Errorf("LoadMetadata() = %+v, %v", meta, err)
+	}
+}
+
+func TestSQLiteStore_QueryPage(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	costs := map[string]float64{"run-a": 0.10, "run-b": 0.50, "run-c": 1.25, "run-d": 0.75}
+	for _, runID := range []string{"run-a", "run-b", "run-c", "run-d"} {
+		flow := "review"
+		if runID == "run-d" {
+			flow = "ticket-to-pr"
+		}
+		if err := store.StartRun(runID, RunMetadata{FlowID: flow}); err != nil {
+			t.Fatalf("StartRun() error = %v", err)
+		}
+		if err := store.RecordTurn(runID, Turn{Role: "assistant", TokensOut: 100, Cost: costs[runID]}); err != nil {
+			t.Fatalf("RecordTurn() error = %v", err)
+		}
+		if err := store.EndRun(runID, RunStatusCompleted); err != nil {
+			t.Fatalf("EndRun() error = %v", err)
+		}
+	}
+
+	// Page through review runs costing at least 0.20, most expensive first
+	filter := QueryFilter{
+		ListFilter: ListFilter{FlowID: "review", SortBy: SortByCost, Limit: 1},
+		MinCost:    0.20,
+	}
+	var got []string
+	for {
+		page, err := store.QueryPage(filter)
+		if err != nil {
+			t.Fatalf("QueryPage() error = %v", err)
+		}
+		for _, m := range page.Runs {
+			got = append(got, m.RunID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+	if len(got) != 2 || got[0] != "run-c" || got[1] != "run-b" {
+		t.Errorf("pages = %v, want [run-c run-b]", got)
+	}
+
+	runs, err := store.Query(QueryFilter{MinTokens: 100, MaxCost: 0.60})
+	if err != nil || len(runs) != 2 {
+		t.Errorf("Query(tokens, max cost) = %d runs, %v; want 2", len(runs), err)
+	}
+}
+
+func TestSQLiteStore_TotalCost(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	if err := store.StartRun("run-1", RunMetadata{}); err != nil {
+		t.Fatalf("StartRun() error = %v", err)
+	}
+	if err := store.RecordTurn("run-1", Turn{Role: "assistant", Cost: 0.25}); err != nil {
+		t.Fatalf("RecordTurn() error = %v", err)
+	}
+	if err := store.AddCost("run-1", 0.5); err != nil {
+		t.Fatalf("AddCost() error = %v", err)
+	}
+
+	// Turn cost counted once, plus the unattributed amount
+	meta, err := store.LoadMetadata("run-1")
+	if err != nil || meta.TotalCost != 0.75 {
+		t.Errorf("TotalCost = %v (%v), want 0.75", meta.TotalCost, err)
+	}
+}
@@ -0,0 +1,208 @@
+package transcript
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+)
+
+// GroupKey selects how GroupBy partitions runs
+type GroupKey string
+
+const (
+	GroupByFlow  GroupKey = "flow"  // Meta.FlowID
+	GroupByModel GroupKey = "model" // Model with the most tokens in the run
+	GroupByWeek  GroupKey = "week"  // ISO week of StartedAt, e.g. "2024-W05"
+)
+
+// GroupStats summarizes the runs in one group
+type GroupStats struct {
+	Group string `json:"group"`
+
+	Runs      int `json:"runs"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+	Canceled  int `json:"canceled"`
+
+	// SuccessRate is Completed over finished runs (0 if none finished)
+	SuccessRate float64 `json:"successRate"`
+
+	TotalCost      float64 `json:"totalCost"`
+	TotalTokensIn  int     `json:"totalTokensIn"`
+	TotalTokensOut int     `json:"totalTokensOut"`
+
+	Cost     Percentiles         `json:"cost"`
+	Tokens   Percentiles         `json:"tokens"` // In + out per run
+	Duration DurationPercentiles `json:"duration"`
+}
+
+// Percentiles summarizes a distribution of per-run values
+type Percentiles struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// DurationPercentiles summarizes run durations. Only finished runs count.
+type DurationPercentiles struct {
+	Min  time.Duration `json:"min"`
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+}
+
+// GroupBy partitions matching runs by key and returns per-group cost, token,
+// and duration percentiles plus success rate, sorted by group.
+//
+// Grouping by model loads each transcript and assigns the run to the model
+// that used the most tokens; runs without model data are grouped under
+// "unknown".
+func (s *Searcher) GroupBy(key GroupKey, filter ListFilter) ([]GroupStats, error) {
+	store, err := NewFileStore(StoreConfig{BaseDir: s.baseDir})
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := store.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]Meta)
+	for _, run := range runs {
+		var group string
+		switch key {
+		case GroupByFlow:
+			group = run.FlowID
+		case GroupByWeek:
+			year, week := run.StartedAt.ISOWeek()
+			group = fmt.Sprintf("%d-W%02d", year, week)
+		case GroupByModel:
+			group = s.dominantModel(store, run.RunID)
+		default:
+			return nil, fmt.Errorf("unknown group key %q", key)
+		}
+		groups[group] = append(groups[group], run)
+	}
+
+	stats := make([]GroupStats, 0, len(groups))
+	for group, metas := range groups {
+		stats = append(stats, groupStats(group, metas))
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Group < stats[j].Group })
+
+	return stats, nil
+}
+
+// dominantModel returns the model with the most tokens in a run
+func (s *Searcher) dominantModel(store *FileStore, runID string) string {
+	t, err := store.Load(runID)
+	if err != nil {
+		slog.Debug("skipping unreadable transcript",
+			slog.String("run_id", runID),
+			slog.String("error", err.Error()))
+		return "unknown"
+	}
+
+	tokens := make(map[string]int)
+	for _, turn := range t.Turns {
+		if turn.Model != "" {
+			tokens[turn.Model] += turn.TokensIn + turn.TokensOut
+		}
+	}
+
+	best, bestTokens := "unknown", -1
+	for name, n := range tokens {
+		if n > bestTokens || (n == bestTokens && name < best) {
+			best, bestTokens = name, n
+		}
+	}
+	return best
+}
+
+func groupStats(group string, runs []Meta) GroupStats {
+	gs := GroupStats{Group: group, Runs: len(runs)}
+
+	var costs, tokens []float64
+	var durations []time.Duration
+
+	for _, run := range runs {
+		gs.TotalCost += run.TotalCost
+		gs.TotalTokensIn += run.TotalTokensIn
+		gs.TotalTokensOut += run.TotalTokensOut
+		costs = append(costs, run.TotalCost)
+		tokens = append(tokens, float64(run.TotalTokensIn+run.TotalTokensOut))
+
+		switch run.Status {
+		case RunStatusCompleted:
+			gs.Completed++
+		case RunStatusFailed:
+			gs.Failed++
+		case RunStatusCanceled:
+			gs.Canceled++
+		}
+
+		if run.Status != RunStatusRunning && !run.EndedAt.IsZero() {
+			durations = append(durations, run.EndedAt.Sub(run.StartedAt))
+		}
+	}
+
+	if finished := gs.Completed + gs.Failed + gs.Canceled; finished > 0 {
+		gs.SuccessRate = float64(gs.Completed) / float64(finished)
+	}
+
+	gs.Cost = percentiles(costs)
+	gs.Tokens = percentiles(tokens)
+
+	nanos := make([]float64, len(durations))
+	for i, d := range durations {
+		nanos[i] = float64(d)
+	}
+	p := percentiles(nanos)
+	gs.Duration = DurationPercentiles{
+		Min:  time.Duration(p.Min),
+		Mean: time.Duration(p.Mean),
+		P50:  time.Duration(p.P50),
+		P90:  time.Duration(p.P90),
+		P99:  time.Duration(p.P99),
+		Max:  time.Duration(p.Max),
+	}
+
+	return gs
+}
+
+// percentiles computes summary statistics using nearest-rank percentiles
+func percentiles(values []float64) Percentiles {
+	if len(values) == 0 {
+		return Percentiles{}
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		return sorted[max(i, 0)]
+	}
+
+	return Percentiles{
+		Min:  sorted[0],
+		Mean: sum / float64(len(sorted)),
+		P50:  rank(50),
+		P90:  rank(90),
+		P99:  rank(99),
+		Max:  sorted[len(sorted)-1],
+	}
+}
@@ -1,25 +1,48 @@
 package transcript
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
-	"sort"
 	"sync"
 	"time"
+
+	"github.com/randalmurphal/devflow/artifact"
 )
 
-// FileStore stores transcripts as files
+// FileStore stores transcripts as files.
+//
+// Changes to active runs are appended to a per-run turns.jsonl log, with a
+// transcript.json snapshot written every CompactEvery records. Runs that
+// were interrupted before EndRun are recovered from the snapshot and log
+// on Load.
 type FileStore struct {
-	baseDir string
-	mu      sync.RWMutex
-	active  map[string]*activeRun
+	baseDir      string
+	compactEvery int
+	redactor     *Redactor
+	index        *Index
+	pricing      *PricingRegistry
+	compression  Compression
+	threshold    int
+
+	maxToolOutput     int
+	toolOutputPreview int
+	artifacts         ArtifactSaver
+
+	mu     sync.RWMutex
+	active map[string]*activeRun
 }
 
 type activeRun struct {
 	transcript *Transcript
+	logged     int // records appended since the last snapshot
+	redactions *RedactionReport
+	followers  map[*follower]struct{}
 }
 
 // NewFileStore creates a file-based transcript store
@@ -29,15 +52,84 @@ func NewFileStore(config StoreConfig) (*FileStore, error) {
 		return nil, err
 	}
 
+	compactEvery := config.CompactEvery
+	if compactEvery <= 0 {
+		compactEvery = DefaultCompactEvery
+	}
+
+	if _, err := config.Compression.fileFor(); err != nil {
+		return nil, err
+	}
+	threshold := config.CompressionThreshold
+	if threshold == 0 {
+		threshold = DefaultCompressionThreshold
+	}
+
+	preview := config.ToolOutputPreview
+	if preview <= 0 {
+		preview = DefaultToolOutputPreview
+	}
+	if config.MaxToolOutput > 0 && preview > config.MaxToolOutput {
+		preview = config.MaxToolOutput
+	}
+	artifacts := config.Artifacts
+	if artifacts == nil {
+		artifacts = artifact.NewManager(artifact.Config{BaseDir: config.BaseDir})
+	}
+
 	return &FileStore{
-		baseDir: config.BaseDir,
-		active:  make(map[string]*activeRun),
+		baseDir:      config.BaseDir,
+		compactEvery: compactEvery,
+		redactor:     config.Redactor,
+		index:        config.Index,
+		pricing:      config.Pricing,
+		compression:  config.Compression,
+		threshold:    threshold,
+
+		maxToolOutput:     config.MaxToolOutput,
+		toolOutputPreview: preview,
+		artifacts:         artifacts,
+
+		active: make(map[string]*activeRun),
 	}, nil
 }
 
 // StoreConfig holds configuration for transcript storage
 type StoreConfig struct {
 	BaseDir string
+
+	// CompactEvery is the number of turn log records between transcript
+	// snapshots for active runs (default: DefaultCompactEvery).
+	CompactEvery int
+
+	// Redactor, if set, scrubs secrets from turns and tool calls before
+	// they are written.
+	Redactor *Redactor
+
+	// Index, if set, is updated on every RecordTurn and saved when runs end.
+	Index *Index
+
+	// Pricing, if set, prices turns that have a Model but no Cost.
+	Pricing *PricingRegistry
+
+	// Compression used for transcripts larger than CompressionThreshold
+	// (default: CompressionGzip). Load reads every variant regardless.
+	Compression Compression
+
+	// CompressionThreshold is the transcript size in bytes above which
+	// Compression applies (default: DefaultCompressionThreshold; negative
+	// compresses every transcript).
+	CompressionThreshold int
+
+	// MaxToolOutput is the largest tool call output in bytes kept inline
+	// (0: unlimited). Larger outputs are saved to Artifacts and replaced by
+	// a ToolOutputPreview-byte preview and an OutputRef.
+	MaxToolOutput     int
+	ToolOutputPreview int // default: DefaultToolOutputPreview
+
+	// Artifacts receives overflowed tool output (default: an
+	// artifact.Manager on BaseDir, i.e. runs/<id>/artifacts/tool-output/).
+	Artifacts ArtifactSaver
 }
 
 // StartRun begins a new transcript
@@ -79,6 +171,7 @@ func (s *FileStore) StartRun(runID string, meta RunMetadata) error {
 
 	s.active[runID] = &activeRun{
 		transcript: transcript,
+		redactions: &RedactionReport{RunID: runID, ByRule: make(map[string]int)},
 	}
 
 	return nil
@@ -99,6 +192,23 @@ func (s *FileStore) RecordTurn(runID string, turn Turn) error {
 		turn.Timestamp = time.Now()
 	}
 
+	if s.pricing != nil {
+		s.pricing.PriceTurn(&turn)
+	}
+
+	// Copy tool calls so redaction and overflow don't modify the caller's slice
+	turn.ToolCalls = append([]ToolCall(nil), turn.ToolCalls...)
+	if s.redactor != nil {
+		active.redactions.add(s.redactor.RedactTurn(&turn))
+	}
+	for i := range turn.ToolCalls {
+		s.spillToolOutput(runID, turn.ID, i, &turn.ToolCalls[i])
+	}
+
+	if err := appendLog(s.runDir(runID), logRecord{Turn: &turn}); err != nil {
+		return err
+	}
+
 	active.transcript.Turns = append(active.transcript.Turns, turn)
 
 	// Update token counts
@@ -108,10 +218,16 @@ func (s *FileStore) RecordTurn(runID string, turn Turn) error {
 	case "assistant":
 		active.transcript.Metadata.TotalTokensOut += turn.TokensOut
 	}
+	active.transcript.Metadata.TotalCost += turn.Cost
 
 	active.transcript.Metadata.TurnCount = len(active.transcript.Turns)
 
-	return nil
+	if s.index != nil {
+		s.index.Add(runID, active.transcript.Metadata.FlowID, turn)
+	}
+	active.notifyFollowers(turn)
+
+	return s.logged(runID, active)
 }
 
 // RecordToolCall adds a tool call to the last turn of an active transcript
@@ -128,13 +244,26 @@ func (s *FileStore) RecordToolCall(runID string, tc ToolCall) error {
 		return fmt.Errorf("no turns to add tool call to")
 	}
 
+	if s.redactor != nil {
+		active.redactions.add(s.redactor.RedactToolCall(&tc))
+	}
+
 	last := &active.transcript.Turns[len(active.transcript.Turns)-1]
+	s.spillToolOutput(runID, last.ID, len(last.ToolCalls), &tc)
+
+	rec := logRecord{ToolCall: &tc, TurnID: last.ID, Index: len(last.ToolCalls)}
+	if err := appendLog(s.runDir(runID), rec); err != nil {
+		return err
+	}
 	last.ToolCalls = append(last.ToolCalls, tc)
 
-	return nil
+	return s.logged(runID, active)
 }
 
-// AddCost adds cost to an active transcript
+// AddCost adds cost that is not attributed to any turn (embeddings, tool
+// usage, ...) to an active transcript. RecordTurn already adds Turn.Cost,
+// including cost set by Pricing, to TotalCost, so usage reported on a turn
+// must not also be passed to AddCost.
 func (s *FileStore) AddCost(runID string, cost float64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -144,7 +273,58 @@ func (s *FileStore) AddCost(runID string, cost float64) error {
 		return ErrRunNotStarted
 	}
 
-	active.transcript.Metadata.TotalCost += cost
+	total := active.transcript.Metadata.TotalCost + cost
+	if err := appendLog(s.runDir(runID), logRecord{TotalCost: &total}); err != nil {
+		return err
+	}
+	active.transcript.Metadata.TotalCost = total
+
+	return s.logged(runID, active)
+}
+
+// SetUsageByTask records an active run's LLM usage per task type in its
+// metadata, replacing any earlier value. Call it before EndRun, typically
+// through task.UsageRecorder.EndRun. The usage is informational: TotalCost
+// already includes the same spend through turn costs.
+func (s *FileStore) SetUsageByTask(runID string, usage map[string]TaskUsage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	active, ok := s.active[runID]
+	if !ok {
+		return ErrRunNotStarted
+	}
+
+	usage = maps.Clone(usage)
+	if err := appendLog(s.runDir(runID), logRecord{UsageByTask: usage}); err != nil {
+		return err
+	}
+	active.transcript.Metadata.UsageByTask = usage
+
+	return s.logged(runID, active)
+}
+
+// logged counts an appended log record and snapshots the run when due
+func (s *FileStore) logged(runID string, active *activeRun) error {
+	active.logged++
+	if active.logged < s.compactEvery {
+		return nil
+	}
+	return s.compact(runID, active)
+}
+
+// compact writes a transcript snapshot and truncates the turn log
+func (s *FileStore) compact(runID string, active *activeRun) error {
+	if err := active.transcript.SaveWith(s.baseDir, s.compression, s.threshold); err != nil {
+		return err
+	}
+	if err := s.writeMetadata(runID, &active.transcript.Metadata); err != nil {
+		return err
+	}
+	if err := truncateLog(s.runDir(runID)); err != nil {
+		return err
+	}
+	active.logged = 0
 	return nil
 }
 
@@ -162,7 +342,7 @@ func (s *FileStore) EndRun(runID string, status RunStatus) error {
 	active.transcript.Metadata.EndedAt = time.Now()
 
 	// Save full transcript
-	if err := active.transcript.Save(s.baseDir); err != nil {
+	if err := active.transcript.SaveWith(s.baseDir, s.compression, s.threshold); err != nil {
 		return err
 	}
 
@@ -171,6 +351,22 @@ func (s *FileStore) EndRun(runID string, status RunStatus) error {
 		return err
 	}
 
+	// The saved transcript now holds everything in the log
+	if err := removeLog(s.runDir(runID)); err != nil {
+		return err
+	}
+
+	if err := active.redactions.save(s.runDir(runID)); err != nil {
+		return err
+	}
+
+	if s.index != nil {
+		if err := s.index.Save(); err != nil {
+			return err
+		}
+	}
+
+	active.finishFollowers()
 	delete(s.active, runID)
 	return nil
 }
@@ -192,7 +388,7 @@ func (s *FileStore) EndRunWithError(runID string, err error) error {
 	}
 
 	// Save full transcript
-	if saveErr := active.transcript.Save(s.baseDir); saveErr != nil {
+	if saveErr := active.transcript.SaveWith(s.baseDir, s.compression, s.threshold); saveErr != nil {
 		return saveErr
 	}
 
@@ -201,6 +397,22 @@ func (s *FileStore) EndRunWithError(runID string, err error) error {
 		return writeErr
 	}
 
+	// The saved transcript now holds everything in the log
+	if rmErr := removeLog(s.runDir(runID)); rmErr != nil {
+		return rmErr
+	}
+
+	if saveErr := active.redactions.save(s.runDir(runID)); saveErr != nil {
+		return saveErr
+	}
+
+	if s.index != nil {
+		if idxErr := s.index.Save(); idxErr != nil {
+			return idxErr
+		}
+	}
+
+	active.finishFollowers()
 	delete(s.active, runID)
 	return nil
 }
@@ -224,7 +436,49 @@ func (s *FileStore) Load(runID string) (*Transcript, error) {
 	}
 	s.mu.RUnlock()
 
-	return Load(s.baseDir, runID)
+	return s.loadWithRecovery(runID)
+}
+
+// loadWithRecovery loads a saved transcript and replays any turn log left
+// behind by a run that did not reach EndRun.
+func (s *FileStore) loadWithRecovery(runID string) (*Transcript, error) {
+	t, err := Load(s.baseDir, runID)
+	if err != nil && !errors.Is(err, ErrRunNotFound) {
+		return nil, err
+	}
+
+	if t == nil {
+		// No snapshot yet; start from the metadata written by StartRun
+		meta, metaErr := s.LoadMetadata(runID)
+		if metaErr != nil {
+			return nil, err
+		}
+		t = &Transcript{RunID: runID, Metadata: *meta, Turns: make([]Turn, 0)}
+	}
+
+	found, replayErr := replayLog(s.runDir(runID), t)
+	if replayErr != nil {
+		return nil, replayErr
+	}
+	if !found && err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// HealthCheck verifies the runs directory is writable
+func (s *FileStore) HealthCheck(_ context.Context) error {
+	f, err := os.CreateTemp(filepath.Join(s.baseDir, "runs"), ".health-*")
+	if err != nil {
+		return fmt.Errorf("transcript store not writable: %w", err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+func (s *FileStore) runDir(runID string) string {
+	return filepath.Join(s.baseDir, "runs", runID)
 }
 
 // LoadMetadata retrieves just the metadata
@@ -257,6 +511,16 @@ func (s *FileStore) LoadMetadata(runID string) (*Meta, error) {
 
 // List returns metadata for runs matching filter
 func (s *FileStore) List(filter ListFilter) ([]Meta, error) {
+	page, err := s.ListPage(filter)
+	if err != nil {
+		return nil, err
+	}
+	return page.Runs, nil
+}
+
+// ListPage returns one page of runs matching filter in the filter's sort
+// order. Pass page.NextCursor as filter.Cursor to fetch the next page.
+func (s *FileStore) ListPage(filter ListFilter) (*ListPage, error) {
 	runsDir := filepath.Join(s.baseDir, "runs")
 	entries, err := os.ReadDir(runsDir)
 	if err != nil {
@@ -281,34 +545,12 @@ func (s *FileStore) List(filter ListFilter) ([]Meta, error) {
 			continue
 		}
 
-		// Apply filters
-		if filter.FlowID != "" && meta.FlowID != filter.FlowID {
-			continue
-		}
-		if filter.Status != "" && meta.Status != filter.Status {
-			continue
-		}
-		if !filter.After.IsZero() && meta.StartedAt.Before(filter.After) {
-			continue
-		}
-		if !filter.Before.IsZero() && meta.StartedAt.After(filter.Before) {
-			continue
+		if filter.Matches(*meta) {
+			results = append(results, *meta)
 		}
-
-		results = append(results, *meta)
 	}
 
-	// Sort by start time (newest first)
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].StartedAt.After(results[j].StartedAt)
-	})
-
-	// Apply limit
-	if filter.Limit > 0 && len(results) > filter.Limit {
-		results = results[:filter.Limit]
-	}
-
-	return results, nil
+	return filter.page(results)
 }
 
 // Delete removes a run
@@ -317,6 +559,9 @@ func (s *FileStore) Delete(runID string) error {
 	defer s.mu.Unlock()
 
 	// Remove from active if present
+	if active, ok := s.active[runID]; ok {
+		active.finishFollowers()
+	}
 	delete(s.active, runID)
 
 	runDir := filepath.Join(s.baseDir, "runs", runID)
@@ -324,9 +569,32 @@ func (s *FileStore) Delete(runID string) error {
 		return err
 	}
 
+	if s.index != nil {
+		s.index.RemoveRun(runID)
+		return s.index.Save()
+	}
+
 	return nil
 }
 
+// RedactionReport returns the redactions made for a run.
+// Runs without redactions return an empty report.
+func (s *FileStore) RedactionReport(runID string) (*RedactionReport, error) {
+	s.mu.RLock()
+	if active, ok := s.active[runID]; ok {
+		report := &RedactionReport{RunID: runID, ByRule: make(map[string]int)}
+		report.add(active.redactions.ByRule)
+		s.mu.RUnlock()
+		return report, nil
+	}
+	s.mu.RUnlock()
+
+	if _, err := os.Stat(s.runDir(runID)); os.IsNotExist(err) {
+		return nil, ErrRunNotFound
+	}
+	return loadRedactionReport(s.runDir(runID), runID)
+}
+
 // GetActive returns an active transcript (for monitoring)
 func (s *FileStore) GetActive(runID string) (*Transcript, bool) {
 	s.mu.RLock()
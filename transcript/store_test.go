@@ -0,0 +1,95 @@
+package transcript
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFileStore_TotalCost(t *testing.T) {
+	dir := t.TempDir()
+	pricing := NewPricingRegistry()
+	pricing.Set("priced-model", ModelPrice{InputPer1K: 1, OutputPer1K: 2})
+
+	store, err := NewFileStore(StoreConfig{BaseDir: dir, Pricing: pricing})
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	if err := store.StartRun("run-1", RunMetadata{}); err != nil {
+		t.Fatalf("StartRun() error = %v", err)
+	}
+
+	// 0.25 set by the caller, 2.0 from pricing, 0.5 not attributed to a turn
+	turns := []Turn{
+		{Role: "assistant", Model: "manual", Cost: 0.25},
+		{Role: "assistant", Model: "priced-model", TokensIn: 1000, TokensOut: 500},
+	}
+	for _, turn := range turns {
+		if err := store.RecordTurn("run-1", turn); err != nil {
+			t.Fatalf("RecordTurn() error = %v", err)
+		}
+	}
+	if err := store.AddCost("run-1", 0.5); err != nil {
+		t.Fatalf("AddCost() error = %v", err)
+	}
+
+	const want = 0.25 + 2.0 + 0.5
+	active, _ := store.GetActive("run-1")
+	if got := active.Metadata.TotalCost; math.Abs(got-want) > 1e-9 {
+		t.Errorf("active TotalCost = %v, want %v", got, want)
+	}
+
+	// Replaying the turn log after a crash gives the same total
+	recovered, err := (&FileStore{baseDir: dir}).loadWithRecovery("run-1")
+	if err != nil {
+		t.Fatalf("loadWithRecovery() error = %v", err)
+	}
+	if got := recovered.Metadata.TotalCost; math.Abs(got-want) > 1e-9 {
+		t.Errorf("recovered TotalCost = %v, want %v", got, want)
+	}
+
+	if err := store.EndRun("run-1", RunStatusCompleted); err != nil {
+		t.Fatalf("EndRun() error = %v", err)
+	}
+	meta, err := store.LoadMetadata("run-1")
+	if err != nil || math.Abs(meta.TotalCost-want) > 1e-9 {
+		t.Errorf("saved TotalCost = %v (%v), want %v", meta.TotalCost, err, want)
+	}
+
+	// Per-model attribution covers exactly the turn costs
+	byModel, err := NewSearcher(dir).CostByModel(ListFilter{})
+	if err != nil {
+		t.Fatalf("CostByModel() error = %v", err)
+	}
+	if byModel["manual"].Cost != 0.25 || byModel["priced-model"].Cost != 2.0 {
+		t.Errorf("CostByModel() = manual %v, priced-model %v", byModel["manual"].Cost, byModel["priced-model"].Cost)
+	}
+}
+
+func TestFileStore_SetUsageByTask(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(StoreConfig{BaseDir: dir})
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	if err := store.SetUsageByTask("run-1", nil); !errors.Is(err, ErrRunNotStarted) {
+		t.Errorf("SetUsageByTask(not started) error = %v, want ErrRunNotStarted", err)
+	}
+	if err := store.StartRun("run-1", RunMetadata{}); err != nil {
+		t.Fatalf("StartRun() error = %v", err)
+	}
+
+	usage := map[string]TaskUsage{"review": {Calls: 2, TokensIn: 900, TokensOut: 80, Cost: 0.3}}
+	if err := store.SetUsageByTask("run-1", usage); err != nil {
+		t.Fatalf("SetUsageByTask() error = %v", err)
+	}
+
+	// The turn log carries it across a crash
+	recovered, err := (&FileStore{baseDir: dir}).loadWithRecovery("run-1")
+	if err != nil {
+		t.Fatalf("loadWithRecovery() error = %v", err)
+	}
+	if got := recovered.Metadata.UsageByTask["review"]; got != usage["review"] {
+		t.Errorf("recovered UsageByTask = %+v", recovered.Metadata.UsageByTask)
+	}
+}
@@ -1,10 +1,8 @@
 package transcript
 
 import (
-	"compress/gzip"
 	"encoding/json"
 	"errors"
-	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -49,6 +47,18 @@ type Meta struct {
 	TotalCost      float64        `json:"totalCost"`
 	TurnCount      int            `json:"turnCount"`
 	Error          string         `json:"error,omitempty"`
+
+	// UsageByTask is the run's LLM usage per task type ("review",
+	// "implement", ...), set with FileStore.SetUsageByTask
+	UsageByTask map[string]TaskUsage `json:"usageByTask,omitempty"`
+}
+
+// TaskUsage is the LLM usage of one task type within a run
+type TaskUsage struct {
+	Calls     int     `json:"calls"`
+	TokensIn  int     `json:"tokensIn"`
+	TokensOut int     `json:"tokensOut"`
+	Cost      float64 `json:"cost"`
 }
 
 // Turn represents a conversation turn
@@ -61,6 +71,8 @@ type Turn struct {
 	Timestamp  time.Time  `json:"timestamp"`
 	ToolCalls  []ToolCall `json:"toolCalls,omitempty"`
 	DurationMs int64      `json:"durationMs,omitempty"`
+	Model      string     `json:"model,omitempty"` // Model that produced or received the turn
+	Cost       float64    `json:"cost,omitempty"`  // Cost in USD attributed to this turn
 }
 
 // ToolCall represents a tool/function call
@@ -70,6 +82,10 @@ type ToolCall struct {
 	Input  map[string]any `json:"input"`
 	Output string         `json:"output,omitempty"`
 	Error  string         `json:"error,omitempty"`
+
+	// OutputRef is set when Output was truncated to a preview and the full
+	// output stored as an artifact (see StoreConfig.MaxToolOutput)
+	OutputRef *OutputRef `json:"outputRef,omitempty"`
 }
 
 // RunMetadata is input for starting a new run
@@ -130,6 +146,7 @@ func (t *Transcript) AddTurnWithDetails(turn Turn) *Turn {
 	case "assistant":
 		t.Metadata.TotalTokensOut += turn.TokensOut
 	}
+	t.Metadata.TotalCost += turn.Cost
 
 	t.Turns = append(t.Turns, turn)
 	t.Metadata.TurnCount = len(t.Turns)
@@ -177,7 +194,8 @@ func (t *Transcript) SetCost(cost float64) {
 	t.Metadata.TotalCost = cost
 }
 
-// AddCost adds to the total cost
+// AddCost adds cost not attributed to any turn to the total.
+// AddTurnWithDetails already counts Turn.Cost.
 func (t *Transcript) AddCost(cost float64) {
 	t.Metadata.TotalCost += cost
 }
@@ -235,11 +253,16 @@ func (t *Transcript) TurnsByRole(role string) []Turn {
 	return result
 }
 
-// compressionThreshold is the size above which transcripts are compressed
-const compressionThreshold = 100 * 1024 // 100KB
-
-// Save writes the transcript to disk
+// Save writes the transcript to disk, gzip-compressed above
+// DefaultCompressionThreshold
 func (t *Transcript) Save(baseDir string) error {
+	return t.SaveWith(baseDir, CompressionGzip, DefaultCompressionThreshold)
+}
+
+// SaveWith writes the transcript to disk, compressed with compression when
+// the encoded JSON is larger than threshold bytes (a negative threshold
+// always compresses). Other variants of the transcript file are removed.
+func (t *Transcript) SaveWith(baseDir string, compression Compression, threshold int) error {
 	runDir := filepath.Join(baseDir, "runs", t.RunID)
 	if err := os.MkdirAll(runDir, 0755); err != nil {
 		return err
@@ -251,48 +274,53 @@ func (t *Transcript) Save(baseDir string) error {
 	}
 
 	// Compress if large
-	if len(data) > compressionThreshold {
-		return t.saveCompressed(runDir, data)
+	if len(data) <= threshold {
+		compression = CompressionNone
 	}
-
-	// Remove compressed version if it exists
-	os.Remove(filepath.Join(runDir, "transcript.json.gz"))
-
-	return os.WriteFile(filepath.Join(runDir, "transcript.json"), data, 0644)
-}
-
-func (t *Transcript) saveCompressed(runDir string, data []byte) error {
-	// Remove uncompressed version if it exists
-	os.Remove(filepath.Join(runDir, "transcript.json"))
-
-	f, err := os.Create(filepath.Join(runDir, "transcript.json.gz"))
+	name, err := compression.fileFor()
+	if err != nil {
+		return err
+	}
+	data, err = compression.compress(data)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
 
-	gz := gzip.NewWriter(f)
-	defer gz.Close()
+	// Write to a temp file and rename so a crash never leaves a partial transcript
+	tmp := filepath.Join(runDir, name+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(runDir, name)); err != nil {
+		return err
+	}
 
-	_, err = gz.Write(data)
-	return err
+	// Remove other variants so Load never sees a stale one
+	for _, variant := range transcriptFiles {
+		if variant.name != name {
+			os.Remove(filepath.Join(runDir, variant.name))
+		}
+	}
+	return nil
 }
 
-// Load loads a transcript from disk
+// Load loads a transcript from disk, whichever compression it was saved with
 func Load(baseDir, runID string) (*Transcript, error) {
 	runDir := filepath.Join(baseDir, "runs", runID)
 
-	// Try compressed first
-	data, err := loadCompressed(filepath.Join(runDir, "transcript.json.gz"))
+	var data []byte
+	var err error
+	for _, variant := range transcriptFiles {
+		data, err = readTranscriptFile(filepath.Join(runDir, variant.name), variant.compression)
+		if !os.IsNotExist(err) {
+			break
+		}
+	}
 	if err != nil {
-		// Try uncompressed
-		data, err = os.ReadFile(filepath.Join(runDir, "transcript.json"))
-		if err != nil {
-			if os.IsNotExist(err) {
-				return nil, ErrRunNotFound
-			}
-			return nil, err
+		if os.IsNotExist(err) {
+			return nil, ErrRunNotFound
 		}
+		return nil, err
 	}
 
 	var t Transcript
@@ -302,19 +330,3 @@ func Load(baseDir, runID string) (*Transcript, error) {
 
 	return &t, nil
 }
-
-func loadCompressed(path string) ([]byte, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	gz, err := gzip.NewReader(f)
-	if err != nil {
-		return nil, err
-	}
-	defer gz.Close()
-
-	return io.ReadAll(gz)
-}
@@ -128,6 +128,9 @@ func (v *Viewer) writeTurn(w io.Writer, turn Turn) {
 			}
 			fmt.Fprintf(w, "     Output: %s\n", output)
 		}
+		if tc.OutputRef != nil {
+			fmt.Fprintf(w, "     Full output: artifact %s (%d bytes)\n", tc.OutputRef.Artifact, tc.OutputRef.Size)
+		}
 		if tc.Error != "" {
 			fmt.Fprintf(w, "     Error: %s\n", tc.Error)
 		}
@@ -181,6 +184,9 @@ func (v *Viewer) ExportMarkdown(w io.Writer, t *Transcript) error {
 			if tc.Output != "" {
 				fmt.Fprintf(w, "**Output:**\n```\n%s\n```\n\n", tc.Output)
 			}
+			if tc.OutputRef != nil {
+				fmt.Fprintf(w, "**Full output:** artifact `%s` (%d bytes)\n\n", tc.OutputRef.Artifact, tc.OutputRef.Size)
+			}
 			if tc.Error != "" {
 				fmt.Fprintf(w, "**Error:** %s\n\n", tc.Error)
 			}
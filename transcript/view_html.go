@@ -0,0 +1,354 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ExportHTML exports a self-contained HTML page: a metadata header followed
+// by collapsible turns with highlighted code blocks and tool-call panels.
+// The page has no external assets, so it can be shared as a single file.
+func (v *Viewer) ExportHTML(w io.Writer, t *Transcript) error {
+	page := htmlPage{
+		Title:    "Transcript: " + t.RunID,
+		RunID:    t.RunID,
+		Metadata: htmlMetadata(t),
+		Error:    t.Metadata.Error,
+	}
+
+	for _, turn := range t.Turns {
+		page.Turns = append(page.Turns, htmlTurnFor(turn))
+	}
+
+	return htmlTemplate.Execute(w, page)
+}
+
+type htmlPage struct {
+	Title    string
+	RunID    string
+	Metadata []htmlField
+	Error    string
+	Turns    []htmlTurn
+}
+
+type htmlField struct {
+	Name  string
+	Value string
+}
+
+type htmlTurn struct {
+	ID        int
+	Role      string
+	Summary   string
+	Content   template.HTML
+	ToolCalls []htmlToolCall
+	Open      bool
+}
+
+type htmlToolCall struct {
+	Name   string
+	Input  template.HTML
+	Output string
+	Error  string
+	Ref    *OutputRef
+}
+
+func htmlMetadata(t *Transcript) []htmlField {
+	fields := []htmlField{
+		{"Flow", t.Metadata.FlowID},
+		{"Status", string(t.Metadata.Status)},
+		{"Started", t.Metadata.StartedAt.Format(time.RFC3339)},
+	}
+	if !t.Metadata.EndedAt.IsZero() {
+		fields = append(fields, htmlField{"Ended", t.Metadata.EndedAt.Format(time.RFC3339)})
+	}
+	fields = append(fields,
+		htmlField{"Duration", t.Duration().Round(time.Second).String()},
+		htmlField{"Turns", fmt.Sprintf("%d", len(t.Turns))},
+		htmlField{"Tokens", fmt.Sprintf("%d in / %d out", t.Metadata.TotalTokensIn, t.Metadata.TotalTokensOut)},
+		htmlField{"Cost", fmt.Sprintf("$%.4f", t.Metadata.TotalCost)},
+	)
+	if t.Metadata.NodeID != "" {
+		fields = append(fields, htmlField{"Node", t.Metadata.NodeID})
+	}
+	return fields
+}
+
+func htmlTurnFor(turn Turn) htmlTurn {
+	parts := []string{turn.Timestamp.Format("15:04:05")}
+	if turn.Model != "" {
+		parts = append(parts, turn.Model)
+	}
+	if turn.TokensIn > 0 {
+		parts = append(parts, fmt.Sprintf("%d in", turn.TokensIn))
+	}
+	if turn.TokensOut > 0 {
+		parts = append(parts, fmt.Sprintf("%d out", turn.TokensOut))
+	}
+	if turn.Cost > 0 {
+		parts = append(parts, fmt.Sprintf("$%.4f", turn.Cost))
+	}
+	if turn.DurationMs > 0 {
+		parts = append(parts, fmt.Sprintf("%dms", turn.DurationMs))
+	}
+	switch n := len(turn.ToolCalls); {
+	case n == 1:
+		parts = append(parts, "1 tool call")
+	case n > 1:
+		parts = append(parts, fmt.Sprintf("%d tool calls", n))
+	}
+
+	ht := htmlTurn{
+		ID:      turn.ID,
+		Role:    turn.Role,
+		Summary: strings.Join(parts, " · "),
+		Content: renderContent(turn.Content),
+		Open:    turn.Role != "system",
+	}
+
+	for _, tc := range turn.ToolCalls {
+		htc := htmlToolCall{
+			Name:   tc.Name,
+			Output: tc.Output,
+			Error:  tc.Error,
+			Ref:    tc.OutputRef,
+		}
+		if tc.Input != nil {
+			// The page escapes its own content, so keep JSON readable
+			var buf bytes.Buffer
+			enc := json.NewEncoder(&buf)
+			enc.SetEscapeHTML(false)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(tc.Input)
+			htc.Input = highlightCode(strings.TrimRight(buf.String(), "\n"), "json")
+		}
+		ht.ToolCalls = append(ht.ToolCalls, htc)
+	}
+
+	return ht
+}
+
+// renderContent escapes turn content and turns fenced code blocks into
+// highlighted <pre> elements. Everything else is kept as preformatted text.
+func renderContent(content string) template.HTML {
+	var b strings.Builder
+	lines := strings.Split(content, "\n")
+
+	var text, code []string
+	var lang string
+	inCode := false
+
+	flushText := func() {
+		if len(text) == 0 {
+			return
+		}
+		joined := strings.Trim(strings.Join(text, "\n"), "\n")
+		if joined != "" {
+			b.WriteString(`<div class="text">`)
+			b.WriteString(html.EscapeString(joined))
+			b.WriteString("</div>\n")
+		}
+		text = nil
+	}
+
+	flushCode := func() {
+		b.WriteString(`<pre class="code"`)
+		if lang != "" {
+			fmt.Fprintf(&b, ` data-lang="%s"`, html.EscapeString(lang))
+		}
+		b.WriteString("><code>")
+		b.WriteString(string(highlightCode(strings.Join(code, "\n"), lang)))
+		b.WriteString("</code></pre>\n")
+		code = nil
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case !inCode && strings.HasPrefix(trimmed, "```"):
+			flushText()
+			lang = strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			inCode = true
+		case inCode && trimmed == "```":
+			flushCode()
+			inCode = false
+		case inCode:
+			code = append(code, line)
+		default:
+			text = append(text, line)
+		}
+	}
+
+	// An unterminated fence still renders as code
+	if inCode {
+		flushCode()
+	}
+	flushText()
+
+	return template.HTML(b.String()) //nolint:gosec // built from escaped content
+}
+
+// highlightTokens matches comments, strings, numbers, and identifiers in one
+// pass so a keyword inside a string or comment is never highlighted.
+// highlightHashTokens also treats '#' as starting a comment.
+var (
+	highlightTokens     = regexp.MustCompile(highlightPattern(`//.*$|/\*[\s\S]*?\*/`))
+	highlightHashTokens = regexp.MustCompile(highlightPattern(`//.*$|#.*$|/\*[\s\S]*?\*/`))
+)
+
+func highlightPattern(comments string) string {
+	return `(?m)(` + comments + `)` +
+		`|("(?:[^"\\\n]|\\.)*"|'(?:[^'\\\n]|\\.)*'|` + "`[^`]*`" + `)` +
+		`|\b(\d+(?:\.\d+)?)\b` +
+		`|\b([A-Za-z_][A-Za-z0-9_]*)\b`
+}
+
+// highlightKeywords is a language-agnostic keyword set covering the
+// languages that commonly show up in transcripts
+var highlightKeywords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "def": true, "default": true, "defer": true, "else": true,
+	"elif": true, "except": true, "export": true, "extends": true, "false": true,
+	"finally": true, "fn": true, "for": true, "from": true, "func": true,
+	"function": true, "go": true, "if": true, "import": true, "in": true,
+	"interface": true, "let": true, "map": true, "match": true, "mut": true,
+	"new": true, "nil": true, "None": true, "null": true, "package": true,
+	"pub": true, "raise": true, "range": true, "return": true, "select": true,
+	"self": true, "struct": true, "switch": true, "this": true, "throw": true,
+	"true": true, "True": true, "False": true, "try": true, "type": true,
+	"use": true, "var": true, "while": true, "with": true, "yield": true,
+}
+
+// highlightCode escapes code and wraps comments, strings, numbers, and
+// keywords in spans. '#' comments are only recognized for languages that
+// use them, since '#' is meaningful elsewhere (e.g., C preprocessor, CSS).
+func highlightCode(code, lang string) template.HTML {
+	tokens := highlightTokens
+	switch strings.ToLower(lang) {
+	case "python", "py", "sh", "bash", "shell", "zsh", "yaml", "yml", "toml", "ruby", "rb", "perl", "r", "dockerfile", "makefile":
+		tokens = highlightHashTokens
+	}
+
+	var b strings.Builder
+	last := 0
+
+	for _, m := range tokens.FindAllStringSubmatchIndex(code, -1) {
+		start, end := m[0], m[1]
+		token := code[start:end]
+
+		var class string
+		switch {
+		case m[2] >= 0:
+			class = "c"
+		case m[4] >= 0:
+			class = "s"
+		case m[6] >= 0:
+			class = "n"
+		case m[8] >= 0:
+			if !highlightKeywords[token] {
+				continue
+			}
+			class = "k"
+		}
+
+		b.WriteString(html.EscapeString(code[last:start]))
+		fmt.Fprintf(&b, `<span class="%s">%s</span>`, class, html.EscapeString(token))
+		last = end
+	}
+	b.WriteString(html.EscapeString(code[last:]))
+
+	return template.HTML(b.String()) //nolint:gosec // built from escaped content
+}
+
+var htmlTemplate = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0 auto; max-width: 960px; padding: 24px; color: #1f2328; background: #fff; }
+h1 { font-size: 1.4em; margin-bottom: 8px; word-break: break-all; }
+table.meta { border-collapse: collapse; margin-bottom: 16px; }
+table.meta th, table.meta td { border: 1px solid #d0d7de; padding: 4px 10px; text-align: left; font-size: 0.9em; }
+table.meta th { background: #f6f8fa; }
+.error { background: #ffebe9; border: 1px solid #ff8182; padding: 8px 12px; border-radius: 6px; margin-bottom: 16px; white-space: pre-wrap; }
+.controls { margin-bottom: 12px; }
+.controls button { font-size: 0.85em; margin-right: 6px; cursor: pointer; }
+details.turn { border: 1px solid #d0d7de; border-radius: 6px; margin-bottom: 12px; }
+details.turn > summary { cursor: pointer; padding: 8px 12px; background: #f6f8fa; border-radius: 6px; font-size: 0.9em; }
+details.turn[open] > summary { border-bottom: 1px solid #d0d7de; border-radius: 6px 6px 0 0; }
+.role { font-weight: 600; text-transform: uppercase; margin-right: 8px; }
+.role-user { color: #0969da; }
+.role-assistant { color: #1a7f37; }
+.role-system { color: #8250df; }
+.stats { color: #656d76; }
+.body { padding: 8px 12px; }
+.text { white-space: pre-wrap; word-wrap: break-word; margin: 8px 0; }
+pre { background: #f6f8fa; border-radius: 6px; padding: 10px; overflow-x: auto; font-size: 0.85em; position: relative; }
+pre.code[data-lang]::before { content: attr(data-lang); position: absolute; top: 2px; right: 8px; font-size: 0.75em; color: #656d76; }
+details.tool { border: 1px solid #d8dee4; border-left: 3px solid #bf8700; border-radius: 4px; margin: 8px 0; }
+details.tool > summary { cursor: pointer; padding: 6px 10px; font-family: monospace; font-size: 0.85em; }
+details.tool .panel { padding: 0 10px 6px; }
+details.tool h4 { margin: 6px 0 2px; font-size: 0.8em; color: #656d76; text-transform: uppercase; }
+details.tool.failed { border-left-color: #cf222e; }
+.k { color: #cf222e; }
+.s { color: #0a3069; }
+.c { color: #6e7781; font-style: italic; }
+.n { color: #0550ae; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table class="meta">
+{{- range .Metadata}}
+<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- if .Error}}
+<div class="error">{{.Error}}</div>
+{{- end}}
+<div class="controls">
+<button type="button" onclick="document.querySelectorAll('details').forEach(function(d){d.open=true})">Expand all</button>
+<button type="button" onclick="document.querySelectorAll('details').forEach(function(d){d.open=false})">Collapse all</button>
+</div>
+{{- range .Turns}}
+<details class="turn" id="turn-{{.ID}}"{{if .Open}} open{{end}}>
+<summary><span class="role role-{{.Role}}">[{{.ID}}] {{.Role}}</span><span class="stats">{{.Summary}}</span></summary>
+<div class="body">
+{{.Content}}
+{{- range .ToolCalls}}
+<details class="tool{{if .Error}} failed{{end}}">
+<summary>{{.Name}}</summary>
+<div class="panel">
+{{- if .Input}}
+<h4>Input</h4>
+<pre><code>{{.Input}}</code></pre>
+{{- end}}
+{{- if .Output}}
+<h4>Output</h4>
+<pre><code>{{.Output}}</code></pre>
+{{- end}}
+{{- with .Ref}}
+<p class="stats">Full output: artifact <code>{{.Artifact}}</code> ({{.Size}} bytes)</p>
+{{- end}}
+{{- if .Error}}
+<h4>Error</h4>
+<pre><code>{{.Error}}</code></pre>
+{{- end}}
+</div>
+</details>
+{{- end}}
+</div>
+</details>
+{{- end}}
+</body>
+</html>
+`))
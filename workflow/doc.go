@@ -2,12 +2,14 @@
 // for AI-powered development workflows.
 //
 // Core types:
-//   - State: Workflow execution state with git, spec, implementation, and review data
+//   - State: Workflow execution state with git, spec, implementation, and review data,
+//     plus the prompt versions used
 //   - NodeFunc: Function signature for workflow nodes
 //   - NodeConfig: Configuration for node behavior (retries, transcripts, etc.)
-//   - Ticket: External ticket reference (Jira, GitHub issue, etc.)
+//   - Ticket: External ticket reference (alias of ticketing.Ticket)
 //
 // Workflow nodes:
+//   - FetchTicketNode: Loads state.Ticket from the ticketing.Provider in context
 //   - CreateWorktreeNode: Creates git worktree for isolated work
 //   - GenerateSpecNode: Generates feature specification from ticket
 //   - ImplementNode: Implements code based on specification
@@ -17,6 +19,7 @@
 //   - CheckLintNode: Runs linting checks
 //   - CreatePRNode: Creates pull request
 //   - NotifyNode: Sends workflow notifications
+//   - PostMergeNode: Waits for PR merge and runs follow-ups (branch, ticket, worktree, notify)
 //
 // Example usage:
 //
@@ -2,9 +2,11 @@ package workflow
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 
 	devcontext "github.com/randalmurphal/devflow/context"
+	"github.com/randalmurphal/devflow/task"
 	"github.com/randalmurphal/flowgraph/pkg/flowgraph"
 	"github.com/randalmurphal/llmkit/claude"
 )
@@ -24,16 +26,11 @@ func ImplementNode(ctx flowgraph.Context, state State) (State, error) {
 		return state, fmt.Errorf("claude.Client not found in context")
 	}
 
-	// Build prompt
-	prompt := formatImplementPrompt(state.Spec, state.Ticket)
+	// Build prompt with the repository files most relevant to the spec
+	prompt := formatImplementPrompt(state.Spec, state.Ticket, buildImplementContext(ctx, state))
 
 	// Load system prompt if available
-	var systemPrompt string
-	if loader := devcontext.Prompt(ctx); loader != nil {
-		if sp, err := loader.Load("implement"); err == nil {
-			systemPrompt = sp
-		}
-	}
+	systemPrompt := loadSystemPrompt(ctx, &state, "implement")
 
 	// Run LLM
 	// Note: For implementation nodes that need to execute in a specific directory,
@@ -47,6 +44,8 @@ func ImplementNode(ctx flowgraph.Context, state State) (State, error) {
 		return state, err
 	}
 
+	recordUsage(ctx, task.Implement, result)
+
 	state.Implementation = result.Content
 	// Note: Files tracking now happens through git diff, not LLM response
 	state.ImplementTokensIn = result.Usage.InputTokens
@@ -66,8 +65,40 @@ func ImplementNode(ctx flowgraph.Context, state State) (State, error) {
 	return state, nil
 }
 
+// ImplementContextFiles is the maximum number of relevant repository files
+// included in the implementation prompt.
+const ImplementContextFiles = 15
+
+// buildImplementContext selects the worktree files most relevant to the spec
+// and ticket. Failures only cost the prompt its file context.
+func buildImplementContext(ctx flowgraph.Context, state State) string {
+	query := state.Spec
+	if state.Ticket != nil {
+		query = state.Ticket.Title + "\n" + state.Ticket.Description + "\n" + query
+	}
+
+	builder := devcontext.NewContextBuilder(state.Worktree)
+	if cache := devcontext.ContextCache(ctx); cache != nil {
+		builder.WithCache(cache)
+	}
+	if _, err := builder.AddRelevant(query, devcontext.RelevanceConfig{MaxFiles: ImplementContextFiles}); err != nil {
+		slog.Debug("skipping implementation context", "error", err)
+		return ""
+	}
+	if builder.FileCount() == 0 {
+		return ""
+	}
+
+	fileContext, err := builder.Build()
+	if err != nil {
+		slog.Debug("skipping implementation context", "error", err)
+		return ""
+	}
+	return fileContext
+}
+
 // formatImplementPrompt creates the implementation prompt
-func formatImplementPrompt(spec string, ticket *Ticket) string {
+func formatImplementPrompt(spec string, ticket *Ticket, fileContext string) string {
 	var b strings.Builder
 	b.WriteString("Implement the following specification:\n\n")
 	b.WriteString("## Specification\n\n")
@@ -76,6 +107,10 @@ func formatImplementPrompt(spec string, ticket *Ticket) string {
 	if ticket != nil {
 		b.WriteString(fmt.Sprintf("Original ticket: %s - %s\n\n", ticket.ID, ticket.Title))
 	}
+	if fileContext != "" {
+		b.WriteString("## Relevant Files\n\n")
+		b.WriteString(fileContext)
+	}
 	b.WriteString("Please implement this in the codebase. ")
 	b.WriteString("Make sure to:\n")
 	b.WriteString("- Follow existing code patterns\n")
@@ -1,11 +1,14 @@
 package workflow
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	devcontext "github.com/randalmurphal/devflow/context"
+	"github.com/randalmurphal/devflow/prompt"
 	"github.com/randalmurphal/devflow/transcript"
 	"github.com/randalmurphal/flowgraph/pkg/flowgraph"
 )
@@ -20,22 +23,42 @@ type NodeFunc func(ctx flowgraph.Context, state State) (State, error)
 
 // NodeConfig configures node behavior
 type NodeConfig struct {
-	MaxReviewAttempts int    // Max review/fix cycles (default: 3)
-	TestCommand       string // Test command (default: "go test ./...")
-	LintCommand       string // Lint command (default: "go vet ./...")
-	BaseBranch        string // Default base branch (default: "main")
+	MaxReviewAttempts      int    // Max review/fix cycles (default: 3)
+	TestCommand            string // Test command (default: "go test ./...")
+	LintCommand            string // Lint command (default: "go vet ./...")
+	BaseBranch             string // Default base branch (default: "main")
+	ChunkedReviewThreshold int    // Diff bytes above which reviews are per file (default: 32KB; negative disables)
 }
 
 // DefaultNodeConfig returns sensible defaults
 func DefaultNodeConfig() NodeConfig {
 	return NodeConfig{
-		MaxReviewAttempts: 3,
-		TestCommand:       "go test -race ./...",
-		LintCommand:       "go vet ./...",
-		BaseBranch:        "main",
+		MaxReviewAttempts:      3,
+		TestCommand:            "go test -race ./...",
+		LintCommand:            "go vet ./...",
+		BaseBranch:             "main",
+		ChunkedReviewThreshold: DefaultChunkedReviewThreshold,
 	}
 }
 
+// nodeConfigKey is the context key for NodeConfig
+type nodeConfigKey struct{}
+
+// WithNodeConfig adds node settings to the context, for nodes that read
+// them (such as ReviewNode's chunking threshold).
+func WithNodeConfig(ctx context.Context, cfg NodeConfig) context.Context {
+	return context.WithValue(ctx, nodeConfigKey{}, cfg)
+}
+
+// NodeConfigFrom returns the node settings in ctx, or DefaultNodeConfig if
+// none were added.
+func NodeConfigFrom(ctx context.Context) NodeConfig {
+	if cfg, ok := ctx.Value(nodeConfigKey{}).(NodeConfig); ok {
+		return cfg
+	}
+	return DefaultNodeConfig()
+}
+
 // =============================================================================
 // Node Wrappers
 // =============================================================================
@@ -75,6 +98,11 @@ func WithTranscript(node NodeFunc, nodeName string) NodeFunc {
 			if err != nil {
 				turn.Content = fmt.Sprintf("Node %s failed: %v", nodeName, err)
 			}
+			// Prompt versions let transcript analysis correlate behavior
+			// changes with prompt edits
+			for _, p := range newPrompts(state.Prompts, result.Prompts) {
+				turn.Content += fmt.Sprintf("\nPrompt %s", p)
+			}
 			mgr.RecordTurn(state.RunID, turn)
 		}
 
@@ -82,6 +110,17 @@ func WithTranscript(node NodeFunc, nodeName string) NodeFunc {
 	}
 }
 
+// newPrompts returns the prompt records in after that are not in before
+func newPrompts(before, after []prompt.Resolved) []prompt.Resolved {
+	var added []prompt.Resolved
+	for _, p := range after {
+		if !slices.Contains(before, p) {
+			added = append(added, p)
+		}
+	}
+	return added
+}
+
 // WithTiming wraps a node with timing metrics
 func WithTiming(node NodeFunc) NodeFunc {
 	return func(ctx flowgraph.Context, state State) (State, error) {
@@ -27,6 +27,7 @@ func NotifyNode(ctx flowgraph.Context, state State) (State, error) {
 		FlowID:    state.FlowID,
 		Timestamp: time.Now(),
 		Metadata:  buildMetadata(state),
+		State:     state,
 	}
 
 	// Set severity based on state
@@ -69,6 +70,9 @@ func buildMetadata(state State) map[string]any {
 	}
 	if state.PR != nil {
 		meta["prUrl"] = state.PR.URL
+		if state.PR.HeadSHA != "" {
+			meta["headSha"] = state.PR.HeadSHA
+		}
 	}
 	if state.Review != nil {
 		meta["reviewApproved"] = state.Review.Approved
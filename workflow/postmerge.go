@@ -0,0 +1,210 @@
+package workflow
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	devcontext "github.com/randalmurphal/devflow/context"
+	"github.com/randalmurphal/devflow/notify"
+	"github.com/randalmurphal/devflow/pr"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph"
+)
+
+// PostMergeConfig configures PostMergeNode behavior.
+type PostMergeConfig struct {
+	PollInterval     time.Duration // Time between PR state checks (default: 1m)
+	Timeout          time.Duration // Max time to wait for merge (default: 24h)
+	DeleteBranch     bool          // Delete local and remote branch after merge
+	DoneTransition   string        // Ticket status to move to (empty = skip)
+	CleanupWorktree  bool          // Remove the worktree after merge
+	NotifyCompletion bool          // Send a completion notification after merge
+}
+
+// DefaultPostMergeConfig returns sensible defaults.
+func DefaultPostMergeConfig() PostMergeConfig {
+	return PostMergeConfig{
+		PollInterval:     time.Minute,
+		Timeout:          24 * time.Hour,
+		DeleteBranch:     true,
+		DoneTransition:   "Done",
+		CleanupWorktree:  true,
+		NotifyCompletion: true,
+	}
+}
+
+// PostMergeNode waits for the PR to be merged and performs follow-ups
+// using DefaultPostMergeConfig.
+//
+// Prerequisites: state.PR must be set
+// Updates: state.PR, state.PRMerged, clears state.Worktree
+func PostMergeNode(ctx flowgraph.Context, state State) (State, error) {
+	return PostMergeNodeWithConfig(DefaultPostMergeConfig())(ctx, state)
+}
+
+// PostMergeNodeWithConfig returns a node that polls the PR until it is merged,
+// then cleans the worktree, deletes the branch, transitions the ticket,
+// and sends a completion notification as configured.
+//
+// Follow-up failures are logged and do not fail the node. A PR that is closed
+// without merging, or that is not merged before the timeout, is an error.
+func PostMergeNodeWithConfig(cfg PostMergeConfig) NodeFunc {
+	defaults := DefaultPostMergeConfig()
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaults.PollInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+
+	return func(ctx flowgraph.Context, state State) (State, error) {
+		if err := state.Validate(RequirePR); err != nil {
+			return state, err
+		}
+
+		merged, err := waitForMerge(ctx, state.PR.ID, cfg)
+		if err != nil {
+			state.SetError(err)
+			return state, err
+		}
+
+		state.PR = merged
+		if merged.MergedAt != nil {
+			state.PRMerged = *merged.MergedAt
+		} else {
+			state.PRMerged = time.Now()
+		}
+
+		// Worktree goes first: it holds the branch checked out, which
+		// would otherwise block local branch deletion.
+		if cfg.CleanupWorktree && state.Worktree != "" {
+			if gitCtx := devcontext.Git(ctx); gitCtx != nil {
+				if cleanupErr := gitCtx.CleanupWorktree(state.Worktree); cleanupErr != nil {
+					slog.Warn("post-merge worktree cleanup failed",
+						slog.String("worktree", state.Worktree),
+						slog.String("error", cleanupErr.Error()))
+				} else {
+					state.Worktree = ""
+				}
+			}
+		}
+		if cfg.DeleteBranch {
+			deleteMergedBranch(ctx, state)
+		}
+		if cfg.DoneTransition != "" {
+			transitionTicket(ctx, state, cfg.DoneTransition)
+		}
+		if cfg.NotifyCompletion {
+			notifyMerged(ctx, state)
+		}
+
+		return state, nil
+	}
+}
+
+// waitForMerge polls the PR provider until the PR is merged, closed, or the
+// timeout elapses.
+func waitForMerge(ctx flowgraph.Context, id int, cfg PostMergeConfig) (*pr.PullRequest, error) {
+	provider := devcontext.PR(ctx)
+	if provider == nil {
+		return nil, fmt.Errorf("pr.Provider not found in context")
+	}
+
+	deadline := time.NewTimer(cfg.Timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		pullRequest, err := provider.GetPR(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get PR #%d: %w", id, err)
+		}
+
+		switch pullRequest.State {
+		case pr.StateMerged:
+			return pullRequest, nil
+		case pr.StateClosed:
+			return nil, fmt.Errorf("PR #%d was closed without merging", id)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			return nil, fmt.Errorf("PR #%d not merged within %v", id, cfg.Timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// deleteMergedBranch removes the PR branch locally and on origin (best effort)
+func deleteMergedBranch(ctx flowgraph.Context, state State) {
+	gitCtx := devcontext.Git(ctx)
+	if gitCtx == nil || state.Branch == "" {
+		return
+	}
+
+	if _, err := gitCtx.RunGit("push", "origin", "--delete", state.Branch); err != nil {
+		slog.Warn("delete remote branch failed",
+			slog.String("branch", state.Branch),
+			slog.String("error", err.Error()))
+	}
+
+	// A branch still checked out in a worktree cannot be deleted locally
+	if state.Worktree == "" && gitCtx.BranchExists(state.Branch) {
+		if err := gitCtx.DeleteBranch(state.Branch, true); err != nil {
+			slog.Warn("delete local branch failed",
+				slog.String("branch", state.Branch),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// transitionTicket moves the ticket to the given status (best effort)
+func transitionTicket(ctx flowgraph.Context, state State, status string) {
+	provider := ticketProvider(ctx)
+	if provider == nil || state.TicketID == "" {
+		return
+	}
+
+	if err := provider.Transition(ctx, state.TicketID, status); err != nil {
+		slog.Warn("ticket transition failed",
+			slog.String("provider", provider.Name()),
+			slog.String("ticket", state.TicketID),
+			slog.String("status", status),
+			slog.String("error", err.Error()))
+	}
+}
+
+// notifyMerged sends a run-completed notification for the merged PR
+func notifyMerged(ctx flowgraph.Context, state State) {
+	notifier := notify.NotifierFromContext(ctx)
+	if notifier == nil {
+		return
+	}
+
+	meta := buildMetadata(state)
+	meta["merged"] = true
+	if state.PR.MergedBy != "" {
+		meta["mergedBy"] = state.PR.MergedBy
+	}
+
+	event := notify.Event{
+		Type:      notify.EventRunCompleted,
+		RunID:     state.RunID,
+		FlowID:    state.FlowID,
+		Message:   fmt.Sprintf("PR #%d merged", state.PR.ID),
+		Severity:  notify.SeverityInfo,
+		Timestamp: time.Now(),
+		Metadata:  meta,
+		State:     state,
+	}
+
+	if notifyErr := notifier.Notify(ctx, event); notifyErr != nil {
+		slog.WarnContext(ctx, "notification failed",
+			slog.String("event_type", string(event.Type)),
+			slog.String("run_id", state.RunID),
+			slog.String("error", notifyErr.Error()))
+	}
+}
@@ -0,0 +1,174 @@
+package workflow
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	devcontext "github.com/randalmurphal/devflow/context"
+	"github.com/randalmurphal/devflow/git"
+	"github.com/randalmurphal/devflow/jira"
+	"github.com/randalmurphal/devflow/notify"
+	"github.com/randalmurphal/devflow/pr"
+	"github.com/randalmurphal/devflow/testutil"
+	"github.com/randalmurphal/devflow/ticketing"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph"
+)
+
+// postMergeEnv wires mock git, PR, and Jira services into a context
+type postMergeEnv struct {
+	ctx    context.Context
+	runner *git.MockRunner
+	jira   *testutil.JiraServer
+}
+
+func newPostMergeEnv(t *testing.T, getPR func(ctx context.Context, id int) (*pr.PullRequest, error)) *postMergeEnv {
+	t.Helper()
+
+	runner := git.NewMockRunner()
+	gitCtx, err := git.NewContext(testutil.SetupTestRepo(t), git.WithRunner(runner))
+	if err != nil {
+		t.Fatalf("git.NewContext() error = %v", err)
+	}
+
+	server := testutil.NewJiraServer(t)
+	server.AddIssue(testutil.JiraIssue{Key: "PROJ-1", Summary: "Ship it"})
+	cfg := jira.DefaultConfig()
+	cfg.URL = server.URL
+	cfg.APIVersion = jira.APIVersionV3
+	cfg.Auth = jira.AuthConfig{Type: jira.AuthAPIToken, Email: "bot@example.com", Token: "token"}
+	client, err := jira.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("jira.NewClient() error = %v", err)
+	}
+
+	ctx := testutil.TestContext(t)
+	ctx = devcontext.WithGit(ctx, gitCtx)
+	ctx = devcontext.WithPR(ctx, &pr.MockProvider{GetPRFunc: getPR})
+	ctx = ticketing.ContextWithProvider(ctx, ticketing.NewJiraProvider(client))
+	return &postMergeEnv{ctx: ctx, runner: runner, jira: server}
+}
+
+func (e *postMergeEnv) branchDeleted() bool {
+	return e.runner.WasCalled("git", "push", "origin", "--delete", "feature/proj-1")
+}
+
+func (e *postMergeEnv) ticketStatus(t *testing.T) string {
+	t.Helper()
+	issue, ok := e.jira.Issue("PROJ-1")
+	if !ok {
+		t.Fatal("issue PROJ-1 missing")
+	}
+	return issue.Status
+}
+
+func postMergeState() State {
+	state := NewState("ticket-to-pr")
+	state.RunID = "run-1"
+	state.Branch = "feature/proj-1"
+	state.TicketID = "PROJ-1"
+	state.PR = &pr.PullRequest{ID: 7, State: pr.StateOpen}
+	return state
+}
+
+func TestPostMergeNode_Timeout(t *testing.T) {
+	env := newPostMergeEnv(t, func(ctx context.Context, id int) (*pr.PullRequest, error) {
+		return &pr.PullRequest{ID: id, State: pr.StateOpen}, nil
+	})
+
+	cfg := DefaultPostMergeConfig()
+	cfg.PollInterval = 5 * time.Millisecond
+	cfg.Timeout = 30 * time.Millisecond
+	_, err := PostMergeNodeWithConfig(cfg)(flowgraph.NewContext(env.ctx), postMergeState())
+	if err == nil || !strings.Contains(err.Error(), "not merged within") {
+		t.Fatalf("PostMergeNode() error = %v, want timeout", err)
+	}
+	if env.branchDeleted() {
+		t.Error("branch deleted after a timeout")
+	}
+	if got := env.ticketStatus(t); got != "To Do" {
+		t.Errorf("ticket status = %q, want unchanged", got)
+	}
+}
+
+func TestPostMergeNode_ClosedWithoutMerge(t *testing.T) {
+	env := newPostMergeEnv(t, func(ctx context.Context, id int) (*pr.PullRequest, error) {
+		return &pr.PullRequest{ID: id, State: pr.StateClosed}, nil
+	})
+
+	_, err := PostMergeNodeWithConfig(DefaultPostMergeConfig())(flowgraph.NewContext(env.ctx), postMergeState())
+	if err == nil || !strings.Contains(err.Error(), "closed without merging") {
+		t.Fatalf("PostMergeNode() error = %v, want closed without merging", err)
+	}
+	if env.branchDeleted() {
+		t.Error("branch deleted for a PR closed without merging")
+	}
+	if got := env.ticketStatus(t); got != "To Do" {
+		t.Errorf("ticket status = %q, want unchanged", got)
+	}
+}
+
+func TestPostMergeNode_FollowUpsAfterMerge(t *testing.T) {
+	var env *postMergeEnv
+	mergedAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	polls := 0
+	env = newPostMergeEnv(t, func(ctx context.Context, id int) (*pr.PullRequest, error) {
+		polls++
+		if polls < 3 {
+			// Nothing may happen while the PR is still open
+			if env.branchDeleted() || env.ticketStatus(t) != "To Do" {
+				t.Errorf("follow-ups ran before the merge was confirmed (poll %d)", polls)
+			}
+			return &pr.PullRequest{ID: id, State: pr.StateOpen}, nil
+		}
+		return &pr.PullRequest{ID: id, State: pr.StateMerged, MergedAt: &mergedAt, MergedBy: "alice"}, nil
+	})
+
+	var events []notify.Event
+	ctx := notify.WithNotifier(env.ctx, notify.NotifierFunc(func(_ context.Context, event notify.Event) error {
+		events = append(events, event)
+		return nil
+	}))
+
+	cfg := DefaultPostMergeConfig()
+	cfg.PollInterval = time.Millisecond
+	state, err := PostMergeNodeWithConfig(cfg)(flowgraph.NewContext(ctx), postMergeState())
+	if err != nil {
+		t.Fatalf("PostMergeNode() error = %v", err)
+	}
+	if polls != 3 {
+		t.Errorf("GetPR called %d times, want 3", polls)
+	}
+	if !state.PRMerged.Equal(mergedAt) || state.PR.State != pr.StateMerged {
+		t.Errorf("state PR = %+v, merged %v", state.PR, state.PRMerged)
+	}
+	if !env.branchDeleted() {
+		t.Error("remote branch not deleted")
+	}
+	if !env.runner.WasCalled("git", "branch", "-D", "feature/proj-1") {
+		t.Error("local branch not deleted")
+	}
+	if got := env.ticketStatus(t); got != "Done" {
+		t.Errorf("ticket status = %q, want Done", got)
+	}
+	if len(events) != 1 || events[0].Type != notify.EventRunCompleted || events[0].Metadata["mergedBy"] != "alice" {
+		t.Errorf("notifications = %+v, want one run-completed event", events)
+	}
+}
+
+func TestPostMergeNode_WithoutNotifierOrTicketProvider(t *testing.T) {
+	ctx := devcontext.WithPR(testutil.TestContext(t), &pr.MockProvider{
+		GetPRFunc: func(ctx context.Context, id int) (*pr.PullRequest, error) {
+			return &pr.PullRequest{ID: id, State: pr.StateMerged}, nil
+		},
+	})
+
+	state, err := PostMergeNodeWithConfig(DefaultPostMergeConfig())(flowgraph.NewContext(ctx), postMergeState())
+	if err != nil {
+		t.Fatalf("PostMergeNode() error = %v", err)
+	}
+	if state.PRMerged.IsZero() {
+		t.Error("PRMerged not set")
+	}
+}
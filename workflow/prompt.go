@@ -0,0 +1,29 @@
+package workflow
+
+import (
+	"log/slog"
+
+	devcontext "github.com/randalmurphal/devflow/context"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph"
+)
+
+// loadSystemPrompt renders the named prompt from the context's loader, using
+// the A/B variant selected for the run, and records the resolved variant and
+// version in state. It returns "" when no loader is configured or the prompt
+// is unavailable.
+func loadSystemPrompt(ctx flowgraph.Context, state *State, name string) string {
+	loader := devcontext.Prompt(ctx)
+	if loader == nil {
+		return ""
+	}
+	name = loader.Variant(name, state.RunID)
+	systemPrompt, err := loader.Load(name)
+	if err != nil {
+		return ""
+	}
+	if resolved, err := loader.Resolve(name); err == nil {
+		state.RecordPrompt(resolved)
+		slog.Debug("resolved prompt", "runId", state.RunID, "prompt", resolved.String())
+	}
+	return systemPrompt
+}
@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -8,12 +9,19 @@ import (
 
 	"github.com/randalmurphal/devflow/artifact"
 	devcontext "github.com/randalmurphal/devflow/context"
+	"github.com/randalmurphal/devflow/task"
 	"github.com/randalmurphal/flowgraph/pkg/flowgraph"
 	"github.com/randalmurphal/llmkit/claude"
 )
 
 // ReviewNode reviews implementation for issues.
 //
+// Diffs larger than NodeConfig.ChunkedReviewThreshold that touch multiple
+// files are split per file and reviewed in parallel; the per-file findings
+// are aggregated into a single review. If some chunks fail, the whole diff
+// is reviewed in one request instead, and if that fails too the partial
+// review is used. Models come from the task.Selector in the context, if any.
+//
 // Prerequisites: state.Spec or state.Implementation must be set
 // Updates: state.Review, state.ReviewAttempts, state.ReviewTokensIn/Out
 func ReviewNode(ctx flowgraph.Context, state State) (State, error) {
@@ -41,30 +49,63 @@ func ReviewNode(ctx flowgraph.Context, state State) (State, error) {
 		return state, fmt.Errorf("no implementation to review")
 	}
 
-	// Build prompt
-	prompt := formatReviewPrompt(diff, state.Spec)
-
 	// Load system prompt if available
-	var systemPrompt string
-	if loader := devcontext.Prompt(ctx); loader != nil {
-		if sp, err := loader.Load("review-code"); err == nil {
-			systemPrompt = sp
-		}
-	}
+	systemPrompt := loadSystemPrompt(ctx, &state, "review-code")
 
 	// Increment attempts before running
 	state.ReviewAttempts++
 
-	// Run LLM
+	// Large multi-file diffs are reviewed per file
+	var partial *artifact.ReviewResult
+	var partialIn, partialOut int
+	threshold := NodeConfigFrom(ctx).ChunkedReviewThreshold
+	if threshold == 0 {
+		threshold = DefaultChunkedReviewThreshold
+	}
+	if threshold > 0 && len(diff) > threshold {
+		if chunks := splitDiffByFile(diff); len(chunks) > 1 {
+			results := reviewChunks(ctx, client, systemPrompt, state.Spec, chunks)
+			for _, r := range results {
+				partialIn += r.tokensIn
+				partialOut += r.tokensOut
+			}
+			review, err := aggregateChunkReviews(results)
+			if err == nil {
+				return applyReview(ctx, state, review, partialIn, partialOut), nil
+			}
+			slog.Warn("chunked review incomplete, reviewing the whole diff",
+				slog.String("error", err.Error()))
+			partial = review
+		}
+	}
+
+	review, tokensIn, tokensOut, err := reviewDiff(ctx, client, systemPrompt, diff, state.Spec)
+	if err != nil {
+		if partial != nil {
+			return applyReview(ctx, state, partial, partialIn, partialOut), nil
+		}
+		state.SetError(err)
+		return state, err
+	}
+	return applyReview(ctx, state, review, partialIn+tokensIn, partialOut+tokensOut), nil
+}
+
+// reviewDiff runs one review request for diff, with the model the
+// context's selector picks for its size
+func reviewDiff(ctx context.Context, client claude.Client, systemPrompt, diff, spec string) (*artifact.ReviewResult, int, int, error) {
+	prompt := formatReviewPrompt(diff, spec)
+
 	result, err := client.Complete(ctx, claude.CompletionRequest{
 		SystemPrompt: systemPrompt,
+		Model:        reviewModel(ctx, prompt),
 		Messages:     []claude.Message{{Role: claude.RoleUser, Content: prompt}},
 	})
 	if err != nil {
-		state.SetError(err)
-		return state, err
+		return nil, 0, 0, err
 	}
 
+	recordUsage(ctx, task.Review, result)
+
 	// Parse review result
 	review, parseErr := parseReviewOutput(result.Content)
 	if parseErr != nil {
@@ -74,18 +115,33 @@ func ReviewNode(ctx flowgraph.Context, state State) (State, error) {
 			Summary:  result.Content,
 		}
 	}
+	return review, result.Usage.InputTokens, result.Usage.OutputTokens, nil
+}
+
+// reviewModel returns the review model for prompt from the context's
+// selector, or "" for the client's default
+func reviewModel(ctx context.Context, prompt string) string {
+	selector := devcontext.Selector(ctx)
+	if selector == nil {
+		return ""
+	}
+	return string(selector.ModelForInput(task.Review, devcontext.DefaultTokenizer().Count(prompt)).Model)
+}
 
+// applyReview records a review result and its token usage on state
+// and saves the review artifact.
+func applyReview(ctx flowgraph.Context, state State, review *artifact.ReviewResult, tokensIn, tokensOut int) State {
 	state.Review = review
-	state.ReviewTokensIn = result.Usage.InputTokens
-	state.ReviewTokensOut = result.Usage.OutputTokens
-	state.AddTokens(result.Usage.InputTokens, result.Usage.OutputTokens)
+	state.ReviewTokensIn = tokensIn
+	state.ReviewTokensOut = tokensOut
+	state.AddTokens(tokensIn, tokensOut)
 
 	// Save review artifact
 	if artifacts := devcontext.Artifact(ctx); artifacts != nil {
 		artifacts.SaveReview(state.RunID, review)
 	}
 
-	return state, nil
+	return state
 }
 
 // FixFindingsNode fixes issues found in review.
@@ -112,12 +168,7 @@ func FixFindingsNode(ctx flowgraph.Context, state State) (State, error) {
 	prompt := formatFixPrompt(state.Review)
 
 	// Load system prompt if available
-	var systemPrompt string
-	if loader := devcontext.Prompt(ctx); loader != nil {
-		if sp, err := loader.Load("fix-findings"); err == nil {
-			systemPrompt = sp
-		}
-	}
+	systemPrompt := loadSystemPrompt(ctx, &state, "fix-findings")
 
 	// Run LLM
 	result, err := client.Complete(ctx, claude.CompletionRequest{
@@ -129,6 +180,8 @@ func FixFindingsNode(ctx flowgraph.Context, state State) (State, error) {
 		return state, err
 	}
 
+	recordUsage(ctx, task.Fix, result)
+
 	state.Implementation = result.Content
 	// Note: Files tracking now happens through git diff, not LLM response
 	state.AddTokens(result.Usage.InputTokens, result.Usage.OutputTokens)
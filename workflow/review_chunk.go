@@ -0,0 +1,172 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/randalmurphal/devflow/artifact"
+	"github.com/randalmurphal/llmkit/claude"
+)
+
+// DefaultChunkedReviewThreshold is the diff size (in bytes) above which
+// ReviewNode splits the diff per file and reviews the chunks in parallel,
+// unless NodeConfig.ChunkedReviewThreshold says otherwise. Smaller diffs
+// are reviewed in a single request.
+const DefaultChunkedReviewThreshold = 32 * 1024
+
+// MaxParallelChunkReviews limits concurrent LLM requests during chunked review.
+const MaxParallelChunkReviews = 4
+
+// diffChunk is the portion of a unified diff that touches a single file.
+type diffChunk struct {
+	File   string
+	Diff   string
+	binary bool
+}
+
+// chunkReview is the outcome of reviewing a single diffChunk.
+type chunkReview struct {
+	chunk     diffChunk
+	review    *artifact.ReviewResult
+	tokensIn  int
+	tokensOut int
+	err       error
+}
+
+// splitDiffByFile splits a unified git diff into per-file chunks.
+// Content before the first "diff --git" header is discarded, as are binary
+// files, which have no text to review. A renamed file is named by its new
+// path.
+func splitDiffByFile(diff string) []diffChunk {
+	var chunks []diffChunk
+	var current *diffChunk
+	var b strings.Builder
+	inHunk := false
+
+	flush := func() {
+		if current != nil && !current.binary {
+			current.Diff = b.String()
+			chunks = append(chunks, *current)
+		}
+		b.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			current = &diffChunk{File: diffHeaderFile(line)}
+			inHunk = false
+		}
+		if current == nil {
+			continue
+		}
+		b.WriteString(line)
+
+		// Extended headers name the file unambiguously, even with spaces
+		switch {
+		case inHunk:
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case strings.HasPrefix(line, "rename to "):
+			current.File = strings.TrimRight(strings.TrimPrefix(line, "rename to "), "\r\n")
+		case strings.HasPrefix(line, "+++ b/"):
+			current.File = strings.TrimRight(strings.TrimPrefix(line, "+++ b/"), "\t\r\n")
+		case strings.HasPrefix(line, "Binary files "), strings.HasPrefix(line, "GIT binary patch"):
+			current.binary = true
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// diffHeaderFile extracts the destination path from a "diff --git a/x b/y" line.
+func diffHeaderFile(header string) string {
+	header = strings.TrimSpace(strings.TrimPrefix(header, "diff --git "))
+	if idx := strings.LastIndex(header, " b/"); idx != -1 {
+		return header[idx+3:]
+	}
+	return strings.TrimPrefix(header, "a/")
+}
+
+// reviewChunks reviews each chunk in parallel. Results are returned in the
+// same order as the input chunks.
+func reviewChunks(ctx context.Context, client claude.Client, systemPrompt, spec string, chunks []diffChunk) []chunkReview {
+	results := make([]chunkReview, len(chunks))
+	sem := make(chan struct{}, MaxParallelChunkReviews)
+	var wg sync.WaitGroup
+
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk diffChunk) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			res := chunkReview{chunk: chunk}
+			res.review, res.tokensIn, res.tokensOut, res.err = reviewDiff(ctx, client, systemPrompt, chunk.Diff, spec)
+			if res.err != nil {
+				res.err = fmt.Errorf("review %s: %w", chunk.File, res.err)
+			}
+			results[i] = res
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// aggregateChunkReviews merges per-file reviews into a single result.
+// The change is approved only if every chunk was reviewed and approved.
+// Chunks whose review failed are listed in the summary and their errors
+// returned joined; the result is nil only if every chunk failed.
+func aggregateChunkReviews(results []chunkReview) (*artifact.ReviewResult, error) {
+	merged := &artifact.ReviewResult{Approved: true}
+	var summaries, unreviewed []string
+	var errs []error
+
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+			unreviewed = append(unreviewed, r.chunk.File)
+			merged.Approved = false
+			continue
+		}
+
+		if !r.review.Approved {
+			merged.Approved = false
+		}
+		for _, f := range r.review.Findings {
+			if f.File == "" {
+				f.File = r.chunk.File
+			}
+			merged.Findings = append(merged.Findings, f)
+		}
+		if s := strings.TrimSpace(r.review.Summary); s != "" {
+			summaries = append(summaries, fmt.Sprintf("**%s**: %s", r.chunk.File, s))
+		}
+
+		merged.Metrics.FilesReviewed++
+		merged.Metrics.LinesReviewed += strings.Count(r.chunk.Diff, "\n")
+		merged.Metrics.TokensUsed += r.tokensIn + r.tokensOut
+	}
+
+	if len(errs) == len(results) {
+		return nil, errors.Join(errs...)
+	}
+	if len(unreviewed) > 0 {
+		summaries = append(summaries, fmt.Sprintf("**Not reviewed** (review failed): %s", strings.Join(unreviewed, ", ")))
+	}
+
+	if merged.Approved {
+		merged.Verdict = "APPROVE"
+	} else {
+		merged.Verdict = "REQUEST_CHANGES"
+	}
+	merged.Summary = strings.Join(summaries, "\n\n")
+
+	return merged, errors.Join(errs...)
+}
@@ -0,0 +1,135 @@
+package workflow
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/randalmurphal/devflow/artifact"
+)
+
+func TestSplitDiffByFile(t *testing.T) {
+	diff := `preamble is dropped
+diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+-old
++new
++++ b/not-a-header.go
+diff --git a/old name.go b/new name.go
+similarity index 90%
+rename from old name.go
+rename to new name.go
+@@ -1 +1 @@
+-a
++b
+diff --git a/logo.png b/logo.png
+index 3333333..4444444 100644
+Binary files a/logo.png and b/logo.png differ
+diff --git a/gone.go b/gone.go
+deleted file mode 100644
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+`
+
+	chunks := splitDiffByFile(diff)
+	var files []string
+	for _, c := range chunks {
+		files = append(files, c.File)
+	}
+	if got := strings.Join(files, ","); got != "main.go,new name.go,gone.go" {
+		t.Fatalf("files = %q, want main.go,new name.go,gone.go", got)
+	}
+
+	if !strings.HasPrefix(chunks[0].Diff, "diff --git a/main.go") || !strings.Contains(chunks[0].Diff, "+++ b/not-a-header.go") {
+		t.Errorf("main.go chunk = %q", chunks[0].Diff)
+	}
+	if strings.Contains(chunks[1].Diff, "logo.png") {
+		t.Errorf("rename chunk includes the next file: %q", chunks[1].Diff)
+	}
+}
+
+func TestSplitDiffByFile_SingleLargeFile(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("diff --git a/big.go b/big.go\n--- a/big.go\n+++ b/big.go\n@@ -0,0 +1,5000 @@\n")
+	for range 5000 {
+		b.WriteString("+line of generated code\n")
+	}
+	diff := b.String()
+
+	chunks := splitDiffByFile(diff)
+	if len(chunks) != 1 || chunks[0].File != "big.go" || chunks[0].Diff != diff {
+		t.Fatalf("got %d chunks, want the whole diff as one", len(chunks))
+	}
+}
+
+func TestAggregateChunkReviews(t *testing.T) {
+	results := []chunkReview{
+		{
+			chunk:    diffChunk{File: "a.go", Diff: "1\n2\n"},
+			review:   &artifact.ReviewResult{Approved: true, Summary: "fine"},
+			tokensIn: 10, tokensOut: 5,
+		},
+		{
+			chunk: diffChunk{File: "b.go", Diff: "1\n"},
+			review: &artifact.ReviewResult{
+				Approved: false,
+				Summary:  "nil check missing",
+				Findings: []artifact.ReviewFinding{{Message: "possible nil dereference"}},
+			},
+			tokensIn: 20, tokensOut: 7,
+		},
+	}
+
+	merged, err := aggregateChunkReviews(results)
+	if err != nil {
+		t.Fatalf("aggregateChunkReviews() error = %v", err)
+	}
+	if merged.Approved || merged.Verdict != "REQUEST_CHANGES" {
+		t.Errorf("Approved = %v, Verdict = %q; want rejected", merged.Approved, merged.Verdict)
+	}
+	if len(merged.Findings) != 1 || merged.Findings[0].File != "b.go" {
+		t.Errorf("Findings = %+v, want one finding attributed to b.go", merged.Findings)
+	}
+	if merged.Metrics.FilesReviewed != 2 || merged.Metrics.LinesReviewed != 3 || merged.Metrics.TokensUsed != 42 {
+		t.Errorf("Metrics = %+v", merged.Metrics)
+	}
+	if !strings.Contains(merged.Summary, "**a.go**: fine") || !strings.Contains(merged.Summary, "**b.go**: nil check missing") {
+		t.Errorf("Summary = %q", merged.Summary)
+	}
+
+	results[1].review.Approved = true
+	results[1].review.Findings = nil
+	if merged, _ := aggregateChunkReviews(results); !merged.Approved || merged.Verdict != "APPROVE" {
+		t.Errorf("all approved: Approved = %v, Verdict = %q", merged.Approved, merged.Verdict)
+	}
+}
+
+func TestAggregateChunkReviews_Failures(t *testing.T) {
+	errTimeout := errors.New("timeout")
+	results := []chunkReview{
+		{chunk: diffChunk{File: "a.go"}, review: &artifact.ReviewResult{Approved: true}},
+		{chunk: diffChunk{File: "b.go"}, err: errTimeout},
+	}
+
+	// Partial results are kept, but never approve the change
+	merged, err := aggregateChunkReviews(results)
+	if !errors.Is(err, errTimeout) {
+		t.Errorf("error = %v, want the chunk error", err)
+	}
+	if merged == nil || merged.Approved || merged.Metrics.FilesReviewed != 1 {
+		t.Fatalf("merged = %+v, want unapproved partial review", merged)
+	}
+	if !strings.Contains(merged.Summary, "Not reviewed") || !strings.Contains(merged.Summary, "b.go") {
+		t.Errorf("Summary = %q, want b.go listed as not reviewed", merged.Summary)
+	}
+
+	results[0] = chunkReview{chunk: diffChunk{File: "a.go"}, err: errTimeout}
+	if merged, err := aggregateChunkReviews(results); merged != nil || err == nil {
+		t.Errorf("all failed: merged = %+v, error = %v; want nil and error", merged, err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	devcontext "github.com/randalmurphal/devflow/context"
+	"github.com/randalmurphal/devflow/task"
 	"github.com/randalmurphal/flowgraph/pkg/flowgraph"
 	"github.com/randalmurphal/llmkit/claude"
 )
@@ -29,12 +30,7 @@ func GenerateSpecNode(ctx flowgraph.Context, state State) (State, error) {
 	prompt := formatSpecPrompt(state.Ticket)
 
 	// Load system prompt if available
-	var systemPrompt string
-	if loader := devcontext.Prompt(ctx); loader != nil {
-		if sp, err := loader.Load("generate-spec"); err == nil {
-			systemPrompt = sp
-		}
-	}
+	systemPrompt := loadSystemPrompt(ctx, &state, "generate-spec")
 
 	// Run LLM
 	result, err := client.Complete(ctx, claude.CompletionRequest{
@@ -46,6 +42,8 @@ func GenerateSpecNode(ctx flowgraph.Context, state State) (State, error) {
 		return state, err
 	}
 
+	recordUsage(ctx, task.Architecture, result)
+
 	state.Spec = result.Content
 	state.SpecTokensIn = result.Usage.InputTokens
 	state.SpecTokensOut = result.Usage.OutputTokens
@@ -4,29 +4,22 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/randalmurphal/devflow/artifact"
 	"github.com/randalmurphal/devflow/pr"
+	"github.com/randalmurphal/devflow/prompt"
+	"github.com/randalmurphal/devflow/ticketing"
 )
 
 // =============================================================================
 // Ticket Type
 // =============================================================================
 
-// Ticket represents input ticket data from an issue tracker
-type Ticket struct {
-	ID          string            `json:"id"`
-	Title       string            `json:"title"`
-	Description string            `json:"description"`
-	Priority    string            `json:"priority,omitempty"`
-	Type        string            `json:"type,omitempty"` // bug, feature, task, etc.
-	Labels      []string          `json:"labels,omitempty"`
-	Assignee    string            `json:"assignee,omitempty"`
-	Reporter    string            `json:"reporter,omitempty"`
-	URL         string            `json:"url,omitempty"`
-	Metadata    map[string]string `json:"metadata,omitempty"`
-}
+// Ticket represents input ticket data from an issue tracker (Jira, GitHub
+// Issues, Linear). It is fetched by FetchTicketNode or set with WithTicket.
+type Ticket = ticketing.Ticket
 
 // =============================================================================
 // Embeddable State Components
@@ -75,6 +68,7 @@ type ReviewState struct {
 type PullRequestState struct {
 	PR        *pr.PullRequest `json:"pr,omitempty"`
 	PRCreated time.Time       `json:"prCreated,omitempty"`
+	PRMerged  time.Time       `json:"prMerged,omitempty"`
 }
 
 // TestState tracks test execution
@@ -124,6 +118,9 @@ type State struct {
 	LintState
 	MetricsState
 
+	// Prompt templates used, for correlating behavior with prompt changes
+	Prompts []prompt.Resolved `json:"prompts,omitempty"`
+
 	// Error tracking
 	Error string `json:"error,omitempty"`
 }
@@ -191,6 +188,16 @@ func (s *State) SetError(err error) {
 	}
 }
 
+// RecordPrompt records the template version a node used, replacing any
+// earlier record for the same prompt name. The slice is copied, so State
+// values taken before the call are unaffected.
+func (s *State) RecordPrompt(resolved prompt.Resolved) {
+	prompts := slices.DeleteFunc(slices.Clone(s.Prompts), func(p prompt.Resolved) bool {
+		return p.Name == resolved.Name
+	})
+	s.Prompts = append(prompts, resolved)
+}
+
 // HasError returns true if state has an error
 func (s State) HasError() bool {
 	return s.Error != ""
@@ -211,6 +218,7 @@ const (
 	RequireReview         StateRequirement = "review"
 	RequireBranch         StateRequirement = "branch"
 	RequireFiles          StateRequirement = "files"
+	RequirePR             StateRequirement = "pr"
 )
 
 // Validate checks if state has required fields
@@ -245,6 +253,10 @@ func (s State) Validate(requirements ...StateRequirement) error {
 			if len(s.Files) == 0 {
 				return fmt.Errorf("files required")
 			}
+		case RequirePR:
+			if s.PR == nil {
+				return fmt.Errorf("pull request required")
+			}
 		default:
 			return fmt.Errorf("unknown requirement: %s", req)
 		}
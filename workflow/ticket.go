@@ -0,0 +1,46 @@
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/randalmurphal/devflow/jira"
+	"github.com/randalmurphal/devflow/ticketing"
+	"github.com/randalmurphal/flowgraph/pkg/flowgraph"
+)
+
+// FetchTicketNode loads the ticket from the configured issue tracker.
+//
+// Prerequisites: state.TicketID must be set, and a ticketing.Provider (or a
+// jira.Client) must be in context
+// Updates: state.Ticket
+func FetchTicketNode(ctx flowgraph.Context, state State) (State, error) {
+	if state.TicketID == "" {
+		return state, fmt.Errorf("ticket id required")
+	}
+
+	provider := ticketProvider(ctx)
+	if provider == nil {
+		return state, ticketing.ErrNoProvider
+	}
+
+	ticket, err := provider.GetTicket(ctx, state.TicketID)
+	if err != nil {
+		state.SetError(err)
+		return state, fmt.Errorf("fetch ticket: %w", err)
+	}
+
+	state.Ticket = ticket
+	return state, nil
+}
+
+// ticketProvider returns the ticket provider in context, falling back to a
+// Jira client for workflows configured before ticketing existed
+func ticketProvider(ctx flowgraph.Context) ticketing.Provider {
+	if provider := ticketing.ProviderFromContext(ctx); provider != nil {
+		return provider
+	}
+	if client := jira.ClientFromContext(ctx); client != nil {
+		return ticketing.NewJiraProvider(client)
+	}
+	return nil
+}
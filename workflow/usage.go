@@ -0,0 +1,27 @@
+package workflow
+
+import (
+	"context"
+
+	devcontext "github.com/randalmurphal/devflow/context"
+	"github.com/randalmurphal/devflow/task"
+	"github.com/randalmurphal/devflow/transcript"
+	"github.com/randalmurphal/llmkit/claude"
+	"github.com/randalmurphal/llmkit/model"
+)
+
+// usagePricing prices calls whose response carries no cost
+var usagePricing = transcript.NewPricingRegistry()
+
+// recordUsage reports an LLM call to the usage recorder in ctx, if any
+func recordUsage(ctx context.Context, t task.Type, result *claude.CompletionResponse) {
+	recorder := devcontext.Usage(ctx)
+	if recorder == nil {
+		return
+	}
+	cost := result.CostUSD
+	if cost == 0 {
+		cost = usagePricing.Cost(result.Model, result.Usage.InputTokens, result.Usage.OutputTokens)
+	}
+	recorder.Record(t, model.ModelName(result.Model), result.Usage.InputTokens, result.Usage.OutputTokens, cost)
+}